@@ -12,20 +12,32 @@ import (
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 
+	encrypteddataclient "github.com/ktigay/goph-keeper/internal/client/client/encrypted/userdata"
+	accountclient "github.com/ktigay/goph-keeper/internal/client/client/grpc/account"
 	authclient "github.com/ktigay/goph-keeper/internal/client/client/grpc/auth"
+	secondfactorclient "github.com/ktigay/goph-keeper/internal/client/client/grpc/secondfactor"
+	sessionclient "github.com/ktigay/goph-keeper/internal/client/client/grpc/session"
 	userdataclient "github.com/ktigay/goph-keeper/internal/client/client/grpc/userdata"
+	vaultclient "github.com/ktigay/goph-keeper/internal/client/client/grpc/vault"
 	"github.com/ktigay/goph-keeper/internal/client/config"
+	"github.com/ktigay/goph-keeper/internal/client/crypto"
 	"github.com/ktigay/goph-keeper/internal/client/interceptor"
 	authrepo "github.com/ktigay/goph-keeper/internal/client/repository/auth"
 	userdatarepo "github.com/ktigay/goph-keeper/internal/client/repository/userdata"
+	vaultrepo "github.com/ktigay/goph-keeper/internal/client/repository/vault"
 	authsrv "github.com/ktigay/goph-keeper/internal/client/service/auth"
 	syncsrv "github.com/ktigay/goph-keeper/internal/client/service/sync"
 	userdatasrv "github.com/ktigay/goph-keeper/internal/client/service/userdata"
+	vaultsrv "github.com/ktigay/goph-keeper/internal/client/service/vault"
+	"github.com/ktigay/goph-keeper/internal/client/tlsconfig"
 	"github.com/ktigay/goph-keeper/internal/client/tui/app"
 	"github.com/ktigay/goph-keeper/internal/contracts/v1/auth"
 	"github.com/ktigay/goph-keeper/internal/contracts/v1/data"
+	"github.com/ktigay/goph-keeper/internal/contracts/v1/session"
+	"github.com/ktigay/goph-keeper/internal/contracts/v1/vault"
 	"github.com/ktigay/goph-keeper/internal/entity"
 	applog "github.com/ktigay/goph-keeper/internal/log"
 )
@@ -41,6 +53,7 @@ func main() {
 		cfg        *config.Config
 		fileLogger *os.File
 		logger     *slog.Logger
+		authConn   *grpc.ClientConn
 		grpcClient *grpc.ClientConn
 		err        error
 	)
@@ -73,33 +86,70 @@ func main() {
 	var (
 		authRepo       *authrepo.Repository
 		userDataRepo   *userdatarepo.Repository
+		vaultRepo      *vaultrepo.Repository
 		authClient     *authclient.Client
-		userDataClient *userdataclient.Client
+		userDataClient *encrypteddataclient.Client
+		vaultClient    *vaultclient.Client
 		authSrv        *authsrv.Service
 		userDataSrv    *userdatasrv.Service
 		syncSrv        *syncsrv.Service
+		vaultSrv       *vaultsrv.Service
+		transportCreds credentials.TransportCredentials
 	)
 
+	if transportCreds, err = transportCredentials(cfg.TLS); err != nil {
+		log.Fatalf("Error loading TLS config: %v", err)
+	}
+
+	// Методы AuthService не требуют токена, поэтому идут через отдельное соединение
+	// без AuthInterceptor: через него же интерцептор обновляет токен.
+	authConn, err = grpc.NewClient(
+		cfg.ServerGRPCHost,
+		grpc.WithTransportCredentials(transportCreds),
+		grpc.WithChainUnaryInterceptor(
+			interceptor.TimeoutInterceptor(cfg.SrvRequestTimeout),
+		),
+	)
+	if err != nil {
+		log.Fatalf("failed to create grpc client: %v", err)
+	}
+
 	authRepo = authrepo.New()
+	hostname, _ := os.Hostname()
+	authClient = authclient.New(auth.NewAuthServiceClient(authConn), hostname, buildVersion)
+	authSrv = authsrv.New(authClient, authRepo, logger)
+
 	grpcClient, err = grpc.NewClient(
 		cfg.ServerGRPCHost,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithTransportCredentials(transportCreds),
 		grpc.WithChainUnaryInterceptor(
 			interceptor.TimeoutInterceptor(cfg.SrvRequestTimeout),
-			interceptor.AuthInterceptor(authRepo),
+			interceptor.AuthInterceptor(authSrv),
+		),
+		grpc.WithChainStreamInterceptor(
+			interceptor.AuthStreamInterceptor(authSrv),
 		),
 	)
 	if err != nil {
 		log.Fatalf("failed to create grpc client: %v", err)
 	}
 
-	authClient = authclient.New(auth.NewAuthServiceClient(grpcClient))
-	userDataClient = userdataclient.New(data.NewUserDataServiceClient(grpcClient))
-	authSrv = authsrv.New(authClient, authRepo, logger)
+	vaultClient = vaultclient.New(vault.NewVaultServiceClient(grpcClient))
+
+	vaultRepo = vaultrepo.New()
+	userDataClient = encrypteddataclient.New(
+		userdataclient.New(data.NewUserDataServiceClient(grpcClient)),
+		vaultRepo,
+	)
+	vaultSrv = vaultsrv.New(vaultClient, userDataClient, vaultRepo, crypto.DefaultKDFParams, logger)
 
 	userDataRepo = userdatarepo.New()
 	userDataSrv = userdatasrv.New(userDataRepo)
-	syncSrv = syncsrv.New(userDataClient, userDataRepo, logger)
+	conflictStrategy, err := syncsrv.ParseStrategy(cfg.ConflictStrategy)
+	if err != nil {
+		log.Fatalf("Error loading config: %v", err)
+	}
+	syncSrv = syncsrv.New(userDataClient, userDataRepo, conflictStrategy, logger)
 
 	signedInCh := make(chan struct{})
 
@@ -112,9 +162,15 @@ func main() {
 	defer stop()
 
 	consoleApp := app.New(exitCtx, app.Api{
-		AuthSrv:         authSrv,
-		UserDataSrv:     userDataSrv,
-		UserDataSyncSrv: syncSrv,
+		AuthSrv:            authSrv,
+		UserDataSrv:        userDataSrv,
+		UserDataSyncSrv:    syncSrv,
+		UserDataHistorySrv: syncSrv,
+		ConflictSrv:        syncSrv,
+		VaultSrv:           vaultSrv,
+		SessionSrv:         sessionclient.New(session.NewSessionServiceClient(grpcClient)),
+		SecondFactorSrv:    secondfactorclient.New(auth.NewAuthServiceClient(grpcClient)),
+		AccountSrv:         accountclient.New(auth.NewAuthServiceClient(grpcClient)),
 	}, logger, isSyncedCh, signedInCh, quitCh)
 
 	wg := &sync.WaitGroup{}
@@ -144,6 +200,22 @@ func main() {
 		}
 	}()
 
+	go func() {
+		<-signedInCh
+		wg.Add(1)
+
+		// Изменения с других устройств приходят по мере их появления.
+		syncSrv.Watch(exitCtx, func() {
+			// Перерисовка уже запрошена, если канал занят.
+			select {
+			case isSyncedCh <- true:
+			default:
+			}
+		})
+		logger.Debug("WatchChanges exit")
+		wg.Done()
+	}()
+
 	wg.Add(1)
 	go func() {
 		if err = consoleApp.Run(); err != nil {
@@ -174,3 +246,21 @@ func buildInfo() {
   Build date: %s
 `, buildVersion, buildDate)
 }
+
+// transportCredentials возвращает TLS учетные данные соединения или без шифрования с --plaintext.
+func transportCredentials(cfg config.TLS) (credentials.TransportCredentials, error) {
+	if cfg.Plaintext {
+		return insecure.NewCredentials(), nil
+	}
+	tlsCfg, err := tlsconfig.New(tlsconfig.Options{
+		CAFile:     cfg.TLSCAFile,
+		CertFile:   cfg.TLSCertFile,
+		KeyFile:    cfg.TLSKeyFile,
+		ServerName: cfg.TLSServerName,
+		PinSHA256:  cfg.TLSPinSHA256,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(tlsCfg), nil
+}
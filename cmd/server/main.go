@@ -2,45 +2,109 @@ package main
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
 	"log"
 	"log/slog"
 	"net"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"sync"
 	"syscall"
+	"text/tabwriter"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/reflection"
 
 	_ "github.com/golang/mock/mockgen/model"
 	"github.com/ktigay/goph-keeper/internal/contracts/v1/auth"
 	"github.com/ktigay/goph-keeper/internal/contracts/v1/data"
+	"github.com/ktigay/goph-keeper/internal/contracts/v1/keys"
+	"github.com/ktigay/goph-keeper/internal/contracts/v1/session"
+	"github.com/ktigay/goph-keeper/internal/contracts/v1/token"
+	"github.com/ktigay/goph-keeper/internal/contracts/v1/vault"
 	"github.com/ktigay/goph-keeper/internal/entity"
 	applog "github.com/ktigay/goph-keeper/internal/log"
+	"github.com/ktigay/goph-keeper/internal/server/changefeed"
 	"github.com/ktigay/goph-keeper/internal/server/config"
 	appdb "github.com/ktigay/goph-keeper/internal/server/db"
+	"github.com/ktigay/goph-keeper/internal/server/db/migrate"
+	srventity "github.com/ktigay/goph-keeper/internal/server/entity"
 	datahandler "github.com/ktigay/goph-keeper/internal/server/handler/grpc"
 	"github.com/ktigay/goph-keeper/internal/server/interceptor"
+	"github.com/ktigay/goph-keeper/internal/server/policy"
+	apitokenrepo "github.com/ktigay/goph-keeper/internal/server/repository/apitoken"
+	attemptrepo "github.com/ktigay/goph-keeper/internal/server/repository/attempt"
+	auditrepo "github.com/ktigay/goph-keeper/internal/server/repository/audit"
+	datakeyrepo "github.com/ktigay/goph-keeper/internal/server/repository/datakey"
+	sfrepo "github.com/ktigay/goph-keeper/internal/server/repository/secondfactor"
+	sessionrepo "github.com/ktigay/goph-keeper/internal/server/repository/session"
+	srprepo "github.com/ktigay/goph-keeper/internal/server/repository/srp"
 	userrepo "github.com/ktigay/goph-keeper/internal/server/repository/user"
 	userdatarepo "github.com/ktigay/goph-keeper/internal/server/repository/userdata"
+	vaultrepo "github.com/ktigay/goph-keeper/internal/server/repository/vault"
 	"github.com/ktigay/goph-keeper/internal/server/security"
+	"github.com/ktigay/goph-keeper/internal/server/security/certs"
+	"github.com/ktigay/goph-keeper/internal/server/security/envelope"
+	"github.com/ktigay/goph-keeper/internal/server/security/password"
+	accountsrv "github.com/ktigay/goph-keeper/internal/server/service/account"
+	apitokensrv "github.com/ktigay/goph-keeper/internal/server/service/apitoken"
 	authsrv "github.com/ktigay/goph-keeper/internal/server/service/auth"
+	sfsrv "github.com/ktigay/goph-keeper/internal/server/service/secondfactor"
+	sessionsrv "github.com/ktigay/goph-keeper/internal/server/service/session"
 	userdatasrv "github.com/ktigay/goph-keeper/internal/server/service/userdata"
+	vaultsrv "github.com/ktigay/goph-keeper/internal/server/service/vault"
+)
+
+const (
+	// challengeTTL срок действия токена входа, ожидающего второй фактор.
+	challengeTTL = 5 * time.Minute
+	// purgeInterval период удаления аккаунтов, срок удаления которых наступил.
+	purgeInterval = time.Minute
+)
+
+// minSRPSecretLen наименьший размер секрета для ответов SRP на несуществующие логины.
+const minSRPSecretLen = 32
+
+const (
+	// commandRewrapKeys перешифровывает ключи данных пользователей текущим мастер-ключом и завершается.
+	commandRewrapKeys = "rewrap-keys"
+	// commandMigrate управляет миграциями б.д.: migrate up, migrate down [n], migrate status.
+	commandMigrate = "migrate"
 )
 
 func main() {
 	ctx := context.TODO()
 
 	var (
-		cfg    *config.Config
-		logger *slog.Logger
-		pool   *pgxpool.Pool
-		err    error
+		cfg       *config.Config
+		logger    *slog.Logger
+		pool      *pgxpool.Pool
+		migrator  *migrate.Migrator
+		keyring   *envelope.Keyring
+		srpSecret []byte
+		hasher    *password.Hasher
+		err       error
+
+		tokenKeys, challengeKeys *security.KeySet
 	)
 
-	if cfg, err = config.New(os.Args[1:]); err != nil {
+	command, args := parseCommand(os.Args[1:])
+	if command != "" && command != commandRewrapKeys && command != commandMigrate {
+		log.Fatalf("unknown command: %s", command)
+	}
+	var migrateArgs []string
+	if command == commandMigrate {
+		migrateArgs, args = splitCommandArgs(args)
+	}
+
+	if cfg, err = config.New(args); err != nil {
 		log.Fatalf("can't load config: %v", err)
 	}
 
@@ -50,35 +114,122 @@ func main() {
 	if pool, err = appdb.NewPgxPool(ctx, cfg.DatabaseDSN); err != nil {
 		log.Fatalf("Failed to create connect to DB: %v", err)
 	}
-	if err = appdb.CreateSchema(ctx, pool); err != nil {
-		log.Fatalf("Failed to create structure: %v", err)
+	if migrator, err = migrate.New(pool, logger); err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+	if command == commandMigrate {
+		if err = runMigrate(ctx, migrator, migrateArgs); err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		return
+	}
+	if _, err = migrator.Up(ctx); err != nil {
+		log.Fatalf("Failed to migrate database: %v", err)
+	}
+	if cfg.AttemptStore != config.AttemptStoreMemory && cfg.AttemptStore != config.AttemptStorePostgres {
+		log.Fatalf("unknown login attempt store: %s", cfg.AttemptStore)
+	}
+	if keyring, err = envelope.LoadKeyring(cfg.MasterKeyFile, cfg.MasterKey); err != nil {
+		log.Fatalf("Failed to load master keys: %v", err)
+	}
+	if tokenKeys, challengeKeys, err = loadTokenKeys(cfg); err != nil {
+		log.Fatalf("Failed to load token signing keys: %v", err)
+	}
+	if srpSecret, err = loadSRPSecret(cfg); err != nil {
+		log.Fatalf("Failed to load srp secret: %v", err)
+	}
+	hasher, err = password.New(password.Params{
+		Time:    cfg.PasswordHashTime,
+		Memory:  cfg.PasswordHashMemory,
+		Threads: cfg.PasswordHashThreads,
+	})
+	if err != nil {
+		log.Fatalf("Invalid password hash parameters: %v", err)
 	}
 
 	var (
-		jwtAuth = security.NewJWTWrapper[entity.Identity](cfg.AuthSecret)
+		jwtAuth      = security.NewJWTWrapper[entity.Identity](tokenKeys, security.AudienceAccess, time.Duration(cfg.AccessTokenTTL)*time.Second)
+		jwtChallenge = security.NewJWTWrapper[srventity.SecondFactorChallenge](challengeKeys, security.AudienceSecondFactor, challengeTTL)
 
-		// txFacade  = appdb.NewPgxTxFacade(pool)
+		txFacade  = appdb.NewPgxTxFacade(pool)
 		dbWrapper = appdb.NewTxConnWrapper(pool)
 
 		userRepo = userrepo.New(dbWrapper, logger)
-		userSrv  = authsrv.New(userRepo)
+		userSrv  = authsrv.New(userRepo, hasher, cfg.LegacyLogin)
+		srpSrv   = authsrv.NewSRP(userRepo, srprepo.New(dbWrapper, logger), srpSecret, cfg.LegacyLogin)
+
+		sessionSrv = sessionsrv.New(sessionrepo.New(dbWrapper, logger), time.Duration(cfg.RefreshTokenTTL)*time.Second)
+
+		apiTokenSrv = apitokensrv.New(apitokenrepo.New(dbWrapper, logger))
 
-		userdataRepo = userdatarepo.New(dbWrapper, logger)
-		userdataSrv  = userdatasrv.New(userdataRepo)
+		accountSrv = accountsrv.New(userRepo, auditrepo.New(dbWrapper, logger), sessionSrv, srpSrv, hasher, txFacade, time.Duration(cfg.DeletionGrace)*time.Second)
+
+		vaultRepo = vaultrepo.New(dbWrapper, logger)
+
+		dataKeys     = envelope.NewDataKeys(datakeyrepo.New(dbWrapper, logger), keyring, logger)
+		userdataRepo = userdatarepo.NewEnvelopeRepository(userdatarepo.New(dbWrapper, logger), dataKeys)
+		userdataSrv  = userdatasrv.New(userdataRepo, vaultRepo, txFacade, cfg.HistoryRetention, time.Duration(cfg.TombstoneRetention)*time.Second)
+
+		secondFactorSrv = sfsrv.New(sfrepo.New(dbWrapper, logger), userRepo, dataKeys)
+
+		attemptStore authsrv.AttemptStore = attemptrepo.NewMemory()
+
+		vaultSrv = vaultsrv.New(vaultRepo, userdataRepo, txFacade)
 	)
 
+	if command == commandRewrapKeys {
+		var n int
+		if n, err = dataKeys.Rewrap(ctx); err != nil {
+			log.Fatalf("Failed to rewrap data keys: %v", err)
+		}
+		logger.Info("data keys rewrapped", "kek_id", keyring.CurrentID(), "count", n)
+		return
+	}
+
+	if cfg.AttemptStore == config.AttemptStorePostgres {
+		attemptStore = attemptrepo.New(dbWrapper, logger)
+	}
+	loginLimiter := authsrv.NewLimiter(attemptStore, authsrv.DefaultPolicies())
+
 	exitCtx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM, syscall.SIGQUIT)
 	defer stop()
 
-	grpcServer := grpc.NewServer(
+	authInterceptor := interceptor.NewAuth(jwtAuth, sessionSrv, apiTokenSrv, policy.New(interceptor.Rules()))
+	serverOpts := []grpc.ServerOption{
 		grpc.ChainUnaryInterceptor(
 			interceptor.WithRecover(logger),
 			interceptor.WithLogging(logger),
-			interceptor.NewAuth(jwtAuth, interceptor.AccessList()).WithAuthorization(),
+			authInterceptor.WithAuthorization(),
+			interceptor.WithClientCertBinding(cfg.TLSBindClientCert),
 		),
-	)
-	data.RegisterUserDataServiceServer(grpcServer, datahandler.NewUserDataHandler(userdataSrv))
-	auth.RegisterAuthServiceServer(grpcServer, datahandler.NewAuthHandler(userSrv, jwtAuth))
+		grpc.ChainStreamInterceptor(
+			interceptor.WithStreamRecover(logger),
+			interceptor.WithStreamLogging(logger),
+			authInterceptor.WithStreamAuthorization(),
+			interceptor.WithStreamClientCertBinding(cfg.TLSBindClientCert),
+		),
+	}
+	if cfg.TLS.Enabled() {
+		var reloader *certs.Reloader
+		if reloader, err = newCertReloader(cfg.TLS, logger); err != nil {
+			log.Fatalf("Failed to load TLS certificates: %v", err)
+		}
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(reloader.TLSConfig())))
+		go reloader.Watch(exitCtx, time.Duration(cfg.TLSReloadInterval)*time.Second)
+	} else {
+		logger.Warn("TLS is disabled, passwords and tokens are sent in cleartext")
+	}
+
+	changes := changefeed.NewBroker()
+	go changefeed.Listen(exitCtx, pool, changes, logger)
+
+	grpcServer := grpc.NewServer(serverOpts...)
+	data.RegisterUserDataServiceServer(grpcServer, datahandler.NewUserDataHandler(userdataSrv, changes))
+	auth.RegisterAuthServiceServer(grpcServer, datahandler.NewAuthHandler(userSrv, srpSrv, sessionSrv, secondFactorSrv, jwtAuth, jwtChallenge, loginLimiter, accountSrv))
+	vault.RegisterVaultServiceServer(grpcServer, datahandler.NewVaultHandler(vaultSrv))
+	session.RegisterSessionServiceServer(grpcServer, datahandler.NewSessionHandler(sessionSrv))
+	keys.RegisterKeyServiceServer(grpcServer, datahandler.NewKeyHandler(tokenKeys))
+	token.RegisterTokenServiceServer(grpcServer, datahandler.NewTokenHandler(apiTokenSrv))
 	reflection.Register(grpcServer)
 
 	wg := &sync.WaitGroup{}
@@ -99,9 +250,43 @@ func main() {
 		wg.Done()
 	}()
 
+	go func() {
+		ticker := time.NewTicker(purgeInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				n, pErr := accountSrv.PurgeDue(exitCtx)
+				if pErr != nil {
+					logger.Error("account purge failed", "error", pErr)
+				}
+				if n > 0 {
+					logger.Info("accounts purged", "count", n)
+				}
+				if _, pErr = srpSrv.PurgeExpired(exitCtx); pErr != nil {
+					logger.Error("srp handshake purge failed", "error", pErr)
+				}
+				if _, pErr = userdataSrv.PruneRevisions(exitCtx); pErr != nil {
+					logger.Error("user data history prune failed", "error", pErr)
+				}
+				if _, pErr = userdataSrv.PruneTombstones(exitCtx); pErr != nil {
+					logger.Error("user data tombstone prune failed", "error", pErr)
+				}
+				if _, pErr = userdataSrv.PruneBatches(exitCtx); pErr != nil {
+					logger.Error("user data batch prune failed", "error", pErr)
+				}
+			case <-exitCtx.Done():
+				return
+			}
+		}
+	}()
+
 	go func() {
 		<-exitCtx.Done()
 
+		// Открытые потоки изменений не дают серверу остановиться.
+		changes.Close()
 		if grpcServer != nil {
 			grpcServer.GracefulStop()
 			logger.Debug("grpc server gracefully stopped")
@@ -112,3 +297,118 @@ func main() {
 
 	logger.Debug("server shutdown gracefully")
 }
+
+// loadTokenKeys загружает ключи подписи токенов: асимметричные из каталога или общий секрет HS256.
+// Ключи из каталога общие для access токенов и токенов входа, их разделяет назначение (aud).
+func loadTokenKeys(cfg *config.Config) (*security.KeySet, *security.KeySet, error) {
+	if cfg.JWTKeyDir == "" {
+		// Отдельный секрет не дает использовать токен входа как access токен и наоборот.
+		return security.NewSecretKeySet(cfg.AuthSecret), security.NewSecretKeySet(cfg.AuthSecret + ":second-factor"), nil
+	}
+
+	ks, err := security.LoadKeyDir(cfg.JWTKeyDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err = ks.Signing(time.Now()); err != nil {
+		return nil, nil, err
+	}
+	return ks, ks, nil
+}
+
+// loadSRPSecret возвращает секрет для соли и верификатора несуществующих логинов. Секрет отдельный от мастер-ключей:
+// при его смене меняются ответы на несуществующие логины, и их можно отличить от существующих.
+func loadSRPSecret(cfg *config.Config) ([]byte, error) {
+	if cfg.SRPSecret == "" {
+		return nil, errors.New("SRP_SECRET is required")
+	}
+	secret, err := base64.StdEncoding.DecodeString(cfg.SRPSecret)
+	if err != nil {
+		return nil, err
+	}
+	if len(secret) < minSRPSecretLen {
+		return nil, fmt.Errorf("SRP_SECRET must be at least %d bytes", minSRPSecretLen)
+	}
+	return secret, nil
+}
+
+// newCertReloader загружает сертификаты сервера, привязка клиентских сертификатов требует CA для их проверки.
+func newCertReloader(cfg config.TLS, logger *slog.Logger) (*certs.Reloader, error) {
+	if cfg.TLSBindClientCert && cfg.TLSClientCAFile == "" {
+		return nil, certs.ErrClientCARequired
+	}
+	return certs.New(certs.Files{
+		CertFile:          cfg.TLSCertFile,
+		KeyFile:           cfg.TLSKeyFile,
+		ClientCAFile:      cfg.TLSClientCAFile,
+		RequireClientCert: cfg.TLSRequireClientCert,
+	}, logger)
+}
+
+// parseCommand отделяет команду (первый аргумент, не являющийся флагом) от флагов.
+func parseCommand(args []string) (string, []string) {
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		return args[0], args[1:]
+	}
+	return "", args
+}
+
+// splitCommandArgs отделяет аргументы команды (идущие до первого флага) от флагов.
+func splitCommandArgs(args []string) ([]string, []string) {
+	for i, a := range args {
+		if strings.HasPrefix(a, "-") {
+			return args[:i], args[i:]
+		}
+	}
+	return args, nil
+}
+
+// runMigrate выполняет команду migrate.
+func runMigrate(ctx context.Context, m *migrate.Migrator, args []string) error {
+	action := "up"
+	if len(args) > 0 {
+		action = args[0]
+	}
+
+	switch {
+	case action == "up" && len(args) <= 1:
+		applied, err := m.Up(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("applied %d migration(s)\n", len(applied))
+		return nil
+	case action == "down" && len(args) <= 2:
+		steps := 1
+		if len(args) == 2 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid number of steps: %s", args[1])
+			}
+			steps = n
+		}
+		reverted, err := m.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("reverted %d migration(s)\n", len(reverted))
+		return nil
+	case action == "status" && len(args) == 1:
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range statuses {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.Format(time.RFC3339)
+			}
+			_, _ = fmt.Fprintf(w, "%04d\t%s\t%s\n", s.Version, s.Name, applied)
+		}
+		return w.Flush()
+	default:
+		return fmt.Errorf("usage: migrate up | migrate down [n] | migrate status")
+	}
+}
@@ -0,0 +1,412 @@
+package userdata
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ktigay/goph-keeper/internal/client/crypto"
+	ce "github.com/ktigay/goph-keeper/internal/client/entity"
+	"github.com/ktigay/goph-keeper/internal/entity"
+)
+
+// MetaDataTag название метаданных записи с тегом. По HMAC тегов сервер ограничивает токены доступа.
+const MetaDataTag = "tag"
+
+const (
+	// metaDataTitle название записи метаданных, содержащей зашифрованные метаданные.
+	metaDataTitle = "encrypted"
+
+	fieldTitle    = "title"
+	fieldData     = "data"
+	fieldMetaData = "metadata"
+)
+
+var (
+	// ErrMalformedMetaData некорректные зашифрованные метаданные.
+	ErrMalformedMetaData = errors.New("malformed encrypted metadata")
+	// ErrRevisionUnavailable версию записи не удалось восстановить: её ключ хранилища старше
+	// предыдущей версии, история при ротации ключа не перешифровывается.
+	ErrRevisionUnavailable = errors.New("item revision cannot be decrypted")
+	// ErrPlaintextData запись не зашифрована: создана до включения шифрования и ещё не перенесена
+	// [Client.MigratePlaintext].
+	ErrPlaintextData = errors.New("user data is not encrypted")
+	// ErrTagKeyNotFound у хранилища нет ключа тегов, он создаётся при ротации ключа хранилища.
+	ErrTagKeyNotFound = errors.New("vault tag key not found")
+)
+
+// Next клиент, которому передаются зашифрованные данные.
+//
+//go:generate mockgen -destination=./mocks/mock_next.go -package=mocks github.com/ktigay/goph-keeper/internal/client/client/encrypted/userdata Next
+type Next interface {
+	Create(ctx context.Context, d entity.UserData) (*entity.UserData, error)
+	Update(ctx context.Context, d entity.UserData) (*entity.UserData, error)
+	Read(ctx context.Context, uuid ...string) ([]entity.UserData, error)
+	Delete(ctx context.Context, uuids ...string) error
+	Revisions(ctx context.Context, itemUUID string) ([]entity.UserDataRevision, error)
+	Restore(ctx context.Context, itemUUID, revisionUUID string) (*entity.UserData, error)
+	Changes(ctx context.Context, since uint64) (*entity.UserDataChanges, error)
+	Watch(ctx context.Context, since uint64, apply func(*entity.UserDataChanges) error) error
+	SyncBatch(ctx context.Context, batch entity.UserDataBatch) (*entity.UserDataBatchResult, error)
+}
+
+// KeyRepository репозиторий ключей хранилища.
+//
+//go:generate mockgen -destination=./mocks/mock_keys.go -package=mocks github.com/ktigay/goph-keeper/internal/client/client/encrypted/userdata KeyRepository
+type KeyRepository interface {
+	CurrentKey(ctx context.Context) (*crypto.Key, error)
+	Key(ctx context.Context, version uint32) (*crypto.Key, error)
+	TagKey(ctx context.Context) (*crypto.Key, error)
+}
+
+// Client клиент, шифрующий пользовательские данные перед отправкой на сервер.
+// Название, данные и метаданные шифруются ключом хранилища, сервер видит только шифротекст
+// и HMAC тегов записи.
+type Client struct {
+	next Next
+	keys KeyRepository
+}
+
+// Create шифрует и создаёт запись пользовательских данных.
+func (c *Client) Create(ctx context.Context, d entity.UserData) (*entity.UserData, error) {
+	// UUID участвует в дополнительных данных шифра, поэтому назначается до шифрования.
+	if d.UUID == "" {
+		d.UUID = uuid.New().String()
+	}
+	enc, err := c.encrypt(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.next.Create(ctx, *enc)
+	if err != nil {
+		return nil, err
+	}
+	return c.decrypt(ctx, *resp)
+}
+
+// Read читает и расшифровывает записи пользовательских данных.
+func (c *Client) Read(ctx context.Context, uuid ...string) ([]entity.UserData, error) {
+	data, err := c.next.Read(ctx, uuid...)
+	if err != nil {
+		return nil, err
+	}
+	for i := range data {
+		var d *entity.UserData
+		if d, err = c.decrypt(ctx, data[i]); err != nil {
+			return nil, err
+		}
+		data[i] = *d
+	}
+	return data, nil
+}
+
+// Update шифрует и обновляет запись пользовательских данных.
+func (c *Client) Update(ctx context.Context, d entity.UserData) (*entity.UserData, error) {
+	enc, err := c.encrypt(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.next.Update(ctx, *enc)
+	if err != nil {
+		return nil, c.decryptConflict(ctx, err)
+	}
+	return c.decrypt(ctx, *resp)
+}
+
+// Delete удаляет записи пользовательских данных.
+func (c *Client) Delete(ctx context.Context, uuids ...string) error {
+	return c.next.Delete(ctx, uuids...)
+}
+
+// SyncBatch шифрует записи пакета изменений, отправляет его и расшифровывает записи результата.
+func (c *Client) SyncBatch(ctx context.Context, batch entity.UserDataBatch) (*entity.UserDataBatchResult, error) {
+	upserts := make([]entity.UserData, 0, len(batch.Upserts))
+	for _, d := range batch.Upserts {
+		enc, err := c.encrypt(ctx, d)
+		if err != nil {
+			return nil, err
+		}
+		upserts = append(upserts, *enc)
+	}
+	batch.Upserts = upserts
+
+	res, err := c.next.SyncBatch(ctx, batch)
+	if err != nil {
+		return nil, err
+	}
+	for i := range res.Items {
+		if res.Items[i].Item == nil {
+			continue
+		}
+		if res.Items[i].Item, err = c.decrypt(ctx, *res.Items[i].Item); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// Revisions читает и расшифровывает предыдущие версии записи. Версии, которые не удалось
+// расшифровать, например, на ключе старше предыдущего, возвращаются с признаком Unavailable.
+func (c *Client) Revisions(ctx context.Context, itemUUID string) ([]entity.UserDataRevision, error) {
+	revisions, err := c.next.Revisions(ctx, itemUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range revisions {
+		d, err := c.decrypt(ctx, revisions[i].Item)
+		if err != nil {
+			revisions[i].Unavailable = true
+			revisions[i].Item = entity.UserData{
+				UUID:       revisions[i].Item.UUID,
+				Type:       revisions[i].Item.Type,
+				Version:    revisions[i].Item.Version,
+				KeyVersion: revisions[i].Item.KeyVersion,
+				UpdatedAt:  revisions[i].Item.UpdatedAt,
+			}
+			continue
+		}
+		revisions[i].Item = *d
+	}
+	return revisions, nil
+}
+
+// Restore восстанавливает версию записи. Версию на текущем ключе хранилища восстанавливает сервер,
+// версию на предыдущем ключе клиент расшифровывает и сохраняет поверх записи текущим ключом:
+// сервер такие версии не восстанавливает.
+func (c *Client) Restore(ctx context.Context, itemUUID, revisionUUID string) (*entity.UserData, error) {
+	key, err := c.keys.CurrentKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	revisions, err := c.next.Revisions(ctx, itemUUID)
+	if err != nil {
+		return nil, err
+	}
+	i := slices.IndexFunc(revisions, func(r entity.UserDataRevision) bool { return r.UUID == revisionUUID })
+	if i < 0 || revisions[i].Item.KeyVersion == key.Version {
+		resp, err := c.next.Restore(ctx, itemUUID, revisionUUID)
+		if err != nil {
+			return nil, c.decryptConflict(ctx, err)
+		}
+		return c.decrypt(ctx, *resp)
+	}
+
+	d, err := c.decrypt(ctx, revisions[i].Item)
+	if err != nil {
+		return nil, fmt.Errorf("%w: revision %s, key version %d: %w",
+			ErrRevisionUnavailable, revisionUUID, revisions[i].Item.KeyVersion, err)
+	}
+	current, err := c.next.Read(ctx, itemUUID)
+	if err != nil {
+		return nil, err
+	}
+	d.UpdatedAt = time.Now()
+	if len(current) == 0 {
+		// Запись удалена, она создаётся заново с тем же UUID.
+		d.Version = 0
+		return c.Create(ctx, *d)
+	}
+	d.Version = current[0].Version
+	return c.Update(ctx, *d)
+}
+
+// Changes читает изменения записей после ревизии since и расшифровывает измененные записи.
+func (c *Client) Changes(ctx context.Context, since uint64) (*entity.UserDataChanges, error) {
+	changes, err := c.next.Changes(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.decryptChanges(ctx, changes); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// Watch подписывается на изменения записей после ревизии since и передает их apply расшифрованными.
+func (c *Client) Watch(ctx context.Context, since uint64, apply func(*entity.UserDataChanges) error) error {
+	return c.next.Watch(ctx, since, func(changes *entity.UserDataChanges) error {
+		if err := c.decryptChanges(ctx, changes); err != nil {
+			return err
+		}
+		return apply(changes)
+	})
+}
+
+func (c *Client) decryptChanges(ctx context.Context, changes *entity.UserDataChanges) error {
+	for i := range changes.Upserts {
+		d, err := c.decrypt(ctx, changes.Upserts[i])
+		if err != nil {
+			return err
+		}
+		changes.Upserts[i] = *d
+	}
+	return nil
+}
+
+// MigratePlaintext шифрует текущим ключом хранилища записи, созданные до включения шифрования,
+// и возвращает количество перенесенных. Только здесь принимаются записи без версии ключа,
+// при остальном чтении они - [ErrPlaintextData]. Запись, изменённая за это время другим
+// клиентом, пропускается: её уже перенес он.
+func (c *Client) MigratePlaintext(ctx context.Context) (int, error) {
+	data, err := c.next.Read(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var n int
+	for _, d := range data {
+		if d.KeyVersion != 0 {
+			continue
+		}
+		if _, err = c.Update(ctx, d); err != nil {
+			var conflict *ce.VersionConflictError
+			if errors.As(err, &conflict) {
+				continue
+			}
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// TagMAC возвращает HMAC тега для ограничения токена доступа (TokenScope.tag_mac).
+func (c *Client) TagMAC(ctx context.Context, tag string) ([]byte, error) {
+	key, err := c.keys.TagKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, ErrTagKeyNotFound
+	}
+	return key.MAC([]byte(tag)), nil
+}
+
+// EncryptWith шифрует расшифрованную запись ключом key и вычисляет HMAC её тегов ключом tagKey.
+// tagKey = nil - у хранилища ещё нет ключа тегов, HMAC не вычисляются.
+func (c *Client) EncryptWith(key, tagKey *crypto.Key, d entity.UserData) (*entity.UserData, error) {
+	if d.KeyVersion != 0 {
+		return nil, errors.New("user data is already encrypted")
+	}
+
+	d.TagMACs = nil
+	if tagKey != nil {
+		for _, m := range d.MetaData {
+			if m.Title == MetaDataTag {
+				d.TagMACs = append(d.TagMACs, tagKey.MAC([]byte(m.Value)))
+			}
+		}
+	}
+
+	title, err := key.Seal([]byte(d.Title), associatedData(d, fieldTitle))
+	if err != nil {
+		return nil, err
+	}
+	if d.Data, err = key.Seal(d.Data, associatedData(d, fieldData)); err != nil {
+		return nil, err
+	}
+
+	meta, err := json.Marshal(d.MetaData)
+	if err != nil {
+		return nil, err
+	}
+	if meta, err = key.Seal(meta, associatedData(d, fieldMetaData)); err != nil {
+		return nil, err
+	}
+
+	d.Title = base64.StdEncoding.EncodeToString(title)
+	d.MetaData = []entity.MetaData{{
+		Title: metaDataTitle,
+		Value: base64.StdEncoding.EncodeToString(meta),
+	}}
+	d.KeyVersion = key.Version
+	return &d, nil
+}
+
+func (c *Client) encrypt(ctx context.Context, d entity.UserData) (*entity.UserData, error) {
+	key, err := c.keys.CurrentKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tagKey, err := c.keys.TagKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.EncryptWith(key, tagKey, d)
+}
+
+func (c *Client) decrypt(ctx context.Context, d entity.UserData) (*entity.UserData, error) {
+	// Данные, созданные до включения шифрования, переносит только [Client.MigratePlaintext].
+	if d.KeyVersion == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrPlaintextData, d.UUID)
+	}
+
+	key, err := c.keys.Key(ctx, d.KeyVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	title, err := base64.StdEncoding.DecodeString(d.Title)
+	if err != nil {
+		return nil, err
+	}
+	if title, err = key.Open(title, associatedData(d, fieldTitle)); err != nil {
+		return nil, err
+	}
+	if d.Data, err = key.Open(d.Data, associatedData(d, fieldData)); err != nil {
+		return nil, err
+	}
+
+	if len(d.MetaData) != 1 || d.MetaData[0].Title != metaDataTitle {
+		return nil, ErrMalformedMetaData
+	}
+	meta, err := base64.StdEncoding.DecodeString(d.MetaData[0].Value)
+	if err != nil {
+		return nil, err
+	}
+	if meta, err = key.Open(meta, associatedData(d, fieldMetaData)); err != nil {
+		return nil, err
+	}
+	d.MetaData = nil
+	if err = json.Unmarshal(meta, &d.MetaData); err != nil {
+		return nil, err
+	}
+
+	d.Title = string(title)
+	d.KeyVersion = 0
+	return &d, nil
+}
+
+// decryptConflict расшифровывает серверную копию записи в ошибке конфликта версий.
+func (c *Client) decryptConflict(ctx context.Context, err error) error {
+	var conflict *ce.VersionConflictError
+	if !errors.As(err, &conflict) {
+		return err
+	}
+	current, dErr := c.decrypt(ctx, conflict.Current)
+	if dErr != nil {
+		return errors.Join(err, dErr)
+	}
+	return &ce.VersionConflictError{Current: *current}
+}
+
+// associatedData привязывает шифротекст к записи, её типу и полю,
+// чтобы сервер не мог переставить зашифрованные значения между записями.
+func associatedData(d entity.UserData, field string) []byte {
+	return []byte(d.UUID + "|" + string(d.Type) + "|" + field)
+}
+
+// New конструктор.
+func New(next Next, keys KeyRepository) *Client {
+	return &Client{
+		next: next,
+		keys: keys,
+	}
+}
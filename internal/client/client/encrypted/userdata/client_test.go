@@ -0,0 +1,497 @@
+package userdata
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/ktigay/goph-keeper/internal/client/client/encrypted/userdata/mocks"
+	"github.com/ktigay/goph-keeper/internal/client/crypto"
+	ce "github.com/ktigay/goph-keeper/internal/client/entity"
+	"github.com/ktigay/goph-keeper/internal/entity"
+)
+
+func TestClient_CreateRead(t *testing.T) {
+	key, err := crypto.GenerateKey(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain := entity.UserData{
+		UUID:     "9f1c2d4e-0000-4000-8000-000000000001",
+		Title:    "bank",
+		Type:     entity.DataTypeText,
+		Data:     []byte("secret"),
+		MetaData: []entity.MetaData{{Title: "site", Value: "example.com"}},
+		IsNew:    true,
+	}
+
+	ctrl := gomock.NewController(t)
+	keys := mocks.NewMockKeyRepository(ctrl)
+	keys.EXPECT().CurrentKey(gomock.Any()).AnyTimes().Return(key, nil)
+	keys.EXPECT().Key(gomock.Any(), uint32(1)).AnyTimes().Return(key, nil)
+	keys.EXPECT().TagKey(gomock.Any()).AnyTimes().Return(nil, nil)
+
+	var stored entity.UserData
+	next := mocks.NewMockNext(ctrl)
+	next.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+		func(_ context.Context, d entity.UserData) (*entity.UserData, error) {
+			if d.KeyVersion != 1 {
+				t.Errorf("Create() KeyVersion = %v, want %v", d.KeyVersion, 1)
+			}
+			if d.Title == plain.Title || string(d.Data) == string(plain.Data) {
+				t.Errorf("Create() sent plaintext")
+			}
+			if len(d.MetaData) != 1 || d.MetaData[0].Title != metaDataTitle {
+				t.Errorf("Create() sent metadata %v", d.MetaData)
+			}
+			stored = d
+			return &d, nil
+		})
+
+	c := New(next, keys)
+	got, err := c.Create(context.Background(), plain)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if !reflect.DeepEqual(*got, plain) {
+		t.Errorf("Create() got = %v, want %v", *got, plain)
+	}
+
+	legacy := entity.UserData{UUID: "legacy", Title: "plain", Type: entity.DataTypeText, Data: []byte("plain")}
+	swapped := stored
+	swapped.UUID = "9f1c2d4e-0000-4000-8000-000000000002"
+
+	tests := []struct {
+		name    string
+		remote  []entity.UserData
+		want    []entity.UserData
+		wantErr bool
+	}{
+		{
+			name:   "Read_Encrypted_Success",
+			remote: []entity.UserData{stored},
+			want:   []entity.UserData{plain},
+		},
+		{
+			name:    "Read_Legacy_Plaintext_Error",
+			remote:  []entity.UserData{legacy},
+			wantErr: true,
+		},
+		{
+			name:    "Read_Moved_Ciphertext_Error",
+			remote:  []entity.UserData{swapped},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next.EXPECT().Read(gomock.Any()).Times(1).Return(tt.remote, nil)
+			got, err := c.Read(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Read() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Read() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	next.EXPECT().Changes(gomock.Any(), uint64(2)).Times(1).Return(&entity.UserDataChanges{
+		Revision: 3,
+		Upserts:  []entity.UserData{stored},
+		Deleted:  []string{legacy.UUID},
+	}, nil)
+	changes, err := c.Changes(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("Changes() error = %v", err)
+	}
+	wantChanges := &entity.UserDataChanges{Revision: 3, Upserts: []entity.UserData{plain}, Deleted: []string{legacy.UUID}}
+	if !reflect.DeepEqual(changes, wantChanges) {
+		t.Errorf("Changes() got = %v, want %v", changes, wantChanges)
+	}
+
+	next.EXPECT().Watch(gomock.Any(), uint64(3), gomock.Any()).Times(1).
+		DoAndReturn(func(_ context.Context, _ uint64, apply func(*entity.UserDataChanges) error) error {
+			return apply(&entity.UserDataChanges{Revision: 4, Upserts: []entity.UserData{stored}})
+		})
+	var watched *entity.UserDataChanges
+	err = c.Watch(context.Background(), 3, func(changes *entity.UserDataChanges) error {
+		watched = changes
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	if want := (&entity.UserDataChanges{Revision: 4, Upserts: []entity.UserData{plain}}); !reflect.DeepEqual(watched, want) {
+		t.Errorf("Watch() got = %v, want %v", watched, want)
+	}
+}
+
+func TestClient_MigratePlaintext(t *testing.T) {
+	key, err := crypto.GenerateKey(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy := entity.UserData{UUID: "9f1c2d4e-0000-4000-8000-000000000007", Title: "plain", Type: entity.DataTypeText, Data: []byte("plain"), Version: 3}
+	raced := entity.UserData{UUID: "9f1c2d4e-0000-4000-8000-000000000008", Title: "raced", Type: entity.DataTypeText, Data: []byte("raced"), Version: 1}
+
+	ctrl := gomock.NewController(t)
+	keys := mocks.NewMockKeyRepository(ctrl)
+	keys.EXPECT().CurrentKey(gomock.Any()).AnyTimes().Return(key, nil)
+	keys.EXPECT().Key(gomock.Any(), uint32(1)).AnyTimes().Return(key, nil)
+	keys.EXPECT().TagKey(gomock.Any()).AnyTimes().Return(nil, nil)
+
+	c := New(nil, keys)
+	encrypted, err := c.EncryptWith(key, nil, entity.UserData{UUID: "9f1c2d4e-0000-4000-8000-000000000009", Title: "done", Type: entity.DataTypeText})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next := mocks.NewMockNext(ctrl)
+	next.EXPECT().Read(gomock.Any()).Times(1).Return([]entity.UserData{legacy, raced, *encrypted}, nil)
+	next.EXPECT().Update(gomock.Any(), gomock.Any()).Times(2).DoAndReturn(
+		func(_ context.Context, d entity.UserData) (*entity.UserData, error) {
+			if d.KeyVersion != key.Version || d.Title == legacy.Title {
+				t.Errorf("Update() sent plaintext %+v", d)
+			}
+			if d.UUID == raced.UUID {
+				moved, _ := c.EncryptWith(key, nil, raced)
+				moved.Version = 2
+				return nil, &ce.VersionConflictError{Current: *moved}
+			}
+			d.Version++
+			return &d, nil
+		})
+	c.next = next
+
+	n, err := c.MigratePlaintext(context.Background())
+	if err != nil {
+		t.Fatalf("MigratePlaintext() error = %v", err)
+	}
+	if n != 1 {
+		t.Errorf("MigratePlaintext() = %d, want 1", n)
+	}
+}
+
+func TestClient_TagMACs(t *testing.T) {
+	key, err := crypto.GenerateKey(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tagKey, err := crypto.GenerateKey(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain := entity.UserData{
+		UUID:  "9f1c2d4e-0000-4000-8000-000000000006",
+		Title: "deploy key",
+		Type:  entity.DataTypeText,
+		Data:  []byte("secret"),
+		MetaData: []entity.MetaData{
+			{Title: MetaDataTag, Value: "deploy"},
+			{Title: "site", Value: "example.com"},
+			{Title: MetaDataTag, Value: "prod"},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	keys := mocks.NewMockKeyRepository(ctrl)
+	keys.EXPECT().CurrentKey(gomock.Any()).AnyTimes().Return(key, nil)
+	keys.EXPECT().TagKey(gomock.Any()).AnyTimes().Return(tagKey, nil)
+
+	c := New(nil, keys)
+	sealed, err := c.encrypt(context.Background(), plain)
+	if err != nil {
+		t.Fatalf("encrypt() error = %v", err)
+	}
+	deploy, err := c.TagMAC(context.Background(), "deploy")
+	if err != nil {
+		t.Fatalf("TagMAC() error = %v", err)
+	}
+	prod, _ := c.TagMAC(context.Background(), "prod")
+	if want := [][]byte{deploy, prod}; !reflect.DeepEqual(sealed.TagMACs, want) {
+		t.Errorf("encrypt() TagMACs = %x, want %x", sealed.TagMACs, want)
+	}
+
+	noTagKey := mocks.NewMockKeyRepository(ctrl)
+	noTagKey.EXPECT().TagKey(gomock.Any()).Times(1).Return(nil, nil)
+	if _, err = New(nil, noTagKey).TagMAC(context.Background(), "deploy"); !errors.Is(err, ErrTagKeyNotFound) {
+		t.Errorf("TagMAC() error = %v, want %v", err, ErrTagKeyNotFound)
+	}
+}
+
+func TestClient_UpdateConflict(t *testing.T) {
+	key, err := crypto.GenerateKey(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := entity.UserData{
+		UUID:     "9f1c2d4e-0000-4000-8000-000000000002",
+		Title:    "server title",
+		Type:     entity.DataTypeText,
+		Data:     []byte("server secret"),
+		MetaData: []entity.MetaData{},
+		Version:  5,
+	}
+
+	ctrl := gomock.NewController(t)
+	keys := mocks.NewMockKeyRepository(ctrl)
+	keys.EXPECT().CurrentKey(gomock.Any()).AnyTimes().Return(key, nil)
+	keys.EXPECT().Key(gomock.Any(), uint32(1)).AnyTimes().Return(key, nil)
+	keys.EXPECT().TagKey(gomock.Any()).AnyTimes().Return(nil, nil)
+
+	c := New(nil, keys)
+	sealed, err := c.EncryptWith(key, nil, server)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next := mocks.NewMockNext(ctrl)
+	next.EXPECT().Update(gomock.Any(), gomock.Any()).Times(1).Return(nil, &ce.VersionConflictError{Current: *sealed})
+	c.next = next
+
+	_, err = c.Update(context.Background(), entity.UserData{UUID: server.UUID, Title: "local", Type: entity.DataTypeText, Data: []byte("local"), Version: 4})
+	var conflict *ce.VersionConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("Update() error = %v, want VersionConflictError", err)
+	}
+	if !reflect.DeepEqual(conflict.Current, server) {
+		t.Errorf("Update() conflict current = %v, want %v", conflict.Current, server)
+	}
+}
+
+func TestClient_SyncBatch(t *testing.T) {
+	key, err := crypto.GenerateKey(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain := entity.UserData{
+		UUID:     "9f1c2d4e-0000-4000-8000-000000000003",
+		Title:    "bank",
+		Type:     entity.DataTypeText,
+		Data:     []byte("secret"),
+		MetaData: []entity.MetaData{},
+		Version:  2,
+	}
+
+	ctrl := gomock.NewController(t)
+	keys := mocks.NewMockKeyRepository(ctrl)
+	keys.EXPECT().CurrentKey(gomock.Any()).AnyTimes().Return(key, nil)
+	keys.EXPECT().Key(gomock.Any(), uint32(1)).AnyTimes().Return(key, nil)
+	keys.EXPECT().TagKey(gomock.Any()).AnyTimes().Return(nil, nil)
+
+	next := mocks.NewMockNext(ctrl)
+	next.EXPECT().SyncBatch(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+		func(_ context.Context, b entity.UserDataBatch) (*entity.UserDataBatchResult, error) {
+			if len(b.Upserts) != 1 || b.Upserts[0].Title == plain.Title || string(b.Upserts[0].Data) == string(plain.Data) {
+				t.Errorf("SyncBatch() sent plaintext")
+			}
+			if b.Upserts[0].Version != plain.Version {
+				t.Errorf("SyncBatch() Version = %v, want %v", b.Upserts[0].Version, plain.Version)
+			}
+			saved := b.Upserts[0]
+			saved.Version++
+			return &entity.UserDataBatchResult{Items: []entity.UserDataBatchItem{
+				{UUID: saved.UUID, Status: entity.BatchItemApplied, Item: &saved},
+				{UUID: "9f1c2d4e-0000-4000-8000-000000000004", Status: entity.BatchItemApplied, Deleted: true},
+			}}, nil
+		})
+
+	c := New(next, keys)
+	got, err := c.SyncBatch(context.Background(), entity.UserDataBatch{
+		UUID:    "9f1c2d4e-0000-4000-8000-0000000000ff",
+		Upserts: []entity.UserData{plain},
+		Deletes: []string{"9f1c2d4e-0000-4000-8000-000000000004"},
+	})
+	if err != nil {
+		t.Fatalf("SyncBatch() error = %v", err)
+	}
+	want := plain
+	want.Version = 3
+	if len(got.Items) != 2 || !reflect.DeepEqual(*got.Items[0].Item, want) || got.Items[1].Item != nil {
+		t.Errorf("SyncBatch() got = %+v, want decrypted %v", got.Items, want)
+	}
+}
+
+func TestClient_Revisions(t *testing.T) {
+	current, err := crypto.GenerateKey(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	retired, err := crypto.GenerateKey(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain := entity.UserData{
+		UUID:     "9f1c2d4e-0000-4000-8000-000000000005",
+		Title:    "wifi",
+		Type:     entity.DataTypeText,
+		Data:     []byte("password"),
+		MetaData: []entity.MetaData{},
+	}
+
+	ctrl := gomock.NewController(t)
+	keys := mocks.NewMockKeyRepository(ctrl)
+	keys.EXPECT().Key(gomock.Any(), uint32(3)).AnyTimes().Return(current, nil)
+	keys.EXPECT().Key(gomock.Any(), uint32(1)).AnyTimes().Return(nil, errors.New("vault key version not found"))
+
+	c := New(nil, keys)
+	fresh, err := c.EncryptWith(current, nil, plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old, err := c.EncryptWith(retired, nil, plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Decrypted", func(t *testing.T) {
+		next := mocks.NewMockNext(ctrl)
+		next.EXPECT().Revisions(gomock.Any(), plain.UUID).Times(1).Return([]entity.UserDataRevision{{UUID: "rev-1", Item: *fresh}}, nil)
+		c.next = next
+
+		got, err := c.Revisions(context.Background(), plain.UUID)
+		if err != nil {
+			t.Fatalf("Revisions() error = %v", err)
+		}
+		if len(got) != 1 || !reflect.DeepEqual(got[0].Item, plain) {
+			t.Errorf("Revisions() got = %+v, want %v", got, plain)
+		}
+	})
+
+	t.Run("Retired_Key_Unavailable", func(t *testing.T) {
+		next := mocks.NewMockNext(ctrl)
+		next.EXPECT().Revisions(gomock.Any(), plain.UUID).Times(1).Return([]entity.UserDataRevision{
+			{UUID: "rev-2", Item: *fresh},
+			{UUID: "rev-1", Item: *old},
+		}, nil)
+		c.next = next
+
+		got, err := c.Revisions(context.Background(), plain.UUID)
+		if err != nil {
+			t.Fatalf("Revisions() error = %v", err)
+		}
+		if len(got) != 2 || got[0].Unavailable || !reflect.DeepEqual(got[0].Item, plain) {
+			t.Errorf("Revisions() got[0] = %+v, want %v", got, plain)
+		}
+		if !got[1].Unavailable || got[1].Item.KeyVersion != 1 || got[1].Item.Title != "" || got[1].Item.Data != nil {
+			t.Errorf("Revisions() got[1] = %+v, want unavailable on key version 1", got[1])
+		}
+	})
+}
+
+func TestClient_Restore(t *testing.T) {
+	current, err := crypto.GenerateKey(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous, err := crypto.GenerateKey(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	retired, err := crypto.GenerateKey(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain := entity.UserData{
+		UUID:     "9f1c2d4e-0000-4000-8000-000000000006",
+		Title:    "wifi",
+		Type:     entity.DataTypeText,
+		Data:     []byte("password"),
+		MetaData: []entity.MetaData{},
+	}
+
+	ctrl := gomock.NewController(t)
+	keys := mocks.NewMockKeyRepository(ctrl)
+	keys.EXPECT().CurrentKey(gomock.Any()).AnyTimes().Return(current, nil)
+	keys.EXPECT().TagKey(gomock.Any()).AnyTimes().Return(nil, nil)
+	keys.EXPECT().Key(gomock.Any(), uint32(3)).AnyTimes().Return(current, nil)
+	keys.EXPECT().Key(gomock.Any(), uint32(2)).AnyTimes().Return(previous, nil)
+	keys.EXPECT().Key(gomock.Any(), uint32(1)).AnyTimes().Return(nil, errors.New("vault key version not found"))
+
+	c := New(nil, keys)
+	encrypt := func(key *crypto.Key) entity.UserData {
+		d, err := c.EncryptWith(key, nil, plain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d.Version = 2
+		return *d
+	}
+	onCurrent, onPrevious, onRetired := encrypt(current), encrypt(previous), encrypt(retired)
+
+	t.Run("Current_Key_On_Server", func(t *testing.T) {
+		next := mocks.NewMockNext(ctrl)
+		next.EXPECT().Revisions(gomock.Any(), plain.UUID).Times(1).Return([]entity.UserDataRevision{{UUID: "rev-1", Item: onCurrent}}, nil)
+		next.EXPECT().Restore(gomock.Any(), plain.UUID, "rev-1").Times(1).Return(&onCurrent, nil)
+		c.next = next
+
+		got, err := c.Restore(context.Background(), plain.UUID, "rev-1")
+		if err != nil {
+			t.Fatalf("Restore() error = %v", err)
+		}
+		if got.Title != plain.Title {
+			t.Errorf("Restore() got = %+v, want %v", got, plain)
+		}
+	})
+
+	t.Run("Previous_Key_Reencrypted", func(t *testing.T) {
+		next := mocks.NewMockNext(ctrl)
+		next.EXPECT().Revisions(gomock.Any(), plain.UUID).Times(1).Return([]entity.UserDataRevision{{UUID: "rev-1", Item: onPrevious}}, nil)
+		next.EXPECT().Read(gomock.Any(), plain.UUID).Times(1).Return([]entity.UserData{{UUID: plain.UUID, Version: 5, KeyVersion: 3}}, nil)
+		next.EXPECT().Restore(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		next.EXPECT().Update(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+			func(_ context.Context, d entity.UserData) (*entity.UserData, error) {
+				if d.KeyVersion != current.Version || d.Version != 5 {
+					t.Errorf("Update() KeyVersion = %d, Version = %d, want %d, 5", d.KeyVersion, d.Version, current.Version)
+				}
+				d.Version++
+				return &d, nil
+			})
+		c.next = next
+
+		got, err := c.Restore(context.Background(), plain.UUID, "rev-1")
+		if err != nil {
+			t.Fatalf("Restore() error = %v", err)
+		}
+		if got.Title != plain.Title || got.Version != 6 {
+			t.Errorf("Restore() got = %+v, want %v on version 6", got, plain)
+		}
+	})
+
+	t.Run("Previous_Key_Deleted_Created", func(t *testing.T) {
+		next := mocks.NewMockNext(ctrl)
+		next.EXPECT().Revisions(gomock.Any(), plain.UUID).Times(1).Return([]entity.UserDataRevision{{UUID: "rev-1", Item: onPrevious, Deleted: true}}, nil)
+		next.EXPECT().Read(gomock.Any(), plain.UUID).Times(1).Return([]entity.UserData{}, nil)
+		next.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+			func(_ context.Context, d entity.UserData) (*entity.UserData, error) {
+				if d.UUID != plain.UUID || d.KeyVersion != current.Version {
+					t.Errorf("Create() UUID = %s, KeyVersion = %d", d.UUID, d.KeyVersion)
+				}
+				d.Version = 1
+				return &d, nil
+			})
+		c.next = next
+
+		if _, err := c.Restore(context.Background(), plain.UUID, "rev-1"); err != nil {
+			t.Fatalf("Restore() error = %v", err)
+		}
+	})
+
+	t.Run("Retired_Key_Error", func(t *testing.T) {
+		next := mocks.NewMockNext(ctrl)
+		next.EXPECT().Revisions(gomock.Any(), plain.UUID).Times(1).Return([]entity.UserDataRevision{{UUID: "rev-1", Item: onRetired}}, nil)
+		c.next = next
+
+		if _, err := c.Restore(context.Background(), plain.UUID, "rev-1"); !errors.Is(err, ErrRevisionUnavailable) {
+			t.Errorf("Restore() error = %v, want %v", err, ErrRevisionUnavailable)
+		}
+	})
+}
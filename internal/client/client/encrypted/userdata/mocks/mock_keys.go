@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ktigay/goph-keeper/internal/client/client/encrypted/userdata (interfaces: KeyRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	crypto "github.com/ktigay/goph-keeper/internal/client/crypto"
+)
+
+// MockKeyRepository is a mock of KeyRepository interface.
+type MockKeyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockKeyRepositoryMockRecorder
+}
+
+// MockKeyRepositoryMockRecorder is the mock recorder for MockKeyRepository.
+type MockKeyRepositoryMockRecorder struct {
+	mock *MockKeyRepository
+}
+
+// NewMockKeyRepository creates a new mock instance.
+func NewMockKeyRepository(ctrl *gomock.Controller) *MockKeyRepository {
+	mock := &MockKeyRepository{ctrl: ctrl}
+	mock.recorder = &MockKeyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKeyRepository) EXPECT() *MockKeyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CurrentKey mocks base method.
+func (m *MockKeyRepository) CurrentKey(arg0 context.Context) (*crypto.Key, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CurrentKey", arg0)
+	ret0, _ := ret[0].(*crypto.Key)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CurrentKey indicates an expected call of CurrentKey.
+func (mr *MockKeyRepositoryMockRecorder) CurrentKey(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CurrentKey", reflect.TypeOf((*MockKeyRepository)(nil).CurrentKey), arg0)
+}
+
+// Key mocks base method.
+func (m *MockKeyRepository) Key(arg0 context.Context, arg1 uint32) (*crypto.Key, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Key", arg0, arg1)
+	ret0, _ := ret[0].(*crypto.Key)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Key indicates an expected call of Key.
+func (mr *MockKeyRepositoryMockRecorder) Key(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Key", reflect.TypeOf((*MockKeyRepository)(nil).Key), arg0, arg1)
+}
+
+// TagKey mocks base method.
+func (m *MockKeyRepository) TagKey(arg0 context.Context) (*crypto.Key, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TagKey", arg0)
+	ret0, _ := ret[0].(*crypto.Key)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TagKey indicates an expected call of TagKey.
+func (mr *MockKeyRepositoryMockRecorder) TagKey(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TagKey", reflect.TypeOf((*MockKeyRepository)(nil).TagKey), arg0)
+}
@@ -0,0 +1,179 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ktigay/goph-keeper/internal/client/client/encrypted/userdata (interfaces: Next)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	entity "github.com/ktigay/goph-keeper/internal/entity"
+)
+
+// MockNext is a mock of Next interface.
+type MockNext struct {
+	ctrl     *gomock.Controller
+	recorder *MockNextMockRecorder
+}
+
+// MockNextMockRecorder is the mock recorder for MockNext.
+type MockNextMockRecorder struct {
+	mock *MockNext
+}
+
+// NewMockNext creates a new mock instance.
+func NewMockNext(ctrl *gomock.Controller) *MockNext {
+	mock := &MockNext{ctrl: ctrl}
+	mock.recorder = &MockNextMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNext) EXPECT() *MockNextMockRecorder {
+	return m.recorder
+}
+
+// Changes mocks base method.
+func (m *MockNext) Changes(arg0 context.Context, arg1 uint64) (*entity.UserDataChanges, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Changes", arg0, arg1)
+	ret0, _ := ret[0].(*entity.UserDataChanges)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Changes indicates an expected call of Changes.
+func (mr *MockNextMockRecorder) Changes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Changes", reflect.TypeOf((*MockNext)(nil).Changes), arg0, arg1)
+}
+
+// Create mocks base method.
+func (m *MockNext) Create(arg0 context.Context, arg1 entity.UserData) (*entity.UserData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(*entity.UserData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockNextMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockNext)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method.
+func (m *MockNext) Delete(arg0 context.Context, arg1 ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Delete", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockNextMockRecorder) Delete(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockNext)(nil).Delete), varargs...)
+}
+
+// Read mocks base method.
+func (m *MockNext) Read(arg0 context.Context, arg1 ...string) ([]entity.UserData, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Read", varargs...)
+	ret0, _ := ret[0].([]entity.UserData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Read indicates an expected call of Read.
+func (mr *MockNextMockRecorder) Read(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Read", reflect.TypeOf((*MockNext)(nil).Read), varargs...)
+}
+
+// Restore mocks base method.
+func (m *MockNext) Restore(arg0 context.Context, arg1, arg2 string) (*entity.UserData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*entity.UserData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockNextMockRecorder) Restore(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockNext)(nil).Restore), arg0, arg1, arg2)
+}
+
+// Revisions mocks base method.
+func (m *MockNext) Revisions(arg0 context.Context, arg1 string) ([]entity.UserDataRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revisions", arg0, arg1)
+	ret0, _ := ret[0].([]entity.UserDataRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revisions indicates an expected call of Revisions.
+func (mr *MockNextMockRecorder) Revisions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revisions", reflect.TypeOf((*MockNext)(nil).Revisions), arg0, arg1)
+}
+
+// SyncBatch mocks base method.
+func (m *MockNext) SyncBatch(arg0 context.Context, arg1 entity.UserDataBatch) (*entity.UserDataBatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncBatch", arg0, arg1)
+	ret0, _ := ret[0].(*entity.UserDataBatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SyncBatch indicates an expected call of SyncBatch.
+func (mr *MockNextMockRecorder) SyncBatch(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncBatch", reflect.TypeOf((*MockNext)(nil).SyncBatch), arg0, arg1)
+}
+
+// Update mocks base method.
+func (m *MockNext) Update(arg0 context.Context, arg1 entity.UserData) (*entity.UserData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(*entity.UserData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockNextMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockNext)(nil).Update), arg0, arg1)
+}
+
+// Watch mocks base method.
+func (m *MockNext) Watch(arg0 context.Context, arg1 uint64, arg2 func(*entity.UserDataChanges) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Watch", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Watch indicates an expected call of Watch.
+func (mr *MockNextMockRecorder) Watch(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*MockNext)(nil).Watch), arg0, arg1, arg2)
+}
@@ -0,0 +1,105 @@
+package account
+
+import (
+	"context"
+	"time"
+
+	"github.com/ktigay/goph-keeper/internal/client/client/grpc/errmap"
+	"github.com/ktigay/goph-keeper/internal/contracts/v1/auth"
+	"github.com/ktigay/goph-keeper/internal/srp"
+)
+
+// Client клиент.
+type Client struct {
+	conn auth.AuthServiceClient
+}
+
+// passwordProof подтверждение текущего пароля: доказательство SRP или сам пароль,
+// если у пользователя ещё нет верификатора.
+type passwordProof struct {
+	handshakeID string
+	proof       []byte
+	password    string
+}
+
+// ChangePassword меняет пароль, новый пароль передается серверу только верификатором SRP.
+// Возвращает количество отозванных сессий других устройств.
+func (c *Client) ChangePassword(ctx context.Context, current, next string) (uint32, error) {
+	p, err := c.prove(ctx, current)
+	if err != nil {
+		return 0, err
+	}
+	salt, err := srp.NewSalt()
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := c.conn.ChangePassword(ctx, &auth.ChangePasswordRequest{
+		CurrentPassword: p.password,
+		HandshakeId:     p.handshakeID,
+		ClientProof:     p.proof,
+		NewSalt:         salt,
+		NewVerifier:     srp.Verifier(next, salt),
+	})
+	if err != nil {
+		return 0, errmap.Map(err)
+	}
+	return resp.GetRevokedSessions(), nil
+}
+
+// DeleteAccount запрашивает удаление аккаунта. Возвращает время, после которого аккаунт будет удален.
+func (c *Client) DeleteAccount(ctx context.Context, password string) (time.Time, error) {
+	p, err := c.prove(ctx, password)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	resp, err := c.conn.DeleteAccount(ctx, &auth.DeleteAccountRequest{
+		Password:    p.password,
+		HandshakeId: p.handshakeID,
+		ClientProof: p.proof,
+	})
+	if err != nil {
+		return time.Time{}, errmap.Map(err)
+	}
+	return resp.GetDeleteAfter().AsTime(), nil
+}
+
+// CancelDeletion отменяет удаление аккаунта.
+func (c *Client) CancelDeletion(ctx context.Context) error {
+	if _, err := c.conn.CancelAccountDeletion(ctx, &auth.CancelAccountDeletionRequest{}); err != nil {
+		return errmap.Map(err)
+	}
+	return nil
+}
+
+// prove подтверждает текущий пароль обменом SRP.
+func (c *Client) prove(ctx context.Context, password string) (*passwordProof, error) {
+	client, err := srp.NewClient(password)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.conn.SrpProofStart(ctx, &auth.SrpProofStartRequest{
+		ClientPublic: client.PublicKey(),
+	})
+	if err != nil {
+		return nil, errmap.Map(err)
+	}
+	if resp.GetLegacy() {
+		return &passwordProof{password: password}, nil
+	}
+
+	proof, err := client.Proof(resp.GetSalt(), resp.GetServerPublic())
+	if err != nil {
+		return nil, err
+	}
+	return &passwordProof{handshakeID: resp.GetHandshakeId(), proof: proof}, nil
+}
+
+// New конструктор.
+func New(c auth.AuthServiceClient) *Client {
+	return &Client{
+		conn: c,
+	}
+}
@@ -3,46 +3,140 @@ package auth
 import (
 	"context"
 
+	"github.com/ktigay/goph-keeper/internal/client/client/grpc/errmap"
 	"github.com/ktigay/goph-keeper/internal/client/entity"
 	"github.com/ktigay/goph-keeper/internal/contracts/v1/auth"
 )
 
 // Client grpc клиент.
 type Client struct {
-	conn auth.AuthServiceClient
+	conn          auth.AuthServiceClient
+	deviceName    string
+	clientVersion string
 }
 
-// Login авторизует пользователя.
-func (c *Client) Login(ctx context.Context, data entity.Credentials) (string, error) {
+// Login авторизует пользователя по паролю, если у него ещё нет верификатора SRP.
+// Переданный верификатор сервер сохраняет, следующий вход выполняется по SRP.
+// Если для аккаунта включен второй фактор, возвращает только токен подтверждения.
+func (c *Client) Login(ctx context.Context, data entity.Credentials, v entity.SRPVerifier) (*entity.Tokens, string, error) {
 	req := &auth.LoginRequest{
-		Login:    data.Login,
-		Password: data.Password,
+		Login:         data.Login,
+		Password:      data.Password,
+		DeviceName:    c.deviceName,
+		ClientVersion: c.clientVersion,
+		SrpSalt:       v.Salt,
+		SrpVerifier:   v.Verifier,
 	}
 
 	resp, err := c.conn.Login(ctx, req)
 	if err != nil {
-		return "", err
+		return nil, "", errmap.Map(err)
+	}
+	if resp.GetChallengeToken() != "" {
+		return nil, resp.GetChallengeToken(), nil
+	}
+
+	return &entity.Tokens{
+		Access:  resp.Token,
+		Refresh: resp.RefreshToken,
+	}, "", nil
+}
+
+// SrpStart начинает вход по SRP.
+func (c *Client) SrpStart(ctx context.Context, login string, clientPublic []byte) (*entity.SRPChallenge, error) {
+	resp, err := c.conn.SrpStart(ctx, &auth.SrpStartRequest{
+		Login:        login,
+		ClientPublic: clientPublic,
+	})
+	if err != nil {
+		return nil, errmap.Map(err)
+	}
+
+	return &entity.SRPChallenge{
+		HandshakeID:  resp.GetHandshakeId(),
+		Salt:         resp.GetSalt(),
+		ServerPublic: resp.GetServerPublic(),
+		Legacy:       resp.GetLegacy(),
+	}, nil
+}
+
+// SrpLogin завершает вход по SRP доказательством клиента.
+func (c *Client) SrpLogin(ctx context.Context, handshakeID string, proof []byte) (*entity.SRPLogin, error) {
+	resp, err := c.conn.SrpLogin(ctx, &auth.SrpLoginRequest{
+		HandshakeId:   handshakeID,
+		ClientProof:   proof,
+		DeviceName:    c.deviceName,
+		ClientVersion: c.clientVersion,
+	})
+	if err != nil {
+		return nil, errmap.Map(err)
+	}
+
+	res := &entity.SRPLogin{
+		ServerProof: resp.GetServerProof(),
+		Challenge:   resp.GetChallengeToken(),
+	}
+	if res.Challenge == "" {
+		res.Tokens = &entity.Tokens{
+			Access:  resp.Token,
+			Refresh: resp.RefreshToken,
+		}
+	}
+	return res, nil
+}
+
+// VerifySecondFactor завершает вход кодом второго фактора.
+func (c *Client) VerifySecondFactor(ctx context.Context, challenge, code string) (*entity.Tokens, error) {
+	resp, err := c.conn.VerifySecondFactor(ctx, &auth.VerifySecondFactorRequest{
+		ChallengeToken: challenge,
+		Code:           code,
+	})
+	if err != nil {
+		return nil, errmap.Map(err)
 	}
 
-	return resp.Token, nil
+	return &entity.Tokens{
+		Access:  resp.Token,
+		Refresh: resp.RefreshToken,
+	}, nil
 }
 
-// Register регистрирует пользователя.
-func (c *Client) Register(ctx context.Context, data entity.Credentials) (string, error) {
-	req := &auth.RegisterRequest{
-		Login:    data.Login,
-		Password: data.Password,
+// Refresh обменивает refresh токен на новую пару токенов.
+func (c *Client) Refresh(ctx context.Context, refreshToken string) (*entity.Tokens, error) {
+	resp, err := c.conn.Refresh(ctx, &auth.RefreshRequest{
+		RefreshToken: refreshToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.Tokens{
+		Access:  resp.Token,
+		Refresh: resp.RefreshToken,
+	}, nil
+}
+
+// Register регистрирует пользователя с верификатором SRP.
+func (c *Client) Register(ctx context.Context, login string, v entity.SRPVerifier) (string, error) {
+	req := &auth.SrpRegisterRequest{
+		Login:    login,
+		Salt:     v.Salt,
+		Verifier: v.Verifier,
 	}
 
-	resp, err := c.conn.Register(ctx, req)
+	resp, err := c.conn.SrpRegister(ctx, req)
 	if err != nil {
-		return "", err
+		return "", errmap.Map(err)
 	}
 
 	return resp.UserUuid, nil
 }
 
-// New конструктор.
-func New(c auth.AuthServiceClient) *Client {
-	return &Client{c}
+// New конструктор. deviceName и clientVersion передаются серверу при входе для списка устройств.
+func New(c auth.AuthServiceClient, deviceName, clientVersion string) *Client {
+	return &Client{
+		conn:          c,
+		deviceName:    deviceName,
+		clientVersion: clientVersion,
+	}
 }
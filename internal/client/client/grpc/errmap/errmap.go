@@ -0,0 +1,56 @@
+package errmap
+
+import (
+	"fmt"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ktigay/goph-keeper/internal/client/entity"
+	e "github.com/ktigay/goph-keeper/internal/entity"
+)
+
+// reasonErrors ошибки клиента по причине из errdetails.ErrorInfo.
+var reasonErrors = map[string]error{
+	e.ReasonEmptyCredentials:        entity.ErrEmptyCredentials,
+	e.ReasonInvalidCredentials:      entity.ErrInvalidCredentials,
+	e.ReasonLoginExists:             entity.ErrLoginExists,
+	e.ReasonWrongPassword:           entity.ErrWrongPassword,
+	e.ReasonInvalidSecondFactorCode: entity.ErrInvalidSecondFactorCode,
+	e.ReasonLegacyLoginDisabled:     entity.ErrLegacyLoginDisabled,
+	e.ReasonHandshakeExpired:        entity.ErrHandshakeExpired,
+}
+
+// Map преобразует статус сервера в ошибки клиента по ErrorInfo и RetryInfo.
+func Map(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	if st.Code() == codes.ResourceExhausted {
+		locked := &entity.TooManyAttemptsError{}
+		for _, d := range st.Details() {
+			if info, ok := d.(*errdetails.RetryInfo); ok {
+				locked.RetryAfter = info.GetRetryDelay().AsDuration()
+			}
+		}
+		return locked
+	}
+
+	for _, d := range st.Details() {
+		info, ok := d.(*errdetails.ErrorInfo)
+		if !ok || info.GetDomain() != e.ErrorDomain {
+			continue
+		}
+		if mapped, ok := reasonErrors[info.GetReason()]; ok {
+			return mapped
+		}
+	}
+
+	if st.Code() == codes.Unavailable || st.Code() == codes.DeadlineExceeded {
+		return fmt.Errorf("%w: %w", entity.ErrServerUnavailable, err)
+	}
+	return err
+}
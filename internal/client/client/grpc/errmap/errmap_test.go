@@ -0,0 +1,82 @@
+package errmap
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/ktigay/goph-keeper/internal/client/entity"
+	e "github.com/ktigay/goph-keeper/internal/entity"
+)
+
+func statusErr(code codes.Code, details ...protoadapt.MessageV1) error {
+	st, _ := status.New(code, "server message").WithDetails(details...)
+	return st.Err()
+}
+
+func TestMap(t *testing.T) {
+	plain := errors.New("plain error")
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{
+			name: "Invalid_Credentials",
+			err:  statusErr(codes.Unauthenticated, &errdetails.ErrorInfo{Reason: e.ReasonInvalidCredentials, Domain: e.ErrorDomain}),
+			want: entity.ErrInvalidCredentials,
+		},
+		{
+			name: "Login_Exists",
+			err:  statusErr(codes.AlreadyExists, &errdetails.ErrorInfo{Reason: e.ReasonLoginExists, Domain: e.ErrorDomain}),
+			want: entity.ErrLoginExists,
+		},
+		{
+			name: "Foreign_Domain_Ignored",
+			err:  statusErr(codes.Unauthenticated, &errdetails.ErrorInfo{Reason: e.ReasonInvalidCredentials, Domain: "other"}),
+		},
+		{
+			name: "Unavailable",
+			err:  statusErr(codes.Unavailable),
+			want: entity.ErrServerUnavailable,
+		},
+		{
+			name: "Not_Status",
+			err:  plain,
+			want: plain,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Map(tt.err)
+			if tt.want == nil {
+				if got != tt.err {
+					t.Errorf("Map() = %v, want unchanged %v", got, tt.err)
+				}
+				return
+			}
+			if !errors.Is(got, tt.want) {
+				t.Errorf("Map() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMap_TooManyAttempts(t *testing.T) {
+	err := Map(statusErr(codes.ResourceExhausted, &errdetails.RetryInfo{RetryDelay: durationpb.New(90 * time.Second)}))
+
+	var locked *entity.TooManyAttemptsError
+	if !errors.As(err, &locked) {
+		t.Fatalf("Map() = %v, want TooManyAttemptsError", err)
+	}
+	if locked.RetryAfter != 90*time.Second {
+		t.Errorf("Map() RetryAfter = %v, want %v", locked.RetryAfter, 90*time.Second)
+	}
+}
@@ -0,0 +1,36 @@
+package secondfactor
+
+import (
+	"context"
+
+	"github.com/ktigay/goph-keeper/internal/contracts/v1/auth"
+)
+
+// Client клиент.
+type Client struct {
+	conn auth.AuthServiceClient
+}
+
+// Enroll начинает подключение второго фактора. Возвращает otpauth:// URI и коды восстановления.
+func (c *Client) Enroll(ctx context.Context) (string, []string, error) {
+	resp, err := c.conn.EnrollSecondFactor(ctx, &auth.EnrollSecondFactorRequest{})
+	if err != nil {
+		return "", nil, err
+	}
+	return resp.GetUri(), resp.GetRecoveryCodes(), nil
+}
+
+// Confirm включает второй фактор кодом из приложения-аутентификатора.
+func (c *Client) Confirm(ctx context.Context, code string) error {
+	_, err := c.conn.ConfirmSecondFactor(ctx, &auth.ConfirmSecondFactorRequest{
+		Code: code,
+	})
+	return err
+}
+
+// New конструктор.
+func New(c auth.AuthServiceClient) *Client {
+	return &Client{
+		conn: c,
+	}
+}
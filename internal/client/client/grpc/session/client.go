@@ -0,0 +1,52 @@
+package session
+
+import (
+	"context"
+
+	"github.com/ktigay/goph-keeper/internal/contracts/v1/session"
+	"github.com/ktigay/goph-keeper/internal/contracts/v1/session/mapper"
+	"github.com/ktigay/goph-keeper/internal/entity"
+)
+
+// Client клиент.
+type Client struct {
+	conn session.SessionServiceClient
+}
+
+// List возвращает действующие сессии пользователя.
+func (c *Client) List(ctx context.Context) ([]entity.SessionInfo, error) {
+	resp, err := c.conn.ListSessions(ctx, &session.ListSessionsRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]entity.SessionInfo, 0, len(resp.GetSessions()))
+	for _, s := range resp.GetSessions() {
+		sessions = append(sessions, mapper.MapSessionToEntity(s))
+	}
+	return sessions, nil
+}
+
+// Revoke отзывает сессию.
+func (c *Client) Revoke(ctx context.Context, uuid string) error {
+	_, err := c.conn.RevokeSession(ctx, &session.RevokeSessionRequest{
+		Uuid: uuid,
+	})
+	return err
+}
+
+// RevokeAllOther отзывает все сессии, кроме текущей. Возвращает количество отозванных.
+func (c *Client) RevokeAllOther(ctx context.Context) (uint32, error) {
+	resp, err := c.conn.RevokeAllOtherSessions(ctx, &session.RevokeAllOtherSessionsRequest{})
+	if err != nil {
+		return 0, err
+	}
+	return resp.GetRevoked(), nil
+}
+
+// New конструктор.
+func New(c session.SessionServiceClient) *Client {
+	return &Client{
+		conn: c,
+	}
+}
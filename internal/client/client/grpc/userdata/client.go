@@ -2,11 +2,14 @@ package userdata
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	ce "github.com/ktigay/goph-keeper/internal/client/entity"
 	"github.com/ktigay/goph-keeper/internal/contracts/v1/data"
 	"github.com/ktigay/goph-keeper/internal/contracts/v1/data/mapper"
 	"github.com/ktigay/goph-keeper/internal/entity"
@@ -55,14 +58,16 @@ func (c *Client) Read(ctx context.Context, uuid ...string) ([]entity.UserData, e
 	return d, nil
 }
 
-// Update обновляет запись пользовательских данных.
+// Update обновляет запись пользовательских данных версии d.Version.
+// Если запись на сервере уже изменена, возвращает [*ce.VersionConflictError].
 func (c *Client) Update(ctx context.Context, d entity.UserData) (*entity.UserData, error) {
 	req := data.UpdateUserDataItemRequest{
-		Item: mapper.MapEntityToItem(d),
+		Item:            mapper.MapEntityToItem(d),
+		ExpectedVersion: d.Version,
 	}
 	resp, err := c.conn.UpdateUserDataItem(ctx, &req)
 	if err != nil {
-		return nil, err
+		return nil, mapConflict(err)
 	}
 	if resp == nil || resp.Item == nil {
 		return nil, fmt.Errorf("response is nil")
@@ -78,6 +83,123 @@ func (c *Client) Delete(ctx context.Context, uuid ...string) error {
 	return err
 }
 
+// SyncBatch отправляет пакет изменений, сервер применяет его одной транзакцией.
+// Version записей пакета - версия, на основе которой сделано изменение, 0 - новая запись.
+func (c *Client) SyncBatch(ctx context.Context, batch entity.UserDataBatch) (*entity.UserDataBatchResult, error) {
+	req := data.SyncBatchRequest{
+		BatchId:     batch.UUID,
+		Upserts:     make([]*data.SyncBatchUpsert, 0, len(batch.Upserts)),
+		DeleteUuids: batch.Deletes,
+	}
+	for _, d := range batch.Upserts {
+		req.Upserts = append(req.Upserts, &data.SyncBatchUpsert{
+			Item:            mapper.MapEntityToItem(d),
+			ExpectedVersion: d.Version,
+		})
+	}
+	resp, err := c.conn.SyncBatch(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &entity.UserDataBatchResult{
+		Revision: resp.GetRevision(),
+		Items:    make([]entity.UserDataBatchItem, len(resp.Results)),
+		Replayed: resp.GetReplayed(),
+	}
+	for i := range resp.Results {
+		res.Items[i] = mapper.MapBatchItemToEntity(resp.Results[i], "")
+	}
+	return res, nil
+}
+
+// Revisions возвращает предыдущие версии записи, от новых к старым.
+func (c *Client) Revisions(ctx context.Context, itemUUID string) ([]entity.UserDataRevision, error) {
+	resp, err := c.conn.ListItemRevisions(ctx, &data.ListItemRevisionsRequest{ItemUuid: itemUUID})
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := make([]entity.UserDataRevision, len(resp.Revisions))
+	for i := range resp.Revisions {
+		revisions[i] = mapper.MapRevisionToEntity(resp.Revisions[i], "")
+	}
+	return revisions, nil
+}
+
+// Restore восстанавливает версию записи.
+func (c *Client) Restore(ctx context.Context, itemUUID, revisionUUID string) (*entity.UserData, error) {
+	resp, err := c.conn.RestoreItemRevision(ctx, &data.RestoreItemRevisionRequest{
+		ItemUuid:     itemUUID,
+		RevisionUuid: revisionUUID,
+	})
+	if err != nil {
+		return nil, mapConflict(err)
+	}
+	if resp == nil || resp.Item == nil {
+		return nil, fmt.Errorf("response is nil")
+	}
+	d := mapper.MapItemToEntity(resp.GetItem(), "")
+	return &d, nil
+}
+
+// Changes возвращает изменения записей после ревизии since; since = 0 - все записи.
+func (c *Client) Changes(ctx context.Context, since uint64) (*entity.UserDataChanges, error) {
+	resp, err := c.conn.ListChanges(ctx, &data.ListChangesRequest{SinceRevision: since})
+	if err != nil {
+		return nil, err
+	}
+	return mapChanges(resp), nil
+}
+
+// Watch подписывается на изменения записей после ревизии since и передает их apply по мере поступления.
+// Возвращает ошибку потока или apply; при закрытии потока сервером - nil.
+func (c *Client) Watch(ctx context.Context, since uint64, apply func(*entity.UserDataChanges) error) error {
+	stream, err := c.conn.WatchChanges(ctx, &data.WatchChangesRequest{SinceRevision: since})
+	if err != nil {
+		return err
+	}
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err = apply(mapChanges(resp)); err != nil {
+			return err
+		}
+	}
+}
+
+func mapChanges(resp *data.ListChangesResponse) *entity.UserDataChanges {
+	changes := &entity.UserDataChanges{
+		Revision: resp.GetRevision(),
+		Upserts:  make([]entity.UserData, len(resp.Upserts)),
+		Deleted:  resp.GetDeletedUuids(),
+		Full:     resp.GetFull(),
+	}
+	for i := range resp.Upserts {
+		changes.Upserts[i] = mapper.MapItemToEntity(resp.Upserts[i], "")
+	}
+	return changes
+}
+
+// mapConflict преобразует ABORTED с деталями VersionConflict в [*ce.VersionConflictError].
+func mapConflict(err error) error {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.Aborted {
+		return err
+	}
+	for _, d := range st.Details() {
+		if conflict, ok := d.(*data.VersionConflict); ok && conflict.GetCurrent() != nil {
+			return &ce.VersionConflictError{Current: mapper.MapItemToEntity(conflict.GetCurrent(), "")}
+		}
+	}
+	return err
+}
+
 // New конструктор.
 func New(conn data.UserDataServiceClient) *Client {
 	return &Client{
@@ -0,0 +1,95 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ktigay/goph-keeper/internal/contracts/v1/vault"
+	"github.com/ktigay/goph-keeper/internal/contracts/v1/vault/mapper"
+	"github.com/ktigay/goph-keeper/internal/entity"
+)
+
+// Client клиент.
+type Client struct {
+	conn vault.VaultServiceClient
+}
+
+// Get возвращает ключ хранилища. Если ключ не создан, возвращает nil.
+func (c *Client) Get(ctx context.Context) (*entity.VaultKey, error) {
+	resp, err := c.conn.GetVaultKey(ctx, &vault.GetVaultKeyRequest{})
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil || resp.Key == nil {
+		return nil, fmt.Errorf("response is nil")
+	}
+	k := mapper.MapKeyToEntity(resp.GetKey())
+	if resp.GetRotation() != nil {
+		r := mapper.MapKeyToEntity(resp.GetRotation())
+		k.Rotation = &r
+	}
+	return &k, nil
+}
+
+// Create сохраняет ключ хранилища на сервере.
+func (c *Client) Create(ctx context.Context, key entity.VaultKey) (*entity.VaultKey, error) {
+	resp, err := c.conn.CreateVaultKey(ctx, &vault.CreateVaultKeyRequest{
+		Key: mapper.MapEntityToKey(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil || resp.Key == nil {
+		return nil, fmt.Errorf("response is nil")
+	}
+	k := mapper.MapKeyToEntity(resp.GetKey())
+	return &k, nil
+}
+
+// BeginRotation сохраняет на сервере ключ новой версии как незавершённую ротацию.
+func (c *Client) BeginRotation(ctx context.Context, key entity.VaultKey) (*entity.VaultKey, error) {
+	resp, err := c.conn.BeginRotation(ctx, &vault.BeginRotationRequest{
+		Key: mapper.MapEntityToKey(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil || resp.Key == nil {
+		return nil, fmt.Errorf("response is nil")
+	}
+	k := mapper.MapKeyToEntity(resp.GetKey())
+	return &k, nil
+}
+
+// CommitRotation отправляет перешифрованные записи и завершает ротацию.
+func (c *Client) CommitRotation(ctx context.Context, keyVersion uint32, items []entity.UserData) (*entity.VaultKey, error) {
+	req := vault.CommitRotationRequest{
+		KeyVersion: keyVersion,
+		Items:      make([]*vault.RotatedItem, len(items)),
+	}
+	for i := range items {
+		req.Items[i] = mapper.MapEntityToRotatedItem(items[i])
+	}
+	resp, err := c.conn.CommitRotation(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil || resp.Key == nil {
+		return nil, fmt.Errorf("response is nil")
+	}
+	k := mapper.MapKeyToEntity(resp.GetKey())
+	return &k, nil
+}
+
+// New конструктор.
+func New(conn vault.VaultServiceClient) *Client {
+	return &Client{
+		conn: conn,
+	}
+}
@@ -10,6 +10,7 @@ const (
 	defaultLogFile           = "./client.log"
 	defaultSrvSyncToInterval = 2000
 	defaultSrvSyncTimeout    = 300
+	defaultConflictStrategy  = "manual"
 )
 
 // Config конфигурация.
@@ -19,7 +20,19 @@ type Config struct {
 	LogFile           string `env:"CONFIG" json:"log_file" arg:"-c" help:"log file path"`
 	SrvSyncToInterval int64  `env:"SRV_SYNC_INTERVAL" json:"srv_sync_to_interval" arg:"-i" help:"server sync interval"`
 	SrvRequestTimeout int64  `env:"SRV_REQUEST_TIMEOUT" json:"srv_request_timeout" arg:"-t" help:"server request timeout"`
+	ConflictStrategy  string `env:"CONFLICT_STRATEGY" json:"conflict_strategy" arg:"--conflict-strategy" help:"sync conflict strategy: last-writer-wins, server-wins, client-wins, keep-both, manual"`
 	Version           bool   `arg:"-v" help:"show version"`
+	TLS
+}
+
+// TLS параметры TLS соединения с сервером.
+type TLS struct {
+	Plaintext     bool   `env:"GRPC_PLAINTEXT" json:"plaintext" arg:"--plaintext" help:"connect without TLS, for local development only"`
+	TLSCAFile     string `env:"TLS_CA_FILE" json:"tls_ca_file" arg:"--tls-ca" help:"server CA PEM file, system roots if empty"`
+	TLSCertFile   string `env:"TLS_CERT_FILE" json:"tls_cert_file" arg:"--tls-cert" help:"client certificate PEM file for mTLS"`
+	TLSKeyFile    string `env:"TLS_KEY_FILE" json:"tls_key_file" arg:"--tls-key" help:"client private key PEM file for mTLS"`
+	TLSServerName string `env:"TLS_SERVER_NAME" json:"tls_server_name" arg:"--tls-server-name" help:"server name to verify the certificate against"`
+	TLSPinSHA256  string `env:"TLS_PIN_SHA256" json:"tls_pin_sha256" arg:"--tls-pin" help:"pinned SHA-256 fingerprint of the server certificate"`
 }
 
 // New конструктор.
@@ -52,6 +65,7 @@ func (d *DefaultHandler) Handle(c *Config) (*Config, error) {
 	c.LogFile = defaultLogFile
 	c.SrvSyncToInterval = defaultSrvSyncToInterval
 	c.SrvRequestTimeout = defaultSrvSyncTimeout
+	c.ConflictStrategy = defaultConflictStrategy
 
 	return d.next.Handle(c)
 }
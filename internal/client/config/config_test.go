@@ -38,6 +38,7 @@ func TestNew(t *testing.T) {
 				LogLevel:          "error",
 				SrvSyncToInterval: 4000,
 				SrvRequestTimeout: 500,
+				ConflictStrategy:  defaultConflictStrategy,
 			},
 			wantErr: false,
 		},
@@ -58,6 +59,7 @@ func TestNew(t *testing.T) {
 				LogLevel:          "error",
 				SrvSyncToInterval: 5000,
 				SrvRequestTimeout: 500,
+				ConflictStrategy:  defaultConflictStrategy,
 			},
 			wantErr: false,
 		},
@@ -70,6 +72,9 @@ func TestNew(t *testing.T) {
 					"-l=fatal",
 					"-i=4000",
 					"-t=550",
+					"--tls-ca=ca.crt",
+					"--tls-pin=ab:cd",
+					"--conflict-strategy=keep-both",
 				},
 			},
 			want: &Config{
@@ -78,6 +83,11 @@ func TestNew(t *testing.T) {
 				LogLevel:          "fatal",
 				SrvSyncToInterval: 4000,
 				SrvRequestTimeout: 550,
+				ConflictStrategy:  "keep-both",
+				TLS: TLS{
+					TLSCAFile:    "ca.crt",
+					TLSPinSHA256: "ab:cd",
+				},
 			},
 			wantErr: false,
 		},
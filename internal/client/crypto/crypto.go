@@ -0,0 +1,176 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+
+	"golang.org/x/crypto/argon2"
+
+	"github.com/ktigay/goph-keeper/internal/entity"
+)
+
+const (
+	// KeySize размер ключа AES-256.
+	KeySize = 32
+	// SaltSize размер соли для Argon2id.
+	SaltSize = 16
+
+	formatVersion byte = 1
+	nonceSize          = 12
+	// headerSize формат: версия формата (1) | версия ключа (4) | nonce (12).
+	headerSize = 1 + 4 + nonceSize
+)
+
+var (
+	// DefaultKDFParams параметры Argon2id по умолчанию (рекомендации RFC 9106).
+	DefaultKDFParams = entity.KDFParams{
+		Time:    3,
+		Memory:  64 * 1024,
+		Threads: 4,
+	}
+
+	// ErrMalformed некорректный формат шифротекста.
+	ErrMalformed = errors.New("malformed ciphertext")
+	// ErrKeyVersion шифротекст зашифрован другой версией ключа.
+	ErrKeyVersion = errors.New("ciphertext key version mismatch")
+	// ErrDecrypt ошибка расшифровки или проверки целостности.
+	ErrDecrypt = errors.New("decryption failed")
+
+	wrapAD    = []byte("goph-keeper/vault-key")
+	tagWrapAD = []byte("goph-keeper/tag-key")
+)
+
+// Key симметричный ключ хранилища.
+type Key struct {
+	Version uint32
+	aead    cipher.AEAD
+	secret  []byte
+}
+
+// Seal шифрует данные, ad - дополнительные аутентифицируемые данные.
+// Результат содержит заголовок с версией ключа.
+func (k *Key) Seal(plain, ad []byte) ([]byte, error) {
+	header := make([]byte, headerSize, headerSize+len(plain)+k.aead.Overhead())
+	header[0] = formatVersion
+	binary.BigEndian.PutUint32(header[1:5], k.Version)
+	if _, err := rand.Read(header[5:headerSize]); err != nil {
+		return nil, err
+	}
+
+	return k.aead.Seal(header, header[5:headerSize], plain, associated(header, ad)), nil
+}
+
+// Open расшифровывает данные, зашифрованные [Key.Seal].
+func (k *Key) Open(ciphertext, ad []byte) ([]byte, error) {
+	version, err := KeyVersion(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	if version != k.Version {
+		return nil, ErrKeyVersion
+	}
+
+	header := ciphertext[:headerSize]
+	plain, err := k.aead.Open(nil, header[5:headerSize], ciphertext[headerSize:], associated(header, ad))
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plain, nil
+}
+
+// MAC возвращает HMAC-SHA256 сообщения на секрете ключа.
+func (k *Key) MAC(msg []byte) []byte {
+	m := hmac.New(sha256.New, k.secret)
+	m.Write(msg)
+	return m.Sum(nil)
+}
+
+// NewKey создаёт ключ из секрета.
+func NewKey(version uint32, secret []byte) (*Key, error) {
+	if len(secret) != KeySize {
+		return nil, errors.New("invalid key size")
+	}
+	block, err := aes.NewCipher(secret)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Key{
+		Version: version,
+		aead:    aead,
+		secret:  secret,
+	}, nil
+}
+
+// GenerateKey генерирует случайный ключ хранилища.
+func GenerateKey(version uint32) (*Key, error) {
+	secret, err := Random(KeySize)
+	if err != nil {
+		return nil, err
+	}
+	return NewKey(version, secret)
+}
+
+// DeriveKEK выводит ключ шифрования ключа из мастер-пароля (Argon2id).
+func DeriveKEK(masterPassword string, salt []byte, p entity.KDFParams) (*Key, error) {
+	secret := argon2.IDKey([]byte(masterPassword), salt, p.Time, p.Memory, p.Threads, KeySize)
+	return NewKey(0, secret)
+}
+
+// WrapKey шифрует ключ хранилища ключом kek.
+func WrapKey(kek, key *Key) ([]byte, error) {
+	return kek.Seal(key.secret, wrapAD)
+}
+
+// UnwrapKey расшифровывает ключ хранилища версии version.
+func UnwrapKey(kek *Key, wrapped []byte, version uint32) (*Key, error) {
+	secret, err := kek.Open(wrapped, wrapAD)
+	if err != nil {
+		return nil, err
+	}
+	return NewKey(version, secret)
+}
+
+// WrapTagKey шифрует ключ тегов ключом kek.
+// Дополнительные данные отличаются от [WrapKey]: ключ хранилища нельзя подставить вместо ключа тегов.
+func WrapTagKey(kek, key *Key) ([]byte, error) {
+	return kek.Seal(key.secret, tagWrapAD)
+}
+
+// UnwrapTagKey расшифровывает ключ тегов.
+func UnwrapTagKey(kek *Key, wrapped []byte) (*Key, error) {
+	secret, err := kek.Open(wrapped, tagWrapAD)
+	if err != nil {
+		return nil, err
+	}
+	return NewKey(0, secret)
+}
+
+// KeyVersion возвращает версию ключа из заголовка шифротекста.
+func KeyVersion(ciphertext []byte) (uint32, error) {
+	if len(ciphertext) < headerSize || ciphertext[0] != formatVersion {
+		return 0, ErrMalformed
+	}
+	return binary.BigEndian.Uint32(ciphertext[1:5]), nil
+}
+
+// Random возвращает n случайных байт.
+func Random(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func associated(header, ad []byte) []byte {
+	return append(append(make([]byte, 0, 5+len(ad)), header[:5]...), ad...)
+}
@@ -0,0 +1,167 @@
+package crypto
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/ktigay/goph-keeper/internal/entity"
+)
+
+var testKDFParams = entity.KDFParams{Time: 1, Memory: 64, Threads: 1}
+
+func TestKey_SealOpen(t *testing.T) {
+	key, err := GenerateKey(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := GenerateKey(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ct, err := key.Seal([]byte("secret"), []byte("ad"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v, _ := KeyVersion(ct); v != 3 {
+		t.Errorf("KeyVersion() got = %v, want %v", v, 3)
+	}
+	if bytes.Contains(ct, []byte("secret")) {
+		t.Errorf("Seal() ciphertext contains plaintext")
+	}
+
+	tests := []struct {
+		name    string
+		key     *Key
+		ct      []byte
+		ad      []byte
+		want    []byte
+		wantErr error
+	}{
+		{
+			name: "Open_Success",
+			key:  key,
+			ct:   ct,
+			ad:   []byte("ad"),
+			want: []byte("secret"),
+		},
+		{
+			name:    "Open_Wrong_AD_Error",
+			key:     key,
+			ct:      ct,
+			ad:      []byte("other"),
+			wantErr: ErrDecrypt,
+		},
+		{
+			name:    "Open_Wrong_Key_Error",
+			key:     other,
+			ct:      ct,
+			ad:      []byte("ad"),
+			wantErr: ErrDecrypt,
+		},
+		{
+			name: "Open_Tampered_Error",
+			key:  key,
+			ct: func() []byte {
+				c := bytes.Clone(ct)
+				c[len(c)-1] ^= 1
+				return c
+			}(),
+			ad:      []byte("ad"),
+			wantErr: ErrDecrypt,
+		},
+		{
+			name:    "Open_Malformed_Error",
+			key:     key,
+			ct:      []byte("short"),
+			wantErr: ErrMalformed,
+		},
+		{
+			name: "Open_Key_Version_Mismatch_Error",
+			key: func() *Key {
+				k, _ := NewKey(4, key.secret)
+				return k
+			}(),
+			ct:      ct,
+			ad:      []byte("ad"),
+			wantErr: ErrKeyVersion,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.key.Open(tt.ct, tt.ad)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Open() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("Open() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapUnwrapKey(t *testing.T) {
+	salt, _ := Random(SaltSize)
+	kek, err := DeriveKEK("master", salt, testKDFParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := GenerateKey(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wrapped, err := WrapKey(kek, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := UnwrapKey(kek, wrapped, 1)
+	if err != nil {
+		t.Fatalf("UnwrapKey() error = %v", err)
+	}
+	if !bytes.Equal(got.secret, key.secret) || got.Version != 1 {
+		t.Errorf("UnwrapKey() got different key")
+	}
+
+	wrong, _ := DeriveKEK("wrong", salt, testKDFParams)
+	if _, err = UnwrapKey(wrong, wrapped, 1); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("UnwrapKey() with wrong password error = %v, want %v", err, ErrDecrypt)
+	}
+}
+
+func TestWrapUnwrapTagKey(t *testing.T) {
+	salt, _ := Random(SaltSize)
+	kek, err := DeriveKEK("master", salt, testKDFParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := GenerateKey(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wrapped, err := WrapTagKey(kek, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := UnwrapTagKey(kek, wrapped)
+	if err != nil {
+		t.Fatalf("UnwrapTagKey() error = %v", err)
+	}
+	if !bytes.Equal(got.MAC([]byte("deploy")), key.MAC([]byte("deploy"))) {
+		t.Errorf("UnwrapTagKey() got different key")
+	}
+	if bytes.Equal(key.MAC([]byte("deploy")), key.MAC([]byte("prod"))) {
+		t.Errorf("MAC() same for different tags")
+	}
+
+	// Ключ хранилища не расшифровывается как ключ тегов.
+	vaultKey, _ := WrapKey(kek, key)
+	if _, err = UnwrapTagKey(kek, vaultKey); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("UnwrapTagKey() of vault key error = %v, want %v", err, ErrDecrypt)
+	}
+}
@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ktigay/goph-keeper/internal/entity"
+)
+
+// Conflict конфликт локальных изменений записи с изменениями на сервере.
+type Conflict struct {
+	// Local локальная версия записи с несинхронизированными изменениями.
+	Local entity.UserData
+	// Remote текущая версия записи на сервере; nil - запись удалена на сервере.
+	Remote *entity.UserData
+	// Base последняя синхронизированная версия, на основе которой сделаны локальные изменения; nil - неизвестна.
+	Base *entity.UserData
+}
+
+// Resolution способ разрешения конфликта.
+type Resolution string
+
+const (
+	// ResolutionLocal сохранить локальную версию поверх версии сервера.
+	ResolutionLocal Resolution = "local"
+	// ResolutionRemote принять версию сервера, локальные изменения теряются.
+	ResolutionRemote Resolution = "remote"
+	// ResolutionBoth принять версию сервера, а локальную сохранить копией записи.
+	ResolutionBoth Resolution = "both"
+	// ResolutionMerge объединить изменения обеих сторон относительно базовой версии.
+	ResolutionMerge Resolution = "merge"
+)
+
+// MergeConflictError поля изменены по-разному локально и на сервере, объединение невозможно.
+type MergeConflictError struct {
+	Fields []string
+}
+
+// Error описание ошибки.
+func (e *MergeConflictError) Error() string {
+	return fmt.Sprintf("fields changed on both sides: %s", strings.Join(e.Fields, ", "))
+}
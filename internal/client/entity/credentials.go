@@ -4,4 +4,6 @@ package entity
 type Credentials struct {
 	Login    string `validate:"required"`
 	Password string `validate:"required"`
+	// MasterPassword мастер-пароль для шифрования данных, на сервер не передаётся.
+	MasterPassword string
 }
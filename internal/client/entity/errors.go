@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ktigay/goph-keeper/internal/entity"
+)
+
+var (
+	// ErrSecondFactorRequired для входа нужен код второго фактора.
+	ErrSecondFactorRequired = errors.New("second factor code required")
+	// ErrEmptyCredentials не заполнен логин или пароль.
+	ErrEmptyCredentials = errors.New("login and password are required")
+	// ErrInvalidCredentials неверный логин или пароль.
+	ErrInvalidCredentials = errors.New("invalid login or password")
+	// ErrWrongPassword неверный текущий пароль.
+	ErrWrongPassword = errors.New("wrong password")
+	// ErrLoginExists логин уже занят.
+	ErrLoginExists = errors.New("login already exists")
+	// ErrInvalidSecondFactorCode неверный код второго фактора.
+	ErrInvalidSecondFactorCode = errors.New("invalid second factor code")
+	// ErrLegacyLoginDisabled сервер не принимает пароль, а верификатора SRP у пользователя ещё нет.
+	ErrLegacyLoginDisabled = errors.New("password login is disabled on the server")
+	// ErrHandshakeExpired вход по SRP не завершен вовремя.
+	ErrHandshakeExpired = errors.New("login handshake expired")
+	// ErrServerProof сервер не подтвердил, что знает верификатор пароля.
+	ErrServerProof = errors.New("server failed to prove the password verifier")
+	// ErrServerUnavailable сервер недоступен.
+	ErrServerUnavailable = errors.New("server is unavailable")
+)
+
+// TooManyAttemptsError сервер временно заблокировал вход после неудачных попыток.
+type TooManyAttemptsError struct {
+	RetryAfter time.Duration
+}
+
+// Error описание ошибки.
+func (e *TooManyAttemptsError) Error() string {
+	if e.RetryAfter <= 0 {
+		return "too many attempts, try again later"
+	}
+	return fmt.Sprintf("too many attempts, try again in %s", e.RetryAfter.Round(time.Second))
+}
+
+// VersionConflictError запись изменена на сервере с другого устройства после последней синхронизации.
+type VersionConflictError struct {
+	// Current текущая версия записи на сервере.
+	Current entity.UserData
+}
+
+// Error описание ошибки.
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("item %s has been modified on another device (server version %d)", e.Current.UUID, e.Current.Version)
+}
@@ -0,0 +1,25 @@
+package entity
+
+// SRPVerifier соль и верификатор SRP, которые сервер хранит вместо пароля.
+type SRPVerifier struct {
+	Salt     []byte
+	Verifier []byte
+}
+
+// SRPChallenge ответ сервера на начало входа по SRP.
+type SRPChallenge struct {
+	HandshakeID  string
+	Salt         []byte
+	ServerPublic []byte
+	// Legacy у пользователя ещё нет верификатора, вход выполняется по паролю.
+	Legacy bool
+}
+
+// SRPLogin результат входа по SRP.
+type SRPLogin struct {
+	// ServerProof доказательство сервера, проверяется до сохранения токенов.
+	ServerProof []byte
+	Tokens      *Tokens
+	// Challenge токен подтверждения, если для аккаунта включен второй фактор.
+	Challenge string
+}
@@ -0,0 +1,9 @@
+package entity
+
+// Tokens токены сессии.
+type Tokens struct {
+	// Access короткоживущий JWT для запросов к серверу.
+	Access string
+	// Refresh одноразовый токен для получения новой пары токенов.
+	Refresh string
+}
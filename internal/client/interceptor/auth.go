@@ -4,21 +4,80 @@ import (
 	"context"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 // AuthService сервис авторизации.
+//
+//go:generate mockgen -destination=./mocks/mock_auth.go -package=mocks github.com/ktigay/goph-keeper/internal/client/interceptor AuthService
 type AuthService interface {
 	GetJWT(ctx context.Context) (string, error)
+	Refresh(ctx context.Context, stale string) error
 }
 
 // AuthInterceptor интерцептор аутентификации.
+// При ответе Unauthenticated обновляет токен и один раз повторяет запрос.
 func AuthInterceptor(s AuthService) grpc.UnaryClientInterceptor {
 	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
 		jwt, _ := s.GetJWT(ctx)
-		if jwt != "" {
-			ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+jwt)
+		err := invoker(withJWT(ctx, jwt), method, req, reply, cc, opts...)
+		if jwt == "" || status.Code(err) != codes.Unauthenticated {
+			return err
 		}
-		return invoker(ctx, method, req, reply, cc, opts...)
+
+		if refreshErr := s.Refresh(ctx, jwt); refreshErr != nil {
+			return err
+		}
+
+		jwt, _ = s.GetJWT(ctx)
+		return invoker(withJWT(ctx, jwt), method, req, reply, cc, opts...)
+	}
+}
+
+// AuthStreamInterceptor интерцептор аутентификации потоков.
+// Поток нельзя повторить прозрачно: при ответе Unauthenticated токен обновляется,
+// а переподключение с новым токеном остается вызывающему.
+func AuthStreamInterceptor(s AuthService) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		jwt, _ := s.GetJWT(ctx)
+		cs, err := streamer(withJWT(ctx, jwt), desc, cc, method, opts...)
+		if err != nil {
+			refreshOnUnauthenticated(ctx, s, jwt, err)
+			return nil, err
+		}
+		return &authStream{ClientStream: cs, ctx: ctx, srv: s, jwt: jwt}, nil
+	}
+}
+
+// authStream поток, обновляющий токен при ответе Unauthenticated.
+type authStream struct {
+	grpc.ClientStream
+	ctx context.Context
+	srv AuthService
+	jwt string
+}
+
+// RecvMsg получает сообщение потока.
+func (a *authStream) RecvMsg(m any) error {
+	err := a.ClientStream.RecvMsg(m)
+	if err != nil {
+		refreshOnUnauthenticated(a.ctx, a.srv, a.jwt, err)
+	}
+	return err
+}
+
+func refreshOnUnauthenticated(ctx context.Context, s AuthService, jwt string, err error) {
+	if jwt == "" || status.Code(err) != codes.Unauthenticated {
+		return
+	}
+	_ = s.Refresh(ctx, jwt)
+}
+
+func withJWT(ctx context.Context, jwt string) context.Context {
+	if jwt == "" {
+		return ctx
 	}
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+jwt)
 }
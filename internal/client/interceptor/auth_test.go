@@ -0,0 +1,188 @@
+package interceptor
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/ktigay/goph-keeper/internal/client/interceptor/mocks"
+)
+
+func TestAuthInterceptor(t *testing.T) {
+	unauthenticated := status.Error(codes.Unauthenticated, "token is expired")
+
+	tests := []struct {
+		name      string
+		srv       func(ctrl *gomock.Controller) AuthService
+		responses []error
+		wantAuth  []string
+		wantCode  codes.Code
+	}{
+		{
+			name: "Success_Without_Refresh",
+			srv: func(ctrl *gomock.Controller) AuthService {
+				s := mocks.NewMockAuthService(ctrl)
+				s.EXPECT().GetJWT(gomock.Any()).Times(1).Return("jwt-token", nil)
+				s.EXPECT().Refresh(gomock.Any(), gomock.Any()).Times(0)
+				return s
+			},
+			responses: []error{nil},
+			wantAuth:  []string{"Bearer jwt-token"},
+			wantCode:  codes.OK,
+		},
+		{
+			name: "Unauthenticated_Refresh_And_Retry",
+			srv: func(ctrl *gomock.Controller) AuthService {
+				s := mocks.NewMockAuthService(ctrl)
+				gomock.InOrder(
+					s.EXPECT().GetJWT(gomock.Any()).Times(1).Return("stale-token", nil),
+					s.EXPECT().Refresh(gomock.Any(), "stale-token").Times(1).Return(nil),
+					s.EXPECT().GetJWT(gomock.Any()).Times(1).Return("jwt-token", nil),
+				)
+				return s
+			},
+			responses: []error{unauthenticated, nil},
+			wantAuth:  []string{"Bearer stale-token", "Bearer jwt-token"},
+			wantCode:  codes.OK,
+		},
+		{
+			name: "Unauthenticated_Retry_Only_Once",
+			srv: func(ctrl *gomock.Controller) AuthService {
+				s := mocks.NewMockAuthService(ctrl)
+				s.EXPECT().GetJWT(gomock.Any()).Times(2).Return("jwt-token", nil)
+				s.EXPECT().Refresh(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+				return s
+			},
+			responses: []error{unauthenticated, unauthenticated},
+			wantAuth:  []string{"Bearer jwt-token", "Bearer jwt-token"},
+			wantCode:  codes.Unauthenticated,
+		},
+		{
+			name: "Refresh_Failed_Returns_Original_Error",
+			srv: func(ctrl *gomock.Controller) AuthService {
+				s := mocks.NewMockAuthService(ctrl)
+				s.EXPECT().GetJWT(gomock.Any()).Times(1).Return("stale-token", nil)
+				s.EXPECT().Refresh(gomock.Any(), gomock.Any()).Times(1).Return(fmt.Errorf("session revoked"))
+				return s
+			},
+			responses: []error{unauthenticated},
+			wantAuth:  []string{"Bearer stale-token"},
+			wantCode:  codes.Unauthenticated,
+		},
+		{
+			name: "Not_Authorized_Without_Refresh",
+			srv: func(ctrl *gomock.Controller) AuthService {
+				s := mocks.NewMockAuthService(ctrl)
+				s.EXPECT().GetJWT(gomock.Any()).Times(1).Return("", nil)
+				s.EXPECT().Refresh(gomock.Any(), gomock.Any()).Times(0)
+				return s
+			},
+			responses: []error{unauthenticated},
+			wantAuth:  []string{""},
+			wantCode:  codes.Unauthenticated,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			var gotAuth []string
+			invoker := func(ctx context.Context, _ string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+				md, _ := metadata.FromOutgoingContext(ctx)
+				gotAuth = append(gotAuth, append(md.Get("authorization"), "")[0])
+				return tt.responses[len(gotAuth)-1]
+			}
+
+			err := AuthInterceptor(tt.srv(ctrl))(context.Background(), "method", nil, nil, nil, invoker)
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("AuthInterceptor() code = %v, want %v", status.Code(err), tt.wantCode)
+			}
+			if !reflect.DeepEqual(gotAuth, tt.wantAuth) {
+				t.Errorf("AuthInterceptor() authorization = %v, want %v", gotAuth, tt.wantAuth)
+			}
+		})
+	}
+}
+
+// recvStream поток, возвращающий заданную ошибку при получении сообщения.
+type recvStream struct {
+	grpc.ClientStream
+	err error
+}
+
+func (r *recvStream) RecvMsg(any) error {
+	return r.err
+}
+
+func TestAuthStreamInterceptor(t *testing.T) {
+	unauthenticated := status.Error(codes.Unauthenticated, "token is expired")
+
+	tests := []struct {
+		name     string
+		srv      func(ctrl *gomock.Controller) AuthService
+		recvErr  error
+		wantCode codes.Code
+	}{
+		{
+			name: "Recv_Success_Without_Refresh",
+			srv: func(ctrl *gomock.Controller) AuthService {
+				s := mocks.NewMockAuthService(ctrl)
+				s.EXPECT().GetJWT(gomock.Any()).Times(1).Return("jwt-token", nil)
+				s.EXPECT().Refresh(gomock.Any(), gomock.Any()).Times(0)
+				return s
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name: "Recv_Unauthenticated_Refresh",
+			srv: func(ctrl *gomock.Controller) AuthService {
+				s := mocks.NewMockAuthService(ctrl)
+				s.EXPECT().GetJWT(gomock.Any()).Times(1).Return("stale-token", nil)
+				s.EXPECT().Refresh(gomock.Any(), "stale-token").Times(1).Return(nil)
+				return s
+			},
+			recvErr:  unauthenticated,
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name: "Recv_Unavailable_Without_Refresh",
+			srv: func(ctrl *gomock.Controller) AuthService {
+				s := mocks.NewMockAuthService(ctrl)
+				s.EXPECT().GetJWT(gomock.Any()).Times(1).Return("jwt-token", nil)
+				s.EXPECT().Refresh(gomock.Any(), gomock.Any()).Times(0)
+				return s
+			},
+			recvErr:  status.Error(codes.Unavailable, "server is shutting down"),
+			wantCode: codes.Unavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			var gotAuth []string
+			streamer := func(ctx context.Context, _ *grpc.StreamDesc, _ *grpc.ClientConn, _ string, _ ...grpc.CallOption) (grpc.ClientStream, error) {
+				md, _ := metadata.FromOutgoingContext(ctx)
+				gotAuth = md.Get("authorization")
+				return &recvStream{err: tt.recvErr}, nil
+			}
+
+			cs, err := AuthStreamInterceptor(tt.srv(ctrl))(context.Background(), &grpc.StreamDesc{}, nil, "/test.Service/Watch", streamer)
+			if err != nil {
+				t.Fatalf("AuthStreamInterceptor() error = %v", err)
+			}
+			if err = cs.RecvMsg(nil); status.Code(err) != tt.wantCode {
+				t.Errorf("RecvMsg() code = %v, want %v", status.Code(err), tt.wantCode)
+			}
+			if len(gotAuth) != 1 {
+				t.Errorf("AuthStreamInterceptor() authorization = %v", gotAuth)
+			}
+		})
+	}
+}
@@ -1,21 +1,41 @@
 package auth
 
-import "context"
+import (
+	"context"
+	"sync"
+
+	"github.com/ktigay/goph-keeper/internal/client/entity"
+)
 
 // Repository репозиторий.
 type Repository struct {
-	jwt string
+	mu     sync.RWMutex
+	tokens entity.Tokens
 }
 
-// SetJWT сохраняет JWT токен в репозиторий.
-func (r *Repository) SetJWT(_ context.Context, jwt string) error {
-	r.jwt = jwt
+// SetTokens сохраняет токены сессии в репозиторий.
+func (r *Repository) SetTokens(_ context.Context, tokens entity.Tokens) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.tokens = tokens
 	return nil
 }
 
 // GetJWT возвращает JWT токен.
 func (r *Repository) GetJWT(_ context.Context) (string, error) {
-	return r.jwt, nil
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.tokens.Access, nil
+}
+
+// GetRefreshToken возвращает refresh токен.
+func (r *Repository) GetRefreshToken(_ context.Context) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.tokens.Refresh, nil
 }
 
 // New конструктор.
@@ -9,6 +9,7 @@ import (
 
 	"github.com/google/uuid"
 
+	ce "github.com/ktigay/goph-keeper/internal/client/entity"
 	"github.com/ktigay/goph-keeper/internal/entity"
 )
 
@@ -17,6 +18,14 @@ type Repository struct {
 	m            sync.Mutex
 	data         map[string]entity.UserData
 	initRequired bool
+	// deleted записи, удаленные локально, удаление которых еще не отправлено на сервер.
+	deleted map[string]struct{}
+	// revision ревизия данных сервера, до которой синхронизированы локальные данные.
+	revision uint64
+	// base последние синхронизированные версии записей с локальными изменениями.
+	base map[string]entity.UserData
+	// conflicts неразрешенные конфликты локальных изменений с изменениями на сервере.
+	conflicts map[string]ce.Conflict
 }
 
 // Sync синхронизирует данные.
@@ -27,6 +36,9 @@ func (r *Repository) Sync(_ context.Context, data []entity.UserData) error {
 
 	r.data = make(map[string]entity.UserData, len(data))
 	for _, d := range data {
+		if _, ok := r.deleted[d.UUID]; ok {
+			continue
+		}
 		d.IsSynced = true
 		r.data[d.UUID] = d
 	}
@@ -53,17 +65,25 @@ func (r *Repository) Update(_ context.Context, data entity.UserData) (*entity.Us
 	r.m.Lock()
 	defer r.m.Unlock()
 
+	if old, ok := r.data[data.UUID]; ok && old.IsSynced {
+		r.base[data.UUID] = old
+	}
 	data.UpdatedAt = time.Now()
 	r.data[data.UUID] = data
 	return &data, nil
 }
 
-// Replace заменяет данные.
+// Replace заменяет данные. Запись, удаленная локально, не восстанавливается.
 func (r *Repository) Replace(_ context.Context, data entity.UserData) (*entity.UserData, error) {
 	r.m.Lock()
 	defer r.m.Unlock()
 
-	r.data[data.UUID] = data
+	if _, ok := r.deleted[data.UUID]; !ok {
+		r.data[data.UUID] = data
+	}
+	if data.IsSynced {
+		delete(r.base, data.UUID)
+	}
 	return &data, nil
 }
 
@@ -74,6 +94,41 @@ func (r *Repository) Delete(_ context.Context, uuids ...string) error {
 
 	for _, uid := range uuids {
 		delete(r.data, uid)
+		delete(r.base, uid)
+		delete(r.conflicts, uid)
+	}
+	return nil
+}
+
+// MarkDeleted удаляет данные и запоминает их для удаления на сервере.
+func (r *Repository) MarkDeleted(_ context.Context, uuids ...string) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	for _, uid := range uuids {
+		delete(r.data, uid)
+		delete(r.base, uid)
+		delete(r.conflicts, uid)
+		r.deleted[uid] = struct{}{}
+	}
+	return nil
+}
+
+// ReadDeleted возвращает UUID записей, удаление которых еще не отправлено на сервер.
+func (r *Repository) ReadDeleted(_ context.Context) ([]string, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	return slices.Sorted(maps.Keys(r.deleted)), nil
+}
+
+// PurgeDeleted забывает записи, удаление которых отправлено на сервер.
+func (r *Repository) PurgeDeleted(_ context.Context, uuids ...string) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	for _, uid := range uuids {
+		delete(r.deleted, uid)
 	}
 	return nil
 }
@@ -96,20 +151,92 @@ func (r *Repository) Read(_ context.Context, uuids ...string) ([]entity.UserData
 	return sortByUpdated(data)
 }
 
-// ReadUnsynced читает не синхронизированные данные.
+// ReadUnsynced читает не синхронизированные данные, кроме записей с неразрешенными конфликтами.
 func (r *Repository) ReadUnsynced(_ context.Context) ([]entity.UserData, error) {
 	r.m.Lock()
 	defer r.m.Unlock()
 
 	var data []entity.UserData
 	for _, d := range r.data {
-		if !d.IsSynced {
+		if _, ok := r.conflicts[d.UUID]; !ok && !d.IsSynced {
 			data = append(data, d)
 		}
 	}
 	return sortByUpdated(data)
 }
 
+// Base возвращает последнюю синхронизированную версию записи с локальными изменениями или nil, если её нет.
+func (r *Repository) Base(_ context.Context, uuid string) (*entity.UserData, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if d, ok := r.base[uuid]; ok {
+		return &d, nil
+	}
+	return nil, nil
+}
+
+// SetBase запоминает версию сервера, на основе которой сделаны локальные изменения записи.
+func (r *Repository) SetBase(_ context.Context, data entity.UserData) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.base[data.UUID] = data
+	return nil
+}
+
+// SaveConflict сохраняет неразрешенный конфликт записи, заменяя предыдущий.
+func (r *Repository) SaveConflict(_ context.Context, c ce.Conflict) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.conflicts[c.Local.UUID] = c
+	return nil
+}
+
+// Conflicts возвращает неразрешенные конфликты; uuids - только конфликты этих записей.
+func (r *Repository) Conflicts(_ context.Context, uuids ...string) ([]ce.Conflict, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	conflicts := make([]ce.Conflict, 0, len(r.conflicts))
+	for uid, c := range r.conflicts {
+		if len(uuids) == 0 || slices.Contains(uuids, uid) {
+			conflicts = append(conflicts, c)
+		}
+	}
+	slices.SortFunc(conflicts, func(a, b ce.Conflict) int {
+		return b.Local.UpdatedAt.Compare(a.Local.UpdatedAt)
+	})
+	return conflicts, nil
+}
+
+// DeleteConflict забывает разрешенный конфликт записи.
+func (r *Repository) DeleteConflict(_ context.Context, uuid string) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	delete(r.conflicts, uuid)
+	return nil
+}
+
+// Revision возвращает ревизию данных сервера, до которой синхронизированы локальные данные.
+func (r *Repository) Revision(_ context.Context) (uint64, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	return r.revision, nil
+}
+
+// SetRevision сохраняет ревизию данных сервера, до которой синхронизированы локальные данные.
+func (r *Repository) SetRevision(_ context.Context, revision uint64) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.revision = revision
+	return nil
+}
+
 func sortByUpdated(data []entity.UserData) ([]entity.UserData, error) {
 	slices.SortFunc(data, func(a, b entity.UserData) int {
 		return b.UpdatedAt.Compare(a.UpdatedAt)
@@ -121,6 +248,9 @@ func sortByUpdated(data []entity.UserData) ([]entity.UserData, error) {
 func New() *Repository {
 	return &Repository{
 		data:         make(map[string]entity.UserData),
+		deleted:      make(map[string]struct{}),
+		base:         make(map[string]entity.UserData),
+		conflicts:    make(map[string]ce.Conflict),
 		initRequired: true,
 	}
 }
@@ -1,10 +1,12 @@
 package userdata
 
 import (
+	"context"
 	"reflect"
 	"testing"
 	"time"
 
+	ce "github.com/ktigay/goph-keeper/internal/client/entity"
 	"github.com/ktigay/goph-keeper/internal/entity"
 )
 
@@ -74,3 +76,80 @@ func Test_sortByUpdated(t *testing.T) {
 		})
 	}
 }
+
+func TestRepository_MarkDeleted(t *testing.T) {
+	ctx := context.Background()
+	r := New()
+	item := entity.UserData{UUID: "5d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf", Title: "title", IsSynced: true}
+	if err := r.Sync(ctx, []entity.UserData{item}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.MarkDeleted(ctx, item.UUID); err != nil {
+		t.Fatalf("MarkDeleted() error = %v", err)
+	}
+	// Ответ на синхронизацию, начатую до удаления, не восстанавливает запись.
+	if _, err := r.Replace(ctx, item); err != nil {
+		t.Fatalf("Replace() error = %v", err)
+	}
+	if got, _ := r.Read(ctx, item.UUID); len(got) != 0 {
+		t.Errorf("Read() got = %v, want deleted item to stay deleted", got)
+	}
+	if got, _ := r.ReadDeleted(ctx); !reflect.DeepEqual(got, []string{item.UUID}) {
+		t.Errorf("ReadDeleted() got = %v, want %v", got, []string{item.UUID})
+	}
+
+	if err := r.PurgeDeleted(ctx, item.UUID); err != nil {
+		t.Fatalf("PurgeDeleted() error = %v", err)
+	}
+	if got, _ := r.ReadDeleted(ctx); len(got) != 0 {
+		t.Errorf("ReadDeleted() got = %v, want none", got)
+	}
+}
+
+func TestRepository_Conflicts(t *testing.T) {
+	ctx := context.Background()
+	r := New()
+	item := entity.UserData{UUID: "5d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf", Title: "title", Version: 2, IsSynced: true}
+	if err := r.Sync(ctx, []entity.UserData{item}); err != nil {
+		t.Fatal(err)
+	}
+
+	edited := item
+	edited.Title = "edited"
+	edited.IsSynced = false
+	if _, err := r.Update(ctx, edited); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	edited.Title = "edited twice"
+	if _, err := r.Update(ctx, edited); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	// Базой остается последняя синхронизированная версия, а не промежуточная правка.
+	if got, _ := r.Base(ctx, item.UUID); got == nil || got.Title != item.Title {
+		t.Errorf("Base() got = %v, want %v", got, item)
+	}
+
+	if err := r.SaveConflict(ctx, ce.Conflict{Local: edited}); err != nil {
+		t.Fatalf("SaveConflict() error = %v", err)
+	}
+	if got, _ := r.ReadUnsynced(ctx); len(got) != 0 {
+		t.Errorf("ReadUnsynced() got = %v, want conflicted item skipped", got)
+	}
+	if got, _ := r.Conflicts(ctx, item.UUID); len(got) != 1 {
+		t.Errorf("Conflicts() got = %v, want one conflict", got)
+	}
+
+	if err := r.DeleteConflict(ctx, item.UUID); err != nil {
+		t.Fatalf("DeleteConflict() error = %v", err)
+	}
+	if _, err := r.Replace(ctx, item); err != nil {
+		t.Fatalf("Replace() error = %v", err)
+	}
+	if got, _ := r.Base(ctx, item.UUID); got != nil {
+		t.Errorf("Base() got = %v, want none for synced item", got)
+	}
+	if got, _ := r.Conflicts(ctx); len(got) != 0 {
+		t.Errorf("Conflicts() got = %v, want none", got)
+	}
+}
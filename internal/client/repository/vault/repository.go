@@ -0,0 +1,99 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/ktigay/goph-keeper/internal/client/crypto"
+)
+
+var (
+	// ErrLocked хранилище не разблокировано.
+	ErrLocked = errors.New("vault is locked")
+	// ErrKeyNotFound ключа этой версии нет: хранится только текущий и предыдущий ключ.
+	ErrKeyNotFound = errors.New("vault key version not found")
+)
+
+// Repository хранилище ключей в памяти.
+type Repository struct {
+	mu      sync.RWMutex
+	keys    map[uint32]*crypto.Key
+	current uint32
+	tagKey  *crypto.Key
+}
+
+// SetKey сохраняет ключ и делает его текущим.
+func (r *Repository) SetKey(_ context.Context, key *crypto.Key) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.keys[key.Version] = key
+	r.current = key.Version
+	return nil
+}
+
+// CurrentKey возвращает текущий ключ.
+func (r *Repository) CurrentKey(_ context.Context) (*crypto.Key, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key, ok := r.keys[r.current]
+	if !ok {
+		return nil, ErrLocked
+	}
+	return key, nil
+}
+
+// Key возвращает ключ по версии.
+func (r *Repository) Key(_ context.Context, version uint32) (*crypto.Key, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, ok := r.keys[r.current]; !ok {
+		return nil, ErrLocked
+	}
+	key, ok := r.keys[version]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return key, nil
+}
+
+// SetTagKey сохраняет ключ тегов.
+func (r *Repository) SetTagKey(_ context.Context, key *crypto.Key) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.tagKey = key
+	return nil
+}
+
+// TagKey возвращает ключ тегов или nil, если у хранилища его ещё нет.
+func (r *Repository) TagKey(_ context.Context) (*crypto.Key, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, ok := r.keys[r.current]; !ok {
+		return nil, ErrLocked
+	}
+	return r.tagKey, nil
+}
+
+// Clear удаляет ключи из памяти.
+func (r *Repository) Clear(_ context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.keys = make(map[uint32]*crypto.Key)
+	r.current = 0
+	r.tagKey = nil
+	return nil
+}
+
+// New конструктор.
+func New() *Repository {
+	return &Repository{
+		keys: make(map[uint32]*crypto.Key),
+	}
+}
@@ -9,6 +9,7 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	entity "github.com/ktigay/goph-keeper/internal/client/entity"
 )
 
 // MockRepository is a mock of Repository interface.
@@ -49,16 +50,31 @@ func (mr *MockRepositoryMockRecorder) GetJWT(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJWT", reflect.TypeOf((*MockRepository)(nil).GetJWT), arg0)
 }
 
-// SetJWT mocks base method.
-func (m *MockRepository) SetJWT(arg0 context.Context, arg1 string) error {
+// GetRefreshToken mocks base method.
+func (m *MockRepository) GetRefreshToken(arg0 context.Context) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetJWT", arg0, arg1)
+	ret := m.ctrl.Call(m, "GetRefreshToken", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRefreshToken indicates an expected call of GetRefreshToken.
+func (mr *MockRepositoryMockRecorder) GetRefreshToken(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefreshToken", reflect.TypeOf((*MockRepository)(nil).GetRefreshToken), arg0)
+}
+
+// SetTokens mocks base method.
+func (m *MockRepository) SetTokens(arg0 context.Context, arg1 entity.Tokens) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTokens", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SetJWT indicates an expected call of SetJWT.
-func (mr *MockRepositoryMockRecorder) SetJWT(arg0, arg1 interface{}) *gomock.Call {
+// SetTokens indicates an expected call of SetTokens.
+func (mr *MockRepositoryMockRecorder) SetTokens(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetJWT", reflect.TypeOf((*MockRepository)(nil).SetJWT), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTokens", reflect.TypeOf((*MockRepository)(nil).SetTokens), arg0, arg1)
 }
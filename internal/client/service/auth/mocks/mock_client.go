@@ -36,31 +36,92 @@ func (m *MockClient) EXPECT() *MockClientMockRecorder {
 }
 
 // Login mocks base method.
-func (m *MockClient) Login(arg0 context.Context, arg1 entity.Credentials) (string, error) {
+func (m *MockClient) Login(arg0 context.Context, arg1 entity.Credentials, arg2 entity.SRPVerifier) (*entity.Tokens, string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Login", arg0, arg1)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "Login", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*entity.Tokens)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Login indicates an expected call of Login.
+func (mr *MockClientMockRecorder) Login(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockClient)(nil).Login), arg0, arg1, arg2)
+}
+
+// Refresh mocks base method.
+func (m *MockClient) Refresh(arg0 context.Context, arg1 string) (*entity.Tokens, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", arg0, arg1)
+	ret0, _ := ret[0].(*entity.Tokens)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Login indicates an expected call of Login.
-func (mr *MockClientMockRecorder) Login(arg0, arg1 interface{}) *gomock.Call {
+// Refresh indicates an expected call of Refresh.
+func (mr *MockClientMockRecorder) Refresh(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockClient)(nil).Login), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockClient)(nil).Refresh), arg0, arg1)
 }
 
 // Register mocks base method.
-func (m *MockClient) Register(arg0 context.Context, arg1 entity.Credentials) (string, error) {
+func (m *MockClient) Register(arg0 context.Context, arg1 string, arg2 entity.SRPVerifier) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Register", arg0, arg1)
+	ret := m.ctrl.Call(m, "Register", arg0, arg1, arg2)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Register indicates an expected call of Register.
-func (mr *MockClientMockRecorder) Register(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockClientMockRecorder) Register(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockClient)(nil).Register), arg0, arg1, arg2)
+}
+
+// SrpLogin mocks base method.
+func (m *MockClient) SrpLogin(arg0 context.Context, arg1 string, arg2 []byte) (*entity.SRPLogin, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SrpLogin", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*entity.SRPLogin)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SrpLogin indicates an expected call of SrpLogin.
+func (mr *MockClientMockRecorder) SrpLogin(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SrpLogin", reflect.TypeOf((*MockClient)(nil).SrpLogin), arg0, arg1, arg2)
+}
+
+// SrpStart mocks base method.
+func (m *MockClient) SrpStart(arg0 context.Context, arg1 string, arg2 []byte) (*entity.SRPChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SrpStart", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*entity.SRPChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SrpStart indicates an expected call of SrpStart.
+func (mr *MockClientMockRecorder) SrpStart(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SrpStart", reflect.TypeOf((*MockClient)(nil).SrpStart), arg0, arg1, arg2)
+}
+
+// VerifySecondFactor mocks base method.
+func (m *MockClient) VerifySecondFactor(arg0 context.Context, arg1, arg2 string) (*entity.Tokens, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifySecondFactor", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*entity.Tokens)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifySecondFactor indicates an expected call of VerifySecondFactor.
+func (mr *MockClientMockRecorder) VerifySecondFactor(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockClient)(nil).Register), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifySecondFactor", reflect.TypeOf((*MockClient)(nil).VerifySecondFactor), arg0, arg1, arg2)
 }
@@ -2,57 +2,171 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"sync"
 
 	"github.com/ktigay/goph-keeper/internal/client/entity"
 	"github.com/ktigay/goph-keeper/internal/client/validator"
+	"github.com/ktigay/goph-keeper/internal/srp"
+)
+
+var (
+	// ErrNoRefreshToken нет refresh токена, нужна повторная авторизация.
+	ErrNoRefreshToken = errors.New("no refresh token")
+	// ErrNoChallenge нет незавершенного входа, ожидающего второй фактор.
+	ErrNoChallenge = errors.New("no pending second factor challenge")
 )
 
 // Client клиент.
 //
 //go:generate mockgen -destination=./mocks/mock_client.go -package=mocks github.com/ktigay/goph-keeper/internal/client/service/auth Client
 type Client interface {
-	Login(ctx context.Context, data entity.Credentials) (string, error)
-	Register(ctx context.Context, data entity.Credentials) (string, error)
+	Login(ctx context.Context, data entity.Credentials, v entity.SRPVerifier) (*entity.Tokens, string, error)
+	Register(ctx context.Context, login string, v entity.SRPVerifier) (string, error)
+	SrpStart(ctx context.Context, login string, clientPublic []byte) (*entity.SRPChallenge, error)
+	SrpLogin(ctx context.Context, handshakeID string, proof []byte) (*entity.SRPLogin, error)
+	Refresh(ctx context.Context, refreshToken string) (*entity.Tokens, error)
+	VerifySecondFactor(ctx context.Context, challenge, code string) (*entity.Tokens, error)
 }
 
 // Repository репозиторий.
 //
 //go:generate mockgen -destination=./mocks/mock_auth.go -package=mocks github.com/ktigay/goph-keeper/internal/client/service/auth Repository
 type Repository interface {
-	SetJWT(ctx context.Context, jwt string) error
+	SetTokens(ctx context.Context, tokens entity.Tokens) error
 	GetJWT(ctx context.Context) (string, error)
+	GetRefreshToken(ctx context.Context) (string, error)
 }
 
 // Service сервис.
 type Service struct {
-	client Client
-	repo   Repository
-	logger *slog.Logger
+	// refreshMu не дает параллельным запросам обновлять токен одновременно:
+	// повторное предъявление refresh токена сервер считает утечкой и отзывает сессию.
+	refreshMu sync.Mutex
+	client    Client
+	repo      Repository
+	logger    *slog.Logger
+	// challenge токен входа, ожидающего второй фактор.
+	challenge string
 }
 
-// Login авторизирует пользователя.
+// Login авторизирует пользователя по SRP, пароль на сервер не передаётся.
+// Если у пользователя ещё нет верификатора, входит по паролю и передает верификатор для следующих входов.
+// Если нужен второй фактор, возвращает [entity.ErrSecondFactorRequired], вход завершается [Service.VerifySecondFactor].
 func (s *Service) Login(ctx context.Context, data entity.Credentials) error {
 	if err := validator.ValidateCredentials(data); err != nil {
 		return err
 	}
 
-	token, err := s.client.Login(ctx, data)
+	tokens, challenge, err := s.login(ctx, data)
 	if err != nil {
 		s.logger.Debug("login failed", "error", err)
 		return err
 	}
-	s.logger.Debug("login success", "token", token)
-	return s.repo.SetJWT(ctx, token)
+	s.challenge = challenge
+	if challenge != "" {
+		s.logger.Debug("second factor required")
+		return entity.ErrSecondFactorRequired
+	}
+	s.logger.Debug("login success")
+	return s.repo.SetTokens(ctx, *tokens)
+}
+
+func (s *Service) login(ctx context.Context, data entity.Credentials) (*entity.Tokens, string, error) {
+	client, err := srp.NewClient(data.Password)
+	if err != nil {
+		return nil, "", err
+	}
+
+	ch, err := s.client.SrpStart(ctx, data.Login, client.PublicKey())
+	if err != nil {
+		return nil, "", err
+	}
+
+	proof, err := client.Proof(ch.Salt, ch.ServerPublic)
+	if err != nil {
+		return nil, "", err
+	}
+	res, err := s.client.SrpLogin(ctx, ch.HandshakeID, proof)
+	// Сервер отвечает одинаково для всех логинов, поэтому у пользователя без верификатора
+	// SRP не проходит и, если сервер это разрешает, вход повторяется паролем.
+	if errors.Is(err, entity.ErrInvalidCredentials) && ch.Legacy {
+		s.logger.Debug("srp login failed, login with password")
+		v, err := newVerifier(data.Password)
+		if err != nil {
+			return nil, "", err
+		}
+		return s.client.Login(ctx, data, v)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	// Токены не сохраняются, если сервер не доказал знание верификатора.
+	if err = client.VerifyServer(res.ServerProof); err != nil {
+		return nil, "", entity.ErrServerProof
+	}
+	return res.Tokens, res.Challenge, nil
+}
+
+// VerifySecondFactor завершает вход кодом второго фактора.
+func (s *Service) VerifySecondFactor(ctx context.Context, code string) error {
+	if s.challenge == "" {
+		return ErrNoChallenge
+	}
+
+	tokens, err := s.client.VerifySecondFactor(ctx, s.challenge, code)
+	if err != nil {
+		s.logger.Debug("second factor failed", "error", err)
+		return err
+	}
+	s.challenge = ""
+	s.logger.Debug("login success")
+	return s.repo.SetTokens(ctx, *tokens)
 }
 
-// Register регистрирует пользователя.
+// Refresh обновляет токены сессии, если access токен всё ещё stale.
+// Если токен уже обновлен другим запросом, повторно не обновляет.
+func (s *Service) Refresh(ctx context.Context, stale string) error {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	jwt, err := s.repo.GetJWT(ctx)
+	if err != nil {
+		return err
+	}
+	if jwt != stale {
+		return nil
+	}
+
+	refresh, err := s.repo.GetRefreshToken(ctx)
+	if err != nil {
+		return err
+	}
+	if refresh == "" {
+		return ErrNoRefreshToken
+	}
+
+	tokens, err := s.client.Refresh(ctx, refresh)
+	if err != nil {
+		s.logger.Debug("refresh failed", "error", err)
+		return err
+	}
+	s.logger.Debug("token refreshed")
+	return s.repo.SetTokens(ctx, *tokens)
+}
+
+// Register регистрирует пользователя, сервер получает только верификатор SRP.
 func (s *Service) Register(ctx context.Context, data entity.Credentials) error {
 	if err := validator.ValidateCredentials(data); err != nil {
 		return err
 	}
 
-	uuid, err := s.client.Register(ctx, data)
+	v, err := newVerifier(data.Password)
+	if err != nil {
+		return err
+	}
+	uuid, err := s.client.Register(ctx, data.Login, v)
 	if err != nil {
 		return err
 	}
@@ -75,6 +189,15 @@ func (s *Service) IsAuthorized(ctx context.Context) bool {
 	return jwt != ""
 }
 
+// newVerifier вычисляет верификатор SRP с новой солью.
+func newVerifier(password string) (entity.SRPVerifier, error) {
+	salt, err := srp.NewSalt()
+	if err != nil {
+		return entity.SRPVerifier{}, err
+	}
+	return entity.SRPVerifier{Salt: salt, Verifier: srp.Verifier(password, salt)}, nil
+}
+
 // New конструктор.
 func New(c Client, r Repository, l *slog.Logger) *Service {
 	return &Service{
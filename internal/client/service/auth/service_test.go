@@ -1,7 +1,10 @@
 package auth
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -9,8 +12,11 @@ import (
 	"github.com/ktigay/goph-keeper/internal/client/entity"
 	"github.com/ktigay/goph-keeper/internal/client/service/auth/mocks"
 	"github.com/ktigay/goph-keeper/internal/log"
+	"github.com/ktigay/goph-keeper/internal/srp"
 )
 
+var errTest = errors.New("test error")
+
 func TestService_GetJWT(t *testing.T) {
 	type fields struct {
 		client func(controller *gomock.Controller) Client
@@ -128,7 +134,41 @@ func TestService_IsAuthorized(t *testing.T) {
 	}
 }
 
+// expectSRP настраивает клиент так, будто сервер хранит верификатор пароля password.
+// Доказательство сервера берется из login, если оно задано.
+func expectSRP(t *testing.T, c *mocks.MockClient, password string, login *entity.SRPLogin) {
+	t.Helper()
+
+	salt, err := srp.NewSalt()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier := srp.Verifier(password, salt)
+
+	var server *srp.Server
+	c.EXPECT().SrpStart(gomock.Any(), "login", gomock.Any()).Times(1).DoAndReturn(
+		func(_ context.Context, _ string, clientPublic []byte) (*entity.SRPChallenge, error) {
+			if server, err = srp.NewServer(verifier, clientPublic); err != nil {
+				return nil, err
+			}
+			return &entity.SRPChallenge{HandshakeID: "handshake", Salt: salt, ServerPublic: server.PublicKey()}, nil
+		})
+	c.EXPECT().SrpLogin(gomock.Any(), "handshake", gomock.Any()).Times(1).DoAndReturn(
+		func(_ context.Context, _ string, proof []byte) (*entity.SRPLogin, error) {
+			if srp.VerifyProof(server.ClientProof(), proof) != nil {
+				return nil, entity.ErrInvalidCredentials
+			}
+			res := *login
+			if res.ServerProof == nil {
+				res.ServerProof = server.ServerProof()
+			}
+			return &res, nil
+		})
+}
+
 func TestService_Login(t *testing.T) {
+	tokens := &entity.Tokens{Access: "jwt-token", Refresh: "refresh-token"}
+
 	type fields struct {
 		client func(controller *gomock.Controller) Client
 		repo   func(controller *gomock.Controller) Repository
@@ -141,19 +181,103 @@ func TestService_Login(t *testing.T) {
 		name    string
 		fields  fields
 		args    args
-		wantErr bool
+		wantErr error
 	}{
 		{
 			name: "Login_Success",
 			fields: fields{
 				repo: func(ctrl *gomock.Controller) Repository {
 					repo := mocks.NewMockRepository(ctrl)
-					repo.EXPECT().SetJWT(gomock.Eq(context.Background()), gomock.Eq("jwt-token")).Times(1).Return(nil)
+					repo.EXPECT().SetTokens(gomock.Eq(context.Background()), gomock.Eq(*tokens)).Times(1).Return(nil)
 					return repo
 				},
 				client: func(controller *gomock.Controller) Client {
 					c := mocks.NewMockClient(controller)
-					c.EXPECT().Login(gomock.Any(), gomock.Any()).Times(1).Return("jwt-token", nil)
+					expectSRP(t, c, "password", &entity.SRPLogin{Tokens: tokens})
+					return c
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				data: entity.Credentials{
+					Login:    "login",
+					Password: "password",
+				},
+			},
+		},
+		{
+			name: "Login_Wrong_Password",
+			fields: fields{
+				repo: func(ctrl *gomock.Controller) Repository {
+					repo := mocks.NewMockRepository(ctrl)
+					repo.EXPECT().SetTokens(gomock.Any(), gomock.Any()).Times(0)
+					return repo
+				},
+				client: func(controller *gomock.Controller) Client {
+					c := mocks.NewMockClient(controller)
+					expectSRP(t, c, "password", &entity.SRPLogin{Tokens: tokens})
+					return c
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				data: entity.Credentials{
+					Login:    "login",
+					Password: "other",
+				},
+			},
+			wantErr: entity.ErrInvalidCredentials,
+		},
+		{
+			name: "Login_Wrong_Server_Proof",
+			fields: fields{
+				repo: func(ctrl *gomock.Controller) Repository {
+					repo := mocks.NewMockRepository(ctrl)
+					repo.EXPECT().SetTokens(gomock.Any(), gomock.Any()).Times(0)
+					return repo
+				},
+				client: func(controller *gomock.Controller) Client {
+					c := mocks.NewMockClient(controller)
+					expectSRP(t, c, "password", &entity.SRPLogin{Tokens: tokens, ServerProof: []byte("forged")})
+					return c
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				data: entity.Credentials{
+					Login:    "login",
+					Password: "password",
+				},
+			},
+			wantErr: entity.ErrServerProof,
+		},
+		{
+			name: "Login_Legacy_Sends_Verifier",
+			fields: fields{
+				repo: func(ctrl *gomock.Controller) Repository {
+					repo := mocks.NewMockRepository(ctrl)
+					repo.EXPECT().SetTokens(gomock.Any(), gomock.Eq(*tokens)).Times(1).Return(nil)
+					return repo
+				},
+				client: func(controller *gomock.Controller) Client {
+					c := mocks.NewMockClient(controller)
+					salt := []byte("fake-salt")
+					c.EXPECT().SrpStart(gomock.Any(), "login", gomock.Any()).Times(1).DoAndReturn(
+						func(_ context.Context, _ string, clientPublic []byte) (*entity.SRPChallenge, error) {
+							server, err := srp.NewServer(srp.Verifier("other", salt), clientPublic)
+							if err != nil {
+								return nil, err
+							}
+							return &entity.SRPChallenge{HandshakeID: "handshake", Salt: salt, ServerPublic: server.PublicKey(), Legacy: true}, nil
+						})
+					c.EXPECT().SrpLogin(gomock.Any(), "handshake", gomock.Any()).Times(1).Return(nil, entity.ErrInvalidCredentials)
+					c.EXPECT().Login(gomock.Any(), entity.Credentials{Login: "login", Password: "password"}, gomock.Any()).Times(1).DoAndReturn(
+						func(_ context.Context, _ entity.Credentials, v entity.SRPVerifier) (*entity.Tokens, string, error) {
+							if !bytes.Equal(v.Verifier, srp.Verifier("password", v.Salt)) {
+								t.Errorf("Login() verifier does not match the password")
+							}
+							return tokens, "", nil
+						})
 					return c
 				},
 			},
@@ -164,19 +288,18 @@ func TestService_Login(t *testing.T) {
 					Password: "password",
 				},
 			},
-			wantErr: false,
 		},
 		{
 			name: "Login_Failed_Empty_Login",
 			fields: fields{
 				repo: func(ctrl *gomock.Controller) Repository {
 					repo := mocks.NewMockRepository(ctrl)
-					repo.EXPECT().SetJWT(gomock.Eq(context.Background()), gomock.Eq("jwt-token")).Times(0)
+					repo.EXPECT().SetTokens(gomock.Any(), gomock.Any()).Times(0)
 					return repo
 				},
 				client: func(controller *gomock.Controller) Client {
 					c := mocks.NewMockClient(controller)
-					c.EXPECT().Login(gomock.Any(), gomock.Any()).Times(0)
+					c.EXPECT().SrpStart(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 					return c
 				},
 			},
@@ -187,7 +310,30 @@ func TestService_Login(t *testing.T) {
 					Password: "password",
 				},
 			},
-			wantErr: true,
+			wantErr: entity.ErrEmptyCredentials,
+		},
+		{
+			name: "Login_Second_Factor_Required",
+			fields: fields{
+				repo: func(ctrl *gomock.Controller) Repository {
+					repo := mocks.NewMockRepository(ctrl)
+					repo.EXPECT().SetTokens(gomock.Any(), gomock.Any()).Times(0)
+					return repo
+				},
+				client: func(controller *gomock.Controller) Client {
+					c := mocks.NewMockClient(controller)
+					expectSRP(t, c, "password", &entity.SRPLogin{Challenge: "challenge-token"})
+					return c
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				data: entity.Credentials{
+					Login:    "login",
+					Password: "password",
+				},
+			},
+			wantErr: entity.ErrSecondFactorRequired,
 		},
 	}
 	for _, tt := range tests {
@@ -198,8 +344,175 @@ func TestService_Login(t *testing.T) {
 				repo:   tt.fields.repo(ctrl),
 				logger: log.MockLogger,
 			}
-			if err := s.Login(tt.args.ctx, tt.args.data); (err != nil) != tt.wantErr {
-				t.Errorf("Login() error = %v, wantErr %v", err, tt.wantErr)
+			if err := s.Login(tt.args.ctx, tt.args.data); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Login() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestService_VerifySecondFactor(t *testing.T) {
+	type fields struct {
+		client    func(controller *gomock.Controller) Client
+		repo      func(controller *gomock.Controller) Repository
+		challenge string
+	}
+	tests := []struct {
+		name          string
+		fields        fields
+		code          string
+		wantErr       error
+		wantChallenge string
+	}{
+		{
+			name: "Verify_Success",
+			fields: fields{
+				repo: func(ctrl *gomock.Controller) Repository {
+					repo := mocks.NewMockRepository(ctrl)
+					repo.EXPECT().SetTokens(gomock.Any(), gomock.Eq(entity.Tokens{Access: "jwt-token", Refresh: "refresh-token"})).Times(1).Return(nil)
+					return repo
+				},
+				client: func(controller *gomock.Controller) Client {
+					c := mocks.NewMockClient(controller)
+					c.EXPECT().VerifySecondFactor(gomock.Any(), "challenge-token", "123456").Times(1).
+						Return(&entity.Tokens{Access: "jwt-token", Refresh: "refresh-token"}, nil)
+					return c
+				},
+				challenge: "challenge-token",
+			},
+			code: "123456",
+		},
+		{
+			name: "Verify_Wrong_Code_Keeps_Challenge",
+			fields: fields{
+				repo: func(ctrl *gomock.Controller) Repository {
+					repo := mocks.NewMockRepository(ctrl)
+					repo.EXPECT().SetTokens(gomock.Any(), gomock.Any()).Times(0)
+					return repo
+				},
+				client: func(controller *gomock.Controller) Client {
+					c := mocks.NewMockClient(controller)
+					c.EXPECT().VerifySecondFactor(gomock.Any(), "challenge-token", "000000").Times(1).
+						Return(nil, errTest)
+					return c
+				},
+				challenge: "challenge-token",
+			},
+			code:          "000000",
+			wantErr:       errTest,
+			wantChallenge: "challenge-token",
+		},
+		{
+			name: "Verify_No_Challenge",
+			fields: fields{
+				repo: func(ctrl *gomock.Controller) Repository {
+					return mocks.NewMockRepository(ctrl)
+				},
+				client: func(controller *gomock.Controller) Client {
+					return mocks.NewMockClient(controller)
+				},
+			},
+			code:    "123456",
+			wantErr: ErrNoChallenge,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			s := &Service{
+				client:    tt.fields.client(ctrl),
+				repo:      tt.fields.repo(ctrl),
+				logger:    log.MockLogger,
+				challenge: tt.fields.challenge,
+			}
+			if err := s.VerifySecondFactor(context.Background(), tt.code); !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifySecondFactor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if s.challenge != tt.wantChallenge {
+				t.Errorf("VerifySecondFactor() challenge = %v, want %v", s.challenge, tt.wantChallenge)
+			}
+		})
+	}
+}
+
+func TestService_Refresh(t *testing.T) {
+	tests := []struct {
+		name    string
+		client  func(controller *gomock.Controller) Client
+		repo    func(controller *gomock.Controller) Repository
+		wantErr bool
+	}{
+		{
+			name: "Refresh_Success",
+			repo: func(ctrl *gomock.Controller) Repository {
+				repo := mocks.NewMockRepository(ctrl)
+				repo.EXPECT().GetJWT(gomock.Any()).Times(1).Return("stale-token", nil)
+				repo.EXPECT().GetRefreshToken(gomock.Any()).Times(1).Return("refresh-token", nil)
+				repo.EXPECT().SetTokens(gomock.Any(), entity.Tokens{Access: "jwt-token", Refresh: "next-refresh-token"}).Times(1).Return(nil)
+				return repo
+			},
+			client: func(ctrl *gomock.Controller) Client {
+				c := mocks.NewMockClient(ctrl)
+				c.EXPECT().Refresh(gomock.Any(), "refresh-token").Times(1).Return(&entity.Tokens{Access: "jwt-token", Refresh: "next-refresh-token"}, nil)
+				return c
+			},
+		},
+		{
+			name: "Refresh_Already_Refreshed_Skip",
+			repo: func(ctrl *gomock.Controller) Repository {
+				repo := mocks.NewMockRepository(ctrl)
+				repo.EXPECT().GetJWT(gomock.Any()).Times(1).Return("jwt-token", nil)
+				repo.EXPECT().SetTokens(gomock.Any(), gomock.Any()).Times(0)
+				return repo
+			},
+			client: func(ctrl *gomock.Controller) Client {
+				c := mocks.NewMockClient(ctrl)
+				c.EXPECT().Refresh(gomock.Any(), gomock.Any()).Times(0)
+				return c
+			},
+		},
+		{
+			name: "Refresh_No_Refresh_Token_Error",
+			repo: func(ctrl *gomock.Controller) Repository {
+				repo := mocks.NewMockRepository(ctrl)
+				repo.EXPECT().GetJWT(gomock.Any()).Times(1).Return("stale-token", nil)
+				repo.EXPECT().GetRefreshToken(gomock.Any()).Times(1).Return("", nil)
+				return repo
+			},
+			client: func(ctrl *gomock.Controller) Client {
+				c := mocks.NewMockClient(ctrl)
+				c.EXPECT().Refresh(gomock.Any(), gomock.Any()).Times(0)
+				return c
+			},
+			wantErr: true,
+		},
+		{
+			name: "Refresh_Client_Error",
+			repo: func(ctrl *gomock.Controller) Repository {
+				repo := mocks.NewMockRepository(ctrl)
+				repo.EXPECT().GetJWT(gomock.Any()).Times(1).Return("stale-token", nil)
+				repo.EXPECT().GetRefreshToken(gomock.Any()).Times(1).Return("refresh-token", nil)
+				repo.EXPECT().SetTokens(gomock.Any(), gomock.Any()).Times(0)
+				return repo
+			},
+			client: func(ctrl *gomock.Controller) Client {
+				c := mocks.NewMockClient(ctrl)
+				c.EXPECT().Refresh(gomock.Any(), gomock.Any()).Times(1).Return(nil, fmt.Errorf("unauthenticated"))
+				return c
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			s := &Service{
+				client: tt.client(ctrl),
+				repo:   tt.repo(ctrl),
+				logger: log.MockLogger,
+			}
+			if err := s.Refresh(context.Background(), "stale-token"); (err != nil) != tt.wantErr {
+				t.Errorf("Refresh() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
@@ -228,10 +541,7 @@ func TestService_Register(t *testing.T) {
 				},
 				client: func(controller *gomock.Controller) Client {
 					c := mocks.NewMockClient(controller)
-					c.EXPECT().Register(gomock.Eq(context.Background()), gomock.Eq(entity.Credentials{
-						Login:    "login",
-						Password: "password",
-					})).Times(1).Return("9b89b845-164b-498d-bc0e-f197fec9008a", nil)
+					c.EXPECT().Register(gomock.Eq(context.Background()), "login", gomock.Any()).Times(1).Return("9b89b845-164b-498d-bc0e-f197fec9008a", nil)
 					return c
 				},
 			},
@@ -252,7 +562,7 @@ func TestService_Register(t *testing.T) {
 				},
 				client: func(controller *gomock.Controller) Client {
 					c := mocks.NewMockClient(controller)
-					c.EXPECT().Register(gomock.Any(), gomock.Any()).Times(0)
+					c.EXPECT().Register(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 					return c
 				},
 			},
@@ -0,0 +1,146 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	ce "github.com/ktigay/goph-keeper/internal/client/entity"
+	"github.com/ktigay/goph-keeper/internal/entity"
+)
+
+// ErrRemoteDeleted запись с локальными изменениями удалена на сервере.
+var ErrRemoteDeleted = errors.New("data has been deleted remotely")
+
+// nextBatch возвращает неотправленный пакет или собирает новый из локальных изменений; nil - изменений нет.
+// Вызывается под s.m.
+func (s *Service) nextBatch(ctx context.Context) (*entity.UserDataBatch, error) {
+	if s.pending != nil {
+		return s.pending, nil
+	}
+
+	data, err := s.repo.ReadUnsynced(ctx)
+	if err != nil {
+		return nil, err
+	}
+	deleted, err := s.repo.ReadDeleted(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 && len(deleted) == 0 {
+		return nil, nil
+	}
+
+	// Изменения сверх размера пакета отправляются следующим пакетом.
+	data = data[:min(len(data), entity.MaxUserDataBatchSize)]
+	deleted = deleted[:min(len(deleted), entity.MaxUserDataBatchSize-len(data))]
+	s.pending = &entity.UserDataBatch{
+		UUID:    uuid.New().String(),
+		Upserts: data,
+		Deletes: deleted,
+	}
+	return s.pending, nil
+}
+
+// retryable возвращает true, если пакет мог быть применен сервером без ответа клиенту,
+// и его нужно повторить без изменений. Остальные ошибки означают, что пакет не применен.
+func retryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled:
+		return true
+	}
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// applyBatchResult применяет локально результат пакета: отправленные записи отмечаются синхронизированными,
+// удаления забываются, конфликты разрешаются по стратегии. Вызывается под s.m.
+func (s *Service) applyBatchResult(ctx context.Context, batch entity.UserDataBatch, res *entity.UserDataBatchResult) ([]entity.UserData, error) {
+	var (
+		updated   = make([]entity.UserData, 0, len(batch.Upserts))
+		purged    = make([]string, 0, len(batch.Deletes))
+		conflicts []error
+	)
+
+	sent := make(map[string]entity.UserData, len(batch.Upserts))
+	for _, d := range batch.Upserts {
+		sent[d.UUID] = d
+	}
+
+	for _, item := range res.Items {
+		if item.Deleted {
+			purged = append(purged, item.UUID)
+			continue
+		}
+		local, err := s.readOneLocal(ctx, item.UUID)
+		if err != nil {
+			return nil, err
+		}
+		// Запись удалена локально после отправки пакета, её удаление уйдет следующим пакетом.
+		if local == nil {
+			continue
+		}
+
+		switch item.Status {
+		case entity.BatchItemApplied:
+			var d *entity.UserData
+			if d, err = s.markSynced(ctx, *local, sent[item.UUID], item.Item); err != nil {
+				return nil, err
+			}
+			if d != nil {
+				updated = append(updated, *d)
+			}
+		case entity.BatchItemConflict, entity.BatchItemNotFound:
+			s.logger.Debug("userdata batch conflict", slog.String("uuid", item.UUID), slog.String("status", string(item.Status)))
+			var resolved bool
+			if resolved, err = s.handleConflict(ctx, ce.Conflict{Local: *local, Remote: item.Item}); err != nil {
+				return nil, err
+			}
+			if resolved {
+				continue
+			}
+			if item.Item != nil {
+				conflicts = append(conflicts, &ce.VersionConflictError{Current: *item.Item})
+			} else {
+				conflicts = append(conflicts, fmt.Errorf("%s: %w", item.UUID, ErrRemoteDeleted))
+			}
+		}
+	}
+
+	if len(purged) > 0 {
+		if err := s.repo.PurgeDeleted(ctx, purged...); err != nil {
+			return nil, err
+		}
+	}
+	return updated, errors.Join(conflicts...)
+}
+
+// markSynced отмечает локальную запись синхронизированной версией сервера saved.
+// Если запись изменена локально после отправки пакета, изменения сохраняются на основе новой версии
+// и уйдут следующим пакетом. saved = nil - запись удалена на сервере уже после применения пакета.
+func (s *Service) markSynced(ctx context.Context, local, sent entity.UserData, saved *entity.UserData) (*entity.UserData, error) {
+	if saved == nil {
+		return nil, nil
+	}
+	if !sameContent(local, sent) {
+		local.Version = saved.Version
+		local.IsNew = false
+		if err := s.repo.SetBase(ctx, *saved); err != nil {
+			return nil, err
+		}
+		_, err := s.repo.Replace(ctx, local)
+		return nil, err
+	}
+
+	d := *saved
+	d.IsSynced = true
+	d.IsNew = false
+	if _, err := s.repo.Replace(ctx, d); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
@@ -0,0 +1,179 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	ce "github.com/ktigay/goph-keeper/internal/client/entity"
+	"github.com/ktigay/goph-keeper/internal/entity"
+)
+
+// Strategy стратегия разрешения конфликтов синхронизации.
+type Strategy string
+
+const (
+	// StrategyLastWriterWins сохраняется версия, измененная позже. Удаление на сервере
+	// уступает локальным изменениям: время удаления неизвестно.
+	StrategyLastWriterWins Strategy = "last-writer-wins"
+	// StrategyServerWins сохраняется версия сервера.
+	StrategyServerWins Strategy = "server-wins"
+	// StrategyClientWins сохраняется локальная версия.
+	StrategyClientWins Strategy = "client-wins"
+	// StrategyKeepBoth сохраняется версия сервера, локальная - копией записи.
+	StrategyKeepBoth Strategy = "keep-both"
+	// StrategyManual конфликт сохраняется до разрешения пользователем.
+	StrategyManual Strategy = "manual"
+)
+
+// Strategies стратегии разрешения конфликтов.
+var Strategies = []Strategy{StrategyLastWriterWins, StrategyServerWins, StrategyClientWins, StrategyKeepBoth, StrategyManual}
+
+// conflictCopySuffix суффикс названия локальной копии записи при разрешении конфликта [ce.ResolutionBoth].
+const conflictCopySuffix = " (conflict copy)"
+
+// ErrConflictNotFound конфликт записи не найден.
+var ErrConflictNotFound = errors.New("conflict not found")
+
+// ParseStrategy разбирает название стратегии разрешения конфликтов.
+func ParseStrategy(s string) (Strategy, error) {
+	if !slices.Contains(Strategies, Strategy(s)) {
+		return "", fmt.Errorf("unknown conflict strategy: %s", s)
+	}
+	return Strategy(s), nil
+}
+
+// resolution способ разрешения конфликта по стратегии; false - конфликт разрешает пользователь.
+func (st Strategy) resolution(c ce.Conflict) (ce.Resolution, bool) {
+	switch st {
+	case StrategyServerWins:
+		return ce.ResolutionRemote, true
+	case StrategyClientWins:
+		return ce.ResolutionLocal, true
+	case StrategyKeepBoth:
+		return ce.ResolutionBoth, true
+	case StrategyLastWriterWins:
+		if c.Remote == nil || c.Local.UpdatedAt.After(c.Remote.UpdatedAt) {
+			return ce.ResolutionLocal, true
+		}
+		return ce.ResolutionRemote, true
+	default:
+		return "", false
+	}
+}
+
+// Conflicts возвращает неразрешенные конфликты синхронизации.
+func (s *Service) Conflicts(ctx context.Context) ([]ce.Conflict, error) {
+	return s.repo.Conflicts(ctx)
+}
+
+// Resolve разрешает конфликт записи выбранным способом. Итог отправляется на сервер при следующей синхронизации.
+func (s *Service) Resolve(ctx context.Context, uuid string, r ce.Resolution) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	conflicts, err := s.repo.Conflicts(ctx, uuid)
+	if err != nil {
+		return err
+	}
+	if len(conflicts) == 0 {
+		return ErrConflictNotFound
+	}
+	return s.resolve(ctx, conflicts[0], r)
+}
+
+// handleConflict разрешает конфликт по стратегии или сохраняет его для пользователя.
+// Возвращает false, если конфликт сохранен неразрешенным.
+func (s *Service) handleConflict(ctx context.Context, c ce.Conflict) (bool, error) {
+	base, err := s.repo.Base(ctx, c.Local.UUID)
+	if err != nil {
+		return false, err
+	}
+	c.Base = base
+
+	r, ok := s.strategy.resolution(c)
+	if !ok {
+		s.logger.Debug("userdata conflict saved", slog.String("uuid", c.Local.UUID), slog.Bool("deleted_remotely", c.Remote == nil))
+		return false, s.repo.SaveConflict(ctx, c)
+	}
+
+	s.logger.Debug("userdata conflict resolved", slog.String("uuid", c.Local.UUID), slog.String("resolution", string(r)))
+	return true, s.resolve(ctx, c, r)
+}
+
+// resolve применяет разрешение конфликта к локальным данным. Вызывается под s.m.
+func (s *Service) resolve(ctx context.Context, c ce.Conflict, r ce.Resolution) error {
+	var err error
+	switch r {
+	case ce.ResolutionRemote:
+		err = s.takeRemote(ctx, c)
+	case ce.ResolutionLocal:
+		err = s.keepLocal(ctx, c, c.Local)
+	case ce.ResolutionBoth:
+		if c.Remote == nil {
+			// Копия удаленной на сервере записи - сама локальная запись.
+			err = s.keepLocal(ctx, c, c.Local)
+			break
+		}
+		if err = s.takeRemote(ctx, c); err != nil {
+			return err
+		}
+		d := c.Local
+		d.UUID = ""
+		d.Title += conflictCopySuffix
+		d.Version = 0
+		d.Revision = 0
+		d.IsNew = true
+		d.IsSynced = false
+		_, err = s.repo.Create(ctx, d)
+	case ce.ResolutionMerge:
+		if c.Remote == nil {
+			return errors.New("item has been deleted remotely, nothing to merge with")
+		}
+		var merged entity.UserData
+		if merged, err = Merge(c.Base, c.Local, *c.Remote); err != nil {
+			return err
+		}
+		merged.CreatedAt, merged.UpdatedAt = c.Local.CreatedAt, c.Local.UpdatedAt
+		err = s.keepLocal(ctx, c, merged)
+	default:
+		return fmt.Errorf("unknown conflict resolution: %s", r)
+	}
+	if err != nil {
+		return err
+	}
+	return s.repo.DeleteConflict(ctx, c.Local.UUID)
+}
+
+// takeRemote заменяет локальную запись версией сервера.
+func (s *Service) takeRemote(ctx context.Context, c ce.Conflict) error {
+	if c.Remote == nil {
+		return s.repo.Delete(ctx, c.Local.UUID)
+	}
+	d := *c.Remote
+	d.IsSynced = true
+	d.IsNew = false
+	_, err := s.repo.Replace(ctx, d)
+	return err
+}
+
+// keepLocal сохраняет локальную версию d для отправки на сервер поверх текущей версии сервера.
+// Запись, удаленная на сервере, будет создана заново.
+func (s *Service) keepLocal(ctx context.Context, c ce.Conflict, d entity.UserData) error {
+	d.IsSynced = false
+	if c.Remote == nil {
+		d.Version = 0
+		d.IsNew = true
+	} else {
+		d.Version = c.Remote.Version
+		d.IsNew = false
+		// Версия сервера - новая база для следующего конфликта.
+		if err := s.repo.SetBase(ctx, *c.Remote); err != nil {
+			return err
+		}
+	}
+	_, err := s.repo.Replace(ctx, d)
+	return err
+}
@@ -0,0 +1,146 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	ce "github.com/ktigay/goph-keeper/internal/client/entity"
+	"github.com/ktigay/goph-keeper/internal/client/service/sync/mocks"
+	"github.com/ktigay/goph-keeper/internal/entity"
+	"github.com/ktigay/goph-keeper/internal/log"
+)
+
+func TestService_Resolve(t *testing.T) {
+	const itemUUID = "5d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf"
+	base := entity.UserData{UUID: itemUUID, Title: "Base", Type: entity.DataTypeText, Data: []byte("text"), Version: 3, IsSynced: true}
+	local := entity.UserData{UUID: itemUUID, Title: "Local", Type: entity.DataTypeText, Data: []byte("text"), Version: 3}
+	remote := entity.UserData{UUID: itemUUID, Title: "Base", Type: entity.DataTypeText, Data: []byte("remote"), Version: 5}
+	conflict := ce.Conflict{Local: local, Remote: &remote, Base: &base}
+
+	tests := []struct {
+		name       string
+		conflict   ce.Conflict
+		resolution ce.Resolution
+		repo       func(repo *mocks.MockRepository)
+		wantErr    error
+	}{
+		{
+			name:       "Resolve_Local",
+			conflict:   conflict,
+			resolution: ce.ResolutionLocal,
+			repo: func(repo *mocks.MockRepository) {
+				want := local
+				want.Version = 5
+				repo.EXPECT().SetBase(gomock.Any(), remote).Times(1).Return(nil)
+				repo.EXPECT().Replace(gomock.Any(), want).Times(1).Return(&want, nil)
+				repo.EXPECT().DeleteConflict(gomock.Any(), itemUUID).Times(1).Return(nil)
+			},
+		},
+		{
+			name:       "Resolve_Both",
+			conflict:   conflict,
+			resolution: ce.ResolutionBoth,
+			repo: func(repo *mocks.MockRepository) {
+				synced := remote
+				synced.IsSynced = true
+				repo.EXPECT().Replace(gomock.Any(), synced).Times(1).Return(&synced, nil)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, d entity.UserData) (*entity.UserData, error) {
+						if d.UUID != "" || !d.IsNew || d.Version != 0 || d.Title != "Local"+conflictCopySuffix {
+							t.Errorf("Create() got = %v, want new copy of local version", d)
+						}
+						return &d, nil
+					})
+				repo.EXPECT().DeleteConflict(gomock.Any(), itemUUID).Times(1).Return(nil)
+			},
+		},
+		{
+			name:       "Resolve_Merge",
+			conflict:   conflict,
+			resolution: ce.ResolutionMerge,
+			repo: func(repo *mocks.MockRepository) {
+				want := remote
+				want.Title = "Local"
+				repo.EXPECT().SetBase(gomock.Any(), remote).Times(1).Return(nil)
+				repo.EXPECT().Replace(gomock.Any(), want).Times(1).Return(&want, nil)
+				repo.EXPECT().DeleteConflict(gomock.Any(), itemUUID).Times(1).Return(nil)
+			},
+		},
+		{
+			name:       "Resolve_Remote_Deleted",
+			conflict:   ce.Conflict{Local: local},
+			resolution: ce.ResolutionRemote,
+			repo: func(repo *mocks.MockRepository) {
+				repo.EXPECT().Delete(gomock.Any(), itemUUID).Times(1).Return(nil)
+				repo.EXPECT().DeleteConflict(gomock.Any(), itemUUID).Times(1).Return(nil)
+			},
+		},
+		{
+			name:       "Resolve_Merge_Deleted_Error",
+			conflict:   ce.Conflict{Local: local},
+			resolution: ce.ResolutionMerge,
+			repo: func(repo *mocks.MockRepository) {
+				repo.EXPECT().DeleteConflict(gomock.Any(), gomock.Any()).Times(0)
+			},
+			wantErr: errors.New("item has been deleted remotely, nothing to merge with"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockRepository(ctrl)
+			repo.EXPECT().Conflicts(gomock.Any(), itemUUID).Times(1).Return([]ce.Conflict{tt.conflict}, nil)
+			tt.repo(repo)
+
+			s := New(mocks.NewMockClient(ctrl), repo, StrategyManual, log.MockLogger)
+			err := s.Resolve(context.Background(), itemUUID, tt.resolution)
+			if (err != nil) != (tt.wantErr != nil) {
+				t.Fatalf("Resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("Resolve_Not_Found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockRepository(ctrl)
+		repo.EXPECT().Conflicts(gomock.Any(), itemUUID).Times(1).Return(nil, nil)
+
+		s := New(mocks.NewMockClient(ctrl), repo, StrategyManual, log.MockLogger)
+		if err := s.Resolve(context.Background(), itemUUID, ce.ResolutionLocal); !errors.Is(err, ErrConflictNotFound) {
+			t.Errorf("Resolve() error = %v, want %v", err, ErrConflictNotFound)
+		}
+	})
+}
+
+func TestStrategy_resolution(t *testing.T) {
+	local := entity.UserData{UUID: "5d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf"}
+	remote := local
+	local.UpdatedAt = remote.UpdatedAt.AddDate(0, 0, 1)
+
+	tests := []struct {
+		name     string
+		strategy Strategy
+		conflict ce.Conflict
+		want     ce.Resolution
+		wantOk   bool
+	}{
+		{name: "LastWriterWins_Local_Newer", strategy: StrategyLastWriterWins, conflict: ce.Conflict{Local: local, Remote: &remote}, want: ce.ResolutionLocal, wantOk: true},
+		{name: "LastWriterWins_Remote_Newer", strategy: StrategyLastWriterWins, conflict: ce.Conflict{Local: remote, Remote: &local}, want: ce.ResolutionRemote, wantOk: true},
+		{name: "LastWriterWins_Remote_Deleted", strategy: StrategyLastWriterWins, conflict: ce.Conflict{Local: local}, want: ce.ResolutionLocal, wantOk: true},
+		{name: "ServerWins", strategy: StrategyServerWins, conflict: ce.Conflict{Local: local, Remote: &remote}, want: ce.ResolutionRemote, wantOk: true},
+		{name: "ClientWins", strategy: StrategyClientWins, conflict: ce.Conflict{Local: local, Remote: &remote}, want: ce.ResolutionLocal, wantOk: true},
+		{name: "KeepBoth", strategy: StrategyKeepBoth, conflict: ce.Conflict{Local: local, Remote: &remote}, want: ce.ResolutionBoth, wantOk: true},
+		{name: "Manual", strategy: StrategyManual, conflict: ce.Conflict{Local: local, Remote: &remote}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.strategy.resolution(tt.conflict)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("resolution() got = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package sync
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+
+	ce "github.com/ktigay/goph-keeper/internal/client/entity"
+	"github.com/ktigay/goph-keeper/internal/entity"
+)
+
+// Merge объединяет изменения записи, сделанные локально и на сервере после базовой версии base.
+// Название, поля структурированных данных и метаданные объединяются независимо: берется значение
+// стороны, которая его изменила. Поля, измененные обеими сторонами по-разному, возвращаются
+// ошибкой [*ce.MergeConflictError]. Без базовой версии различающиеся поля считаются конфликтом.
+// Результат основан на версии сервера, чтобы обновление прошло проверку версии.
+func Merge(base *entity.UserData, local, remote entity.UserData) (entity.UserData, error) {
+	var (
+		b         entity.UserData
+		hasBase   = base != nil
+		conflicts []string
+		ok        bool
+	)
+	if hasBase {
+		b = *base
+	}
+
+	merged := remote
+	if merged.Title, ok = merge3(b.Title, local.Title, remote.Title, hasBase); !ok {
+		conflicts = append(conflicts, "title")
+	}
+	if merged.Type, ok = merge3(b.Type, local.Type, remote.Type, hasBase); !ok {
+		conflicts = append(conflicts, "type")
+	}
+
+	if ok && local.Type == remote.Type && isStructured(local.Type) {
+		data, fields := mergeFields(b.Data, local.Data, remote.Data, hasBase && b.Type == local.Type)
+		merged.Data = data
+		conflicts = append(conflicts, fields...)
+	} else {
+		var data string
+		if data, ok = merge3(string(b.Data), string(local.Data), string(remote.Data), hasBase); !ok {
+			conflicts = append(conflicts, "data")
+		}
+		merged.Data = []byte(data)
+	}
+
+	meta, fields := mergeMetaData(b.MetaData, local.MetaData, remote.MetaData, hasBase)
+	merged.MetaData = meta
+	conflicts = append(conflicts, fields...)
+
+	if len(conflicts) > 0 {
+		return local, &ce.MergeConflictError{Fields: conflicts}
+	}
+	return merged, nil
+}
+
+// merge3 трехстороннее объединение значения.
+func merge3[T comparable](base, local, remote T, hasBase bool) (T, bool) {
+	switch {
+	case local == remote:
+		return local, true
+	case !hasBase:
+		return local, false
+	case local == base:
+		return remote, true
+	case remote == base:
+		return local, true
+	}
+	return local, false
+}
+
+func isStructured(t entity.UserDataType) bool {
+	return t == entity.DataTypeCard || t == entity.DataTypeCredential || t == entity.DataTypeOTP
+}
+
+// mergeFields объединяет поля JSON данных. Отсутствующее поле - пустая строка:
+// значение JSON не бывает пустым.
+func mergeFields(base, local, remote []byte, hasBase bool) ([]byte, []string) {
+	if bytes.Equal(local, remote) {
+		return local, nil
+	}
+
+	var b, l, r map[string]json.RawMessage
+	if json.Unmarshal(local, &l) != nil || json.Unmarshal(remote, &r) != nil {
+		data, ok := merge3(string(base), string(local), string(remote), hasBase)
+		if !ok {
+			return local, []string{"data"}
+		}
+		return []byte(data), nil
+	}
+	if hasBase && json.Unmarshal(base, &b) != nil {
+		hasBase = false
+	}
+
+	keys := make([]string, 0, len(l)+len(r))
+	for k := range l {
+		keys = append(keys, k)
+	}
+	for k := range r {
+		if _, ok := l[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+
+	var conflicts []string
+	merged := make(map[string]json.RawMessage, len(keys))
+	for _, k := range keys {
+		v, ok := merge3(string(b[k]), string(l[k]), string(r[k]), hasBase)
+		if !ok {
+			conflicts = append(conflicts, "data."+k)
+		}
+		if v != "" {
+			merged[k] = json.RawMessage(v)
+		}
+	}
+
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return local, []string{"data"}
+	}
+	return data, conflicts
+}
+
+// mergeMetaData объединяет метаданные по названию. Порядок локальный, новые метаданные сервера добавляются в конец.
+func mergeMetaData(base, local, remote []entity.MetaData, hasBase bool) ([]entity.MetaData, []string) {
+	b, l, r := metaValues(base), metaValues(local), metaValues(remote)
+
+	titles := make([]string, 0, len(local)+len(remote))
+	for _, m := range local {
+		if !slices.Contains(titles, m.Title) {
+			titles = append(titles, m.Title)
+		}
+	}
+	for _, m := range remote {
+		if !slices.Contains(titles, m.Title) {
+			titles = append(titles, m.Title)
+		}
+	}
+
+	if len(titles) == 0 {
+		return nil, nil
+	}
+
+	var conflicts []string
+	merged := make([]entity.MetaData, 0, len(titles))
+	for _, t := range titles {
+		v, ok := merge3(b[t], l[t], r[t], hasBase)
+		if !ok {
+			conflicts = append(conflicts, "metadata."+t)
+		}
+		if v.present {
+			merged = append(merged, entity.MetaData{Title: t, Value: v.value})
+		}
+	}
+	return merged, conflicts
+}
+
+// metaValue значение метаданных; отсутствующие метаданные отличаются от пустого значения.
+type metaValue struct {
+	value   string
+	present bool
+}
+
+// metaValues значения метаданных по названию.
+func metaValues(meta []entity.MetaData) map[string]metaValue {
+	values := make(map[string]metaValue, len(meta))
+	for _, m := range meta {
+		if _, ok := values[m.Title]; !ok {
+			values[m.Title] = metaValue{value: m.Value, present: true}
+		}
+	}
+	return values
+}
@@ -0,0 +1,90 @@
+package sync
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+
+	ce "github.com/ktigay/goph-keeper/internal/client/entity"
+	"github.com/ktigay/goph-keeper/internal/entity"
+)
+
+func TestMerge(t *testing.T) {
+	credential := func(username, password, notes string) entity.UserData {
+		d := entity.UserData{UUID: "5d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf", Title: "Mail", Type: entity.DataTypeCredential}
+		_ = d.SetData(entity.UserDataCredential{Username: username, Password: password, Notes: notes})
+		return d
+	}
+	withMeta := func(d entity.UserData, meta ...entity.MetaData) entity.UserData {
+		d.MetaData = meta
+		return d
+	}
+	withTitle := func(d entity.UserData, title string) entity.UserData {
+		d.Title = title
+		return d
+	}
+	base := credential("user", "old", "")
+
+	tests := []struct {
+		name       string
+		base       *entity.UserData
+		local      entity.UserData
+		remote     entity.UserData
+		want       entity.UserData
+		wantFields []string
+	}{
+		{
+			name:   "Merge_Different_Fields",
+			base:   &base,
+			local:  withTitle(credential("user", "old", "local note"), "Mail (work)"),
+			remote: credential("user", "new", ""),
+			want:   withTitle(credential("user", "new", "local note"), "Mail (work)"),
+		},
+		{
+			name:   "Merge_Same_Change",
+			base:   &base,
+			local:  credential("user", "new", ""),
+			remote: credential("user", "new", ""),
+			want:   credential("user", "new", ""),
+		},
+		{
+			name:       "Merge_Field_Conflict",
+			base:       &base,
+			local:      credential("user", "local", ""),
+			remote:     credential("user", "remote", ""),
+			wantFields: []string{"data.password"},
+		},
+		{
+			name:   "Merge_Metadata",
+			base:   &entity.UserData{Title: "Note", Type: entity.DataTypeText, Data: []byte("text"), MetaData: []entity.MetaData{{Title: "a", Value: "1"}, {Title: "b", Value: "2"}}},
+			local:  entity.UserData{Title: "Note", Type: entity.DataTypeText, Data: []byte("text"), MetaData: []entity.MetaData{{Title: "a", Value: "10"}, {Title: "b", Value: "2"}}},
+			remote: entity.UserData{Title: "Note", Type: entity.DataTypeText, Data: []byte("changed"), MetaData: []entity.MetaData{{Title: "a", Value: "1"}, {Title: "c", Value: "3"}}},
+			want:   entity.UserData{Title: "Note", Type: entity.DataTypeText, Data: []byte("changed"), MetaData: []entity.MetaData{{Title: "a", Value: "10"}, {Title: "c", Value: "3"}}},
+		},
+		{
+			name:       "Merge_Without_Base_Conflict",
+			local:      withMeta(base, entity.MetaData{Title: "a", Value: "1"}),
+			remote:     withTitle(base, "Mail (home)"),
+			wantFields: []string{"title", "metadata.a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Merge(tt.base, tt.local, tt.remote)
+			var mergeErr *ce.MergeConflictError
+			if errors.As(err, &mergeErr) {
+				if !reflect.DeepEqual(mergeErr.Fields, tt.wantFields) {
+					t.Errorf("Merge() conflict fields = %v, want %v", mergeErr.Fields, tt.wantFields)
+				}
+				return
+			}
+			if err != nil || tt.wantFields != nil {
+				t.Fatalf("Merge() error = %v, want conflict fields %v", err, tt.wantFields)
+			}
+			if got.Title != tt.want.Title || !reflect.DeepEqual(got.GetData(), tt.want.GetData()) || !slices.Equal(got.MetaData, tt.want.MetaData) {
+				t.Errorf("Merge() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -9,7 +9,6 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
-
 	entity "github.com/ktigay/goph-keeper/internal/entity"
 )
 
@@ -36,71 +35,76 @@ func (m *MockClient) EXPECT() *MockClientMockRecorder {
 	return m.recorder
 }
 
-// Create mocks base method.
-func (m *MockClient) Create(arg0 context.Context, arg1 entity.UserData) (*entity.UserData, error) {
+// Changes mocks base method.
+func (m *MockClient) Changes(arg0 context.Context, arg1 uint64) (*entity.UserDataChanges, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", arg0, arg1)
-	ret0, _ := ret[0].(*entity.UserData)
+	ret := m.ctrl.Call(m, "Changes", arg0, arg1)
+	ret0, _ := ret[0].(*entity.UserDataChanges)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Create indicates an expected call of Create.
-func (mr *MockClientMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+// Changes indicates an expected call of Changes.
+func (mr *MockClientMockRecorder) Changes(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockClient)(nil).Create), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Changes", reflect.TypeOf((*MockClient)(nil).Changes), arg0, arg1)
 }
 
-// Delete mocks base method.
-func (m *MockClient) Delete(arg0 context.Context, arg1 ...string) error {
+// Restore mocks base method.
+func (m *MockClient) Restore(arg0 context.Context, arg1, arg2 string) (*entity.UserData, error) {
 	m.ctrl.T.Helper()
-	varargs := []interface{}{arg0}
-	for _, a := range arg1 {
-		varargs = append(varargs, a)
-	}
-	ret := m.ctrl.Call(m, "Delete", varargs...)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "Restore", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*entity.UserData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// Delete indicates an expected call of Delete.
-func (mr *MockClientMockRecorder) Delete(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+// Restore indicates an expected call of Restore.
+func (mr *MockClientMockRecorder) Restore(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	varargs := append([]interface{}{arg0}, arg1...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockClient)(nil).Delete), varargs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockClient)(nil).Restore), arg0, arg1, arg2)
 }
 
-// Read mocks base method.
-func (m *MockClient) Read(arg0 context.Context, arg1 ...string) ([]entity.UserData, error) {
+// Revisions mocks base method.
+func (m *MockClient) Revisions(arg0 context.Context, arg1 string) ([]entity.UserDataRevision, error) {
 	m.ctrl.T.Helper()
-	varargs := []interface{}{arg0}
-	for _, a := range arg1 {
-		varargs = append(varargs, a)
-	}
-	ret := m.ctrl.Call(m, "Read", varargs...)
-	ret0, _ := ret[0].([]entity.UserData)
+	ret := m.ctrl.Call(m, "Revisions", arg0, arg1)
+	ret0, _ := ret[0].([]entity.UserDataRevision)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Read indicates an expected call of Read.
-func (mr *MockClientMockRecorder) Read(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+// Revisions indicates an expected call of Revisions.
+func (mr *MockClientMockRecorder) Revisions(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	varargs := append([]interface{}{arg0}, arg1...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Read", reflect.TypeOf((*MockClient)(nil).Read), varargs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revisions", reflect.TypeOf((*MockClient)(nil).Revisions), arg0, arg1)
 }
 
-// Update mocks base method.
-func (m *MockClient) Update(arg0 context.Context, arg1 entity.UserData) (*entity.UserData, error) {
+// SyncBatch mocks base method.
+func (m *MockClient) SyncBatch(arg0 context.Context, arg1 entity.UserDataBatch) (*entity.UserDataBatchResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", arg0, arg1)
-	ret0, _ := ret[0].(*entity.UserData)
+	ret := m.ctrl.Call(m, "SyncBatch", arg0, arg1)
+	ret0, _ := ret[0].(*entity.UserDataBatchResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Update indicates an expected call of Update.
-func (mr *MockClientMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+// SyncBatch indicates an expected call of SyncBatch.
+func (mr *MockClientMockRecorder) SyncBatch(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncBatch", reflect.TypeOf((*MockClient)(nil).SyncBatch), arg0, arg1)
+}
+
+// Watch mocks base method.
+func (m *MockClient) Watch(arg0 context.Context, arg1 uint64, arg2 func(*entity.UserDataChanges) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Watch", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Watch indicates an expected call of Watch.
+func (mr *MockClientMockRecorder) Watch(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockClient)(nil).Update), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*MockClient)(nil).Watch), arg0, arg1, arg2)
 }
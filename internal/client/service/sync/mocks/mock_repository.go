@@ -0,0 +1,309 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ktigay/goph-keeper/internal/client/service/sync (interfaces: Repository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	entity "github.com/ktigay/goph-keeper/internal/client/entity"
+	entity0 "github.com/ktigay/goph-keeper/internal/entity"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Base mocks base method.
+func (m *MockRepository) Base(arg0 context.Context, arg1 string) (*entity0.UserData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Base", arg0, arg1)
+	ret0, _ := ret[0].(*entity0.UserData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Base indicates an expected call of Base.
+func (mr *MockRepositoryMockRecorder) Base(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Base", reflect.TypeOf((*MockRepository)(nil).Base), arg0, arg1)
+}
+
+// Conflicts mocks base method.
+func (m *MockRepository) Conflicts(arg0 context.Context, arg1 ...string) ([]entity.Conflict, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Conflicts", varargs...)
+	ret0, _ := ret[0].([]entity.Conflict)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Conflicts indicates an expected call of Conflicts.
+func (mr *MockRepositoryMockRecorder) Conflicts(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Conflicts", reflect.TypeOf((*MockRepository)(nil).Conflicts), varargs...)
+}
+
+// Create mocks base method.
+func (m *MockRepository) Create(arg0 context.Context, arg1 entity0.UserData) (*entity0.UserData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(*entity0.UserData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRepositoryMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method.
+func (m *MockRepository) Delete(arg0 context.Context, arg1 ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Delete", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockRepositoryMockRecorder) Delete(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), varargs...)
+}
+
+// DeleteConflict mocks base method.
+func (m *MockRepository) DeleteConflict(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteConflict", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteConflict indicates an expected call of DeleteConflict.
+func (mr *MockRepositoryMockRecorder) DeleteConflict(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteConflict", reflect.TypeOf((*MockRepository)(nil).DeleteConflict), arg0, arg1)
+}
+
+// MarkDeleted mocks base method.
+func (m *MockRepository) MarkDeleted(arg0 context.Context, arg1 ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "MarkDeleted", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkDeleted indicates an expected call of MarkDeleted.
+func (mr *MockRepositoryMockRecorder) MarkDeleted(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDeleted", reflect.TypeOf((*MockRepository)(nil).MarkDeleted), varargs...)
+}
+
+// PurgeDeleted mocks base method.
+func (m *MockRepository) PurgeDeleted(arg0 context.Context, arg1 ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PurgeDeleted", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeDeleted indicates an expected call of PurgeDeleted.
+func (mr *MockRepositoryMockRecorder) PurgeDeleted(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeleted", reflect.TypeOf((*MockRepository)(nil).PurgeDeleted), varargs...)
+}
+
+// Read mocks base method.
+func (m *MockRepository) Read(arg0 context.Context, arg1 ...string) ([]entity0.UserData, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Read", varargs...)
+	ret0, _ := ret[0].([]entity0.UserData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Read indicates an expected call of Read.
+func (mr *MockRepositoryMockRecorder) Read(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Read", reflect.TypeOf((*MockRepository)(nil).Read), varargs...)
+}
+
+// ReadDeleted mocks base method.
+func (m *MockRepository) ReadDeleted(arg0 context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadDeleted", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadDeleted indicates an expected call of ReadDeleted.
+func (mr *MockRepositoryMockRecorder) ReadDeleted(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadDeleted", reflect.TypeOf((*MockRepository)(nil).ReadDeleted), arg0)
+}
+
+// ReadUnsynced mocks base method.
+func (m *MockRepository) ReadUnsynced(arg0 context.Context) ([]entity0.UserData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadUnsynced", arg0)
+	ret0, _ := ret[0].([]entity0.UserData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadUnsynced indicates an expected call of ReadUnsynced.
+func (mr *MockRepositoryMockRecorder) ReadUnsynced(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadUnsynced", reflect.TypeOf((*MockRepository)(nil).ReadUnsynced), arg0)
+}
+
+// Replace mocks base method.
+func (m *MockRepository) Replace(arg0 context.Context, arg1 entity0.UserData) (*entity0.UserData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replace", arg0, arg1)
+	ret0, _ := ret[0].(*entity0.UserData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Replace indicates an expected call of Replace.
+func (mr *MockRepositoryMockRecorder) Replace(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockRepository)(nil).Replace), arg0, arg1)
+}
+
+// Revision mocks base method.
+func (m *MockRepository) Revision(arg0 context.Context) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revision", arg0)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revision indicates an expected call of Revision.
+func (mr *MockRepositoryMockRecorder) Revision(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revision", reflect.TypeOf((*MockRepository)(nil).Revision), arg0)
+}
+
+// SaveConflict mocks base method.
+func (m *MockRepository) SaveConflict(arg0 context.Context, arg1 entity.Conflict) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveConflict", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveConflict indicates an expected call of SaveConflict.
+func (mr *MockRepositoryMockRecorder) SaveConflict(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveConflict", reflect.TypeOf((*MockRepository)(nil).SaveConflict), arg0, arg1)
+}
+
+// SetBase mocks base method.
+func (m *MockRepository) SetBase(arg0 context.Context, arg1 entity0.UserData) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBase", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetBase indicates an expected call of SetBase.
+func (mr *MockRepositoryMockRecorder) SetBase(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBase", reflect.TypeOf((*MockRepository)(nil).SetBase), arg0, arg1)
+}
+
+// SetRevision mocks base method.
+func (m *MockRepository) SetRevision(arg0 context.Context, arg1 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRevision", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRevision indicates an expected call of SetRevision.
+func (mr *MockRepositoryMockRecorder) SetRevision(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRevision", reflect.TypeOf((*MockRepository)(nil).SetRevision), arg0, arg1)
+}
+
+// Sync mocks base method.
+func (m *MockRepository) Sync(arg0 context.Context, arg1 []entity0.UserData) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sync", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Sync indicates an expected call of Sync.
+func (mr *MockRepositoryMockRecorder) Sync(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sync", reflect.TypeOf((*MockRepository)(nil).Sync), arg0, arg1)
+}
+
+// Update mocks base method.
+func (m *MockRepository) Update(arg0 context.Context, arg1 entity0.UserData) (*entity0.UserData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(*entity0.UserData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockRepositoryMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), arg0, arg1)
+}
@@ -1,10 +1,14 @@
 package sync
 
 import (
+	"bytes"
 	"context"
-	"errors"
 	"log/slog"
+	"slices"
+	"sync"
+	"time"
 
+	ce "github.com/ktigay/goph-keeper/internal/client/entity"
 	"github.com/ktigay/goph-keeper/internal/client/service/userdata"
 	"github.com/ktigay/goph-keeper/internal/entity"
 )
@@ -13,123 +17,328 @@ import (
 //
 //go:generate mockgen -destination=./mocks/mock_client.go -package=mocks github.com/ktigay/goph-keeper/internal/client/service/sync Client
 type Client interface {
-	Create(ctx context.Context, d entity.UserData) (*entity.UserData, error)
-	Update(ctx context.Context, d entity.UserData) (*entity.UserData, error)
-	Read(ctx context.Context, uuid ...string) ([]entity.UserData, error)
-	Delete(ctx context.Context, uuids ...string) error
+	SyncBatch(ctx context.Context, batch entity.UserDataBatch) (*entity.UserDataBatchResult, error)
+	Revisions(ctx context.Context, itemUUID string) ([]entity.UserDataRevision, error)
+	Restore(ctx context.Context, itemUUID, revisionUUID string) (*entity.UserData, error)
+	Changes(ctx context.Context, since uint64) (*entity.UserDataChanges, error)
+	Watch(ctx context.Context, since uint64, apply func(*entity.UserDataChanges) error) error
+}
+
+const (
+	// watchMinBackoff пауза перед первой попыткой переподключения к потоку изменений.
+	watchMinBackoff = time.Second
+	// watchMaxBackoff наибольшая пауза между попытками переподключения.
+	watchMaxBackoff = time.Minute
+)
+
+// Repository локальный репозиторий пользовательских данных вместе с курсором синхронизации.
+//
+//go:generate mockgen -destination=./mocks/mock_repository.go -package=mocks github.com/ktigay/goph-keeper/internal/client/service/sync Repository
+type Repository interface {
+	userdata.Repository
+	// Revision ревизия данных сервера, до которой синхронизированы локальные данные; 0 - не синхронизированы.
+	Revision(ctx context.Context) (uint64, error)
+	SetRevision(ctx context.Context, revision uint64) error
+	// Base последняя синхронизированная версия записи с локальными изменениями; nil - неизвестна.
+	Base(ctx context.Context, uuid string) (*entity.UserData, error)
+	SetBase(ctx context.Context, data entity.UserData) error
+	SaveConflict(ctx context.Context, c ce.Conflict) error
+	// Conflicts неразрешенные конфликты; uuids - только конфликты этих записей.
+	Conflicts(ctx context.Context, uuids ...string) ([]ce.Conflict, error)
+	DeleteConflict(ctx context.Context, uuid string) error
 }
 
 // Service сервис синхронизации данных.
 type Service struct {
-	client Client
-	repo   userdata.Repository
-	logger *slog.Logger
+	client   Client
+	repo     Repository
+	strategy Strategy
+	logger   *slog.Logger
+	// m упорядочивает применение изменений сервера из синхронизации и потока изменений.
+	m sync.Mutex
+	// pending пакет изменений, результат которого не получен; отправляется повторно с тем же UUID.
+	pending *entity.UserDataBatch
+
+	minBackoff, maxBackoff time.Duration
 }
 
 // Initialize инициализирует пользовательские данные.
+// При первой синхронизации загружаются все записи, далее - только изменения после сохраненной ревизии.
 func (s *Service) Initialize(ctx context.Context) ([]entity.UserData, error) {
-	data, err := s.readAllRemote(ctx)
+	since, err := s.repo.Revision(ctx)
 	if err != nil {
 		return nil, err
 	}
+	if since != 0 {
+		if err = s.SyncFromRemote(ctx); err != nil {
+			return nil, err
+		}
+		return s.repo.Read(ctx)
+	}
 
-	if err = s.repo.Sync(ctx, data); err != nil {
+	changes, err := s.client.Changes(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.repo.Sync(ctx, changes.Upserts); err != nil {
 		return nil, err
 	}
-	return data, nil
+	if err = s.repo.SetRevision(ctx, changes.Revision); err != nil {
+		return nil, err
+	}
+	return changes.Upserts, nil
 }
 
-// SyncToRemote синхронизирует локальные данные на сервер.
+// SyncToRemote отправляет на сервер локальные изменения, включая удаления, одним пакетом.
+// Сервер применяет пакет одной транзакцией; если ответ не получен (Unavailable, DeadlineExceeded, Canceled),
+// тот же пакет отправляется при следующей синхронизации и не применяется повторно, отклоненный пакет забывается.
+// Конфликты с записями, измененными на сервере с другого устройства, разрешаются по стратегии;
+// неразрешенные конфликты сохраняются и возвращаются ошибками вместе с синхронизированными записями.
 func (s *Service) SyncToRemote(ctx context.Context) ([]entity.UserData, error) {
-	var (
-		data []entity.UserData
-		err  error
-	)
-	data, err = s.repo.ReadUnsynced(ctx)
+	// Поток изменений не применяет эхо отправленной записи, пока она не отмечена синхронизированной.
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	batch, err := s.nextBatch(ctx)
+	if err != nil || batch == nil {
+		return nil, err
+	}
+	res, err := s.client.SyncBatch(ctx, *batch)
 	if err != nil {
+		s.logger.Debug("error syncing userdata batch", slog.String("batch", batch.UUID), "err", err)
+		if !retryable(err) {
+			// Сервер отклонил пакет: следующий пакет собирается заново из локальных изменений.
+			s.pending = nil
+		}
 		return nil, err
 	}
-	updated := make([]entity.UserData, len(data))
-	for i := range data {
-		var d *entity.UserData
-		if data[i].IsNew {
-			d, err = s.client.Create(ctx, data[i])
-		} else {
-			d, err = s.client.Update(ctx, data[i])
+	s.pending = nil
+
+	s.logger.Debug("synced userdata batch",
+		slog.String("batch", batch.UUID),
+		slog.Uint64("revision", res.Revision),
+		slog.Int("upserts", len(batch.Upserts)),
+		slog.Int("deletes", len(batch.Deletes)),
+		slog.Bool("replayed", res.Replayed),
+	)
+	return s.applyBatchResult(ctx, *batch, res)
+}
+
+// SyncFromRemote синхронизирует с сервера изменения после сохраненной ревизии и сохраняет новую ревизию.
+// Если применить изменения не удалось, ревизия не меняется и изменения будут получены повторно.
+func (s *Service) SyncFromRemote(ctx context.Context) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	since, err := s.repo.Revision(ctx)
+	if err != nil {
+		return err
+	}
+	changes, err := s.client.Changes(ctx, since)
+	if err != nil {
+		s.logger.Debug("error reading remote changes", "err", err)
+		return err
+	}
+	return s.applyChanges(ctx, since, changes)
+}
+
+// Watch получает изменения с сервера по мере их появления, применяет их локально и вызывает onChange.
+// При обрыве потока переподключается с экспоненциально растущей паузой; завершается при отмене ctx.
+func (s *Service) Watch(ctx context.Context, onChange func()) {
+	backoff := s.minBackoff
+	for {
+		received, err := s.watch(ctx, onChange)
+		if ctx.Err() != nil {
+			return
 		}
-		if err != nil {
-			s.logger.Debug("error updating userdata", slog.String("uuid", data[i].UUID))
-			return nil, err
+		if received {
+			backoff = s.minBackoff
 		}
+		s.logger.Debug("watch changes interrupted", "err", err, "retry_in", backoff)
 
-		d.IsSynced = true
-		d.IsNew = false
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return
+		}
+		backoff = min(backoff*2, s.maxBackoff)
+	}
+}
 
-		_, err = s.repo.Replace(ctx, *d)
+// watch подписывается на изменения после сохраненной ревизии до обрыва потока.
+// received - были ли применены изменения из потока.
+func (s *Service) watch(ctx context.Context, onChange func()) (received bool, err error) {
+	since, err := s.repo.Revision(ctx)
+	if err != nil {
+		return false, err
+	}
+	err = s.client.Watch(ctx, since, func(changes *entity.UserDataChanges) error {
+		applied, err := s.applyWatched(ctx, since, changes)
 		if err != nil {
-			s.logger.Debug("error replacing userdata", slog.String("uuid", data[i].UUID))
-			return nil, err
+			return err
 		}
-		updated[i] = *d
-	}
-	return updated, nil
+		since = changes.Revision
+		received = true
+		if applied {
+			onChange()
+		}
+		return nil
+	})
+	return received, err
 }
 
-// SyncFromRemote синхронизирует данные с сервера.
-func (s *Service) SyncFromRemote(ctx context.Context) error {
-	data, err := s.readAllRemote(ctx)
+// applyWatched применяет изменения из потока. Изменения, которые уже получены
+// синхронизацией после since, пропускаются.
+func (s *Service) applyWatched(ctx context.Context, since uint64, changes *entity.UserDataChanges) (bool, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	current, err := s.repo.Revision(ctx)
 	if err != nil {
-		s.logger.Debug("error reading remote data", "err", err)
-		return err
+		return false, err
 	}
+	if current > since && changes.Revision <= current {
+		return false, nil
+	}
+	return true, s.applyChanges(ctx, since, changes)
+}
 
-	for _, d := range data {
-		_, err = s.updateLocal(ctx, d)
-		if err != nil {
+// applyChanges применяет локально изменения после ревизии since и сохраняет новую ревизию.
+// Вызывается под s.m.
+func (s *Service) applyChanges(ctx context.Context, since uint64, changes *entity.UserDataChanges) (err error) {
+	if changes.Full {
+		if err = s.dropMissingLocal(ctx, changes.Upserts); err != nil {
+			s.logger.Debug("error deleting local data", "err", err)
+			return err
+		}
+	}
+	for _, d := range changes.Upserts {
+		if err = s.updateLocal(ctx, d); err != nil {
 			s.logger.Debug("error updating local data", "err", err)
 			return err
 		}
 	}
-	return nil
+	for _, uuid := range changes.Deleted {
+		if err = s.deleteLocal(ctx, uuid); err != nil {
+			s.logger.Debug("error deleting local data", "err", err)
+			return err
+		}
+	}
+
+	s.logger.Debug("synced remote changes",
+		slog.Uint64("since", since),
+		slog.Uint64("revision", changes.Revision),
+		slog.Int("upserts", len(changes.Upserts)),
+		slog.Int("deleted", len(changes.Deleted)),
+		slog.Bool("full", changes.Full),
+	)
+	return s.repo.SetRevision(ctx, changes.Revision)
 }
 
-func (s *Service) updateLocal(ctx context.Context, data entity.UserData) (*entity.UserData, error) {
-	old, err := s.readOneLocal(ctx, data.UUID)
+// Revisions возвращает предыдущие версии записи с сервера.
+func (s *Service) Revisions(ctx context.Context, itemUUID string) ([]entity.UserDataRevision, error) {
+	return s.client.Revisions(ctx, itemUUID)
+}
+
+// Restore восстанавливает версию записи на сервере и заменяет ею локальную запись.
+// Несинхронизированные локальные изменения записи при этом теряются.
+func (s *Service) Restore(ctx context.Context, itemUUID, revisionUUID string) (*entity.UserData, error) {
+	d, err := s.client.Restore(ctx, itemUUID, revisionUUID)
 	if err != nil {
 		return nil, err
 	}
 
-	if old != nil && old.UpdatedAt.Compare(data.UpdatedAt) != 0 {
-		if data.IsSynced {
-			return nil, errors.New("data has been modified remotely")
-		}
-		return nil, errors.New("data has been modified since last sync")
+	d.IsSynced = true
+	d.IsNew = false
+	return s.repo.Replace(ctx, *d)
+}
+
+// updateLocal сохраняет запись с сервера локально. Для записи с несинхронизированными
+// локальными изменениями это конфликт, он разрешается по стратегии.
+func (s *Service) updateLocal(ctx context.Context, data entity.UserData) error {
+	old, err := s.readOneLocal(ctx, data.UUID)
+	if err != nil {
+		return err
+	}
+
+	if old != nil && !old.IsSynced && !sameContent(*old, data) {
+		_, err = s.handleConflict(ctx, ce.Conflict{Local: *old, Remote: &data})
+		return err
 	}
 
 	data.IsSynced = true
-	return s.repo.Replace(ctx, data)
+	data.IsNew = false
+	if _, err = s.repo.Replace(ctx, data); err != nil {
+		return err
+	}
+	if old != nil && !old.IsSynced {
+		// Локальные изменения совпали с изменениями на сервере.
+		return s.repo.DeleteConflict(ctx, data.UUID)
+	}
+	return nil
+}
+
+// deleteLocal удаляет локально запись, удаленную на сервере.
+// Для записи с несинхронизированными локальными изменениями это конфликт, он разрешается по стратегии.
+func (s *Service) deleteLocal(ctx context.Context, uuid string) error {
+	old, err := s.readOneLocal(ctx, uuid)
+	if err != nil || old == nil {
+		return err
+	}
+	if !old.IsSynced {
+		_, err = s.handleConflict(ctx, ce.Conflict{Local: *old})
+		return err
+	}
+	return s.repo.Delete(ctx, uuid)
 }
 
-func (s *Service) readAllRemote(ctx context.Context) ([]entity.UserData, error) {
-	return s.client.Read(ctx)
+// sameContent записи совпадают по содержимому.
+func sameContent(a, b entity.UserData) bool {
+	return a.Title == b.Title && a.Type == b.Type && bytes.Equal(a.Data, b.Data) && slices.Equal(a.MetaData, b.MetaData)
+}
+
+// dropMissingLocal удаляет синхронизированные локальные записи, которых нет среди всех записей сервера.
+func (s *Service) dropMissingLocal(ctx context.Context, remote []entity.UserData) error {
+	local, err := s.repo.Read(ctx)
+	if err != nil {
+		return err
+	}
+
+	exists := make(map[string]struct{}, len(remote))
+	for _, d := range remote {
+		exists[d.UUID] = struct{}{}
+	}
+	for _, d := range local {
+		if _, ok := exists[d.UUID]; ok || !d.IsSynced {
+			continue
+		}
+		if err = s.repo.Delete(ctx, d.UUID); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
+// readOneLocal возвращает локальную запись или nil, если её нет.
 func (s *Service) readOneLocal(ctx context.Context, uuid string) (*entity.UserData, error) {
 	data, err := s.repo.Read(ctx, uuid)
 	if err != nil {
 		return nil, err
 	}
 	if len(data) == 0 {
-		return nil, errors.New("data not found")
+		return nil, nil
 	}
 	return &data[0], nil
 }
 
 // New конструктор.
-func New(c Client, r userdata.Repository, l *slog.Logger) *Service {
+func New(c Client, r Repository, strategy Strategy, l *slog.Logger) *Service {
 	return &Service{
-		repo:   r,
-		client: c,
-		logger: l,
+		repo:       r,
+		client:     c,
+		strategy:   strategy,
+		logger:     l,
+		minBackoff: watchMinBackoff,
+		maxBackoff: watchMaxBackoff,
 	}
 }
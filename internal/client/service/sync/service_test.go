@@ -5,20 +5,36 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
+	ce "github.com/ktigay/goph-keeper/internal/client/entity"
 	"github.com/ktigay/goph-keeper/internal/client/service/sync/mocks"
-	"github.com/ktigay/goph-keeper/internal/client/service/userdata"
-	m "github.com/ktigay/goph-keeper/internal/client/service/userdata/mocks"
 	"github.com/ktigay/goph-keeper/internal/entity"
 	"github.com/ktigay/goph-keeper/internal/log"
 )
 
 func TestService_SyncFromRemote(t *testing.T) {
+	const (
+		changedUUID = "5d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf"
+		addedUUID   = "6d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf"
+		deletedUUID = "7d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf"
+	)
+	changed := entity.UserData{UUID: changedUUID, Title: "Changed", Type: entity.DataTypeText, Data: []byte("Test"), Version: 2}
+	added := entity.UserData{UUID: addedUUID, Title: "Added", Type: entity.DataTypeText, Data: []byte("Test"), Version: 1}
+	changes := &entity.UserDataChanges{
+		Revision: 12,
+		Upserts:  []entity.UserData{changed, added},
+		Deleted:  []string{deletedUUID},
+	}
+
 	type fields struct {
-		client func(*gomock.Controller) Client
-		repo   func(*gomock.Controller) userdata.Repository
+		strategy Strategy
+		client   func(*gomock.Controller) Client
+		repo     func(*gomock.Controller) Repository
 	}
 	tests := []struct {
 		name    string
@@ -30,31 +46,111 @@ func TestService_SyncFromRemote(t *testing.T) {
 			fields: fields{
 				client: func(ctrl *gomock.Controller) Client {
 					cl := mocks.NewMockClient(ctrl)
-					cl.EXPECT().Read(gomock.Any()).Times(1).Return(
-						[]entity.UserData{
-							{
-								UUID:  "5d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf",
-								Title: "Test",
-								Type:  entity.DataTypeText,
-								Data:  []byte("Test"),
-								IsNew: true,
-							},
-						}, nil)
+					cl.EXPECT().Changes(gomock.Any(), uint64(10)).Times(1).Return(changes, nil)
 					return cl
 				},
-				repo: func(ctrl *gomock.Controller) userdata.Repository {
-					e := entity.UserData{
-						UUID:  "5d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf",
-						Title: "Test",
-						Type:  entity.DataTypeText,
-						Data:  []byte("Test"),
-						IsNew: true,
+				repo: func(ctrl *gomock.Controller) Repository {
+					repo := mocks.NewMockRepository(ctrl)
+					repo.EXPECT().Revision(gomock.Any()).Times(1).Return(uint64(10), nil)
+					repo.EXPECT().Read(gomock.Any(), changedUUID).Times(1).Return([]entity.UserData{{UUID: changedUUID, Version: 1, IsSynced: true}}, nil)
+					repo.EXPECT().Read(gomock.Any(), addedUUID).Times(1).Return(nil, nil)
+					repo.EXPECT().Read(gomock.Any(), deletedUUID).Times(1).Return([]entity.UserData{{UUID: deletedUUID, IsSynced: true}}, nil)
+					for _, d := range []entity.UserData{changed, added} {
+						d.IsSynced = true
+						repo.EXPECT().Replace(gomock.Any(), gomock.Eq(d)).Times(1).Return(&d, nil)
 					}
-					repo := m.NewMockRepository(ctrl)
-					repo.EXPECT().Read(gomock.Any(), gomock.Any()).Times(1).Return([]entity.UserData{e}, nil)
-					n := e
-					n.IsSynced = true
-					repo.EXPECT().Replace(gomock.Any(), gomock.Eq(n)).Times(1).Return(&entity.UserData{}, nil)
+					repo.EXPECT().Delete(gomock.Any(), deletedUUID).Times(1).Return(nil)
+					repo.EXPECT().SetRevision(gomock.Any(), uint64(12)).Times(1).Return(nil)
+					return repo
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "SyncFromRemote_Full_DropsMissing",
+			fields: fields{
+				client: func(ctrl *gomock.Controller) Client {
+					cl := mocks.NewMockClient(ctrl)
+					cl.EXPECT().Changes(gomock.Any(), uint64(10)).Times(1).Return(&entity.UserDataChanges{
+						Revision: 40,
+						Upserts:  []entity.UserData{added},
+						Full:     true,
+					}, nil)
+					return cl
+				},
+				repo: func(ctrl *gomock.Controller) Repository {
+					repo := mocks.NewMockRepository(ctrl)
+					repo.EXPECT().Revision(gomock.Any()).Times(1).Return(uint64(10), nil)
+					repo.EXPECT().Read(gomock.Any()).Times(1).Return([]entity.UserData{
+						{UUID: deletedUUID, IsSynced: true},
+						{UUID: changedUUID, Title: "Local"},
+						{UUID: addedUUID, IsSynced: true},
+					}, nil)
+					repo.EXPECT().Delete(gomock.Any(), deletedUUID).Times(1).Return(nil)
+					repo.EXPECT().Read(gomock.Any(), addedUUID).Times(1).Return([]entity.UserData{{UUID: addedUUID, IsSynced: true}}, nil)
+					d := added
+					d.IsSynced = true
+					repo.EXPECT().Replace(gomock.Any(), gomock.Eq(d)).Times(1).Return(&d, nil)
+					repo.EXPECT().SetRevision(gomock.Any(), uint64(40)).Times(1).Return(nil)
+					return repo
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "SyncFromRemote_LocalChanges_ConflictSaved",
+			fields: fields{
+				client: func(ctrl *gomock.Controller) Client {
+					cl := mocks.NewMockClient(ctrl)
+					cl.EXPECT().Changes(gomock.Any(), uint64(10)).Times(1).Return(changes, nil)
+					return cl
+				},
+				repo: func(ctrl *gomock.Controller) Repository {
+					local := entity.UserData{UUID: changedUUID, Version: 1, Title: "Local"}
+					base := entity.UserData{UUID: changedUUID, Version: 1, Title: "Base", IsSynced: true}
+					localDeleted := entity.UserData{UUID: deletedUUID, Version: 3, Title: "Edited"}
+
+					repo := mocks.NewMockRepository(ctrl)
+					repo.EXPECT().Revision(gomock.Any()).Times(1).Return(uint64(10), nil)
+					repo.EXPECT().Read(gomock.Any(), changedUUID).Times(1).Return([]entity.UserData{local}, nil)
+					repo.EXPECT().Base(gomock.Any(), changedUUID).Times(1).Return(&base, nil)
+					repo.EXPECT().SaveConflict(gomock.Any(), ce.Conflict{Local: local, Remote: &changed, Base: &base}).Times(1).Return(nil)
+					repo.EXPECT().Read(gomock.Any(), addedUUID).Times(1).Return(nil, nil)
+					a := added
+					a.IsSynced = true
+					repo.EXPECT().Replace(gomock.Any(), gomock.Eq(a)).Times(1).Return(&a, nil)
+					repo.EXPECT().Read(gomock.Any(), deletedUUID).Times(1).Return([]entity.UserData{localDeleted}, nil)
+					repo.EXPECT().Base(gomock.Any(), deletedUUID).Times(1).Return(nil, nil)
+					repo.EXPECT().SaveConflict(gomock.Any(), ce.Conflict{Local: localDeleted}).Times(1).Return(nil)
+					repo.EXPECT().Delete(gomock.Any(), gomock.Any()).Times(0)
+					repo.EXPECT().SetRevision(gomock.Any(), uint64(12)).Times(1).Return(nil)
+					return repo
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "SyncFromRemote_LocalChanges_ServerWins",
+			fields: fields{
+				strategy: StrategyServerWins,
+				client: func(ctrl *gomock.Controller) Client {
+					cl := mocks.NewMockClient(ctrl)
+					cl.EXPECT().Changes(gomock.Any(), uint64(10)).Times(1).Return(&entity.UserDataChanges{
+						Revision: 12,
+						Upserts:  []entity.UserData{changed},
+					}, nil)
+					return cl
+				},
+				repo: func(ctrl *gomock.Controller) Repository {
+					repo := mocks.NewMockRepository(ctrl)
+					repo.EXPECT().Revision(gomock.Any()).Times(1).Return(uint64(10), nil)
+					repo.EXPECT().Read(gomock.Any(), changedUUID).Times(1).Return([]entity.UserData{{UUID: changedUUID, Version: 1, Title: "Local"}}, nil)
+					repo.EXPECT().Base(gomock.Any(), changedUUID).Times(1).Return(nil, nil)
+					c := changed
+					c.IsSynced = true
+					repo.EXPECT().Replace(gomock.Any(), gomock.Eq(c)).Times(1).Return(&c, nil)
+					repo.EXPECT().DeleteConflict(gomock.Any(), changedUUID).Times(1).Return(nil)
+					repo.EXPECT().SetRevision(gomock.Any(), uint64(12)).Times(1).Return(nil)
 					return repo
 				},
 			},
@@ -65,9 +161,10 @@ func TestService_SyncFromRemote(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			s := &Service{
-				client: tt.fields.client(ctrl),
-				repo:   tt.fields.repo(ctrl),
-				logger: log.MockLogger,
+				client:   tt.fields.client(ctrl),
+				repo:     tt.fields.repo(ctrl),
+				strategy: tt.fields.strategy,
+				logger:   log.MockLogger,
 			}
 			if err := s.SyncFromRemote(context.Background()); (err != nil) != tt.wantErr {
 				t.Errorf("SyncFromRemote() error = %v, wantErr %v", err, tt.wantErr)
@@ -77,9 +174,16 @@ func TestService_SyncFromRemote(t *testing.T) {
 }
 
 func TestService_Initialize(t *testing.T) {
+	item := entity.UserData{
+		UUID:  "5d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf",
+		Title: "Test",
+		Type:  entity.DataTypeText,
+		Data:  []byte("Test"),
+	}
+
 	type fields struct {
 		client func(*gomock.Controller) Client
-		repo   func(*gomock.Controller) userdata.Repository
+		repo   func(*gomock.Controller) Repository
 	}
 	tests := []struct {
 		name    string
@@ -88,43 +192,43 @@ func TestService_Initialize(t *testing.T) {
 		wantErr bool
 	}{
 		{
-			name: "Initialize_Success",
+			name: "Initialize_Full_Success",
 			fields: fields{
 				client: func(ctrl *gomock.Controller) Client {
 					cl := mocks.NewMockClient(ctrl)
-					cl.EXPECT().Read(gomock.Any()).Times(1).Return(
-						[]entity.UserData{
-							{
-								UUID:  "5d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf",
-								Title: "Test",
-								Type:  entity.DataTypeText,
-								Data:  []byte("Test"),
-							},
-						}, nil)
+					cl.EXPECT().Changes(gomock.Any(), uint64(0)).Times(1).Return(
+						&entity.UserDataChanges{Revision: 3, Upserts: []entity.UserData{item}}, nil)
 					return cl
 				},
-				repo: func(ctrl *gomock.Controller) userdata.Repository {
-					d := []entity.UserData{
-						{
-							UUID:  "5d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf",
-							Title: "Test",
-							Type:  entity.DataTypeText,
-							Data:  []byte("Test"),
-						},
-					}
-					repo := m.NewMockRepository(ctrl)
-					repo.EXPECT().Sync(gomock.Any(), gomock.Eq(d)).Times(1).Return(nil)
+				repo: func(ctrl *gomock.Controller) Repository {
+					repo := mocks.NewMockRepository(ctrl)
+					repo.EXPECT().Revision(gomock.Any()).Times(1).Return(uint64(0), nil)
+					repo.EXPECT().Sync(gomock.Any(), gomock.Eq([]entity.UserData{item})).Times(1).Return(nil)
+					repo.EXPECT().SetRevision(gomock.Any(), uint64(3)).Times(1).Return(nil)
 					return repo
 				},
 			},
-			want: []entity.UserData{
-				{
-					UUID:  "5d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf",
-					Title: "Test",
-					Type:  entity.DataTypeText,
-					Data:  []byte("Test"),
+			want:    []entity.UserData{item},
+			wantErr: false,
+		},
+		{
+			name: "Initialize_Delta_Success",
+			fields: fields{
+				client: func(ctrl *gomock.Controller) Client {
+					cl := mocks.NewMockClient(ctrl)
+					cl.EXPECT().Changes(gomock.Any(), uint64(3)).Times(1).Return(&entity.UserDataChanges{Revision: 3}, nil)
+					return cl
+				},
+				repo: func(ctrl *gomock.Controller) Repository {
+					repo := mocks.NewMockRepository(ctrl)
+					repo.EXPECT().Revision(gomock.Any()).Times(2).Return(uint64(3), nil)
+					repo.EXPECT().Sync(gomock.Any(), gomock.Any()).Times(0)
+					repo.EXPECT().SetRevision(gomock.Any(), uint64(3)).Times(1).Return(nil)
+					repo.EXPECT().Read(gomock.Any()).Times(1).Return([]entity.UserData{item}, nil)
+					return repo
 				},
 			},
+			want:    []entity.UserData{item},
 			wantErr: false,
 		},
 	}
@@ -149,9 +253,24 @@ func TestService_Initialize(t *testing.T) {
 }
 
 func TestService_SyncToRemote(t *testing.T) {
+	const (
+		newUUID      = "5d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf"
+		changedUUID  = "1d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf"
+		conflictUUID = "3d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf"
+		deletedUUID  = "6d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf"
+	)
+	created := entity.UserData{UUID: newUUID, Title: "Test", Type: entity.DataTypeText, Data: []byte("Test"), IsNew: true}
+	changed := entity.UserData{UUID: changedUUID, Title: "Test", Type: entity.DataTypeText, Data: []byte("Test"), Version: 2}
+	synced := func(d entity.UserData, version uint64) entity.UserData {
+		d.Version = version
+		d.IsNew = false
+		d.IsSynced = true
+		return d
+	}
+
 	type fields struct {
 		client func(*gomock.Controller) Client
-		repo   func(*gomock.Controller) userdata.Repository
+		repo   func(*gomock.Controller) Repository
 	}
 	tests := []struct {
 		name    string
@@ -160,122 +279,139 @@ func TestService_SyncToRemote(t *testing.T) {
 		wantErr bool
 	}{
 		{
-			name: "SyncToRemote_Created_Success",
+			name: "SyncToRemote_NoChanges",
 			fields: fields{
 				client: func(ctrl *gomock.Controller) Client {
-					e := entity.UserData{
-						UUID:  "5d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf",
-						Title: "Test",
-						Type:  entity.DataTypeText,
-						Data:  []byte("Test"),
-						IsNew: true,
-					}
 					cl := mocks.NewMockClient(ctrl)
-					cl.EXPECT().Create(gomock.Any(), gomock.Eq(e)).Times(1).Return(&e, nil)
+					cl.EXPECT().SyncBatch(gomock.Any(), gomock.Any()).Times(0)
 					return cl
 				},
-				repo: func(ctrl *gomock.Controller) userdata.Repository {
-					e := entity.UserData{
-						UUID:  "5d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf",
-						Title: "Test",
-						Type:  entity.DataTypeText,
-						Data:  []byte("Test"),
-						IsNew: true,
-					}
-					repo := m.NewMockRepository(ctrl)
-					repo.EXPECT().ReadUnsynced(gomock.Any()).Return([]entity.UserData{e}, nil)
-					n := e
-					n.IsNew = false
-					n.IsSynced = true
-					repo.EXPECT().Replace(gomock.Any(), gomock.Eq(n)).Times(1).Return(&entity.UserData{}, nil)
+				repo: func(ctrl *gomock.Controller) Repository {
+					repo := mocks.NewMockRepository(ctrl)
+					repo.EXPECT().ReadUnsynced(gomock.Any()).Times(1).Return(nil, nil)
+					repo.EXPECT().ReadDeleted(gomock.Any()).Times(1).Return(nil, nil)
 					return repo
 				},
 			},
-			want: []entity.UserData{
-				{
-					UUID:     "5d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf",
-					Title:    "Test",
-					Type:     entity.DataTypeText,
-					Data:     []byte("Test"),
-					IsNew:    false,
-					IsSynced: true,
+			want:    nil,
+			wantErr: false,
+		},
+		{
+			name: "SyncToRemote_Batch_Success",
+			fields: fields{
+				client: func(ctrl *gomock.Controller) Client {
+					cl := mocks.NewMockClient(ctrl)
+					cl.EXPECT().SyncBatch(gomock.Any(), gomock.Any()).Times(1).
+						DoAndReturn(func(_ context.Context, b entity.UserDataBatch) (*entity.UserDataBatchResult, error) {
+							if b.UUID == "" || !reflect.DeepEqual(b.Upserts, []entity.UserData{created, changed}) || !reflect.DeepEqual(b.Deletes, []string{deletedUUID}) {
+								t.Errorf("SyncBatch() got batch = %+v", b)
+							}
+							newSaved, changedSaved := synced(created, 1), synced(changed, 3)
+							return &entity.UserDataBatchResult{Revision: 7, Items: []entity.UserDataBatchItem{
+								{UUID: newUUID, Status: entity.BatchItemApplied, Item: &newSaved},
+								{UUID: changedUUID, Status: entity.BatchItemApplied, Item: &changedSaved},
+								{UUID: deletedUUID, Status: entity.BatchItemApplied, Deleted: true},
+							}}, nil
+						})
+					return cl
+				},
+				repo: func(ctrl *gomock.Controller) Repository {
+					repo := mocks.NewMockRepository(ctrl)
+					repo.EXPECT().ReadUnsynced(gomock.Any()).Times(1).Return([]entity.UserData{created, changed}, nil)
+					repo.EXPECT().ReadDeleted(gomock.Any()).Times(1).Return([]string{deletedUUID}, nil)
+					repo.EXPECT().Read(gomock.Any(), newUUID).Times(1).Return([]entity.UserData{created}, nil)
+					repo.EXPECT().Read(gomock.Any(), changedUUID).Times(1).Return([]entity.UserData{changed}, nil)
+					repo.EXPECT().Replace(gomock.Any(), synced(created, 1)).Times(1).Return(&entity.UserData{}, nil)
+					repo.EXPECT().Replace(gomock.Any(), synced(changed, 3)).Times(1).Return(&entity.UserData{}, nil)
+					repo.EXPECT().PurgeDeleted(gomock.Any(), deletedUUID).Times(1).Return(nil)
+					return repo
 				},
 			},
+			want:    []entity.UserData{synced(created, 1), synced(changed, 3)},
 			wantErr: false,
 		},
 		{
-			name: "SyncToRemote_Updated_Success",
+			name: "SyncToRemote_Batch_Failed_KeepLocal",
 			fields: fields{
 				client: func(ctrl *gomock.Controller) Client {
-					e := entity.UserData{
-						UUID:  "1d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf",
-						Title: "Test",
-						Type:  entity.DataTypeText,
-						Data:  []byte("Test"),
-						IsNew: false,
-					}
 					cl := mocks.NewMockClient(ctrl)
-					cl.EXPECT().Update(gomock.Any(), gomock.Eq(e)).Times(1).Return(&e, nil)
+					cl.EXPECT().SyncBatch(gomock.Any(), gomock.Any()).Times(1).Return(nil, fmt.Errorf("some error"))
 					return cl
 				},
-				repo: func(ctrl *gomock.Controller) userdata.Repository {
-					e := entity.UserData{
-						UUID:  "1d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf",
-						Title: "Test",
-						Type:  entity.DataTypeText,
-						Data:  []byte("Test"),
-						IsNew: false,
-					}
-					repo := m.NewMockRepository(ctrl)
-					repo.EXPECT().ReadUnsynced(gomock.Any()).Return([]entity.UserData{e}, nil)
-					n := e
-					n.IsNew = false
-					n.IsSynced = true
-					repo.EXPECT().Replace(gomock.Any(), gomock.Eq(n)).Times(1).Return(&entity.UserData{}, nil)
+				repo: func(ctrl *gomock.Controller) Repository {
+					repo := mocks.NewMockRepository(ctrl)
+					repo.EXPECT().ReadUnsynced(gomock.Any()).Times(1).Return([]entity.UserData{changed}, nil)
+					repo.EXPECT().ReadDeleted(gomock.Any()).Times(1).Return([]string{deletedUUID}, nil)
+					repo.EXPECT().Replace(gomock.Any(), gomock.Any()).Times(0)
+					repo.EXPECT().PurgeDeleted(gomock.Any(), gomock.Any()).Times(0)
 					return repo
 				},
 			},
-			want: []entity.UserData{
-				{
-					UUID:     "1d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf",
-					Title:    "Test",
-					Type:     entity.DataTypeText,
-					Data:     []byte("Test"),
-					IsNew:    false,
-					IsSynced: true,
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "SyncToRemote_ChangedAfterSend_KeptUnsynced",
+			fields: fields{
+				client: func(ctrl *gomock.Controller) Client {
+					cl := mocks.NewMockClient(ctrl)
+					cl.EXPECT().SyncBatch(gomock.Any(), gomock.Any()).Times(1).
+						DoAndReturn(func(_ context.Context, _ entity.UserDataBatch) (*entity.UserDataBatchResult, error) {
+							saved := synced(changed, 3)
+							return &entity.UserDataBatchResult{Items: []entity.UserDataBatchItem{
+								{UUID: changedUUID, Status: entity.BatchItemApplied, Item: &saved},
+							}}, nil
+						})
+					return cl
+				},
+				repo: func(ctrl *gomock.Controller) Repository {
+					edited := changed
+					edited.Title = "Edited"
+					kept := edited
+					kept.Version = 3
+
+					repo := mocks.NewMockRepository(ctrl)
+					repo.EXPECT().ReadUnsynced(gomock.Any()).Times(1).Return([]entity.UserData{changed}, nil)
+					repo.EXPECT().ReadDeleted(gomock.Any()).Times(1).Return(nil, nil)
+					repo.EXPECT().Read(gomock.Any(), changedUUID).Times(1).Return([]entity.UserData{edited}, nil)
+					repo.EXPECT().SetBase(gomock.Any(), synced(changed, 3)).Times(1).Return(nil)
+					repo.EXPECT().Replace(gomock.Any(), kept).Times(1).Return(&entity.UserData{}, nil)
+					return repo
 				},
 			},
+			want:    []entity.UserData{},
 			wantErr: false,
 		},
 		{
-			name: "SyncToRemote_Update_Failed",
+			name: "SyncToRemote_VersionConflict_Skipped",
 			fields: fields{
 				client: func(ctrl *gomock.Controller) Client {
-					e := entity.UserData{
-						UUID:  "2d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf",
-						Title: "Test",
-						Type:  entity.DataTypeText,
-						Data:  []byte("Test"),
-						IsNew: false,
-					}
 					cl := mocks.NewMockClient(ctrl)
-					cl.EXPECT().Update(gomock.Any(), gomock.Eq(e)).Times(1).Return(nil, fmt.Errorf("some error"))
+					cl.EXPECT().SyncBatch(gomock.Any(), gomock.Any()).Times(1).
+						DoAndReturn(func(_ context.Context, _ entity.UserDataBatch) (*entity.UserDataBatchResult, error) {
+							current := entity.UserData{UUID: conflictUUID, Version: 5}
+							saved := synced(created, 1)
+							return &entity.UserDataBatchResult{Items: []entity.UserDataBatchItem{
+								{UUID: conflictUUID, Status: entity.BatchItemConflict, Item: &current},
+								{UUID: newUUID, Status: entity.BatchItemApplied, Item: &saved},
+							}}, nil
+						})
 					return cl
 				},
-				repo: func(ctrl *gomock.Controller) userdata.Repository {
-					repo := m.NewMockRepository(ctrl)
-					repo.EXPECT().ReadUnsynced(gomock.Any()).Return([]entity.UserData{{
-						UUID:  "2d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf",
-						Title: "Test",
-						Type:  entity.DataTypeText,
-						Data:  []byte("Test"),
-						IsNew: false,
-					}}, nil)
-					repo.EXPECT().Replace(gomock.Any(), gomock.Any()).Times(0)
+				repo: func(ctrl *gomock.Controller) Repository {
+					local := entity.UserData{UUID: conflictUUID, Title: "Conflict", Version: 4}
+					repo := mocks.NewMockRepository(ctrl)
+					repo.EXPECT().ReadUnsynced(gomock.Any()).Times(1).Return([]entity.UserData{local, created}, nil)
+					repo.EXPECT().ReadDeleted(gomock.Any()).Times(1).Return(nil, nil)
+					repo.EXPECT().Read(gomock.Any(), conflictUUID).Times(1).Return([]entity.UserData{local}, nil)
+					repo.EXPECT().Read(gomock.Any(), newUUID).Times(1).Return([]entity.UserData{created}, nil)
+					repo.EXPECT().Base(gomock.Any(), conflictUUID).Times(1).Return(nil, nil)
+					repo.EXPECT().SaveConflict(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+					repo.EXPECT().Replace(gomock.Any(), synced(created, 1)).Times(1).Return(&entity.UserData{}, nil)
 					return repo
 				},
 			},
-			want:    nil,
+			want:    []entity.UserData{synced(created, 1)},
 			wantErr: true,
 		},
 	}
@@ -298,3 +434,143 @@ func TestService_SyncToRemote(t *testing.T) {
 		})
 	}
 }
+
+func TestService_SyncToRemote_RetrySameBatch(t *testing.T) {
+	const deletedUUID = "6d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf"
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockRepository(ctrl)
+	repo.EXPECT().ReadUnsynced(gomock.Any()).Times(1).Return(nil, nil)
+	repo.EXPECT().ReadDeleted(gomock.Any()).Times(1).Return([]string{deletedUUID}, nil)
+	repo.EXPECT().PurgeDeleted(gomock.Any(), deletedUUID).Times(1).Return(nil)
+
+	var batches []string
+	cl := mocks.NewMockClient(ctrl)
+	gomock.InOrder(
+		cl.EXPECT().SyncBatch(gomock.Any(), gomock.Any()).Times(1).
+			DoAndReturn(func(_ context.Context, b entity.UserDataBatch) (*entity.UserDataBatchResult, error) {
+				batches = append(batches, b.UUID)
+				return nil, status.Error(codes.DeadlineExceeded, "deadline exceeded")
+			}),
+		cl.EXPECT().SyncBatch(gomock.Any(), gomock.Any()).Times(1).
+			DoAndReturn(func(_ context.Context, b entity.UserDataBatch) (*entity.UserDataBatchResult, error) {
+				batches = append(batches, b.UUID)
+				return &entity.UserDataBatchResult{Replayed: true, Items: []entity.UserDataBatchItem{
+					{UUID: deletedUUID, Status: entity.BatchItemApplied, Deleted: true},
+				}}, nil
+			}),
+	)
+
+	s := New(cl, repo, StrategyManual, log.MockLogger)
+	if _, err := s.SyncToRemote(context.Background()); err == nil {
+		t.Fatal("SyncToRemote() error = nil, want transport error")
+	}
+	if _, err := s.SyncToRemote(context.Background()); err != nil {
+		t.Fatalf("SyncToRemote() retry error = %v", err)
+	}
+	if len(batches) != 2 || batches[0] != batches[1] {
+		t.Errorf("SyncToRemote() batches = %v, want the same batch sent twice", batches)
+	}
+	if s.pending != nil {
+		t.Errorf("SyncToRemote() pending = %+v, want nil after a response", s.pending)
+	}
+}
+
+func TestService_SyncToRemote_DropRejectedBatch(t *testing.T) {
+	const deletedUUID = "6d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf"
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockRepository(ctrl)
+	repo.EXPECT().ReadUnsynced(gomock.Any()).Times(2).Return(nil, nil)
+	repo.EXPECT().ReadDeleted(gomock.Any()).Times(2).Return([]string{deletedUUID}, nil)
+
+	var batches []string
+	cl := mocks.NewMockClient(ctrl)
+	cl.EXPECT().SyncBatch(gomock.Any(), gomock.Any()).Times(2).
+		DoAndReturn(func(_ context.Context, b entity.UserDataBatch) (*entity.UserDataBatchResult, error) {
+			batches = append(batches, b.UUID)
+			return nil, status.Error(codes.PermissionDenied, "access token scope does not allow changing items")
+		})
+
+	s := New(cl, repo, StrategyManual, log.MockLogger)
+	for range 2 {
+		if _, err := s.SyncToRemote(context.Background()); status.Code(err) != codes.PermissionDenied {
+			t.Fatalf("SyncToRemote() error = %v, want %v", err, codes.PermissionDenied)
+		}
+		if s.pending != nil {
+			t.Fatalf("SyncToRemote() pending = %+v, want nil after a rejected batch", s.pending)
+		}
+	}
+	if len(batches) != 2 || batches[0] == batches[1] {
+		t.Errorf("SyncToRemote() batches = %v, want a new batch after rejection", batches)
+	}
+}
+
+func TestService_Restore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	restored := entity.UserData{UUID: "5d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf", Title: "Old", Version: 6}
+
+	cl := mocks.NewMockClient(ctrl)
+	cl.EXPECT().Restore(gomock.Any(), restored.UUID, "6e44e37e-58c6-4a7f-bd4a-d0a7f6b2d2d0").Times(1).Return(&restored, nil)
+
+	want := restored
+	want.IsSynced = true
+	repo := mocks.NewMockRepository(ctrl)
+	repo.EXPECT().Replace(gomock.Any(), gomock.Eq(want)).Times(1).Return(&want, nil)
+
+	s := New(cl, repo, StrategyManual, log.MockLogger)
+	got, err := s.Restore(context.Background(), restored.UUID, "6e44e37e-58c6-4a7f-bd4a-d0a7f6b2d2d0")
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if !reflect.DeepEqual(*got, want) {
+		t.Errorf("Restore() got = %v, want %v", *got, want)
+	}
+}
+
+func TestService_Watch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	added := entity.UserData{UUID: "6d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf", Title: "Added", Version: 1}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cl := mocks.NewMockClient(ctrl)
+	gomock.InOrder(
+		cl.EXPECT().Watch(gomock.Any(), uint64(10), gomock.Any()).Times(1).
+			DoAndReturn(func(_ context.Context, _ uint64, apply func(*entity.UserDataChanges) error) error {
+				if err := apply(&entity.UserDataChanges{Revision: 11, Upserts: []entity.UserData{added}}); err != nil {
+					return err
+				}
+				// Ревизию 12 уже получила синхронизация, изменения пропускаются.
+				if err := apply(&entity.UserDataChanges{Revision: 12, Deleted: []string{added.UUID}}); err != nil {
+					return err
+				}
+				return fmt.Errorf("stream broken")
+			}),
+		cl.EXPECT().Watch(gomock.Any(), uint64(13), gomock.Any()).Times(1).
+			DoAndReturn(func(ctx context.Context, _ uint64, _ func(*entity.UserDataChanges) error) error {
+				cancel()
+				return ctx.Err()
+			}),
+	)
+
+	want := added
+	want.IsSynced = true
+	repo := mocks.NewMockRepository(ctrl)
+	gomock.InOrder(
+		repo.EXPECT().Revision(gomock.Any()).Times(2).Return(uint64(10), nil),
+		repo.EXPECT().Read(gomock.Any(), added.UUID).Times(1).Return(nil, nil),
+		repo.EXPECT().Replace(gomock.Any(), gomock.Eq(want)).Times(1).Return(&want, nil),
+		repo.EXPECT().SetRevision(gomock.Any(), uint64(11)).Times(1).Return(nil),
+		repo.EXPECT().Revision(gomock.Any()).Times(2).Return(uint64(13), nil),
+	)
+
+	s := New(cl, repo, StrategyManual, log.MockLogger)
+	s.minBackoff, s.maxBackoff = time.Millisecond, time.Millisecond
+
+	var redraws int
+	s.Watch(ctx, func() { redraws++ })
+	if redraws != 1 {
+		t.Errorf("Watch() onChange called %d times, want 1", redraws)
+	}
+}
@@ -69,6 +69,44 @@ func (mr *MockRepositoryMockRecorder) Delete(arg0 interface{}, arg1 ...interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), varargs...)
 }
 
+// MarkDeleted mocks base method.
+func (m *MockRepository) MarkDeleted(arg0 context.Context, arg1 ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "MarkDeleted", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkDeleted indicates an expected call of MarkDeleted.
+func (mr *MockRepositoryMockRecorder) MarkDeleted(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDeleted", reflect.TypeOf((*MockRepository)(nil).MarkDeleted), varargs...)
+}
+
+// PurgeDeleted mocks base method.
+func (m *MockRepository) PurgeDeleted(arg0 context.Context, arg1 ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PurgeDeleted", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeDeleted indicates an expected call of PurgeDeleted.
+func (mr *MockRepositoryMockRecorder) PurgeDeleted(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeleted", reflect.TypeOf((*MockRepository)(nil).PurgeDeleted), varargs...)
+}
+
 // Read mocks base method.
 func (m *MockRepository) Read(arg0 context.Context, arg1 ...string) ([]entity.UserData, error) {
 	m.ctrl.T.Helper()
@@ -89,6 +127,21 @@ func (mr *MockRepositoryMockRecorder) Read(arg0 interface{}, arg1 ...interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Read", reflect.TypeOf((*MockRepository)(nil).Read), varargs...)
 }
 
+// ReadDeleted mocks base method.
+func (m *MockRepository) ReadDeleted(arg0 context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadDeleted", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadDeleted indicates an expected call of ReadDeleted.
+func (mr *MockRepositoryMockRecorder) ReadDeleted(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadDeleted", reflect.TypeOf((*MockRepository)(nil).ReadDeleted), arg0)
+}
+
 // ReadUnsynced mocks base method.
 func (m *MockRepository) ReadUnsynced(arg0 context.Context) ([]entity.UserData, error) {
 	m.ctrl.T.Helper()
@@ -2,11 +2,21 @@ package userdata
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/ktigay/goph-keeper/internal/entity"
+	"github.com/ktigay/goph-keeper/internal/otp"
 	"github.com/ktigay/goph-keeper/internal/validator"
 )
 
+var (
+	// ErrDataNotFound данные не найдены.
+	ErrDataNotFound = errors.New("data not found")
+	// ErrNotOTP запись не является [entity.DataTypeOTP].
+	ErrNotOTP = errors.New("user data is not an otp")
+)
+
 // Repository репозиторий.
 //
 //go:generate mockgen -destination=./mocks/mock_userdata.go -package=mocks github.com/ktigay/goph-keeper/internal/client/service/userdata Repository
@@ -18,6 +28,10 @@ type Repository interface {
 	Delete(ctx context.Context, uuids ...string) error
 	Read(ctx context.Context, uuids ...string) ([]entity.UserData, error)
 	ReadUnsynced(ctx context.Context) ([]entity.UserData, error)
+	// MarkDeleted удаляет записи и запоминает их для удаления на сервере.
+	MarkDeleted(ctx context.Context, uuids ...string) error
+	ReadDeleted(ctx context.Context) ([]string, error)
+	PurgeDeleted(ctx context.Context, uuids ...string) error
 }
 
 // Service сервис пользовательских данных.
@@ -42,12 +56,12 @@ func (s *Service) Update(ctx context.Context, data entity.UserData) (*entity.Use
 	return s.repo.Update(ctx, data)
 }
 
-// Delete удаляет запись пользовательских данных.
+// Delete удаляет запись пользовательских данных. Удаление отправляется на сервер при синхронизации.
 func (s *Service) Delete(ctx context.Context, uuids ...string) error {
 	if len(uuids) == 0 {
 		return nil
 	}
-	return s.repo.Delete(ctx, uuids...)
+	return s.repo.MarkDeleted(ctx, uuids...)
 }
 
 // Read читает записи пользовательских данных.
@@ -67,6 +81,30 @@ func (s *Service) ReadOne(ctx context.Context, uuid string) (*entity.UserData, e
 	return &data[0], nil
 }
 
+// GenerateCode генерирует одноразовый код записи [entity.DataTypeOTP] на момент t.
+func (s *Service) GenerateCode(ctx context.Context, uuid string, t time.Time) (string, error) {
+	data, err := s.ReadOne(ctx, uuid)
+	if err != nil {
+		return "", err
+	}
+	if data == nil {
+		return "", ErrDataNotFound
+	}
+	if data.Type != entity.DataTypeOTP {
+		return "", ErrNotOTP
+	}
+
+	o := data.GetData().(entity.UserDataOTP)
+	return otp.Key{
+		Type:      otp.Type(o.Type),
+		Secret:    o.Secret,
+		Algorithm: otp.Algorithm(o.Algorithm),
+		Digits:    o.Digits,
+		Period:    o.Period,
+		Counter:   o.Counter,
+	}.Code(t)
+}
+
 // New конструктор.
 func New(r Repository) *Service {
 	return &Service{
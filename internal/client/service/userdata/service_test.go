@@ -4,6 +4,7 @@ import (
 	"context"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 
@@ -118,7 +119,7 @@ func TestService_Delete(t *testing.T) {
 				repo: func(ctrl *gomock.Controller) Repository {
 					repo := mocks.NewMockRepository(ctrl)
 					repo.EXPECT().
-						Delete(gomock.Any(), gomock.Any()).
+						MarkDeleted(gomock.Any(), "9b89b845-164b-498d-bc0e-f197fec9008a", "5d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf").
 						Times(1).
 						Return(nil)
 					return repo
@@ -139,7 +140,7 @@ func TestService_Delete(t *testing.T) {
 				repo: func(ctrl *gomock.Controller) Repository {
 					repo := mocks.NewMockRepository(ctrl)
 					repo.EXPECT().
-						Delete(gomock.Any(), gomock.Any()).
+						MarkDeleted(gomock.Any(), gomock.Any()).
 						Times(0)
 					return repo
 				},
@@ -403,3 +404,132 @@ func TestService_Update(t *testing.T) {
 		})
 	}
 }
+
+func TestService_GenerateCode(t *testing.T) {
+	type fields struct {
+		repo func(*gomock.Controller) Repository
+	}
+	type args struct {
+		uuid string
+		t    time.Time
+	}
+	otpItem := func(data string) entity.UserData {
+		return entity.UserData{
+			UUID:  "5d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf",
+			Title: "Test",
+			Type:  entity.DataTypeOTP,
+			Data:  []byte(data),
+		}
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "GenerateCode_TOTP_SHA1_RFC6238",
+			fields: fields{
+				repo: func(ctrl *gomock.Controller) Repository {
+					repo := mocks.NewMockRepository(ctrl)
+					repo.EXPECT().Read(gomock.Any(), gomock.Eq("5d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf")).Times(1).Return([]entity.UserData{
+						otpItem(`{"type":"TOTP","secret":"GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ","algorithm":"SHA1","digits":8,"period":30}`),
+					}, nil)
+					return repo
+				},
+			},
+			args: args{
+				uuid: "5d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf",
+				t:    time.Unix(1111111109, 0),
+			},
+			want: "07081804",
+		},
+		{
+			name: "GenerateCode_TOTP_SHA256_RFC6238",
+			fields: fields{
+				repo: func(ctrl *gomock.Controller) Repository {
+					repo := mocks.NewMockRepository(ctrl)
+					repo.EXPECT().Read(gomock.Any(), gomock.Any()).Times(1).Return([]entity.UserData{
+						otpItem(`{"type":"TOTP","secret":"GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZA","algorithm":"SHA256","digits":8,"period":30}`),
+					}, nil)
+					return repo
+				},
+			},
+			args: args{
+				uuid: "5d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf",
+				t:    time.Unix(59, 0),
+			},
+			want: "46119246",
+		},
+		{
+			name: "GenerateCode_HOTP_RFC4226",
+			fields: fields{
+				repo: func(ctrl *gomock.Controller) Repository {
+					repo := mocks.NewMockRepository(ctrl)
+					repo.EXPECT().Read(gomock.Any(), gomock.Any()).Times(1).Return([]entity.UserData{
+						otpItem(`{"type":"HOTP","secret":"GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ","algorithm":"SHA1","digits":6,"counter":9}`),
+					}, nil)
+					return repo
+				},
+			},
+			args: args{
+				uuid: "5d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf",
+				t:    time.Now(),
+			},
+			want: "520489",
+		},
+		{
+			name: "GenerateCode_Not_OTP_Error",
+			fields: fields{
+				repo: func(ctrl *gomock.Controller) Repository {
+					repo := mocks.NewMockRepository(ctrl)
+					repo.EXPECT().Read(gomock.Any(), gomock.Any()).Times(1).Return([]entity.UserData{
+						{
+							UUID: "5d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf",
+							Type: entity.DataTypeText,
+							Data: []byte("Test"),
+						},
+					}, nil)
+					return repo
+				},
+			},
+			args: args{
+				uuid: "5d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf",
+				t:    time.Now(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "GenerateCode_Not_Found_Error",
+			fields: fields{
+				repo: func(ctrl *gomock.Controller) Repository {
+					repo := mocks.NewMockRepository(ctrl)
+					repo.EXPECT().Read(gomock.Any(), gomock.Any()).Times(1).Return(nil, nil)
+					return repo
+				},
+			},
+			args: args{
+				uuid: "5d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf",
+				t:    time.Now(),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			s := &Service{
+				repo: tt.fields.repo(ctrl),
+			}
+			got, err := s.GenerateCode(context.Background(), tt.args.uuid, tt.args.t)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GenerateCode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GenerateCode() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
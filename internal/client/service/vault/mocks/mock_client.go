@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ktigay/goph-keeper/internal/client/service/vault (interfaces: Client)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	entity "github.com/ktigay/goph-keeper/internal/entity"
+)
+
+// MockClient is a mock of Client interface.
+type MockClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockClientMockRecorder
+}
+
+// MockClientMockRecorder is the mock recorder for MockClient.
+type MockClientMockRecorder struct {
+	mock *MockClient
+}
+
+// NewMockClient creates a new mock instance.
+func NewMockClient(ctrl *gomock.Controller) *MockClient {
+	mock := &MockClient{ctrl: ctrl}
+	mock.recorder = &MockClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClient) EXPECT() *MockClientMockRecorder {
+	return m.recorder
+}
+
+// BeginRotation mocks base method.
+func (m *MockClient) BeginRotation(arg0 context.Context, arg1 entity.VaultKey) (*entity.VaultKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginRotation", arg0, arg1)
+	ret0, _ := ret[0].(*entity.VaultKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginRotation indicates an expected call of BeginRotation.
+func (mr *MockClientMockRecorder) BeginRotation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginRotation", reflect.TypeOf((*MockClient)(nil).BeginRotation), arg0, arg1)
+}
+
+// CommitRotation mocks base method.
+func (m *MockClient) CommitRotation(arg0 context.Context, arg1 uint32, arg2 []entity.UserData) (*entity.VaultKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CommitRotation", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*entity.VaultKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CommitRotation indicates an expected call of CommitRotation.
+func (mr *MockClientMockRecorder) CommitRotation(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommitRotation", reflect.TypeOf((*MockClient)(nil).CommitRotation), arg0, arg1, arg2)
+}
+
+// Create mocks base method.
+func (m *MockClient) Create(arg0 context.Context, arg1 entity.VaultKey) (*entity.VaultKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(*entity.VaultKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockClientMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockClient)(nil).Create), arg0, arg1)
+}
+
+// Get mocks base method.
+func (m *MockClient) Get(arg0 context.Context) (*entity.VaultKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0)
+	ret0, _ := ret[0].(*entity.VaultKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockClientMockRecorder) Get(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockClient)(nil).Get), arg0)
+}
@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ktigay/goph-keeper/internal/client/service/vault (interfaces: DataClient)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	crypto "github.com/ktigay/goph-keeper/internal/client/crypto"
+	entity "github.com/ktigay/goph-keeper/internal/entity"
+)
+
+// MockDataClient is a mock of DataClient interface.
+type MockDataClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockDataClientMockRecorder
+}
+
+// MockDataClientMockRecorder is the mock recorder for MockDataClient.
+type MockDataClientMockRecorder struct {
+	mock *MockDataClient
+}
+
+// NewMockDataClient creates a new mock instance.
+func NewMockDataClient(ctrl *gomock.Controller) *MockDataClient {
+	mock := &MockDataClient{ctrl: ctrl}
+	mock.recorder = &MockDataClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDataClient) EXPECT() *MockDataClientMockRecorder {
+	return m.recorder
+}
+
+// EncryptWith mocks base method.
+func (m *MockDataClient) EncryptWith(arg0, arg1 *crypto.Key, arg2 entity.UserData) (*entity.UserData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EncryptWith", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*entity.UserData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EncryptWith indicates an expected call of EncryptWith.
+func (mr *MockDataClientMockRecorder) EncryptWith(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EncryptWith", reflect.TypeOf((*MockDataClient)(nil).EncryptWith), arg0, arg1, arg2)
+}
+
+// MigratePlaintext mocks base method.
+func (m *MockDataClient) MigratePlaintext(arg0 context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MigratePlaintext", arg0)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MigratePlaintext indicates an expected call of MigratePlaintext.
+func (mr *MockDataClientMockRecorder) MigratePlaintext(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MigratePlaintext", reflect.TypeOf((*MockDataClient)(nil).MigratePlaintext), arg0)
+}
+
+// Read mocks base method.
+func (m *MockDataClient) Read(arg0 context.Context, arg1 ...string) ([]entity.UserData, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Read", varargs...)
+	ret0, _ := ret[0].([]entity.UserData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Read indicates an expected call of Read.
+func (mr *MockDataClientMockRecorder) Read(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Read", reflect.TypeOf((*MockDataClient)(nil).Read), varargs...)
+}
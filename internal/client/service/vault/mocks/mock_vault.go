@@ -0,0 +1,78 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ktigay/goph-keeper/internal/client/service/vault (interfaces: Repository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	crypto "github.com/ktigay/goph-keeper/internal/client/crypto"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Clear mocks base method.
+func (m *MockRepository) Clear(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Clear", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Clear indicates an expected call of Clear.
+func (mr *MockRepositoryMockRecorder) Clear(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Clear", reflect.TypeOf((*MockRepository)(nil).Clear), arg0)
+}
+
+// SetKey mocks base method.
+func (m *MockRepository) SetKey(arg0 context.Context, arg1 *crypto.Key) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetKey", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetKey indicates an expected call of SetKey.
+func (mr *MockRepositoryMockRecorder) SetKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetKey", reflect.TypeOf((*MockRepository)(nil).SetKey), arg0, arg1)
+}
+
+// SetTagKey mocks base method.
+func (m *MockRepository) SetTagKey(arg0 context.Context, arg1 *crypto.Key) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTagKey", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTagKey indicates an expected call of SetTagKey.
+func (mr *MockRepositoryMockRecorder) SetTagKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTagKey", reflect.TypeOf((*MockRepository)(nil).SetTagKey), arg0, arg1)
+}
@@ -0,0 +1,332 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/ktigay/goph-keeper/internal/client/crypto"
+	"github.com/ktigay/goph-keeper/internal/entity"
+)
+
+// firstKeyVersion версия первого ключа хранилища.
+const firstKeyVersion = 1
+
+var (
+	// ErrMasterPasswordEmpty мастер-пароль не указан.
+	ErrMasterPasswordEmpty = errors.New("master password is empty")
+	// ErrWrongMasterPassword неверный мастер-пароль.
+	ErrWrongMasterPassword = errors.New("wrong master password")
+	// ErrVaultNotFound ключ хранилища ещё не создан.
+	ErrVaultNotFound = errors.New("vault key not found")
+)
+
+// Client клиент.
+//
+//go:generate mockgen -destination=./mocks/mock_client.go -package=mocks github.com/ktigay/goph-keeper/internal/client/service/vault Client
+type Client interface {
+	Get(ctx context.Context) (*entity.VaultKey, error)
+	Create(ctx context.Context, key entity.VaultKey) (*entity.VaultKey, error)
+	BeginRotation(ctx context.Context, key entity.VaultKey) (*entity.VaultKey, error)
+	CommitRotation(ctx context.Context, keyVersion uint32, items []entity.UserData) (*entity.VaultKey, error)
+}
+
+// DataClient клиент пользовательских данных, расшифровывающий записи при чтении.
+//
+//go:generate mockgen -destination=./mocks/mock_data.go -package=mocks github.com/ktigay/goph-keeper/internal/client/service/vault DataClient
+type DataClient interface {
+	Read(ctx context.Context, uuid ...string) ([]entity.UserData, error)
+	EncryptWith(key, tagKey *crypto.Key, d entity.UserData) (*entity.UserData, error)
+	MigratePlaintext(ctx context.Context) (int, error)
+}
+
+// Repository репозиторий.
+//
+//go:generate mockgen -destination=./mocks/mock_vault.go -package=mocks github.com/ktigay/goph-keeper/internal/client/service/vault Repository
+type Repository interface {
+	SetKey(ctx context.Context, key *crypto.Key) error
+	SetTagKey(ctx context.Context, key *crypto.Key) error
+	Clear(ctx context.Context) error
+}
+
+// Service сервис ключей хранилища.
+type Service struct {
+	client Client
+	data   DataClient
+	repo   Repository
+	kdf    entity.KDFParams
+	logger *slog.Logger
+}
+
+// Unlock разблокирует хранилище мастер-паролем.
+// При первом входе создаёт ключ хранилища и сохраняет его на сервере в зашифрованном виде.
+// Если на сервере есть прерванная ротация ключа, открываемая этим мастер-паролем, она завершается.
+// Записи, созданные до включения шифрования, шифруются текущим ключом.
+func (s *Service) Unlock(ctx context.Context, masterPassword string) error {
+	if masterPassword == "" {
+		return ErrMasterPasswordEmpty
+	}
+
+	vk, err := s.client.Get(ctx)
+	if err != nil {
+		return err
+	}
+	if vk == nil {
+		s.logger.Debug("vault key not found, creating")
+		if err = s.create(ctx, masterPassword); err != nil {
+			return err
+		}
+		s.migrate(ctx)
+		return nil
+	}
+
+	keys, err := s.unwrap(masterPassword, *vk)
+	if err == nil {
+		if err = s.setKeys(ctx, keys); err != nil {
+			return err
+		}
+		s.migrate(ctx)
+		if vk.Rotation != nil {
+			next, rErr := s.unwrap(masterPassword, *vk.Rotation)
+			if rErr != nil {
+				s.logger.Info("vault key rotation is pending with another master password")
+				return nil
+			}
+			s.resume(ctx, next)
+		}
+		return nil
+	}
+
+	// Мастер-пароль мог быть изменён прерванной ротацией: ключ ротации содержит и текущий ключ.
+	if vk.Rotation == nil {
+		return ErrWrongMasterPassword
+	}
+	next, err := s.unwrap(masterPassword, *vk.Rotation)
+	if err != nil || next.previous == nil {
+		return ErrWrongMasterPassword
+	}
+	if err = s.repo.SetKey(ctx, next.previous); err != nil {
+		return err
+	}
+	s.migrate(ctx)
+	s.resume(ctx, next)
+	return nil
+}
+
+// Rotate создаёт ключ хранилища новой версии, зашифрованный мастер-паролем newMasterPassword,
+// перешифровывает им все записи и атомарно заменяет ими данные на сервере.
+// Для смены только ключа newMasterPassword совпадает с текущим.
+// Ключ тегов не меняется; хранилище, созданное без него, получает его при ротации.
+func (s *Service) Rotate(ctx context.Context, masterPassword, newMasterPassword string) error {
+	if masterPassword == "" || newMasterPassword == "" {
+		return ErrMasterPasswordEmpty
+	}
+
+	vk, err := s.client.Get(ctx)
+	if err != nil {
+		return err
+	}
+	if vk == nil {
+		return ErrVaultNotFound
+	}
+	keys, err := s.unwrap(masterPassword, *vk)
+	if err != nil {
+		return ErrWrongMasterPassword
+	}
+	if err = s.setKeys(ctx, keys); err != nil {
+		return err
+	}
+	current, tag := keys.current, keys.tag
+	if tag == nil {
+		if tag, err = crypto.GenerateKey(0); err != nil {
+			return err
+		}
+	}
+
+	salt, err := crypto.Random(crypto.SaltSize)
+	if err != nil {
+		return err
+	}
+	kek, err := crypto.DeriveKEK(newMasterPassword, salt, s.kdf)
+	if err != nil {
+		return err
+	}
+	next, err := crypto.GenerateKey(current.Version + 1)
+	if err != nil {
+		return err
+	}
+	wrapped, err := crypto.WrapKey(kek, next)
+	if err != nil {
+		return err
+	}
+	wrappedPrevious, err := crypto.WrapKey(kek, current)
+	if err != nil {
+		return err
+	}
+	wrappedTag, err := crypto.WrapTagKey(kek, tag)
+	if err != nil {
+		return err
+	}
+
+	// Ключ ротации на сервере - маркер прогресса: если ротация прервётся,
+	// она будет продолжена при следующей разблокировке хранилища.
+	if _, err = s.client.BeginRotation(ctx, entity.VaultKey{
+		Salt:               salt,
+		WrappedKey:         wrapped,
+		KeyVersion:         next.Version,
+		KDF:                s.kdf,
+		WrappedPreviousKey: wrappedPrevious,
+		WrappedTagKey:      wrappedTag,
+	}); err != nil {
+		return err
+	}
+	return s.commit(ctx, vaultKeys{current: next, previous: current, tag: tag})
+}
+
+// Lock удаляет ключи хранилища из памяти.
+func (s *Service) Lock(ctx context.Context) error {
+	return s.repo.Clear(ctx)
+}
+
+func (s *Service) create(ctx context.Context, masterPassword string) error {
+	salt, err := crypto.Random(crypto.SaltSize)
+	if err != nil {
+		return err
+	}
+	kek, err := crypto.DeriveKEK(masterPassword, salt, s.kdf)
+	if err != nil {
+		return err
+	}
+	key, err := crypto.GenerateKey(firstKeyVersion)
+	if err != nil {
+		return err
+	}
+	wrapped, err := crypto.WrapKey(kek, key)
+	if err != nil {
+		return err
+	}
+	tag, err := crypto.GenerateKey(0)
+	if err != nil {
+		return err
+	}
+	wrappedTag, err := crypto.WrapTagKey(kek, tag)
+	if err != nil {
+		return err
+	}
+
+	if _, err = s.client.Create(ctx, entity.VaultKey{
+		Salt:          salt,
+		WrappedKey:    wrapped,
+		KeyVersion:    key.Version,
+		KDF:           s.kdf,
+		WrappedTagKey: wrappedTag,
+	}); err != nil {
+		return err
+	}
+	return s.setKeys(ctx, vaultKeys{current: key, tag: tag})
+}
+
+// commit перешифровывает все записи ключом next.current и завершает ротацию на сервере.
+func (s *Service) commit(ctx context.Context, next vaultKeys) error {
+	items, err := s.data.Read(ctx)
+	if err != nil {
+		return err
+	}
+
+	encrypted := make([]entity.UserData, len(items))
+	for i := range items {
+		var d *entity.UserData
+		if d, err = s.data.EncryptWith(next.current, next.tag, items[i]); err != nil {
+			return err
+		}
+		encrypted[i] = *d
+	}
+
+	if _, err = s.client.CommitRotation(ctx, next.current.Version, encrypted); err != nil {
+		return err
+	}
+	if err = s.repo.SetKey(ctx, next.current); err != nil {
+		return err
+	}
+	return s.repo.SetTagKey(ctx, next.tag)
+}
+
+// resume продолжает прерванную ротацию. Ошибка не мешает работе с текущим ключом.
+func (s *Service) resume(ctx context.Context, next vaultKeys) {
+	if err := s.commit(ctx, next); err != nil {
+		s.logger.Info("resume vault key rotation failed", "error", err)
+		return
+	}
+	s.logger.Debug("vault key rotation resumed", "version", next.current.Version)
+}
+
+// migrate шифрует записи, созданные до включения шифрования. Ошибка не мешает разблокировке,
+// перенос повторяется при следующей.
+func (s *Service) migrate(ctx context.Context) {
+	n, err := s.data.MigratePlaintext(ctx)
+	if err != nil {
+		s.logger.Info("migrate plaintext user data failed", "error", err)
+		return
+	}
+	if n > 0 {
+		s.logger.Debug("plaintext user data encrypted", "items", n)
+	}
+}
+
+// vaultKeys расшифрованные ключи хранилища.
+type vaultKeys struct {
+	current *crypto.Key
+	// previous ключ предыдущей версии, nil - его нет.
+	previous *crypto.Key
+	// tag ключ тегов, nil - хранилище создано без него.
+	tag *crypto.Key
+}
+
+// unwrap расшифровывает ключ и, если есть, ключ предыдущей версии и ключ тегов.
+func (s *Service) unwrap(masterPassword string, vk entity.VaultKey) (vaultKeys, error) {
+	var keys vaultKeys
+
+	kek, err := crypto.DeriveKEK(masterPassword, vk.Salt, vk.KDF)
+	if err != nil {
+		return keys, err
+	}
+	if keys.current, err = crypto.UnwrapKey(kek, vk.WrappedKey, vk.KeyVersion); err != nil {
+		s.logger.Debug("unwrap vault key failed", "error", err)
+		return keys, ErrWrongMasterPassword
+	}
+	if len(vk.WrappedPreviousKey) != 0 && vk.KeyVersion > firstKeyVersion {
+		if keys.previous, err = crypto.UnwrapKey(kek, vk.WrappedPreviousKey, vk.KeyVersion-1); err != nil {
+			return keys, err
+		}
+	}
+	if len(vk.WrappedTagKey) != 0 {
+		if keys.tag, err = crypto.UnwrapTagKey(kek, vk.WrappedTagKey); err != nil {
+			return keys, err
+		}
+	}
+	return keys, nil
+}
+
+// setKeys сохраняет ключи, текущий ключ сохраняется последним и становится текущим.
+func (s *Service) setKeys(ctx context.Context, keys vaultKeys) error {
+	if keys.previous != nil {
+		if err := s.repo.SetKey(ctx, keys.previous); err != nil {
+			return err
+		}
+	}
+	if err := s.repo.SetKey(ctx, keys.current); err != nil {
+		return err
+	}
+	return s.repo.SetTagKey(ctx, keys.tag)
+}
+
+// New конструктор.
+func New(c Client, d DataClient, r Repository, kdf entity.KDFParams, l *slog.Logger) *Service {
+	return &Service{
+		client: c,
+		data:   d,
+		repo:   r,
+		kdf:    kdf,
+		logger: l,
+	}
+}
@@ -0,0 +1,296 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/ktigay/goph-keeper/internal/client/crypto"
+	"github.com/ktigay/goph-keeper/internal/client/service/vault/mocks"
+	"github.com/ktigay/goph-keeper/internal/entity"
+	"github.com/ktigay/goph-keeper/internal/log"
+)
+
+var testKDFParams = entity.KDFParams{Time: 1, Memory: 64, Threads: 1}
+
+func TestService_Unlock(t *testing.T) {
+	salt, _ := crypto.Random(crypto.SaltSize)
+	kek, _ := crypto.DeriveKEK("master", salt, testKDFParams)
+	key, _ := crypto.GenerateKey(2)
+	wrapped, _ := crypto.WrapKey(kek, key)
+	tagKey, _ := crypto.GenerateKey(0)
+	wrappedTag, _ := crypto.WrapTagKey(kek, tagKey)
+	stored := &entity.VaultKey{
+		Salt:          salt,
+		WrappedKey:    wrapped,
+		KeyVersion:    2,
+		KDF:           testKDFParams,
+		WrappedTagKey: wrappedTag,
+	}
+
+	type fields struct {
+		client func(ctrl *gomock.Controller) Client
+		repo   func(ctrl *gomock.Controller) Repository
+	}
+	tests := []struct {
+		name           string
+		fields         fields
+		masterPassword string
+		// migrated количество вызовов переноса незашифрованных записей.
+		migrated int
+		wantErr  error
+	}{
+		{
+			name: "Unlock_Existing_Key_Success",
+			fields: fields{
+				client: func(ctrl *gomock.Controller) Client {
+					c := mocks.NewMockClient(ctrl)
+					c.EXPECT().Get(gomock.Any()).Times(1).Return(stored, nil)
+					return c
+				},
+				repo: func(ctrl *gomock.Controller) Repository {
+					r := mocks.NewMockRepository(ctrl)
+					r.EXPECT().SetKey(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+						func(_ context.Context, k *crypto.Key) error {
+							if k.Version != 2 {
+								t.Errorf("SetKey() version = %v, want %v", k.Version, 2)
+							}
+							return nil
+						})
+					r.EXPECT().SetTagKey(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+						func(_ context.Context, k *crypto.Key) error {
+							if k == nil || !bytes.Equal(k.MAC([]byte("tag")), tagKey.MAC([]byte("tag"))) {
+								t.Errorf("SetTagKey() got another key")
+							}
+							return nil
+						})
+					return r
+				},
+			},
+			masterPassword: "master",
+			migrated:       1,
+		},
+		{
+			name: "Unlock_Wrong_Master_Password_Error",
+			fields: fields{
+				client: func(ctrl *gomock.Controller) Client {
+					c := mocks.NewMockClient(ctrl)
+					c.EXPECT().Get(gomock.Any()).Times(1).Return(stored, nil)
+					return c
+				},
+				repo: func(ctrl *gomock.Controller) Repository {
+					return mocks.NewMockRepository(ctrl)
+				},
+			},
+			masterPassword: "wrong",
+			wantErr:        ErrWrongMasterPassword,
+		},
+		{
+			name: "Unlock_Create_Key_Success",
+			fields: fields{
+				client: func(ctrl *gomock.Controller) Client {
+					c := mocks.NewMockClient(ctrl)
+					c.EXPECT().Get(gomock.Any()).Times(1).Return(nil, nil)
+					c.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+						func(_ context.Context, k entity.VaultKey) (*entity.VaultKey, error) {
+							if k.KeyVersion != firstKeyVersion || len(k.Salt) != crypto.SaltSize || k.KDF != testKDFParams {
+								t.Errorf("Create() got unexpected key %+v", k)
+							}
+							if len(k.WrappedTagKey) == 0 {
+								t.Errorf("Create() got key without tag key")
+							}
+							return &k, nil
+						})
+					return c
+				},
+				repo: func(ctrl *gomock.Controller) Repository {
+					r := mocks.NewMockRepository(ctrl)
+					r.EXPECT().SetKey(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+					r.EXPECT().SetTagKey(gomock.Any(), gomock.Not(gomock.Nil())).Times(1).Return(nil)
+					return r
+				},
+			},
+			masterPassword: "master",
+			migrated:       1,
+		},
+		{
+			name: "Unlock_Empty_Master_Password_Error",
+			fields: fields{
+				client: func(ctrl *gomock.Controller) Client {
+					return mocks.NewMockClient(ctrl)
+				},
+				repo: func(ctrl *gomock.Controller) Repository {
+					return mocks.NewMockRepository(ctrl)
+				},
+			},
+			wantErr: ErrMasterPasswordEmpty,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			data := mocks.NewMockDataClient(ctrl)
+			data.EXPECT().MigratePlaintext(gomock.Any()).Times(tt.migrated).Return(0, nil)
+			s := New(tt.fields.client(ctrl), data, tt.fields.repo(ctrl), testKDFParams, log.MockLogger)
+			if err := s.Unlock(context.Background(), tt.masterPassword); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Unlock() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestService_Rotate(t *testing.T) {
+	salt, _ := crypto.Random(crypto.SaltSize)
+	kek, _ := crypto.DeriveKEK("master", salt, testKDFParams)
+	key, _ := crypto.GenerateKey(2)
+	wrapped, _ := crypto.WrapKey(kek, key)
+	stored := &entity.VaultKey{
+		Salt:       salt,
+		WrappedKey: wrapped,
+		KeyVersion: 2,
+		KDF:        testKDFParams,
+	}
+	items := []entity.UserData{{UUID: "a"}, {UUID: "b"}}
+
+	ctrl := gomock.NewController(t)
+
+	var rotation entity.VaultKey
+	client := mocks.NewMockClient(ctrl)
+	client.EXPECT().Get(gomock.Any()).Times(1).Return(stored, nil)
+	client.EXPECT().BeginRotation(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+		func(_ context.Context, k entity.VaultKey) (*entity.VaultKey, error) {
+			rotation = k
+			return &k, nil
+		})
+	client.EXPECT().CommitRotation(gomock.Any(), uint32(3), gomock.Len(2)).Times(1).Return(&rotation, nil)
+
+	data := mocks.NewMockDataClient(ctrl)
+	data.EXPECT().Read(gomock.Any()).Times(1).Return(items, nil)
+	var tagKeys []*crypto.Key
+	data.EXPECT().EncryptWith(gomock.Any(), gomock.Any(), gomock.Any()).Times(2).DoAndReturn(
+		func(k, tag *crypto.Key, d entity.UserData) (*entity.UserData, error) {
+			tagKeys = append(tagKeys, tag)
+			d.KeyVersion = k.Version
+			return &d, nil
+		})
+
+	var versions []uint32
+	repo := mocks.NewMockRepository(ctrl)
+	repo.EXPECT().SetKey(gomock.Any(), gomock.Any()).Times(2).DoAndReturn(
+		func(_ context.Context, k *crypto.Key) error {
+			versions = append(versions, k.Version)
+			return nil
+		})
+
+	// Хранилище создано без ключа тегов: ротация создаёт его и сохраняет после завершения.
+	var stagedTagKeys []*crypto.Key
+	repo.EXPECT().SetTagKey(gomock.Any(), gomock.Any()).Times(2).DoAndReturn(
+		func(_ context.Context, k *crypto.Key) error {
+			stagedTagKeys = append(stagedTagKeys, k)
+			return nil
+		})
+
+	s := New(client, data, repo, testKDFParams, log.MockLogger)
+	if err := s.Rotate(context.Background(), "master", "new-master"); err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+	if !reflect.DeepEqual(versions, []uint32{2, 3}) {
+		t.Errorf("SetKey() versions = %v, want %v", versions, []uint32{2, 3})
+	}
+	if len(stagedTagKeys) != 2 || stagedTagKeys[0] != nil || stagedTagKeys[1] == nil || tagKeys[0] != stagedTagKeys[1] {
+		t.Errorf("SetTagKey() got = %v, want nil, then the key records are tagged with", stagedTagKeys)
+	}
+
+	// Новый мастер-пароль открывает и новый, и предыдущий ключ.
+	newKEK, _ := crypto.DeriveKEK("new-master", rotation.Salt, rotation.KDF)
+	if _, err := crypto.UnwrapKey(newKEK, rotation.WrappedKey, 3); err != nil {
+		t.Errorf("UnwrapKey() new key error = %v", err)
+	}
+	if _, err := crypto.UnwrapKey(newKEK, rotation.WrappedPreviousKey, 2); err != nil {
+		t.Errorf("UnwrapKey() previous key error = %v", err)
+	}
+	if _, err := crypto.UnwrapTagKey(newKEK, rotation.WrappedTagKey); err != nil {
+		t.Errorf("UnwrapTagKey() error = %v", err)
+	}
+}
+
+func TestService_Unlock_ResumeRotation(t *testing.T) {
+	oldSalt, _ := crypto.Random(crypto.SaltSize)
+	oldKEK, _ := crypto.DeriveKEK("old", oldSalt, testKDFParams)
+	current, _ := crypto.GenerateKey(1)
+	wrapped, _ := crypto.WrapKey(oldKEK, current)
+
+	newSalt, _ := crypto.Random(crypto.SaltSize)
+	newKEK, _ := crypto.DeriveKEK("new", newSalt, testKDFParams)
+	next, _ := crypto.GenerateKey(2)
+	wrappedNext, _ := crypto.WrapKey(newKEK, next)
+	wrappedPrevious, _ := crypto.WrapKey(newKEK, current)
+
+	stored := &entity.VaultKey{
+		Salt:       oldSalt,
+		WrappedKey: wrapped,
+		KeyVersion: 1,
+		KDF:        testKDFParams,
+		Rotation: &entity.VaultKey{
+			Salt:               newSalt,
+			WrappedKey:         wrappedNext,
+			KeyVersion:         2,
+			KDF:                testKDFParams,
+			WrappedPreviousKey: wrappedPrevious,
+		},
+	}
+
+	tests := []struct {
+		name           string
+		masterPassword string
+		commit         int
+		wantVersions   []uint32
+	}{
+		{
+			name:           "Unlock_New_Password_Resumes_Rotation",
+			masterPassword: "new",
+			commit:         1,
+			wantVersions:   []uint32{1, 2},
+		},
+		{
+			name:           "Unlock_Old_Password_Keeps_Current_Key",
+			masterPassword: "old",
+			wantVersions:   []uint32{1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			client := mocks.NewMockClient(ctrl)
+			client.EXPECT().Get(gomock.Any()).Times(1).Return(stored, nil)
+			client.EXPECT().CommitRotation(gomock.Any(), uint32(2), gomock.Any()).Times(tt.commit).Return(stored.Rotation, nil)
+
+			data := mocks.NewMockDataClient(ctrl)
+			// Незашифрованные записи переносятся до продолжения ротации: её чтение их не принимает.
+			migrate := data.EXPECT().MigratePlaintext(gomock.Any()).Times(1).Return(0, nil)
+			data.EXPECT().Read(gomock.Any()).Times(tt.commit).After(migrate).Return([]entity.UserData{}, nil)
+
+			var versions []uint32
+			repo := mocks.NewMockRepository(ctrl)
+			repo.EXPECT().SetKey(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+				func(_ context.Context, k *crypto.Key) error {
+					versions = append(versions, k.Version)
+					return nil
+				})
+			repo.EXPECT().SetTagKey(gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+
+			s := New(client, data, repo, testKDFParams, log.MockLogger)
+			if err := s.Unlock(context.Background(), tt.masterPassword); err != nil {
+				t.Fatalf("Unlock() error = %v", err)
+			}
+			if !reflect.DeepEqual(versions, tt.wantVersions) {
+				t.Errorf("SetKey() versions = %v, want %v", versions, tt.wantVersions)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package tlsconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+func selfSigned(t *testing.T) *x509.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "server"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func TestNew_Pin(t *testing.T) {
+	server := selfSigned(t)
+	other := selfSigned(t)
+
+	// Формат openssl x509 -fingerprint -sha256: AB:CD:...
+	var pairs []string
+	for _, b := range Fingerprint(server.Raw) {
+		pairs = append(pairs, strings.ToUpper(hex.EncodeToString([]byte{b})))
+	}
+
+	cfg, err := New(Options{PinSHA256: strings.Join(pairs, ":")})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		peer    []*x509.Certificate
+		wantErr error
+	}{
+		{name: "Pinned_Certificate", peer: []*x509.Certificate{server}},
+		{name: "Other_Certificate", peer: []*x509.Certificate{other}, wantErr: ErrFingerprintMismatch},
+		{name: "No_Certificate", wantErr: ErrFingerprintMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := cfg.VerifyConnection(tls.ConnectionState{PeerCertificates: tt.peer})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifyConnection() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseFingerprint(t *testing.T) {
+	if _, err := ParseFingerprint("abcd"); !errors.Is(err, ErrInvalidFingerprint) {
+		t.Errorf("ParseFingerprint() error = %v, want %v", err, ErrInvalidFingerprint)
+	}
+	if _, err := ParseFingerprint(strings.Repeat("zz", 32)); !errors.Is(err, ErrInvalidFingerprint) {
+		t.Errorf("ParseFingerprint() error = %v, want %v", err, ErrInvalidFingerprint)
+	}
+	if _, err := ParseFingerprint(strings.Repeat("0a", 32)); err != nil {
+		t.Errorf("ParseFingerprint() error = %v", err)
+	}
+}
@@ -3,23 +3,46 @@ package app
 import (
 	"context"
 	"log/slog"
+	"time"
 
 	"github.com/rivo/tview"
 
 	"github.com/ktigay/goph-keeper/internal/client/entity"
+	accounthandler "github.com/ktigay/goph-keeper/internal/client/tui/handler/account"
 	authhandler "github.com/ktigay/goph-keeper/internal/client/tui/handler/auth"
+	conflicthandler "github.com/ktigay/goph-keeper/internal/client/tui/handler/conflict"
+	secondfactorhandler "github.com/ktigay/goph-keeper/internal/client/tui/handler/secondfactor"
+	sessionhandler "github.com/ktigay/goph-keeper/internal/client/tui/handler/session"
 	userdatahanler "github.com/ktigay/goph-keeper/internal/client/tui/handler/userdata"
+	vaulthandler "github.com/ktigay/goph-keeper/internal/client/tui/handler/vault"
 	apppage "github.com/ktigay/goph-keeper/internal/client/tui/page"
+	"github.com/ktigay/goph-keeper/internal/client/tui/page/account"
 	"github.com/ktigay/goph-keeper/internal/client/tui/page/auth"
+	"github.com/ktigay/goph-keeper/internal/client/tui/page/conflict"
+	"github.com/ktigay/goph-keeper/internal/client/tui/page/devices"
+	"github.com/ktigay/goph-keeper/internal/client/tui/page/secondfactor"
 	"github.com/ktigay/goph-keeper/internal/client/tui/page/userdatalist"
+	"github.com/ktigay/goph-keeper/internal/client/tui/page/vault"
 	e "github.com/ktigay/goph-keeper/internal/entity"
 )
 
 // Api api сервисы.
 type Api struct {
-	AuthSrv         authhandler.Service
-	UserDataSrv     userdatahanler.Service
-	UserDataSyncSrv authhandler.SyncService
+	AuthSrv            authhandler.Service
+	UserDataSrv        userdatahanler.Service
+	UserDataSyncSrv    authhandler.SyncService
+	UserDataHistorySrv userdatahanler.HistoryService
+	VaultSrv           VaultService
+	SessionSrv         sessionhandler.Service
+	SecondFactorSrv    secondfactorhandler.Service
+	AccountSrv         accounthandler.Service
+	ConflictSrv        conflicthandler.Service
+}
+
+// VaultService сервис ключей хранилища.
+type VaultService interface {
+	authhandler.VaultService
+	vaulthandler.Service
 }
 
 // New создаёт консольное приложение.
@@ -27,7 +50,7 @@ func New(ctx context.Context, api Api, logger *slog.Logger, isSyncedCh <-chan bo
 	app := tview.NewApplication()
 	appPages := apppage.NewPages()
 
-	loginHandler := authhandler.New(api.AuthSrv, api.UserDataSyncSrv)
+	loginHandler := authhandler.New(api.AuthSrv, api.UserDataSyncSrv, api.VaultSrv)
 	loginView := auth.New(
 		auth.Callbacks{
 			OnSignIn: func(credentials entity.Credentials) error {
@@ -42,6 +65,18 @@ func New(ctx context.Context, api Api, logger *slog.Logger, isSyncedCh <-chan bo
 				logger.Debug("user signed in")
 				return nil
 			},
+			OnSecondFactor: func(credentials entity.Credentials, code string) error {
+				err := loginHandler.VerifySecondFactor(ctx, credentials, code)
+				if err != nil {
+					return err
+				}
+				appPages.SwitchToPage(apppage.UserDataList)
+
+				close(signedInCh)
+
+				logger.Debug("user signed in with second factor")
+				return nil
+			},
 			OnSignUp: func(credentials entity.Credentials) error {
 				err := loginHandler.SignUp(ctx, credentials)
 				if err != nil {
@@ -55,9 +90,10 @@ func New(ctx context.Context, api Api, logger *slog.Logger, isSyncedCh <-chan bo
 		},
 	)
 
-	userDataHandler := userdatahanler.New(api.UserDataSrv)
+	conflictHandler := conflicthandler.New(api.ConflictSrv)
+	userDataHandler := userdatahanler.New(api.UserDataSrv, api.UserDataHistorySrv)
 	var userDataView *userdatalist.Page
-	userDataView = userdatalist.New(
+	userDataView = userdatalist.New(ctx,
 		userdatalist.Callbacks{
 			OnItemUpdate: func(data e.UserData) error {
 				_, err := userDataHandler.ItemUpdate(ctx, data)
@@ -80,14 +116,155 @@ func New(ctx context.Context, api Api, logger *slog.Logger, isSyncedCh <-chan bo
 			OnQuit: func() {
 				close(quitCh)
 			},
+			OnMasterPassword: func() {
+				appPages.SwitchToPage(apppage.Vault)
+			},
+			OnDevices: func() {
+				appPages.SwitchToPage(apppage.Devices)
+			},
+			OnSecondFactor: func() {
+				appPages.SwitchToPage(apppage.SecondFactor)
+			},
+			OnAccount: func() {
+				appPages.SwitchToPage(apppage.Account)
+			},
+			OnConflicts: func() {
+				appPages.SwitchToPage(apppage.Conflicts)
+			},
+			OnConflictCount: func() int {
+				conflicts, err := conflictHandler.GetList(ctx)
+				if err != nil {
+					logger.Debug("conflicts load failed", "error", err.Error())
+				}
+				return len(conflicts)
+			},
+			OnItemRevisions: func(uuid string) ([]e.UserDataRevision, error) {
+				return userDataHandler.ItemRevisions(ctx, uuid)
+			},
+			OnItemRestore: func(uuid, revisionUUID string) error {
+				_, err := userDataHandler.ItemRestore(ctx, uuid, revisionUUID)
+				return err
+			},
+			OnGenerateCode: func(uuid string, t time.Time) (string, error) {
+				return userDataHandler.ItemCode(ctx, uuid, t)
+			},
+			QueueUpdateDraw: func(f func()) {
+				app.QueueUpdateDraw(f)
+			},
 		},
 		func() ([]e.UserData, error) {
 			return userDataHandler.GetList(ctx)
 		},
 	)
 
+	vaultHandler := vaulthandler.New(api.VaultSrv)
+	vaultView := vault.New(
+		vault.Callbacks{
+			OnChange: func(current, next, repeat string) error {
+				err := vaultHandler.ChangeMasterPassword(ctx, current, next, repeat)
+				if err != nil {
+					logger.Debug("master password change failed", "error", err.Error())
+				}
+				return err
+			},
+			OnBack: func() {
+				appPages.SwitchToPage(apppage.UserDataList)
+			},
+		},
+	)
+
+	sessionHandler := sessionhandler.New(api.SessionSrv)
+	devicesView := devices.New(
+		devices.Callbacks{
+			OnList: func() ([]e.SessionInfo, error) {
+				return sessionHandler.GetList(ctx)
+			},
+			OnRevoke: func(s e.SessionInfo) error {
+				err := sessionHandler.Revoke(ctx, s)
+				if err != nil {
+					logger.Debug("session revoke failed", "error", err.Error())
+				}
+				return err
+			},
+			OnRevokeAllOther: func() (uint32, error) {
+				return sessionHandler.RevokeAllOther(ctx)
+			},
+			OnBack: func() {
+				appPages.SwitchToPage(apppage.UserDataList)
+			},
+		},
+	)
+
+	secondFactorHandler := secondfactorhandler.New(api.SecondFactorSrv)
+	secondFactorView := secondfactor.New(
+		secondfactor.Callbacks{
+			OnEnroll: func() (string, []string, error) {
+				return secondFactorHandler.Enroll(ctx)
+			},
+			OnConfirm: func(code string) error {
+				err := secondFactorHandler.Confirm(ctx, code)
+				if err != nil {
+					logger.Debug("second factor confirm failed", "error", err.Error())
+				}
+				return err
+			},
+			OnBack: func() {
+				appPages.SwitchToPage(apppage.UserDataList)
+			},
+		},
+	)
+
+	accountHandler := accounthandler.New(api.AccountSrv)
+	accountView := account.New(
+		account.Callbacks{
+			OnChangePassword: func(current, next, repeat string) (uint32, error) {
+				revoked, err := accountHandler.ChangePassword(ctx, current, next, repeat)
+				if err != nil {
+					logger.Debug("password change failed", "error", err.Error())
+				}
+				return revoked, err
+			},
+			OnDelete: func(password string) (time.Time, error) {
+				deleteAfter, err := accountHandler.DeleteAccount(ctx, password)
+				if err != nil {
+					logger.Debug("account deletion failed", "error", err.Error())
+				}
+				return deleteAfter, err
+			},
+			OnCancelDelete: func() error {
+				return accountHandler.CancelDeletion(ctx)
+			},
+			OnBack: func() {
+				appPages.SwitchToPage(apppage.UserDataList)
+			},
+		},
+	)
+
+	conflictView := conflict.New(
+		conflict.Callbacks{
+			OnList: func() ([]entity.Conflict, error) {
+				return conflictHandler.GetList(ctx)
+			},
+			OnResolve: func(c entity.Conflict, r entity.Resolution) error {
+				err := conflictHandler.Resolve(ctx, c, r)
+				if err != nil {
+					logger.Debug("conflict resolve failed", "error", err.Error())
+				}
+				return err
+			},
+			OnBack: func() {
+				appPages.SwitchToPage(apppage.UserDataList)
+			},
+		},
+	)
+
 	appPages.AddPage(apppage.Auth, loginView, true, true)
 	appPages.AddPage(apppage.UserDataList, userDataView, true, false)
+	appPages.AddPage(apppage.Vault, vaultView, true, false)
+	appPages.AddPage(apppage.Devices, devicesView, true, false)
+	appPages.AddPage(apppage.SecondFactor, secondFactorView, true, false)
+	appPages.AddPage(apppage.Account, accountView, true, false)
+	appPages.AddPage(apppage.Conflicts, conflictView, true, false)
 
 	go func() {
 		for {
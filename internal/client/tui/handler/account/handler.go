@@ -0,0 +1,47 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrPasswordMismatch новый пароль и повтор не совпадают.
+var ErrPasswordMismatch = errors.New("new passwords do not match")
+
+// Service сервис аккаунта.
+type Service interface {
+	ChangePassword(ctx context.Context, current, next string) (uint32, error)
+	DeleteAccount(ctx context.Context, password string) (time.Time, error)
+	CancelDeletion(ctx context.Context) error
+}
+
+// Handler обработчик аккаунта.
+type Handler struct {
+	srv Service
+}
+
+// ChangePassword меняет пароль. Возвращает количество устройств, на которых выполнен выход.
+func (h *Handler) ChangePassword(ctx context.Context, current, next, repeat string) (uint32, error) {
+	if next != repeat {
+		return 0, ErrPasswordMismatch
+	}
+	return h.srv.ChangePassword(ctx, current, next)
+}
+
+// DeleteAccount запрашивает удаление аккаунта.
+func (h *Handler) DeleteAccount(ctx context.Context, password string) (time.Time, error) {
+	return h.srv.DeleteAccount(ctx, password)
+}
+
+// CancelDeletion отменяет удаление аккаунта.
+func (h *Handler) CancelDeletion(ctx context.Context) error {
+	return h.srv.CancelDeletion(ctx)
+}
+
+// New конструктор.
+func New(srv Service) *Handler {
+	return &Handler{
+		srv: srv,
+	}
+}
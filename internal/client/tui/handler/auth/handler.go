@@ -11,6 +11,7 @@ import (
 type Service interface {
 	Login(ctx context.Context, data entity.Credentials) error
 	Register(ctx context.Context, data entity.Credentials) error
+	VerifySecondFactor(ctx context.Context, code string) error
 }
 
 // SyncService сервис синхронизации данных.
@@ -19,20 +20,40 @@ type SyncService interface {
 	SyncFromRemote(ctx context.Context) error
 }
 
+// VaultService сервис ключей хранилища.
+type VaultService interface {
+	Unlock(ctx context.Context, masterPassword string) error
+}
+
 // Handler обработчик аутентификации.
 type Handler struct {
-	srv     Service
-	syncSrv SyncService
+	srv      Service
+	syncSrv  SyncService
+	vaultSrv VaultService
 }
 
 // SignIn авторизует пользователя.
+// Если включен второй фактор, возвращает [entity.ErrSecondFactorRequired], вход завершается [Handler.VerifySecondFactor].
 func (h *Handler) SignIn(ctx context.Context, l entity.Credentials) error {
-	var err error
+	if err := h.srv.Login(ctx, l); err != nil {
+		return err
+	}
+	return h.unlock(ctx, l)
+}
+
+// VerifySecondFactor завершает вход кодом второго фактора.
+func (h *Handler) VerifySecondFactor(ctx context.Context, l entity.Credentials, code string) error {
+	if err := h.srv.VerifySecondFactor(ctx, code); err != nil {
+		return err
+	}
+	return h.unlock(ctx, l)
+}
 
-	if err = h.srv.Login(ctx, l); err != nil {
+func (h *Handler) unlock(ctx context.Context, l entity.Credentials) error {
+	if err := h.vaultSrv.Unlock(ctx, l.MasterPassword); err != nil {
 		return err
 	}
-	if _, err = h.syncSrv.Initialize(ctx); err != nil {
+	if _, err := h.syncSrv.Initialize(ctx); err != nil {
 		return err
 	}
 	return nil
@@ -49,9 +70,10 @@ func (h *Handler) SignUp(ctx context.Context, l entity.Credentials) error {
 }
 
 // New конструктор.
-func New(srv Service, syncSrv SyncService) *Handler {
+func New(srv Service, syncSrv SyncService, vaultSrv VaultService) *Handler {
 	return &Handler{
-		srv:     srv,
-		syncSrv: syncSrv,
+		srv:      srv,
+		syncSrv:  syncSrv,
+		vaultSrv: vaultSrv,
 	}
 }
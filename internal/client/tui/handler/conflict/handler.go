@@ -0,0 +1,35 @@
+package conflict
+
+import (
+	"context"
+
+	ce "github.com/ktigay/goph-keeper/internal/client/entity"
+)
+
+// Service сервис конфликтов синхронизации.
+type Service interface {
+	Conflicts(ctx context.Context) ([]ce.Conflict, error)
+	Resolve(ctx context.Context, uuid string, r ce.Resolution) error
+}
+
+// Handler обработчик конфликтов синхронизации.
+type Handler struct {
+	srv Service
+}
+
+// GetList возвращает неразрешенные конфликты.
+func (h *Handler) GetList(ctx context.Context) ([]ce.Conflict, error) {
+	return h.srv.Conflicts(ctx)
+}
+
+// Resolve разрешает конфликт записи.
+func (h *Handler) Resolve(ctx context.Context, c ce.Conflict, r ce.Resolution) error {
+	return h.srv.Resolve(ctx, c.Local.UUID, r)
+}
+
+// New конструктор.
+func New(srv Service) *Handler {
+	return &Handler{
+		srv: srv,
+	}
+}
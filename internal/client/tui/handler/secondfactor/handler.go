@@ -0,0 +1,42 @@
+package secondfactor
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// ErrEmptyCode не введен код.
+var ErrEmptyCode = errors.New("code is required")
+
+// Service сервис второго фактора.
+type Service interface {
+	Enroll(ctx context.Context) (string, []string, error)
+	Confirm(ctx context.Context, code string) error
+}
+
+// Handler обработчик второго фактора.
+type Handler struct {
+	srv Service
+}
+
+// Enroll начинает подключение второго фактора.
+func (h *Handler) Enroll(ctx context.Context) (string, []string, error) {
+	return h.srv.Enroll(ctx)
+}
+
+// Confirm включает второй фактор.
+func (h *Handler) Confirm(ctx context.Context, code string) error {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return ErrEmptyCode
+	}
+	return h.srv.Confirm(ctx, code)
+}
+
+// New конструктор.
+func New(srv Service) *Handler {
+	return &Handler{
+		srv: srv,
+	}
+}
@@ -0,0 +1,48 @@
+package session
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ktigay/goph-keeper/internal/entity"
+)
+
+// ErrCurrentSession попытка отозвать текущую сессию.
+var ErrCurrentSession = errors.New("current session can't be revoked, use Quit instead")
+
+// Service сервис сессий.
+type Service interface {
+	List(ctx context.Context) ([]entity.SessionInfo, error)
+	Revoke(ctx context.Context, uuid string) error
+	RevokeAllOther(ctx context.Context) (uint32, error)
+}
+
+// Handler обработчик сессий (устройств).
+type Handler struct {
+	srv Service
+}
+
+// GetList возвращает список сессий.
+func (h *Handler) GetList(ctx context.Context) ([]entity.SessionInfo, error) {
+	return h.srv.List(ctx)
+}
+
+// Revoke отзывает сессию другого устройства.
+func (h *Handler) Revoke(ctx context.Context, s entity.SessionInfo) error {
+	if s.Current {
+		return ErrCurrentSession
+	}
+	return h.srv.Revoke(ctx, s.UUID)
+}
+
+// RevokeAllOther отзывает сессии всех устройств, кроме текущего.
+func (h *Handler) RevokeAllOther(ctx context.Context) (uint32, error) {
+	return h.srv.RevokeAllOther(ctx)
+}
+
+// New конструктор.
+func New(srv Service) *Handler {
+	return &Handler{
+		srv: srv,
+	}
+}
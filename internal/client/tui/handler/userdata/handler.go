@@ -2,6 +2,7 @@ package userdata
 
 import (
 	"context"
+	"time"
 
 	"github.com/ktigay/goph-keeper/internal/entity"
 )
@@ -12,11 +13,19 @@ type Service interface {
 	Update(ctx context.Context, data entity.UserData) (*entity.UserData, error)
 	Delete(ctx context.Context, uuids ...string) error
 	Read(ctx context.Context, uuids ...string) ([]entity.UserData, error)
+	GenerateCode(ctx context.Context, uuid string, t time.Time) (string, error)
+}
+
+// HistoryService сервис предыдущих версий записей.
+type HistoryService interface {
+	Revisions(ctx context.Context, itemUUID string) ([]entity.UserDataRevision, error)
+	Restore(ctx context.Context, itemUUID, revisionUUID string) (*entity.UserData, error)
 }
 
 // Handler обработчик пользовательских данных.
 type Handler struct {
-	srv Service
+	srv     Service
+	history HistoryService
 }
 
 // GetList возвращает список данных.
@@ -51,9 +60,25 @@ func (h *Handler) ItemDelete(ctx context.Context, uuids ...string) error {
 	return h.srv.Delete(ctx, uuids...)
 }
 
+// ItemCode возвращает текущий одноразовый код записи.
+func (h *Handler) ItemCode(ctx context.Context, uuid string, t time.Time) (string, error) {
+	return h.srv.GenerateCode(ctx, uuid, t)
+}
+
+// ItemRevisions возвращает предыдущие версии записи.
+func (h *Handler) ItemRevisions(ctx context.Context, uuid string) ([]entity.UserDataRevision, error) {
+	return h.history.Revisions(ctx, uuid)
+}
+
+// ItemRestore восстанавливает версию записи.
+func (h *Handler) ItemRestore(ctx context.Context, uuid, revisionUUID string) (*entity.UserData, error) {
+	return h.history.Restore(ctx, uuid, revisionUUID)
+}
+
 // New конструктор.
-func New(s Service) *Handler {
+func New(s Service, h HistoryService) *Handler {
 	return &Handler{
-		srv: s,
+		srv:     s,
+		history: h,
 	}
 }
@@ -0,0 +1,38 @@
+package vault
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrPasswordMismatch новый мастер-пароль и повтор не совпадают.
+var ErrPasswordMismatch = errors.New("new master passwords do not match")
+
+// Service сервис ключей хранилища.
+type Service interface {
+	Rotate(ctx context.Context, masterPassword, newMasterPassword string) error
+}
+
+// Handler обработчик ключей хранилища.
+type Handler struct {
+	srv Service
+}
+
+// ChangeMasterPassword меняет мастер-пароль и ключ хранилища.
+// Если новый мастер-пароль не указан, меняется только ключ.
+func (h *Handler) ChangeMasterPassword(ctx context.Context, current, next, repeat string) error {
+	if next == "" && repeat == "" {
+		next, repeat = current, current
+	}
+	if next != repeat {
+		return ErrPasswordMismatch
+	}
+	return h.srv.Rotate(ctx, current, next)
+}
+
+// New конструктор.
+func New(srv Service) *Handler {
+	return &Handler{
+		srv: srv,
+	}
+}
@@ -0,0 +1,149 @@
+package account
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const timeLayout = "02.01.2006 15:04"
+
+// Callbacks callback события.
+type Callbacks struct {
+	// OnChangePassword меняет пароль, возвращает количество устройств, на которых выполнен выход.
+	OnChangePassword func(current, next, repeat string) (uint32, error)
+	// OnDelete запрашивает удаление аккаунта, возвращает время удаления.
+	OnDelete func(password string) (time.Time, error)
+	// OnCancelDelete отменяет удаление аккаунта.
+	OnCancelDelete func() error
+	OnBack         func()
+}
+
+// Page страница смены пароля и удаления аккаунта.
+type Page struct {
+	callbacks Callbacks
+	cmp       *tview.Grid
+}
+
+// Component компонент страницы.
+func (p *Page) Component() tview.Primitive {
+	return p.cmp
+}
+
+// Render рендер.
+func (p *Page) Render() tview.Primitive {
+	p.cmp.Clear()
+
+	bx := tview.NewBox()
+
+	var current, next, repeat, deletePassword string
+
+	currentLabel := tview.NewTextView().SetText("Password:")
+	currentField := tview.NewInputField().SetMaskCharacter('*')
+	currentField.SetChangedFunc(func(text string) {
+		current = text
+	})
+
+	nextLabel := tview.NewTextView().SetText("New password:")
+	nextField := tview.NewInputField().SetMaskCharacter('*')
+	nextField.SetChangedFunc(func(text string) {
+		next = text
+	})
+
+	repeatLabel := tview.NewTextView().SetText("Repeat:")
+	repeatField := tview.NewInputField().SetMaskCharacter('*')
+	repeatField.SetChangedFunc(func(text string) {
+		repeat = text
+	})
+
+	deleteLabel := tview.NewTextView().SetText("Password:")
+	deleteField := tview.NewInputField().SetMaskCharacter('*')
+	deleteField.SetChangedFunc(func(text string) {
+		deletePassword = text
+	})
+
+	noticeTxt := tview.NewTextView().SetTextAlign(tview.AlignCenter)
+
+	change := tview.NewButton("Change password")
+	change.SetSelectedFunc(func() {
+		defer change.Blur()
+
+		revoked, err := p.callbacks.OnChangePassword(current, next, repeat)
+		if err != nil {
+			noticeTxt.SetTextColor(tcell.ColorRed).SetText(fmt.Errorf("change failed: %w", err).Error())
+			return
+		}
+		currentField.SetText("")
+		nextField.SetText("")
+		repeatField.SetText("")
+		noticeTxt.
+			SetTextColor(tcell.ColorGreen).
+			SetText(fmt.Sprintf("password changed, %d other device(s) signed out", revoked))
+	})
+
+	del := tview.NewButton("Delete account")
+	del.SetSelectedFunc(func() {
+		defer del.Blur()
+
+		deleteAfter, err := p.callbacks.OnDelete(deletePassword)
+		if err != nil {
+			noticeTxt.SetTextColor(tcell.ColorRed).SetText(fmt.Errorf("delete failed: %w", err).Error())
+			return
+		}
+		deleteField.SetText("")
+		noticeTxt.
+			SetTextColor(tcell.ColorYellow).
+			SetText(fmt.Sprintf("account and all data will be deleted after %s", deleteAfter.Local().Format(timeLayout)))
+	})
+
+	cancelDel := tview.NewButton("Cancel deletion")
+	cancelDel.SetSelectedFunc(func() {
+		defer cancelDel.Blur()
+
+		if err := p.callbacks.OnCancelDelete(); err != nil {
+			noticeTxt.SetTextColor(tcell.ColorRed).SetText(fmt.Errorf("cancel failed: %w", err).Error())
+			return
+		}
+		noticeTxt.SetTextColor(tcell.ColorGreen).SetText("account deletion cancelled")
+	})
+
+	back := tview.NewButton("Back")
+	back.SetSelectedFunc(func() {
+		p.callbacks.OnBack()
+		back.Blur()
+	})
+
+	p.cmp.
+		SetColumns(-1, 16, 26, -1).
+		SetRows(-1, 2, 2, 2, 3, 2, 3, 3, 3, -1).
+		AddItem(bx, 0, 0, 4, 1, 0, 0, false). // Left - 4 rows
+		AddItem(bx, 0, 1, 1, 1, 0, 0, false). // Top - 1 row
+		AddItem(bx, 0, 3, 4, 1, 0, 0, false). // Right - 4 rows
+		AddItem(currentLabel, 1, 1, 1, 1, 0, 0, false).
+		AddItem(currentField, 1, 2, 1, 1, 0, 0, false).
+		AddItem(nextLabel, 2, 1, 1, 1, 0, 0, false).
+		AddItem(nextField, 2, 2, 1, 1, 0, 0, false).
+		AddItem(repeatLabel, 3, 1, 1, 1, 0, 0, false).
+		AddItem(repeatField, 3, 2, 1, 1, 0, 0, false).
+		AddItem(change, 4, 2, 1, 1, 1, 0, false).
+		AddItem(deleteLabel, 5, 1, 1, 1, 0, 0, false).
+		AddItem(deleteField, 5, 2, 1, 1, 0, 0, false).
+		AddItem(del, 6, 1, 1, 1, 1, 0, false).
+		AddItem(cancelDel, 6, 2, 1, 1, 1, 0, false).
+		AddItem(noticeTxt, 7, 1, 1, 2, 0, 0, false).
+		AddItem(back, 8, 2, 1, 1, 1, 0, false)
+
+	p.cmp.SetGap(0, 1)
+
+	return p.cmp
+}
+
+// New конструктор.
+func New(c Callbacks) *Page {
+	return &Page{
+		callbacks: c,
+		cmp:       tview.NewGrid(),
+	}
+}
@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ktigay/goph-keeper/internal/client/entity"
+)
+
+const (
+	langEN = "en"
+	langRU = "ru"
+)
+
+// messages понятные пользователю сообщения об ошибках аутентификации.
+var messages = map[string]map[error]string{
+	langEN: {
+		entity.ErrEmptyCredentials:        "Enter login and password.",
+		entity.ErrInvalidCredentials:      "Wrong login or password.",
+		entity.ErrLoginExists:             "This login is already taken.",
+		entity.ErrInvalidSecondFactorCode: "Wrong code, try again.",
+		entity.ErrServerUnavailable:       "Server is unavailable, try again later.",
+		entity.ErrLegacyLoginDisabled:     "Password login is disabled on the server, contact the administrator.",
+		entity.ErrHandshakeExpired:        "Login took too long, try again.",
+		entity.ErrServerProof:             "Server could not confirm your password, it may be an impostor.",
+	},
+	langRU: {
+		entity.ErrEmptyCredentials:        "Введите логин и пароль.",
+		entity.ErrInvalidCredentials:      "Неверный логин или пароль.",
+		entity.ErrLoginExists:             "Этот логин уже занят.",
+		entity.ErrInvalidSecondFactorCode: "Неверный код, попробуйте еще раз.",
+		entity.ErrServerUnavailable:       "Сервер недоступен, попробуйте позже.",
+		entity.ErrLegacyLoginDisabled:     "Вход по паролю на сервере отключен, обратитесь к администратору.",
+		entity.ErrHandshakeExpired:        "Вход занял слишком много времени, попробуйте еще раз.",
+		entity.ErrServerProof:             "Сервер не подтвердил пароль, возможно, это подмена.",
+	},
+}
+
+// tooManyAttempts сообщение о блокировке входа, %s - время до повтора.
+var tooManyAttempts = map[string]string{
+	langEN: "Too many attempts, try again in %s.",
+	langRU: "Слишком много попыток, повторите через %s.",
+}
+
+// localize возвращает сообщение об ошибке на языке пользователя.
+// Для неизвестных ошибок возвращает fallback с текстом ошибки.
+func localize(lang string, err error, fallback string) string {
+	var locked *entity.TooManyAttemptsError
+	if errors.As(err, &locked) {
+		return fmt.Sprintf(tooManyAttempts[lang], locked.RetryAfter.Round(time.Second))
+	}
+
+	for target, msg := range messages[lang] {
+		if errors.Is(err, target) {
+			return msg
+		}
+	}
+	return fmt.Errorf("%s: %w", fallback, err).Error()
+}
+
+// detectLanguage язык интерфейса из переменных окружения локали.
+func detectLanguage() string {
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		v := os.Getenv(env)
+		if v == "" {
+			continue
+		}
+		if strings.HasPrefix(strings.ToLower(v), langRU) {
+			return langRU
+		}
+		return langEN
+	}
+	return langEN
+}
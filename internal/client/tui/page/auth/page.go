@@ -1,7 +1,7 @@
 package auth
 
 import (
-	"fmt"
+	"errors"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
@@ -13,12 +13,15 @@ import (
 type Callbacks struct {
 	OnSignIn func(entity.Credentials) error
 	OnSignUp func(entity.Credentials) error
+	// OnSecondFactor завершает вход кодом второго фактора.
+	OnSecondFactor func(c entity.Credentials, code string) error
 }
 
 // Page структура страницы.
 type Page struct {
 	callbacks Callbacks
 	cmp       *tview.Grid
+	lang      string
 }
 
 // Component компонент страницы.
@@ -45,28 +48,69 @@ func (l *Page) Render() tview.Primitive {
 		e.Password = text
 	})
 
+	masterLabel := tview.NewTextView().SetText("Master password:")
+	master := tview.NewInputField().SetMaskCharacter('*')
+	master.SetChangedFunc(func(text string) {
+		e.MasterPassword = text
+	})
+
+	var code string
+	codeLabel := tview.NewTextView().SetText("Code:")
+	codeField := tview.NewInputField()
+	codeField.SetChangedFunc(func(text string) {
+		code = text
+	})
+
 	noticeTxt := tview.NewTextView().SetTextAlign(tview.AlignCenter)
 
 	// style := tcell.Style{}.Background(tcell.ColorNone)
 
+	var codeRequired bool
+	signUp := tview.NewButton("Sign-up")
 	signIn := tview.NewButton("Sign-in")
 	// signIn.SetStyle(style).SetActivatedStyle(style)
 	signIn.SetSelectedFunc(func() {
+		defer signIn.Blur()
+
+		if codeRequired {
+			if err := l.callbacks.OnSecondFactor(e, code); err != nil {
+				noticeTxt.
+					SetTextColor(tcell.ColorRed).
+					SetText(localize(l.lang, err, "verification failed"))
+			}
+			return
+		}
+
 		err := l.callbacks.OnSignIn(e)
+		if errors.Is(err, entity.ErrSecondFactorRequired) {
+			codeRequired = true
+			signIn.SetLabel("Verify")
+			l.cmp.
+				SetRows(-1, 2, 2, 2, 2, 3, 3, -1).
+				RemoveItem(noticeTxt).
+				RemoveItem(signIn).
+				RemoveItem(signUp).
+				AddItem(codeLabel, 4, 1, 1, 1, 0, 0, false).
+				AddItem(codeField, 4, 2, 1, 1, 0, 0, false).
+				AddItem(noticeTxt, 5, 1, 1, 2, 0, 0, false).
+				AddItem(signIn, 6, 1, 1, 1, 1, 0, false)
+			noticeTxt.
+				SetTextColor(tcell.ColorYellow).
+				SetText("enter authenticator or recovery code")
+			return
+		}
 		if err != nil {
 			noticeTxt.
 				SetTextColor(tcell.ColorRed).
-				SetText(fmt.Errorf("sign-in failed: %w", err).Error())
+				SetText(localize(l.lang, err, "sign-in failed"))
 		}
-		signIn.Blur()
 	})
 
-	signUp := tview.NewButton("Sign-up")
 	// signUp.SetStyle(style).SetActivatedStyle(style)
 	signUp.SetSelectedFunc(func() {
 		err := l.callbacks.OnSignUp(e)
 		if err != nil {
-			noticeTxt.SetTextColor(tcell.ColorRed).SetText(fmt.Errorf("sign-up failed: %w", err).Error())
+			noticeTxt.SetTextColor(tcell.ColorRed).SetText(localize(l.lang, err, "sign-up failed"))
 			return
 		}
 		noticeTxt.
@@ -78,18 +122,20 @@ func (l *Page) Render() tview.Primitive {
 	// Create Grid containing the application's widgets
 	appGrid := l.cmp.
 		SetColumns(-1, 16, 26, -1).
-		SetRows(-1, 2, 2, 3, 3, -1).
-		AddItem(bx, 0, 0, 3, 1, 0, 0, false). // Left - 3 rows
+		SetRows(-1, 2, 2, 2, 3, 3, -1).
+		AddItem(bx, 0, 0, 4, 1, 0, 0, false). // Left - 4 rows
 		AddItem(bx, 0, 1, 1, 1, 0, 0, false). // Top - 1 row
-		AddItem(bx, 0, 3, 3, 1, 0, 0, false). // Right - 3 rows
-		AddItem(bx, 4, 1, 1, 1, 0, 0, false). // Bottom - 1 row
+		AddItem(bx, 0, 3, 4, 1, 0, 0, false). // Right - 4 rows
+		AddItem(bx, 5, 1, 1, 1, 0, 0, false). // Bottom - 1 row
 		AddItem(loginLabel, 1, 1, 1, 1, 0, 0, false).
 		AddItem(login, 1, 2, 1, 1, 0, 0, false).
 		AddItem(passLabel, 2, 1, 1, 1, 0, 0, false).
 		AddItem(pass, 2, 2, 1, 1, 0, 0, false).
-		AddItem(noticeTxt, 3, 1, 1, 2, 0, 0, false).
-		AddItem(signIn, 4, 1, 1, 1, 1, 0, false).
-		AddItem(signUp, 4, 2, 1, 1, 1, 0, false)
+		AddItem(masterLabel, 3, 1, 1, 1, 0, 0, false).
+		AddItem(master, 3, 2, 1, 1, 0, 0, false).
+		AddItem(noticeTxt, 4, 1, 1, 2, 0, 0, false).
+		AddItem(signIn, 5, 1, 1, 1, 1, 0, false).
+		AddItem(signUp, 5, 2, 1, 1, 1, 0, false)
 
 	appGrid.SetGap(0, 1)
 
@@ -101,5 +147,6 @@ func New(c Callbacks) *Page {
 	return &Page{
 		callbacks: c,
 		cmp:       tview.NewGrid(),
+		lang:      detectLanguage(),
 	}
 }
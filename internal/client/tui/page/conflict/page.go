@@ -0,0 +1,200 @@
+package conflict
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	ce "github.com/ktigay/goph-keeper/internal/client/entity"
+	"github.com/ktigay/goph-keeper/internal/client/tui/page/itemdiff"
+)
+
+// Callbacks callback события.
+type Callbacks struct {
+	// OnList возвращает неразрешенные конфликты синхронизации.
+	OnList func() ([]ce.Conflict, error)
+	// OnResolve разрешает конфликт выбранным способом.
+	OnResolve func(c ce.Conflict, r ce.Resolution) error
+	OnBack    func()
+}
+
+// Page страница конфликтов синхронизации: локальная версия и версия сервера рядом.
+type Page struct {
+	callbacks Callbacks
+	cmp       *tview.Flex
+	list      *tview.List
+	local     *tview.TextView
+	remote    *tview.TextView
+	notice    *tview.TextView
+	conflicts []ce.Conflict
+}
+
+// Component компонент страницы.
+func (p *Page) Component() tview.Primitive {
+	return p.cmp
+}
+
+// Render рендер.
+func (p *Page) Render() tview.Primitive {
+	p.renderList()
+	return p.cmp
+}
+
+func (p *Page) renderList() {
+	p.list.Clear()
+	p.local.Clear()
+	p.remote.Clear()
+
+	conflicts, err := p.callbacks.OnList()
+	if err != nil {
+		p.conflicts = nil
+		p.setNotice(tcell.ColorRed, fmt.Errorf("load conflicts failed: %w", err).Error())
+		return
+	}
+	p.conflicts = conflicts
+
+	if len(conflicts) == 0 {
+		p.local.SetText("no conflicts")
+		return
+	}
+	for _, c := range conflicts {
+		title := c.Local.Title
+		if c.Remote == nil {
+			title += " (deleted on server)"
+		}
+		p.list.AddItem(title, "", 0, nil)
+	}
+	p.renderConflict(0)
+}
+
+// renderConflict показывает локальную версию и версию сервера; строки, отличающиеся
+// от другой стороны, выделяются, а измененные относительно базовой версии помечаются "*".
+func (p *Page) renderConflict(idx int) {
+	if idx < 0 || idx >= len(p.conflicts) {
+		return
+	}
+	c := p.conflicts[idx]
+
+	var base []string
+	if c.Base != nil {
+		base = itemdiff.ItemLines(*c.Base)
+	}
+	local := itemdiff.ItemLines(c.Local)
+	if c.Remote == nil {
+		p.local.SetText(sideText(itemdiff.Lines(nil, local), itemdiff.Added, base)).ScrollToBeginning()
+		p.remote.SetText("[red]deleted on the server[-]").ScrollToBeginning()
+		return
+	}
+	remote := itemdiff.ItemLines(*c.Remote)
+
+	diff := itemdiff.Lines(remote, local)
+	p.local.SetText(sideText(diff, itemdiff.Added, base)).ScrollToBeginning()
+	p.remote.SetText(sideText(diff, itemdiff.Removed, base)).ScrollToBeginning()
+}
+
+// sideText строки одной стороны сравнения: общие и отмеченные op.
+func sideText(diff []itemdiff.Line, op itemdiff.Op, base []string) string {
+	var b strings.Builder
+	for _, l := range diff {
+		if l.Op != itemdiff.Same && l.Op != op {
+			continue
+		}
+		marker := "  "
+		if base != nil && !slices.Contains(base, l.Text) {
+			marker = "* "
+		}
+		if l.Op == op && op != itemdiff.Same {
+			b.WriteString("[yellow]" + marker + tview.Escape(l.Text) + "[-]\n")
+			continue
+		}
+		b.WriteString(marker + tview.Escape(l.Text) + "\n")
+	}
+	return b.String()
+}
+
+func (p *Page) resolve(r ce.Resolution) {
+	idx := p.list.GetCurrentItem()
+	if idx < 0 || idx >= len(p.conflicts) {
+		return
+	}
+	if err := p.callbacks.OnResolve(p.conflicts[idx], r); err != nil {
+		var mergeErr *ce.MergeConflictError
+		if errors.As(err, &mergeErr) {
+			p.setNotice(tcell.ColorYellow, fmt.Sprintf("can't merge, choose a version: %s", strings.Join(mergeErr.Fields, ", ")))
+			return
+		}
+		p.setNotice(tcell.ColorRed, fmt.Errorf("resolve failed: %w", err).Error())
+		return
+	}
+	p.setNotice(tcell.ColorGreen, "conflict resolved")
+	p.renderList()
+}
+
+func (p *Page) setNotice(color tcell.Color, text string) {
+	p.notice.SetTextColor(color).SetText(text)
+}
+
+// New конструктор.
+func New(c Callbacks) *Page {
+	p := &Page{
+		callbacks: c,
+		cmp:       tview.NewFlex().SetDirection(tview.FlexRow),
+		list:      tview.NewList().ShowSecondaryText(false),
+		local:     tview.NewTextView().SetDynamicColors(true).SetScrollable(true),
+		remote:    tview.NewTextView().SetDynamicColors(true).SetScrollable(true),
+		notice:    tview.NewTextView().SetTextAlign(tview.AlignCenter),
+	}
+	p.list.SetBorder(true).SetTitle("Conflicts")
+	p.local.SetBorder(true).SetTitle("Local")
+	p.remote.SetBorder(true).SetTitle("Server")
+	p.list.SetChangedFunc(func(i int, _, _ string, _ rune) {
+		p.renderConflict(i)
+	})
+
+	buttons := tview.NewFlex().SetDirection(tview.FlexColumn)
+	for i, b := range []struct {
+		label string
+		r     ce.Resolution
+	}{
+		{"Keep local", ce.ResolutionLocal},
+		{"Keep server", ce.ResolutionRemote},
+		{"Keep both", ce.ResolutionBoth},
+		{"Merge", ce.ResolutionMerge},
+	} {
+		btn := tview.NewButton(b.label)
+		btn.SetSelectedFunc(func() {
+			defer btn.Blur()
+			p.resolve(b.r)
+		})
+		if i > 0 {
+			buttons.AddItem(tview.NewBox(), 1, 1, false)
+		}
+		buttons.AddItem(btn, 20, 1, false)
+	}
+
+	backBtn := tview.NewButton("Back")
+	backBtn.SetSelectedFunc(func() {
+		p.setNotice(tcell.ColorWhite, "")
+		p.callbacks.OnBack()
+		backBtn.Blur()
+	})
+	buttons.
+		AddItem(tview.NewBox(), 1, 1, false).
+		AddItem(backBtn, 20, 1, false)
+
+	p.cmp.
+		AddItem(
+			tview.NewFlex().SetDirection(tview.FlexColumn).
+				AddItem(p.list, 0, 1, true).
+				AddItem(p.local, 0, 2, false).
+				AddItem(p.remote, 0, 2, false),
+			0, 1, true).
+		AddItem(p.notice, 1, 1, false).
+		AddItem(buttons, 1, 1, false)
+
+	return p
+}
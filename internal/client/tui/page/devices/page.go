@@ -0,0 +1,140 @@
+package devices
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/ktigay/goph-keeper/internal/entity"
+)
+
+const timeLayout = "02.01.2006 15:04"
+
+// Callbacks callback события.
+type Callbacks struct {
+	// OnList возвращает сессии (устройства) пользователя.
+	OnList func() ([]entity.SessionInfo, error)
+	// OnRevoke отзывает сессию устройства.
+	OnRevoke func(s entity.SessionInfo) error
+	// OnRevokeAllOther отзывает сессии всех устройств, кроме текущего.
+	OnRevokeAllOther func() (uint32, error)
+	OnBack           func()
+}
+
+// Page страница устройств, с которых выполнен вход.
+type Page struct {
+	callbacks Callbacks
+	cmp       *tview.Flex
+	list      *tview.List
+	notice    *tview.TextView
+	sessions  []entity.SessionInfo
+}
+
+// Component компонент страницы.
+func (p *Page) Component() tview.Primitive {
+	return p.cmp
+}
+
+// Render рендер.
+func (p *Page) Render() tview.Primitive {
+	p.renderList()
+	return p.cmp
+}
+
+func (p *Page) renderList() {
+	p.list.Clear()
+
+	sessions, err := p.callbacks.OnList()
+	if err != nil {
+		p.sessions = nil
+		p.setNotice(tcell.ColorRed, fmt.Errorf("load devices failed: %w", err).Error())
+		return
+	}
+	p.sessions = sessions
+
+	for _, s := range sessions {
+		title := s.DeviceName
+		if title == "" {
+			title = "unknown device"
+		}
+		if s.Current {
+			title += " (this device)"
+		}
+		p.list.AddItem(title, fmt.Sprintf("version: %s, ip: %s, last seen: %s",
+			s.ClientVersion, s.IP, s.LastSeenAt.Local().Format(timeLayout)), 0, nil)
+	}
+}
+
+func (p *Page) setNotice(color tcell.Color, text string) {
+	p.notice.SetTextColor(color).SetText(text)
+}
+
+// New конструктор.
+func New(c Callbacks) *Page {
+	p := &Page{
+		callbacks: c,
+		cmp:       tview.NewFlex().SetDirection(tview.FlexRow),
+		list:      tview.NewList(),
+		notice:    tview.NewTextView().SetTextAlign(tview.AlignCenter),
+	}
+	p.list.SetBorder(true).SetTitle("Devices")
+
+	revokeBtn := tview.NewButton("Revoke")
+	revokeBtn.SetSelectedFunc(func() {
+		defer revokeBtn.Blur()
+
+		idx := p.list.GetCurrentItem()
+		if idx < 0 || idx >= len(p.sessions) {
+			return
+		}
+		if err := p.callbacks.OnRevoke(p.sessions[idx]); err != nil {
+			p.setNotice(tcell.ColorRed, fmt.Errorf("revoke failed: %w", err).Error())
+			return
+		}
+		p.setNotice(tcell.ColorGreen, "device signed out")
+		p.renderList()
+	})
+
+	revokeAllBtn := tview.NewButton("Revoke all other")
+	revokeAllBtn.SetSelectedFunc(func() {
+		defer revokeAllBtn.Blur()
+
+		n, err := p.callbacks.OnRevokeAllOther()
+		if err != nil {
+			p.setNotice(tcell.ColorRed, fmt.Errorf("revoke failed: %w", err).Error())
+			return
+		}
+		p.setNotice(tcell.ColorGreen, fmt.Sprintf("%d device(s) signed out", n))
+		p.renderList()
+	})
+
+	refreshBtn := tview.NewButton("Refresh")
+	refreshBtn.SetSelectedFunc(func() {
+		p.setNotice(tcell.ColorWhite, "")
+		p.renderList()
+		refreshBtn.Blur()
+	})
+
+	backBtn := tview.NewButton("Back")
+	backBtn.SetSelectedFunc(func() {
+		p.callbacks.OnBack()
+		backBtn.Blur()
+	})
+
+	p.cmp.
+		AddItem(p.list, 0, 1, true).
+		AddItem(p.notice, 1, 1, false).
+		AddItem(
+			tview.NewFlex().SetDirection(tview.FlexColumn).
+				AddItem(revokeBtn, 20, 1, false).
+				AddItem(tview.NewBox(), 1, 1, false).
+				AddItem(revokeAllBtn, 20, 1, false).
+				AddItem(tview.NewBox(), 1, 1, false).
+				AddItem(refreshBtn, 20, 1, false).
+				AddItem(tview.NewBox(), 1, 1, false).
+				AddItem(backBtn, 20, 1, false),
+			1, 1, false)
+
+	return p
+}
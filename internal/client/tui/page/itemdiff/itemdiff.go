@@ -0,0 +1,107 @@
+package itemdiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ktigay/goph-keeper/internal/entity"
+)
+
+// Op операция строки сравнения.
+type Op int
+
+const (
+	// Same строка есть в обеих версиях.
+	Same Op = iota
+	// Removed строка есть только в первой версии.
+	Removed
+	// Added строка есть только во второй версии.
+	Added
+)
+
+// Line строка сравнения.
+type Line struct {
+	Op   Op
+	Text string
+}
+
+// ItemLines представляет запись строками "поле: значение" для построчного сравнения.
+func ItemLines(d entity.UserData) []string {
+	lines := []string{
+		"Title: " + d.Title,
+		"Type: " + string(d.Type),
+	}
+
+	switch d.Type {
+	case entity.DataTypeText:
+		for _, l := range strings.Split(string(d.Data), "\n") {
+			lines = append(lines, "Data: "+l)
+		}
+	case entity.DataTypeBinary:
+		lines = append(lines, "Data (base64): "+fmt.Sprint(d.GetData()))
+	default:
+		// Структурированные данные сравниваются по полям.
+		var fields map[string]any
+		if err := json.Unmarshal(d.Data, &fields); err != nil {
+			lines = append(lines, "Data: "+string(d.Data))
+			break
+		}
+		keys := make([]string, 0, len(fields))
+		for k := range fields {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			lines = append(lines, fmt.Sprintf("Data %s: %v", k, fields[k]))
+		}
+	}
+
+	for _, m := range d.MetaData {
+		lines = append(lines, fmt.Sprintf("Metadata %s: %s", m.Title, m.Value))
+	}
+	return lines
+}
+
+// Lines построчное сравнение по наибольшей общей подпоследовательности.
+func Lines(a, b []string) []Line {
+	// lcs[i][j] длина общей подпоследовательности a[i:] и b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	lines := make([]Line, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, Line{Op: Same, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, Line{Op: Removed, Text: a[i]})
+			i++
+		default:
+			lines = append(lines, Line{Op: Added, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, Line{Op: Removed, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, Line{Op: Added, Text: b[j]})
+	}
+	return lines
+}
@@ -7,6 +7,16 @@ const (
 	Auth = "Auth"
 	// UserDataList страница пользовательских данных.
 	UserDataList = "UserDataList"
+	// Vault страница смены мастер-пароля.
+	Vault = "Vault"
+	// Devices страница устройств (сессий) пользователя.
+	Devices = "Devices"
+	// SecondFactor страница двухфакторной аутентификации.
+	SecondFactor = "SecondFactor"
+	// Account страница смены пароля и удаления аккаунта.
+	Account = "Account"
+	// Conflicts страница конфликтов синхронизации.
+	Conflicts = "Conflicts"
 )
 
 // Page страница.
@@ -15,28 +25,47 @@ type Page interface {
 	Render() tview.Primitive
 }
 
+// Closer страница с фоновой работой, которая идёт, только пока страница открыта.
+type Closer interface {
+	Open()
+	Close()
+}
+
 // Pages страницы.
 type Pages struct {
 	TviewPages *tview.Pages
 	pages      map[string]Page
+	current    string
 }
 
 // AddPage добавляет страницу.
 func (p *Pages) AddPage(key string, page Page, resize, visible bool) {
 	p.TviewPages.AddPage(key, page.Component(), resize, visible)
+	p.pages[key] = page
 	if visible {
-		page.Render()
+		p.open(key)
 	}
-	p.pages[key] = page
 }
 
-// SwitchToPage переключает страницы.
+// SwitchToPage переключает страницы. Открытая до этого страница закрывается.
 func (p *Pages) SwitchToPage(page string) {
-	e, ok := p.pages[page]
-	if ok {
-		e.Render()
-		p.TviewPages.SwitchToPage(page)
+	if _, ok := p.pages[page]; !ok {
+		return
+	}
+	if c, ok := p.pages[p.current].(Closer); ok && p.current != page {
+		c.Close()
+	}
+	p.open(page)
+	p.TviewPages.SwitchToPage(page)
+}
+
+func (p *Pages) open(key string) {
+	e := p.pages[key]
+	if c, ok := e.(Closer); ok {
+		c.Open()
 	}
+	e.Render()
+	p.current = key
 }
 
 // NewPages конструктор.
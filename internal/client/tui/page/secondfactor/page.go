@@ -0,0 +1,104 @@
+package secondfactor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Callbacks callback события.
+type Callbacks struct {
+	// OnEnroll начинает подключение, возвращает otpauth:// URI и коды восстановления.
+	OnEnroll func() (string, []string, error)
+	// OnConfirm включает второй фактор кодом из приложения-аутентификатора.
+	OnConfirm func(code string) error
+	OnBack    func()
+}
+
+// Page страница подключения второго фактора.
+type Page struct {
+	callbacks Callbacks
+	cmp       *tview.Flex
+	info      *tview.TextView
+	code      *tview.InputField
+	notice    *tview.TextView
+}
+
+// Component компонент страницы.
+func (p *Page) Component() tview.Primitive {
+	return p.cmp
+}
+
+// Render рендер.
+func (p *Page) Render() tview.Primitive {
+	p.info.SetText("Press \"Enable\" to set up an authenticator app.")
+	p.code.SetText("")
+	p.setNotice(tcell.ColorWhite, "")
+	return p.cmp
+}
+
+func (p *Page) setNotice(color tcell.Color, text string) {
+	p.notice.SetTextColor(color).SetText(text)
+}
+
+// New конструктор.
+func New(c Callbacks) *Page {
+	p := &Page{
+		callbacks: c,
+		cmp:       tview.NewFlex().SetDirection(tview.FlexRow),
+		info:      tview.NewTextView().SetWrap(true),
+		code:      tview.NewInputField().SetLabel("Code: ").SetFieldWidth(12),
+		notice:    tview.NewTextView().SetTextAlign(tview.AlignCenter),
+	}
+	p.info.SetBorder(true).SetTitle("Two-factor authentication")
+
+	enableBtn := tview.NewButton("Enable")
+	enableBtn.SetSelectedFunc(func() {
+		defer enableBtn.Blur()
+
+		uri, codes, err := p.callbacks.OnEnroll()
+		if err != nil {
+			p.setNotice(tcell.ColorRed, fmt.Errorf("enroll failed: %w", err).Error())
+			return
+		}
+		p.info.SetText(fmt.Sprintf(
+			"Add this key to your authenticator app:\n%s\n\nRecovery codes (save them, each works once):\n%s\n\nThen enter the current code and press \"Confirm\".",
+			uri, strings.Join(codes, "\n")))
+		p.setNotice(tcell.ColorWhite, "")
+	})
+
+	confirmBtn := tview.NewButton("Confirm")
+	confirmBtn.SetSelectedFunc(func() {
+		defer confirmBtn.Blur()
+
+		if err := p.callbacks.OnConfirm(p.code.GetText()); err != nil {
+			p.setNotice(tcell.ColorRed, fmt.Errorf("confirm failed: %w", err).Error())
+			return
+		}
+		p.code.SetText("")
+		p.setNotice(tcell.ColorGreen, "two-factor authentication enabled")
+	})
+
+	backBtn := tview.NewButton("Back")
+	backBtn.SetSelectedFunc(func() {
+		p.callbacks.OnBack()
+		backBtn.Blur()
+	})
+
+	p.cmp.
+		AddItem(p.info, 0, 1, false).
+		AddItem(p.code, 1, 1, true).
+		AddItem(p.notice, 1, 1, false).
+		AddItem(
+			tview.NewFlex().SetDirection(tview.FlexColumn).
+				AddItem(enableBtn, 20, 1, false).
+				AddItem(tview.NewBox(), 1, 1, false).
+				AddItem(confirmBtn, 20, 1, false).
+				AddItem(tview.NewBox(), 1, 1, false).
+				AddItem(backBtn, 20, 1, false),
+			1, 1, false)
+
+	return p
+}
@@ -1,9 +1,16 @@
 package userdatalist
 
 import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/rivo/tview"
 
 	"github.com/ktigay/goph-keeper/internal/entity"
+	"github.com/ktigay/goph-keeper/internal/otp"
 )
 
 // ComponentData компонент полей данных.
@@ -11,6 +18,11 @@ type ComponentData interface {
 	AddToForm(form *tview.Form)
 }
 
+// Refresher компонент, который нужно периодически обновлять.
+type Refresher interface {
+	Refresh(now time.Time)
+}
+
 // StringComponent текстовый компонент  [entity.DataTypeText].
 type StringComponent struct {
 	data        string
@@ -127,8 +139,250 @@ func NewCardComponent(data entity.UserDataCard, fieldWidth int, changed func(any
 	}
 }
 
+// CredentialComponent компонент для [entity.DataTypeCredential].
+type CredentialComponent struct {
+	data        entity.UserDataCredential
+	fieldWidth  int
+	fieldHeight int
+	changed     func(any)
+}
+
+// AddToForm добавляет компонент в форму.
+func (c *CredentialComponent) AddToForm(form *tview.Form) {
+	var (
+		username, password *tview.InputField
+		urls, notes        *tview.TextArea
+	)
+
+	username = tview.NewInputField()
+	password = tview.NewInputField()
+	urls = tview.NewTextArea()
+	notes = tview.NewTextArea()
+
+	changed := func() {
+		c.data.Username = username.GetText()
+		c.data.Password = password.GetText()
+		c.data.URLs = splitLines(urls.GetText())
+		c.data.Notes = notes.GetText()
+		c.changed(c.data)
+	}
+
+	username.
+		SetLabel("Username").
+		SetFieldWidth(c.fieldWidth).
+		SetText(c.data.Username).
+		SetChangedFunc(func(_ string) { changed() })
+	form.AddFormItem(username)
+
+	password.
+		SetLabel("Password").
+		SetFieldWidth(c.fieldWidth).
+		SetMaskCharacter('*').
+		SetText(c.data.Password).
+		SetChangedFunc(func(_ string) { changed() })
+	form.AddFormItem(password)
+
+	urls.
+		SetLabel("URLs (one per line)").
+		SetSize(3, c.fieldWidth).
+		SetText(strings.Join(c.data.URLs, "\n"), false).
+		SetChangedFunc(changed)
+	form.AddFormItem(urls)
+
+	notes.
+		SetLabel("Notes").
+		SetSize(c.fieldHeight, c.fieldWidth).
+		SetText(c.data.Notes, false).
+		SetChangedFunc(changed)
+	form.AddFormItem(notes)
+}
+
+// NewCredentialComponent конструктор.
+func NewCredentialComponent(data entity.UserDataCredential, fieldWidth, fieldHeight int, changed func(any)) *CredentialComponent {
+	return &CredentialComponent{
+		data:        data,
+		fieldWidth:  fieldWidth,
+		fieldHeight: fieldHeight,
+		changed:     changed,
+	}
+}
+
+func splitLines(text string) []string {
+	lines := make([]string, 0)
+	for _, l := range strings.Split(text, "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	return lines
+}
+
+// OTPComponent компонент для [entity.DataTypeOTP].
+type OTPComponent struct {
+	data       entity.UserDataOTP
+	fieldWidth int
+	changed    func(any)
+	code       func(time.Time) (string, error)
+	codeView   *tview.TextView
+}
+
+// AddToForm добавляет компонент в форму.
+func (o *OTPComponent) AddToForm(form *tview.Form) {
+	var (
+		uri, secret, issuer, account, digits, period, counter *tview.InputField
+		typ, algorithm                                        *tview.DropDown
+	)
+
+	types := []string{string(otp.TypeTOTP), string(otp.TypeHOTP)}
+	algorithms := []string{string(otp.AlgorithmSHA1), string(otp.AlgorithmSHA256), string(otp.AlgorithmSHA512)}
+
+	if o.data.Type == "" {
+		o.data.Type = string(otp.TypeTOTP)
+		o.data.Algorithm = string(otp.AlgorithmSHA1)
+		o.data.Digits = otp.DefaultDigits
+		o.data.Period = otp.DefaultPeriod
+	}
+
+	uri = tview.NewInputField()
+	secret = tview.NewInputField()
+	issuer = tview.NewInputField()
+	account = tview.NewInputField()
+	digits = tview.NewInputField()
+	period = tview.NewInputField()
+	counter = tview.NewInputField()
+	typ = tview.NewDropDown()
+	algorithm = tview.NewDropDown()
+	o.codeView = tview.NewTextView()
+
+	ready := false
+	changed := func() {
+		if !ready {
+			return
+		}
+		_, o.data.Type = typ.GetCurrentOption()
+		_, o.data.Algorithm = algorithm.GetCurrentOption()
+		o.data.Secret = strings.ToUpper(strings.TrimSpace(secret.GetText()))
+		o.data.Issuer = issuer.GetText()
+		o.data.Account = account.GetText()
+		o.data.Digits, _ = strconv.Atoi(digits.GetText())
+		o.data.Period, _ = strconv.Atoi(period.GetText())
+		o.data.Counter, _ = strconv.ParseUint(counter.GetText(), 10, 64)
+		o.changed(o.data)
+	}
+	onText := func(_ string) { changed() }
+
+	uri.
+		SetLabel("Import otpauth:// URI").
+		SetFieldWidth(o.fieldWidth).
+		SetChangedFunc(func(text string) {
+			k, err := otp.ParseURI(text)
+			if err != nil {
+				return
+			}
+			ready = false
+			typ.SetCurrentOption(slices.Index(types, string(k.Type)))
+			algorithm.SetCurrentOption(slices.Index(algorithms, string(k.Algorithm)))
+			secret.SetText(k.Secret)
+			issuer.SetText(k.Issuer)
+			account.SetText(k.Account)
+			digits.SetText(strconv.Itoa(k.Digits))
+			period.SetText(strconv.Itoa(k.Period))
+			counter.SetText(strconv.FormatUint(k.Counter, 10))
+			ready = true
+			changed()
+		})
+	form.AddFormItem(uri)
+
+	typ.
+		SetLabel("OTP Type").
+		SetOptions(types, func(_ string, _ int) { changed() }).
+		SetCurrentOption(max(slices.Index(types, o.data.Type), 0))
+	form.AddFormItem(typ)
+
+	secret.
+		SetLabel("Secret (base32)").
+		SetFieldWidth(o.fieldWidth).
+		SetMaskCharacter('*').
+		SetText(o.data.Secret).
+		SetChangedFunc(onText)
+	form.AddFormItem(secret)
+
+	issuer.
+		SetLabel("Issuer").
+		SetFieldWidth(o.fieldWidth).
+		SetText(o.data.Issuer).
+		SetChangedFunc(onText)
+	form.AddFormItem(issuer)
+
+	account.
+		SetLabel("Account").
+		SetFieldWidth(o.fieldWidth).
+		SetText(o.data.Account).
+		SetChangedFunc(onText)
+	form.AddFormItem(account)
+
+	algorithm.
+		SetLabel("Algorithm").
+		SetOptions(algorithms, func(_ string, _ int) { changed() }).
+		SetCurrentOption(max(slices.Index(algorithms, o.data.Algorithm), 0))
+	form.AddFormItem(algorithm)
+
+	for _, f := range []struct {
+		field *tview.InputField
+		label string
+		value string
+	}{
+		{digits, "Digits", strconv.Itoa(o.data.Digits)},
+		{period, "Period (sec)", strconv.Itoa(o.data.Period)},
+		{counter, "Counter", strconv.FormatUint(o.data.Counter, 10)},
+	} {
+		f.field.
+			SetLabel(f.label).
+			SetFieldWidth(12).
+			SetAcceptanceFunc(tview.InputFieldInteger).
+			SetText(f.value).
+			SetChangedFunc(onText)
+		form.AddFormItem(f.field)
+	}
+
+	o.codeView.SetLabel("Current code").SetSize(1, 0)
+	form.AddFormItem(o.codeView)
+
+	ready = true
+	o.Refresh(time.Now())
+}
+
+// Refresh обновляет текущий код.
+func (o *OTPComponent) Refresh(now time.Time) {
+	if o.codeView == nil || o.code == nil {
+		return
+	}
+
+	code, err := o.code(now)
+	if err != nil {
+		o.codeView.SetText("save the record to see the code")
+		return
+	}
+
+	if o.data.Type == string(otp.TypeHOTP) {
+		o.codeView.SetText(fmt.Sprintf("%s (counter %d)", code, o.data.Counter))
+		return
+	}
+	o.codeView.SetText(fmt.Sprintf("%s (%ds left)", code, int(otp.Remaining(now, o.data.Period).Seconds())))
+}
+
+// NewOTPComponent конструктор.
+func NewOTPComponent(data entity.UserDataOTP, fieldWidth int, changed func(any), code func(time.Time) (string, error)) *OTPComponent {
+	return &OTPComponent{
+		data:       data,
+		fieldWidth: fieldWidth,
+		changed:    changed,
+		code:       code,
+	}
+}
+
 // ComponentDataFactory фабрика компонентов.
-func ComponentDataFactory(data *entity.UserData, fieldWidth, fieldHeight, maxLength int, changed func(any)) ComponentData {
+func ComponentDataFactory(data *entity.UserData, fieldWidth, fieldHeight, maxLength int, changed func(any), code func(time.Time) (string, error)) ComponentData {
 	switch data.Type {
 	case entity.DataTypeText:
 		return NewStringComponent(data.GetData().(string), fieldWidth, fieldHeight, maxLength, changed)
@@ -136,6 +390,10 @@ func ComponentDataFactory(data *entity.UserData, fieldWidth, fieldHeight, maxLen
 		return NewBinaryComponent(data.GetData().(string), fieldWidth, fieldHeight, maxLength, changed)
 	case entity.DataTypeCard:
 		return NewCardComponent(data.GetData().(entity.UserDataCard), fieldWidth, changed)
+	case entity.DataTypeCredential:
+		return NewCredentialComponent(data.GetData().(entity.UserDataCredential), fieldWidth, fieldHeight, changed)
+	case entity.DataTypeOTP:
+		return NewOTPComponent(data.GetData().(entity.UserDataOTP), fieldWidth, changed, code)
 	}
 	return nil
 }
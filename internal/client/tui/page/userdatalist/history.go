@@ -0,0 +1,120 @@
+package userdatalist
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/ktigay/goph-keeper/internal/client/tui/page/itemdiff"
+	"github.com/ktigay/goph-keeper/internal/entity"
+)
+
+// historyView просмотр предыдущих версий записи: сравнение двух версий и восстановление.
+type historyView struct {
+	cmp      *tview.Flex
+	form     *tview.Form
+	diff     *tview.TextView
+	notice   *tview.TextView
+	versions []historyVersion
+	from     int
+	to       int
+}
+
+// historyVersion версия записи в списке сравнения; revisionUUID пустой у текущей версии.
+type historyVersion struct {
+	label        string
+	revisionUUID string
+	item         entity.UserData
+	unavailable  bool
+}
+
+// newHistoryView создает просмотр истории записи current. restore восстанавливает версию, back закрывает просмотр.
+func newHistoryView(current entity.UserData, revisions []entity.UserDataRevision, restore func(revisionUUID string) error, back func()) *historyView {
+	h := &historyView{
+		cmp:    tview.NewFlex().SetDirection(tview.FlexRow),
+		form:   tview.NewForm(),
+		diff:   tview.NewTextView().SetDynamicColors(true).SetScrollable(true),
+		notice: tview.NewTextView(),
+	}
+	h.form.SetBorder(true).SetTitle("History: " + current.Title).SetTitleAlign(tview.AlignCenter)
+	h.diff.SetBorder(true).SetTitle("Changes")
+
+	h.versions = append(h.versions, historyVersion{label: "current", item: current})
+	for _, rev := range revisions {
+		label := rev.ArchivedAt.In(time.Local).Format(time.RFC822)
+		if rev.Deleted {
+			label += " (deleted)"
+		}
+		if rev.Unavailable {
+			label += fmt.Sprintf(" (unavailable, key v%d)", rev.Item.KeyVersion)
+		}
+		h.versions = append(h.versions, historyVersion{
+			label:        fmt.Sprintf("v%d, %s", rev.Item.Version, label),
+			revisionUUID: rev.UUID,
+			item:         rev.Item,
+			unavailable:  rev.Unavailable,
+		})
+	}
+
+	labels := make([]string, 0, len(h.versions))
+	for _, v := range h.versions {
+		labels = append(labels, v.label)
+	}
+	// По умолчанию сравнивается последняя предыдущая версия с текущей.
+	h.from = min(1, len(h.versions)-1)
+	h.form.AddDropDown("From", labels, h.from, func(_ string, i int) {
+		h.from = i
+		h.renderDiff()
+	})
+	h.form.AddDropDown("To", labels, h.to, func(_ string, i int) {
+		h.to = i
+		h.renderDiff()
+	})
+	h.form.AddFormItem(h.notice)
+	h.form.AddButton("Restore \"From\"", func() {
+		v := h.versions[h.from]
+		if v.revisionUUID == "" {
+			h.notice.SetTextColor(tcell.ColorYellow).SetText("current version is selected")
+			return
+		}
+		if v.unavailable {
+			h.notice.SetTextColor(tcell.ColorYellow).SetText("version is encrypted with a retired vault key")
+			return
+		}
+		if err := restore(v.revisionUUID); err != nil {
+			h.notice.SetTextColor(tcell.ColorRed).SetText(err.Error())
+		}
+	})
+	h.form.AddButton("Back", back)
+
+	h.cmp.
+		AddItem(h.form, 9, 1, true).
+		AddItem(h.diff, 0, 1, false)
+	h.renderDiff()
+
+	return h
+}
+
+// renderDiff показывает изменения от версии "From" к версии "To".
+func (h *historyView) renderDiff() {
+	if len(h.versions) < 2 {
+		h.diff.SetText("no previous versions")
+		return
+	}
+
+	var b strings.Builder
+	for _, l := range itemdiff.Lines(itemdiff.ItemLines(h.versions[h.from].item), itemdiff.ItemLines(h.versions[h.to].item)) {
+		switch l.Op {
+		case itemdiff.Removed:
+			b.WriteString("[red]- " + tview.Escape(l.Text) + "[-]\n")
+		case itemdiff.Added:
+			b.WriteString("[green]+ " + tview.Escape(l.Text) + "[-]\n")
+		default:
+			b.WriteString("  " + tview.Escape(l.Text) + "\n")
+		}
+	}
+	h.diff.SetText(b.String()).ScrollToBeginning()
+}
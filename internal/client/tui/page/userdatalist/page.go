@@ -1,6 +1,9 @@
 package userdatalist
 
 import (
+	"context"
+	"fmt"
+	"slices"
 	"time"
 
 	"github.com/rivo/tview"
@@ -15,11 +18,34 @@ type Callbacks struct {
 	OnItemDelete  func(entity.UserData) error
 	OnRefreshData func()
 	OnQuit        func()
+	// OnMasterPassword открывает страницу смены мастер-пароля.
+	OnMasterPassword func()
+	// OnDevices открывает страницу устройств.
+	OnDevices func()
+	// OnSecondFactor открывает страницу двухфакторной аутентификации.
+	OnSecondFactor func()
+	// OnAccount открывает страницу смены пароля и удаления аккаунта.
+	OnAccount func()
+	// OnConflicts открывает страницу конфликтов синхронизации.
+	OnConflicts func()
+	// OnConflictCount возвращает количество неразрешенных конфликтов синхронизации.
+	OnConflictCount func() int
+	// OnItemRevisions возвращает предыдущие версии записи.
+	OnItemRevisions func(uuid string) ([]entity.UserDataRevision, error)
+	// OnItemRestore восстанавливает версию записи.
+	OnItemRestore func(uuid, revisionUUID string) error
+	// OnGenerateCode генерирует одноразовый код записи.
+	OnGenerateCode func(uuid string, t time.Time) (string, error)
+	// QueueUpdateDraw выполняет обновление в потоке приложения и перерисовывает экран.
+	QueueUpdateDraw func(func())
 }
 
 // Page структура страницы пользовательских данных.
 type Page struct {
 	cmp          *tview.Flex
+	body         *tview.Flex
+	formFlex     *tview.Flex
+	history      tview.Primitive
 	callbacks    Callbacks
 	dataSourceFn func() ([]entity.UserData, error)
 	userData     []*entity.UserData
@@ -27,7 +53,23 @@ type Page struct {
 	metaForm     *tview.Form
 	list         *tview.List
 	notice       *tview.TextView
+	conflictsBtn *tview.Button
 	activeIdx    int
+	// ctx контекст приложения, обновление компонентов прекращается с его отменой.
+	ctx         context.Context
+	closed      bool
+	stopRefresh context.CancelFunc
+}
+
+// Open открывает страницу.
+func (u *Page) Open() {
+	u.closed = false
+}
+
+// Close закрывает страницу и останавливает обновление компонента записи.
+func (u *Page) Close() {
+	u.closed = true
+	u.cancelRefresh()
 }
 
 // RefreshData обновляет данные.
@@ -52,6 +94,7 @@ func (u *Page) Render() tview.Primitive {
 
 	changed := func(i int, text, secondary string, r rune) {
 		u.activeIdx = i
+		u.hideHistory()
 		u.renderForm(u.saveBtnChanged, u.delBtnChanged)
 	}
 	list.SetSelectedFunc(changed)
@@ -59,10 +102,20 @@ func (u *Page) Render() tview.Primitive {
 
 	u.renderMetaForm()
 	u.renderList()
+	u.renderConflictsBtn()
 
 	return u.cmp
 }
 
+// renderConflictsBtn показывает количество неразрешенных конфликтов на кнопке.
+func (u *Page) renderConflictsBtn() {
+	label := "Conflicts"
+	if n := u.callbacks.OnConflictCount(); n > 0 {
+		label = fmt.Sprintf("Conflicts (%d)", n)
+	}
+	u.conflictsBtn.SetLabel(label)
+}
+
 func (u *Page) renderList() *tview.List {
 	list := u.list
 	list.Clear()
@@ -91,21 +144,12 @@ func (u *Page) renderForm(save, delete func(userData entity.UserData)) *tview.Fo
 		SetSize(2, 0)
 	form.AddFormItem(u.notice)
 
-	form.AddDropDown("Type", []string{
-		string(entity.DataTypeText),
-		string(entity.DataTypeBinary),
-		string(entity.DataTypeCard),
-	},
-		func() int {
-			switch data.Type {
-			case entity.DataTypeBinary:
-				return 1
-			case entity.DataTypeCard:
-				return 2
-			default:
-				return 0
-			}
-		}(),
+	types := make([]string, 0, len(entity.DataTypes))
+	for _, t := range entity.DataTypes {
+		types = append(types, string(t))
+	}
+	form.AddDropDown("Type", types,
+		max(slices.Index(entity.DataTypes, data.Type), 0),
 		func(option string, optionIndex int) {
 			if string(data.Type) != option {
 				data.Type = entity.UserDataType(option)
@@ -115,8 +159,11 @@ func (u *Page) renderForm(save, delete func(userData entity.UserData)) *tview.Fo
 
 	dataCmp := ComponentDataFactory(data, 80, 10, 0, func(d any) {
 		_ = data.SetData(d)
+	}, func(t time.Time) (string, error) {
+		return u.callbacks.OnGenerateCode(data.UUID, t)
 	})
 	dataCmp.AddToForm(form)
+	u.startRefresh(dataCmp)
 
 	if !data.UpdatedAt.IsZero() {
 		form.AddTextView("Created At", data.CreatedAt.In(time.Local).Format(time.RFC822), 40, 1, false, false)
@@ -160,10 +207,87 @@ func (u *Page) renderForm(save, delete func(userData entity.UserData)) *tview.Fo
 			delete(*data)
 		})
 	}
+	if !data.IsNew && u.callbacks.OnItemRevisions != nil {
+		form.AddButton("History", func() {
+			u.showHistory(*data)
+		})
+	}
 
 	return form
 }
 
+// showHistory показывает вместо формы предыдущие версии записи.
+func (u *Page) showHistory(data entity.UserData) {
+	revisions, err := u.callbacks.OnItemRevisions(data.UUID)
+	if err != nil {
+		u.notice.SetText(err.Error())
+		return
+	}
+
+	h := newHistoryView(data, revisions, func(revisionUUID string) error {
+		if err := u.callbacks.OnItemRestore(data.UUID, revisionUUID); err != nil {
+			return err
+		}
+		u.hideHistory()
+		u.RefreshData()
+		u.renderList().SetCurrentItem(0)
+		return nil
+	}, u.hideHistory)
+
+	u.hideHistory()
+	u.body.RemoveItem(u.formFlex)
+	u.history = h.cmp
+	u.body.AddItem(h.cmp, 0, 4, false)
+}
+
+// hideHistory возвращает форму записи вместо истории.
+func (u *Page) hideHistory() {
+	if u.history == nil {
+		return
+	}
+	u.body.RemoveItem(u.history)
+	u.history = nil
+	u.body.AddItem(u.formFlex, 0, 4, false)
+}
+
+// startRefresh периодически обновляет компонент, если он это поддерживает, пока страница открыта.
+func (u *Page) startRefresh(cmp ComponentData) {
+	u.cancelRefresh()
+
+	r, ok := cmp.(Refresher)
+	if !ok || u.closed || u.callbacks.QueueUpdateDraw == nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(u.ctx)
+	u.stopRefresh = cancel
+
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case now := <-ticker.C:
+				u.callbacks.QueueUpdateDraw(func() {
+					if ctx.Err() == nil {
+						r.Refresh(now)
+					}
+				})
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (u *Page) cancelRefresh() {
+	if u.stopRefresh != nil {
+		u.stopRefresh()
+		u.stopRefresh = nil
+	}
+}
+
 func (u *Page) renderMetaForm() *tview.Form {
 	form := u.form
 	metaForm := u.metaForm
@@ -233,7 +357,7 @@ func (u *Page) delBtnChanged(data entity.UserData) {
 }
 
 // New конструктор.
-func New(c Callbacks, dataSource func() ([]entity.UserData, error)) *Page {
+func New(ctx context.Context, c Callbacks, dataSource func() ([]entity.UserData, error)) *Page {
 	cmp := tview.NewFlex()
 	cmp.SetDirection(tview.FlexRow)
 
@@ -257,11 +381,16 @@ func New(c Callbacks, dataSource func() ([]entity.UserData, error)) *Page {
 
 	page := Page{
 		cmp:          cmp,
+		body:         flex,
+		formFlex:     formFlex,
 		callbacks:    c,
 		dataSourceFn: dataSource,
 		form:         form,
 		metaForm:     metaForm,
 		list:         list,
+		ctx:          ctx,
+		// Страница открывается при переключении на неё.
+		closed: true,
 	}
 
 	addBtn := tview.NewButton("New record")
@@ -285,6 +414,36 @@ func New(c Callbacks, dataSource func() ([]entity.UserData, error)) *Page {
 		refreshBtn.Blur()
 	})
 
+	vaultBtn := tview.NewButton("Master password")
+	vaultBtn.SetSelectedFunc(func() {
+		page.callbacks.OnMasterPassword()
+		vaultBtn.Blur()
+	})
+
+	devicesBtn := tview.NewButton("Devices")
+	devicesBtn.SetSelectedFunc(func() {
+		page.callbacks.OnDevices()
+		devicesBtn.Blur()
+	})
+
+	secondFactorBtn := tview.NewButton("Two-factor")
+	secondFactorBtn.SetSelectedFunc(func() {
+		page.callbacks.OnSecondFactor()
+		secondFactorBtn.Blur()
+	})
+
+	accountBtn := tview.NewButton("Account")
+	accountBtn.SetSelectedFunc(func() {
+		page.callbacks.OnAccount()
+		accountBtn.Blur()
+	})
+
+	page.conflictsBtn = tview.NewButton("Conflicts")
+	page.conflictsBtn.SetSelectedFunc(func() {
+		page.callbacks.OnConflicts()
+		page.conflictsBtn.Blur()
+	})
+
 	quitBtn := tview.NewButton("Quit")
 	quitBtn.SetSelectedFunc(func() {
 		page.callbacks.OnQuit()
@@ -297,6 +456,16 @@ func New(c Callbacks, dataSource func() ([]entity.UserData, error)) *Page {
 			AddItem(tview.NewBox(), 1, 1, false).
 			AddItem(refreshBtn, 20, 1, false).
 			AddItem(tview.NewBox(), 1, 1, false).
+			AddItem(vaultBtn, 20, 1, false).
+			AddItem(tview.NewBox(), 1, 1, false).
+			AddItem(devicesBtn, 20, 1, false).
+			AddItem(tview.NewBox(), 1, 1, false).
+			AddItem(secondFactorBtn, 20, 1, false).
+			AddItem(tview.NewBox(), 1, 1, false).
+			AddItem(accountBtn, 20, 1, false).
+			AddItem(tview.NewBox(), 1, 1, false).
+			AddItem(page.conflictsBtn, 20, 1, false).
+			AddItem(tview.NewBox(), 1, 1, false).
 			AddItem(quitBtn, 20, 1, false),
 
 		1, 1, false)
@@ -0,0 +1,111 @@
+package vault
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Callbacks callback события.
+type Callbacks struct {
+	// OnChange меняет мастер-пароль и ключ хранилища.
+	OnChange func(current, next, repeat string) error
+	OnBack   func()
+}
+
+// Page страница смены мастер-пароля и ротации ключа хранилища.
+type Page struct {
+	callbacks Callbacks
+	cmp       *tview.Grid
+}
+
+// Component компонент страницы.
+func (p *Page) Component() tview.Primitive {
+	return p.cmp
+}
+
+// Render рендер.
+func (p *Page) Render() tview.Primitive {
+	p.cmp.Clear()
+
+	bx := tview.NewBox()
+
+	var current, next, repeat string
+
+	currentLabel := tview.NewTextView().SetText("Master password:")
+	currentField := tview.NewInputField().SetMaskCharacter('*')
+	currentField.SetChangedFunc(func(text string) {
+		current = text
+	})
+
+	nextLabel := tview.NewTextView().SetText("New password:")
+	nextField := tview.NewInputField().SetMaskCharacter('*')
+	nextField.SetChangedFunc(func(text string) {
+		next = text
+	})
+
+	repeatLabel := tview.NewTextView().SetText("Repeat:")
+	repeatField := tview.NewInputField().SetMaskCharacter('*')
+	repeatField.SetChangedFunc(func(text string) {
+		repeat = text
+	})
+
+	noticeTxt := tview.NewTextView().
+		SetTextAlign(tview.AlignCenter).
+		SetText("leave new password empty to rotate the key only")
+
+	change := tview.NewButton("Change")
+	change.SetSelectedFunc(func() {
+		noticeTxt.SetTextColor(tcell.ColorYellow).SetText("re-encrypting data...")
+		if err := p.callbacks.OnChange(current, next, repeat); err != nil {
+			noticeTxt.
+				SetTextColor(tcell.ColorRed).
+				SetText(fmt.Errorf("change failed: %w", err).Error())
+			change.Blur()
+			return
+		}
+		currentField.SetText("")
+		nextField.SetText("")
+		repeatField.SetText("")
+		noticeTxt.
+			SetTextColor(tcell.ColorGreen).
+			SetText("vault key rotated")
+		change.Blur()
+	})
+
+	back := tview.NewButton("Back")
+	back.SetSelectedFunc(func() {
+		p.callbacks.OnBack()
+		back.Blur()
+	})
+
+	p.cmp.
+		SetColumns(-1, 16, 26, -1).
+		SetRows(-1, 2, 2, 2, 3, 3, -1).
+		AddItem(bx, 0, 0, 4, 1, 0, 0, false). // Left - 4 rows
+		AddItem(bx, 0, 1, 1, 1, 0, 0, false). // Top - 1 row
+		AddItem(bx, 0, 3, 4, 1, 0, 0, false). // Right - 4 rows
+		AddItem(bx, 5, 1, 1, 1, 0, 0, false). // Bottom - 1 row
+		AddItem(currentLabel, 1, 1, 1, 1, 0, 0, false).
+		AddItem(currentField, 1, 2, 1, 1, 0, 0, false).
+		AddItem(nextLabel, 2, 1, 1, 1, 0, 0, false).
+		AddItem(nextField, 2, 2, 1, 1, 0, 0, false).
+		AddItem(repeatLabel, 3, 1, 1, 1, 0, 0, false).
+		AddItem(repeatField, 3, 2, 1, 1, 0, 0, false).
+		AddItem(noticeTxt, 4, 1, 1, 2, 0, 0, false).
+		AddItem(change, 5, 1, 1, 1, 1, 0, false).
+		AddItem(back, 5, 2, 1, 1, 1, 0, false)
+
+	p.cmp.SetGap(0, 1)
+
+	return p.cmp
+}
+
+// New конструктор.
+func New(c Callbacks) *Page {
+	return &Page{
+		callbacks: c,
+		cmp:       tview.NewGrid(),
+	}
+}
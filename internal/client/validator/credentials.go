@@ -1,6 +1,7 @@
 package validator
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
@@ -14,7 +15,7 @@ func ValidateCredentials(data entity.Credentials) error {
 	data.Password = strings.TrimSpace(data.Password)
 	vd := validator.New()
 	if err := vd.Struct(data); err != nil {
-		return err
+		return fmt.Errorf("%w: %w", entity.ErrEmptyCredentials, err)
 	}
 	return nil
 }
@@ -9,6 +9,7 @@ package auth
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -121,6 +122,10 @@ type LoginRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Login         string                 `protobuf:"bytes,1,opt,name=login,proto3" json:"login,omitempty"`
 	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	DeviceName    string                 `protobuf:"bytes,3,opt,name=device_name,json=deviceName,proto3" json:"device_name,omitempty"`
+	ClientVersion string                 `protobuf:"bytes,4,opt,name=client_version,json=clientVersion,proto3" json:"client_version,omitempty"`
+	SrpSalt       []byte                 `protobuf:"bytes,5,opt,name=srp_salt,json=srpSalt,proto3" json:"srp_salt,omitempty"`
+	SrpVerifier   []byte                 `protobuf:"bytes,6,opt,name=srp_verifier,json=srpVerifier,proto3" json:"srp_verifier,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -169,11 +174,41 @@ func (x *LoginRequest) GetPassword() string {
 	return ""
 }
 
+func (x *LoginRequest) GetDeviceName() string {
+	if x != nil {
+		return x.DeviceName
+	}
+	return ""
+}
+
+func (x *LoginRequest) GetClientVersion() string {
+	if x != nil {
+		return x.ClientVersion
+	}
+	return ""
+}
+
+func (x *LoginRequest) GetSrpSalt() []byte {
+	if x != nil {
+		return x.SrpSalt
+	}
+	return nil
+}
+
+func (x *LoginRequest) GetSrpVerifier() []byte {
+	if x != nil {
+		return x.SrpVerifier
+	}
+	return nil
+}
+
 type LoginResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Token          string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	RefreshToken   string                 `protobuf:"bytes,2,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	ChallengeToken string                 `protobuf:"bytes,3,opt,name=challenge_token,json=challengeToken,proto3" json:"challenge_token,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *LoginResponse) Reset() {
@@ -213,24 +248,1148 @@ func (x *LoginResponse) GetToken() string {
 	return ""
 }
 
+func (x *LoginResponse) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+func (x *LoginResponse) GetChallengeToken() string {
+	if x != nil {
+		return x.ChallengeToken
+	}
+	return ""
+}
+
+type SrpRegisterRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Login         string                 `protobuf:"bytes,1,opt,name=login,proto3" json:"login,omitempty"`
+	Salt          []byte                 `protobuf:"bytes,2,opt,name=salt,proto3" json:"salt,omitempty"`
+	Verifier      []byte                 `protobuf:"bytes,3,opt,name=verifier,proto3" json:"verifier,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SrpRegisterRequest) Reset() {
+	*x = SrpRegisterRequest{}
+	mi := &file_contracts_auth_v1_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SrpRegisterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SrpRegisterRequest) ProtoMessage() {}
+
+func (x *SrpRegisterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_auth_v1_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SrpRegisterRequest.ProtoReflect.Descriptor instead.
+func (*SrpRegisterRequest) Descriptor() ([]byte, []int) {
+	return file_contracts_auth_v1_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SrpRegisterRequest) GetLogin() string {
+	if x != nil {
+		return x.Login
+	}
+	return ""
+}
+
+func (x *SrpRegisterRequest) GetSalt() []byte {
+	if x != nil {
+		return x.Salt
+	}
+	return nil
+}
+
+func (x *SrpRegisterRequest) GetVerifier() []byte {
+	if x != nil {
+		return x.Verifier
+	}
+	return nil
+}
+
+type SrpStartRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Login         string                 `protobuf:"bytes,1,opt,name=login,proto3" json:"login,omitempty"`
+	ClientPublic  []byte                 `protobuf:"bytes,2,opt,name=client_public,json=clientPublic,proto3" json:"client_public,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SrpStartRequest) Reset() {
+	*x = SrpStartRequest{}
+	mi := &file_contracts_auth_v1_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SrpStartRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SrpStartRequest) ProtoMessage() {}
+
+func (x *SrpStartRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_auth_v1_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SrpStartRequest.ProtoReflect.Descriptor instead.
+func (*SrpStartRequest) Descriptor() ([]byte, []int) {
+	return file_contracts_auth_v1_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SrpStartRequest) GetLogin() string {
+	if x != nil {
+		return x.Login
+	}
+	return ""
+}
+
+func (x *SrpStartRequest) GetClientPublic() []byte {
+	if x != nil {
+		return x.ClientPublic
+	}
+	return nil
+}
+
+type SrpStartResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	HandshakeId   string                 `protobuf:"bytes,1,opt,name=handshake_id,json=handshakeId,proto3" json:"handshake_id,omitempty"`
+	Salt          []byte                 `protobuf:"bytes,2,opt,name=salt,proto3" json:"salt,omitempty"`
+	ServerPublic  []byte                 `protobuf:"bytes,3,opt,name=server_public,json=serverPublic,proto3" json:"server_public,omitempty"`
+	Legacy        bool                   `protobuf:"varint,4,opt,name=legacy,proto3" json:"legacy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SrpStartResponse) Reset() {
+	*x = SrpStartResponse{}
+	mi := &file_contracts_auth_v1_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SrpStartResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SrpStartResponse) ProtoMessage() {}
+
+func (x *SrpStartResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_auth_v1_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SrpStartResponse.ProtoReflect.Descriptor instead.
+func (*SrpStartResponse) Descriptor() ([]byte, []int) {
+	return file_contracts_auth_v1_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SrpStartResponse) GetHandshakeId() string {
+	if x != nil {
+		return x.HandshakeId
+	}
+	return ""
+}
+
+func (x *SrpStartResponse) GetSalt() []byte {
+	if x != nil {
+		return x.Salt
+	}
+	return nil
+}
+
+func (x *SrpStartResponse) GetServerPublic() []byte {
+	if x != nil {
+		return x.ServerPublic
+	}
+	return nil
+}
+
+func (x *SrpStartResponse) GetLegacy() bool {
+	if x != nil {
+		return x.Legacy
+	}
+	return false
+}
+
+type SrpLoginRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	HandshakeId   string                 `protobuf:"bytes,1,opt,name=handshake_id,json=handshakeId,proto3" json:"handshake_id,omitempty"`
+	ClientProof   []byte                 `protobuf:"bytes,2,opt,name=client_proof,json=clientProof,proto3" json:"client_proof,omitempty"`
+	DeviceName    string                 `protobuf:"bytes,3,opt,name=device_name,json=deviceName,proto3" json:"device_name,omitempty"`
+	ClientVersion string                 `protobuf:"bytes,4,opt,name=client_version,json=clientVersion,proto3" json:"client_version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SrpLoginRequest) Reset() {
+	*x = SrpLoginRequest{}
+	mi := &file_contracts_auth_v1_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SrpLoginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SrpLoginRequest) ProtoMessage() {}
+
+func (x *SrpLoginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_auth_v1_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SrpLoginRequest.ProtoReflect.Descriptor instead.
+func (*SrpLoginRequest) Descriptor() ([]byte, []int) {
+	return file_contracts_auth_v1_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SrpLoginRequest) GetHandshakeId() string {
+	if x != nil {
+		return x.HandshakeId
+	}
+	return ""
+}
+
+func (x *SrpLoginRequest) GetClientProof() []byte {
+	if x != nil {
+		return x.ClientProof
+	}
+	return nil
+}
+
+func (x *SrpLoginRequest) GetDeviceName() string {
+	if x != nil {
+		return x.DeviceName
+	}
+	return ""
+}
+
+func (x *SrpLoginRequest) GetClientVersion() string {
+	if x != nil {
+		return x.ClientVersion
+	}
+	return ""
+}
+
+type SrpLoginResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ServerProof    []byte                 `protobuf:"bytes,1,opt,name=server_proof,json=serverProof,proto3" json:"server_proof,omitempty"`
+	Token          string                 `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	RefreshToken   string                 `protobuf:"bytes,3,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	ChallengeToken string                 `protobuf:"bytes,4,opt,name=challenge_token,json=challengeToken,proto3" json:"challenge_token,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SrpLoginResponse) Reset() {
+	*x = SrpLoginResponse{}
+	mi := &file_contracts_auth_v1_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SrpLoginResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SrpLoginResponse) ProtoMessage() {}
+
+func (x *SrpLoginResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_auth_v1_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SrpLoginResponse.ProtoReflect.Descriptor instead.
+func (*SrpLoginResponse) Descriptor() ([]byte, []int) {
+	return file_contracts_auth_v1_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SrpLoginResponse) GetServerProof() []byte {
+	if x != nil {
+		return x.ServerProof
+	}
+	return nil
+}
+
+func (x *SrpLoginResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *SrpLoginResponse) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+func (x *SrpLoginResponse) GetChallengeToken() string {
+	if x != nil {
+		return x.ChallengeToken
+	}
+	return ""
+}
+
+type SrpProofStartRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientPublic  []byte                 `protobuf:"bytes,1,opt,name=client_public,json=clientPublic,proto3" json:"client_public,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SrpProofStartRequest) Reset() {
+	*x = SrpProofStartRequest{}
+	mi := &file_contracts_auth_v1_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SrpProofStartRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SrpProofStartRequest) ProtoMessage() {}
+
+func (x *SrpProofStartRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_auth_v1_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SrpProofStartRequest.ProtoReflect.Descriptor instead.
+func (*SrpProofStartRequest) Descriptor() ([]byte, []int) {
+	return file_contracts_auth_v1_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SrpProofStartRequest) GetClientPublic() []byte {
+	if x != nil {
+		return x.ClientPublic
+	}
+	return nil
+}
+
+type VerifySecondFactorRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ChallengeToken string                 `protobuf:"bytes,1,opt,name=challenge_token,json=challengeToken,proto3" json:"challenge_token,omitempty"`
+	Code           string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *VerifySecondFactorRequest) Reset() {
+	*x = VerifySecondFactorRequest{}
+	mi := &file_contracts_auth_v1_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifySecondFactorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifySecondFactorRequest) ProtoMessage() {}
+
+func (x *VerifySecondFactorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_auth_v1_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifySecondFactorRequest.ProtoReflect.Descriptor instead.
+func (*VerifySecondFactorRequest) Descriptor() ([]byte, []int) {
+	return file_contracts_auth_v1_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *VerifySecondFactorRequest) GetChallengeToken() string {
+	if x != nil {
+		return x.ChallengeToken
+	}
+	return ""
+}
+
+func (x *VerifySecondFactorRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type VerifySecondFactorResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	RefreshToken  string                 `protobuf:"bytes,2,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifySecondFactorResponse) Reset() {
+	*x = VerifySecondFactorResponse{}
+	mi := &file_contracts_auth_v1_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifySecondFactorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifySecondFactorResponse) ProtoMessage() {}
+
+func (x *VerifySecondFactorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_auth_v1_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifySecondFactorResponse.ProtoReflect.Descriptor instead.
+func (*VerifySecondFactorResponse) Descriptor() ([]byte, []int) {
+	return file_contracts_auth_v1_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *VerifySecondFactorResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *VerifySecondFactorResponse) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+type EnrollSecondFactorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnrollSecondFactorRequest) Reset() {
+	*x = EnrollSecondFactorRequest{}
+	mi := &file_contracts_auth_v1_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnrollSecondFactorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnrollSecondFactorRequest) ProtoMessage() {}
+
+func (x *EnrollSecondFactorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_auth_v1_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnrollSecondFactorRequest.ProtoReflect.Descriptor instead.
+func (*EnrollSecondFactorRequest) Descriptor() ([]byte, []int) {
+	return file_contracts_auth_v1_proto_rawDescGZIP(), []int{12}
+}
+
+type EnrollSecondFactorResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uri           string                 `protobuf:"bytes,1,opt,name=uri,proto3" json:"uri,omitempty"`
+	RecoveryCodes []string               `protobuf:"bytes,2,rep,name=recovery_codes,json=recoveryCodes,proto3" json:"recovery_codes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnrollSecondFactorResponse) Reset() {
+	*x = EnrollSecondFactorResponse{}
+	mi := &file_contracts_auth_v1_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnrollSecondFactorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnrollSecondFactorResponse) ProtoMessage() {}
+
+func (x *EnrollSecondFactorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_auth_v1_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnrollSecondFactorResponse.ProtoReflect.Descriptor instead.
+func (*EnrollSecondFactorResponse) Descriptor() ([]byte, []int) {
+	return file_contracts_auth_v1_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *EnrollSecondFactorResponse) GetUri() string {
+	if x != nil {
+		return x.Uri
+	}
+	return ""
+}
+
+func (x *EnrollSecondFactorResponse) GetRecoveryCodes() []string {
+	if x != nil {
+		return x.RecoveryCodes
+	}
+	return nil
+}
+
+type ConfirmSecondFactorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmSecondFactorRequest) Reset() {
+	*x = ConfirmSecondFactorRequest{}
+	mi := &file_contracts_auth_v1_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmSecondFactorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmSecondFactorRequest) ProtoMessage() {}
+
+func (x *ConfirmSecondFactorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_auth_v1_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmSecondFactorRequest.ProtoReflect.Descriptor instead.
+func (*ConfirmSecondFactorRequest) Descriptor() ([]byte, []int) {
+	return file_contracts_auth_v1_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ConfirmSecondFactorRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type ConfirmSecondFactorResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmSecondFactorResponse) Reset() {
+	*x = ConfirmSecondFactorResponse{}
+	mi := &file_contracts_auth_v1_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmSecondFactorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmSecondFactorResponse) ProtoMessage() {}
+
+func (x *ConfirmSecondFactorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_auth_v1_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmSecondFactorResponse.ProtoReflect.Descriptor instead.
+func (*ConfirmSecondFactorResponse) Descriptor() ([]byte, []int) {
+	return file_contracts_auth_v1_proto_rawDescGZIP(), []int{15}
+}
+
+type RefreshRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RefreshToken  string                 `protobuf:"bytes,1,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefreshRequest) Reset() {
+	*x = RefreshRequest{}
+	mi := &file_contracts_auth_v1_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefreshRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefreshRequest) ProtoMessage() {}
+
+func (x *RefreshRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_auth_v1_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefreshRequest.ProtoReflect.Descriptor instead.
+func (*RefreshRequest) Descriptor() ([]byte, []int) {
+	return file_contracts_auth_v1_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *RefreshRequest) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+type RefreshResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	RefreshToken  string                 `protobuf:"bytes,2,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefreshResponse) Reset() {
+	*x = RefreshResponse{}
+	mi := &file_contracts_auth_v1_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefreshResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefreshResponse) ProtoMessage() {}
+
+func (x *RefreshResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_auth_v1_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefreshResponse.ProtoReflect.Descriptor instead.
+func (*RefreshResponse) Descriptor() ([]byte, []int) {
+	return file_contracts_auth_v1_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *RefreshResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *RefreshResponse) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+type ChangePasswordRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	CurrentPassword string                 `protobuf:"bytes,1,opt,name=current_password,json=currentPassword,proto3" json:"current_password,omitempty"`
+	NewPassword     string                 `protobuf:"bytes,2,opt,name=new_password,json=newPassword,proto3" json:"new_password,omitempty"`
+	HandshakeId     string                 `protobuf:"bytes,3,opt,name=handshake_id,json=handshakeId,proto3" json:"handshake_id,omitempty"`
+	ClientProof     []byte                 `protobuf:"bytes,4,opt,name=client_proof,json=clientProof,proto3" json:"client_proof,omitempty"`
+	NewSalt         []byte                 `protobuf:"bytes,5,opt,name=new_salt,json=newSalt,proto3" json:"new_salt,omitempty"`
+	NewVerifier     []byte                 `protobuf:"bytes,6,opt,name=new_verifier,json=newVerifier,proto3" json:"new_verifier,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ChangePasswordRequest) Reset() {
+	*x = ChangePasswordRequest{}
+	mi := &file_contracts_auth_v1_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangePasswordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePasswordRequest) ProtoMessage() {}
+
+func (x *ChangePasswordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_auth_v1_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangePasswordRequest.ProtoReflect.Descriptor instead.
+func (*ChangePasswordRequest) Descriptor() ([]byte, []int) {
+	return file_contracts_auth_v1_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ChangePasswordRequest) GetCurrentPassword() string {
+	if x != nil {
+		return x.CurrentPassword
+	}
+	return ""
+}
+
+func (x *ChangePasswordRequest) GetNewPassword() string {
+	if x != nil {
+		return x.NewPassword
+	}
+	return ""
+}
+
+func (x *ChangePasswordRequest) GetHandshakeId() string {
+	if x != nil {
+		return x.HandshakeId
+	}
+	return ""
+}
+
+func (x *ChangePasswordRequest) GetClientProof() []byte {
+	if x != nil {
+		return x.ClientProof
+	}
+	return nil
+}
+
+func (x *ChangePasswordRequest) GetNewSalt() []byte {
+	if x != nil {
+		return x.NewSalt
+	}
+	return nil
+}
+
+func (x *ChangePasswordRequest) GetNewVerifier() []byte {
+	if x != nil {
+		return x.NewVerifier
+	}
+	return nil
+}
+
+type ChangePasswordResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	RevokedSessions uint32                 `protobuf:"varint,1,opt,name=revoked_sessions,json=revokedSessions,proto3" json:"revoked_sessions,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ChangePasswordResponse) Reset() {
+	*x = ChangePasswordResponse{}
+	mi := &file_contracts_auth_v1_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangePasswordResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePasswordResponse) ProtoMessage() {}
+
+func (x *ChangePasswordResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_auth_v1_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangePasswordResponse.ProtoReflect.Descriptor instead.
+func (*ChangePasswordResponse) Descriptor() ([]byte, []int) {
+	return file_contracts_auth_v1_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ChangePasswordResponse) GetRevokedSessions() uint32 {
+	if x != nil {
+		return x.RevokedSessions
+	}
+	return 0
+}
+
+type DeleteAccountRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Password      string                 `protobuf:"bytes,1,opt,name=password,proto3" json:"password,omitempty"`
+	HandshakeId   string                 `protobuf:"bytes,2,opt,name=handshake_id,json=handshakeId,proto3" json:"handshake_id,omitempty"`
+	ClientProof   []byte                 `protobuf:"bytes,3,opt,name=client_proof,json=clientProof,proto3" json:"client_proof,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAccountRequest) Reset() {
+	*x = DeleteAccountRequest{}
+	mi := &file_contracts_auth_v1_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAccountRequest) ProtoMessage() {}
+
+func (x *DeleteAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_auth_v1_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAccountRequest.ProtoReflect.Descriptor instead.
+func (*DeleteAccountRequest) Descriptor() ([]byte, []int) {
+	return file_contracts_auth_v1_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *DeleteAccountRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *DeleteAccountRequest) GetHandshakeId() string {
+	if x != nil {
+		return x.HandshakeId
+	}
+	return ""
+}
+
+func (x *DeleteAccountRequest) GetClientProof() []byte {
+	if x != nil {
+		return x.ClientProof
+	}
+	return nil
+}
+
+type DeleteAccountResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeleteAfter   *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=delete_after,json=deleteAfter,proto3" json:"delete_after,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAccountResponse) Reset() {
+	*x = DeleteAccountResponse{}
+	mi := &file_contracts_auth_v1_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAccountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAccountResponse) ProtoMessage() {}
+
+func (x *DeleteAccountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_auth_v1_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAccountResponse.ProtoReflect.Descriptor instead.
+func (*DeleteAccountResponse) Descriptor() ([]byte, []int) {
+	return file_contracts_auth_v1_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *DeleteAccountResponse) GetDeleteAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeleteAfter
+	}
+	return nil
+}
+
+type CancelAccountDeletionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelAccountDeletionRequest) Reset() {
+	*x = CancelAccountDeletionRequest{}
+	mi := &file_contracts_auth_v1_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelAccountDeletionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelAccountDeletionRequest) ProtoMessage() {}
+
+func (x *CancelAccountDeletionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_auth_v1_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelAccountDeletionRequest.ProtoReflect.Descriptor instead.
+func (*CancelAccountDeletionRequest) Descriptor() ([]byte, []int) {
+	return file_contracts_auth_v1_proto_rawDescGZIP(), []int{22}
+}
+
+type CancelAccountDeletionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelAccountDeletionResponse) Reset() {
+	*x = CancelAccountDeletionResponse{}
+	mi := &file_contracts_auth_v1_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelAccountDeletionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelAccountDeletionResponse) ProtoMessage() {}
+
+func (x *CancelAccountDeletionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_auth_v1_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelAccountDeletionResponse.ProtoReflect.Descriptor instead.
+func (*CancelAccountDeletionResponse) Descriptor() ([]byte, []int) {
+	return file_contracts_auth_v1_proto_rawDescGZIP(), []int{23}
+}
+
 var File_contracts_auth_v1_proto protoreflect.FileDescriptor
 
 const file_contracts_auth_v1_proto_rawDesc = "" +
 	"\n" +
-	"\x17contracts/auth.v1.proto\x12\fuser.auth.v1\"C\n" +
+	"\x17contracts/auth.v1.proto\x12\fuser.auth.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"C\n" +
 	"\x0fRegisterRequest\x12\x14\n" +
 	"\x05login\x18\x01 \x01(\tR\x05login\x12\x1a\n" +
 	"\bpassword\x18\x02 \x01(\tR\bpassword\"/\n" +
 	"\x10RegisterResponse\x12\x1b\n" +
-	"\tuser_uuid\x18\x01 \x01(\tR\buserUuid\"@\n" +
+	"\tuser_uuid\x18\x01 \x01(\tR\buserUuid\"\xc6\x01\n" +
 	"\fLoginRequest\x12\x14\n" +
 	"\x05login\x18\x01 \x01(\tR\x05login\x12\x1a\n" +
-	"\bpassword\x18\x02 \x01(\tR\bpassword\"%\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\x12\x1f\n" +
+	"\vdevice_name\x18\x03 \x01(\tR\n" +
+	"deviceName\x12%\n" +
+	"\x0eclient_version\x18\x04 \x01(\tR\rclientVersion\x12\x19\n" +
+	"\bsrp_salt\x18\x05 \x01(\fR\asrpSalt\x12!\n" +
+	"\fsrp_verifier\x18\x06 \x01(\fR\vsrpVerifier\"s\n" +
 	"\rLoginResponse\x12\x14\n" +
-	"\x05token\x18\x01 \x01(\tR\x05token2\x9a\x01\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12#\n" +
+	"\rrefresh_token\x18\x02 \x01(\tR\frefreshToken\x12'\n" +
+	"\x0fchallenge_token\x18\x03 \x01(\tR\x0echallengeToken\"Z\n" +
+	"\x12SrpRegisterRequest\x12\x14\n" +
+	"\x05login\x18\x01 \x01(\tR\x05login\x12\x12\n" +
+	"\x04salt\x18\x02 \x01(\fR\x04salt\x12\x1a\n" +
+	"\bverifier\x18\x03 \x01(\fR\bverifier\"L\n" +
+	"\x0fSrpStartRequest\x12\x14\n" +
+	"\x05login\x18\x01 \x01(\tR\x05login\x12#\n" +
+	"\rclient_public\x18\x02 \x01(\fR\fclientPublic\"\x86\x01\n" +
+	"\x10SrpStartResponse\x12!\n" +
+	"\fhandshake_id\x18\x01 \x01(\tR\vhandshakeId\x12\x12\n" +
+	"\x04salt\x18\x02 \x01(\fR\x04salt\x12#\n" +
+	"\rserver_public\x18\x03 \x01(\fR\fserverPublic\x12\x16\n" +
+	"\x06legacy\x18\x04 \x01(\bR\x06legacy\"\x9f\x01\n" +
+	"\x0fSrpLoginRequest\x12!\n" +
+	"\fhandshake_id\x18\x01 \x01(\tR\vhandshakeId\x12!\n" +
+	"\fclient_proof\x18\x02 \x01(\fR\vclientProof\x12\x1f\n" +
+	"\vdevice_name\x18\x03 \x01(\tR\n" +
+	"deviceName\x12%\n" +
+	"\x0eclient_version\x18\x04 \x01(\tR\rclientVersion\"\x99\x01\n" +
+	"\x10SrpLoginResponse\x12!\n" +
+	"\fserver_proof\x18\x01 \x01(\fR\vserverProof\x12\x14\n" +
+	"\x05token\x18\x02 \x01(\tR\x05token\x12#\n" +
+	"\rrefresh_token\x18\x03 \x01(\tR\frefreshToken\x12'\n" +
+	"\x0fchallenge_token\x18\x04 \x01(\tR\x0echallengeToken\";\n" +
+	"\x14SrpProofStartRequest\x12#\n" +
+	"\rclient_public\x18\x01 \x01(\fR\fclientPublic\"X\n" +
+	"\x19VerifySecondFactorRequest\x12'\n" +
+	"\x0fchallenge_token\x18\x01 \x01(\tR\x0echallengeToken\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\"W\n" +
+	"\x1aVerifySecondFactorResponse\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12#\n" +
+	"\rrefresh_token\x18\x02 \x01(\tR\frefreshToken\"\x1b\n" +
+	"\x19EnrollSecondFactorRequest\"U\n" +
+	"\x1aEnrollSecondFactorResponse\x12\x10\n" +
+	"\x03uri\x18\x01 \x01(\tR\x03uri\x12%\n" +
+	"\x0erecovery_codes\x18\x02 \x03(\tR\rrecoveryCodes\"0\n" +
+	"\x1aConfirmSecondFactorRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\"\x1d\n" +
+	"\x1bConfirmSecondFactorResponse\"5\n" +
+	"\x0eRefreshRequest\x12#\n" +
+	"\rrefresh_token\x18\x01 \x01(\tR\frefreshToken\"L\n" +
+	"\x0fRefreshResponse\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12#\n" +
+	"\rrefresh_token\x18\x02 \x01(\tR\frefreshToken\"\xe9\x01\n" +
+	"\x15ChangePasswordRequest\x12)\n" +
+	"\x10current_password\x18\x01 \x01(\tR\x0fcurrentPassword\x12!\n" +
+	"\fnew_password\x18\x02 \x01(\tR\vnewPassword\x12!\n" +
+	"\fhandshake_id\x18\x03 \x01(\tR\vhandshakeId\x12!\n" +
+	"\fclient_proof\x18\x04 \x01(\fR\vclientProof\x12\x19\n" +
+	"\bnew_salt\x18\x05 \x01(\fR\anewSalt\x12!\n" +
+	"\fnew_verifier\x18\x06 \x01(\fR\vnewVerifier\"C\n" +
+	"\x16ChangePasswordResponse\x12)\n" +
+	"\x10revoked_sessions\x18\x01 \x01(\rR\x0frevokedSessions\"x\n" +
+	"\x14DeleteAccountRequest\x12\x1a\n" +
+	"\bpassword\x18\x01 \x01(\tR\bpassword\x12!\n" +
+	"\fhandshake_id\x18\x02 \x01(\tR\vhandshakeId\x12!\n" +
+	"\fclient_proof\x18\x03 \x01(\fR\vclientProof\"V\n" +
+	"\x15DeleteAccountResponse\x12=\n" +
+	"\fdelete_after\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\vdeleteAfter\"\x1e\n" +
+	"\x1cCancelAccountDeletionRequest\"\x1f\n" +
+	"\x1dCancelAccountDeletionResponse2\x85\t\n" +
 	"\vAuthService\x12I\n" +
 	"\bRegister\x12\x1d.user.auth.v1.RegisterRequest\x1a\x1e.user.auth.v1.RegisterResponse\x12@\n" +
-	"\x05Login\x12\x1a.user.auth.v1.LoginRequest\x1a\x1b.user.auth.v1.LoginResponseB\x1cZ\x1ainternal/contracts/v1/authb\x06proto3"
+	"\x05Login\x12\x1a.user.auth.v1.LoginRequest\x1a\x1b.user.auth.v1.LoginResponse\x12F\n" +
+	"\aRefresh\x12\x1c.user.auth.v1.RefreshRequest\x1a\x1d.user.auth.v1.RefreshResponse\x12O\n" +
+	"\vSrpRegister\x12 .user.auth.v1.SrpRegisterRequest\x1a\x1e.user.auth.v1.RegisterResponse\x12I\n" +
+	"\bSrpStart\x12\x1d.user.auth.v1.SrpStartRequest\x1a\x1e.user.auth.v1.SrpStartResponse\x12I\n" +
+	"\bSrpLogin\x12\x1d.user.auth.v1.SrpLoginRequest\x1a\x1e.user.auth.v1.SrpLoginResponse\x12S\n" +
+	"\rSrpProofStart\x12\".user.auth.v1.SrpProofStartRequest\x1a\x1e.user.auth.v1.SrpStartResponse\x12g\n" +
+	"\x12VerifySecondFactor\x12'.user.auth.v1.VerifySecondFactorRequest\x1a(.user.auth.v1.VerifySecondFactorResponse\x12g\n" +
+	"\x12EnrollSecondFactor\x12'.user.auth.v1.EnrollSecondFactorRequest\x1a(.user.auth.v1.EnrollSecondFactorResponse\x12j\n" +
+	"\x13ConfirmSecondFactor\x12(.user.auth.v1.ConfirmSecondFactorRequest\x1a).user.auth.v1.ConfirmSecondFactorResponse\x12[\n" +
+	"\x0eChangePassword\x12#.user.auth.v1.ChangePasswordRequest\x1a$.user.auth.v1.ChangePasswordResponse\x12X\n" +
+	"\rDeleteAccount\x12\".user.auth.v1.DeleteAccountRequest\x1a#.user.auth.v1.DeleteAccountResponse\x12p\n" +
+	"\x15CancelAccountDeletion\x12*.user.auth.v1.CancelAccountDeletionRequest\x1a+.user.auth.v1.CancelAccountDeletionResponseB\x1cZ\x1ainternal/contracts/v1/authb\x06proto3"
 
 var (
 	file_contracts_auth_v1_proto_rawDescOnce sync.Once
@@ -244,23 +1403,67 @@ func file_contracts_auth_v1_proto_rawDescGZIP() []byte {
 	return file_contracts_auth_v1_proto_rawDescData
 }
 
-var file_contracts_auth_v1_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_contracts_auth_v1_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
 var file_contracts_auth_v1_proto_goTypes = []any{
-	(*RegisterRequest)(nil),  // 0: user.auth.v1.RegisterRequest
-	(*RegisterResponse)(nil), // 1: user.auth.v1.RegisterResponse
-	(*LoginRequest)(nil),     // 2: user.auth.v1.LoginRequest
-	(*LoginResponse)(nil),    // 3: user.auth.v1.LoginResponse
+	(*RegisterRequest)(nil),               // 0: user.auth.v1.RegisterRequest
+	(*RegisterResponse)(nil),              // 1: user.auth.v1.RegisterResponse
+	(*LoginRequest)(nil),                  // 2: user.auth.v1.LoginRequest
+	(*LoginResponse)(nil),                 // 3: user.auth.v1.LoginResponse
+	(*SrpRegisterRequest)(nil),            // 4: user.auth.v1.SrpRegisterRequest
+	(*SrpStartRequest)(nil),               // 5: user.auth.v1.SrpStartRequest
+	(*SrpStartResponse)(nil),              // 6: user.auth.v1.SrpStartResponse
+	(*SrpLoginRequest)(nil),               // 7: user.auth.v1.SrpLoginRequest
+	(*SrpLoginResponse)(nil),              // 8: user.auth.v1.SrpLoginResponse
+	(*SrpProofStartRequest)(nil),          // 9: user.auth.v1.SrpProofStartRequest
+	(*VerifySecondFactorRequest)(nil),     // 10: user.auth.v1.VerifySecondFactorRequest
+	(*VerifySecondFactorResponse)(nil),    // 11: user.auth.v1.VerifySecondFactorResponse
+	(*EnrollSecondFactorRequest)(nil),     // 12: user.auth.v1.EnrollSecondFactorRequest
+	(*EnrollSecondFactorResponse)(nil),    // 13: user.auth.v1.EnrollSecondFactorResponse
+	(*ConfirmSecondFactorRequest)(nil),    // 14: user.auth.v1.ConfirmSecondFactorRequest
+	(*ConfirmSecondFactorResponse)(nil),   // 15: user.auth.v1.ConfirmSecondFactorResponse
+	(*RefreshRequest)(nil),                // 16: user.auth.v1.RefreshRequest
+	(*RefreshResponse)(nil),               // 17: user.auth.v1.RefreshResponse
+	(*ChangePasswordRequest)(nil),         // 18: user.auth.v1.ChangePasswordRequest
+	(*ChangePasswordResponse)(nil),        // 19: user.auth.v1.ChangePasswordResponse
+	(*DeleteAccountRequest)(nil),          // 20: user.auth.v1.DeleteAccountRequest
+	(*DeleteAccountResponse)(nil),         // 21: user.auth.v1.DeleteAccountResponse
+	(*CancelAccountDeletionRequest)(nil),  // 22: user.auth.v1.CancelAccountDeletionRequest
+	(*CancelAccountDeletionResponse)(nil), // 23: user.auth.v1.CancelAccountDeletionResponse
+	(*timestamppb.Timestamp)(nil),         // 24: google.protobuf.Timestamp
 }
 var file_contracts_auth_v1_proto_depIdxs = []int32{
-	0, // 0: user.auth.v1.AuthService.Register:input_type -> user.auth.v1.RegisterRequest
-	2, // 1: user.auth.v1.AuthService.Login:input_type -> user.auth.v1.LoginRequest
-	1, // 2: user.auth.v1.AuthService.Register:output_type -> user.auth.v1.RegisterResponse
-	3, // 3: user.auth.v1.AuthService.Login:output_type -> user.auth.v1.LoginResponse
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	24, // 0: user.auth.v1.DeleteAccountResponse.delete_after:type_name -> google.protobuf.Timestamp
+	0,  // 1: user.auth.v1.AuthService.Register:input_type -> user.auth.v1.RegisterRequest
+	2,  // 2: user.auth.v1.AuthService.Login:input_type -> user.auth.v1.LoginRequest
+	16, // 3: user.auth.v1.AuthService.Refresh:input_type -> user.auth.v1.RefreshRequest
+	4,  // 4: user.auth.v1.AuthService.SrpRegister:input_type -> user.auth.v1.SrpRegisterRequest
+	5,  // 5: user.auth.v1.AuthService.SrpStart:input_type -> user.auth.v1.SrpStartRequest
+	7,  // 6: user.auth.v1.AuthService.SrpLogin:input_type -> user.auth.v1.SrpLoginRequest
+	9,  // 7: user.auth.v1.AuthService.SrpProofStart:input_type -> user.auth.v1.SrpProofStartRequest
+	10, // 8: user.auth.v1.AuthService.VerifySecondFactor:input_type -> user.auth.v1.VerifySecondFactorRequest
+	12, // 9: user.auth.v1.AuthService.EnrollSecondFactor:input_type -> user.auth.v1.EnrollSecondFactorRequest
+	14, // 10: user.auth.v1.AuthService.ConfirmSecondFactor:input_type -> user.auth.v1.ConfirmSecondFactorRequest
+	18, // 11: user.auth.v1.AuthService.ChangePassword:input_type -> user.auth.v1.ChangePasswordRequest
+	20, // 12: user.auth.v1.AuthService.DeleteAccount:input_type -> user.auth.v1.DeleteAccountRequest
+	22, // 13: user.auth.v1.AuthService.CancelAccountDeletion:input_type -> user.auth.v1.CancelAccountDeletionRequest
+	1,  // 14: user.auth.v1.AuthService.Register:output_type -> user.auth.v1.RegisterResponse
+	3,  // 15: user.auth.v1.AuthService.Login:output_type -> user.auth.v1.LoginResponse
+	17, // 16: user.auth.v1.AuthService.Refresh:output_type -> user.auth.v1.RefreshResponse
+	1,  // 17: user.auth.v1.AuthService.SrpRegister:output_type -> user.auth.v1.RegisterResponse
+	6,  // 18: user.auth.v1.AuthService.SrpStart:output_type -> user.auth.v1.SrpStartResponse
+	8,  // 19: user.auth.v1.AuthService.SrpLogin:output_type -> user.auth.v1.SrpLoginResponse
+	6,  // 20: user.auth.v1.AuthService.SrpProofStart:output_type -> user.auth.v1.SrpStartResponse
+	11, // 21: user.auth.v1.AuthService.VerifySecondFactor:output_type -> user.auth.v1.VerifySecondFactorResponse
+	13, // 22: user.auth.v1.AuthService.EnrollSecondFactor:output_type -> user.auth.v1.EnrollSecondFactorResponse
+	15, // 23: user.auth.v1.AuthService.ConfirmSecondFactor:output_type -> user.auth.v1.ConfirmSecondFactorResponse
+	19, // 24: user.auth.v1.AuthService.ChangePassword:output_type -> user.auth.v1.ChangePasswordResponse
+	21, // 25: user.auth.v1.AuthService.DeleteAccount:output_type -> user.auth.v1.DeleteAccountResponse
+	23, // 26: user.auth.v1.AuthService.CancelAccountDeletion:output_type -> user.auth.v1.CancelAccountDeletionResponse
+	14, // [14:27] is the sub-list for method output_type
+	1,  // [1:14] is the sub-list for method input_type
+	1,  // [1:1] is the sub-list for extension type_name
+	1,  // [1:1] is the sub-list for extension extendee
+	0,  // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_contracts_auth_v1_proto_init() }
@@ -274,7 +1477,7 @@ func file_contracts_auth_v1_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_contracts_auth_v1_proto_rawDesc), len(file_contracts_auth_v1_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   24,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -25,8 +25,11 @@ func MapItemToEntity(item *data.UserDataItem, userUUID string) entity.UserData {
 			}
 			return d
 		}(),
-		CreatedAt: item.CreatedAt.AsTime(),
-		UpdatedAt: item.UpdatedAt.AsTime(),
+		TagMACs:    item.TagMacs,
+		KeyVersion: item.KeyVersion,
+		Version:    item.Version,
+		CreatedAt:  item.CreatedAt.AsTime(),
+		UpdatedAt:  item.UpdatedAt.AsTime(),
 	}
 }
 
@@ -49,7 +52,71 @@ func MapEntityToItem(e entity.UserData) *data.UserDataItem {
 			}
 			return d
 		}(),
-		CreatedAt: timestamppb.New(e.CreatedAt),
-		UpdatedAt: timestamppb.New(e.UpdatedAt),
+		TagMacs:    e.TagMACs,
+		KeyVersion: e.KeyVersion,
+		Version:    e.Version,
+		CreatedAt:  timestamppb.New(e.CreatedAt),
+		UpdatedAt:  timestamppb.New(e.UpdatedAt),
 	}
 }
+
+// MapRevisionToEntity мапит [data.ItemRevision] в [entity.UserDataRevision].
+func MapRevisionToEntity(rev *data.ItemRevision, userUUID string) entity.UserDataRevision {
+	return entity.UserDataRevision{
+		UUID:       rev.Uuid,
+		Item:       MapItemToEntity(rev.Item, userUUID),
+		Deleted:    rev.Deleted,
+		ArchivedAt: rev.ArchivedAt.AsTime(),
+	}
+}
+
+// MapEntityToRevision мапит [entity.UserDataRevision] в [data.ItemRevision].
+func MapEntityToRevision(e entity.UserDataRevision) *data.ItemRevision {
+	return &data.ItemRevision{
+		Uuid:       e.UUID,
+		Item:       MapEntityToItem(e.Item),
+		Deleted:    e.Deleted,
+		ArchivedAt: timestamppb.New(e.ArchivedAt),
+	}
+}
+
+// MapBatchItemToEntity мапит [data.SyncBatchItemResult] в [entity.UserDataBatchItem].
+func MapBatchItemToEntity(r *data.SyncBatchItemResult, userUUID string) entity.UserDataBatchItem {
+	item := entity.UserDataBatchItem{
+		UUID:    r.Uuid,
+		Deleted: r.Deleted,
+	}
+	switch r.Status {
+	case data.SyncBatchItemResult_VERSION_CONFLICT:
+		item.Status = entity.BatchItemConflict
+	case data.SyncBatchItemResult_NOT_FOUND:
+		item.Status = entity.BatchItemNotFound
+	default:
+		item.Status = entity.BatchItemApplied
+	}
+	if r.Item != nil {
+		d := MapItemToEntity(r.Item, userUUID)
+		item.Item = &d
+	}
+	return item
+}
+
+// MapEntityToBatchItem мапит [entity.UserDataBatchItem] в [data.SyncBatchItemResult].
+func MapEntityToBatchItem(e entity.UserDataBatchItem) *data.SyncBatchItemResult {
+	r := &data.SyncBatchItemResult{
+		Uuid:    e.UUID,
+		Deleted: e.Deleted,
+	}
+	switch e.Status {
+	case entity.BatchItemConflict:
+		r.Status = data.SyncBatchItemResult_VERSION_CONFLICT
+	case entity.BatchItemNotFound:
+		r.Status = data.SyncBatchItemResult_NOT_FOUND
+	default:
+		r.Status = data.SyncBatchItemResult_APPLIED
+	}
+	if e.Item != nil {
+		r.Item = MapEntityToItem(*e.Item)
+	}
+	return r
+}
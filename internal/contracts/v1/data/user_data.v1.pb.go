@@ -26,9 +26,11 @@ const (
 type UserDataItem_DataType int32
 
 const (
-	UserDataItem_TEXT   UserDataItem_DataType = 0
-	UserDataItem_BINARY UserDataItem_DataType = 1
-	UserDataItem_CARD   UserDataItem_DataType = 2
+	UserDataItem_TEXT       UserDataItem_DataType = 0
+	UserDataItem_BINARY     UserDataItem_DataType = 1
+	UserDataItem_CARD       UserDataItem_DataType = 2
+	UserDataItem_CREDENTIAL UserDataItem_DataType = 3
+	UserDataItem_OTP        UserDataItem_DataType = 4
 )
 
 // Enum value maps for UserDataItem_DataType.
@@ -37,11 +39,15 @@ var (
 		0: "TEXT",
 		1: "BINARY",
 		2: "CARD",
+		3: "CREDENTIAL",
+		4: "OTP",
 	}
 	UserDataItem_DataType_value = map[string]int32{
-		"TEXT":   0,
-		"BINARY": 1,
-		"CARD":   2,
+		"TEXT":       0,
+		"BINARY":     1,
+		"CARD":       2,
+		"CREDENTIAL": 3,
+		"OTP":        4,
 	}
 )
 
@@ -72,6 +78,55 @@ func (UserDataItem_DataType) EnumDescriptor() ([]byte, []int) {
 	return file_contracts_user_data_v1_proto_rawDescGZIP(), []int{1, 0}
 }
 
+type SyncBatchItemResult_Status int32
+
+const (
+	SyncBatchItemResult_APPLIED          SyncBatchItemResult_Status = 0
+	SyncBatchItemResult_VERSION_CONFLICT SyncBatchItemResult_Status = 1
+	SyncBatchItemResult_NOT_FOUND        SyncBatchItemResult_Status = 2
+)
+
+// Enum value maps for SyncBatchItemResult_Status.
+var (
+	SyncBatchItemResult_Status_name = map[int32]string{
+		0: "APPLIED",
+		1: "VERSION_CONFLICT",
+		2: "NOT_FOUND",
+	}
+	SyncBatchItemResult_Status_value = map[string]int32{
+		"APPLIED":          0,
+		"VERSION_CONFLICT": 1,
+		"NOT_FOUND":        2,
+	}
+)
+
+func (x SyncBatchItemResult_Status) Enum() *SyncBatchItemResult_Status {
+	p := new(SyncBatchItemResult_Status)
+	*p = x
+	return p
+}
+
+func (x SyncBatchItemResult_Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SyncBatchItemResult_Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_contracts_user_data_v1_proto_enumTypes[1].Descriptor()
+}
+
+func (SyncBatchItemResult_Status) Type() protoreflect.EnumType {
+	return &file_contracts_user_data_v1_proto_enumTypes[1]
+}
+
+func (x SyncBatchItemResult_Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SyncBatchItemResult_Status.Descriptor instead.
+func (SyncBatchItemResult_Status) EnumDescriptor() ([]byte, []int) {
+	return file_contracts_user_data_v1_proto_rawDescGZIP(), []int{22, 0}
+}
+
 type MetaData struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
@@ -133,6 +188,9 @@ type UserDataItem struct {
 	Metadata      []*MetaData            `protobuf:"bytes,5,rep,name=metadata,proto3" json:"metadata,omitempty"`
 	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	KeyVersion    uint32                 `protobuf:"varint,8,opt,name=key_version,json=keyVersion,proto3" json:"key_version,omitempty"`
+	Version       uint64                 `protobuf:"varint,9,opt,name=version,proto3" json:"version,omitempty"`
+	TagMacs       [][]byte               `protobuf:"bytes,10,rep,name=tag_macs,json=tagMacs,proto3" json:"tag_macs,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -216,6 +274,27 @@ func (x *UserDataItem) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *UserDataItem) GetKeyVersion() uint32 {
+	if x != nil {
+		return x.KeyVersion
+	}
+	return 0
+}
+
+func (x *UserDataItem) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *UserDataItem) GetTagMacs() [][]byte {
+	if x != nil {
+		return x.TagMacs
+	}
+	return nil
+}
+
 type CreateUserDataItemRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Item          *UserDataItem          `protobuf:"bytes,1,opt,name=item,proto3" json:"item,omitempty"`
@@ -305,10 +384,11 @@ func (x *CreateUserDataItemResponse) GetItem() *UserDataItem {
 }
 
 type UpdateUserDataItemRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Item          *UserDataItem          `protobuf:"bytes,1,opt,name=item,proto3" json:"item,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Item            *UserDataItem          `protobuf:"bytes,1,opt,name=item,proto3" json:"item,omitempty"`
+	ExpectedVersion uint64                 `protobuf:"varint,2,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *UpdateUserDataItemRequest) Reset() {
@@ -348,6 +428,57 @@ func (x *UpdateUserDataItemRequest) GetItem() *UserDataItem {
 	return nil
 }
 
+func (x *UpdateUserDataItemRequest) GetExpectedVersion() uint64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type VersionConflict struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Current       *UserDataItem          `protobuf:"bytes,1,opt,name=current,proto3" json:"current,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VersionConflict) Reset() {
+	*x = VersionConflict{}
+	mi := &file_contracts_user_data_v1_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VersionConflict) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VersionConflict) ProtoMessage() {}
+
+func (x *VersionConflict) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_user_data_v1_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VersionConflict.ProtoReflect.Descriptor instead.
+func (*VersionConflict) Descriptor() ([]byte, []int) {
+	return file_contracts_user_data_v1_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *VersionConflict) GetCurrent() *UserDataItem {
+	if x != nil {
+		return x.Current
+	}
+	return nil
+}
+
 type UpdateUserDataItemResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Item          *UserDataItem          `protobuf:"bytes,1,opt,name=item,proto3" json:"item,omitempty"`
@@ -357,7 +488,7 @@ type UpdateUserDataItemResponse struct {
 
 func (x *UpdateUserDataItemResponse) Reset() {
 	*x = UpdateUserDataItemResponse{}
-	mi := &file_contracts_user_data_v1_proto_msgTypes[5]
+	mi := &file_contracts_user_data_v1_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -369,7 +500,7 @@ func (x *UpdateUserDataItemResponse) String() string {
 func (*UpdateUserDataItemResponse) ProtoMessage() {}
 
 func (x *UpdateUserDataItemResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_contracts_user_data_v1_proto_msgTypes[5]
+	mi := &file_contracts_user_data_v1_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -382,7 +513,7 @@ func (x *UpdateUserDataItemResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateUserDataItemResponse.ProtoReflect.Descriptor instead.
 func (*UpdateUserDataItemResponse) Descriptor() ([]byte, []int) {
-	return file_contracts_user_data_v1_proto_rawDescGZIP(), []int{5}
+	return file_contracts_user_data_v1_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *UpdateUserDataItemResponse) GetItem() *UserDataItem {
@@ -401,7 +532,7 @@ type GetUserDataItemRequest struct {
 
 func (x *GetUserDataItemRequest) Reset() {
 	*x = GetUserDataItemRequest{}
-	mi := &file_contracts_user_data_v1_proto_msgTypes[6]
+	mi := &file_contracts_user_data_v1_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -413,7 +544,7 @@ func (x *GetUserDataItemRequest) String() string {
 func (*GetUserDataItemRequest) ProtoMessage() {}
 
 func (x *GetUserDataItemRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_contracts_user_data_v1_proto_msgTypes[6]
+	mi := &file_contracts_user_data_v1_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -426,7 +557,7 @@ func (x *GetUserDataItemRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetUserDataItemRequest.ProtoReflect.Descriptor instead.
 func (*GetUserDataItemRequest) Descriptor() ([]byte, []int) {
-	return file_contracts_user_data_v1_proto_rawDescGZIP(), []int{6}
+	return file_contracts_user_data_v1_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *GetUserDataItemRequest) GetItemUuid() string {
@@ -445,7 +576,7 @@ type GetUserDataItemResponse struct {
 
 func (x *GetUserDataItemResponse) Reset() {
 	*x = GetUserDataItemResponse{}
-	mi := &file_contracts_user_data_v1_proto_msgTypes[7]
+	mi := &file_contracts_user_data_v1_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -457,7 +588,7 @@ func (x *GetUserDataItemResponse) String() string {
 func (*GetUserDataItemResponse) ProtoMessage() {}
 
 func (x *GetUserDataItemResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_contracts_user_data_v1_proto_msgTypes[7]
+	mi := &file_contracts_user_data_v1_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -470,7 +601,7 @@ func (x *GetUserDataItemResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetUserDataItemResponse.ProtoReflect.Descriptor instead.
 func (*GetUserDataItemResponse) Descriptor() ([]byte, []int) {
-	return file_contracts_user_data_v1_proto_rawDescGZIP(), []int{7}
+	return file_contracts_user_data_v1_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *GetUserDataItemResponse) GetItem() *UserDataItem {
@@ -489,7 +620,7 @@ type GetUserDataItemsRequest struct {
 
 func (x *GetUserDataItemsRequest) Reset() {
 	*x = GetUserDataItemsRequest{}
-	mi := &file_contracts_user_data_v1_proto_msgTypes[8]
+	mi := &file_contracts_user_data_v1_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -501,7 +632,7 @@ func (x *GetUserDataItemsRequest) String() string {
 func (*GetUserDataItemsRequest) ProtoMessage() {}
 
 func (x *GetUserDataItemsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_contracts_user_data_v1_proto_msgTypes[8]
+	mi := &file_contracts_user_data_v1_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -514,7 +645,7 @@ func (x *GetUserDataItemsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetUserDataItemsRequest.ProtoReflect.Descriptor instead.
 func (*GetUserDataItemsRequest) Descriptor() ([]byte, []int) {
-	return file_contracts_user_data_v1_proto_rawDescGZIP(), []int{8}
+	return file_contracts_user_data_v1_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *GetUserDataItemsRequest) GetItemUuids() []string {
@@ -533,7 +664,7 @@ type GetUserDataItemsResponse struct {
 
 func (x *GetUserDataItemsResponse) Reset() {
 	*x = GetUserDataItemsResponse{}
-	mi := &file_contracts_user_data_v1_proto_msgTypes[9]
+	mi := &file_contracts_user_data_v1_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -545,7 +676,7 @@ func (x *GetUserDataItemsResponse) String() string {
 func (*GetUserDataItemsResponse) ProtoMessage() {}
 
 func (x *GetUserDataItemsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_contracts_user_data_v1_proto_msgTypes[9]
+	mi := &file_contracts_user_data_v1_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -558,7 +689,7 @@ func (x *GetUserDataItemsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetUserDataItemsResponse.ProtoReflect.Descriptor instead.
 func (*GetUserDataItemsResponse) Descriptor() ([]byte, []int) {
-	return file_contracts_user_data_v1_proto_rawDescGZIP(), []int{9}
+	return file_contracts_user_data_v1_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *GetUserDataItemsResponse) GetItems() []*UserDataItem {
@@ -577,7 +708,7 @@ type DeleteUserDataItemsRequest struct {
 
 func (x *DeleteUserDataItemsRequest) Reset() {
 	*x = DeleteUserDataItemsRequest{}
-	mi := &file_contracts_user_data_v1_proto_msgTypes[10]
+	mi := &file_contracts_user_data_v1_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -589,7 +720,7 @@ func (x *DeleteUserDataItemsRequest) String() string {
 func (*DeleteUserDataItemsRequest) ProtoMessage() {}
 
 func (x *DeleteUserDataItemsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_contracts_user_data_v1_proto_msgTypes[10]
+	mi := &file_contracts_user_data_v1_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -602,7 +733,7 @@ func (x *DeleteUserDataItemsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteUserDataItemsRequest.ProtoReflect.Descriptor instead.
 func (*DeleteUserDataItemsRequest) Descriptor() ([]byte, []int) {
-	return file_contracts_user_data_v1_proto_rawDescGZIP(), []int{10}
+	return file_contracts_user_data_v1_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *DeleteUserDataItemsRequest) GetItemUuids() []string {
@@ -612,35 +743,694 @@ func (x *DeleteUserDataItemsRequest) GetItemUuids() []string {
 	return nil
 }
 
-var File_contracts_user_data_v1_proto protoreflect.FileDescriptor
+type ItemRevision struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Item          *UserDataItem          `protobuf:"bytes,2,opt,name=item,proto3" json:"item,omitempty"`
+	Deleted       bool                   `protobuf:"varint,3,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	ArchivedAt    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=archived_at,json=archivedAt,proto3" json:"archived_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_contracts_user_data_v1_proto_rawDesc = "" +
-	"\n" +
-	"\x1ccontracts/user_data.v1.proto\x12\fuser.data.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"6\n" +
-	"\bMetaData\x12\x14\n" +
-	"\x05title\x18\x01 \x01(\tR\x05title\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value\"\xdb\x02\n" +
-	"\fUserDataItem\x12\x12\n" +
-	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x14\n" +
-	"\x05title\x18\x02 \x01(\tR\x05title\x127\n" +
-	"\x04type\x18\x03 \x01(\x0e2#.user.data.v1.UserDataItem.DataTypeR\x04type\x12\x12\n" +
-	"\x04data\x18\x04 \x01(\fR\x04data\x122\n" +
-	"\bmetadata\x18\x05 \x03(\v2\x16.user.data.v1.MetaDataR\bmetadata\x129\n" +
-	"\n" +
-	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
-	"\n" +
-	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"*\n" +
-	"\bDataType\x12\b\n" +
-	"\x04TEXT\x10\x00\x12\n" +
-	"\n" +
-	"\x06BINARY\x10\x01\x12\b\n" +
-	"\x04CARD\x10\x02\"K\n" +
-	"\x19CreateUserDataItemRequest\x12.\n" +
-	"\x04item\x18\x01 \x01(\v2\x1a.user.data.v1.UserDataItemR\x04item\"L\n" +
-	"\x1aCreateUserDataItemResponse\x12.\n" +
-	"\x04item\x18\x01 \x01(\v2\x1a.user.data.v1.UserDataItemR\x04item\"K\n" +
-	"\x19UpdateUserDataItemRequest\x12.\n" +
-	"\x04item\x18\x01 \x01(\v2\x1a.user.data.v1.UserDataItemR\x04item\"L\n" +
+func (x *ItemRevision) Reset() {
+	*x = ItemRevision{}
+	mi := &file_contracts_user_data_v1_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ItemRevision) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ItemRevision) ProtoMessage() {}
+
+func (x *ItemRevision) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_user_data_v1_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ItemRevision.ProtoReflect.Descriptor instead.
+func (*ItemRevision) Descriptor() ([]byte, []int) {
+	return file_contracts_user_data_v1_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ItemRevision) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *ItemRevision) GetItem() *UserDataItem {
+	if x != nil {
+		return x.Item
+	}
+	return nil
+}
+
+func (x *ItemRevision) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+func (x *ItemRevision) GetArchivedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ArchivedAt
+	}
+	return nil
+}
+
+type ListItemRevisionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ItemUuid      string                 `protobuf:"bytes,1,opt,name=item_uuid,json=itemUuid,proto3" json:"item_uuid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListItemRevisionsRequest) Reset() {
+	*x = ListItemRevisionsRequest{}
+	mi := &file_contracts_user_data_v1_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListItemRevisionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListItemRevisionsRequest) ProtoMessage() {}
+
+func (x *ListItemRevisionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_user_data_v1_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListItemRevisionsRequest.ProtoReflect.Descriptor instead.
+func (*ListItemRevisionsRequest) Descriptor() ([]byte, []int) {
+	return file_contracts_user_data_v1_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListItemRevisionsRequest) GetItemUuid() string {
+	if x != nil {
+		return x.ItemUuid
+	}
+	return ""
+}
+
+type ListItemRevisionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Revisions     []*ItemRevision        `protobuf:"bytes,1,rep,name=revisions,proto3" json:"revisions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListItemRevisionsResponse) Reset() {
+	*x = ListItemRevisionsResponse{}
+	mi := &file_contracts_user_data_v1_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListItemRevisionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListItemRevisionsResponse) ProtoMessage() {}
+
+func (x *ListItemRevisionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_user_data_v1_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListItemRevisionsResponse.ProtoReflect.Descriptor instead.
+func (*ListItemRevisionsResponse) Descriptor() ([]byte, []int) {
+	return file_contracts_user_data_v1_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListItemRevisionsResponse) GetRevisions() []*ItemRevision {
+	if x != nil {
+		return x.Revisions
+	}
+	return nil
+}
+
+type RestoreItemRevisionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ItemUuid      string                 `protobuf:"bytes,1,opt,name=item_uuid,json=itemUuid,proto3" json:"item_uuid,omitempty"`
+	RevisionUuid  string                 `protobuf:"bytes,2,opt,name=revision_uuid,json=revisionUuid,proto3" json:"revision_uuid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreItemRevisionRequest) Reset() {
+	*x = RestoreItemRevisionRequest{}
+	mi := &file_contracts_user_data_v1_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreItemRevisionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreItemRevisionRequest) ProtoMessage() {}
+
+func (x *RestoreItemRevisionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_user_data_v1_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreItemRevisionRequest.ProtoReflect.Descriptor instead.
+func (*RestoreItemRevisionRequest) Descriptor() ([]byte, []int) {
+	return file_contracts_user_data_v1_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RestoreItemRevisionRequest) GetItemUuid() string {
+	if x != nil {
+		return x.ItemUuid
+	}
+	return ""
+}
+
+func (x *RestoreItemRevisionRequest) GetRevisionUuid() string {
+	if x != nil {
+		return x.RevisionUuid
+	}
+	return ""
+}
+
+type RestoreItemRevisionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Item          *UserDataItem          `protobuf:"bytes,1,opt,name=item,proto3" json:"item,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreItemRevisionResponse) Reset() {
+	*x = RestoreItemRevisionResponse{}
+	mi := &file_contracts_user_data_v1_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreItemRevisionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreItemRevisionResponse) ProtoMessage() {}
+
+func (x *RestoreItemRevisionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_user_data_v1_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreItemRevisionResponse.ProtoReflect.Descriptor instead.
+func (*RestoreItemRevisionResponse) Descriptor() ([]byte, []int) {
+	return file_contracts_user_data_v1_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *RestoreItemRevisionResponse) GetItem() *UserDataItem {
+	if x != nil {
+		return x.Item
+	}
+	return nil
+}
+
+type ListChangesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SinceRevision uint64                 `protobuf:"varint,1,opt,name=since_revision,json=sinceRevision,proto3" json:"since_revision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListChangesRequest) Reset() {
+	*x = ListChangesRequest{}
+	mi := &file_contracts_user_data_v1_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListChangesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListChangesRequest) ProtoMessage() {}
+
+func (x *ListChangesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_user_data_v1_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListChangesRequest.ProtoReflect.Descriptor instead.
+func (*ListChangesRequest) Descriptor() ([]byte, []int) {
+	return file_contracts_user_data_v1_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListChangesRequest) GetSinceRevision() uint64 {
+	if x != nil {
+		return x.SinceRevision
+	}
+	return 0
+}
+
+type ListChangesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Upserts       []*UserDataItem        `protobuf:"bytes,1,rep,name=upserts,proto3" json:"upserts,omitempty"`
+	DeletedUuids  []string               `protobuf:"bytes,2,rep,name=deleted_uuids,json=deletedUuids,proto3" json:"deleted_uuids,omitempty"`
+	Revision      uint64                 `protobuf:"varint,3,opt,name=revision,proto3" json:"revision,omitempty"`
+	Full          bool                   `protobuf:"varint,4,opt,name=full,proto3" json:"full,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListChangesResponse) Reset() {
+	*x = ListChangesResponse{}
+	mi := &file_contracts_user_data_v1_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListChangesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListChangesResponse) ProtoMessage() {}
+
+func (x *ListChangesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_user_data_v1_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListChangesResponse.ProtoReflect.Descriptor instead.
+func (*ListChangesResponse) Descriptor() ([]byte, []int) {
+	return file_contracts_user_data_v1_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ListChangesResponse) GetUpserts() []*UserDataItem {
+	if x != nil {
+		return x.Upserts
+	}
+	return nil
+}
+
+func (x *ListChangesResponse) GetDeletedUuids() []string {
+	if x != nil {
+		return x.DeletedUuids
+	}
+	return nil
+}
+
+func (x *ListChangesResponse) GetRevision() uint64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *ListChangesResponse) GetFull() bool {
+	if x != nil {
+		return x.Full
+	}
+	return false
+}
+
+type WatchChangesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SinceRevision uint64                 `protobuf:"varint,1,opt,name=since_revision,json=sinceRevision,proto3" json:"since_revision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchChangesRequest) Reset() {
+	*x = WatchChangesRequest{}
+	mi := &file_contracts_user_data_v1_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchChangesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchChangesRequest) ProtoMessage() {}
+
+func (x *WatchChangesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_user_data_v1_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchChangesRequest.ProtoReflect.Descriptor instead.
+func (*WatchChangesRequest) Descriptor() ([]byte, []int) {
+	return file_contracts_user_data_v1_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *WatchChangesRequest) GetSinceRevision() uint64 {
+	if x != nil {
+		return x.SinceRevision
+	}
+	return 0
+}
+
+type SyncBatchUpsert struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Item            *UserDataItem          `protobuf:"bytes,1,opt,name=item,proto3" json:"item,omitempty"`
+	ExpectedVersion uint64                 `protobuf:"varint,2,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SyncBatchUpsert) Reset() {
+	*x = SyncBatchUpsert{}
+	mi := &file_contracts_user_data_v1_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncBatchUpsert) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncBatchUpsert) ProtoMessage() {}
+
+func (x *SyncBatchUpsert) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_user_data_v1_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncBatchUpsert.ProtoReflect.Descriptor instead.
+func (*SyncBatchUpsert) Descriptor() ([]byte, []int) {
+	return file_contracts_user_data_v1_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *SyncBatchUpsert) GetItem() *UserDataItem {
+	if x != nil {
+		return x.Item
+	}
+	return nil
+}
+
+func (x *SyncBatchUpsert) GetExpectedVersion() uint64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type SyncBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BatchId       string                 `protobuf:"bytes,1,opt,name=batch_id,json=batchId,proto3" json:"batch_id,omitempty"`
+	Upserts       []*SyncBatchUpsert     `protobuf:"bytes,2,rep,name=upserts,proto3" json:"upserts,omitempty"`
+	DeleteUuids   []string               `protobuf:"bytes,3,rep,name=delete_uuids,json=deleteUuids,proto3" json:"delete_uuids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncBatchRequest) Reset() {
+	*x = SyncBatchRequest{}
+	mi := &file_contracts_user_data_v1_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncBatchRequest) ProtoMessage() {}
+
+func (x *SyncBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_user_data_v1_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncBatchRequest.ProtoReflect.Descriptor instead.
+func (*SyncBatchRequest) Descriptor() ([]byte, []int) {
+	return file_contracts_user_data_v1_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *SyncBatchRequest) GetBatchId() string {
+	if x != nil {
+		return x.BatchId
+	}
+	return ""
+}
+
+func (x *SyncBatchRequest) GetUpserts() []*SyncBatchUpsert {
+	if x != nil {
+		return x.Upserts
+	}
+	return nil
+}
+
+func (x *SyncBatchRequest) GetDeleteUuids() []string {
+	if x != nil {
+		return x.DeleteUuids
+	}
+	return nil
+}
+
+type SyncBatchItemResult struct {
+	state         protoimpl.MessageState     `protogen:"open.v1"`
+	Uuid          string                     `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Status        SyncBatchItemResult_Status `protobuf:"varint,2,opt,name=status,proto3,enum=user.data.v1.SyncBatchItemResult_Status" json:"status,omitempty"`
+	Item          *UserDataItem              `protobuf:"bytes,3,opt,name=item,proto3" json:"item,omitempty"`
+	Deleted       bool                       `protobuf:"varint,4,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncBatchItemResult) Reset() {
+	*x = SyncBatchItemResult{}
+	mi := &file_contracts_user_data_v1_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncBatchItemResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncBatchItemResult) ProtoMessage() {}
+
+func (x *SyncBatchItemResult) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_user_data_v1_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncBatchItemResult.ProtoReflect.Descriptor instead.
+func (*SyncBatchItemResult) Descriptor() ([]byte, []int) {
+	return file_contracts_user_data_v1_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *SyncBatchItemResult) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *SyncBatchItemResult) GetStatus() SyncBatchItemResult_Status {
+	if x != nil {
+		return x.Status
+	}
+	return SyncBatchItemResult_APPLIED
+}
+
+func (x *SyncBatchItemResult) GetItem() *UserDataItem {
+	if x != nil {
+		return x.Item
+	}
+	return nil
+}
+
+func (x *SyncBatchItemResult) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+type SyncBatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*SyncBatchItemResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	Revision      uint64                 `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	Replayed      bool                   `protobuf:"varint,3,opt,name=replayed,proto3" json:"replayed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncBatchResponse) Reset() {
+	*x = SyncBatchResponse{}
+	mi := &file_contracts_user_data_v1_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncBatchResponse) ProtoMessage() {}
+
+func (x *SyncBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_user_data_v1_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncBatchResponse.ProtoReflect.Descriptor instead.
+func (*SyncBatchResponse) Descriptor() ([]byte, []int) {
+	return file_contracts_user_data_v1_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *SyncBatchResponse) GetResults() []*SyncBatchItemResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *SyncBatchResponse) GetRevision() uint64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *SyncBatchResponse) GetReplayed() bool {
+	if x != nil {
+		return x.Replayed
+	}
+	return false
+}
+
+var File_contracts_user_data_v1_proto protoreflect.FileDescriptor
+
+const file_contracts_user_data_v1_proto_rawDesc = "" +
+	"\n" +
+	"\x1ccontracts/user_data.v1.proto\x12\fuser.data.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"6\n" +
+	"\bMetaData\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\"\xca\x03\n" +
+	"\fUserDataItem\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x127\n" +
+	"\x04type\x18\x03 \x01(\x0e2#.user.data.v1.UserDataItem.DataTypeR\x04type\x12\x12\n" +
+	"\x04data\x18\x04 \x01(\fR\x04data\x122\n" +
+	"\bmetadata\x18\x05 \x03(\v2\x16.user.data.v1.MetaDataR\bmetadata\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x1f\n" +
+	"\vkey_version\x18\b \x01(\rR\n" +
+	"keyVersion\x12\x18\n" +
+	"\aversion\x18\t \x01(\x04R\aversion\x12\x19\n" +
+	"\btag_macs\x18\n" +
+	" \x03(\fR\atagMacs\"C\n" +
+	"\bDataType\x12\b\n" +
+	"\x04TEXT\x10\x00\x12\n" +
+	"\n" +
+	"\x06BINARY\x10\x01\x12\b\n" +
+	"\x04CARD\x10\x02\x12\x0e\n" +
+	"\n" +
+	"CREDENTIAL\x10\x03\x12\a\n" +
+	"\x03OTP\x10\x04\"K\n" +
+	"\x19CreateUserDataItemRequest\x12.\n" +
+	"\x04item\x18\x01 \x01(\v2\x1a.user.data.v1.UserDataItemR\x04item\"L\n" +
+	"\x1aCreateUserDataItemResponse\x12.\n" +
+	"\x04item\x18\x01 \x01(\v2\x1a.user.data.v1.UserDataItemR\x04item\"v\n" +
+	"\x19UpdateUserDataItemRequest\x12.\n" +
+	"\x04item\x18\x01 \x01(\v2\x1a.user.data.v1.UserDataItemR\x04item\x12)\n" +
+	"\x10expected_version\x18\x02 \x01(\x04R\x0fexpectedVersion\"G\n" +
+	"\x0fVersionConflict\x124\n" +
+	"\acurrent\x18\x01 \x01(\v2\x1a.user.data.v1.UserDataItemR\acurrent\"L\n" +
 	"\x1aUpdateUserDataItemResponse\x12.\n" +
 	"\x04item\x18\x01 \x01(\v2\x1a.user.data.v1.UserDataItemR\x04item\"5\n" +
 	"\x16GetUserDataItemRequest\x12\x1b\n" +
@@ -654,13 +1444,62 @@ const file_contracts_user_data_v1_proto_rawDesc = "" +
 	"\x05items\x18\x01 \x03(\v2\x1a.user.data.v1.UserDataItemR\x05items\";\n" +
 	"\x1aDeleteUserDataItemsRequest\x12\x1d\n" +
 	"\n" +
-	"item_uuids\x18\x01 \x03(\tR\titemUuids2\xff\x03\n" +
+	"item_uuids\x18\x01 \x03(\tR\titemUuids\"\xa9\x01\n" +
+	"\fItemRevision\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12.\n" +
+	"\x04item\x18\x02 \x01(\v2\x1a.user.data.v1.UserDataItemR\x04item\x12\x18\n" +
+	"\adeleted\x18\x03 \x01(\bR\adeleted\x12;\n" +
+	"\varchived_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"archivedAt\"7\n" +
+	"\x18ListItemRevisionsRequest\x12\x1b\n" +
+	"\titem_uuid\x18\x01 \x01(\tR\bitemUuid\"U\n" +
+	"\x19ListItemRevisionsResponse\x128\n" +
+	"\trevisions\x18\x01 \x03(\v2\x1a.user.data.v1.ItemRevisionR\trevisions\"^\n" +
+	"\x1aRestoreItemRevisionRequest\x12\x1b\n" +
+	"\titem_uuid\x18\x01 \x01(\tR\bitemUuid\x12#\n" +
+	"\rrevision_uuid\x18\x02 \x01(\tR\frevisionUuid\"M\n" +
+	"\x1bRestoreItemRevisionResponse\x12.\n" +
+	"\x04item\x18\x01 \x01(\v2\x1a.user.data.v1.UserDataItemR\x04item\";\n" +
+	"\x12ListChangesRequest\x12%\n" +
+	"\x0esince_revision\x18\x01 \x01(\x04R\rsinceRevision\"\xa0\x01\n" +
+	"\x13ListChangesResponse\x124\n" +
+	"\aupserts\x18\x01 \x03(\v2\x1a.user.data.v1.UserDataItemR\aupserts\x12#\n" +
+	"\rdeleted_uuids\x18\x02 \x03(\tR\fdeletedUuids\x12\x1a\n" +
+	"\brevision\x18\x03 \x01(\x04R\brevision\x12\x12\n" +
+	"\x04full\x18\x04 \x01(\bR\x04full\"<\n" +
+	"\x13WatchChangesRequest\x12%\n" +
+	"\x0esince_revision\x18\x01 \x01(\x04R\rsinceRevision\"l\n" +
+	"\x0fSyncBatchUpsert\x12.\n" +
+	"\x04item\x18\x01 \x01(\v2\x1a.user.data.v1.UserDataItemR\x04item\x12)\n" +
+	"\x10expected_version\x18\x02 \x01(\x04R\x0fexpectedVersion\"\x89\x01\n" +
+	"\x10SyncBatchRequest\x12\x19\n" +
+	"\bbatch_id\x18\x01 \x01(\tR\abatchId\x127\n" +
+	"\aupserts\x18\x02 \x03(\v2\x1d.user.data.v1.SyncBatchUpsertR\aupserts\x12!\n" +
+	"\fdelete_uuids\x18\x03 \x03(\tR\vdeleteUuids\"\xf1\x01\n" +
+	"\x13SyncBatchItemResult\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12@\n" +
+	"\x06status\x18\x02 \x01(\x0e2(.user.data.v1.SyncBatchItemResult.StatusR\x06status\x12.\n" +
+	"\x04item\x18\x03 \x01(\v2\x1a.user.data.v1.UserDataItemR\x04item\x12\x18\n" +
+	"\adeleted\x18\x04 \x01(\bR\adeleted\":\n" +
+	"\x06Status\x12\v\n" +
+	"\aAPPLIED\x10\x00\x12\x14\n" +
+	"\x10VERSION_CONFLICT\x10\x01\x12\r\n" +
+	"\tNOT_FOUND\x10\x02\"\x88\x01\n" +
+	"\x11SyncBatchResponse\x12;\n" +
+	"\aresults\x18\x01 \x03(\v2!.user.data.v1.SyncBatchItemResultR\aresults\x12\x1a\n" +
+	"\brevision\x18\x02 \x01(\x04R\brevision\x12\x1a\n" +
+	"\breplayed\x18\x03 \x01(\bR\breplayed2\xcb\a\n" +
 	"\x0fUserDataService\x12g\n" +
 	"\x12CreateUserDataItem\x12'.user.data.v1.CreateUserDataItemRequest\x1a(.user.data.v1.CreateUserDataItemResponse\x12g\n" +
 	"\x12UpdateUserDataItem\x12'.user.data.v1.UpdateUserDataItemRequest\x1a(.user.data.v1.UpdateUserDataItemResponse\x12^\n" +
 	"\x0fGetUserDataItem\x12$.user.data.v1.GetUserDataItemRequest\x1a%.user.data.v1.GetUserDataItemResponse\x12a\n" +
 	"\x10GetUserDataItems\x12%.user.data.v1.GetUserDataItemsRequest\x1a&.user.data.v1.GetUserDataItemsResponse\x12W\n" +
-	"\x13DeleteUserDataItems\x12(.user.data.v1.DeleteUserDataItemsRequest\x1a\x16.google.protobuf.EmptyB\x1cZ\x1ainternal/contracts/v1/datab\x06proto3"
+	"\x13DeleteUserDataItems\x12(.user.data.v1.DeleteUserDataItemsRequest\x1a\x16.google.protobuf.Empty\x12d\n" +
+	"\x11ListItemRevisions\x12&.user.data.v1.ListItemRevisionsRequest\x1a'.user.data.v1.ListItemRevisionsResponse\x12j\n" +
+	"\x13RestoreItemRevision\x12(.user.data.v1.RestoreItemRevisionRequest\x1a).user.data.v1.RestoreItemRevisionResponse\x12R\n" +
+	"\vListChanges\x12 .user.data.v1.ListChangesRequest\x1a!.user.data.v1.ListChangesResponse\x12V\n" +
+	"\fWatchChanges\x12!.user.data.v1.WatchChangesRequest\x1a!.user.data.v1.ListChangesResponse0\x01\x12L\n" +
+	"\tSyncBatch\x12\x1e.user.data.v1.SyncBatchRequest\x1a\x1f.user.data.v1.SyncBatchResponseB\x1cZ\x1ainternal/contracts/v1/datab\x06proto3"
 
 var (
 	file_contracts_user_data_v1_proto_rawDescOnce sync.Once
@@ -674,50 +1513,85 @@ func file_contracts_user_data_v1_proto_rawDescGZIP() []byte {
 	return file_contracts_user_data_v1_proto_rawDescData
 }
 
-var file_contracts_user_data_v1_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_contracts_user_data_v1_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_contracts_user_data_v1_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_contracts_user_data_v1_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
 var file_contracts_user_data_v1_proto_goTypes = []any{
-	(UserDataItem_DataType)(0),         // 0: user.data.v1.UserDataItem.DataType
-	(*MetaData)(nil),                   // 1: user.data.v1.MetaData
-	(*UserDataItem)(nil),               // 2: user.data.v1.UserDataItem
-	(*CreateUserDataItemRequest)(nil),  // 3: user.data.v1.CreateUserDataItemRequest
-	(*CreateUserDataItemResponse)(nil), // 4: user.data.v1.CreateUserDataItemResponse
-	(*UpdateUserDataItemRequest)(nil),  // 5: user.data.v1.UpdateUserDataItemRequest
-	(*UpdateUserDataItemResponse)(nil), // 6: user.data.v1.UpdateUserDataItemResponse
-	(*GetUserDataItemRequest)(nil),     // 7: user.data.v1.GetUserDataItemRequest
-	(*GetUserDataItemResponse)(nil),    // 8: user.data.v1.GetUserDataItemResponse
-	(*GetUserDataItemsRequest)(nil),    // 9: user.data.v1.GetUserDataItemsRequest
-	(*GetUserDataItemsResponse)(nil),   // 10: user.data.v1.GetUserDataItemsResponse
-	(*DeleteUserDataItemsRequest)(nil), // 11: user.data.v1.DeleteUserDataItemsRequest
-	(*timestamppb.Timestamp)(nil),      // 12: google.protobuf.Timestamp
-	(*emptypb.Empty)(nil),              // 13: google.protobuf.Empty
+	(UserDataItem_DataType)(0),          // 0: user.data.v1.UserDataItem.DataType
+	(SyncBatchItemResult_Status)(0),     // 1: user.data.v1.SyncBatchItemResult.Status
+	(*MetaData)(nil),                    // 2: user.data.v1.MetaData
+	(*UserDataItem)(nil),                // 3: user.data.v1.UserDataItem
+	(*CreateUserDataItemRequest)(nil),   // 4: user.data.v1.CreateUserDataItemRequest
+	(*CreateUserDataItemResponse)(nil),  // 5: user.data.v1.CreateUserDataItemResponse
+	(*UpdateUserDataItemRequest)(nil),   // 6: user.data.v1.UpdateUserDataItemRequest
+	(*VersionConflict)(nil),             // 7: user.data.v1.VersionConflict
+	(*UpdateUserDataItemResponse)(nil),  // 8: user.data.v1.UpdateUserDataItemResponse
+	(*GetUserDataItemRequest)(nil),      // 9: user.data.v1.GetUserDataItemRequest
+	(*GetUserDataItemResponse)(nil),     // 10: user.data.v1.GetUserDataItemResponse
+	(*GetUserDataItemsRequest)(nil),     // 11: user.data.v1.GetUserDataItemsRequest
+	(*GetUserDataItemsResponse)(nil),    // 12: user.data.v1.GetUserDataItemsResponse
+	(*DeleteUserDataItemsRequest)(nil),  // 13: user.data.v1.DeleteUserDataItemsRequest
+	(*ItemRevision)(nil),                // 14: user.data.v1.ItemRevision
+	(*ListItemRevisionsRequest)(nil),    // 15: user.data.v1.ListItemRevisionsRequest
+	(*ListItemRevisionsResponse)(nil),   // 16: user.data.v1.ListItemRevisionsResponse
+	(*RestoreItemRevisionRequest)(nil),  // 17: user.data.v1.RestoreItemRevisionRequest
+	(*RestoreItemRevisionResponse)(nil), // 18: user.data.v1.RestoreItemRevisionResponse
+	(*ListChangesRequest)(nil),          // 19: user.data.v1.ListChangesRequest
+	(*ListChangesResponse)(nil),         // 20: user.data.v1.ListChangesResponse
+	(*WatchChangesRequest)(nil),         // 21: user.data.v1.WatchChangesRequest
+	(*SyncBatchUpsert)(nil),             // 22: user.data.v1.SyncBatchUpsert
+	(*SyncBatchRequest)(nil),            // 23: user.data.v1.SyncBatchRequest
+	(*SyncBatchItemResult)(nil),         // 24: user.data.v1.SyncBatchItemResult
+	(*SyncBatchResponse)(nil),           // 25: user.data.v1.SyncBatchResponse
+	(*timestamppb.Timestamp)(nil),       // 26: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),               // 27: google.protobuf.Empty
 }
 var file_contracts_user_data_v1_proto_depIdxs = []int32{
 	0,  // 0: user.data.v1.UserDataItem.type:type_name -> user.data.v1.UserDataItem.DataType
-	1,  // 1: user.data.v1.UserDataItem.metadata:type_name -> user.data.v1.MetaData
-	12, // 2: user.data.v1.UserDataItem.created_at:type_name -> google.protobuf.Timestamp
-	12, // 3: user.data.v1.UserDataItem.updated_at:type_name -> google.protobuf.Timestamp
-	2,  // 4: user.data.v1.CreateUserDataItemRequest.item:type_name -> user.data.v1.UserDataItem
-	2,  // 5: user.data.v1.CreateUserDataItemResponse.item:type_name -> user.data.v1.UserDataItem
-	2,  // 6: user.data.v1.UpdateUserDataItemRequest.item:type_name -> user.data.v1.UserDataItem
-	2,  // 7: user.data.v1.UpdateUserDataItemResponse.item:type_name -> user.data.v1.UserDataItem
-	2,  // 8: user.data.v1.GetUserDataItemResponse.item:type_name -> user.data.v1.UserDataItem
-	2,  // 9: user.data.v1.GetUserDataItemsResponse.items:type_name -> user.data.v1.UserDataItem
-	3,  // 10: user.data.v1.UserDataService.CreateUserDataItem:input_type -> user.data.v1.CreateUserDataItemRequest
-	5,  // 11: user.data.v1.UserDataService.UpdateUserDataItem:input_type -> user.data.v1.UpdateUserDataItemRequest
-	7,  // 12: user.data.v1.UserDataService.GetUserDataItem:input_type -> user.data.v1.GetUserDataItemRequest
-	9,  // 13: user.data.v1.UserDataService.GetUserDataItems:input_type -> user.data.v1.GetUserDataItemsRequest
-	11, // 14: user.data.v1.UserDataService.DeleteUserDataItems:input_type -> user.data.v1.DeleteUserDataItemsRequest
-	4,  // 15: user.data.v1.UserDataService.CreateUserDataItem:output_type -> user.data.v1.CreateUserDataItemResponse
-	6,  // 16: user.data.v1.UserDataService.UpdateUserDataItem:output_type -> user.data.v1.UpdateUserDataItemResponse
-	8,  // 17: user.data.v1.UserDataService.GetUserDataItem:output_type -> user.data.v1.GetUserDataItemResponse
-	10, // 18: user.data.v1.UserDataService.GetUserDataItems:output_type -> user.data.v1.GetUserDataItemsResponse
-	13, // 19: user.data.v1.UserDataService.DeleteUserDataItems:output_type -> google.protobuf.Empty
-	15, // [15:20] is the sub-list for method output_type
-	10, // [10:15] is the sub-list for method input_type
-	10, // [10:10] is the sub-list for extension type_name
-	10, // [10:10] is the sub-list for extension extendee
-	0,  // [0:10] is the sub-list for field type_name
+	2,  // 1: user.data.v1.UserDataItem.metadata:type_name -> user.data.v1.MetaData
+	26, // 2: user.data.v1.UserDataItem.created_at:type_name -> google.protobuf.Timestamp
+	26, // 3: user.data.v1.UserDataItem.updated_at:type_name -> google.protobuf.Timestamp
+	3,  // 4: user.data.v1.CreateUserDataItemRequest.item:type_name -> user.data.v1.UserDataItem
+	3,  // 5: user.data.v1.CreateUserDataItemResponse.item:type_name -> user.data.v1.UserDataItem
+	3,  // 6: user.data.v1.UpdateUserDataItemRequest.item:type_name -> user.data.v1.UserDataItem
+	3,  // 7: user.data.v1.VersionConflict.current:type_name -> user.data.v1.UserDataItem
+	3,  // 8: user.data.v1.UpdateUserDataItemResponse.item:type_name -> user.data.v1.UserDataItem
+	3,  // 9: user.data.v1.GetUserDataItemResponse.item:type_name -> user.data.v1.UserDataItem
+	3,  // 10: user.data.v1.GetUserDataItemsResponse.items:type_name -> user.data.v1.UserDataItem
+	3,  // 11: user.data.v1.ItemRevision.item:type_name -> user.data.v1.UserDataItem
+	26, // 12: user.data.v1.ItemRevision.archived_at:type_name -> google.protobuf.Timestamp
+	14, // 13: user.data.v1.ListItemRevisionsResponse.revisions:type_name -> user.data.v1.ItemRevision
+	3,  // 14: user.data.v1.RestoreItemRevisionResponse.item:type_name -> user.data.v1.UserDataItem
+	3,  // 15: user.data.v1.ListChangesResponse.upserts:type_name -> user.data.v1.UserDataItem
+	3,  // 16: user.data.v1.SyncBatchUpsert.item:type_name -> user.data.v1.UserDataItem
+	22, // 17: user.data.v1.SyncBatchRequest.upserts:type_name -> user.data.v1.SyncBatchUpsert
+	1,  // 18: user.data.v1.SyncBatchItemResult.status:type_name -> user.data.v1.SyncBatchItemResult.Status
+	3,  // 19: user.data.v1.SyncBatchItemResult.item:type_name -> user.data.v1.UserDataItem
+	24, // 20: user.data.v1.SyncBatchResponse.results:type_name -> user.data.v1.SyncBatchItemResult
+	4,  // 21: user.data.v1.UserDataService.CreateUserDataItem:input_type -> user.data.v1.CreateUserDataItemRequest
+	6,  // 22: user.data.v1.UserDataService.UpdateUserDataItem:input_type -> user.data.v1.UpdateUserDataItemRequest
+	9,  // 23: user.data.v1.UserDataService.GetUserDataItem:input_type -> user.data.v1.GetUserDataItemRequest
+	11, // 24: user.data.v1.UserDataService.GetUserDataItems:input_type -> user.data.v1.GetUserDataItemsRequest
+	13, // 25: user.data.v1.UserDataService.DeleteUserDataItems:input_type -> user.data.v1.DeleteUserDataItemsRequest
+	15, // 26: user.data.v1.UserDataService.ListItemRevisions:input_type -> user.data.v1.ListItemRevisionsRequest
+	17, // 27: user.data.v1.UserDataService.RestoreItemRevision:input_type -> user.data.v1.RestoreItemRevisionRequest
+	19, // 28: user.data.v1.UserDataService.ListChanges:input_type -> user.data.v1.ListChangesRequest
+	21, // 29: user.data.v1.UserDataService.WatchChanges:input_type -> user.data.v1.WatchChangesRequest
+	23, // 30: user.data.v1.UserDataService.SyncBatch:input_type -> user.data.v1.SyncBatchRequest
+	5,  // 31: user.data.v1.UserDataService.CreateUserDataItem:output_type -> user.data.v1.CreateUserDataItemResponse
+	8,  // 32: user.data.v1.UserDataService.UpdateUserDataItem:output_type -> user.data.v1.UpdateUserDataItemResponse
+	10, // 33: user.data.v1.UserDataService.GetUserDataItem:output_type -> user.data.v1.GetUserDataItemResponse
+	12, // 34: user.data.v1.UserDataService.GetUserDataItems:output_type -> user.data.v1.GetUserDataItemsResponse
+	27, // 35: user.data.v1.UserDataService.DeleteUserDataItems:output_type -> google.protobuf.Empty
+	16, // 36: user.data.v1.UserDataService.ListItemRevisions:output_type -> user.data.v1.ListItemRevisionsResponse
+	18, // 37: user.data.v1.UserDataService.RestoreItemRevision:output_type -> user.data.v1.RestoreItemRevisionResponse
+	20, // 38: user.data.v1.UserDataService.ListChanges:output_type -> user.data.v1.ListChangesResponse
+	20, // 39: user.data.v1.UserDataService.WatchChanges:output_type -> user.data.v1.ListChangesResponse
+	25, // 40: user.data.v1.UserDataService.SyncBatch:output_type -> user.data.v1.SyncBatchResponse
+	31, // [31:41] is the sub-list for method output_type
+	21, // [21:31] is the sub-list for method input_type
+	21, // [21:21] is the sub-list for extension type_name
+	21, // [21:21] is the sub-list for extension extendee
+	0,  // [0:21] is the sub-list for field type_name
 }
 
 func init() { file_contracts_user_data_v1_proto_init() }
@@ -730,8 +1604,8 @@ func file_contracts_user_data_v1_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_contracts_user_data_v1_proto_rawDesc), len(file_contracts_user_data_v1_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   11,
+			NumEnums:      2,
+			NumMessages:   24,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -25,6 +25,11 @@ const (
 	UserDataService_GetUserDataItem_FullMethodName     = "/user.data.v1.UserDataService/GetUserDataItem"
 	UserDataService_GetUserDataItems_FullMethodName    = "/user.data.v1.UserDataService/GetUserDataItems"
 	UserDataService_DeleteUserDataItems_FullMethodName = "/user.data.v1.UserDataService/DeleteUserDataItems"
+	UserDataService_ListItemRevisions_FullMethodName   = "/user.data.v1.UserDataService/ListItemRevisions"
+	UserDataService_RestoreItemRevision_FullMethodName = "/user.data.v1.UserDataService/RestoreItemRevision"
+	UserDataService_ListChanges_FullMethodName         = "/user.data.v1.UserDataService/ListChanges"
+	UserDataService_WatchChanges_FullMethodName        = "/user.data.v1.UserDataService/WatchChanges"
+	UserDataService_SyncBatch_FullMethodName           = "/user.data.v1.UserDataService/SyncBatch"
 )
 
 // UserDataServiceClient is the client API for UserDataService service.
@@ -36,6 +41,11 @@ type UserDataServiceClient interface {
 	GetUserDataItem(ctx context.Context, in *GetUserDataItemRequest, opts ...grpc.CallOption) (*GetUserDataItemResponse, error)
 	GetUserDataItems(ctx context.Context, in *GetUserDataItemsRequest, opts ...grpc.CallOption) (*GetUserDataItemsResponse, error)
 	DeleteUserDataItems(ctx context.Context, in *DeleteUserDataItemsRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListItemRevisions(ctx context.Context, in *ListItemRevisionsRequest, opts ...grpc.CallOption) (*ListItemRevisionsResponse, error)
+	RestoreItemRevision(ctx context.Context, in *RestoreItemRevisionRequest, opts ...grpc.CallOption) (*RestoreItemRevisionResponse, error)
+	ListChanges(ctx context.Context, in *ListChangesRequest, opts ...grpc.CallOption) (*ListChangesResponse, error)
+	WatchChanges(ctx context.Context, in *WatchChangesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ListChangesResponse], error)
+	SyncBatch(ctx context.Context, in *SyncBatchRequest, opts ...grpc.CallOption) (*SyncBatchResponse, error)
 }
 
 type userDataServiceClient struct {
@@ -96,6 +106,65 @@ func (c *userDataServiceClient) DeleteUserDataItems(ctx context.Context, in *Del
 	return out, nil
 }
 
+func (c *userDataServiceClient) ListItemRevisions(ctx context.Context, in *ListItemRevisionsRequest, opts ...grpc.CallOption) (*ListItemRevisionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListItemRevisionsResponse)
+	err := c.cc.Invoke(ctx, UserDataService_ListItemRevisions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userDataServiceClient) RestoreItemRevision(ctx context.Context, in *RestoreItemRevisionRequest, opts ...grpc.CallOption) (*RestoreItemRevisionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestoreItemRevisionResponse)
+	err := c.cc.Invoke(ctx, UserDataService_RestoreItemRevision_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userDataServiceClient) ListChanges(ctx context.Context, in *ListChangesRequest, opts ...grpc.CallOption) (*ListChangesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListChangesResponse)
+	err := c.cc.Invoke(ctx, UserDataService_ListChanges_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userDataServiceClient) WatchChanges(ctx context.Context, in *WatchChangesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ListChangesResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &UserDataService_ServiceDesc.Streams[0], UserDataService_WatchChanges_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchChangesRequest, ListChangesResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UserDataService_WatchChangesClient = grpc.ServerStreamingClient[ListChangesResponse]
+
+func (c *userDataServiceClient) SyncBatch(ctx context.Context, in *SyncBatchRequest, opts ...grpc.CallOption) (*SyncBatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SyncBatchResponse)
+	err := c.cc.Invoke(ctx, UserDataService_SyncBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // UserDataServiceServer is the server API for UserDataService service.
 // All implementations must embed UnimplementedUserDataServiceServer
 // for forward compatibility.
@@ -105,6 +174,11 @@ type UserDataServiceServer interface {
 	GetUserDataItem(context.Context, *GetUserDataItemRequest) (*GetUserDataItemResponse, error)
 	GetUserDataItems(context.Context, *GetUserDataItemsRequest) (*GetUserDataItemsResponse, error)
 	DeleteUserDataItems(context.Context, *DeleteUserDataItemsRequest) (*emptypb.Empty, error)
+	ListItemRevisions(context.Context, *ListItemRevisionsRequest) (*ListItemRevisionsResponse, error)
+	RestoreItemRevision(context.Context, *RestoreItemRevisionRequest) (*RestoreItemRevisionResponse, error)
+	ListChanges(context.Context, *ListChangesRequest) (*ListChangesResponse, error)
+	WatchChanges(*WatchChangesRequest, grpc.ServerStreamingServer[ListChangesResponse]) error
+	SyncBatch(context.Context, *SyncBatchRequest) (*SyncBatchResponse, error)
 	mustEmbedUnimplementedUserDataServiceServer()
 }
 
@@ -130,6 +204,21 @@ func (UnimplementedUserDataServiceServer) GetUserDataItems(context.Context, *Get
 func (UnimplementedUserDataServiceServer) DeleteUserDataItems(context.Context, *DeleteUserDataItemsRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteUserDataItems not implemented")
 }
+func (UnimplementedUserDataServiceServer) ListItemRevisions(context.Context, *ListItemRevisionsRequest) (*ListItemRevisionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListItemRevisions not implemented")
+}
+func (UnimplementedUserDataServiceServer) RestoreItemRevision(context.Context, *RestoreItemRevisionRequest) (*RestoreItemRevisionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreItemRevision not implemented")
+}
+func (UnimplementedUserDataServiceServer) ListChanges(context.Context, *ListChangesRequest) (*ListChangesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListChanges not implemented")
+}
+func (UnimplementedUserDataServiceServer) WatchChanges(*WatchChangesRequest, grpc.ServerStreamingServer[ListChangesResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchChanges not implemented")
+}
+func (UnimplementedUserDataServiceServer) SyncBatch(context.Context, *SyncBatchRequest) (*SyncBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SyncBatch not implemented")
+}
 func (UnimplementedUserDataServiceServer) mustEmbedUnimplementedUserDataServiceServer() {}
 func (UnimplementedUserDataServiceServer) testEmbeddedByValue()                         {}
 
@@ -241,6 +330,89 @@ func _UserDataService_DeleteUserDataItems_Handler(srv interface{}, ctx context.C
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UserDataService_ListItemRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListItemRevisionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserDataServiceServer).ListItemRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserDataService_ListItemRevisions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserDataServiceServer).ListItemRevisions(ctx, req.(*ListItemRevisionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserDataService_RestoreItemRevision_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreItemRevisionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserDataServiceServer).RestoreItemRevision(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserDataService_RestoreItemRevision_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserDataServiceServer).RestoreItemRevision(ctx, req.(*RestoreItemRevisionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserDataService_ListChanges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListChangesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserDataServiceServer).ListChanges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserDataService_ListChanges_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserDataServiceServer).ListChanges(ctx, req.(*ListChangesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserDataService_WatchChanges_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchChangesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(UserDataServiceServer).WatchChanges(m, &grpc.GenericServerStream[WatchChangesRequest, ListChangesResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UserDataService_WatchChangesServer = grpc.ServerStreamingServer[ListChangesResponse]
+
+func _UserDataService_SyncBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SyncBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserDataServiceServer).SyncBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserDataService_SyncBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserDataServiceServer).SyncBatch(ctx, req.(*SyncBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // UserDataService_ServiceDesc is the grpc.ServiceDesc for UserDataService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -268,7 +440,29 @@ var UserDataService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteUserDataItems",
 			Handler:    _UserDataService_DeleteUserDataItems_Handler,
 		},
+		{
+			MethodName: "ListItemRevisions",
+			Handler:    _UserDataService_ListItemRevisions_Handler,
+		},
+		{
+			MethodName: "RestoreItemRevision",
+			Handler:    _UserDataService_RestoreItemRevision_Handler,
+		},
+		{
+			MethodName: "ListChanges",
+			Handler:    _UserDataService_ListChanges_Handler,
+		},
+		{
+			MethodName: "SyncBatch",
+			Handler:    _UserDataService_SyncBatch_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchChanges",
+			Handler:       _UserDataService_WatchChanges_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "contracts/user_data.v1.proto",
 }
@@ -0,0 +1,34 @@
+package mapper
+
+import (
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/ktigay/goph-keeper/internal/contracts/v1/session"
+	"github.com/ktigay/goph-keeper/internal/entity"
+)
+
+// MapSessionToEntity мапит [session.Session] в [entity.SessionInfo].
+func MapSessionToEntity(s *session.Session) entity.SessionInfo {
+	return entity.SessionInfo{
+		UUID:          s.GetUuid(),
+		DeviceName:    s.GetDeviceName(),
+		ClientVersion: s.GetClientVersion(),
+		IP:            s.GetIp(),
+		CreatedAt:     s.GetCreatedAt().AsTime(),
+		LastSeenAt:    s.GetLastSeenAt().AsTime(),
+		Current:       s.GetCurrent(),
+	}
+}
+
+// MapEntityToSession мапит [entity.SessionInfo] в [session.Session].
+func MapEntityToSession(e entity.SessionInfo) *session.Session {
+	return &session.Session{
+		Uuid:          e.UUID,
+		DeviceName:    e.DeviceName,
+		ClientVersion: e.ClientVersion,
+		Ip:            e.IP,
+		CreatedAt:     timestamppb.New(e.CreatedAt),
+		LastSeenAt:    timestamppb.New(e.LastSeenAt),
+		Current:       e.Current,
+	}
+}
@@ -0,0 +1,80 @@
+package mapper
+
+import (
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/ktigay/goph-keeper/internal/contracts/v1/vault"
+	"github.com/ktigay/goph-keeper/internal/entity"
+)
+
+// MapKeyToEntity мапит [vault.VaultKey] в [entity.VaultKey].
+func MapKeyToEntity(key *vault.VaultKey) entity.VaultKey {
+	return entity.VaultKey{
+		Salt:       key.GetSalt(),
+		WrappedKey: key.GetWrappedKey(),
+		KeyVersion: key.GetKeyVersion(),
+		KDF: entity.KDFParams{
+			Time:    key.GetKdf().GetTime(),
+			Memory:  key.GetKdf().GetMemory(),
+			Threads: uint8(min(key.GetKdf().GetThreads(), 255)),
+		},
+		WrappedPreviousKey: key.GetWrappedPreviousKey(),
+		WrappedTagKey:      key.GetWrappedTagKey(),
+	}
+}
+
+// MapEntityToKey мапит [entity.VaultKey] в [vault.VaultKey].
+func MapEntityToKey(e entity.VaultKey) *vault.VaultKey {
+	return &vault.VaultKey{
+		Salt:       e.Salt,
+		WrappedKey: e.WrappedKey,
+		KeyVersion: e.KeyVersion,
+		Kdf: &vault.KdfParams{
+			Time:    e.KDF.Time,
+			Memory:  e.KDF.Memory,
+			Threads: uint32(e.KDF.Threads),
+		},
+		WrappedPreviousKey: e.WrappedPreviousKey,
+		WrappedTagKey:      e.WrappedTagKey,
+	}
+}
+
+// MapRotatedItemToEntity мапит [vault.RotatedItem] в [entity.UserData].
+func MapRotatedItemToEntity(item *vault.RotatedItem) entity.UserData {
+	md := make([]entity.MetaData, len(item.GetMetadata()))
+	for i, m := range item.GetMetadata() {
+		md[i] = entity.MetaData{
+			Title: m.GetTitle(),
+			Value: m.GetValue(),
+		}
+	}
+	return entity.UserData{
+		UUID:      item.GetUuid(),
+		Title:     item.GetTitle(),
+		Data:      item.GetData(),
+		MetaData:  md,
+		UpdatedAt: item.GetUpdatedAt().AsTime(),
+		Version:   item.GetVersion(),
+		TagMACs:   item.GetTagMacs(),
+	}
+}
+
+// MapEntityToRotatedItem мапит [entity.UserData] в [vault.RotatedItem].
+func MapEntityToRotatedItem(d entity.UserData) *vault.RotatedItem {
+	md := make([]*vault.MetaData, len(d.MetaData))
+	for i, m := range d.MetaData {
+		md[i] = &vault.MetaData{
+			Title: m.Title,
+			Value: m.Value,
+		}
+	}
+	return &vault.RotatedItem{
+		Uuid:      d.UUID,
+		Title:     d.Title,
+		Data:      d.Data,
+		Metadata:  md,
+		UpdatedAt: timestamppb.New(d.UpdatedAt),
+		Version:   d.Version,
+		TagMacs:   d.TagMACs,
+	}
+}
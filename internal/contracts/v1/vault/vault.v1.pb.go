@@ -0,0 +1,804 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        v6.30.2
+// source: contracts/vault.v1.proto
+
+package vault
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type KdfParams struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Time          uint32                 `protobuf:"varint,1,opt,name=time,proto3" json:"time,omitempty"`
+	Memory        uint32                 `protobuf:"varint,2,opt,name=memory,proto3" json:"memory,omitempty"`
+	Threads       uint32                 `protobuf:"varint,3,opt,name=threads,proto3" json:"threads,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KdfParams) Reset() {
+	*x = KdfParams{}
+	mi := &file_contracts_vault_v1_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KdfParams) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KdfParams) ProtoMessage() {}
+
+func (x *KdfParams) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_vault_v1_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KdfParams.ProtoReflect.Descriptor instead.
+func (*KdfParams) Descriptor() ([]byte, []int) {
+	return file_contracts_vault_v1_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *KdfParams) GetTime() uint32 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *KdfParams) GetMemory() uint32 {
+	if x != nil {
+		return x.Memory
+	}
+	return 0
+}
+
+func (x *KdfParams) GetThreads() uint32 {
+	if x != nil {
+		return x.Threads
+	}
+	return 0
+}
+
+type VaultKey struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Salt               []byte                 `protobuf:"bytes,1,opt,name=salt,proto3" json:"salt,omitempty"`
+	WrappedKey         []byte                 `protobuf:"bytes,2,opt,name=wrapped_key,json=wrappedKey,proto3" json:"wrapped_key,omitempty"`
+	KeyVersion         uint32                 `protobuf:"varint,3,opt,name=key_version,json=keyVersion,proto3" json:"key_version,omitempty"`
+	Kdf                *KdfParams             `protobuf:"bytes,4,opt,name=kdf,proto3" json:"kdf,omitempty"`
+	WrappedPreviousKey []byte                 `protobuf:"bytes,5,opt,name=wrapped_previous_key,json=wrappedPreviousKey,proto3" json:"wrapped_previous_key,omitempty"`
+	WrappedTagKey      []byte                 `protobuf:"bytes,6,opt,name=wrapped_tag_key,json=wrappedTagKey,proto3" json:"wrapped_tag_key,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *VaultKey) Reset() {
+	*x = VaultKey{}
+	mi := &file_contracts_vault_v1_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VaultKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VaultKey) ProtoMessage() {}
+
+func (x *VaultKey) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_vault_v1_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VaultKey.ProtoReflect.Descriptor instead.
+func (*VaultKey) Descriptor() ([]byte, []int) {
+	return file_contracts_vault_v1_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *VaultKey) GetSalt() []byte {
+	if x != nil {
+		return x.Salt
+	}
+	return nil
+}
+
+func (x *VaultKey) GetWrappedKey() []byte {
+	if x != nil {
+		return x.WrappedKey
+	}
+	return nil
+}
+
+func (x *VaultKey) GetKeyVersion() uint32 {
+	if x != nil {
+		return x.KeyVersion
+	}
+	return 0
+}
+
+func (x *VaultKey) GetKdf() *KdfParams {
+	if x != nil {
+		return x.Kdf
+	}
+	return nil
+}
+
+func (x *VaultKey) GetWrappedPreviousKey() []byte {
+	if x != nil {
+		return x.WrappedPreviousKey
+	}
+	return nil
+}
+
+func (x *VaultKey) GetWrappedTagKey() []byte {
+	if x != nil {
+		return x.WrappedTagKey
+	}
+	return nil
+}
+
+type MetaData struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MetaData) Reset() {
+	*x = MetaData{}
+	mi := &file_contracts_vault_v1_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MetaData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetaData) ProtoMessage() {}
+
+func (x *MetaData) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_vault_v1_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetaData.ProtoReflect.Descriptor instead.
+func (*MetaData) Descriptor() ([]byte, []int) {
+	return file_contracts_vault_v1_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *MetaData) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *MetaData) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type RotatedItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Data          []byte                 `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	Metadata      []*MetaData            `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Version       uint64                 `protobuf:"varint,6,opt,name=version,proto3" json:"version,omitempty"`
+	TagMacs       [][]byte               `protobuf:"bytes,7,rep,name=tag_macs,json=tagMacs,proto3" json:"tag_macs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotatedItem) Reset() {
+	*x = RotatedItem{}
+	mi := &file_contracts_vault_v1_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotatedItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotatedItem) ProtoMessage() {}
+
+func (x *RotatedItem) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_vault_v1_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotatedItem.ProtoReflect.Descriptor instead.
+func (*RotatedItem) Descriptor() ([]byte, []int) {
+	return file_contracts_vault_v1_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RotatedItem) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *RotatedItem) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *RotatedItem) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *RotatedItem) GetMetadata() []*MetaData {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *RotatedItem) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *RotatedItem) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *RotatedItem) GetTagMacs() [][]byte {
+	if x != nil {
+		return x.TagMacs
+	}
+	return nil
+}
+
+type GetVaultKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetVaultKeyRequest) Reset() {
+	*x = GetVaultKeyRequest{}
+	mi := &file_contracts_vault_v1_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVaultKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVaultKeyRequest) ProtoMessage() {}
+
+func (x *GetVaultKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_vault_v1_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVaultKeyRequest.ProtoReflect.Descriptor instead.
+func (*GetVaultKeyRequest) Descriptor() ([]byte, []int) {
+	return file_contracts_vault_v1_proto_rawDescGZIP(), []int{4}
+}
+
+type GetVaultKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           *VaultKey              `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Rotation      *VaultKey              `protobuf:"bytes,2,opt,name=rotation,proto3" json:"rotation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetVaultKeyResponse) Reset() {
+	*x = GetVaultKeyResponse{}
+	mi := &file_contracts_vault_v1_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVaultKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVaultKeyResponse) ProtoMessage() {}
+
+func (x *GetVaultKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_vault_v1_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVaultKeyResponse.ProtoReflect.Descriptor instead.
+func (*GetVaultKeyResponse) Descriptor() ([]byte, []int) {
+	return file_contracts_vault_v1_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetVaultKeyResponse) GetKey() *VaultKey {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *GetVaultKeyResponse) GetRotation() *VaultKey {
+	if x != nil {
+		return x.Rotation
+	}
+	return nil
+}
+
+type CreateVaultKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           *VaultKey              `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateVaultKeyRequest) Reset() {
+	*x = CreateVaultKeyRequest{}
+	mi := &file_contracts_vault_v1_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateVaultKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateVaultKeyRequest) ProtoMessage() {}
+
+func (x *CreateVaultKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_vault_v1_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateVaultKeyRequest.ProtoReflect.Descriptor instead.
+func (*CreateVaultKeyRequest) Descriptor() ([]byte, []int) {
+	return file_contracts_vault_v1_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CreateVaultKeyRequest) GetKey() *VaultKey {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+type CreateVaultKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           *VaultKey              `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateVaultKeyResponse) Reset() {
+	*x = CreateVaultKeyResponse{}
+	mi := &file_contracts_vault_v1_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateVaultKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateVaultKeyResponse) ProtoMessage() {}
+
+func (x *CreateVaultKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_vault_v1_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateVaultKeyResponse.ProtoReflect.Descriptor instead.
+func (*CreateVaultKeyResponse) Descriptor() ([]byte, []int) {
+	return file_contracts_vault_v1_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CreateVaultKeyResponse) GetKey() *VaultKey {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+type BeginRotationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           *VaultKey              `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BeginRotationRequest) Reset() {
+	*x = BeginRotationRequest{}
+	mi := &file_contracts_vault_v1_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginRotationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginRotationRequest) ProtoMessage() {}
+
+func (x *BeginRotationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_vault_v1_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginRotationRequest.ProtoReflect.Descriptor instead.
+func (*BeginRotationRequest) Descriptor() ([]byte, []int) {
+	return file_contracts_vault_v1_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *BeginRotationRequest) GetKey() *VaultKey {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+type BeginRotationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           *VaultKey              `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BeginRotationResponse) Reset() {
+	*x = BeginRotationResponse{}
+	mi := &file_contracts_vault_v1_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginRotationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginRotationResponse) ProtoMessage() {}
+
+func (x *BeginRotationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_vault_v1_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginRotationResponse.ProtoReflect.Descriptor instead.
+func (*BeginRotationResponse) Descriptor() ([]byte, []int) {
+	return file_contracts_vault_v1_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *BeginRotationResponse) GetKey() *VaultKey {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+type CommitRotationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	KeyVersion    uint32                 `protobuf:"varint,1,opt,name=key_version,json=keyVersion,proto3" json:"key_version,omitempty"`
+	Items         []*RotatedItem         `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommitRotationRequest) Reset() {
+	*x = CommitRotationRequest{}
+	mi := &file_contracts_vault_v1_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommitRotationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitRotationRequest) ProtoMessage() {}
+
+func (x *CommitRotationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_vault_v1_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitRotationRequest.ProtoReflect.Descriptor instead.
+func (*CommitRotationRequest) Descriptor() ([]byte, []int) {
+	return file_contracts_vault_v1_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CommitRotationRequest) GetKeyVersion() uint32 {
+	if x != nil {
+		return x.KeyVersion
+	}
+	return 0
+}
+
+func (x *CommitRotationRequest) GetItems() []*RotatedItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type CommitRotationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           *VaultKey              `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommitRotationResponse) Reset() {
+	*x = CommitRotationResponse{}
+	mi := &file_contracts_vault_v1_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommitRotationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitRotationResponse) ProtoMessage() {}
+
+func (x *CommitRotationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_vault_v1_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitRotationResponse.ProtoReflect.Descriptor instead.
+func (*CommitRotationResponse) Descriptor() ([]byte, []int) {
+	return file_contracts_vault_v1_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CommitRotationResponse) GetKey() *VaultKey {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+var File_contracts_vault_v1_proto protoreflect.FileDescriptor
+
+const file_contracts_vault_v1_proto_rawDesc = "" +
+	"\n" +
+	"\x18contracts/vault.v1.proto\x12\ruser.vault.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"Q\n" +
+	"\tKdfParams\x12\x12\n" +
+	"\x04time\x18\x01 \x01(\rR\x04time\x12\x16\n" +
+	"\x06memory\x18\x02 \x01(\rR\x06memory\x12\x18\n" +
+	"\athreads\x18\x03 \x01(\rR\athreads\"\xe6\x01\n" +
+	"\bVaultKey\x12\x12\n" +
+	"\x04salt\x18\x01 \x01(\fR\x04salt\x12\x1f\n" +
+	"\vwrapped_key\x18\x02 \x01(\fR\n" +
+	"wrappedKey\x12\x1f\n" +
+	"\vkey_version\x18\x03 \x01(\rR\n" +
+	"keyVersion\x12*\n" +
+	"\x03kdf\x18\x04 \x01(\v2\x18.user.vault.v1.KdfParamsR\x03kdf\x120\n" +
+	"\x14wrapped_previous_key\x18\x05 \x01(\fR\x12wrappedPreviousKey\x12&\n" +
+	"\x0fwrapped_tag_key\x18\x06 \x01(\fR\rwrappedTagKey\"6\n" +
+	"\bMetaData\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\"\xf0\x01\n" +
+	"\vRotatedItem\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x12\n" +
+	"\x04data\x18\x03 \x01(\fR\x04data\x123\n" +
+	"\bmetadata\x18\x04 \x03(\v2\x17.user.vault.v1.MetaDataR\bmetadata\x129\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x18\n" +
+	"\aversion\x18\x06 \x01(\x04R\aversion\x12\x19\n" +
+	"\btag_macs\x18\a \x03(\fR\atagMacs\"\x14\n" +
+	"\x12GetVaultKeyRequest\"u\n" +
+	"\x13GetVaultKeyResponse\x12)\n" +
+	"\x03key\x18\x01 \x01(\v2\x17.user.vault.v1.VaultKeyR\x03key\x123\n" +
+	"\brotation\x18\x02 \x01(\v2\x17.user.vault.v1.VaultKeyR\brotation\"B\n" +
+	"\x15CreateVaultKeyRequest\x12)\n" +
+	"\x03key\x18\x01 \x01(\v2\x17.user.vault.v1.VaultKeyR\x03key\"C\n" +
+	"\x16CreateVaultKeyResponse\x12)\n" +
+	"\x03key\x18\x01 \x01(\v2\x17.user.vault.v1.VaultKeyR\x03key\"A\n" +
+	"\x14BeginRotationRequest\x12)\n" +
+	"\x03key\x18\x01 \x01(\v2\x17.user.vault.v1.VaultKeyR\x03key\"B\n" +
+	"\x15BeginRotationResponse\x12)\n" +
+	"\x03key\x18\x01 \x01(\v2\x17.user.vault.v1.VaultKeyR\x03key\"j\n" +
+	"\x15CommitRotationRequest\x12\x1f\n" +
+	"\vkey_version\x18\x01 \x01(\rR\n" +
+	"keyVersion\x120\n" +
+	"\x05items\x18\x02 \x03(\v2\x1a.user.vault.v1.RotatedItemR\x05items\"C\n" +
+	"\x16CommitRotationResponse\x12)\n" +
+	"\x03key\x18\x01 \x01(\v2\x17.user.vault.v1.VaultKeyR\x03key2\xfe\x02\n" +
+	"\fVaultService\x12T\n" +
+	"\vGetVaultKey\x12!.user.vault.v1.GetVaultKeyRequest\x1a\".user.vault.v1.GetVaultKeyResponse\x12]\n" +
+	"\x0eCreateVaultKey\x12$.user.vault.v1.CreateVaultKeyRequest\x1a%.user.vault.v1.CreateVaultKeyResponse\x12Z\n" +
+	"\rBeginRotation\x12#.user.vault.v1.BeginRotationRequest\x1a$.user.vault.v1.BeginRotationResponse\x12]\n" +
+	"\x0eCommitRotation\x12$.user.vault.v1.CommitRotationRequest\x1a%.user.vault.v1.CommitRotationResponseB\x1dZ\x1binternal/contracts/v1/vaultb\x06proto3"
+
+var (
+	file_contracts_vault_v1_proto_rawDescOnce sync.Once
+	file_contracts_vault_v1_proto_rawDescData []byte
+)
+
+func file_contracts_vault_v1_proto_rawDescGZIP() []byte {
+	file_contracts_vault_v1_proto_rawDescOnce.Do(func() {
+		file_contracts_vault_v1_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_contracts_vault_v1_proto_rawDesc), len(file_contracts_vault_v1_proto_rawDesc)))
+	})
+	return file_contracts_vault_v1_proto_rawDescData
+}
+
+var file_contracts_vault_v1_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_contracts_vault_v1_proto_goTypes = []any{
+	(*KdfParams)(nil),              // 0: user.vault.v1.KdfParams
+	(*VaultKey)(nil),               // 1: user.vault.v1.VaultKey
+	(*MetaData)(nil),               // 2: user.vault.v1.MetaData
+	(*RotatedItem)(nil),            // 3: user.vault.v1.RotatedItem
+	(*GetVaultKeyRequest)(nil),     // 4: user.vault.v1.GetVaultKeyRequest
+	(*GetVaultKeyResponse)(nil),    // 5: user.vault.v1.GetVaultKeyResponse
+	(*CreateVaultKeyRequest)(nil),  // 6: user.vault.v1.CreateVaultKeyRequest
+	(*CreateVaultKeyResponse)(nil), // 7: user.vault.v1.CreateVaultKeyResponse
+	(*BeginRotationRequest)(nil),   // 8: user.vault.v1.BeginRotationRequest
+	(*BeginRotationResponse)(nil),  // 9: user.vault.v1.BeginRotationResponse
+	(*CommitRotationRequest)(nil),  // 10: user.vault.v1.CommitRotationRequest
+	(*CommitRotationResponse)(nil), // 11: user.vault.v1.CommitRotationResponse
+	(*timestamppb.Timestamp)(nil),  // 12: google.protobuf.Timestamp
+}
+var file_contracts_vault_v1_proto_depIdxs = []int32{
+	0,  // 0: user.vault.v1.VaultKey.kdf:type_name -> user.vault.v1.KdfParams
+	2,  // 1: user.vault.v1.RotatedItem.metadata:type_name -> user.vault.v1.MetaData
+	12, // 2: user.vault.v1.RotatedItem.updated_at:type_name -> google.protobuf.Timestamp
+	1,  // 3: user.vault.v1.GetVaultKeyResponse.key:type_name -> user.vault.v1.VaultKey
+	1,  // 4: user.vault.v1.GetVaultKeyResponse.rotation:type_name -> user.vault.v1.VaultKey
+	1,  // 5: user.vault.v1.CreateVaultKeyRequest.key:type_name -> user.vault.v1.VaultKey
+	1,  // 6: user.vault.v1.CreateVaultKeyResponse.key:type_name -> user.vault.v1.VaultKey
+	1,  // 7: user.vault.v1.BeginRotationRequest.key:type_name -> user.vault.v1.VaultKey
+	1,  // 8: user.vault.v1.BeginRotationResponse.key:type_name -> user.vault.v1.VaultKey
+	3,  // 9: user.vault.v1.CommitRotationRequest.items:type_name -> user.vault.v1.RotatedItem
+	1,  // 10: user.vault.v1.CommitRotationResponse.key:type_name -> user.vault.v1.VaultKey
+	4,  // 11: user.vault.v1.VaultService.GetVaultKey:input_type -> user.vault.v1.GetVaultKeyRequest
+	6,  // 12: user.vault.v1.VaultService.CreateVaultKey:input_type -> user.vault.v1.CreateVaultKeyRequest
+	8,  // 13: user.vault.v1.VaultService.BeginRotation:input_type -> user.vault.v1.BeginRotationRequest
+	10, // 14: user.vault.v1.VaultService.CommitRotation:input_type -> user.vault.v1.CommitRotationRequest
+	5,  // 15: user.vault.v1.VaultService.GetVaultKey:output_type -> user.vault.v1.GetVaultKeyResponse
+	7,  // 16: user.vault.v1.VaultService.CreateVaultKey:output_type -> user.vault.v1.CreateVaultKeyResponse
+	9,  // 17: user.vault.v1.VaultService.BeginRotation:output_type -> user.vault.v1.BeginRotationResponse
+	11, // 18: user.vault.v1.VaultService.CommitRotation:output_type -> user.vault.v1.CommitRotationResponse
+	15, // [15:19] is the sub-list for method output_type
+	11, // [11:15] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_contracts_vault_v1_proto_init() }
+func file_contracts_vault_v1_proto_init() {
+	if File_contracts_vault_v1_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_contracts_vault_v1_proto_rawDesc), len(file_contracts_vault_v1_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_contracts_vault_v1_proto_goTypes,
+		DependencyIndexes: file_contracts_vault_v1_proto_depIdxs,
+		MessageInfos:      file_contracts_vault_v1_proto_msgTypes,
+	}.Build()
+	File_contracts_vault_v1_proto = out.File
+	file_contracts_vault_v1_proto_goTypes = nil
+	file_contracts_vault_v1_proto_depIdxs = nil
+}
@@ -0,0 +1,235 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.30.2
+// source: contracts/vault.v1.proto
+
+package vault
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	VaultService_GetVaultKey_FullMethodName    = "/user.vault.v1.VaultService/GetVaultKey"
+	VaultService_CreateVaultKey_FullMethodName = "/user.vault.v1.VaultService/CreateVaultKey"
+	VaultService_BeginRotation_FullMethodName  = "/user.vault.v1.VaultService/BeginRotation"
+	VaultService_CommitRotation_FullMethodName = "/user.vault.v1.VaultService/CommitRotation"
+)
+
+// VaultServiceClient is the client API for VaultService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type VaultServiceClient interface {
+	GetVaultKey(ctx context.Context, in *GetVaultKeyRequest, opts ...grpc.CallOption) (*GetVaultKeyResponse, error)
+	CreateVaultKey(ctx context.Context, in *CreateVaultKeyRequest, opts ...grpc.CallOption) (*CreateVaultKeyResponse, error)
+	BeginRotation(ctx context.Context, in *BeginRotationRequest, opts ...grpc.CallOption) (*BeginRotationResponse, error)
+	CommitRotation(ctx context.Context, in *CommitRotationRequest, opts ...grpc.CallOption) (*CommitRotationResponse, error)
+}
+
+type vaultServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewVaultServiceClient(cc grpc.ClientConnInterface) VaultServiceClient {
+	return &vaultServiceClient{cc}
+}
+
+func (c *vaultServiceClient) GetVaultKey(ctx context.Context, in *GetVaultKeyRequest, opts ...grpc.CallOption) (*GetVaultKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetVaultKeyResponse)
+	err := c.cc.Invoke(ctx, VaultService_GetVaultKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vaultServiceClient) CreateVaultKey(ctx context.Context, in *CreateVaultKeyRequest, opts ...grpc.CallOption) (*CreateVaultKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateVaultKeyResponse)
+	err := c.cc.Invoke(ctx, VaultService_CreateVaultKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vaultServiceClient) BeginRotation(ctx context.Context, in *BeginRotationRequest, opts ...grpc.CallOption) (*BeginRotationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BeginRotationResponse)
+	err := c.cc.Invoke(ctx, VaultService_BeginRotation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vaultServiceClient) CommitRotation(ctx context.Context, in *CommitRotationRequest, opts ...grpc.CallOption) (*CommitRotationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CommitRotationResponse)
+	err := c.cc.Invoke(ctx, VaultService_CommitRotation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// VaultServiceServer is the server API for VaultService service.
+// All implementations must embed UnimplementedVaultServiceServer
+// for forward compatibility.
+type VaultServiceServer interface {
+	GetVaultKey(context.Context, *GetVaultKeyRequest) (*GetVaultKeyResponse, error)
+	CreateVaultKey(context.Context, *CreateVaultKeyRequest) (*CreateVaultKeyResponse, error)
+	BeginRotation(context.Context, *BeginRotationRequest) (*BeginRotationResponse, error)
+	CommitRotation(context.Context, *CommitRotationRequest) (*CommitRotationResponse, error)
+	mustEmbedUnimplementedVaultServiceServer()
+}
+
+// UnimplementedVaultServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedVaultServiceServer struct{}
+
+func (UnimplementedVaultServiceServer) GetVaultKey(context.Context, *GetVaultKeyRequest) (*GetVaultKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVaultKey not implemented")
+}
+func (UnimplementedVaultServiceServer) CreateVaultKey(context.Context, *CreateVaultKeyRequest) (*CreateVaultKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateVaultKey not implemented")
+}
+func (UnimplementedVaultServiceServer) BeginRotation(context.Context, *BeginRotationRequest) (*BeginRotationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BeginRotation not implemented")
+}
+func (UnimplementedVaultServiceServer) CommitRotation(context.Context, *CommitRotationRequest) (*CommitRotationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CommitRotation not implemented")
+}
+func (UnimplementedVaultServiceServer) mustEmbedUnimplementedVaultServiceServer() {}
+func (UnimplementedVaultServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeVaultServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to VaultServiceServer will
+// result in compilation errors.
+type UnsafeVaultServiceServer interface {
+	mustEmbedUnimplementedVaultServiceServer()
+}
+
+func RegisterVaultServiceServer(s grpc.ServiceRegistrar, srv VaultServiceServer) {
+	// If the following call pancis, it indicates UnimplementedVaultServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&VaultService_ServiceDesc, srv)
+}
+
+func _VaultService_GetVaultKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVaultKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VaultServiceServer).GetVaultKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VaultService_GetVaultKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VaultServiceServer).GetVaultKey(ctx, req.(*GetVaultKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VaultService_CreateVaultKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateVaultKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VaultServiceServer).CreateVaultKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VaultService_CreateVaultKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VaultServiceServer).CreateVaultKey(ctx, req.(*CreateVaultKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VaultService_BeginRotation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BeginRotationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VaultServiceServer).BeginRotation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VaultService_BeginRotation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VaultServiceServer).BeginRotation(ctx, req.(*BeginRotationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VaultService_CommitRotation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommitRotationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VaultServiceServer).CommitRotation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VaultService_CommitRotation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VaultServiceServer).CommitRotation(ctx, req.(*CommitRotationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// VaultService_ServiceDesc is the grpc.ServiceDesc for VaultService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var VaultService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "user.vault.v1.VaultService",
+	HandlerType: (*VaultServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetVaultKey",
+			Handler:    _VaultService_GetVaultKey_Handler,
+		},
+		{
+			MethodName: "CreateVaultKey",
+			Handler:    _VaultService_CreateVaultKey_Handler,
+		},
+		{
+			MethodName: "BeginRotation",
+			Handler:    _VaultService_BeginRotation_Handler,
+		},
+		{
+			MethodName: "CommitRotation",
+			Handler:    _VaultService_CommitRotation_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "contracts/vault.v1.proto",
+}
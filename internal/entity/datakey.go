@@ -0,0 +1,8 @@
+package entity
+
+// DataKey ключ шифрования данных пользователя на сервере, зашифрованный мастер-ключом KEKID.
+type DataKey struct {
+	UserUUID   string
+	KEKID      string
+	WrappedKey []byte
+}
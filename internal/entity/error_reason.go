@@ -0,0 +1,32 @@
+package entity
+
+// ErrorDomain домен ошибок сервиса в errdetails.ErrorInfo.
+const ErrorDomain = "goph-keeper"
+
+// Причины ошибок (errdetails.ErrorInfo.Reason), общие для сервера и клиента.
+const (
+	// ReasonEmptyCredentials не заполнен логин или пароль.
+	ReasonEmptyCredentials = "EMPTY_CREDENTIALS"
+	// ReasonInvalidCredentials неверный логин или пароль, без уточнения, что именно.
+	ReasonInvalidCredentials = "INVALID_CREDENTIALS"
+	// ReasonWrongPassword неверный текущий пароль при изменении аккаунта.
+	ReasonWrongPassword = "WRONG_PASSWORD"
+	// ReasonLoginExists логин уже занят.
+	ReasonLoginExists = "LOGIN_EXISTS"
+	// ReasonInvalidSecondFactorCode неверный код второго фактора.
+	ReasonInvalidSecondFactorCode = "INVALID_SECOND_FACTOR_CODE"
+	// ReasonTooManyAttempts вход временно заблокирован.
+	ReasonTooManyAttempts = "TOO_MANY_ATTEMPTS"
+	// ReasonLegacyLoginDisabled вход по паролю отключен, нужен SRP.
+	ReasonLegacyLoginDisabled = "LEGACY_LOGIN_DISABLED"
+	// ReasonHandshakeExpired обмен SRP не найден или просрочен, его нужно начать заново.
+	ReasonHandshakeExpired = "SRP_HANDSHAKE_EXPIRED"
+	// ReasonMethodNotAllowed метод не описан в правилах доступа.
+	ReasonMethodNotAllowed = "METHOD_NOT_ALLOWED"
+	// ReasonRoleRequired у пользователя нет роли, необходимой для метода.
+	ReasonRoleRequired = "ROLE_REQUIRED"
+	// ReasonScopeRequired у токена нет разрешения, необходимого для метода.
+	ReasonScopeRequired = "SCOPE_REQUIRED"
+	// ReasonVersionConflict запись изменена после чтения клиентом.
+	ReasonVersionConflict = "VERSION_CONFLICT"
+)
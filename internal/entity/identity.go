@@ -1,6 +1,43 @@
 package entity
 
+// Role роль пользователя.
+type Role string
+
+// Роли пользователей.
+const (
+	// RoleUser обычный пользователь.
+	RoleUser Role = "user"
+	// RoleAdmin администратор.
+	RoleAdmin Role = "admin"
+)
+
+// Scope разрешение токена.
+type Scope string
+
+// Разрешения токенов.
+const (
+	// ScopeDataRead чтение записей.
+	ScopeDataRead Scope = "data:read"
+	// ScopeDataWrite создание, изменение и удаление записей.
+	ScopeDataWrite Scope = "data:write"
+	// ScopeVaultRead чтение ключа хранилища.
+	ScopeVaultRead Scope = "vault:read"
+	// ScopeAccount управление аккаунтом: пароль, второй фактор, сессии, токены, ротация ключа хранилища.
+	ScopeAccount Scope = "account"
+)
+
+// SessionScopes разрешения токена сессии, выданного при входе.
+func SessionScopes() []Scope {
+	return []Scope{ScopeDataRead, ScopeDataWrite, ScopeVaultRead, ScopeAccount}
+}
+
 // Identity Идентификатор пользователя.
 type Identity struct {
 	UUID string
+	// SessionUUID сессия, в рамках которой выпущен токен.
+	SessionUUID string
+	// Role роль пользователя.
+	Role Role
+	// Scopes разрешения токена.
+	Scopes []Scope
 }
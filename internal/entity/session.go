@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// SessionInfo сессия (устройство), с которой пользователь вошел в систему.
+type SessionInfo struct {
+	UUID          string
+	DeviceName    string
+	ClientVersion string
+	IP            string
+	CreatedAt     time.Time
+	LastSeenAt    time.Time
+	// Current сессия, из которой выполнен запрос.
+	Current bool
+}
@@ -17,18 +17,34 @@ var (
 	DataTypeBinary UserDataType = "BINARY"
 	// DataTypeCard кредитная карта.
 	DataTypeCard UserDataType = "CARD"
+	// DataTypeCredential логин/пароль.
+	DataTypeCredential UserDataType = "CREDENTIAL"
+	// DataTypeOTP секрет одноразовых паролей (TOTP/HOTP).
+	DataTypeOTP UserDataType = "OTP"
 	// DataTypes типы данных.
-	DataTypes = []UserDataType{DataTypeText, DataTypeBinary, DataTypeCard}
+	DataTypes = []UserDataType{DataTypeText, DataTypeBinary, DataTypeCard, DataTypeCredential, DataTypeOTP}
 )
 
 // UserData сущность пользовательских данных.
 type UserData struct {
-	UUID      string       `validate:"required_if=IsNew false,omitempty,uuid"`
-	UserUUID  string       `validate:"omitempty,uuid"`
-	Title     string       `validate:"required"`
-	Type      UserDataType `validate:"required"`
-	Data      []byte       `validate:"required"`
-	MetaData  []MetaData   `validate:"omitempty"`
+	UUID     string       `validate:"required_if=IsNew false,omitempty,uuid"`
+	UserUUID string       `validate:"omitempty,uuid"`
+	Title    string       `validate:"required"`
+	Type     UserDataType `validate:"required"`
+	Data     []byte       `validate:"required"`
+	MetaData []MetaData   `validate:"omitempty"`
+	// TagMACs HMAC-SHA256 тегов записи, вычисленные клиентом ключом тегов хранилища.
+	// Сервер по ним проверяет ограничение токена доступа, не зная самих тегов.
+	TagMACs [][]byte
+	// KeyVersion версия ключа, которым зашифрованы данные; 0 - не зашифрованы.
+	KeyVersion uint32
+	// Version версия записи на сервере, увеличивается при каждом изменении; 0 - запись еще не сохранена на сервере.
+	// При обновлении передается версия, на основе которой сделано изменение.
+	Version uint64
+	// Revision ревизия данных пользователя, в которой запись изменена последний раз.
+	Revision uint64
+	// Sealed данные и метаданные зашифрованы на сервере ключом данных пользователя.
+	Sealed    bool
 	IsSynced  bool
 	IsNew     bool
 	CreatedAt time.Time
@@ -51,18 +67,11 @@ func (u *UserData) SetData(d any) error {
 		}
 		u.Data = b
 	case DataTypeCard:
-		var (
-			dv  []byte
-			err error
-		)
-		s, ok := d.(UserDataCard)
-		if !ok {
-			return errors.New("invalid user data type")
-		}
-		if dv, err = json.Marshal(s); err != nil {
-			return err
-		}
-		u.Data = dv
+		return setJSONData[UserDataCard](u, d)
+	case DataTypeCredential:
+		return setJSONData[UserDataCredential](u, d)
+	case DataTypeOTP:
+		return setJSONData[UserDataOTP](u, d)
 	}
 	return nil
 }
@@ -75,16 +84,37 @@ func (u *UserData) GetData() any {
 	case DataTypeBinary:
 		return base64.StdEncoding.EncodeToString(u.Data)
 	case DataTypeCard:
-		c := UserDataCard{}
-		err := json.Unmarshal(u.Data, &c)
-		if err != nil {
-			return UserDataCard{}
-		}
-		return c
+		return getJSONData[UserDataCard](u.Data)
+	case DataTypeCredential:
+		return getJSONData[UserDataCredential](u.Data)
+	case DataTypeOTP:
+		return getJSONData[UserDataOTP](u.Data)
+	}
+	return nil
+}
+
+func setJSONData[T any](u *UserData, d any) error {
+	s, ok := d.(T)
+	if !ok {
+		return errors.New("invalid user data type")
 	}
+	dv, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	u.Data = dv
 	return nil
 }
 
+func getJSONData[T any](data []byte) T {
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		var empty T
+		return empty
+	}
+	return v
+}
+
 // UserDataCard стркутура типа [DataTypeCard].
 type UserDataCard struct {
 	Number   string `json:"number" validate:"required"`
@@ -93,6 +123,98 @@ type UserDataCard struct {
 	CVC      string `json:"cvc" validate:"required"`
 }
 
+// UserDataCredential структура типа [DataTypeCredential].
+type UserDataCredential struct {
+	Username string   `json:"username" validate:"required"`
+	Password string   `json:"password" validate:"required"`
+	URLs     []string `json:"urls" validate:"omitempty,dive,url"`
+	Notes    string   `json:"notes"`
+}
+
+// UserDataOTP структура типа [DataTypeOTP].
+type UserDataOTP struct {
+	Type      string `json:"type" validate:"required,oneof=TOTP HOTP"`
+	Secret    string `json:"secret" validate:"required"`
+	Issuer    string `json:"issuer"`
+	Account   string `json:"account"`
+	Algorithm string `json:"algorithm" validate:"required,oneof=SHA1 SHA256 SHA512"`
+	Digits    int    `json:"digits" validate:"required,min=6,max=8"`
+	Period    int    `json:"period" validate:"required_if=Type TOTP,omitempty,min=1"`
+	Counter   uint64 `json:"counter"`
+}
+
+// UserDataRevision предыдущая версия записи, сохраняется при изменении и удалении.
+type UserDataRevision struct {
+	UUID string
+	// Item состояние записи до изменения или удаления.
+	Item UserData
+	// Deleted версия сохранена при удалении записи.
+	Deleted bool
+	// Unavailable версию не удалось расшифровать: у Item остаются только служебные поля
+	// и версия ключа хранилища Item.KeyVersion.
+	Unavailable bool
+	ArchivedAt  time.Time
+}
+
+// UserDataChanges изменения данных пользователя после некоторой ревизии.
+type UserDataChanges struct {
+	// Revision текущая ревизия данных пользователя.
+	Revision uint64
+	// Upserts созданные и измененные записи.
+	Upserts []UserData
+	// Deleted UUID удаленных записей.
+	Deleted []string
+	// Full Upserts содержит все записи: отметки об удалении после запрошенной ревизии уже не хранятся,
+	// локальные синхронизированные записи, которых нет в Upserts, удалены.
+	Full bool
+}
+
+// MaxUserDataBatchSize наибольшее количество изменений в пакете [UserDataBatch].
+const MaxUserDataBatchSize = 500
+
+// UserDataBatch пакет изменений записей, применяемый на сервере одной транзакцией.
+type UserDataBatch struct {
+	// UUID идентификатор пакета, задается клиентом: повторно отправленный пакет не применяется второй раз.
+	UUID string
+	// Upserts создаваемые и изменяемые записи с UUID, назначенными клиентом.
+	// Version - версия, на основе которой сделано изменение, 0 - новая запись.
+	Upserts []UserData
+	// Deletes UUID удаляемых записей.
+	Deletes []string
+}
+
+// BatchItemStatus результат применения записи пакета.
+type BatchItemStatus string
+
+const (
+	// BatchItemApplied изменение применено.
+	BatchItemApplied BatchItemStatus = "applied"
+	// BatchItemConflict запись изменена после чтения клиентом или уже создана, изменение не применено.
+	BatchItemConflict BatchItemStatus = "conflict"
+	// BatchItemNotFound изменяемая запись удалена, изменение не применено.
+	BatchItemNotFound BatchItemStatus = "not_found"
+)
+
+// UserDataBatchItem результат применения записи пакета.
+type UserDataBatchItem struct {
+	UUID   string          `json:"uuid"`
+	Status BatchItemStatus `json:"status"`
+	// Deleted результат удаления записи.
+	Deleted bool `json:"deleted,omitempty"`
+	// Item сохраненная запись ([BatchItemApplied]) или текущая запись на сервере ([BatchItemConflict]).
+	Item *UserData `json:"-"`
+}
+
+// UserDataBatchResult результат применения пакета изменений.
+type UserDataBatchResult struct {
+	// Revision ревизия данных пользователя, в которой применен пакет.
+	Revision uint64 `json:"revision"`
+	// Items результаты в порядке пакета: сначала Upserts, затем Deletes.
+	Items []UserDataBatchItem `json:"items"`
+	// Replayed пакет был применен ранее, возвращен сохраненный результат.
+	Replayed bool `json:"-"`
+}
+
 // MetaData метаданные.
 type MetaData struct {
 	Title string
@@ -44,6 +44,27 @@ func TestUserData_GetData(t *testing.T) {
 				CVC:      "112",
 			},
 		},
+		{
+			name: "Get_Credential_Data",
+			fields: fields{
+				Type: DataTypeCredential,
+				Data: []byte(`{"username":"user","password":"secret","urls":["https://example.com"],"notes":"note"}`),
+			},
+			want: UserDataCredential{
+				Username: "user",
+				Password: "secret",
+				URLs:     []string{"https://example.com"},
+				Notes:    "note",
+			},
+		},
+		{
+			name: "Get_Credential_Data_Invalid_Json",
+			fields: fields{
+				Type: DataTypeCredential,
+				Data: []byte(`test`),
+			},
+			want: UserDataCredential{},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -127,6 +148,29 @@ func TestUserData_SetData(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Set_Credential_Data_Success",
+			args: args{
+				d: UserDataCredential{
+					Username: "user",
+					Password: "secret",
+				},
+			},
+			fields: fields{
+				Type: DataTypeCredential,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Set_Credential_Data_Failed",
+			args: args{
+				d: UserDataCard{},
+			},
+			fields: fields{
+				Type: DataTypeCredential,
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,24 @@
+package entity
+
+// KDFParams параметры Argon2id для вывода ключа из мастер-пароля.
+type KDFParams struct {
+	Time    uint32
+	Memory  uint32
+	Threads uint8
+}
+
+// VaultKey ключ хранилища, зашифрованный ключом из мастер-пароля.
+type VaultKey struct {
+	UserUUID   string
+	Salt       []byte
+	WrappedKey []byte
+	KeyVersion uint32
+	KDF        KDFParams
+	// WrappedPreviousKey ключ предыдущей версии, зашифрованный тем же ключом из мастер-пароля.
+	WrappedPreviousKey []byte
+	// WrappedTagKey ключ тегов записей, зашифрованный тем же ключом из мастер-пароля.
+	// В отличие от ключа хранилища не меняется при ротации.
+	WrappedTagKey []byte
+	// Rotation незавершённая ротация ключа.
+	Rotation *VaultKey
+}
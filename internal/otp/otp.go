@@ -0,0 +1,258 @@
+package otp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Type тип одноразового пароля.
+type Type string
+
+// Algorithm алгоритм HMAC.
+type Algorithm string
+
+const (
+	// TypeTOTP пароль на основе времени (RFC 6238).
+	TypeTOTP Type = "TOTP"
+	// TypeHOTP пароль на основе счётчика (RFC 4226).
+	TypeHOTP Type = "HOTP"
+
+	// AlgorithmSHA1 HMAC-SHA1.
+	AlgorithmSHA1 Algorithm = "SHA1"
+	// AlgorithmSHA256 HMAC-SHA256.
+	AlgorithmSHA256 Algorithm = "SHA256"
+	// AlgorithmSHA512 HMAC-SHA512.
+	AlgorithmSHA512 Algorithm = "SHA512"
+
+	// DefaultDigits количество цифр по умолчанию.
+	DefaultDigits = 6
+	// DefaultPeriod период TOTP в секундах по умолчанию.
+	DefaultPeriod = 30
+
+	uriScheme = "otpauth"
+)
+
+var (
+	// ErrInvalidURI некорректный otpauth:// URI.
+	ErrInvalidURI = errors.New("invalid otpauth uri")
+	// ErrInvalidSecret некорректный секрет.
+	ErrInvalidSecret = errors.New("invalid otp secret")
+	// ErrUnsupportedAlgorithm неподдерживаемый алгоритм.
+	ErrUnsupportedAlgorithm = errors.New("unsupported otp algorithm")
+	// ErrInvalidDigits некорректное количество цифр.
+	ErrInvalidDigits = errors.New("invalid otp digits")
+
+	b32 = base32.StdEncoding.WithPadding(base32.NoPadding)
+)
+
+// Key параметры одноразового пароля.
+type Key struct {
+	Type      Type
+	Secret    string
+	Issuer    string
+	Account   string
+	Algorithm Algorithm
+	Digits    int
+	Period    int
+	Counter   uint64
+}
+
+// URI возвращает otpauth:// URI ключа.
+func (k Key) URI() string {
+	label := k.Account
+	if k.Issuer != "" {
+		label = k.Issuer + ":" + k.Account
+	}
+
+	v := url.Values{}
+	v.Set("secret", k.Secret)
+	if k.Issuer != "" {
+		v.Set("issuer", k.Issuer)
+	}
+	v.Set("algorithm", string(k.Algorithm))
+	v.Set("digits", strconv.Itoa(k.Digits))
+	if k.Type == TypeHOTP {
+		v.Set("counter", strconv.FormatUint(k.Counter, 10))
+	} else {
+		v.Set("period", strconv.Itoa(k.Period))
+	}
+
+	u := url.URL{
+		Scheme:   uriScheme,
+		Host:     strings.ToLower(string(k.Type)),
+		Path:     "/" + label,
+		RawQuery: v.Encode(),
+	}
+	return u.String()
+}
+
+// Code генерирует код на момент времени t.
+// Для HOTP используется [Key.Counter], время игнорируется.
+func (k Key) Code(t time.Time) (string, error) {
+	secret, err := DecodeSecret(k.Secret)
+	if err != nil {
+		return "", err
+	}
+	if k.Type == TypeHOTP {
+		return HOTP(secret, k.Counter, k.Digits, k.Algorithm)
+	}
+	return TOTP(secret, t, k.Period, k.Digits, k.Algorithm)
+}
+
+// ParseURI разбирает otpauth:// URI.
+func ParseURI(uri string) (*Key, error) {
+	u, err := url.Parse(strings.TrimSpace(uri))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidURI, err)
+	}
+	if u.Scheme != uriScheme {
+		return nil, fmt.Errorf("%w: scheme %q", ErrInvalidURI, u.Scheme)
+	}
+
+	k := &Key{
+		Type:      Type(strings.ToUpper(u.Host)),
+		Algorithm: AlgorithmSHA1,
+		Digits:    DefaultDigits,
+		Period:    DefaultPeriod,
+	}
+	if k.Type != TypeTOTP && k.Type != TypeHOTP {
+		return nil, fmt.Errorf("%w: type %q", ErrInvalidURI, u.Host)
+	}
+
+	label := strings.TrimPrefix(u.Path, "/")
+	if issuer, account, ok := strings.Cut(label, ":"); ok {
+		k.Issuer = strings.TrimSpace(issuer)
+		k.Account = strings.TrimSpace(account)
+	} else {
+		k.Account = strings.TrimSpace(label)
+	}
+
+	q := u.Query()
+	if k.Secret = strings.ToUpper(strings.TrimSpace(q.Get("secret"))); k.Secret == "" {
+		return nil, fmt.Errorf("%w: secret is empty", ErrInvalidURI)
+	}
+	if _, err = DecodeSecret(k.Secret); err != nil {
+		return nil, err
+	}
+	if v := q.Get("issuer"); v != "" {
+		k.Issuer = v
+	}
+	if v := q.Get("algorithm"); v != "" {
+		k.Algorithm = Algorithm(strings.ToUpper(v))
+		if _, err = hashFunc(k.Algorithm); err != nil {
+			return nil, err
+		}
+	}
+	if v := q.Get("digits"); v != "" {
+		if k.Digits, err = strconv.Atoi(v); err != nil || k.Digits < 6 || k.Digits > 8 {
+			return nil, ErrInvalidDigits
+		}
+	}
+	if v := q.Get("period"); v != "" {
+		if k.Period, err = strconv.Atoi(v); err != nil || k.Period <= 0 {
+			return nil, fmt.Errorf("%w: period %q", ErrInvalidURI, v)
+		}
+	}
+	if v := q.Get("counter"); v != "" {
+		if k.Counter, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return nil, fmt.Errorf("%w: counter %q", ErrInvalidURI, v)
+		}
+	} else if k.Type == TypeHOTP {
+		return nil, fmt.Errorf("%w: counter is required for hotp", ErrInvalidURI)
+	}
+
+	return k, nil
+}
+
+// DecodeSecret декодирует секрет в base32 (пробелы и регистр игнорируются).
+func DecodeSecret(secret string) ([]byte, error) {
+	s := strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+	s = strings.TrimRight(s, "=")
+	b, err := b32.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil, ErrInvalidSecret
+	}
+	return b, nil
+}
+
+// EncodeSecret кодирует секрет в base32 без выравнивания.
+func EncodeSecret(secret []byte) string {
+	return b32.EncodeToString(secret)
+}
+
+// GenerateSecret генерирует случайный секрет размером size байт.
+func GenerateSecret(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return EncodeSecret(b), nil
+}
+
+// HOTP вычисляет код по RFC 4226.
+func HOTP(secret []byte, counter uint64, digits int, alg Algorithm) (string, error) {
+	if digits < 6 || digits > 8 {
+		return "", ErrInvalidDigits
+	}
+	h, err := hashFunc(alg)
+	if err != nil {
+		return "", err
+	}
+
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+
+	mac := hmac.New(h, secret)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	bin := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for range digits {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", digits, bin%mod), nil
+}
+
+// TOTP вычисляет код по RFC 6238.
+func TOTP(secret []byte, t time.Time, period, digits int, alg Algorithm) (string, error) {
+	if period <= 0 {
+		period = DefaultPeriod
+	}
+	return HOTP(secret, uint64(t.Unix())/uint64(period), digits, alg)
+}
+
+// Remaining возвращает время до смены TOTP кода.
+func Remaining(t time.Time, period int) time.Duration {
+	if period <= 0 {
+		period = DefaultPeriod
+	}
+	return time.Duration(int64(period)-t.Unix()%int64(period)) * time.Second
+}
+
+func hashFunc(alg Algorithm) (func() hash.Hash, error) {
+	switch alg {
+	case AlgorithmSHA1, "":
+		return sha1.New, nil
+	case AlgorithmSHA256:
+		return sha256.New, nil
+	case AlgorithmSHA512:
+		return sha512.New, nil
+	default:
+		return nil, ErrUnsupportedAlgorithm
+	}
+}
@@ -0,0 +1,169 @@
+package otp
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHOTP(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	// RFC 4226, Appendix D.
+	want := []string{
+		"755224", "287082", "359152", "969429", "338314",
+		"254676", "287922", "162583", "399871", "520489",
+	}
+	for counter, code := range want {
+		got, err := HOTP(secret, uint64(counter), 6, AlgorithmSHA1)
+		if err != nil {
+			t.Fatalf("HOTP() error = %v", err)
+		}
+		if got != code {
+			t.Errorf("HOTP() counter = %d, got = %v, want %v", counter, got, code)
+		}
+	}
+}
+
+func TestTOTP(t *testing.T) {
+	secrets := map[Algorithm][]byte{
+		AlgorithmSHA1:   []byte("12345678901234567890"),
+		AlgorithmSHA256: []byte("12345678901234567890123456789012"),
+		AlgorithmSHA512: []byte("1234567890123456789012345678901234567890123456789012345678901234"),
+	}
+	// RFC 6238, Appendix B.
+	tests := []struct {
+		unix int64
+		alg  Algorithm
+		want string
+	}{
+		{unix: 59, alg: AlgorithmSHA1, want: "94287082"},
+		{unix: 59, alg: AlgorithmSHA256, want: "46119246"},
+		{unix: 59, alg: AlgorithmSHA512, want: "90693936"},
+		{unix: 1111111109, alg: AlgorithmSHA1, want: "07081804"},
+		{unix: 1111111109, alg: AlgorithmSHA256, want: "68084774"},
+		{unix: 1111111109, alg: AlgorithmSHA512, want: "25091201"},
+		{unix: 1111111111, alg: AlgorithmSHA1, want: "14050471"},
+		{unix: 1111111111, alg: AlgorithmSHA256, want: "67062674"},
+		{unix: 1111111111, alg: AlgorithmSHA512, want: "99943326"},
+		{unix: 1234567890, alg: AlgorithmSHA1, want: "89005924"},
+		{unix: 1234567890, alg: AlgorithmSHA256, want: "91819424"},
+		{unix: 1234567890, alg: AlgorithmSHA512, want: "93441116"},
+		{unix: 2000000000, alg: AlgorithmSHA1, want: "69279037"},
+		{unix: 2000000000, alg: AlgorithmSHA256, want: "90698825"},
+		{unix: 2000000000, alg: AlgorithmSHA512, want: "38618901"},
+		{unix: 20000000000, alg: AlgorithmSHA1, want: "65353130"},
+		{unix: 20000000000, alg: AlgorithmSHA256, want: "77737706"},
+		{unix: 20000000000, alg: AlgorithmSHA512, want: "47863826"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.alg)+"_"+tt.want, func(t *testing.T) {
+			got, err := TOTP(secrets[tt.alg], time.Unix(tt.unix, 0), 30, 8, tt.alg)
+			if err != nil {
+				t.Fatalf("TOTP() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("TOTP() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseURI(t *testing.T) {
+	tests := []struct {
+		name    string
+		uri     string
+		want    *Key
+		wantErr bool
+	}{
+		{
+			name: "Parse_TOTP_Success",
+			uri:  "otpauth://totp/ACME%20Co:john@example.com?secret=HXDMVJECJJWSRB3HWIZR4IFUGFTMXBOZ&issuer=ACME%20Co&algorithm=SHA256&digits=8&period=60",
+			want: &Key{
+				Type:      TypeTOTP,
+				Secret:    "HXDMVJECJJWSRB3HWIZR4IFUGFTMXBOZ",
+				Issuer:    "ACME Co",
+				Account:   "john@example.com",
+				Algorithm: AlgorithmSHA256,
+				Digits:    8,
+				Period:    60,
+			},
+		},
+		{
+			name: "Parse_TOTP_Defaults_Success",
+			uri:  "otpauth://totp/john?secret=gezdgnbvgy3tqojqgezdgnbvgy3tqojq",
+			want: &Key{
+				Type:      TypeTOTP,
+				Secret:    "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ",
+				Account:   "john",
+				Algorithm: AlgorithmSHA1,
+				Digits:    DefaultDigits,
+				Period:    DefaultPeriod,
+			},
+		},
+		{
+			name: "Parse_HOTP_Success",
+			uri:  "otpauth://hotp/Example:alice?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ&counter=5",
+			want: &Key{
+				Type:      TypeHOTP,
+				Secret:    "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ",
+				Issuer:    "Example",
+				Account:   "alice",
+				Algorithm: AlgorithmSHA1,
+				Digits:    DefaultDigits,
+				Period:    DefaultPeriod,
+				Counter:   5,
+			},
+		},
+		{
+			name:    "Parse_HOTP_Without_Counter_Error",
+			uri:     "otpauth://hotp/alice?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ",
+			wantErr: true,
+		},
+		{
+			name:    "Parse_Wrong_Scheme_Error",
+			uri:     "https://totp/alice?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ",
+			wantErr: true,
+		},
+		{
+			name:    "Parse_Wrong_Secret_Error",
+			uri:     "otpauth://totp/alice?secret=1111",
+			wantErr: true,
+		},
+		{
+			name:    "Parse_Wrong_Algorithm_Error",
+			uri:     "otpauth://totp/alice?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ&algorithm=MD5",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseURI(tt.uri)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseURI() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseURI() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKey_URI(t *testing.T) {
+	k := Key{
+		Type:      TypeTOTP,
+		Secret:    "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ",
+		Issuer:    "goph-keeper",
+		Account:   "alice",
+		Algorithm: AlgorithmSHA1,
+		Digits:    6,
+		Period:    30,
+	}
+	got, err := ParseURI(k.URI())
+	if err != nil {
+		t.Fatalf("ParseURI() error = %v", err)
+	}
+	if !reflect.DeepEqual(*got, k) {
+		t.Errorf("ParseURI(URI()) got = %v, want %v", *got, k)
+	}
+}
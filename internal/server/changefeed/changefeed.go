@@ -0,0 +1,128 @@
+package changefeed
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// Channel канал уведомлений PostgreSQL об изменении данных пользователя.
+	Channel = "user_data_changes"
+	// Resync ревизия, которую получают подписчики после восстановления подписки на уведомления:
+	// уведомления могли быть потеряны, и изменения нужно перечитать.
+	Resync uint64 = math.MaxUint64
+)
+
+// ErrMalformedPayload некорректное уведомление об изменении.
+var ErrMalformedPayload = errors.New("malformed change notification payload")
+
+// Payload уведомление об изменении данных пользователя userUUID в ревизии revision.
+func Payload(userUUID string, revision uint64) string {
+	return userUUID + ":" + strconv.FormatUint(revision, 10)
+}
+
+// ParsePayload разбирает уведомление [Payload].
+func ParsePayload(payload string) (string, uint64, error) {
+	userUUID, rev, ok := strings.Cut(payload, ":")
+	if !ok || userUUID == "" {
+		return "", 0, ErrMalformedPayload
+	}
+	revision, err := strconv.ParseUint(rev, 10, 64)
+	if err != nil {
+		return "", 0, ErrMalformedPayload
+	}
+	return userUUID, revision, nil
+}
+
+// Broker рассылает ревизии изменений данных подписчикам пользователя внутри процесса.
+type Broker struct {
+	mu     sync.Mutex
+	subs   map[string]map[chan uint64]struct{}
+	closed bool
+}
+
+// Subscribe подписывает на изменения данных пользователя. Канал получает последнюю ревизию:
+// если подписчик не успел прочитать предыдущую, она заменяется новой.
+// Канал закрывается при закрытии брокера; cancel отменяет подписку.
+func (b *Broker) Subscribe(userUUID string) (<-chan uint64, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan uint64, 1)
+	if b.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	if b.subs[userUUID] == nil {
+		b.subs[userUUID] = make(map[chan uint64]struct{})
+	}
+	b.subs[userUUID][ch] = struct{}{}
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		if _, ok := b.subs[userUUID][ch]; !ok {
+			return
+		}
+		delete(b.subs[userUUID], ch)
+		if len(b.subs[userUUID]) == 0 {
+			delete(b.subs, userUUID)
+		}
+		close(ch)
+	}
+}
+
+// Publish уведомляет подписчиков пользователя об изменении в ревизии revision.
+func (b *Broker) Publish(userUUID string, revision uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subs[userUUID] {
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- revision
+	}
+}
+
+// PublishResync отправляет [Resync] всем подписчикам.
+func (b *Broker) PublishResync() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, subs := range b.subs {
+		for ch := range subs {
+			select {
+			case <-ch:
+			default:
+			}
+			ch <- Resync
+		}
+	}
+}
+
+// Close закрывает каналы всех подписчиков, чтобы потоки завершились до остановки сервера.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for _, subs := range b.subs {
+		for ch := range subs {
+			close(ch)
+		}
+	}
+	b.subs = make(map[string]map[chan uint64]struct{})
+}
+
+// NewBroker конструктор.
+func NewBroker() *Broker {
+	return &Broker{
+		subs: make(map[string]map[chan uint64]struct{}),
+	}
+}
@@ -0,0 +1,114 @@
+package changefeed
+
+import (
+	"testing"
+)
+
+func TestParsePayload(t *testing.T) {
+	tests := []struct {
+		name     string
+		payload  string
+		wantUUID string
+		wantRev  uint64
+		wantErr  bool
+	}{
+		{
+			name:     "Parse_Success",
+			payload:  Payload("33b06619-1ee7-3db5-827d-0dc85df1f759", 42),
+			wantUUID: "33b06619-1ee7-3db5-827d-0dc85df1f759",
+			wantRev:  42,
+		},
+		{
+			name:    "Parse_No_Revision",
+			payload: "33b06619-1ee7-3db5-827d-0dc85df1f759",
+			wantErr: true,
+		},
+		{
+			name:    "Parse_Bad_Revision",
+			payload: "33b06619-1ee7-3db5-827d-0dc85df1f759:-1",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotUUID, gotRev, err := ParsePayload(tt.payload)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePayload() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotUUID != tt.wantUUID || gotRev != tt.wantRev {
+				t.Errorf("ParsePayload() got = %s, %d, want %s, %d", gotUUID, gotRev, tt.wantUUID, tt.wantRev)
+			}
+		})
+	}
+}
+
+func TestBroker(t *testing.T) {
+	const (
+		userUUID  = "33b06619-1ee7-3db5-827d-0dc85df1f759"
+		otherUUID = "4d7e2f9a-0b1c-4e3d-8a5f-6b7c8d9e0f1a"
+	)
+
+	t.Run("Publish_Coalesces_Latest", func(t *testing.T) {
+		b := NewBroker()
+		ch, cancel := b.Subscribe(userUUID)
+		defer cancel()
+
+		b.Publish(userUUID, 1)
+		b.Publish(userUUID, 2)
+		b.Publish(otherUUID, 3)
+
+		if got := <-ch; got != 2 {
+			t.Errorf("Subscribe() got revision %d, want 2", got)
+		}
+		select {
+		case got := <-ch:
+			t.Errorf("Subscribe() got unexpected revision %d", got)
+		default:
+		}
+	})
+
+	t.Run("Resync_All_Subscribers", func(t *testing.T) {
+		b := NewBroker()
+		ch, cancel := b.Subscribe(userUUID)
+		defer cancel()
+		other, cancelOther := b.Subscribe(otherUUID)
+		defer cancelOther()
+
+		b.Publish(userUUID, 1)
+		b.PublishResync()
+
+		if got := <-ch; got != Resync {
+			t.Errorf("Subscribe() got revision %d, want resync", got)
+		}
+		if got := <-other; got != Resync {
+			t.Errorf("Subscribe() got revision %d, want resync", got)
+		}
+	})
+
+	t.Run("Cancel_Closes_Channel", func(t *testing.T) {
+		b := NewBroker()
+		ch, cancel := b.Subscribe(userUUID)
+		cancel()
+		cancel()
+
+		b.Publish(userUUID, 1)
+		if _, ok := <-ch; ok {
+			t.Error("Subscribe() channel is open after cancel")
+		}
+	})
+
+	t.Run("Close_Ends_Subscriptions", func(t *testing.T) {
+		b := NewBroker()
+		ch, cancel := b.Subscribe(userUUID)
+		b.Close()
+		cancel()
+
+		if _, ok := <-ch; ok {
+			t.Error("Subscribe() channel is open after broker close")
+		}
+		late, _ := b.Subscribe(userUUID)
+		if _, ok := <-late; ok {
+			t.Error("Subscribe() channel is open on closed broker")
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package changefeed
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// reconnectDelay пауза перед повторной подпиской после обрыва соединения.
+const reconnectDelay = time.Second
+
+// Listen подписывается на уведомления PostgreSQL в канале [Channel] и передает их брокеру.
+// При обрыве соединения подписка восстанавливается, и подписчики получают [Resync], так как уведомления
+// за время обрыва потеряны; завершается при отмене ctx.
+func Listen(ctx context.Context, pool *pgxpool.Pool, broker *Broker, logger *slog.Logger) {
+	for reconnect := false; ; reconnect = true {
+		err := listen(ctx, pool, broker, logger, reconnect)
+		if ctx.Err() != nil {
+			return
+		}
+		logger.Error("change feed listener failed", "error", err)
+
+		select {
+		case <-time.After(reconnectDelay):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func listen(ctx context.Context, pool *pgxpool.Pool, broker *Broker, logger *slog.Logger, reconnect bool) error {
+	pooled, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// Соединение с подпиской не возвращается в пул.
+	conn := pooled.Hijack()
+	defer func() {
+		_ = conn.Close(context.Background())
+	}()
+
+	if _, err = conn.Exec(ctx, "LISTEN "+pgx.Identifier{Channel}.Sanitize()); err != nil {
+		return err
+	}
+	if reconnect {
+		logger.Info("change feed listener reconnected")
+		broker.PublishResync()
+	}
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		userUUID, revision, err := ParsePayload(n.Payload)
+		if err != nil {
+			logger.Warn("malformed change notification", "payload", n.Payload)
+			continue
+		}
+		broker.Publish(userUUID, revision)
+	}
+}
@@ -1,20 +1,100 @@
 package config
 
 import (
+	"log/slog"
+
 	h "github.com/ktigay/goph-keeper/internal/config"
 )
 
 const (
-	defaultLogLevel = "debug"
+	defaultLogLevel         = "debug"
+	defaultAccessTokenTTL   = 15 * 60
+	defaultRefreshTokenTTL  = 30 * 24 * 60 * 60
+	defaultDeletionGrace    = 7 * 24 * 60 * 60
+	defaultTLSReload        = 30
+	defaultHistoryRetention = 10
+	// defaultTombstoneRetention отметки об удалении записей хранятся 90 дней.
+	defaultTombstoneRetention = 90 * 24 * 60 * 60
+
+	// Параметры Argon2id по умолчанию (RFC 9106, второй рекомендованный набор).
+	defaultPasswordHashTime    = 3
+	defaultPasswordHashMemory  = 64 * 1024
+	defaultPasswordHashThreads = 4
+
+	// AttemptStoreMemory попытки входа хранятся в памяти процесса.
+	AttemptStoreMemory = "memory"
+	// AttemptStorePostgres попытки входа хранятся в б.д., общие для нескольких экземпляров сервера.
+	AttemptStorePostgres = "postgres"
 )
 
 // Config конфигурация.
 type Config struct {
-	ServerGRPCHost string `env:"GRPC_ADDRESS" arg:"-a" json:"grpc_host" help:"gRPC server address"`
-	LogLevel       string `env:"LOG_LEVEL" arg:"-l" json:"log_level" help:"log level"`
-	DatabaseDSN    string `env:"DATABASE_URI" arg:"-d" json:"database_uri" help:"database URI"`
-	AuthSecret     string `env:"JWT_SECRET" arg:"-s" json:"jwt_secret" help:"jwt secret"`
-	ConfigFile     string `env:"CONFIG" arg:"-c" help:"JSON config file path"`
+	ServerGRPCHost     string `env:"GRPC_ADDRESS" arg:"-a" json:"grpc_host" help:"gRPC server address"`
+	LogLevel           string `env:"LOG_LEVEL" arg:"-l" json:"log_level" help:"log level"`
+	DatabaseDSN        string `env:"DATABASE_URI" arg:"-d" json:"database_uri" help:"database URI"`
+	AuthSecret         string `env:"JWT_SECRET" arg:"-s" json:"jwt_secret" help:"jwt secret, used for HS256 tokens when no key directory is set"`
+	JWTKeyDir          string `env:"JWT_KEY_DIR" arg:"--jwt-key-dir" json:"jwt_key_dir" help:"directory with Ed25519/ES256 token signing keys, one <kid>.pem per key"`
+	AccessTokenTTL     int64  `env:"ACCESS_TOKEN_TTL" arg:"--access-token-ttl" json:"access_token_ttl" help:"access token ttl in seconds"`
+	RefreshTokenTTL    int64  `env:"REFRESH_TOKEN_TTL" arg:"--refresh-token-ttl" json:"refresh_token_ttl" help:"refresh token ttl in seconds"`
+	DeletionGrace      int64  `env:"ACCOUNT_DELETION_GRACE" arg:"--account-deletion-grace" json:"account_deletion_grace" help:"delay before a deleted account is purged, in seconds"`
+	AttemptStore       string `env:"LOGIN_ATTEMPT_STORE" arg:"--login-attempt-store" json:"login_attempt_store" help:"failed login attempts storage: memory or postgres"`
+	MasterKey          string `env:"MASTER_KEY" arg:"-k" json:"master_key" help:"master keys for data at rest: id:base64[,id:base64...], the first one is active"`
+	MasterKeyFile      string `env:"MASTER_KEY_FILE" arg:"-f" json:"master_key_file" help:"file with master keys, one id:base64 per line, the first one is active"`
+	HistoryRetention   int    `env:"HISTORY_RETENTION" arg:"--history-retention" json:"history_retention" help:"previous versions kept per item unless set for the user, 0 disables history"`
+	TombstoneRetention int64  `env:"TOMBSTONE_RETENTION" arg:"--tombstone-retention" json:"tombstone_retention" help:"how long deleted item markers are kept for syncing other devices, in seconds"`
+	LegacyLogin        bool   `env:"LEGACY_PASSWORD_LOGIN" arg:"--legacy-password-login" json:"legacy_password_login" help:"allow Login and Register with a plaintext password for clients without SRP"`
+	SRPSecret          string `env:"SRP_SECRET" arg:"--srp-secret" json:"srp_secret" help:"base64 secret of at least 32 bytes for SRP answers to unknown logins, must not change"`
+	ConfigFile         string `env:"CONFIG" arg:"-c" help:"JSON config file path"`
+	PasswordHash
+	TLS
+}
+
+// TLS сертификаты сервера. Без сертификата и ключа сервер работает без шифрования.
+type TLS struct {
+	TLSCertFile          string `env:"TLS_CERT_FILE" arg:"--tls-cert" json:"tls_cert_file" help:"server certificate PEM file, enables TLS"`
+	TLSKeyFile           string `env:"TLS_KEY_FILE" arg:"--tls-key" json:"tls_key_file" help:"server private key PEM file"`
+	TLSClientCAFile      string `env:"TLS_CLIENT_CA_FILE" arg:"--tls-client-ca" json:"tls_client_ca_file" help:"CA PEM file for client certificates, enables mTLS"`
+	TLSRequireClientCert bool   `env:"TLS_REQUIRE_CLIENT_CERT" arg:"--tls-require-client-cert" json:"tls_require_client_cert" help:"reject connections without a client certificate"`
+	TLSBindClientCert    bool   `env:"TLS_BIND_CLIENT_CERT" arg:"--tls-bind-client-cert" json:"tls_bind_client_cert" help:"require a client certificate bound to the account for authorized requests"`
+	TLSReloadInterval    int64  `env:"TLS_RELOAD_INTERVAL" arg:"--tls-reload-interval" json:"tls_reload_interval" help:"certificate files change check interval in seconds"`
+}
+
+// Enabled возвращает true, если задан сертификат сервера.
+func (t TLS) Enabled() bool {
+	return t.TLSCertFile != "" || t.TLSKeyFile != ""
+}
+
+// PasswordHash параметры Argon2id для хешей паролей. Хеши с другими параметрами пересчитываются при входе.
+type PasswordHash struct {
+	PasswordHashTime    uint32 `env:"PASSWORD_HASH_TIME" arg:"--password-hash-time" json:"password_hash_time" help:"argon2id iterations for password hashes"`
+	PasswordHashMemory  uint32 `env:"PASSWORD_HASH_MEMORY" arg:"--password-hash-memory" json:"password_hash_memory" help:"argon2id memory for password hashes, KiB"`
+	PasswordHashThreads uint8  `env:"PASSWORD_HASH_THREADS" arg:"--password-hash-threads" json:"password_hash_threads" help:"argon2id parallelism for password hashes"`
+}
+
+// LogValue скрывает секреты при логировании конфигурации.
+func (c Config) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("grpc_host", c.ServerGRPCHost),
+		slog.String("log_level", c.LogLevel),
+		slog.String("jwt_key_dir", c.JWTKeyDir),
+		slog.Int64("access_token_ttl", c.AccessTokenTTL),
+		slog.Int64("refresh_token_ttl", c.RefreshTokenTTL),
+		slog.Int64("account_deletion_grace", c.DeletionGrace),
+		slog.String("login_attempt_store", c.AttemptStore),
+		slog.String("master_key_file", c.MasterKeyFile),
+		slog.Int("history_retention", c.HistoryRetention),
+		slog.Int64("tombstone_retention", c.TombstoneRetention),
+		slog.Bool("legacy_password_login", c.LegacyLogin),
+		slog.Uint64("password_hash_time", uint64(c.PasswordHashTime)),
+		slog.Uint64("password_hash_memory", uint64(c.PasswordHashMemory)),
+		slog.Uint64("password_hash_threads", uint64(c.PasswordHashThreads)),
+		slog.String("tls_cert_file", c.TLSCertFile),
+		slog.String("tls_client_ca_file", c.TLSClientCAFile),
+		slog.Bool("tls_require_client_cert", c.TLSRequireClientCert),
+		slog.Bool("tls_bind_client_cert", c.TLSBindClientCert),
+		slog.Int64("tls_reload_interval", c.TLSReloadInterval),
+		slog.String("config_file", c.ConfigFile),
+	)
 }
 
 // New конструктор.
@@ -43,6 +123,18 @@ type DefaultHandler struct {
 // Handle обработчик.
 func (d *DefaultHandler) Handle(c *Config) (*Config, error) {
 	c.LogLevel = defaultLogLevel
+	c.AccessTokenTTL = defaultAccessTokenTTL
+	c.RefreshTokenTTL = defaultRefreshTokenTTL
+	c.DeletionGrace = defaultDeletionGrace
+	c.AttemptStore = AttemptStoreMemory
+	c.PasswordHash = PasswordHash{
+		PasswordHashTime:    defaultPasswordHashTime,
+		PasswordHashMemory:  defaultPasswordHashMemory,
+		PasswordHashThreads: defaultPasswordHashThreads,
+	}
+	c.TLSReloadInterval = defaultTLSReload
+	c.HistoryRetention = defaultHistoryRetention
+	c.TombstoneRetention = defaultTombstoneRetention
 
 	return d.next.Handle(c)
 }
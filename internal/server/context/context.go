@@ -3,14 +3,19 @@ package context
 import (
 	"context"
 	"errors"
+	"net"
+
+	"google.golang.org/grpc/peer"
 
 	"github.com/ktigay/goph-keeper/internal/entity"
+	se "github.com/ktigay/goph-keeper/internal/server/entity"
 )
 
 type contextKey int
 
 const (
 	ctxKey contextKey = iota
+	scopeCtxKey
 )
 
 // NewContextWithIdentity возвращает контекст с идентификационными данными.
@@ -27,3 +32,31 @@ func IdentityFromContext(ctx context.Context) (*entity.Identity, error) {
 
 	return &e, nil
 }
+
+// NewContextWithTokenScope возвращает контекст с ограничениями токена доступа, которым выполнен запрос.
+func NewContextWithTokenScope(ctx context.Context, scope se.APITokenScope) context.Context {
+	return context.WithValue(ctx, scopeCtxKey, scope)
+}
+
+// TokenScopeFromContext извлекает ограничения токена доступа из контекста.
+// Nil - запрос выполнен с JWT сессии, ограничений нет.
+func TokenScopeFromContext(ctx context.Context) *se.APITokenScope {
+	s, ok := ctx.Value(scopeCtxKey).(se.APITokenScope)
+	if !ok {
+		return nil
+	}
+	return &s
+}
+
+// PeerIP возвращает IP адрес клиента gRPC запроса или пустую строку.
+func PeerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
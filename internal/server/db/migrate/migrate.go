@@ -0,0 +1,275 @@
+// Package migrate применяет версионированные миграции схемы б.д.
+//
+// Миграции встроены в бинарник: migrations/<версия>_<имя>.up.sql и .down.sql, версии идут подряд с 1.
+// Примененные версии хранятся в таблице schema_migrations, каждая миграция выполняется в своей транзакции,
+// одновременный запуск на нескольких экземплярах сервера исключается advisory lock.
+package migrate
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// lockID ключ advisory lock миграций.
+const lockID int64 = 0x676b5f6d6967 // "gk_mig"
+
+var (
+	//go:embed migrations/*.sql
+	embedded embed.FS
+
+	fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+	// ErrInvalidMigrations набор миграций некорректен.
+	ErrInvalidMigrations = errors.New("invalid migrations")
+	// ErrUnknownVersion в б.д. применена версия, которой нет в бинарнике.
+	ErrUnknownVersion = errors.New("database has a migration unknown to this binary")
+)
+
+const (
+	createTableQuery = `
+		CREATE TABLE IF NOT EXISTS "schema_migrations"
+		(
+			"version"    BIGINT NOT NULL,
+			"name"       VARCHAR(255) NOT NULL,
+			"applied_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			PRIMARY KEY ("version")
+		)`
+
+	selectAppliedQuery = `SELECT "version", "applied_at" FROM "schema_migrations" ORDER BY "version"`
+
+	insertVersionQuery = `INSERT INTO "schema_migrations" ("version", "name") VALUES ($1, $2)`
+
+	deleteVersionQuery = `DELETE FROM "schema_migrations" WHERE "version" = $1`
+)
+
+// Migration миграция.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status состояние миграции. AppliedAt nil - не применена.
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Migrator применяет миграции.
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []Migration
+	logger     *slog.Logger
+}
+
+// Up применяет все непримененные миграции по порядку. Возвращает примененные.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		done, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, mg := range m.migrations {
+			if _, ok := done[mg.Version]; ok {
+				continue
+			}
+			if err = m.run(ctx, conn, mg.Up, insertVersionQuery, mg.Version, mg.Name); err != nil {
+				return fmt.Errorf("migration %d_%s up: %w", mg.Version, mg.Name, err)
+			}
+			m.logger.Info("migration applied", "version", mg.Version, "name", mg.Name)
+			applied = append(applied, mg)
+		}
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down откатывает steps последних примененных миграций. Возвращает откаченные.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var reverted []Migration
+
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		done, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			mg := m.migrations[i]
+			if _, ok := done[mg.Version]; !ok {
+				continue
+			}
+			if err = m.run(ctx, conn, mg.Down, deleteVersionQuery, mg.Version); err != nil {
+				return fmt.Errorf("migration %d_%s down: %w", mg.Version, mg.Name, err)
+			}
+			m.logger.Info("migration reverted", "version", mg.Version, "name", mg.Name)
+			reverted = append(reverted, mg)
+		}
+		return nil
+	})
+
+	return reverted, err
+}
+
+// Status возвращает состояние всех миграций.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		done, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, mg := range m.migrations {
+			s := Status{Migration: mg}
+			if at, ok := done[mg.Version]; ok {
+				s.AppliedAt = &at
+			}
+			statuses = append(statuses, s)
+		}
+		return nil
+	})
+
+	return statuses, err
+}
+
+// withLock выполняет fn на одном соединении под advisory lock.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *pgxpool.Conn) error) (err error) {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err = conn.Exec(ctx, "SELECT pg_advisory_lock($1)", lockID); err != nil {
+		return err
+	}
+	defer func() {
+		if _, uErr := conn.Exec(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", lockID); uErr != nil {
+			err = errors.Join(err, uErr)
+		}
+	}()
+
+	if _, err = conn.Exec(ctx, createTableQuery); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+// applied возвращает примененные версии и время применения.
+func (m *Migrator) applied(ctx context.Context, conn *pgxpool.Conn) (map[int64]time.Time, error) {
+	rows, err := conn.Query(ctx, selectAppliedQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	done := make(map[int64]time.Time)
+	for rows.Next() {
+		var (
+			version int64
+			at      time.Time
+		)
+		if err = rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		done[version] = at
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	for version := range done {
+		if !slices.ContainsFunc(m.migrations, func(mg Migration) bool { return mg.Version == version }) {
+			return nil, fmt.Errorf("%w: version %d", ErrUnknownVersion, version)
+		}
+	}
+	return done, nil
+}
+
+// run выполняет SQL миграции и запись в schema_migrations в одной транзакции.
+func (m *Migrator) run(ctx context.Context, conn *pgxpool.Conn, sql, bookkeeping string, args ...any) error {
+	return pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, sql); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, bookkeeping, args...)
+		return err
+	})
+}
+
+// Load читает миграции из fsys: каждая версия должна иметь up и down, версии идут подряд с 1.
+func Load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, e := range entries {
+		match := fileName.FindStringSubmatch(e.Name())
+		if match == nil {
+			return nil, fmt.Errorf("%w: unexpected file %s", ErrInvalidMigrations, e.Name())
+		}
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+		b, err := fs.ReadFile(fsys, path.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		mg, ok := byVersion[version]
+		if !ok {
+			mg = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = mg
+		}
+		if mg.Name != match[2] {
+			return nil, fmt.Errorf("%w: version %d has names %s and %s", ErrInvalidMigrations, version, mg.Name, match[2])
+		}
+		if match[3] == "up" {
+			mg.Up = string(b)
+		} else {
+			mg.Down = string(b)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for v := int64(1); v <= int64(len(byVersion)); v++ {
+		mg, ok := byVersion[v]
+		if !ok {
+			return nil, fmt.Errorf("%w: version %d is missing", ErrInvalidMigrations, v)
+		}
+		if mg.Up == "" || mg.Down == "" {
+			return nil, fmt.Errorf("%w: version %d needs both up and down", ErrInvalidMigrations, v)
+		}
+		migrations = append(migrations, *mg)
+	}
+	return migrations, nil
+}
+
+// New конструктор, миграции берутся из бинарника.
+func New(pool *pgxpool.Pool, logger *slog.Logger) (*Migrator, error) {
+	migrations, err := Load(embedded, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{
+		pool:       pool,
+		migrations: migrations,
+		logger:     logger,
+	}, nil
+}
@@ -0,0 +1,99 @@
+package migrate
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoad_Embedded(t *testing.T) {
+	migrations, err := Load(embedded, "migrations")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("Load() returned no migrations")
+	}
+	for i, m := range migrations {
+		if m.Version != int64(i+1) {
+			t.Errorf("migration %d has version %d", i, m.Version)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	file := func(s string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte(s)}
+	}
+
+	tests := []struct {
+		name    string
+		fsys    fstest.MapFS
+		want    []Migration
+		wantErr error
+	}{
+		{
+			name: "ordered_by_version",
+			fsys: fstest.MapFS{
+				"m/0002_b.up.sql":   file("up b"),
+				"m/0002_b.down.sql": file("down b"),
+				"m/0001_a.up.sql":   file("up a"),
+				"m/0001_a.down.sql": file("down a"),
+			},
+			want: []Migration{
+				{Version: 1, Name: "a", Up: "up a", Down: "down a"},
+				{Version: 2, Name: "b", Up: "up b", Down: "down b"},
+			},
+		},
+		{
+			name: "missing_down",
+			fsys: fstest.MapFS{
+				"m/0001_a.up.sql": file("up a"),
+			},
+			wantErr: ErrInvalidMigrations,
+		},
+		{
+			name: "gap_in_versions",
+			fsys: fstest.MapFS{
+				"m/0001_a.up.sql":   file("up a"),
+				"m/0001_a.down.sql": file("down a"),
+				"m/0003_c.up.sql":   file("up c"),
+				"m/0003_c.down.sql": file("down c"),
+			},
+			wantErr: ErrInvalidMigrations,
+		},
+		{
+			name: "name_mismatch",
+			fsys: fstest.MapFS{
+				"m/0001_a.up.sql":   file("up a"),
+				"m/0001_b.down.sql": file("down b"),
+			},
+			wantErr: ErrInvalidMigrations,
+		},
+		{
+			name: "unexpected_file",
+			fsys: fstest.MapFS{
+				"m/0001_a.up.sql":   file("up a"),
+				"m/0001_a.down.sql": file("down a"),
+				"m/README.md":       file("notes"),
+			},
+			wantErr: ErrInvalidMigrations,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Load(tt.fsys, "m")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Load() = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Load()[%d] = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
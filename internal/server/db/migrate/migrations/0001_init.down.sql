@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "user_data";
+DROP TABLE IF EXISTS "user";
+DROP TYPE IF EXISTS user_data_type;
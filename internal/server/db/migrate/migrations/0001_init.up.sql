@@ -4,6 +4,9 @@ DO ' BEGIN
     END IF;
 END ';
 
+ALTER TYPE user_data_type ADD VALUE IF NOT EXISTS 'CREDENTIAL';
+ALTER TYPE user_data_type ADD VALUE IF NOT EXISTS 'OTP';
+
 CREATE TABLE IF NOT EXISTS "user"
 (
     "uuid" UUID DEFAULT gen_random_uuid(),
@@ -28,4 +31,4 @@ CREATE TABLE IF NOT EXISTS "user_data"
     PRIMARY KEY ("uuid")
 );
 
-CREATE INDEX IF NOT EXISTS "user_uuid_idx" ON "user_data" ("user_uuid");
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS "user_uuid_idx" ON "user_data" ("user_uuid");
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "vault_key_rotation";
+DROP TABLE IF EXISTS "vault_key";
+ALTER TABLE "user_data" DROP COLUMN IF EXISTS "key_version";
@@ -0,0 +1,31 @@
+ALTER TABLE "user_data" ADD COLUMN IF NOT EXISTS "key_version" INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS "vault_key"
+(
+    "user_uuid"   UUID NOT NULL,
+    "salt"        BYTEA NOT NULL,
+    "wrapped_key" BYTEA NOT NULL,
+    "key_version" INTEGER NOT NULL,
+    "kdf_time"    INTEGER NOT NULL,
+    "kdf_memory"  INTEGER NOT NULL,
+    "kdf_threads" SMALLINT NOT NULL,
+    "created_at"  TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    "updated_at"  TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY ("user_uuid")
+);
+
+ALTER TABLE "vault_key" ADD COLUMN IF NOT EXISTS "wrapped_previous_key" BYTEA;
+
+CREATE TABLE IF NOT EXISTS "vault_key_rotation"
+(
+    "user_uuid"            UUID NOT NULL,
+    "salt"                 BYTEA NOT NULL,
+    "wrapped_key"          BYTEA NOT NULL,
+    "wrapped_previous_key" BYTEA NOT NULL,
+    "key_version"          INTEGER NOT NULL,
+    "kdf_time"             INTEGER NOT NULL,
+    "kdf_memory"           INTEGER NOT NULL,
+    "kdf_threads"          SMALLINT NOT NULL,
+    "created_at"           TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY ("user_uuid")
+);
@@ -0,0 +1,3 @@
+-- Записи, зашифрованные ключом данных (sealed), без таблицы ключей не расшифровать.
+ALTER TABLE "user_data" DROP COLUMN IF EXISTS "sealed";
+DROP TABLE IF EXISTS "user_data_key";
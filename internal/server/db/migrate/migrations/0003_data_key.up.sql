@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS "user_data_key"
+(
+    "user_uuid"   UUID NOT NULL,
+    "kek_id"      VARCHAR(64) NOT NULL,
+    "wrapped_key" BYTEA NOT NULL,
+    "created_at"  TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    "updated_at"  TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY ("user_uuid")
+);
+
+CREATE INDEX IF NOT EXISTS "user_data_key_kek_id_idx" ON "user_data_key" ("kek_id");
+ALTER TABLE "user_data" ADD COLUMN IF NOT EXISTS "sealed" BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS "session"
+(
+    "uuid"                  UUID DEFAULT gen_random_uuid(),
+    "user_uuid"             UUID NOT NULL,
+    "refresh_hash"          BYTEA NOT NULL,
+    "previous_refresh_hash" BYTEA,
+    "expires_at"            TIMESTAMP WITH TIME ZONE NOT NULL,
+    "revoked_at"            TIMESTAMP WITH TIME ZONE,
+    "created_at"            TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    "updated_at"            TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY ("uuid"),
+    CONSTRAINT session_refresh_hash_idx UNIQUE ("refresh_hash")
+);
+
+CREATE INDEX IF NOT EXISTS "session_previous_refresh_hash_idx" ON "session" ("previous_refresh_hash");
+CREATE INDEX IF NOT EXISTS "session_user_uuid_idx" ON "session" ("user_uuid");
+
+ALTER TABLE "session" ADD COLUMN IF NOT EXISTS "device_name" VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE "session" ADD COLUMN IF NOT EXISTS "client_version" VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE "session" ADD COLUMN IF NOT EXISTS "ip" VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE "session" ADD COLUMN IF NOT EXISTS "last_seen_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW();
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS "user_second_factor"
+(
+    "user_uuid"      UUID NOT NULL,
+    "secret"         BYTEA NOT NULL,
+    "enabled"        BOOLEAN NOT NULL DEFAULT FALSE,
+    "last_used_step" BIGINT NOT NULL DEFAULT 0,
+    "created_at"     TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    "updated_at"     TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY ("user_uuid")
+);
+
+CREATE TABLE IF NOT EXISTS "user_recovery_code"
+(
+    "user_uuid"  UUID NOT NULL,
+    "code_hash"  BYTEA NOT NULL,
+    "used_at"    TIMESTAMP WITH TIME ZONE,
+    "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY ("user_uuid", "code_hash")
+);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS "login_attempt"
+(
+    "key"          VARCHAR(512) NOT NULL,
+    "failures"     INTEGER NOT NULL DEFAULT 0,
+    "locked_until" TIMESTAMP WITH TIME ZONE,
+    "updated_at"   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY ("key")
+);
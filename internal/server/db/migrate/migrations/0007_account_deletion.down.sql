@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "account_audit";
+DROP INDEX IF EXISTS "user_delete_after_idx";
+ALTER TABLE "user" DROP COLUMN IF EXISTS "delete_after";
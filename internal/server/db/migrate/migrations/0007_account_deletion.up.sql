@@ -0,0 +1,14 @@
+ALTER TABLE "user" ADD COLUMN IF NOT EXISTS "delete_after" TIMESTAMP WITH TIME ZONE;
+CREATE INDEX IF NOT EXISTS "user_delete_after_idx" ON "user" ("delete_after") WHERE "delete_after" IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS "account_audit"
+(
+    "id"         BIGSERIAL,
+    "user_uuid"  UUID NOT NULL,
+    "event"      VARCHAR(64) NOT NULL,
+    "ip"         VARCHAR(64) NOT NULL DEFAULT '',
+    "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "account_audit_user_uuid_idx" ON "account_audit" ("user_uuid");
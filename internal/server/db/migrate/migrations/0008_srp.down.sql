@@ -0,0 +1,4 @@
+-- Пользователи, у которых остался только верификатор, после отката войти не смогут.
+DROP TABLE IF EXISTS "srp_handshake";
+ALTER TABLE "user" DROP COLUMN IF EXISTS "srp_verifier";
+ALTER TABLE "user" DROP COLUMN IF EXISTS "srp_salt";
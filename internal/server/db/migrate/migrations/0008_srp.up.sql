@@ -0,0 +1,15 @@
+ALTER TABLE "user" ADD COLUMN IF NOT EXISTS "srp_salt" BYTEA;
+ALTER TABLE "user" ADD COLUMN IF NOT EXISTS "srp_verifier" BYTEA;
+
+CREATE TABLE IF NOT EXISTS "srp_handshake"
+(
+    "id"           UUID DEFAULT gen_random_uuid(),
+    "user_uuid"    UUID,
+    "login"        VARCHAR(255) NOT NULL,
+    "proof_hash"   BYTEA NOT NULL,
+    "server_proof" BYTEA NOT NULL,
+    "expires_at"   TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "srp_handshake_expires_at_idx" ON "srp_handshake" ("expires_at");
@@ -0,0 +1,2 @@
+-- Хеши Argon2id не помещаются в VARCHAR(60), тип колонки не возвращается.
+SELECT 1;
@@ -0,0 +1,2 @@
+-- Хеш Argon2id в формате PHC длиннее 60 символов bcrypt.
+ALTER TABLE "user" ALTER COLUMN "password" TYPE TEXT;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS "api_token"
+(
+    "uuid"         UUID DEFAULT gen_random_uuid(),
+    "user_uuid"    UUID NOT NULL,
+    "name"         VARCHAR(255) NOT NULL,
+    "token_hash"   BYTEA NOT NULL,
+    "scope"        JSONB NOT NULL,
+    "expires_at"   TIMESTAMP WITH TIME ZONE NOT NULL,
+    "last_used_at" TIMESTAMP WITH TIME ZONE,
+    "revoked_at"   TIMESTAMP WITH TIME ZONE,
+    "created_at"   TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY ("uuid"),
+    CONSTRAINT api_token_hash_idx UNIQUE ("token_hash")
+);
+
+CREATE INDEX IF NOT EXISTS "api_token_user_uuid_idx" ON "api_token" ("user_uuid");
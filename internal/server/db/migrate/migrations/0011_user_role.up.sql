@@ -0,0 +1 @@
+ALTER TABLE "user" ADD COLUMN IF NOT EXISTS "role" VARCHAR(32) NOT NULL DEFAULT 'user';
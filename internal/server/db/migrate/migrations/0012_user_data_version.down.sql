@@ -0,0 +1 @@
+ALTER TABLE "user_data" DROP COLUMN IF EXISTS "version";
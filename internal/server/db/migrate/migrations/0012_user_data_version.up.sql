@@ -0,0 +1 @@
+ALTER TABLE "user_data" ADD COLUMN IF NOT EXISTS "version" BIGINT NOT NULL DEFAULT 1;
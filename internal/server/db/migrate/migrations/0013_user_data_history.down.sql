@@ -0,0 +1,3 @@
+ALTER TABLE "user" DROP COLUMN IF EXISTS "history_retention";
+
+DROP TABLE IF EXISTS "user_data_history";
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS "user_data_history"
+(
+    "uuid"        UUID DEFAULT gen_random_uuid(),
+    "item_uuid"   UUID NOT NULL,
+    "user_uuid"   UUID NOT NULL,
+    "title"       TEXT NOT NULL,
+    "type"        user_data_type NOT NULL,
+    "data"        BYTEA,
+    "metadata"    JSONB,
+    "key_version" INTEGER NOT NULL DEFAULT 0,
+    "sealed"      BOOLEAN NOT NULL DEFAULT FALSE,
+    "version"     BIGINT NOT NULL,
+    "created_at"  TIMESTAMP WITH TIME ZONE,
+    "updated_at"  TIMESTAMP WITH TIME ZONE,
+    "deleted"     BOOLEAN NOT NULL DEFAULT FALSE,
+    "archived_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY ("uuid")
+);
+
+CREATE INDEX IF NOT EXISTS "user_data_history_item_idx" ON "user_data_history" ("user_uuid", "item_uuid");
+
+-- Количество хранимых версий каждой записи пользователя, NULL - значение по умолчанию сервера.
+ALTER TABLE "user" ADD COLUMN IF NOT EXISTS "history_retention" INTEGER;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "user_data_tombstone";
+
+DROP INDEX IF EXISTS "user_data_revision_idx";
+ALTER TABLE "user_data" DROP COLUMN IF EXISTS "revision";
+ALTER TABLE "user" DROP COLUMN IF EXISTS "data_revision";
@@ -0,0 +1,22 @@
+-- Счётчик ревизий данных пользователя, увеличивается при каждом изменении его записей.
+ALTER TABLE "user" ADD COLUMN IF NOT EXISTS "data_revision" BIGINT NOT NULL DEFAULT 0;
+-- Ревизия, в которой запись изменена последний раз.
+ALTER TABLE "user_data" ADD COLUMN IF NOT EXISTS "revision" BIGINT NOT NULL DEFAULT 0;
+
+-- Существующие записи попадают в первую ревизию.
+UPDATE "user" SET "data_revision" = 1 WHERE "uuid" IN (SELECT "user_uuid" FROM "user_data");
+UPDATE "user_data" SET "revision" = 1;
+
+CREATE INDEX IF NOT EXISTS "user_data_revision_idx" ON "user_data" ("user_uuid", "revision");
+
+-- Удаленные записи, по ним клиенты узнают об удалении при синхронизации.
+CREATE TABLE IF NOT EXISTS "user_data_tombstone"
+(
+    "item_uuid"  UUID NOT NULL,
+    "user_uuid"  UUID NOT NULL,
+    "revision"   BIGINT NOT NULL,
+    "deleted_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY ("item_uuid")
+);
+
+CREATE INDEX IF NOT EXISTS "user_data_tombstone_revision_idx" ON "user_data_tombstone" ("user_uuid", "revision");
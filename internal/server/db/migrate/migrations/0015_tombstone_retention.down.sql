@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "user_data_tombstone_deleted_idx";
+
+ALTER TABLE "user" DROP COLUMN IF EXISTS "tombstone_revision";
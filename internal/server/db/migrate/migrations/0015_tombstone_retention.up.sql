@@ -0,0 +1,4 @@
+-- Наибольшая ревизия удаленных отметок об удалении: клиент с более ранней ревизией получает все записи заново.
+ALTER TABLE "user" ADD COLUMN IF NOT EXISTS "tombstone_revision" BIGINT NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS "user_data_tombstone_deleted_idx" ON "user_data_tombstone" ("deleted_at");
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "user_data_batch_created_idx";
+DROP TABLE IF EXISTS "user_data_batch";
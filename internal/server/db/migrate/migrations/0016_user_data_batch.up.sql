@@ -0,0 +1,11 @@
+-- Примененные пакеты изменений SyncBatch: повторная отправка пакета возвращает сохраненный результат.
+CREATE TABLE IF NOT EXISTS "user_data_batch"
+(
+    "uuid"       UUID NOT NULL,
+    "user_uuid"  UUID NOT NULL,
+    "result"     JSONB NOT NULL,
+    "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY ("user_uuid", "uuid")
+);
+
+CREATE INDEX IF NOT EXISTS "user_data_batch_created_idx" ON "user_data_batch" ("created_at");
@@ -0,0 +1,2 @@
+ALTER TABLE "user_data_tombstone" DROP CONSTRAINT IF EXISTS "user_data_tombstone_pkey";
+ALTER TABLE "user_data_tombstone" ADD PRIMARY KEY ("item_uuid");
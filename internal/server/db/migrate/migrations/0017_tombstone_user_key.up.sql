@@ -0,0 +1,4 @@
+-- Отметка об удалении принадлежит пользователю: ключ включает "user_uuid", чтобы удаление записи
+-- с тем же UUID у другого пользователя не перезаписывало чужую отметку.
+ALTER TABLE "user_data_tombstone" DROP CONSTRAINT IF EXISTS "user_data_tombstone_pkey";
+ALTER TABLE "user_data_tombstone" ADD PRIMARY KEY ("user_uuid", "item_uuid");
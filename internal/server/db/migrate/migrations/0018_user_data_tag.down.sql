@@ -0,0 +1,5 @@
+ALTER TABLE "vault_key_rotation" DROP COLUMN IF EXISTS "wrapped_tag_key";
+ALTER TABLE "vault_key" DROP COLUMN IF EXISTS "wrapped_tag_key";
+
+ALTER TABLE "user_data_history" DROP COLUMN IF EXISTS "tag_macs";
+ALTER TABLE "user_data" DROP COLUMN IF EXISTS "tag_macs";
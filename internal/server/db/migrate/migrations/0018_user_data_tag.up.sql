@@ -0,0 +1,12 @@
+-- HMAC тегов записи, вычисленные клиентом ключом тегов: по ним проверяется ограничение токена доступа.
+ALTER TABLE "user_data" ADD COLUMN IF NOT EXISTS "tag_macs" BYTEA[] NOT NULL DEFAULT '{}';
+ALTER TABLE "user_data_history" ADD COLUMN IF NOT EXISTS "tag_macs" BYTEA[] NOT NULL DEFAULT '{}';
+
+-- Ключ тегов, зашифрованный ключом из мастер-пароля, не меняется при ротации ключа хранилища.
+ALTER TABLE "vault_key" ADD COLUMN IF NOT EXISTS "wrapped_tag_key" BYTEA;
+ALTER TABLE "vault_key_rotation" ADD COLUMN IF NOT EXISTS "wrapped_tag_key" BYTEA;
+
+-- Ограничение "tag" по открытому значению тега заменено на "tag_mac": токены с ним отзываются,
+-- иначе без проверки тега они получили бы доступ ко всем записям.
+UPDATE "api_token" SET "revoked_at" = NOW()
+WHERE "revoked_at" IS NULL AND COALESCE("scope"->>'tag', '') <> '';
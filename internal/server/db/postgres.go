@@ -2,9 +2,6 @@ package db
 
 import (
 	"context"
-	"io"
-	"log"
-	"os"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -17,7 +14,6 @@ const (
 	// RequestTimeout таймаут запроса.
 	RequestTimeout = 1 * time.Second
 	connTimeout    = 1 * time.Second
-	schemaPath     = "./internal/server/db/schema.sql"
 )
 
 // NewPgxPool Новый пул соединений к БД.
@@ -36,34 +32,6 @@ func NewPgxPool(ctx context.Context, dsn string) (*pgxpool.Pool, error) {
 	return pool, nil
 }
 
-// CreateSchema создает структуру БД.
-func CreateSchema(ctx context.Context, pool *pgxpool.Pool) error {
-	c, cancel := context.WithTimeout(ctx, 10*time.Second)
-	defer cancel()
-
-	file, err := os.OpenFile(schemaPath, os.O_RDONLY, 0o644)
-	if err != nil {
-		return err
-	}
-	defer func() {
-		if err = file.Close(); err != nil {
-			log.Print("File close error:", "err", err)
-		}
-	}()
-
-	var buff []byte
-	if buff, err = io.ReadAll(file); err != nil {
-		return err
-	}
-
-	_, err = pool.Exec(c, string(buff))
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
 // ConnWrapper Интерфейс обертки для коннекта БД.
 type ConnWrapper interface {
 	Connection(ctx context.Context) Conn
@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"bytes"
+	"slices"
+	"time"
+
+	e "github.com/ktigay/goph-keeper/internal/entity"
+)
+
+// APIToken долгоживущий токен доступа к данным для автоматизации (CI).
+type APIToken struct {
+	UUID     string
+	UserUUID string
+	Name     string
+	// Hash SHA-256 токена, сам токен не хранится.
+	Hash       []byte
+	Scope      APITokenScope
+	ExpiresAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+// APITokenScope ограничения токена. Пустые ItemUUIDs и TagMAC - доступ ко всем записям пользователя.
+type APITokenScope struct {
+	ReadOnly  bool     `json:"read_only"`
+	ItemUUIDs []string `json:"item_uuids,omitempty"`
+	// TagMAC HMAC-SHA256 тега, вычисленный клиентом ключом тегов хранилища:
+	// доступны только записи, среди [e.UserData.TagMACs] которых он есть.
+	TagMAC []byte `json:"tag_mac,omitempty"`
+}
+
+// AllowsItem возвращает true, если запись с uuid входит в список записей токена.
+func (s APITokenScope) AllowsItem(uuid string) bool {
+	return len(s.ItemUUIDs) == 0 || slices.Contains(s.ItemUUIDs, uuid)
+}
+
+// Scopes разрешения, которые дает токен: данные (запись - если не ReadOnly) и чтение ключа хранилища.
+func (s APITokenScope) Scopes() []e.Scope {
+	scopes := []e.Scope{e.ScopeDataRead, e.ScopeVaultRead}
+	if !s.ReadOnly {
+		scopes = append(scopes, e.ScopeDataWrite)
+	}
+	return scopes
+}
+
+// Matches возвращает true, если запись доступна токену.
+func (s APITokenScope) Matches(d e.UserData) bool {
+	if !s.AllowsItem(d.UUID) {
+		return false
+	}
+	if len(s.TagMAC) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(d.TagMACs, func(mac []byte) bool {
+		return bytes.Equal(mac, s.TagMAC)
+	})
+}
+
+// APITokenPrefix префикс токена доступа, по нему токен отличается от JWT.
+const APITokenPrefix = "gk_pat_"
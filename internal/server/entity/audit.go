@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// События аудита аккаунта.
+const (
+	AuditPasswordChanged   = "password_changed"
+	AuditDeletionRequested = "deletion_requested"
+	AuditDeletionCancelled = "deletion_cancelled"
+	AuditAccountDeleted    = "account_deleted"
+	AuditSRPEnabled        = "srp_enabled"
+)
+
+// AuditRecord запись аудита аккаунта.
+type AuditRecord struct {
+	ID        int64
+	UserUUID  string
+	Event     string
+	IP        string
+	CreatedAt time.Time
+}
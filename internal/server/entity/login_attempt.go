@@ -0,0 +1,11 @@
+package entity
+
+import "time"
+
+// LoginAttempt неудачные попытки входа по ключу (логин, пользователь, IP).
+type LoginAttempt struct {
+	Key         string
+	Failures    int
+	LockedUntil *time.Time
+	UpdatedAt   time.Time
+}
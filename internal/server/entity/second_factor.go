@@ -0,0 +1,21 @@
+package entity
+
+// SecondFactor второй фактор (TOTP) аккаунта.
+type SecondFactor struct {
+	UserUUID string
+	// Secret секрет TOTP, в б.д. хранится зашифрованным ключом данных пользователя.
+	Secret  []byte
+	Enabled bool
+	// LastUsedStep последний принятый интервал TOTP, защищает от повторного использования кода.
+	LastUsedStep int64
+}
+
+// SecondFactorChallenge незавершенный вход, ожидающий второй фактор.
+type SecondFactorChallenge struct {
+	UserUUID      string
+	DeviceName    string
+	ClientVersion string
+	// SRPSalt и SRPVerifier верификатор, сохраняемый после подтверждения входа по паролю.
+	SRPSalt     []byte `json:",omitempty"`
+	SRPVerifier []byte `json:",omitempty"`
+}
@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"time"
+
+	e "github.com/ktigay/goph-keeper/internal/entity"
+)
+
+// Session сессия пользователя, привязанная к refresh токену.
+type Session struct {
+	UUID     string
+	UserUUID string
+	// RefreshHash SHA-256 действующего refresh токена.
+	RefreshHash []byte
+	// PreviousRefreshHash SHA-256 предыдущего refresh токена, для обнаружения повторного использования.
+	PreviousRefreshHash []byte
+	DeviceName          string
+	ClientVersion       string
+	IP                  string
+	ExpiresAt           time.Time
+	RevokedAt           *time.Time
+	LastSeenAt          time.Time
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+	// Role роль пользователя сессии, попадает в access токен.
+	Role e.Role
+}
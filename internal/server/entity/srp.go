@@ -0,0 +1,32 @@
+package entity
+
+import "time"
+
+// SRPHandshake незавершенный обмен SRP: ожидаемое доказательство клиента и ответ сервера.
+type SRPHandshake struct {
+	ID string
+	// UserUUID пустой для несуществующего логина: обмен выглядит обычно, но завершается ошибкой.
+	UserUUID string
+	Login    string
+	// ProofHash SHA-256 ожидаемого доказательства клиента, само доказательство не хранится.
+	ProofHash   []byte
+	ServerProof []byte
+	ExpiresAt   time.Time
+}
+
+// SRPChallenge ответ сервера на начало обмена SRP.
+type SRPChallenge struct {
+	HandshakeID  string
+	Salt         []byte
+	ServerPublic []byte
+	// Legacy сервер принимает вход по паролю: если вход по SRP не прошел, клиент входит паролем,
+	// и верификатор сохраняется при входе. При подтверждении пароля - у пользователя нет верификатора.
+	Legacy bool
+}
+
+// PasswordProof подтверждение пароля: доказательство SRP или пароль, если верификатора ещё нет.
+type PasswordProof struct {
+	HandshakeID string
+	Proof       []byte
+	Password    string
+}
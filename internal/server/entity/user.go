@@ -4,9 +4,15 @@ import "time"
 
 // User структура пользователя.
 type User struct {
-	UUID      string
-	Login     string `validate:"required"`
-	Password  string `validate:"required"`
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	UUID  string
+	Login string `validate:"required"`
+	// Password хеш пароля (Argon2id, у старых пользователей bcrypt), пустой после перехода на SRP.
+	Password string
+	// SRPSalt и SRPVerifier верификатор SRP-6a, nil - вход только по паролю.
+	SRPSalt     []byte
+	SRPVerifier []byte
+	// DeleteAfter время, после которого аккаунт будет удален, nil - удаление не запрошено.
+	DeleteAfter *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
 }
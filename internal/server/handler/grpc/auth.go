@@ -2,13 +2,26 @@ package grpc
 
 import (
 	"context"
+	"errors"
+	"strings"
+	"time"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/ktigay/goph-keeper/internal/contracts/v1/auth"
 	e "github.com/ktigay/goph-keeper/internal/entity"
+	c "github.com/ktigay/goph-keeper/internal/server/context"
 	"github.com/ktigay/goph-keeper/internal/server/entity"
+	accountsrv "github.com/ktigay/goph-keeper/internal/server/service/account"
+	authsrv "github.com/ktigay/goph-keeper/internal/server/service/auth"
+	sfsrv "github.com/ktigay/goph-keeper/internal/server/service/secondfactor"
+	sessionsrv "github.com/ktigay/goph-keeper/internal/server/service/session"
+	"github.com/ktigay/goph-keeper/internal/srp"
 )
 
 // AuthService сервис аутентификации.
@@ -19,6 +32,65 @@ type AuthService interface {
 	Login(ctx context.Context, login, password string) (*entity.User, error)
 }
 
+// SRPService сервис входа по SRP.
+//
+//go:generate mockgen -destination=./mocks/mock_srp.go -package=mocks github.com/ktigay/goph-keeper/internal/server/handler/grpc SRPService
+type SRPService interface {
+	Register(ctx context.Context, login string, salt, verifier []byte) (*entity.User, error)
+	Start(ctx context.Context, login string, clientPublic []byte) (*entity.SRPChallenge, error)
+	StartForUser(ctx context.Context, userUUID string, clientPublic []byte) (*entity.SRPChallenge, error)
+	Finish(ctx context.Context, handshakeID string, proof []byte) (*entity.SRPHandshake, error)
+}
+
+// SessionService сервис сессий.
+//
+//go:generate mockgen -destination=./mocks/mock_session.go -package=mocks github.com/ktigay/goph-keeper/internal/server/handler/grpc SessionService
+type SessionService interface {
+	Create(ctx context.Context, sess entity.Session) (*entity.Session, string, error)
+	Refresh(ctx context.Context, token string) (*entity.Session, string, error)
+	List(ctx context.Context, userUUID string) ([]entity.Session, error)
+	Revoke(ctx context.Context, userUUID, uuid string) error
+	RevokeAllOther(ctx context.Context, userUUID, currentUUID string) (int64, error)
+	RevokeAll(ctx context.Context, userUUID string) (int64, error)
+}
+
+// SecondFactorService сервис второго фактора аккаунта.
+//
+//go:generate mockgen -destination=./mocks/mock_secondfactor.go -package=mocks github.com/ktigay/goph-keeper/internal/server/handler/grpc SecondFactorService
+type SecondFactorService interface {
+	Enroll(ctx context.Context, userUUID string) (string, []string, error)
+	Confirm(ctx context.Context, userUUID, code string) error
+	IsEnabled(ctx context.Context, userUUID string) (bool, error)
+	Verify(ctx context.Context, userUUID, code string) error
+}
+
+// ChallengeWrapper обработчик токенов входа, ожидающего второй фактор.
+//
+//go:generate mockgen -destination=./mocks/mock_challenge.go -package=mocks github.com/ktigay/goph-keeper/internal/server/handler/grpc ChallengeWrapper
+type ChallengeWrapper interface {
+	GenerateToken(payload entity.SecondFactorChallenge) (string, error)
+	ParseToken(s string) (*entity.SecondFactorChallenge, error)
+}
+
+// AccountService сервис управления аккаунтом.
+//
+//go:generate mockgen -destination=./mocks/mock_account.go -package=mocks github.com/ktigay/goph-keeper/internal/server/handler/grpc AccountService
+type AccountService interface {
+	ChangePassword(ctx context.Context, identity e.Identity, current entity.PasswordProof, salt, verifier []byte, ip string) (int64, error)
+	RequestDeletion(ctx context.Context, userUUID string, current entity.PasswordProof, ip string) (time.Time, error)
+	CancelDeletion(ctx context.Context, userUUID, ip string) error
+	UpgradeToSRP(ctx context.Context, userUUID string, salt, verifier []byte, ip string) error
+}
+
+// LoginLimiter ограничитель неудачных попыток входа.
+//
+//go:generate mockgen -destination=./mocks/mock_limiter.go -package=mocks github.com/ktigay/goph-keeper/internal/server/handler/grpc LoginLimiter
+type LoginLimiter interface {
+	Check(ctx context.Context, keys ...authsrv.LimiterKey) error
+	Fail(ctx context.Context, keys ...authsrv.LimiterKey) error
+	Reset(ctx context.Context, keys ...authsrv.LimiterKey) error
+}
+
 // JWTWrapper обработчик JWT.
 //
 //go:generate mockgen -destination=./mocks/mock_jwt.go -package=mocks github.com/ktigay/goph-keeper/internal/server/handler/grpc JWTWrapper
@@ -30,8 +102,14 @@ type JWTWrapper interface {
 // AuthHandler обработчик аутентификации.
 type AuthHandler struct {
 	auth.UnimplementedAuthServiceServer
-	srv AuthService
-	jwt JWTWrapper
+	srv       AuthService
+	srp       SRPService
+	sess      SessionService
+	sf        SecondFactorService
+	jwt       JWTWrapper
+	challenge ChallengeWrapper
+	limiter   LoginLimiter
+	account   AccountService
 }
 
 // Register регистрирует пользователя.
@@ -42,7 +120,7 @@ func (a *AuthHandler) Register(ctx context.Context, req *auth.RegisterRequest) (
 	)
 
 	if usr, err = a.srv.Register(ctx, req.GetLogin(), req.GetPassword()); err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, authStatus(err, req.GetLogin(), req.GetPassword())
 	}
 
 	return &auth.RegisterResponse{
@@ -50,34 +128,538 @@ func (a *AuthHandler) Register(ctx context.Context, req *auth.RegisterRequest) (
 	}, nil
 }
 
-// Login авторизует пользователя.
+// Login авторизует пользователя по паролю (устаревший способ, см. [AuthHandler.SrpLogin]).
+// Если передан верификатор SRP, он сохраняется после входа и следующий вход выполняется по SRP.
+// Если для аккаунта включен второй фактор, вместо токенов возвращает токен подтверждения
+// для [AuthHandler.VerifySecondFactor].
 func (a *AuthHandler) Login(ctx context.Context, req *auth.LoginRequest) (*auth.LoginResponse, error) {
 	var (
-		usr   *entity.User
-		token string
-		err   error
+		usr       *entity.User
+		challenge string
+		token     string
+		refresh   string
+		err       error
 	)
 
+	if len(req.GetSrpVerifier()) > 0 && (len(req.GetSrpSalt()) == 0 || srp.CheckVerifier(req.GetSrpVerifier()) != nil) {
+		return nil, status.Error(codes.InvalidArgument, authsrv.ErrInvalidVerifier.Error())
+	}
+
+	loginKey := authsrv.LimiterKey{Scope: authsrv.ScopeLogin, Value: strings.TrimSpace(req.GetLogin())}
+	ipKey := authsrv.LimiterKey{Scope: authsrv.ScopeIP, Value: c.PeerIP(ctx)}
+	if err = a.limiter.Check(ctx, loginKey, ipKey); err != nil {
+		return nil, limiterStatus(err)
+	}
+
 	if usr, err = a.srv.Login(ctx, req.GetLogin(), req.GetPassword()); err != nil {
+		if errors.Is(err, authsrv.ErrUserNotFound) || errors.Is(err, authsrv.ErrWrongPassword) {
+			if fErr := a.limiter.Fail(ctx, loginKey, ipKey); fErr != nil {
+				return nil, status.Error(codes.Internal, fErr.Error())
+			}
+		}
+		return nil, authStatus(err, req.GetLogin(), req.GetPassword())
+	}
+	// Счетчик IP не сбрасывается: иначе успешные входы в свой аккаунт позволят подбирать чужие пароли.
+	if err = a.limiter.Reset(ctx, loginKey); err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	token, err = a.jwt.GenerateToken(e.Identity{
-		UUID: usr.UUID,
+	token, refresh, challenge, err = a.completeLogin(ctx, entity.SecondFactorChallenge{
+		UserUUID:      usr.UUID,
+		DeviceName:    req.GetDeviceName(),
+		ClientVersion: req.GetClientVersion(),
+		SRPSalt:       req.GetSrpSalt(),
+		SRPVerifier:   req.GetSrpVerifier(),
 	})
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
 	return &auth.LoginResponse{
-		Token: token,
+		Token:          token,
+		RefreshToken:   refresh,
+		ChallengeToken: challenge,
 	}, nil
 }
 
+// SrpRegister регистрирует пользователя с верификатором SRP, пароль серверу не передается.
+func (a *AuthHandler) SrpRegister(ctx context.Context, req *auth.SrpRegisterRequest) (*auth.RegisterResponse, error) {
+	usr, err := a.srp.Register(ctx, req.GetLogin(), req.GetSalt(), req.GetVerifier())
+	if err != nil {
+		return nil, authStatus(err, req.GetLogin(), string(req.GetVerifier()))
+	}
+
+	return &auth.RegisterResponse{
+		UserUuid: usr.UUID,
+	}, nil
+}
+
+// SrpStart начинает вход по SRP: возвращает соль и открытое значение сервера.
+// Если у пользователя ещё нет верификатора, возвращает legacy - клиент входит по паролю через [AuthHandler.Login]
+// и передает верификатор, который сохраняется после входа.
+func (a *AuthHandler) SrpStart(ctx context.Context, req *auth.SrpStartRequest) (*auth.SrpStartResponse, error) {
+	loginKey := authsrv.LimiterKey{Scope: authsrv.ScopeLogin, Value: strings.TrimSpace(req.GetLogin())}
+	ipKey := authsrv.LimiterKey{Scope: authsrv.ScopeIP, Value: c.PeerIP(ctx)}
+	if err := a.limiter.Check(ctx, loginKey, ipKey); err != nil {
+		return nil, limiterStatus(err)
+	}
+
+	ch, err := a.srp.Start(ctx, req.GetLogin(), req.GetClientPublic())
+	if err != nil {
+		return nil, authStatus(err, req.GetLogin(), string(req.GetClientPublic()))
+	}
+	return mapSRPChallenge(ch), nil
+}
+
+// SrpLogin завершает вход по SRP доказательством клиента. Возвращает доказательство сервера,
+// по которому клиент проверяет, что сервер знает верификатор.
+func (a *AuthHandler) SrpLogin(ctx context.Context, req *auth.SrpLoginRequest) (*auth.SrpLoginResponse, error) {
+	var (
+		h         *entity.SRPHandshake
+		token     string
+		refresh   string
+		challenge string
+		err       error
+	)
+
+	h, err = a.srp.Finish(ctx, req.GetHandshakeId(), req.GetClientProof())
+	if h == nil {
+		return nil, authStatus(err, "", "")
+	}
+
+	// Блокировка проверяется до результата доказательства, чтобы заблокированный вход не подтверждал пароль.
+	loginKey := authsrv.LimiterKey{Scope: authsrv.ScopeLogin, Value: h.Login}
+	ipKey := authsrv.LimiterKey{Scope: authsrv.ScopeIP, Value: c.PeerIP(ctx)}
+	if lErr := a.limiter.Check(ctx, loginKey, ipKey); lErr != nil {
+		return nil, limiterStatus(lErr)
+	}
+	if err != nil {
+		if errors.Is(err, authsrv.ErrUserNotFound) || errors.Is(err, authsrv.ErrWrongPassword) {
+			if fErr := a.limiter.Fail(ctx, loginKey, ipKey); fErr != nil {
+				return nil, status.Error(codes.Internal, fErr.Error())
+			}
+		}
+		return nil, authStatus(err, h.Login, "")
+	}
+	if err = a.limiter.Reset(ctx, loginKey); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	token, refresh, challenge, err = a.completeLogin(ctx, entity.SecondFactorChallenge{
+		UserUUID:      h.UserUUID,
+		DeviceName:    req.GetDeviceName(),
+		ClientVersion: req.GetClientVersion(),
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &auth.SrpLoginResponse{
+		ServerProof:    h.ServerProof,
+		Token:          token,
+		RefreshToken:   refresh,
+		ChallengeToken: challenge,
+	}, nil
+}
+
+// SrpProofStart начинает обмен SRP для авторизованного пользователя,
+// доказательство передается в [AuthHandler.ChangePassword] и [AuthHandler.DeleteAccount].
+func (a *AuthHandler) SrpProofStart(ctx context.Context, req *auth.SrpProofStartRequest) (*auth.SrpStartResponse, error) {
+	var (
+		identity *e.Identity
+		ch       *entity.SRPChallenge
+		err      error
+	)
+
+	if identity, err = c.IdentityFromContext(ctx); err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "authorization required: %v", err)
+	}
+
+	userKey := authsrv.LimiterKey{Scope: authsrv.ScopeUser, Value: identity.UUID}
+	if err = a.limiter.Check(ctx, userKey); err != nil {
+		return nil, limiterStatus(err)
+	}
+
+	if ch, err = a.srp.StartForUser(ctx, identity.UUID, req.GetClientPublic()); err != nil {
+		if errors.Is(err, authsrv.ErrUserNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, authStatus(err, identity.UUID, string(req.GetClientPublic()))
+	}
+	return mapSRPChallenge(ch), nil
+}
+
+// VerifySecondFactor завершает вход кодом второго фактора.
+func (a *AuthHandler) VerifySecondFactor(ctx context.Context, req *auth.VerifySecondFactorRequest) (*auth.VerifySecondFactorResponse, error) {
+	var (
+		challenge *entity.SecondFactorChallenge
+		token     string
+		refresh   string
+		err       error
+	)
+
+	if challenge, err = a.challenge.ParseToken(req.GetChallengeToken()); err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid challenge: %v", err)
+	}
+
+	// Неудачи считаются по пользователю, а не по токену подтверждения, который можно получить заново.
+	userKey := authsrv.LimiterKey{Scope: authsrv.ScopeUser, Value: challenge.UserUUID}
+	ipKey := authsrv.LimiterKey{Scope: authsrv.ScopeIP, Value: c.PeerIP(ctx)}
+	if err = a.limiter.Check(ctx, userKey, ipKey); err != nil {
+		return nil, limiterStatus(err)
+	}
+
+	if err = a.sf.Verify(ctx, challenge.UserUUID, req.GetCode()); err != nil {
+		if errors.Is(err, sfsrv.ErrInvalidCode) {
+			if fErr := a.limiter.Fail(ctx, userKey, ipKey); fErr != nil {
+				return nil, status.Error(codes.Internal, fErr.Error())
+			}
+			return nil, statusWithDetails(codes.Unauthenticated, err.Error(), reasonInfo(e.ReasonInvalidSecondFactorCode))
+		}
+		return nil, status.Errorf(mapSecondFactorErrorToCode(err), "%v", err)
+	}
+	if err = a.limiter.Reset(ctx, userKey); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if token, refresh, err = a.finishLogin(ctx, *challenge); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &auth.VerifySecondFactorResponse{
+		Token:        token,
+		RefreshToken: refresh,
+	}, nil
+}
+
+// EnrollSecondFactor создает секрет второго фактора и коды восстановления.
+func (a *AuthHandler) EnrollSecondFactor(ctx context.Context, _ *auth.EnrollSecondFactorRequest) (*auth.EnrollSecondFactorResponse, error) {
+	var (
+		identity *e.Identity
+		uri      string
+		recovery []string
+		err      error
+	)
+
+	if identity, err = c.IdentityFromContext(ctx); err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "authorization required: %v", err)
+	}
+
+	if uri, recovery, err = a.sf.Enroll(ctx, identity.UUID); err != nil {
+		return nil, status.Errorf(mapSecondFactorErrorToCode(err), "%v", err)
+	}
+
+	return &auth.EnrollSecondFactorResponse{
+		Uri:           uri,
+		RecoveryCodes: recovery,
+	}, nil
+}
+
+// ConfirmSecondFactor включает второй фактор после проверки кода.
+func (a *AuthHandler) ConfirmSecondFactor(ctx context.Context, req *auth.ConfirmSecondFactorRequest) (*auth.ConfirmSecondFactorResponse, error) {
+	var (
+		identity *e.Identity
+		err      error
+	)
+
+	if identity, err = c.IdentityFromContext(ctx); err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "authorization required: %v", err)
+	}
+
+	if err = a.sf.Confirm(ctx, identity.UUID, req.GetCode()); err != nil {
+		code := mapSecondFactorErrorToCode(err)
+		if code == codes.Unauthenticated {
+			// Неверный код при подтверждении не должен приводить к обновлению токена.
+			code = codes.InvalidArgument
+		}
+		return nil, status.Errorf(code, "%v", err)
+	}
+
+	return &auth.ConfirmSecondFactorResponse{}, nil
+}
+
+// ChangePassword меняет пароль, остальные сессии пользователя при этом отзываются.
+// Текущий пароль подтверждается доказательством SRP (или паролем, если верификатора ещё нет),
+// новый передается верификатором.
+func (a *AuthHandler) ChangePassword(ctx context.Context, req *auth.ChangePasswordRequest) (*auth.ChangePasswordResponse, error) {
+	var (
+		identity *e.Identity
+		revoked  int64
+		err      error
+	)
+
+	if identity, err = c.IdentityFromContext(ctx); err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "authorization required: %v", err)
+	}
+
+	userKey := authsrv.LimiterKey{Scope: authsrv.ScopeUser, Value: identity.UUID}
+	if err = a.limiter.Check(ctx, userKey); err != nil {
+		return nil, limiterStatus(err)
+	}
+
+	current := entity.PasswordProof{
+		HandshakeID: req.GetHandshakeId(),
+		Proof:       req.GetClientProof(),
+		Password:    req.GetCurrentPassword(),
+	}
+	if revoked, err = a.account.ChangePassword(ctx, *identity, current, req.GetNewSalt(), req.GetNewVerifier(), c.PeerIP(ctx)); err != nil {
+		return nil, a.accountStatus(ctx, userKey, err)
+	}
+
+	return &auth.ChangePasswordResponse{
+		RevokedSessions: uint32(revoked),
+	}, nil
+}
+
+// DeleteAccount запрашивает удаление аккаунта. Аккаунт и все данные удаляются по истечении периода ожидания.
+func (a *AuthHandler) DeleteAccount(ctx context.Context, req *auth.DeleteAccountRequest) (*auth.DeleteAccountResponse, error) {
+	var (
+		identity    *e.Identity
+		deleteAfter time.Time
+		err         error
+	)
+
+	if identity, err = c.IdentityFromContext(ctx); err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "authorization required: %v", err)
+	}
+
+	userKey := authsrv.LimiterKey{Scope: authsrv.ScopeUser, Value: identity.UUID}
+	if err = a.limiter.Check(ctx, userKey); err != nil {
+		return nil, limiterStatus(err)
+	}
+
+	current := entity.PasswordProof{
+		HandshakeID: req.GetHandshakeId(),
+		Proof:       req.GetClientProof(),
+		Password:    req.GetPassword(),
+	}
+	if deleteAfter, err = a.account.RequestDeletion(ctx, identity.UUID, current, c.PeerIP(ctx)); err != nil {
+		return nil, a.accountStatus(ctx, userKey, err)
+	}
+
+	return &auth.DeleteAccountResponse{
+		DeleteAfter: timestamppb.New(deleteAfter),
+	}, nil
+}
+
+// CancelAccountDeletion отменяет запрошенное удаление аккаунта.
+func (a *AuthHandler) CancelAccountDeletion(ctx context.Context, _ *auth.CancelAccountDeletionRequest) (*auth.CancelAccountDeletionResponse, error) {
+	var (
+		identity *e.Identity
+		err      error
+	)
+
+	if identity, err = c.IdentityFromContext(ctx); err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "authorization required: %v", err)
+	}
+
+	if err = a.account.CancelDeletion(ctx, identity.UUID, c.PeerIP(ctx)); err != nil {
+		return nil, a.accountStatus(ctx, authsrv.LimiterKey{}, err)
+	}
+
+	return &auth.CancelAccountDeletionResponse{}, nil
+}
+
+// Refresh выдает новый access токен по refresh токену, refresh токен при этом заменяется.
+func (a *AuthHandler) Refresh(ctx context.Context, req *auth.RefreshRequest) (*auth.RefreshResponse, error) {
+	var (
+		sess    *entity.Session
+		token   string
+		refresh string
+		err     error
+	)
+
+	if sess, refresh, err = a.sess.Refresh(ctx, req.GetRefreshToken()); err != nil {
+		if errors.Is(err, sessionsrv.ErrSessionNotFound) ||
+			errors.Is(err, sessionsrv.ErrSessionExpired) ||
+			errors.Is(err, sessionsrv.ErrSessionRevoked) ||
+			errors.Is(err, sessionsrv.ErrRefreshTokenReused) {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if token, err = a.generateToken(sess); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &auth.RefreshResponse{
+		Token:        token,
+		RefreshToken: refresh,
+	}, nil
+}
+
+// completeLogin завершает проверенный вход: если включен второй фактор, возвращает токен подтверждения,
+// иначе сохраняет переданный верификатор SRP и выдает пару токенов.
+func (a *AuthHandler) completeLogin(ctx context.Context, ch entity.SecondFactorChallenge) (string, string, string, error) {
+	enabled, err := a.sf.IsEnabled(ctx, ch.UserUUID)
+	if err != nil {
+		return "", "", "", err
+	}
+	if enabled {
+		// Верификатор сохраняется только после второго фактора: иначе знание пароля позволило бы его сменить.
+		challenge, err := a.challenge.GenerateToken(ch)
+		return "", "", challenge, err
+	}
+
+	token, refresh, err := a.finishLogin(ctx, ch)
+	return token, refresh, "", err
+}
+
+// finishLogin сохраняет верификатор SRP, если вход выполнен по паролю, и выдает пару токенов.
+func (a *AuthHandler) finishLogin(ctx context.Context, ch entity.SecondFactorChallenge) (string, string, error) {
+	if len(ch.SRPVerifier) > 0 {
+		if err := a.account.UpgradeToSRP(ctx, ch.UserUUID, ch.SRPSalt, ch.SRPVerifier, c.PeerIP(ctx)); err != nil {
+			return "", "", err
+		}
+	}
+	return a.startSession(ctx, ch.UserUUID, ch.DeviceName, ch.ClientVersion)
+}
+
+// startSession создает сессию устройства и выдает для неё пару токенов.
+func (a *AuthHandler) startSession(ctx context.Context, userUUID, deviceName, clientVersion string) (string, string, error) {
+	sess, refresh, err := a.sess.Create(ctx, entity.Session{
+		UserUUID:      userUUID,
+		DeviceName:    deviceName,
+		ClientVersion: clientVersion,
+		IP:            c.PeerIP(ctx),
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	token, err := a.generateToken(sess)
+	if err != nil {
+		return "", "", err
+	}
+	return token, refresh, nil
+}
+
+// authStatus преобразует ошибку сервиса аутентификации в статус с ErrorInfo.
+// Неизвестный логин и неверный пароль неразличимы, чтобы по ответу нельзя было перебирать логины.
+func authStatus(err error, login, password string) error {
+	switch {
+	case errors.Is(err, authsrv.ErrLoginOrPwdEmpty):
+		var violations []*errdetails.BadRequest_FieldViolation
+		if strings.TrimSpace(login) == "" {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: "login", Description: "login is required"})
+		}
+		if strings.TrimSpace(password) == "" {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: "password", Description: "password is required"})
+		}
+		return statusWithDetails(codes.InvalidArgument, err.Error(),
+			reasonInfo(e.ReasonEmptyCredentials),
+			&errdetails.BadRequest{FieldViolations: violations},
+		)
+	case errors.Is(err, authsrv.ErrUserNotFound), errors.Is(err, authsrv.ErrWrongPassword):
+		return statusWithDetails(codes.Unauthenticated, "invalid login or password", reasonInfo(e.ReasonInvalidCredentials))
+	case errors.Is(err, authsrv.ErrLoginExists):
+		return statusWithDetails(codes.AlreadyExists, err.Error(), reasonInfo(e.ReasonLoginExists))
+	case errors.Is(err, authsrv.ErrLegacyDisabled):
+		return statusWithDetails(codes.FailedPrecondition, err.Error(), reasonInfo(e.ReasonLegacyLoginDisabled))
+	case errors.Is(err, authsrv.ErrHandshakeNotFound):
+		return statusWithDetails(codes.FailedPrecondition, err.Error(), reasonInfo(e.ReasonHandshakeExpired))
+	case errors.Is(err, authsrv.ErrInvalidVerifier), errors.Is(err, authsrv.ErrInvalidPublicKey):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// accountStatus преобразует ошибку сервиса аккаунта в статус, неверный пароль учитывается ограничителем.
+func (a *AuthHandler) accountStatus(ctx context.Context, userKey authsrv.LimiterKey, err error) error {
+	switch {
+	case errors.Is(err, accountsrv.ErrWrongPassword):
+		if fErr := a.limiter.Fail(ctx, userKey); fErr != nil {
+			return status.Error(codes.Internal, fErr.Error())
+		}
+		// Не Unauthenticated: токен действителен, клиент не должен его обновлять.
+		return statusWithDetails(codes.InvalidArgument, err.Error(), reasonInfo(e.ReasonWrongPassword))
+	case errors.Is(err, accountsrv.ErrPasswordEmpty):
+		return statusWithDetails(codes.InvalidArgument, err.Error(), reasonInfo(e.ReasonEmptyCredentials))
+	case errors.Is(err, accountsrv.ErrInvalidVerifier):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, authsrv.ErrHandshakeNotFound):
+		return statusWithDetails(codes.FailedPrecondition, err.Error(), reasonInfo(e.ReasonHandshakeExpired))
+	case errors.Is(err, accountsrv.ErrDeletionNotRequested):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, accountsrv.ErrUserNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// limiterStatus возвращает ResourceExhausted с RetryInfo для блокировки входа.
+func limiterStatus(err error) error {
+	var locked *authsrv.LockedError
+	if !errors.As(err, &locked) {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	return statusWithDetails(codes.ResourceExhausted, err.Error(),
+		reasonInfo(e.ReasonTooManyAttempts),
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(locked.RetryAfter)},
+	)
+}
+
+func reasonInfo(reason string) *errdetails.ErrorInfo {
+	return &errdetails.ErrorInfo{
+		Reason: reason,
+		Domain: e.ErrorDomain,
+	}
+}
+
+// statusWithDetails статус с деталями; если детали не сериализуются, возвращается статус без них.
+func statusWithDetails(code codes.Code, msg string, details ...protoadapt.MessageV1) error {
+	st := status.New(code, msg)
+	if withDetails, err := st.WithDetails(details...); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
+func mapSecondFactorErrorToCode(err error) codes.Code {
+	switch {
+	case errors.Is(err, sfsrv.ErrInvalidCode):
+		return codes.Unauthenticated
+	case errors.Is(err, sfsrv.ErrNotEnrolled), errors.Is(err, sfsrv.ErrAlreadyEnabled):
+		return codes.FailedPrecondition
+	case errors.Is(err, sfsrv.ErrUserNotFound):
+		return codes.NotFound
+	default:
+		return codes.Internal
+	}
+}
+
+func mapSRPChallenge(ch *entity.SRPChallenge) *auth.SrpStartResponse {
+	return &auth.SrpStartResponse{
+		HandshakeId:  ch.HandshakeID,
+		Salt:         ch.Salt,
+		ServerPublic: ch.ServerPublic,
+		Legacy:       ch.Legacy,
+	}
+}
+
+func (a *AuthHandler) generateToken(sess *entity.Session) (string, error) {
+	return a.jwt.GenerateToken(e.Identity{
+		UUID:        sess.UserUUID,
+		SessionUUID: sess.UUID,
+		Role:        sess.Role,
+		Scopes:      e.SessionScopes(),
+	})
+}
+
 // NewAuthHandler конструктор.
-func NewAuthHandler(s AuthService, j JWTWrapper) *AuthHandler {
+func NewAuthHandler(s AuthService, srpSrv SRPService, sess SessionService, sf SecondFactorService, j JWTWrapper, ch ChallengeWrapper, l LoginLimiter, acc AccountService) *AuthHandler {
 	return &AuthHandler{
-		srv: s,
-		jwt: j,
+		srv:       s,
+		srp:       srpSrv,
+		sess:      sess,
+		sf:        sf,
+		jwt:       j,
+		challenge: ch,
+		limiter:   l,
+		account:   acc,
 	}
 }
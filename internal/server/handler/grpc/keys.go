@@ -0,0 +1,49 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/ktigay/goph-keeper/internal/contracts/v1/keys"
+	"github.com/ktigay/goph-keeper/internal/server/security"
+)
+
+// KeyProvider источник открытых ключей проверки токенов.
+//
+//go:generate mockgen -destination=./mocks/mock_keys.go -package=mocks github.com/ktigay/goph-keeper/internal/server/handler/grpc KeyProvider
+type KeyProvider interface {
+	JWKS() []security.JWK
+}
+
+// KeyHandler обработчик открытых ключей проверки токенов.
+type KeyHandler struct {
+	keys.UnimplementedKeyServiceServer
+	keys KeyProvider
+}
+
+// GetJWKS возвращает открытые ключи, которыми можно проверить токены goph-keeper.
+func (h *KeyHandler) GetJWKS(_ context.Context, _ *keys.GetJWKSRequest) (*keys.GetJWKSResponse, error) {
+	jwks := h.keys.JWKS()
+
+	resp := &keys.GetJWKSResponse{
+		Keys: make([]*keys.JsonWebKey, 0, len(jwks)),
+	}
+	for _, k := range jwks {
+		resp.Keys = append(resp.Keys, &keys.JsonWebKey{
+			Kty: k.Kty,
+			Crv: k.Crv,
+			Kid: k.Kid,
+			Use: k.Use,
+			Alg: k.Alg,
+			X:   k.X,
+			Y:   k.Y,
+		})
+	}
+	return resp, nil
+}
+
+// NewKeyHandler конструктор.
+func NewKeyHandler(k KeyProvider) *KeyHandler {
+	return &KeyHandler{
+		keys: k,
+	}
+}
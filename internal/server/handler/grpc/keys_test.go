@@ -0,0 +1,32 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/ktigay/goph-keeper/internal/contracts/v1/keys"
+	"github.com/ktigay/goph-keeper/internal/server/handler/grpc/mocks"
+	"github.com/ktigay/goph-keeper/internal/server/security"
+)
+
+func TestKeyHandler_GetJWKS(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	provider := mocks.NewMockKeyProvider(ctrl)
+	provider.EXPECT().JWKS().Times(1).Return([]security.JWK{
+		{Kty: "OKP", Crv: "Ed25519", Kid: "2024-01", Use: "sig", Alg: "EdDSA", X: "x-ed"},
+		{Kty: "EC", Crv: "P-256", Kid: "2024-06", Use: "sig", Alg: "ES256", X: "x-ec", Y: "y-ec"},
+	})
+
+	got, err := NewKeyHandler(provider).GetJWKS(context.Background(), &keys.GetJWKSRequest{})
+	if err != nil {
+		t.Fatalf("GetJWKS() error = %v", err)
+	}
+	if len(got.GetKeys()) != 2 {
+		t.Fatalf("GetJWKS() got %d keys, want 2", len(got.GetKeys()))
+	}
+	if k := got.GetKeys()[1]; k.GetKid() != "2024-06" || k.GetAlg() != "ES256" || k.GetX() != "x-ec" || k.GetY() != "y-ec" {
+		t.Errorf("GetJWKS() got[1] = %v", k)
+	}
+}
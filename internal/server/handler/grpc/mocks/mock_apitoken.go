@@ -0,0 +1,82 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ktigay/goph-keeper/internal/server/handler/grpc (interfaces: APITokenService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	entity "github.com/ktigay/goph-keeper/internal/server/entity"
+)
+
+// MockAPITokenService is a mock of APITokenService interface.
+type MockAPITokenService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPITokenServiceMockRecorder
+}
+
+// MockAPITokenServiceMockRecorder is the mock recorder for MockAPITokenService.
+type MockAPITokenServiceMockRecorder struct {
+	mock *MockAPITokenService
+}
+
+// NewMockAPITokenService creates a new mock instance.
+func NewMockAPITokenService(ctrl *gomock.Controller) *MockAPITokenService {
+	mock := &MockAPITokenService{ctrl: ctrl}
+	mock.recorder = &MockAPITokenServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPITokenService) EXPECT() *MockAPITokenServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAPITokenService) Create(arg0 context.Context, arg1, arg2 string, arg3 time.Time, arg4 entity.APITokenScope) (*entity.APIToken, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*entity.APIToken)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAPITokenServiceMockRecorder) Create(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAPITokenService)(nil).Create), arg0, arg1, arg2, arg3, arg4)
+}
+
+// List mocks base method.
+func (m *MockAPITokenService) List(arg0 context.Context, arg1 string) ([]entity.APIToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]entity.APIToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockAPITokenServiceMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAPITokenService)(nil).List), arg0, arg1)
+}
+
+// Revoke mocks base method.
+func (m *MockAPITokenService) Revoke(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockAPITokenServiceMockRecorder) Revoke(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockAPITokenService)(nil).Revoke), arg0, arg1, arg2)
+}
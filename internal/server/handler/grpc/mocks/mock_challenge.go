@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ktigay/goph-keeper/internal/server/handler/grpc (interfaces: ChallengeWrapper)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	entity "github.com/ktigay/goph-keeper/internal/server/entity"
+)
+
+// MockChallengeWrapper is a mock of ChallengeWrapper interface.
+type MockChallengeWrapper struct {
+	ctrl     *gomock.Controller
+	recorder *MockChallengeWrapperMockRecorder
+}
+
+// MockChallengeWrapperMockRecorder is the mock recorder for MockChallengeWrapper.
+type MockChallengeWrapperMockRecorder struct {
+	mock *MockChallengeWrapper
+}
+
+// NewMockChallengeWrapper creates a new mock instance.
+func NewMockChallengeWrapper(ctrl *gomock.Controller) *MockChallengeWrapper {
+	mock := &MockChallengeWrapper{ctrl: ctrl}
+	mock.recorder = &MockChallengeWrapperMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChallengeWrapper) EXPECT() *MockChallengeWrapperMockRecorder {
+	return m.recorder
+}
+
+// GenerateToken mocks base method.
+func (m *MockChallengeWrapper) GenerateToken(arg0 entity.SecondFactorChallenge) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateToken", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateToken indicates an expected call of GenerateToken.
+func (mr *MockChallengeWrapperMockRecorder) GenerateToken(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateToken", reflect.TypeOf((*MockChallengeWrapper)(nil).GenerateToken), arg0)
+}
+
+// ParseToken mocks base method.
+func (m *MockChallengeWrapper) ParseToken(arg0 string) (*entity.SecondFactorChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ParseToken", arg0)
+	ret0, _ := ret[0].(*entity.SecondFactorChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ParseToken indicates an expected call of ParseToken.
+func (mr *MockChallengeWrapperMockRecorder) ParseToken(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseToken", reflect.TypeOf((*MockChallengeWrapper)(nil).ParseToken), arg0)
+}
@@ -0,0 +1,49 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ktigay/goph-keeper/internal/server/handler/grpc (interfaces: ChangeFeed)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockChangeFeed is a mock of ChangeFeed interface.
+type MockChangeFeed struct {
+	ctrl     *gomock.Controller
+	recorder *MockChangeFeedMockRecorder
+}
+
+// MockChangeFeedMockRecorder is the mock recorder for MockChangeFeed.
+type MockChangeFeedMockRecorder struct {
+	mock *MockChangeFeed
+}
+
+// NewMockChangeFeed creates a new mock instance.
+func NewMockChangeFeed(ctrl *gomock.Controller) *MockChangeFeed {
+	mock := &MockChangeFeed{ctrl: ctrl}
+	mock.recorder = &MockChangeFeedMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChangeFeed) EXPECT() *MockChangeFeedMockRecorder {
+	return m.recorder
+}
+
+// Subscribe mocks base method.
+func (m *MockChangeFeed) Subscribe(arg0 string) (<-chan uint64, func()) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", arg0)
+	ret0, _ := ret[0].(<-chan uint64)
+	ret1, _ := ret[1].(func())
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockChangeFeedMockRecorder) Subscribe(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockChangeFeed)(nil).Subscribe), arg0)
+}
@@ -0,0 +1,49 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ktigay/goph-keeper/internal/server/handler/grpc (interfaces: KeyProvider)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	security "github.com/ktigay/goph-keeper/internal/server/security"
+)
+
+// MockKeyProvider is a mock of KeyProvider interface.
+type MockKeyProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockKeyProviderMockRecorder
+}
+
+// MockKeyProviderMockRecorder is the mock recorder for MockKeyProvider.
+type MockKeyProviderMockRecorder struct {
+	mock *MockKeyProvider
+}
+
+// NewMockKeyProvider creates a new mock instance.
+func NewMockKeyProvider(ctrl *gomock.Controller) *MockKeyProvider {
+	mock := &MockKeyProvider{ctrl: ctrl}
+	mock.recorder = &MockKeyProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKeyProvider) EXPECT() *MockKeyProviderMockRecorder {
+	return m.recorder
+}
+
+// JWKS mocks base method.
+func (m *MockKeyProvider) JWKS() []security.JWK {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JWKS")
+	ret0, _ := ret[0].([]security.JWK)
+	return ret0
+}
+
+// JWKS indicates an expected call of JWKS.
+func (mr *MockKeyProviderMockRecorder) JWKS() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JWKS", reflect.TypeOf((*MockKeyProvider)(nil).JWKS))
+}
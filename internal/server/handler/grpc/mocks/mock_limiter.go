@@ -0,0 +1,93 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ktigay/goph-keeper/internal/server/handler/grpc (interfaces: LoginLimiter)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	auth "github.com/ktigay/goph-keeper/internal/server/service/auth"
+)
+
+// MockLoginLimiter is a mock of LoginLimiter interface.
+type MockLoginLimiter struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginLimiterMockRecorder
+}
+
+// MockLoginLimiterMockRecorder is the mock recorder for MockLoginLimiter.
+type MockLoginLimiterMockRecorder struct {
+	mock *MockLoginLimiter
+}
+
+// NewMockLoginLimiter creates a new mock instance.
+func NewMockLoginLimiter(ctrl *gomock.Controller) *MockLoginLimiter {
+	mock := &MockLoginLimiter{ctrl: ctrl}
+	mock.recorder = &MockLoginLimiterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginLimiter) EXPECT() *MockLoginLimiterMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockLoginLimiter) Check(arg0 context.Context, arg1 ...auth.LimiterKey) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Check", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockLoginLimiterMockRecorder) Check(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockLoginLimiter)(nil).Check), varargs...)
+}
+
+// Fail mocks base method.
+func (m *MockLoginLimiter) Fail(arg0 context.Context, arg1 ...auth.LimiterKey) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Fail", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Fail indicates an expected call of Fail.
+func (mr *MockLoginLimiterMockRecorder) Fail(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fail", reflect.TypeOf((*MockLoginLimiter)(nil).Fail), varargs...)
+}
+
+// Reset mocks base method.
+func (m *MockLoginLimiter) Reset(arg0 context.Context, arg1 ...auth.LimiterKey) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Reset", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockLoginLimiterMockRecorder) Reset(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockLoginLimiter)(nil).Reset), varargs...)
+}
@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ktigay/goph-keeper/internal/server/handler/grpc (interfaces: SecondFactorService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockSecondFactorService is a mock of SecondFactorService interface.
+type MockSecondFactorService struct {
+	ctrl     *gomock.Controller
+	recorder *MockSecondFactorServiceMockRecorder
+}
+
+// MockSecondFactorServiceMockRecorder is the mock recorder for MockSecondFactorService.
+type MockSecondFactorServiceMockRecorder struct {
+	mock *MockSecondFactorService
+}
+
+// NewMockSecondFactorService creates a new mock instance.
+func NewMockSecondFactorService(ctrl *gomock.Controller) *MockSecondFactorService {
+	mock := &MockSecondFactorService{ctrl: ctrl}
+	mock.recorder = &MockSecondFactorServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSecondFactorService) EXPECT() *MockSecondFactorServiceMockRecorder {
+	return m.recorder
+}
+
+// Confirm mocks base method.
+func (m *MockSecondFactorService) Confirm(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Confirm", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Confirm indicates an expected call of Confirm.
+func (mr *MockSecondFactorServiceMockRecorder) Confirm(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Confirm", reflect.TypeOf((*MockSecondFactorService)(nil).Confirm), arg0, arg1, arg2)
+}
+
+// Enroll mocks base method.
+func (m *MockSecondFactorService) Enroll(arg0 context.Context, arg1 string) (string, []string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enroll", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].([]string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Enroll indicates an expected call of Enroll.
+func (mr *MockSecondFactorServiceMockRecorder) Enroll(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enroll", reflect.TypeOf((*MockSecondFactorService)(nil).Enroll), arg0, arg1)
+}
+
+// IsEnabled mocks base method.
+func (m *MockSecondFactorService) IsEnabled(arg0 context.Context, arg1 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsEnabled", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsEnabled indicates an expected call of IsEnabled.
+func (mr *MockSecondFactorServiceMockRecorder) IsEnabled(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsEnabled", reflect.TypeOf((*MockSecondFactorService)(nil).IsEnabled), arg0, arg1)
+}
+
+// Verify mocks base method.
+func (m *MockSecondFactorService) Verify(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockSecondFactorServiceMockRecorder) Verify(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockSecondFactorService)(nil).Verify), arg0, arg1, arg2)
+}
@@ -0,0 +1,127 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ktigay/goph-keeper/internal/server/handler/grpc (interfaces: SessionService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	entity "github.com/ktigay/goph-keeper/internal/server/entity"
+)
+
+// MockSessionService is a mock of SessionService interface.
+type MockSessionService struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionServiceMockRecorder
+}
+
+// MockSessionServiceMockRecorder is the mock recorder for MockSessionService.
+type MockSessionServiceMockRecorder struct {
+	mock *MockSessionService
+}
+
+// NewMockSessionService creates a new mock instance.
+func NewMockSessionService(ctrl *gomock.Controller) *MockSessionService {
+	mock := &MockSessionService{ctrl: ctrl}
+	mock.recorder = &MockSessionServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSessionService) EXPECT() *MockSessionServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockSessionService) Create(arg0 context.Context, arg1 entity.Session) (*entity.Session, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(*entity.Session)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockSessionServiceMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSessionService)(nil).Create), arg0, arg1)
+}
+
+// List mocks base method.
+func (m *MockSessionService) List(arg0 context.Context, arg1 string) ([]entity.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]entity.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockSessionServiceMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockSessionService)(nil).List), arg0, arg1)
+}
+
+// Refresh mocks base method.
+func (m *MockSessionService) Refresh(arg0 context.Context, arg1 string) (*entity.Session, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", arg0, arg1)
+	ret0, _ := ret[0].(*entity.Session)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockSessionServiceMockRecorder) Refresh(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockSessionService)(nil).Refresh), arg0, arg1)
+}
+
+// Revoke mocks base method.
+func (m *MockSessionService) Revoke(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockSessionServiceMockRecorder) Revoke(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockSessionService)(nil).Revoke), arg0, arg1, arg2)
+}
+
+// RevokeAll mocks base method.
+func (m *MockSessionService) RevokeAll(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAll", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeAll indicates an expected call of RevokeAll.
+func (mr *MockSessionServiceMockRecorder) RevokeAll(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAll", reflect.TypeOf((*MockSessionService)(nil).RevokeAll), arg0, arg1)
+}
+
+// RevokeAllOther mocks base method.
+func (m *MockSessionService) RevokeAllOther(arg0 context.Context, arg1, arg2 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAllOther", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeAllOther indicates an expected call of RevokeAllOther.
+func (mr *MockSessionServiceMockRecorder) RevokeAllOther(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAllOther", reflect.TypeOf((*MockSessionService)(nil).RevokeAllOther), arg0, arg1, arg2)
+}
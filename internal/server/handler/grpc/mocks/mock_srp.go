@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ktigay/goph-keeper/internal/server/handler/grpc (interfaces: SRPService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	entity "github.com/ktigay/goph-keeper/internal/server/entity"
+)
+
+// MockSRPService is a mock of SRPService interface.
+type MockSRPService struct {
+	ctrl     *gomock.Controller
+	recorder *MockSRPServiceMockRecorder
+}
+
+// MockSRPServiceMockRecorder is the mock recorder for MockSRPService.
+type MockSRPServiceMockRecorder struct {
+	mock *MockSRPService
+}
+
+// NewMockSRPService creates a new mock instance.
+func NewMockSRPService(ctrl *gomock.Controller) *MockSRPService {
+	mock := &MockSRPService{ctrl: ctrl}
+	mock.recorder = &MockSRPServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSRPService) EXPECT() *MockSRPServiceMockRecorder {
+	return m.recorder
+}
+
+// Finish mocks base method.
+func (m *MockSRPService) Finish(arg0 context.Context, arg1 string, arg2 []byte) (*entity.SRPHandshake, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Finish", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*entity.SRPHandshake)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Finish indicates an expected call of Finish.
+func (mr *MockSRPServiceMockRecorder) Finish(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Finish", reflect.TypeOf((*MockSRPService)(nil).Finish), arg0, arg1, arg2)
+}
+
+// Register mocks base method.
+func (m *MockSRPService) Register(arg0 context.Context, arg1 string, arg2, arg3 []byte) (*entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Register", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Register indicates an expected call of Register.
+func (mr *MockSRPServiceMockRecorder) Register(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockSRPService)(nil).Register), arg0, arg1, arg2, arg3)
+}
+
+// Start mocks base method.
+func (m *MockSRPService) Start(arg0 context.Context, arg1 string, arg2 []byte) (*entity.SRPChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*entity.SRPChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockSRPServiceMockRecorder) Start(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockSRPService)(nil).Start), arg0, arg1, arg2)
+}
+
+// StartForUser mocks base method.
+func (m *MockSRPService) StartForUser(arg0 context.Context, arg1 string, arg2 []byte) (*entity.SRPChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartForUser", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*entity.SRPChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartForUser indicates an expected call of StartForUser.
+func (mr *MockSRPServiceMockRecorder) StartForUser(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartForUser", reflect.TypeOf((*MockSRPService)(nil).StartForUser), arg0, arg1, arg2)
+}
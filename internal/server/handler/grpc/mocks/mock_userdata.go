@@ -35,6 +35,21 @@ func (m *MockUserDataService) EXPECT() *MockUserDataServiceMockRecorder {
 	return m.recorder
 }
 
+// Changes mocks base method.
+func (m *MockUserDataService) Changes(arg0 context.Context, arg1 string, arg2 uint64) (*entity.UserDataChanges, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Changes", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*entity.UserDataChanges)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Changes indicates an expected call of Changes.
+func (mr *MockUserDataServiceMockRecorder) Changes(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Changes", reflect.TypeOf((*MockUserDataService)(nil).Changes), arg0, arg1, arg2)
+}
+
 // Create mocks base method.
 func (m *MockUserDataService) Create(arg0 context.Context, arg1 string, arg2 entity.UserData) (*entity.UserData, error) {
 	m.ctrl.T.Helper()
@@ -89,6 +104,51 @@ func (mr *MockUserDataServiceMockRecorder) Read(arg0, arg1 interface{}, arg2 ...
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Read", reflect.TypeOf((*MockUserDataService)(nil).Read), varargs...)
 }
 
+// Restore mocks base method.
+func (m *MockUserDataService) Restore(arg0 context.Context, arg1, arg2, arg3 string) (*entity.UserData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*entity.UserData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockUserDataServiceMockRecorder) Restore(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockUserDataService)(nil).Restore), arg0, arg1, arg2, arg3)
+}
+
+// Revisions mocks base method.
+func (m *MockUserDataService) Revisions(arg0 context.Context, arg1, arg2 string) ([]entity.UserDataRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revisions", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]entity.UserDataRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revisions indicates an expected call of Revisions.
+func (mr *MockUserDataServiceMockRecorder) Revisions(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revisions", reflect.TypeOf((*MockUserDataService)(nil).Revisions), arg0, arg1, arg2)
+}
+
+// SyncBatch mocks base method.
+func (m *MockUserDataService) SyncBatch(arg0 context.Context, arg1 string, arg2 entity.UserDataBatch) (*entity.UserDataBatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncBatch", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*entity.UserDataBatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SyncBatch indicates an expected call of SyncBatch.
+func (mr *MockUserDataServiceMockRecorder) SyncBatch(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncBatch", reflect.TypeOf((*MockUserDataService)(nil).SyncBatch), arg0, arg1, arg2)
+}
+
 // Update mocks base method.
 func (m *MockUserDataService) Update(arg0 context.Context, arg1 string, arg2 entity.UserData) (*entity.UserData, error) {
 	m.ctrl.T.Helper()
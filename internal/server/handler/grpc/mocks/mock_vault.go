@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ktigay/goph-keeper/internal/server/handler/grpc (interfaces: VaultService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	entity "github.com/ktigay/goph-keeper/internal/entity"
+)
+
+// MockVaultService is a mock of VaultService interface.
+type MockVaultService struct {
+	ctrl     *gomock.Controller
+	recorder *MockVaultServiceMockRecorder
+}
+
+// MockVaultServiceMockRecorder is the mock recorder for MockVaultService.
+type MockVaultServiceMockRecorder struct {
+	mock *MockVaultService
+}
+
+// NewMockVaultService creates a new mock instance.
+func NewMockVaultService(ctrl *gomock.Controller) *MockVaultService {
+	mock := &MockVaultService{ctrl: ctrl}
+	mock.recorder = &MockVaultServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVaultService) EXPECT() *MockVaultServiceMockRecorder {
+	return m.recorder
+}
+
+// BeginRotation mocks base method.
+func (m *MockVaultService) BeginRotation(arg0 context.Context, arg1 string, arg2 entity.VaultKey) (*entity.VaultKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginRotation", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*entity.VaultKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginRotation indicates an expected call of BeginRotation.
+func (mr *MockVaultServiceMockRecorder) BeginRotation(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginRotation", reflect.TypeOf((*MockVaultService)(nil).BeginRotation), arg0, arg1, arg2)
+}
+
+// CommitRotation mocks base method.
+func (m *MockVaultService) CommitRotation(arg0 context.Context, arg1 string, arg2 uint32, arg3 []entity.UserData) (*entity.VaultKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CommitRotation", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*entity.VaultKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CommitRotation indicates an expected call of CommitRotation.
+func (mr *MockVaultServiceMockRecorder) CommitRotation(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommitRotation", reflect.TypeOf((*MockVaultService)(nil).CommitRotation), arg0, arg1, arg2, arg3)
+}
+
+// Create mocks base method.
+func (m *MockVaultService) Create(arg0 context.Context, arg1 string, arg2 entity.VaultKey) (*entity.VaultKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*entity.VaultKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockVaultServiceMockRecorder) Create(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockVaultService)(nil).Create), arg0, arg1, arg2)
+}
+
+// Get mocks base method.
+func (m *MockVaultService) Get(arg0 context.Context, arg1 string) (*entity.VaultKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	ret0, _ := ret[0].(*entity.VaultKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockVaultServiceMockRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockVaultService)(nil).Get), arg0, arg1)
+}
@@ -0,0 +1,138 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ktigay/goph-keeper/internal/contracts/v1/session"
+	"github.com/ktigay/goph-keeper/internal/contracts/v1/session/mapper"
+	e "github.com/ktigay/goph-keeper/internal/entity"
+	c "github.com/ktigay/goph-keeper/internal/server/context"
+	"github.com/ktigay/goph-keeper/internal/server/entity"
+	sessionsrv "github.com/ktigay/goph-keeper/internal/server/service/session"
+)
+
+// SessionHandler обработчик сессий (устройств) пользователя.
+type SessionHandler struct {
+	session.UnimplementedSessionServiceServer
+	srv SessionService
+}
+
+// ListSessions возвращает действующие сессии пользователя.
+func (h *SessionHandler) ListSessions(ctx context.Context, _ *session.ListSessionsRequest) (*session.ListSessionsResponse, error) {
+	var (
+		identity *e.Identity
+		sessions []entity.Session
+		err      error
+	)
+
+	if identity, err = c.IdentityFromContext(ctx); err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "authorization required: %v", err)
+	}
+
+	if sessions, err = h.srv.List(ctx, identity.UUID); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return mapSessions(sessions, identity.SessionUUID), nil
+}
+
+// RevokeSession отзывает сессию пользователя.
+func (h *SessionHandler) RevokeSession(ctx context.Context, req *session.RevokeSessionRequest) (*session.RevokeSessionResponse, error) {
+	var (
+		identity *e.Identity
+		err      error
+	)
+
+	if identity, err = c.IdentityFromContext(ctx); err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "authorization required: %v", err)
+	}
+
+	if err = h.srv.Revoke(ctx, identity.UUID, req.GetUuid()); err != nil {
+		if errors.Is(err, sessionsrv.ErrSessionNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &session.RevokeSessionResponse{}, nil
+}
+
+// RevokeAllOtherSessions отзывает все сессии пользователя, кроме текущей.
+func (h *SessionHandler) RevokeAllOtherSessions(ctx context.Context, _ *session.RevokeAllOtherSessionsRequest) (*session.RevokeAllOtherSessionsResponse, error) {
+	var (
+		identity *e.Identity
+		n        int64
+		err      error
+	)
+
+	if identity, err = c.IdentityFromContext(ctx); err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "authorization required: %v", err)
+	}
+
+	if n, err = h.srv.RevokeAllOther(ctx, identity.UUID, identity.SessionUUID); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &session.RevokeAllOtherSessionsResponse{
+		Revoked: uint32(n),
+	}, nil
+}
+
+// ListUserSessions возвращает действующие сессии любого пользователя. Доступен только администратору.
+func (h *SessionHandler) ListUserSessions(ctx context.Context, req *session.ListUserSessionsRequest) (*session.ListSessionsResponse, error) {
+	if uuid.Validate(req.GetUserUuid()) != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user uuid")
+	}
+
+	sessions, err := h.srv.List(ctx, req.GetUserUuid())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return mapSessions(sessions, ""), nil
+}
+
+// RevokeUserSessions отзывает все сессии любого пользователя. Доступен только администратору.
+func (h *SessionHandler) RevokeUserSessions(ctx context.Context, req *session.RevokeUserSessionsRequest) (*session.RevokeUserSessionsResponse, error) {
+	if uuid.Validate(req.GetUserUuid()) != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user uuid")
+	}
+
+	n, err := h.srv.RevokeAll(ctx, req.GetUserUuid())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &session.RevokeUserSessionsResponse{
+		Revoked: uint32(n),
+	}, nil
+}
+
+// mapSessions преобразует сессии в ответ, currentUUID - сессия, из которой пришел запрос.
+func mapSessions(sessions []entity.Session, currentUUID string) *session.ListSessionsResponse {
+	resp := &session.ListSessionsResponse{
+		Sessions: make([]*session.Session, 0, len(sessions)),
+	}
+	for _, s := range sessions {
+		resp.Sessions = append(resp.Sessions, mapper.MapEntityToSession(e.SessionInfo{
+			UUID:          s.UUID,
+			DeviceName:    s.DeviceName,
+			ClientVersion: s.ClientVersion,
+			IP:            s.IP,
+			CreatedAt:     s.CreatedAt,
+			LastSeenAt:    s.LastSeenAt,
+			Current:       s.UUID == currentUUID,
+		}))
+	}
+	return resp
+}
+
+// NewSessionHandler конструктор.
+func NewSessionHandler(s SessionService) *SessionHandler {
+	return &SessionHandler{
+		srv: s,
+	}
+}
@@ -0,0 +1,137 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ktigay/goph-keeper/internal/contracts/v1/session"
+	e "github.com/ktigay/goph-keeper/internal/entity"
+	c "github.com/ktigay/goph-keeper/internal/server/context"
+	"github.com/ktigay/goph-keeper/internal/server/entity"
+	"github.com/ktigay/goph-keeper/internal/server/handler/grpc/mocks"
+	sessionsrv "github.com/ktigay/goph-keeper/internal/server/service/session"
+)
+
+func TestSessionHandler_ListSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	srv := mocks.NewMockSessionService(ctrl)
+	srv.EXPECT().List(gomock.Any(), "user-uuid").Times(1).Return([]entity.Session{
+		{UUID: "current-uuid", DeviceName: "laptop", LastSeenAt: time.Unix(1700000000, 0)},
+		{UUID: "other-uuid", DeviceName: "phone", LastSeenAt: time.Unix(1600000000, 0)},
+	}, nil)
+
+	ctx := c.NewContextWithIdentity(context.Background(), e.Identity{
+		UUID:        "user-uuid",
+		SessionUUID: "current-uuid",
+	})
+	got, err := NewSessionHandler(srv).ListSessions(ctx, &session.ListSessionsRequest{})
+	if err != nil {
+		t.Fatalf("ListSessions() error = %v", err)
+	}
+	if len(got.GetSessions()) != 2 {
+		t.Fatalf("ListSessions() got %d sessions, want 2", len(got.GetSessions()))
+	}
+	if !got.GetSessions()[0].GetCurrent() || got.GetSessions()[1].GetCurrent() {
+		t.Errorf("ListSessions() only the requesting session must be current")
+	}
+}
+
+func TestSessionHandler_RevokeSession(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		srv      func(ctrl *gomock.Controller) SessionService
+		wantCode codes.Code
+	}{
+		{
+			name: "Revoke_Success",
+			ctx:  c.NewContextWithIdentity(context.Background(), e.Identity{UUID: "user-uuid"}),
+			srv: func(ctrl *gomock.Controller) SessionService {
+				srv := mocks.NewMockSessionService(ctrl)
+				srv.EXPECT().Revoke(gomock.Any(), "user-uuid", "session-uuid").Times(1).Return(nil)
+				return srv
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name: "Revoke_Not_Found",
+			ctx:  c.NewContextWithIdentity(context.Background(), e.Identity{UUID: "user-uuid"}),
+			srv: func(ctrl *gomock.Controller) SessionService {
+				srv := mocks.NewMockSessionService(ctrl)
+				srv.EXPECT().Revoke(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(sessionsrv.ErrSessionNotFound)
+				return srv
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "Revoke_Without_Identity",
+			ctx:  context.Background(),
+			srv: func(ctrl *gomock.Controller) SessionService {
+				srv := mocks.NewMockSessionService(ctrl)
+				srv.EXPECT().Revoke(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				return srv
+			},
+			wantCode: codes.Unauthenticated,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			_, err := NewSessionHandler(tt.srv(ctrl)).RevokeSession(tt.ctx, &session.RevokeSessionRequest{Uuid: "session-uuid"})
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("RevokeSession() code = %v, want %v", status.Code(err), tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestSessionHandler_RevokeUserSessions(t *testing.T) {
+	const userUUID = "33b06619-1ee7-3db5-827d-0dc85df1f759"
+
+	tests := []struct {
+		name        string
+		userUUID    string
+		srv         func(ctrl *gomock.Controller) SessionService
+		wantRevoked uint32
+		wantCode    codes.Code
+	}{
+		{
+			name:     "Revoke_Success",
+			userUUID: userUUID,
+			srv: func(ctrl *gomock.Controller) SessionService {
+				srv := mocks.NewMockSessionService(ctrl)
+				srv.EXPECT().RevokeAll(gomock.Any(), userUUID).Times(1).Return(int64(2), nil)
+				return srv
+			},
+			wantRevoked: 2,
+			wantCode:    codes.OK,
+		},
+		{
+			name:     "Invalid_User_UUID",
+			userUUID: "user",
+			srv: func(ctrl *gomock.Controller) SessionService {
+				srv := mocks.NewMockSessionService(ctrl)
+				srv.EXPECT().RevokeAll(gomock.Any(), gomock.Any()).Times(0)
+				return srv
+			},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			got, err := NewSessionHandler(tt.srv(ctrl)).RevokeUserSessions(context.Background(), &session.RevokeUserSessionsRequest{UserUuid: tt.userUUID})
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("RevokeUserSessions() code = %v, want %v", status.Code(err), tt.wantCode)
+			}
+			if got.GetRevoked() != tt.wantRevoked {
+				t.Errorf("RevokeUserSessions() revoked = %d, want %d", got.GetRevoked(), tt.wantRevoked)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/ktigay/goph-keeper/internal/contracts/v1/token"
+	e "github.com/ktigay/goph-keeper/internal/entity"
+	c "github.com/ktigay/goph-keeper/internal/server/context"
+	"github.com/ktigay/goph-keeper/internal/server/entity"
+	tokensrv "github.com/ktigay/goph-keeper/internal/server/service/apitoken"
+)
+
+// APITokenService сервис токенов доступа.
+//
+//go:generate mockgen -destination=./mocks/mock_apitoken.go -package=mocks github.com/ktigay/goph-keeper/internal/server/handler/grpc APITokenService
+type APITokenService interface {
+	Create(ctx context.Context, userUUID, name string, expiresAt time.Time, scope entity.APITokenScope) (*entity.APIToken, string, error)
+	List(ctx context.Context, userUUID string) ([]entity.APIToken, error)
+	Revoke(ctx context.Context, userUUID, uuid string) error
+}
+
+// TokenHandler обработчик токенов доступа для автоматизации.
+type TokenHandler struct {
+	token.UnimplementedTokenServiceServer
+	srv APITokenService
+}
+
+// CreateToken создает токен доступа. Значение токена возвращается только в этом ответе.
+func (h *TokenHandler) CreateToken(ctx context.Context, req *token.CreateTokenRequest) (*token.CreateTokenResponse, error) {
+	var (
+		identity *e.Identity
+		created  *entity.APIToken
+		secret   string
+		err      error
+	)
+
+	if identity, err = c.IdentityFromContext(ctx); err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "authorization required: %v", err)
+	}
+	if req.GetExpiresAt() == nil {
+		return nil, status.Error(codes.InvalidArgument, "expires_at is required")
+	}
+
+	scope := entity.APITokenScope{
+		ReadOnly:  req.GetScope().GetReadOnly(),
+		ItemUUIDs: req.GetScope().GetItemUuids(),
+		TagMAC:    req.GetScope().GetTagMac(),
+	}
+	if created, secret, err = h.srv.Create(ctx, identity.UUID, req.GetName(), req.GetExpiresAt().AsTime(), scope); err != nil {
+		if errors.Is(err, tokensrv.ErrInvalidToken) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &token.CreateTokenResponse{
+		Token:  mapAPIToken(*created),
+		Secret: secret,
+	}, nil
+}
+
+// ListTokens возвращает действующие токены пользователя.
+func (h *TokenHandler) ListTokens(ctx context.Context, _ *token.ListTokensRequest) (*token.ListTokensResponse, error) {
+	var (
+		identity *e.Identity
+		tokens   []entity.APIToken
+		err      error
+	)
+
+	if identity, err = c.IdentityFromContext(ctx); err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "authorization required: %v", err)
+	}
+
+	if tokens, err = h.srv.List(ctx, identity.UUID); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	resp := &token.ListTokensResponse{
+		Tokens: make([]*token.Token, 0, len(tokens)),
+	}
+	for _, t := range tokens {
+		resp.Tokens = append(resp.Tokens, mapAPIToken(t))
+	}
+	return resp, nil
+}
+
+// RevokeToken отзывает токен доступа пользователя.
+func (h *TokenHandler) RevokeToken(ctx context.Context, req *token.RevokeTokenRequest) (*token.RevokeTokenResponse, error) {
+	var (
+		identity *e.Identity
+		err      error
+	)
+
+	if identity, err = c.IdentityFromContext(ctx); err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "authorization required: %v", err)
+	}
+
+	if err = h.srv.Revoke(ctx, identity.UUID, req.GetUuid()); err != nil {
+		if errors.Is(err, tokensrv.ErrTokenNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &token.RevokeTokenResponse{}, nil
+}
+
+func mapAPIToken(t entity.APIToken) *token.Token {
+	res := &token.Token{
+		Uuid: t.UUID,
+		Name: t.Name,
+		Scope: &token.TokenScope{
+			ReadOnly:  t.Scope.ReadOnly,
+			ItemUuids: t.Scope.ItemUUIDs,
+			TagMac:    t.Scope.TagMAC,
+		},
+		ExpiresAt: timestamppb.New(t.ExpiresAt),
+		CreatedAt: timestamppb.New(t.CreatedAt),
+	}
+	if t.LastUsedAt != nil {
+		res.LastUsedAt = timestamppb.New(*t.LastUsedAt)
+	}
+	return res
+}
+
+// NewTokenHandler конструктор.
+func NewTokenHandler(s APITokenService) *TokenHandler {
+	return &TokenHandler{
+		srv: s,
+	}
+}
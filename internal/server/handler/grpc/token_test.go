@@ -0,0 +1,98 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/ktigay/goph-keeper/internal/contracts/v1/token"
+	e "github.com/ktigay/goph-keeper/internal/entity"
+	c "github.com/ktigay/goph-keeper/internal/server/context"
+	"github.com/ktigay/goph-keeper/internal/server/entity"
+	"github.com/ktigay/goph-keeper/internal/server/handler/grpc/mocks"
+	tokensrv "github.com/ktigay/goph-keeper/internal/server/service/apitoken"
+)
+
+func TestTokenHandler_CreateToken(t *testing.T) {
+	expiresAt := time.Unix(1700000000, 0).UTC()
+	userCtx := c.NewContextWithIdentity(context.Background(), e.Identity{UUID: "user-uuid"})
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		req      *token.CreateTokenRequest
+		srv      func(srv *mocks.MockAPITokenService)
+		wantCode codes.Code
+	}{
+		{
+			name: "Create_Success",
+			ctx:  userCtx,
+			req: &token.CreateTokenRequest{
+				Name:      "ci",
+				ExpiresAt: timestamppb.New(expiresAt),
+				Scope:     &token.TokenScope{ReadOnly: true, TagMac: []byte("deploy")},
+			},
+			srv: func(srv *mocks.MockAPITokenService) {
+				srv.EXPECT().Create(gomock.Any(), "user-uuid", "ci", expiresAt, entity.APITokenScope{ReadOnly: true, TagMAC: []byte("deploy")}).Times(1).
+					Return(&entity.APIToken{UUID: "token-uuid", Name: "ci", ExpiresAt: expiresAt}, entity.APITokenPrefix+"secret", nil)
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "Create_Without_Expiry",
+			ctx:      userCtx,
+			req:      &token.CreateTokenRequest{Name: "ci"},
+			srv:      func(*mocks.MockAPITokenService) {},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "Create_Invalid",
+			ctx:  userCtx,
+			req:  &token.CreateTokenRequest{ExpiresAt: timestamppb.New(expiresAt)},
+			srv: func(srv *mocks.MockAPITokenService) {
+				srv.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					Return(nil, "", tokensrv.ErrInvalidToken)
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "Create_Without_Identity",
+			ctx:      context.Background(),
+			req:      &token.CreateTokenRequest{Name: "ci", ExpiresAt: timestamppb.New(expiresAt)},
+			srv:      func(*mocks.MockAPITokenService) {},
+			wantCode: codes.Unauthenticated,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			srv := mocks.NewMockAPITokenService(ctrl)
+			tt.srv(srv)
+
+			got, err := NewTokenHandler(srv).CreateToken(tt.ctx, tt.req)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("CreateToken() code = %v, want %v", status.Code(err), tt.wantCode)
+			}
+			if tt.wantCode == codes.OK && (got.GetSecret() == "" || got.GetToken().GetUuid() != "token-uuid") {
+				t.Errorf("CreateToken() got = %v", got)
+			}
+		})
+	}
+}
+
+func TestTokenHandler_RevokeToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	srv := mocks.NewMockAPITokenService(ctrl)
+	srv.EXPECT().Revoke(gomock.Any(), "user-uuid", "token-uuid").Times(1).Return(tokensrv.ErrTokenNotFound)
+
+	ctx := c.NewContextWithIdentity(context.Background(), e.Identity{UUID: "user-uuid"})
+	_, err := NewTokenHandler(srv).RevokeToken(ctx, &token.RevokeTokenRequest{Uuid: "token-uuid"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("RevokeToken() code = %v, want %v", status.Code(err), codes.NotFound)
+	}
+}
@@ -5,13 +5,16 @@ import (
 	"errors"
 
 	"github.com/ktigay/goph-keeper/internal/contracts/v1/data/mapper"
+	grpclib "google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	"github.com/ktigay/goph-keeper/internal/contracts/v1/data"
 	"github.com/ktigay/goph-keeper/internal/entity"
+	"github.com/ktigay/goph-keeper/internal/server/changefeed"
 	c "github.com/ktigay/goph-keeper/internal/server/context"
+	se "github.com/ktigay/goph-keeper/internal/server/entity"
 	"github.com/ktigay/goph-keeper/internal/server/service/userdata"
 )
 
@@ -23,12 +26,24 @@ type UserDataService interface {
 	Update(ctx context.Context, userUUID string, data entity.UserData) (*entity.UserData, error)
 	Delete(ctx context.Context, userUUID string, uuids ...string) error
 	Read(ctx context.Context, userUUID string, uuids ...string) ([]entity.UserData, error)
+	Revisions(ctx context.Context, userUUID, itemUUID string) ([]entity.UserDataRevision, error)
+	Restore(ctx context.Context, userUUID, itemUUID, revisionUUID string) (*entity.UserData, error)
+	Changes(ctx context.Context, userUUID string, since uint64) (*entity.UserDataChanges, error)
+	SyncBatch(ctx context.Context, userUUID string, batch entity.UserDataBatch) (*entity.UserDataBatchResult, error)
+}
+
+// ChangeFeed уведомления об изменении пользовательских данных.
+//
+//go:generate mockgen -destination=./mocks/mock_changefeed.go -package=mocks github.com/ktigay/goph-keeper/internal/server/handler/grpc ChangeFeed
+type ChangeFeed interface {
+	Subscribe(userUUID string) (<-chan uint64, func())
 }
 
 // UserDataHandler обработчик пользовательских данных.
 type UserDataHandler struct {
 	data.UnimplementedUserDataServiceServer
-	srv UserDataService
+	srv  UserDataService
+	feed ChangeFeed
 }
 
 // CreateUserDataItem создает запись пользовательских данных.
@@ -43,7 +58,15 @@ func (u *UserDataHandler) CreateUserDataItem(ctx context.Context, request *data.
 		return nil, status.Errorf(codes.Unauthenticated, "authorization required: %v", err)
 	}
 
-	d, err = u.srv.Create(ctx, identity.UUID, mapper.MapItemToEntity(request.Item, identity.UUID))
+	item := mapper.MapItemToEntity(request.Item, identity.UUID)
+	if scope := c.TokenScopeFromContext(ctx); scope != nil {
+		// Токен с перечнем записей не может создавать новые записи.
+		if scope.ReadOnly || len(scope.ItemUUIDs) > 0 || !scope.Matches(item) {
+			return nil, status.Error(codes.PermissionDenied, "access token scope does not allow creating this item")
+		}
+	}
+
+	d, err = u.srv.Create(ctx, identity.UUID, item)
 	if err != nil {
 		return nil, status.Errorf(mapErrorToCode(err), "%v", err)
 	}
@@ -65,9 +88,20 @@ func (u *UserDataHandler) UpdateUserDataItem(ctx context.Context, request *data.
 		return nil, status.Errorf(codes.Unauthenticated, "authorization required: %v", err)
 	}
 
-	d, err = u.srv.Update(ctx, identity.UUID, mapper.MapItemToEntity(request.Item, identity.UUID))
+	item := mapper.MapItemToEntity(request.Item, identity.UUID)
+	item.Version = request.ExpectedVersion
+	if scope := c.TokenScopeFromContext(ctx); scope != nil {
+		if scope.ReadOnly || !scope.Matches(item) {
+			return nil, status.Error(codes.PermissionDenied, "access token scope does not allow updating this item")
+		}
+		if err = u.checkScope(ctx, *scope, identity.UUID, item.UUID); err != nil {
+			return nil, err
+		}
+	}
+
+	d, err = u.srv.Update(ctx, identity.UUID, item)
 	if err != nil {
-		return nil, status.Errorf(mapErrorToCode(err), "%v", err)
+		return nil, updateStatus(err)
 	}
 
 	return &data.UpdateUserDataItemResponse{
@@ -95,6 +129,9 @@ func (u *UserDataHandler) GetUserDataItem(ctx context.Context, request *data.Get
 	if len(d) == 0 {
 		return nil, status.Errorf(codes.NotFound, "item uuid %s not found", request.ItemUuid)
 	}
+	if scope := c.TokenScopeFromContext(ctx); scope != nil && !scope.Matches(d[0]) {
+		return nil, status.Errorf(codes.PermissionDenied, "item uuid %s is out of access token scope", request.ItemUuid)
+	}
 
 	return &data.GetUserDataItemResponse{
 		Item: mapper.MapEntityToItem(d[0]),
@@ -113,13 +150,23 @@ func (u *UserDataHandler) GetUserDataItems(ctx context.Context, request *data.Ge
 		return nil, status.Errorf(codes.Unauthenticated, "authorization required: %v", err)
 	}
 
-	d, err = u.srv.Read(ctx, identity.UUID, request.ItemUuids...)
+	uuids := request.ItemUuids
+	scope := c.TokenScopeFromContext(ctx)
+	if scope != nil && len(uuids) == 0 {
+		uuids = scope.ItemUUIDs
+	}
+
+	d, err = u.srv.Read(ctx, identity.UUID, uuids...)
 	if err != nil {
 		return nil, status.Errorf(mapErrorToCode(err), "%v", err)
 	}
 
 	items := make([]*data.UserDataItem, 0, len(d))
 	for _, v := range d {
+		// Записи вне ограничений токена не возвращаются.
+		if scope != nil && !scope.Matches(v) {
+			continue
+		}
 		items = append(items, mapper.MapEntityToItem(v))
 	}
 
@@ -139,25 +186,336 @@ func (u *UserDataHandler) DeleteUserDataItems(ctx context.Context, request *data
 		return nil, status.Errorf(codes.Unauthenticated, "authorization required: %v", err)
 	}
 
+	if scope := c.TokenScopeFromContext(ctx); scope != nil {
+		if scope.ReadOnly || len(request.ItemUuids) == 0 {
+			return nil, status.Error(codes.PermissionDenied, "access token scope does not allow deleting these items")
+		}
+		if err = u.checkScope(ctx, *scope, identity.UUID, request.ItemUuids...); err != nil {
+			return nil, err
+		}
+	}
+
 	if err = u.srv.Delete(ctx, identity.UUID, request.ItemUuids...); err != nil {
 		return nil, status.Errorf(mapErrorToCode(err), "%v", err)
 	}
 	return &emptypb.Empty{}, nil
 }
 
+// ListItemRevisions возвращает предыдущие версии записи.
+func (u *UserDataHandler) ListItemRevisions(ctx context.Context, request *data.ListItemRevisionsRequest) (*data.ListItemRevisionsResponse, error) {
+	var (
+		identity  *entity.Identity
+		revisions []entity.UserDataRevision
+		err       error
+	)
+
+	if identity, err = c.IdentityFromContext(ctx); err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "authorization required: %v", err)
+	}
+
+	scope := c.TokenScopeFromContext(ctx)
+	if scope != nil {
+		if err = u.checkScope(ctx, *scope, identity.UUID, request.ItemUuid); err != nil {
+			return nil, err
+		}
+	}
+
+	if revisions, err = u.srv.Revisions(ctx, identity.UUID, request.ItemUuid); err != nil {
+		return nil, status.Errorf(mapErrorToCode(err), "%v", err)
+	}
+
+	items := make([]*data.ItemRevision, 0, len(revisions))
+	for _, rev := range revisions {
+		// Версии вне ограничений токена не возвращаются.
+		if scope != nil && !scope.Matches(rev.Item) {
+			continue
+		}
+		items = append(items, mapper.MapEntityToRevision(rev))
+	}
+
+	return &data.ListItemRevisionsResponse{
+		Revisions: items,
+	}, nil
+}
+
+// RestoreItemRevision восстанавливает версию записи.
+func (u *UserDataHandler) RestoreItemRevision(ctx context.Context, request *data.RestoreItemRevisionRequest) (*data.RestoreItemRevisionResponse, error) {
+	var (
+		identity *entity.Identity
+		d        *entity.UserData
+		err      error
+	)
+
+	if identity, err = c.IdentityFromContext(ctx); err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "authorization required: %v", err)
+	}
+
+	if scope := c.TokenScopeFromContext(ctx); scope != nil {
+		if scope.ReadOnly {
+			return nil, status.Error(codes.PermissionDenied, "access token scope does not allow restoring items")
+		}
+		if err = u.checkRevisionScope(ctx, *scope, identity.UUID, request.ItemUuid, request.RevisionUuid); err != nil {
+			return nil, err
+		}
+	}
+
+	if d, err = u.srv.Restore(ctx, identity.UUID, request.ItemUuid, request.RevisionUuid); err != nil {
+		return nil, updateStatus(err)
+	}
+
+	return &data.RestoreItemRevisionResponse{
+		Item: mapper.MapEntityToItem(*d),
+	}, nil
+}
+
+// ListChanges возвращает изменения пользовательских данных после ревизии клиента.
+func (u *UserDataHandler) ListChanges(ctx context.Context, request *data.ListChangesRequest) (*data.ListChangesResponse, error) {
+	var (
+		identity *entity.Identity
+		changes  *entity.UserDataChanges
+		err      error
+	)
+
+	if identity, err = c.IdentityFromContext(ctx); err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "authorization required: %v", err)
+	}
+
+	if changes, err = u.srv.Changes(ctx, identity.UUID, request.SinceRevision); err != nil {
+		return nil, status.Errorf(mapErrorToCode(err), "%v", err)
+	}
+
+	return changesResponse(changes, c.TokenScopeFromContext(ctx)), nil
+}
+
+// WatchChanges отправляет изменения пользовательских данных по мере их фиксации.
+// Первым сообщением отправляются изменения после ревизии клиента, если они есть.
+func (u *UserDataHandler) WatchChanges(request *data.WatchChangesRequest, stream grpclib.ServerStreamingServer[data.ListChangesResponse]) error {
+	var (
+		ctx      = stream.Context()
+		identity *entity.Identity
+		err      error
+	)
+
+	if identity, err = c.IdentityFromContext(ctx); err != nil {
+		return status.Errorf(codes.Unauthenticated, "authorization required: %v", err)
+	}
+
+	// Подписка до чтения изменений: изменение, зафиксированное во время чтения, не будет пропущено.
+	notify, cancel := u.feed.Subscribe(identity.UUID)
+	defer cancel()
+
+	scope := c.TokenScopeFromContext(ctx)
+	since := request.SinceRevision
+	send := func() error {
+		changes, err := u.srv.Changes(ctx, identity.UUID, since)
+		if err != nil {
+			return status.Errorf(mapErrorToCode(err), "%v", err)
+		}
+		if changes.Revision == since && !changes.Full {
+			return nil
+		}
+		since = changes.Revision
+		return stream.Send(changesResponse(changes, scope))
+	}
+
+	if err = send(); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case rev, ok := <-notify:
+			if !ok {
+				return status.Error(codes.Unavailable, "server is shutting down")
+			}
+			// После [changefeed.Resync] изменения перечитываются: уведомления могли быть потеряны.
+			if rev != changefeed.Resync && rev <= since {
+				continue
+			}
+			if err = send(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// SyncBatch применяет пакет изменений пользовательских данных одной транзакцией.
+func (u *UserDataHandler) SyncBatch(ctx context.Context, request *data.SyncBatchRequest) (*data.SyncBatchResponse, error) {
+	var (
+		identity *entity.Identity
+		res      *entity.UserDataBatchResult
+		err      error
+	)
+
+	if identity, err = c.IdentityFromContext(ctx); err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "authorization required: %v", err)
+	}
+
+	batch := entity.UserDataBatch{
+		UUID:    request.BatchId,
+		Upserts: make([]entity.UserData, 0, len(request.Upserts)),
+		Deletes: request.DeleteUuids,
+	}
+	for _, up := range request.Upserts {
+		if up.GetItem() == nil {
+			return nil, status.Error(codes.InvalidArgument, "batch upsert item is required")
+		}
+		item := mapper.MapItemToEntity(up.Item, identity.UUID)
+		item.Version = up.ExpectedVersion
+		batch.Upserts = append(batch.Upserts, item)
+	}
+	scope := c.TokenScopeFromContext(ctx)
+	if scope != nil {
+		if err = u.checkBatchScope(ctx, *scope, identity.UUID, batch); err != nil {
+			return nil, err
+		}
+	}
+
+	if res, err = u.srv.SyncBatch(ctx, identity.UUID, batch); err != nil {
+		return nil, status.Errorf(mapErrorToCode(err), "%v", err)
+	}
+
+	results := make([]*data.SyncBatchItemResult, 0, len(res.Items))
+	for _, item := range res.Items {
+		// Записи вне ограничений токена не возвращаются, в том числе из повторно запрошенного пакета.
+		if scope != nil && item.Item != nil && !scope.Matches(*item.Item) {
+			item.Item = nil
+		}
+		results = append(results, mapper.MapEntityToBatchItem(item))
+	}
+	return &data.SyncBatchResponse{
+		Results:  results,
+		Revision: res.Revision,
+		Replayed: res.Replayed,
+	}, nil
+}
+
+// changesResponse ответ с изменениями, входящими в ограничения токена.
+func changesResponse(changes *entity.UserDataChanges, scope *se.APITokenScope) *data.ListChangesResponse {
+	upserts := make([]*data.UserDataItem, 0, len(changes.Upserts))
+	for _, v := range changes.Upserts {
+		// Записи вне ограничений токена не возвращаются.
+		if scope != nil && !scope.Matches(v) {
+			continue
+		}
+		upserts = append(upserts, mapper.MapEntityToItem(v))
+	}
+	deleted := make([]string, 0, len(changes.Deleted))
+	for _, id := range changes.Deleted {
+		// Метаданные удаленной записи недоступны, поэтому удаления проверяются только по перечню записей токена.
+		if scope != nil && !scope.AllowsItem(id) {
+			continue
+		}
+		deleted = append(deleted, id)
+	}
+
+	return &data.ListChangesResponse{
+		Upserts:      upserts,
+		DeletedUuids: deleted,
+		Revision:     changes.Revision,
+		Full:         changes.Full,
+	}
+}
+
+// checkRevisionScope проверяет, что и запись, и восстанавливаемая версия входят в ограничения токена.
+func (u *UserDataHandler) checkRevisionScope(ctx context.Context, scope se.APITokenScope, userUUID, itemUUID, revisionUUID string) error {
+	if err := u.checkScope(ctx, scope, userUUID, itemUUID); err != nil {
+		return err
+	}
+	if len(scope.TagMAC) == 0 {
+		return nil
+	}
+
+	revisions, err := u.srv.Revisions(ctx, userUUID, itemUUID)
+	if err != nil {
+		return status.Errorf(mapErrorToCode(err), "%v", err)
+	}
+	for _, rev := range revisions {
+		if rev.UUID == revisionUUID && !scope.Matches(rev.Item) {
+			return status.Errorf(codes.PermissionDenied, "revision %s is out of access token scope", revisionUUID)
+		}
+	}
+	return nil
+}
+
+// checkBatchScope проверяет, что токен разрешает все изменения пакета: создание записей - по тем же правилам,
+// что и [UserDataHandler.CreateUserDataItem], а сохраненные записи с UUID из пакета - по [UserDataHandler.checkScope]
+// независимо от версии: создание с UUID существующей записи возвращает её в конфликте.
+func (u *UserDataHandler) checkBatchScope(ctx context.Context, scope se.APITokenScope, userUUID string, batch entity.UserDataBatch) error {
+	if scope.ReadOnly {
+		return status.Error(codes.PermissionDenied, "access token scope does not allow changing items")
+	}
+
+	uuids := make([]string, 0, len(batch.Upserts)+len(batch.Deletes))
+	for _, d := range batch.Upserts {
+		if !scope.Matches(d) || (d.Version == 0 && len(scope.ItemUUIDs) > 0) {
+			return status.Errorf(codes.PermissionDenied, "item uuid %s is out of access token scope", d.UUID)
+		}
+		uuids = append(uuids, d.UUID)
+	}
+	uuids = append(uuids, batch.Deletes...)
+	if len(uuids) == 0 {
+		return nil
+	}
+	return u.checkScope(ctx, scope, userUUID, uuids...)
+}
+
+// checkScope проверяет, что существующие записи uuids входят в ограничения токена.
+// Проверка по тегу требует HMAC тегов записей, поэтому записи читаются.
+func (u *UserDataHandler) checkScope(ctx context.Context, scope se.APITokenScope, userUUID string, uuids ...string) error {
+	for _, id := range uuids {
+		if !scope.AllowsItem(id) {
+			return status.Errorf(codes.PermissionDenied, "item uuid %s is out of access token scope", id)
+		}
+	}
+	if len(scope.TagMAC) == 0 {
+		return nil
+	}
+
+	d, err := u.srv.Read(ctx, userUUID, uuids...)
+	if err != nil {
+		return status.Errorf(mapErrorToCode(err), "%v", err)
+	}
+	for _, v := range d {
+		if !scope.Matches(v) {
+			return status.Errorf(codes.PermissionDenied, "item uuid %s is out of access token scope", v.UUID)
+		}
+	}
+	return nil
+}
+
 // NewUserDataHandler конструктор.
-func NewUserDataHandler(s UserDataService) *UserDataHandler {
+func NewUserDataHandler(s UserDataService, feed ChangeFeed) *UserDataHandler {
 	return &UserDataHandler{
-		srv: s,
+		srv:  s,
+		feed: feed,
+	}
+}
+
+// updateStatus статус ошибки обновления; при конфликте версий клиент получает текущую версию записи.
+func updateStatus(err error) error {
+	var conflict *userdata.ConflictError
+	if !errors.As(err, &conflict) {
+		return status.Errorf(mapErrorToCode(err), "%v", err)
 	}
+
+	return statusWithDetails(codes.Aborted, err.Error(),
+		reasonInfo(entity.ReasonVersionConflict),
+		&data.VersionConflict{Current: mapper.MapEntityToItem(conflict.Current)},
+	)
 }
 
 func mapErrorToCode(err error) codes.Code {
 	switch true {
-	case errors.Is(err, userdata.ErrDataNotFound):
+	case errors.Is(err, userdata.ErrDataNotFound), errors.Is(err, userdata.ErrRevisionNotFound):
 		return codes.NotFound
 	case errors.Is(err, userdata.ErrBadRequest):
 		return codes.InvalidArgument
+	case errors.Is(err, userdata.ErrVersionConflict):
+		return codes.Aborted
+	case errors.Is(err, userdata.ErrStaleKeyVersion):
+		return codes.FailedPrecondition
 	default:
 		return codes.Internal
 	}
@@ -7,12 +7,18 @@ import (
 
 	"github.com/golang/mock/gomock"
 	"github.com/ktigay/goph-keeper/internal/contracts/v1/data/mapper"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	"github.com/ktigay/goph-keeper/internal/contracts/v1/data"
 	"github.com/ktigay/goph-keeper/internal/entity"
+	"github.com/ktigay/goph-keeper/internal/server/changefeed"
 	c "github.com/ktigay/goph-keeper/internal/server/context"
+	se "github.com/ktigay/goph-keeper/internal/server/entity"
 	"github.com/ktigay/goph-keeper/internal/server/handler/grpc/mocks"
+	"github.com/ktigay/goph-keeper/internal/server/service/userdata"
 )
 
 func TestUserDataHandler_CreateUserDataItem(t *testing.T) {
@@ -417,3 +423,456 @@ func TestUserDataHandler_UpdateUserDataItem(t *testing.T) {
 		})
 	}
 }
+
+func TestUserDataHandler_UpdateUserDataItem_VersionConflict(t *testing.T) {
+	const (
+		userUUID = "33b06619-1ee7-3db5-827d-0dc85df1f759"
+		itemUUID = "10c33409-d8cc-4673-9bfc-3182a894acd4"
+	)
+	ctrl := gomock.NewController(t)
+	srv := mocks.NewMockUserDataService(ctrl)
+	srv.EXPECT().
+		Update(gomock.Any(), userUUID, gomock.Any()).Times(1).
+		DoAndReturn(func(_ context.Context, _ string, d entity.UserData) (*entity.UserData, error) {
+			if d.Version != 3 {
+				t.Errorf("Update() got version %d, want expected_version 3", d.Version)
+			}
+			return nil, &userdata.ConflictError{Current: entity.UserData{UUID: itemUUID, Title: "server", Version: 4}}
+		})
+
+	h := NewUserDataHandler(srv, nil)
+	ctx := c.NewContextWithIdentity(context.Background(), entity.Identity{UUID: userUUID})
+	_, err := h.UpdateUserDataItem(ctx, &data.UpdateUserDataItemRequest{
+		Item:            &data.UserDataItem{Uuid: itemUUID, Title: "local", Version: 7},
+		ExpectedVersion: 3,
+	})
+
+	st := status.Convert(err)
+	if st.Code() != codes.Aborted {
+		t.Fatalf("UpdateUserDataItem() code = %v, want %v", st.Code(), codes.Aborted)
+	}
+	var current *data.UserDataItem
+	for _, d := range st.Details() {
+		if conflict, ok := d.(*data.VersionConflict); ok {
+			current = conflict.GetCurrent()
+		}
+	}
+	if current.GetTitle() != "server" || current.GetVersion() != 4 {
+		t.Errorf("UpdateUserDataItem() current = %v, want server copy of version 4", current)
+	}
+}
+
+func TestUserDataHandler_TokenScope(t *testing.T) {
+	const (
+		userUUID  = "33b06619-1ee7-3db5-827d-0dc85df1f759"
+		itemUUID  = "10c33409-d8cc-4673-9bfc-3182a894acd4"
+		otherUUID = "4d7e2f9a-0b1c-4e3d-8a5f-6b7c8d9e0f1a"
+	)
+	deploy := []byte("0123456789abcdef0123456789abcdef")
+	tagged := entity.UserData{UUID: itemUUID, TagMACs: [][]byte{deploy}}
+	untagged := entity.UserData{UUID: otherUUID}
+
+	scopeCtx := func(scope se.APITokenScope) context.Context {
+		ctx := c.NewContextWithIdentity(context.Background(), entity.Identity{UUID: userUUID})
+		return c.NewContextWithTokenScope(ctx, scope)
+	}
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		call     func(ctx context.Context, h *UserDataHandler) error
+		srv      func(srv *mocks.MockUserDataService)
+		wantCode codes.Code
+	}{
+		{
+			name: "ReadOnly_Create_Denied",
+			ctx:  scopeCtx(se.APITokenScope{ReadOnly: true}),
+			call: func(ctx context.Context, h *UserDataHandler) error {
+				_, err := h.CreateUserDataItem(ctx, &data.CreateUserDataItemRequest{Item: &data.UserDataItem{}})
+				return err
+			},
+			srv:      func(*mocks.MockUserDataService) {},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name: "Items_Get_Out_Of_Scope",
+			ctx:  scopeCtx(se.APITokenScope{ItemUUIDs: []string{itemUUID}}),
+			call: func(ctx context.Context, h *UserDataHandler) error {
+				_, err := h.GetUserDataItem(ctx, &data.GetUserDataItemRequest{ItemUuid: otherUUID})
+				return err
+			},
+			srv: func(srv *mocks.MockUserDataService) {
+				srv.EXPECT().Read(gomock.Any(), userUUID, otherUUID).Times(1).Return([]entity.UserData{untagged}, nil)
+			},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name: "Items_List_Restricted",
+			ctx:  scopeCtx(se.APITokenScope{ItemUUIDs: []string{itemUUID}}),
+			call: func(ctx context.Context, h *UserDataHandler) error {
+				got, err := h.GetUserDataItems(ctx, &data.GetUserDataItemsRequest{})
+				if err == nil && len(got.GetItems()) != 1 {
+					t.Errorf("GetUserDataItems() got %d items, want 1", len(got.GetItems()))
+				}
+				return err
+			},
+			srv: func(srv *mocks.MockUserDataService) {
+				srv.EXPECT().Read(gomock.Any(), userUUID, itemUUID).Times(1).Return([]entity.UserData{tagged}, nil)
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name: "Tag_List_Filtered",
+			ctx:  scopeCtx(se.APITokenScope{TagMAC: deploy}),
+			call: func(ctx context.Context, h *UserDataHandler) error {
+				got, err := h.GetUserDataItems(ctx, &data.GetUserDataItemsRequest{})
+				if err == nil && (len(got.GetItems()) != 1 || got.GetItems()[0].GetUuid() != itemUUID) {
+					t.Errorf("GetUserDataItems() got = %v, want only the tagged item", got.GetItems())
+				}
+				return err
+			},
+			srv: func(srv *mocks.MockUserDataService) {
+				srv.EXPECT().Read(gomock.Any(), userUUID).Times(1).Return([]entity.UserData{tagged, untagged}, nil)
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name: "Items_ListChanges_Filtered",
+			ctx:  scopeCtx(se.APITokenScope{ItemUUIDs: []string{itemUUID}}),
+			call: func(ctx context.Context, h *UserDataHandler) error {
+				got, err := h.ListChanges(ctx, &data.ListChangesRequest{SinceRevision: 3})
+				if err == nil && (len(got.GetUpserts()) != 1 || len(got.GetDeletedUuids()) != 0 || got.GetRevision() != 5) {
+					t.Errorf("ListChanges() got = %v, want only the scoped item", got)
+				}
+				return err
+			},
+			srv: func(srv *mocks.MockUserDataService) {
+				srv.EXPECT().Changes(gomock.Any(), userUUID, uint64(3)).Times(1).Return(&entity.UserDataChanges{
+					Revision: 5,
+					Upserts:  []entity.UserData{tagged, untagged},
+					Deleted:  []string{otherUUID},
+				}, nil)
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name: "Tag_Delete_Untagged_Denied",
+			ctx:  scopeCtx(se.APITokenScope{TagMAC: deploy}),
+			call: func(ctx context.Context, h *UserDataHandler) error {
+				_, err := h.DeleteUserDataItems(ctx, &data.DeleteUserDataItemsRequest{ItemUuids: []string{itemUUID, otherUUID}})
+				return err
+			},
+			srv: func(srv *mocks.MockUserDataService) {
+				srv.EXPECT().Read(gomock.Any(), userUUID, itemUUID, otherUUID).Times(1).Return([]entity.UserData{tagged, untagged}, nil)
+				srv.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name: "Tag_Update_Success",
+			ctx:  scopeCtx(se.APITokenScope{TagMAC: deploy}),
+			call: func(ctx context.Context, h *UserDataHandler) error {
+				_, err := h.UpdateUserDataItem(ctx, &data.UpdateUserDataItemRequest{Item: mapper.MapEntityToItem(tagged)})
+				return err
+			},
+			srv: func(srv *mocks.MockUserDataService) {
+				srv.EXPECT().Read(gomock.Any(), userUUID, itemUUID).Times(1).Return([]entity.UserData{tagged}, nil)
+				srv.EXPECT().Update(gomock.Any(), userUUID, gomock.Any()).Times(1).Return(&tagged, nil)
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name: "Items_SyncBatch_Create_Denied",
+			ctx:  scopeCtx(se.APITokenScope{ItemUUIDs: []string{itemUUID}}),
+			call: func(ctx context.Context, h *UserDataHandler) error {
+				_, err := h.SyncBatch(ctx, &data.SyncBatchRequest{
+					Upserts: []*data.SyncBatchUpsert{{Item: &data.UserDataItem{Uuid: otherUUID}}},
+				})
+				return err
+			},
+			srv: func(srv *mocks.MockUserDataService) {
+				srv.EXPECT().SyncBatch(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name: "Tag_SyncBatch_Delete_Untagged_Denied",
+			ctx:  scopeCtx(se.APITokenScope{TagMAC: deploy}),
+			call: func(ctx context.Context, h *UserDataHandler) error {
+				_, err := h.SyncBatch(ctx, &data.SyncBatchRequest{
+					Upserts:     []*data.SyncBatchUpsert{{Item: mapper.MapEntityToItem(tagged), ExpectedVersion: 1}},
+					DeleteUuids: []string{otherUUID},
+				})
+				return err
+			},
+			srv: func(srv *mocks.MockUserDataService) {
+				srv.EXPECT().Read(gomock.Any(), userUUID, itemUUID, otherUUID).Times(1).Return([]entity.UserData{tagged, untagged}, nil)
+				srv.EXPECT().SyncBatch(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name: "Tag_SyncBatch_Create_Over_Existing_Denied",
+			ctx:  scopeCtx(se.APITokenScope{TagMAC: deploy}),
+			call: func(ctx context.Context, h *UserDataHandler) error {
+				// Новая запись с тегом и UUID существующей записи без тега вернула бы её в конфликте.
+				disguised := tagged
+				disguised.UUID = otherUUID
+				_, err := h.SyncBatch(ctx, &data.SyncBatchRequest{
+					Upserts: []*data.SyncBatchUpsert{{Item: mapper.MapEntityToItem(disguised)}},
+				})
+				return err
+			},
+			srv: func(srv *mocks.MockUserDataService) {
+				srv.EXPECT().Read(gomock.Any(), userUUID, otherUUID).Times(1).Return([]entity.UserData{untagged}, nil)
+				srv.EXPECT().SyncBatch(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name: "Items_SyncBatch_Result_Filtered",
+			ctx:  scopeCtx(se.APITokenScope{ItemUUIDs: []string{itemUUID}}),
+			call: func(ctx context.Context, h *UserDataHandler) error {
+				got, err := h.SyncBatch(ctx, &data.SyncBatchRequest{
+					Upserts: []*data.SyncBatchUpsert{{Item: &data.UserDataItem{Uuid: itemUUID}, ExpectedVersion: 1}},
+				})
+				if err == nil && (len(got.GetResults()) != 2 || got.GetResults()[0].GetItem() == nil || got.GetResults()[1].GetItem() != nil) {
+					t.Errorf("SyncBatch() got = %v, want only the scoped item", got.GetResults())
+				}
+				return err
+			},
+			srv: func(srv *mocks.MockUserDataService) {
+				srv.EXPECT().SyncBatch(gomock.Any(), userUUID, gomock.Any()).Times(1).Return(&entity.UserDataBatchResult{
+					Items: []entity.UserDataBatchItem{
+						{UUID: itemUUID, Status: entity.BatchItemApplied, Item: &tagged},
+						{UUID: otherUUID, Status: entity.BatchItemConflict, Item: &untagged},
+					},
+					Replayed: true,
+				}, nil)
+			},
+			wantCode: codes.OK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			srv := mocks.NewMockUserDataService(ctrl)
+			tt.srv(srv)
+
+			if err := tt.call(tt.ctx, NewUserDataHandler(srv, nil)); status.Code(err) != tt.wantCode {
+				t.Errorf("code = %v, want %v (%v)", status.Code(err), tt.wantCode, err)
+			}
+		})
+	}
+}
+
+func TestUserDataHandler_ItemRevisions(t *testing.T) {
+	const (
+		userUUID     = "33b06619-1ee7-3db5-827d-0dc85df1f759"
+		itemUUID     = "10c33409-d8cc-4673-9bfc-3182a894acd4"
+		revisionUUID = "5a2e7c1d-3f4b-4e6a-8d9c-0b1a2c3d4e5f"
+		otherRevUUID = "6b3f8d2e-4a5c-4f7b-9e0d-1c2b3d4e5f60"
+	)
+	deploy := []byte("0123456789abcdef0123456789abcdef")
+	tagged := entity.UserData{UUID: itemUUID, TagMACs: [][]byte{deploy}}
+	revisions := []entity.UserDataRevision{
+		{UUID: revisionUUID, Item: tagged},
+		{UUID: otherRevUUID, Item: entity.UserData{UUID: itemUUID}},
+	}
+	identity := entity.Identity{UUID: userUUID}
+
+	t.Run("List_FilteredByTokenTag", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		srv := mocks.NewMockUserDataService(ctrl)
+		srv.EXPECT().Read(gomock.Any(), userUUID, itemUUID).Return([]entity.UserData{tagged}, nil)
+		srv.EXPECT().Revisions(gomock.Any(), userUUID, itemUUID).Return(revisions, nil)
+
+		ctx := c.NewContextWithTokenScope(c.NewContextWithIdentity(context.Background(), identity), se.APITokenScope{TagMAC: deploy})
+		got, err := NewUserDataHandler(srv, nil).ListItemRevisions(ctx, &data.ListItemRevisionsRequest{ItemUuid: itemUUID})
+		if err != nil {
+			t.Fatalf("ListItemRevisions() error = %v", err)
+		}
+		if len(got.Revisions) != 1 || got.Revisions[0].Uuid != revisionUUID {
+			t.Errorf("ListItemRevisions() got = %v, want only %s", got.Revisions, revisionUUID)
+		}
+	})
+
+	t.Run("Restore_OutOfTokenTag", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		srv := mocks.NewMockUserDataService(ctrl)
+		srv.EXPECT().Read(gomock.Any(), userUUID, itemUUID).Return([]entity.UserData{tagged}, nil)
+		srv.EXPECT().Revisions(gomock.Any(), userUUID, itemUUID).Return(revisions, nil)
+		srv.EXPECT().Restore(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		ctx := c.NewContextWithTokenScope(c.NewContextWithIdentity(context.Background(), identity), se.APITokenScope{TagMAC: deploy})
+		_, err := NewUserDataHandler(srv, nil).RestoreItemRevision(ctx, &data.RestoreItemRevisionRequest{ItemUuid: itemUUID, RevisionUuid: otherRevUUID})
+		if status.Code(err) != codes.PermissionDenied {
+			t.Errorf("RestoreItemRevision() code = %v, want %v", status.Code(err), codes.PermissionDenied)
+		}
+	})
+
+	t.Run("Restore_Success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		srv := mocks.NewMockUserDataService(ctrl)
+		srv.EXPECT().Restore(gomock.Any(), userUUID, itemUUID, revisionUUID).Return(&entity.UserData{UUID: itemUUID, Version: 7}, nil)
+
+		ctx := c.NewContextWithIdentity(context.Background(), identity)
+		got, err := NewUserDataHandler(srv, nil).RestoreItemRevision(ctx, &data.RestoreItemRevisionRequest{ItemUuid: itemUUID, RevisionUuid: revisionUUID})
+		if err != nil {
+			t.Fatalf("RestoreItemRevision() error = %v", err)
+		}
+		if got.Item.GetVersion() != 7 {
+			t.Errorf("RestoreItemRevision() version = %d, want 7", got.Item.GetVersion())
+		}
+	})
+}
+
+// watchStream поток WatchChanges, сохраняющий отправленные сообщения.
+type watchStream struct {
+	grpclib.ServerStream
+	ctx  context.Context
+	sent []*data.ListChangesResponse
+}
+
+func (s *watchStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *watchStream) Send(m *data.ListChangesResponse) error {
+	s.sent = append(s.sent, m)
+	return nil
+}
+
+func TestUserDataHandler_WatchChanges(t *testing.T) {
+	const (
+		userUUID = "33b06619-1ee7-3db5-827d-0dc85df1f759"
+		itemUUID = "10c33409-d8cc-4673-9bfc-3182a894acd4"
+	)
+	ctx := c.NewContextWithIdentity(context.Background(), entity.Identity{UUID: userUUID})
+
+	t.Run("Watch_CatchUp_And_Notifications", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		srv := mocks.NewMockUserDataService(ctrl)
+		gomock.InOrder(
+			srv.EXPECT().Changes(gomock.Any(), userUUID, uint64(3)).Return(&entity.UserDataChanges{Revision: 3}, nil),
+			srv.EXPECT().Changes(gomock.Any(), userUUID, uint64(3)).Return(&entity.UserDataChanges{
+				Revision: 5,
+				Upserts:  []entity.UserData{{UUID: itemUUID}},
+			}, nil),
+		)
+
+		// Уведомление о ревизии 4 приходит после уже отправленной ревизии 5 и пропускается.
+		notify := make(chan uint64, 2)
+		notify <- 5
+		notify <- 4
+		close(notify)
+		feed := mocks.NewMockChangeFeed(ctrl)
+		feed.EXPECT().Subscribe(userUUID).Return((<-chan uint64)(notify), func() {})
+
+		stream := &watchStream{ctx: ctx}
+		err := NewUserDataHandler(srv, feed).WatchChanges(&data.WatchChangesRequest{SinceRevision: 3}, stream)
+		if status.Code(err) != codes.Unavailable {
+			t.Errorf("WatchChanges() code = %v, want %v", status.Code(err), codes.Unavailable)
+		}
+		if len(stream.sent) != 1 || stream.sent[0].Revision != 5 || len(stream.sent[0].Upserts) != 1 {
+			t.Errorf("WatchChanges() sent = %v, want single change at revision 5", stream.sent)
+		}
+	})
+
+	t.Run("Watch_Resync_Rereads_Changes", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		srv := mocks.NewMockUserDataService(ctrl)
+		gomock.InOrder(
+			srv.EXPECT().Changes(gomock.Any(), userUUID, uint64(5)).Return(&entity.UserDataChanges{Revision: 5}, nil),
+			srv.EXPECT().Changes(gomock.Any(), userUUID, uint64(5)).Return(&entity.UserDataChanges{
+				Revision: 6,
+				Deleted:  []string{itemUUID},
+			}, nil),
+		)
+
+		notify := make(chan uint64, 1)
+		notify <- changefeed.Resync
+		close(notify)
+		feed := mocks.NewMockChangeFeed(ctrl)
+		feed.EXPECT().Subscribe(userUUID).Return((<-chan uint64)(notify), func() {})
+
+		stream := &watchStream{ctx: ctx}
+		err := NewUserDataHandler(srv, feed).WatchChanges(&data.WatchChangesRequest{SinceRevision: 5}, stream)
+		if status.Code(err) != codes.Unavailable {
+			t.Errorf("WatchChanges() code = %v, want %v", status.Code(err), codes.Unavailable)
+		}
+		if len(stream.sent) != 1 || stream.sent[0].Revision != 6 {
+			t.Errorf("WatchChanges() sent = %v, want changes at revision 6 after resync", stream.sent)
+		}
+	})
+
+	t.Run("Watch_Canceled", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		srv := mocks.NewMockUserDataService(ctrl)
+		srv.EXPECT().Changes(gomock.Any(), userUUID, uint64(0)).Return(&entity.UserDataChanges{
+			Revision: 2,
+			Deleted:  []string{itemUUID},
+		}, nil)
+		feed := mocks.NewMockChangeFeed(ctrl)
+		feed.EXPECT().Subscribe(userUUID).Return(make(<-chan uint64), func() {})
+
+		cancelCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		stream := &watchStream{ctx: cancelCtx}
+		if err := NewUserDataHandler(srv, feed).WatchChanges(&data.WatchChangesRequest{}, stream); err != nil {
+			t.Errorf("WatchChanges() error = %v", err)
+		}
+		if len(stream.sent) != 1 || stream.sent[0].Revision != 2 {
+			t.Errorf("WatchChanges() sent = %v, want catch-up at revision 2", stream.sent)
+		}
+	})
+}
+
+func TestUserDataHandler_SyncBatch(t *testing.T) {
+	const (
+		userUUID  = "33b06619-1ee7-3db5-827d-0dc85df1f759"
+		batchUUID = "0f5cf0a4-5a2e-4d0c-93a6-3b2b9d3e4a11"
+		itemUUID  = "10c33409-d8cc-4673-9bfc-3182a894acd4"
+		otherUUID = "4d7e2f9a-0b1c-4e3d-8a5f-6b7c8d9e0f1a"
+	)
+	ctrl := gomock.NewController(t)
+	srv := mocks.NewMockUserDataService(ctrl)
+	srv.EXPECT().
+		SyncBatch(gomock.Any(), userUUID, gomock.Any()).Times(1).
+		DoAndReturn(func(_ context.Context, _ string, b entity.UserDataBatch) (*entity.UserDataBatchResult, error) {
+			if b.UUID != batchUUID || len(b.Upserts) != 1 || b.Upserts[0].Version != 3 || !reflect.DeepEqual(b.Deletes, []string{otherUUID}) {
+				t.Errorf("SyncBatch() got batch = %+v", b)
+			}
+			return &entity.UserDataBatchResult{
+				Revision: 9,
+				Items: []entity.UserDataBatchItem{
+					{UUID: itemUUID, Status: entity.BatchItemConflict, Item: &entity.UserData{UUID: itemUUID, Version: 4}},
+					{UUID: otherUUID, Status: entity.BatchItemApplied, Deleted: true},
+				},
+			}, nil
+		})
+
+	h := NewUserDataHandler(srv, nil)
+	ctx := c.NewContextWithIdentity(context.Background(), entity.Identity{UUID: userUUID})
+	got, err := h.SyncBatch(ctx, &data.SyncBatchRequest{
+		BatchId:     batchUUID,
+		Upserts:     []*data.SyncBatchUpsert{{Item: &data.UserDataItem{Uuid: itemUUID, Version: 7}, ExpectedVersion: 3}},
+		DeleteUuids: []string{otherUUID},
+	})
+	if err != nil {
+		t.Fatalf("SyncBatch() error = %v", err)
+	}
+	if got.GetRevision() != 9 || len(got.GetResults()) != 2 {
+		t.Fatalf("SyncBatch() got = %v", got)
+	}
+	if r := got.GetResults()[0]; r.GetStatus() != data.SyncBatchItemResult_VERSION_CONFLICT || r.GetItem().GetVersion() != 4 {
+		t.Errorf("SyncBatch() upsert result = %v, want conflict with version 4", r)
+	}
+	if r := got.GetResults()[1]; r.GetStatus() != data.SyncBatchItemResult_APPLIED || !r.GetDeleted() {
+		t.Errorf("SyncBatch() delete result = %v, want applied deletion", r)
+	}
+
+	if _, err = h.SyncBatch(ctx, &data.SyncBatchRequest{BatchId: batchUUID, Upserts: []*data.SyncBatchUpsert{{}}}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("SyncBatch() without item code = %v, want %v", status.Code(err), codes.InvalidArgument)
+	}
+}
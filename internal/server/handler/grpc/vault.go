@@ -0,0 +1,157 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ktigay/goph-keeper/internal/contracts/v1/vault"
+	"github.com/ktigay/goph-keeper/internal/contracts/v1/vault/mapper"
+	"github.com/ktigay/goph-keeper/internal/entity"
+	c "github.com/ktigay/goph-keeper/internal/server/context"
+	vaultsrv "github.com/ktigay/goph-keeper/internal/server/service/vault"
+)
+
+// VaultService сервис ключей хранилища.
+//
+//go:generate mockgen -destination=./mocks/mock_vault.go -package=mocks github.com/ktigay/goph-keeper/internal/server/handler/grpc VaultService
+type VaultService interface {
+	Get(ctx context.Context, userUUID string) (*entity.VaultKey, error)
+	Create(ctx context.Context, userUUID string, key entity.VaultKey) (*entity.VaultKey, error)
+	BeginRotation(ctx context.Context, userUUID string, key entity.VaultKey) (*entity.VaultKey, error)
+	CommitRotation(ctx context.Context, userUUID string, keyVersion uint32, items []entity.UserData) (*entity.VaultKey, error)
+}
+
+// VaultHandler обработчик ключей хранилища.
+type VaultHandler struct {
+	vault.UnimplementedVaultServiceServer
+	srv VaultService
+}
+
+// GetVaultKey возвращает ключ хранилища.
+func (v *VaultHandler) GetVaultKey(ctx context.Context, _ *vault.GetVaultKeyRequest) (*vault.GetVaultKeyResponse, error) {
+	var (
+		identity *entity.Identity
+		k        *entity.VaultKey
+		err      error
+	)
+
+	if identity, err = c.IdentityFromContext(ctx); err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "authorization required: %v", err)
+	}
+
+	if k, err = v.srv.Get(ctx, identity.UUID); err != nil {
+		return nil, status.Errorf(mapVaultErrorToCode(err), "%v", err)
+	}
+
+	resp := &vault.GetVaultKeyResponse{
+		Key: mapper.MapEntityToKey(*k),
+	}
+	if k.Rotation != nil {
+		resp.Rotation = mapper.MapEntityToKey(*k.Rotation)
+	}
+	return resp, nil
+}
+
+// CreateVaultKey создаёт ключ хранилища.
+func (v *VaultHandler) CreateVaultKey(ctx context.Context, req *vault.CreateVaultKeyRequest) (*vault.CreateVaultKeyResponse, error) {
+	var (
+		identity *entity.Identity
+		k        *entity.VaultKey
+		err      error
+	)
+
+	if identity, err = c.IdentityFromContext(ctx); err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "authorization required: %v", err)
+	}
+
+	if req.GetKey() == nil {
+		return nil, status.Error(codes.InvalidArgument, "key is required")
+	}
+
+	if k, err = v.srv.Create(ctx, identity.UUID, mapper.MapKeyToEntity(req.GetKey())); err != nil {
+		return nil, status.Errorf(mapVaultErrorToCode(err), "%v", err)
+	}
+
+	return &vault.CreateVaultKeyResponse{
+		Key: mapper.MapEntityToKey(*k),
+	}, nil
+}
+
+// BeginRotation начинает ротацию ключа хранилища.
+func (v *VaultHandler) BeginRotation(ctx context.Context, req *vault.BeginRotationRequest) (*vault.BeginRotationResponse, error) {
+	var (
+		identity *entity.Identity
+		k        *entity.VaultKey
+		err      error
+	)
+
+	if identity, err = c.IdentityFromContext(ctx); err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "authorization required: %v", err)
+	}
+
+	if req.GetKey() == nil {
+		return nil, status.Error(codes.InvalidArgument, "key is required")
+	}
+
+	if k, err = v.srv.BeginRotation(ctx, identity.UUID, mapper.MapKeyToEntity(req.GetKey())); err != nil {
+		return nil, status.Errorf(mapVaultErrorToCode(err), "%v", err)
+	}
+
+	return &vault.BeginRotationResponse{
+		Key: mapper.MapEntityToKey(*k),
+	}, nil
+}
+
+// CommitRotation завершает ротацию ключа хранилища.
+func (v *VaultHandler) CommitRotation(ctx context.Context, req *vault.CommitRotationRequest) (*vault.CommitRotationResponse, error) {
+	var (
+		identity *entity.Identity
+		k        *entity.VaultKey
+		err      error
+	)
+
+	if identity, err = c.IdentityFromContext(ctx); err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "authorization required: %v", err)
+	}
+
+	items := make([]entity.UserData, len(req.GetItems()))
+	for i, item := range req.GetItems() {
+		items[i] = mapper.MapRotatedItemToEntity(item)
+	}
+
+	if k, err = v.srv.CommitRotation(ctx, identity.UUID, req.GetKeyVersion(), items); err != nil {
+		return nil, status.Errorf(mapVaultErrorToCode(err), "%v", err)
+	}
+
+	return &vault.CommitRotationResponse{
+		Key: mapper.MapEntityToKey(*k),
+	}, nil
+}
+
+// NewVaultHandler конструктор.
+func NewVaultHandler(s VaultService) *VaultHandler {
+	return &VaultHandler{
+		srv: s,
+	}
+}
+
+func mapVaultErrorToCode(err error) codes.Code {
+	switch true {
+	case errors.Is(err, vaultsrv.ErrVaultKeyNotFound):
+		return codes.NotFound
+	case errors.Is(err, vaultsrv.ErrVaultKeyExists):
+		return codes.AlreadyExists
+	case errors.Is(err, vaultsrv.ErrBadRequest):
+		return codes.InvalidArgument
+	case errors.Is(err, vaultsrv.ErrRotationNotFound),
+		errors.Is(err, vaultsrv.ErrRotationVersionMismatch):
+		return codes.FailedPrecondition
+	case errors.Is(err, vaultsrv.ErrRotationConflict):
+		return codes.Aborted
+	default:
+		return codes.Internal
+	}
+}
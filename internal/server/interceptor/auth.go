@@ -2,8 +2,12 @@ package interceptor
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
@@ -11,29 +15,76 @@ import (
 
 	"github.com/ktigay/goph-keeper/internal/entity"
 	c "github.com/ktigay/goph-keeper/internal/server/context"
+	se "github.com/ktigay/goph-keeper/internal/server/entity"
+	"github.com/ktigay/goph-keeper/internal/server/policy"
 )
 
 const (
 	authorizationHeader = "authorization"
+	bearerPrefix        = "Bearer "
+	// streamRecheckInterval период повторной проверки сессии или токена открытого потока.
+	streamRecheckInterval = 10 * time.Second
+)
+
+var (
+	// errStreamExpired срок действия токена потока истек.
+	errStreamExpired = errors.New("access token expired")
+	// errStreamRevoked сессия или токен потока отозваны.
+	errStreamRevoked = errors.New("access revoked")
+	// errStreamCheckFailed повторная проверка сессии потока не выполнена.
+	errStreamCheckFailed = errors.New("session check failed")
 )
 
 // JWTWrapper JWT обработчик.
 type JWTWrapper interface {
 	GenerateToken(payload entity.Identity) (string, error)
-	ParseToken(s string) (*entity.Identity, error)
+	ParseTokenExpiry(s string) (*entity.Identity, time.Time, error)
+}
+
+// SessionChecker проверка сессии токена.
+//
+//go:generate mockgen -destination=./mocks/mock_session.go -package=mocks github.com/ktigay/goph-keeper/internal/server/interceptor SessionChecker
+type SessionChecker interface {
+	Touch(ctx context.Context, uuid, ip string) (bool, error)
+}
+
+// TokenAuthenticator проверка токена доступа.
+//
+//go:generate mockgen -destination=./mocks/mock_token.go -package=mocks github.com/ktigay/goph-keeper/internal/server/interceptor TokenAuthenticator
+type TokenAuthenticator interface {
+	Authenticate(ctx context.Context, token string) (*se.APIToken, error)
+}
+
+// Policy правила доступа к методам.
+type Policy interface {
+	Rule(method string) (policy.Rule, bool)
+	Authorize(method string, identity entity.Identity) error
 }
 
 // Auth структура для авторизации.
 type Auth struct {
-	jwt        JWTWrapper
-	accessList map[string]bool
+	jwt      JWTWrapper
+	sessions SessionChecker
+	tokens   TokenAuthenticator
+	policy   Policy
+	// recheck период повторной проверки открытых потоков.
+	recheck time.Duration
+}
+
+// credential токен, по которому авторизован запрос.
+type credential struct {
+	token string
+	// apiToken токен доступа, иначе JWT сессии sessionUUID.
+	apiToken    bool
+	sessionUUID string
+	expiresAt   time.Time
 }
 
 // WithAuthorization интерцептор для работы с авторизацией.
 func (i *Auth) WithAuthorization() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
 		if ctx, err = i.authorization(ctx, info.FullMethod); err != nil {
-			return nil, status.Error(codes.PermissionDenied, err.Error())
+			return nil, err
 		}
 
 		resp, err = handler(ctx, req)
@@ -42,43 +93,193 @@ func (i *Auth) WithAuthorization() grpc.UnaryServerInterceptor {
 	}
 }
 
+// WithStreamAuthorization потоковый интерцептор для работы с авторизацией.
+// Открытый поток завершается с [codes.Unauthenticated] по истечении срока действия токена,
+// а также если при повторной проверке раз в recheck сессия или токен оказались отозваны.
+func (i *Auth) WithStreamAuthorization() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, cred, err := i.authorize(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		if cred == nil {
+			return handler(srv, withContext(ss, ctx))
+		}
+
+		ctx, cancelExpired := context.WithDeadlineCause(ctx, cred.expiresAt, errStreamExpired)
+		defer cancelExpired()
+		ctx, revoke := context.WithCancelCause(ctx)
+		defer revoke(nil)
+		go i.watchCredential(ctx, *cred, revoke)
+
+		err = handler(srv, withContext(ss, ctx))
+		switch cause := context.Cause(ctx); {
+		case errors.Is(cause, errStreamExpired), errors.Is(cause, errStreamRevoked):
+			return status.Errorf(codes.Unauthenticated, "stream closed: %v", cause)
+		case errors.Is(cause, errStreamCheckFailed):
+			return status.Errorf(codes.Unavailable, "stream closed: %v", cause)
+		}
+		return err
+	}
+}
+
+// watchCredential раз в recheck повторяет проверку сессии или токена потока и отменяет поток,
+// если они больше не действуют.
+func (i *Auth) watchCredential(ctx context.Context, cred credential, revoke context.CancelCauseFunc) {
+	ticker := time.NewTicker(i.recheck)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		var err error
+		if cred.apiToken {
+			if _, err = i.tokens.Authenticate(ctx, cred.token); err != nil {
+				err = fmt.Errorf("%w: %v", errStreamRevoked, err)
+			}
+		} else {
+			active, tErr := i.sessions.Touch(ctx, cred.sessionUUID, c.PeerIP(ctx))
+			switch {
+			case tErr != nil:
+				err = fmt.Errorf("%w: %v", errStreamCheckFailed, tErr)
+			case !active:
+				err = errStreamRevoked
+			}
+		}
+		if err != nil && ctx.Err() == nil {
+			revoke(err)
+			return
+		}
+	}
+}
+
+// authorization проверяет токен, доступ к методу по правилам и добавляет в контекст идентификатор пользователя.
+// Нет токена, невалидный или просроченный токен, а также токен отозванной сессии - [codes.Unauthenticated],
+// метод не в правилах или нет роли/разрешения - [codes.PermissionDenied] с причиной.
 func (i *Auth) authorization(ctx context.Context, method string) (context.Context, error) {
+	ctx, _, err := i.authorize(ctx, method)
+	return ctx, err
+}
+
+// authorize проверяет доступ как [Auth.authorization] и возвращает токен запроса; nil - публичный метод.
+func (i *Auth) authorize(ctx context.Context, method string) (context.Context, *credential, error) {
 	var (
 		md       metadata.MD
 		values   []string
 		identity *entity.Identity
+		cred     *credential
+		rule     policy.Rule
 		err      error
-		check    bool
 		ok       bool
 	)
 
-	if check, ok = i.accessList[method]; !ok {
-		return nil, fmt.Errorf("permission denied for method: %s", method)
+	if rule, ok = i.policy.Rule(method); !ok {
+		return nil, nil, permissionDenied(fmt.Errorf("%w: %s", policy.ErrMethodNotAllowed, method))
+	}
+
+	if rule.Public {
+		return ctx, nil, nil
+	}
+
+	if md, ok = metadata.FromIncomingContext(ctx); ok {
+		values = md[authorizationHeader]
+	}
+	if len(values) < 1 {
+		return nil, nil, status.Error(codes.Unauthenticated, "missing authorization header")
+	}
+
+	if token := strings.TrimPrefix(values[0], bearerPrefix); strings.HasPrefix(token, se.APITokenPrefix) {
+		identity, cred, ctx, err = i.tokenIdentity(ctx, token)
+	} else {
+		identity, cred, err = i.sessionIdentity(ctx, values[0])
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = i.policy.Authorize(method, *identity); err != nil {
+		return nil, nil, permissionDenied(err)
+	}
+
+	return c.NewContextWithIdentity(ctx, *identity), cred, nil
+}
+
+// sessionIdentity проверяет JWT и активность его сессии.
+func (i *Auth) sessionIdentity(ctx context.Context, token string) (*entity.Identity, *credential, error) {
+	identity, expiresAt, err := i.jwt.ParseTokenExpiry(token)
+	if err != nil {
+		return nil, nil, status.Errorf(codes.Unauthenticated, "parse token failed: %v", err)
 	}
 
-	if !check {
-		return ctx, nil
+	active, err := i.sessions.Touch(ctx, identity.SessionUUID, c.PeerIP(ctx))
+	if err != nil {
+		return nil, nil, status.Errorf(codes.Internal, "session check failed: %v", err)
+	}
+	if !active {
+		return nil, nil, status.Error(codes.Unauthenticated, "session revoked")
 	}
 
-	if md, ok = metadata.FromIncomingContext(ctx); !ok {
-		return nil, fmt.Errorf("failed to extract authorization header")
+	return identity, &credential{token: token, sessionUUID: identity.SessionUUID, expiresAt: expiresAt}, nil
+}
+
+// tokenIdentity проверяет токен доступа и добавляет в контекст его ограничения.
+// Разрешения токена определяются его ограничениями, роли у токена нет.
+func (i *Auth) tokenIdentity(ctx context.Context, token string) (*entity.Identity, *credential, context.Context, error) {
+	t, err := i.tokens.Authenticate(ctx, token)
+	if err != nil {
+		return nil, nil, nil, status.Errorf(codes.Unauthenticated, "access token rejected: %v", err)
 	}
 
-	if values, ok = md[authorizationHeader]; !ok || len(values) < 1 {
-		return ctx, nil
+	identity := &entity.Identity{
+		UUID:   t.UserUUID,
+		Scopes: t.Scope.Scopes(),
 	}
+	return identity, &credential{token: token, apiToken: true, expiresAt: t.ExpiresAt}, c.NewContextWithTokenScope(ctx, t.Scope), nil
+}
 
-	if identity, err = i.jwt.ParseToken(values[0]); err != nil {
-		return nil, fmt.Errorf("parse token failed: %w", err)
+// contextStream поток с контекстом, дополненным интерцепторами.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context возвращает контекст потока.
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+func withContext(ss grpc.ServerStream, ctx context.Context) grpc.ServerStream {
+	return &contextStream{ServerStream: ss, ctx: ctx}
+}
+
+// permissionDenied возвращает PermissionDenied с причиной отказа в ErrorInfo.
+func permissionDenied(err error) error {
+	reason := entity.ReasonMethodNotAllowed
+	switch {
+	case errors.Is(err, policy.ErrRoleRequired):
+		reason = entity.ReasonRoleRequired
+	case errors.Is(err, policy.ErrScopeRequired):
+		reason = entity.ReasonScopeRequired
 	}
 
-	return c.NewContextWithIdentity(ctx, *identity), nil
+	st := status.New(codes.PermissionDenied, err.Error())
+	if withDetails, dErr := st.WithDetails(&errdetails.ErrorInfo{Reason: reason, Domain: entity.ErrorDomain}); dErr == nil {
+		st = withDetails
+	}
+	return st.Err()
 }
 
 // NewAuth конструктор.
-func NewAuth(jwt JWTWrapper, accessList map[string]bool) *Auth {
+func NewAuth(jwt JWTWrapper, sessions SessionChecker, tokens TokenAuthenticator, p Policy) *Auth {
 	return &Auth{
-		jwt:        jwt,
-		accessList: accessList,
+		jwt:      jwt,
+		sessions: sessions,
+		tokens:   tokens,
+		policy:   p,
+		recheck:  streamRecheckInterval,
 	}
 }
@@ -0,0 +1,69 @@
+package interceptor
+
+import (
+	"context"
+	"crypto/x509"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	c "github.com/ktigay/goph-keeper/internal/server/context"
+	"github.com/ktigay/goph-keeper/internal/server/security/certs"
+)
+
+// WithClientCertBinding проверяет привязку клиентского сертификата к аккаунту (mTLS).
+// Запрос пользователя по сертификату, привязанному к другому аккаунту, отклоняется;
+// с required запросы пользователя без привязанного к нему сертификата тоже отклоняются.
+// Должен идти после интерцептора авторизации.
+func WithClientCertBinding(required bool) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		if err = checkClientCert(ctx, required); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// WithStreamClientCertBinding проверяет привязку клиентского сертификата в потоковых методах,
+// см. [WithClientCertBinding].
+func WithStreamClientCertBinding(required bool) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := checkClientCert(ss.Context(), required); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func checkClientCert(ctx context.Context, required bool) error {
+	identity, err := c.IdentityFromContext(ctx)
+	if err != nil {
+		// Метод без авторизации.
+		return nil
+	}
+
+	bound := certs.BoundUser(verifiedClientCert(ctx))
+	switch {
+	case bound != "" && bound != identity.UUID:
+		return status.Error(codes.PermissionDenied, "client certificate is bound to another account")
+	case bound == "" && required:
+		return status.Error(codes.PermissionDenied, "client certificate bound to the account is required")
+	}
+	return nil
+}
+
+// verifiedClientCert возвращает проверенный по CA клиентский сертификат соединения или nil.
+func verifiedClientCert(ctx context.Context) *x509.Certificate {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return info.State.VerifiedChains[0][0]
+}
@@ -0,0 +1,55 @@
+package interceptor
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/url"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/ktigay/goph-keeper/internal/entity"
+	c "github.com/ktigay/goph-keeper/internal/server/context"
+	"github.com/ktigay/goph-keeper/internal/server/security/certs"
+)
+
+func TestCheckClientCert(t *testing.T) {
+	const userUUID = "33b06619-1ee7-3db5-827d-0dc85df1f759"
+
+	withCert := func(ctx context.Context, boundTo string) context.Context {
+		cert := &x509.Certificate{}
+		if boundTo != "" {
+			u, _ := url.Parse(certs.UserURIPrefix + boundTo)
+			cert.URIs = []*url.URL{u}
+		}
+		return peer.NewContext(ctx, &peer.Peer{AuthInfo: credentials.TLSInfo{
+			State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}},
+		}})
+	}
+	userCtx := c.NewContextWithIdentity(context.Background(), entity.Identity{UUID: userUUID})
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		required bool
+		wantCode codes.Code
+	}{
+		{name: "Public_Method", ctx: context.Background(), required: true, wantCode: codes.OK},
+		{name: "No_Cert_Optional", ctx: userCtx, wantCode: codes.OK},
+		{name: "No_Cert_Required", ctx: userCtx, required: true, wantCode: codes.PermissionDenied},
+		{name: "Unbound_Cert_Required", ctx: withCert(userCtx, ""), required: true, wantCode: codes.PermissionDenied},
+		{name: "Bound_Cert", ctx: withCert(userCtx, userUUID), required: true, wantCode: codes.OK},
+		{name: "Cert_Of_Other_User", ctx: withCert(userCtx, "other-uuid"), wantCode: codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkClientCert(tt.ctx, tt.required); status.Code(err) != tt.wantCode {
+				t.Errorf("checkClientCert() code = %v, want %v", status.Code(err), tt.wantCode)
+			}
+		})
+	}
+}
@@ -31,3 +31,25 @@ func WithLogging(l *slog.Logger) grpc.UnaryServerInterceptor {
 		return resp, err
 	}
 }
+
+// WithStreamLogging логирует открытие и завершение потока.
+func WithStreamLogging(l *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+
+		l.Info(
+			"stream",
+			"method", info.FullMethod,
+		)
+
+		err := handler(srv, ss)
+
+		l.Info(
+			"stream closed",
+			"method", info.FullMethod,
+			"duration", time.Since(start),
+			"error", err,
+		)
+		return err
+	}
+}
@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ktigay/goph-keeper/internal/server/interceptor (interfaces: SessionChecker)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockSessionChecker is a mock of SessionChecker interface.
+type MockSessionChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionCheckerMockRecorder
+}
+
+// MockSessionCheckerMockRecorder is the mock recorder for MockSessionChecker.
+type MockSessionCheckerMockRecorder struct {
+	mock *MockSessionChecker
+}
+
+// NewMockSessionChecker creates a new mock instance.
+func NewMockSessionChecker(ctrl *gomock.Controller) *MockSessionChecker {
+	mock := &MockSessionChecker{ctrl: ctrl}
+	mock.recorder = &MockSessionCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSessionChecker) EXPECT() *MockSessionCheckerMockRecorder {
+	return m.recorder
+}
+
+// Touch mocks base method.
+func (m *MockSessionChecker) Touch(arg0 context.Context, arg1, arg2 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Touch", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Touch indicates an expected call of Touch.
+func (mr *MockSessionCheckerMockRecorder) Touch(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Touch", reflect.TypeOf((*MockSessionChecker)(nil).Touch), arg0, arg1, arg2)
+}
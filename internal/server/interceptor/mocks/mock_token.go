@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ktigay/goph-keeper/internal/server/interceptor (interfaces: TokenAuthenticator)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	entity "github.com/ktigay/goph-keeper/internal/server/entity"
+)
+
+// MockTokenAuthenticator is a mock of TokenAuthenticator interface.
+type MockTokenAuthenticator struct {
+	ctrl     *gomock.Controller
+	recorder *MockTokenAuthenticatorMockRecorder
+}
+
+// MockTokenAuthenticatorMockRecorder is the mock recorder for MockTokenAuthenticator.
+type MockTokenAuthenticatorMockRecorder struct {
+	mock *MockTokenAuthenticator
+}
+
+// NewMockTokenAuthenticator creates a new mock instance.
+func NewMockTokenAuthenticator(ctrl *gomock.Controller) *MockTokenAuthenticator {
+	mock := &MockTokenAuthenticator{ctrl: ctrl}
+	mock.recorder = &MockTokenAuthenticatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTokenAuthenticator) EXPECT() *MockTokenAuthenticatorMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockTokenAuthenticator) Authenticate(arg0 context.Context, arg1 string) (*entity.APIToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", arg0, arg1)
+	ret0, _ := ret[0].(*entity.APIToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockTokenAuthenticatorMockRecorder) Authenticate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockTokenAuthenticator)(nil).Authenticate), arg0, arg1)
+}
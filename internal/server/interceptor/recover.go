@@ -27,3 +27,18 @@ func WithRecover(logger *slog.Logger) grpc.UnaryServerInterceptor {
 		return resp, err
 	}
 }
+
+// WithStreamRecover перехват panic в потоковых методах.
+func WithStreamRecover(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if e := recover(); e != nil {
+				logger.Error("Recovering from", "error", e, "stack", string(debug.Stack()))
+
+				err = status.Errorf(codes.Internal, "panic: %v", e)
+			}
+		}()
+
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,63 @@
+package interceptor
+
+import (
+	"github.com/ktigay/goph-keeper/internal/contracts/v1/auth"
+	"github.com/ktigay/goph-keeper/internal/contracts/v1/data"
+	"github.com/ktigay/goph-keeper/internal/contracts/v1/keys"
+	"github.com/ktigay/goph-keeper/internal/contracts/v1/session"
+	"github.com/ktigay/goph-keeper/internal/contracts/v1/token"
+	"github.com/ktigay/goph-keeper/internal/contracts/v1/vault"
+	"github.com/ktigay/goph-keeper/internal/entity"
+	"github.com/ktigay/goph-keeper/internal/server/policy"
+)
+
+var (
+	public    = policy.Rule{Public: true}
+	account   = policy.Rule{Scopes: []entity.Scope{entity.ScopeAccount}}
+	dataRead  = policy.Rule{Scopes: []entity.Scope{entity.ScopeDataRead}}
+	dataWrite = policy.Rule{Scopes: []entity.Scope{entity.ScopeDataWrite}}
+	vaultRead = policy.Rule{Scopes: []entity.Scope{entity.ScopeVaultRead}}
+	admin     = policy.Rule{Roles: []entity.Role{entity.RoleAdmin}, Scopes: []entity.Scope{entity.ScopeAccount}}
+)
+
+// Rules правила доступа к методам. Метод, которого нет в правилах, недоступен.
+func Rules() map[string]policy.Rule {
+	return map[string]policy.Rule{
+		auth.AuthService_Register_FullMethodName:                     public,
+		auth.AuthService_Login_FullMethodName:                        public,
+		auth.AuthService_Refresh_FullMethodName:                      public,
+		auth.AuthService_VerifySecondFactor_FullMethodName:           public,
+		auth.AuthService_EnrollSecondFactor_FullMethodName:           account,
+		auth.AuthService_ConfirmSecondFactor_FullMethodName:          account,
+		auth.AuthService_ChangePassword_FullMethodName:               account,
+		auth.AuthService_DeleteAccount_FullMethodName:                account,
+		auth.AuthService_CancelAccountDeletion_FullMethodName:        account,
+		auth.AuthService_SrpRegister_FullMethodName:                  public,
+		auth.AuthService_SrpStart_FullMethodName:                     public,
+		auth.AuthService_SrpLogin_FullMethodName:                     public,
+		auth.AuthService_SrpProofStart_FullMethodName:                account,
+		data.UserDataService_CreateUserDataItem_FullMethodName:       dataWrite,
+		data.UserDataService_UpdateUserDataItem_FullMethodName:       dataWrite,
+		data.UserDataService_GetUserDataItem_FullMethodName:          dataRead,
+		data.UserDataService_GetUserDataItems_FullMethodName:         dataRead,
+		data.UserDataService_DeleteUserDataItems_FullMethodName:      dataWrite,
+		data.UserDataService_ListItemRevisions_FullMethodName:        dataRead,
+		data.UserDataService_RestoreItemRevision_FullMethodName:      dataWrite,
+		data.UserDataService_ListChanges_FullMethodName:              dataRead,
+		data.UserDataService_WatchChanges_FullMethodName:             dataRead,
+		data.UserDataService_SyncBatch_FullMethodName:                dataWrite,
+		vault.VaultService_GetVaultKey_FullMethodName:                vaultRead,
+		vault.VaultService_CreateVaultKey_FullMethodName:             account,
+		vault.VaultService_BeginRotation_FullMethodName:              account,
+		vault.VaultService_CommitRotation_FullMethodName:             account,
+		session.SessionService_ListSessions_FullMethodName:           account,
+		session.SessionService_RevokeSession_FullMethodName:          account,
+		session.SessionService_RevokeAllOtherSessions_FullMethodName: account,
+		session.SessionService_ListUserSessions_FullMethodName:       admin,
+		session.SessionService_RevokeUserSessions_FullMethodName:     admin,
+		keys.KeyService_GetJWKS_FullMethodName:                       public,
+		token.TokenService_CreateToken_FullMethodName:                account,
+		token.TokenService_ListTokens_FullMethodName:                 account,
+		token.TokenService_RevokeToken_FullMethodName:                account,
+	}
+}
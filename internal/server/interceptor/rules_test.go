@@ -0,0 +1,94 @@
+package interceptor
+
+import (
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc"
+
+	"github.com/ktigay/goph-keeper/internal/contracts/v1/auth"
+	"github.com/ktigay/goph-keeper/internal/contracts/v1/data"
+	"github.com/ktigay/goph-keeper/internal/contracts/v1/keys"
+	"github.com/ktigay/goph-keeper/internal/contracts/v1/session"
+	"github.com/ktigay/goph-keeper/internal/contracts/v1/token"
+	"github.com/ktigay/goph-keeper/internal/contracts/v1/vault"
+	"github.com/ktigay/goph-keeper/internal/entity"
+	se "github.com/ktigay/goph-keeper/internal/server/entity"
+	"github.com/ktigay/goph-keeper/internal/server/policy"
+)
+
+func TestRules_CoversAllMethods(t *testing.T) {
+	rules := Rules()
+
+	for _, desc := range []grpc.ServiceDesc{
+		auth.AuthService_ServiceDesc,
+		data.UserDataService_ServiceDesc,
+		vault.VaultService_ServiceDesc,
+		session.SessionService_ServiceDesc,
+		keys.KeyService_ServiceDesc,
+		token.TokenService_ServiceDesc,
+	} {
+		for _, m := range desc.Methods {
+			name := "/" + desc.ServiceName + "/" + m.MethodName
+			if _, ok := rules[name]; !ok {
+				t.Errorf("Rules() has no entry for %s", name)
+			}
+		}
+		for _, st := range desc.Streams {
+			name := "/" + desc.ServiceName + "/" + st.StreamName
+			if _, ok := rules[name]; !ok {
+				t.Errorf("Rules() has no entry for %s", name)
+			}
+		}
+	}
+}
+
+func TestRules_APIToken(t *testing.T) {
+	engine := policy.New(Rules())
+	readOnly := entity.Identity{UUID: "user", Scopes: se.APITokenScope{ReadOnly: true}.Scopes()}
+	readWrite := entity.Identity{UUID: "user", Scopes: se.APITokenScope{}.Scopes()}
+
+	tests := []struct {
+		name     string
+		method   string
+		identity entity.Identity
+		allowed  bool
+	}{
+		{name: "ReadOnly_Get", method: data.UserDataService_GetUserDataItems_FullMethodName, identity: readOnly, allowed: true},
+		{name: "ReadOnly_Vault_Key", method: vault.VaultService_GetVaultKey_FullMethodName, identity: readOnly, allowed: true},
+		{name: "ReadOnly_Create", method: data.UserDataService_CreateUserDataItem_FullMethodName, identity: readOnly},
+		{name: "ReadOnly_Delete", method: data.UserDataService_DeleteUserDataItems_FullMethodName, identity: readOnly},
+		{name: "ReadWrite_Update", method: data.UserDataService_UpdateUserDataItem_FullMethodName, identity: readWrite, allowed: true},
+		{name: "ReadWrite_Create_Token", method: token.TokenService_CreateToken_FullMethodName, identity: readWrite},
+		{name: "ReadWrite_Sessions", method: session.SessionService_ListSessions_FullMethodName, identity: readWrite},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := engine.Authorize(tt.method, tt.identity); (err == nil) != tt.allowed {
+				t.Errorf("Authorize() error = %v, allowed %v", err, tt.allowed)
+			}
+		})
+	}
+}
+
+func TestRules_Admin(t *testing.T) {
+	engine := policy.New(Rules())
+	user := entity.Identity{UUID: "user", Role: entity.RoleUser, Scopes: entity.SessionScopes()}
+	admin := entity.Identity{UUID: "admin", Role: entity.RoleAdmin, Scopes: entity.SessionScopes()}
+	token := entity.Identity{UUID: "admin", Scopes: se.APITokenScope{}.Scopes()}
+
+	for _, method := range []string{
+		session.SessionService_ListUserSessions_FullMethodName,
+		session.SessionService_RevokeUserSessions_FullMethodName,
+	} {
+		if err := engine.Authorize(method, user); !errors.Is(err, policy.ErrRoleRequired) {
+			t.Errorf("Authorize(%s) for user error = %v, want %v", method, err, policy.ErrRoleRequired)
+		}
+		if err := engine.Authorize(method, token); err == nil {
+			t.Errorf("Authorize(%s) must deny access tokens", method)
+		}
+		if err := engine.Authorize(method, admin); err != nil {
+			t.Errorf("Authorize(%s) for admin error = %v", method, err)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// Package policy проверяет доступ к методам gRPC по ролям и разрешениям из токена.
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/ktigay/goph-keeper/internal/entity"
+)
+
+var (
+	// ErrMethodNotAllowed метод не описан в правилах.
+	ErrMethodNotAllowed = errors.New("method not allowed")
+	// ErrRoleRequired нет роли, необходимой для метода.
+	ErrRoleRequired = errors.New("role required")
+	// ErrScopeRequired нет разрешения, необходимого для метода.
+	ErrScopeRequired = errors.New("scope required")
+)
+
+// Rule правило доступа к методу.
+type Rule struct {
+	// Public метод доступен без авторизации.
+	Public bool
+	// Roles роли, любая из которых дает доступ. Пусто - любая роль.
+	Roles []entity.Role
+	// Scopes разрешения, которые все должны быть у токена.
+	Scopes []entity.Scope
+}
+
+// Engine правила доступа по полному имени метода.
+type Engine struct {
+	rules map[string]Rule
+}
+
+// Rule возвращает правило метода. False - метод не описан и недоступен.
+func (e *Engine) Rule(method string) (Rule, bool) {
+	r, ok := e.rules[method]
+	return r, ok
+}
+
+// Authorize проверяет доступ пользователя identity к методу.
+// Ошибка оборачивает [ErrMethodNotAllowed], [ErrRoleRequired] или [ErrScopeRequired].
+func (e *Engine) Authorize(method string, identity entity.Identity) error {
+	r, ok := e.rules[method]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrMethodNotAllowed, method)
+	}
+	if r.Public {
+		return nil
+	}
+
+	if len(r.Roles) > 0 && !slices.Contains(r.Roles, identity.Role) {
+		return fmt.Errorf("%w: %s requires one of %v", ErrRoleRequired, method, r.Roles)
+	}
+	for _, s := range r.Scopes {
+		if !slices.Contains(identity.Scopes, s) {
+			return fmt.Errorf("%w: %s requires %q", ErrScopeRequired, method, s)
+		}
+	}
+	return nil
+}
+
+// New конструктор.
+func New(rules map[string]Rule) *Engine {
+	return &Engine{
+		rules: rules,
+	}
+}
@@ -0,0 +1,43 @@
+package policy
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ktigay/goph-keeper/internal/entity"
+)
+
+func TestEngine_Authorize(t *testing.T) {
+	engine := New(map[string]Rule{
+		"public": {Public: true},
+		"read":   {Scopes: []entity.Scope{entity.ScopeDataRead}},
+		"write":  {Scopes: []entity.Scope{entity.ScopeDataRead, entity.ScopeDataWrite}},
+		"admin":  {Roles: []entity.Role{entity.RoleAdmin}, Scopes: []entity.Scope{entity.ScopeAccount}},
+	})
+	session := entity.Identity{UUID: "user", Role: entity.RoleUser, Scopes: entity.SessionScopes()}
+	readOnly := entity.Identity{UUID: "user", Scopes: []entity.Scope{entity.ScopeDataRead}}
+	admin := entity.Identity{UUID: "admin", Role: entity.RoleAdmin, Scopes: entity.SessionScopes()}
+
+	tests := []struct {
+		name     string
+		method   string
+		identity entity.Identity
+		wantErr  error
+	}{
+		{name: "Public_Anonymous", method: "public"},
+		{name: "Unknown_Method", method: "unknown", identity: admin, wantErr: ErrMethodNotAllowed},
+		{name: "Session_Write", method: "write", identity: session},
+		{name: "ReadOnly_Read", method: "read", identity: readOnly},
+		{name: "ReadOnly_Write", method: "write", identity: readOnly, wantErr: ErrScopeRequired},
+		{name: "User_Admin_Method", method: "admin", identity: session, wantErr: ErrRoleRequired},
+		{name: "Admin_Admin_Method", method: "admin", identity: admin},
+		{name: "Admin_Without_Scope", method: "admin", identity: entity.Identity{Role: entity.RoleAdmin}, wantErr: ErrScopeRequired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := engine.Authorize(tt.method, tt.identity); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Authorize() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
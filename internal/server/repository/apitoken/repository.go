@@ -0,0 +1,146 @@
+package apitoken
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ktigay/goph-keeper/internal/server/db"
+	"github.com/ktigay/goph-keeper/internal/server/entity"
+)
+
+const fields = `"uuid", "user_uuid", "name", "token_hash", "scope", "expires_at", "last_used_at", "revoked_at", "created_at"`
+
+var (
+	insertQuery = `
+		INSERT INTO "api_token" ("user_uuid", "name", "token_hash", "scope", "expires_at")
+			VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + fields
+
+	selectByHashQuery = `
+		SELECT ` + fields + `
+		FROM "api_token"
+		WHERE "token_hash" = $1
+	`
+
+	selectActiveByUserQuery = `
+		SELECT ` + fields + `
+		FROM "api_token"
+		WHERE "user_uuid" = $1 AND "revoked_at" IS NULL AND "expires_at" > NOW()
+		ORDER BY "created_at" DESC
+	`
+
+	revokeByUserQuery = `
+		UPDATE "api_token"
+		SET "revoked_at" = NOW()
+		WHERE "uuid" = $1 AND "user_uuid" = $2 AND "revoked_at" IS NULL`
+
+	touchQuery = `
+		UPDATE "api_token"
+		SET "last_used_at" = NOW()
+		WHERE "uuid" = $1`
+)
+
+// Repository репозиторий токенов доступа.
+type Repository struct {
+	db     db.ConnWrapper
+	logger *slog.Logger
+}
+
+// Create создает токен.
+func (r *Repository) Create(ctx context.Context, t entity.APIToken) (*entity.APIToken, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	return r.queryRowOrNil(c, insertQuery, t.UserUUID, t.Name, t.Hash, t.Scope, t.ExpiresAt)
+}
+
+// ReadByHash возвращает токен по хешу или nil, если его нет.
+func (r *Repository) ReadByHash(ctx context.Context, hash []byte) (*entity.APIToken, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	return r.queryRowOrNil(c, selectByHashQuery, hash)
+}
+
+// ListActive возвращает действующие токены пользователя.
+func (r *Repository) ListActive(ctx context.Context, userUUID string) ([]entity.APIToken, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	rows, err := r.db.Connection(ctx).Query(c, selectActiveByUserQuery, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := make([]entity.APIToken, 0)
+	for rows.Next() {
+		var t entity.APIToken
+		if err = r.fullScan(rows, &t); err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, t)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return tokens, nil
+}
+
+// RevokeByUser отзывает токен пользователя. Возвращает false, если действующего токена нет.
+func (r *Repository) RevokeByUser(ctx context.Context, uuid, userUUID string) (bool, error) {
+	return r.exec(ctx, revokeByUserQuery, uuid, userUUID)
+}
+
+// Touch обновляет время последнего использования токена.
+func (r *Repository) Touch(ctx context.Context, uuid string) error {
+	_, err := r.exec(ctx, touchQuery, uuid)
+	return err
+}
+
+func (r *Repository) exec(ctx context.Context, query string, args ...any) (bool, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	tag, err := r.db.Connection(ctx).Exec(c, query, args...)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *Repository) queryRowOrNil(ctx context.Context, query string, args ...any) (*entity.APIToken, error) {
+	var t entity.APIToken
+	if err := r.fullScan(r.db.Connection(ctx).QueryRow(ctx, query, args...), &t); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *Repository) fullScan(row pgx.Row, t *entity.APIToken) error {
+	return row.Scan(
+		&t.UUID,
+		&t.UserUUID,
+		&t.Name,
+		&t.Hash,
+		&t.Scope,
+		&t.ExpiresAt,
+		&t.LastUsedAt,
+		&t.RevokedAt,
+		&t.CreatedAt,
+	)
+}
+
+// New Конструктор.
+func New(db db.ConnWrapper, logger *slog.Logger) *Repository {
+	return &Repository{
+		db:     db,
+		logger: logger,
+	}
+}
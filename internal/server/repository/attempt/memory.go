@@ -0,0 +1,102 @@
+package attempt
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ktigay/goph-keeper/internal/server/entity"
+)
+
+const (
+	// sweepEvery через сколько вызовов Fail удаляются устаревшие записи.
+	sweepEvery = 1024
+	// sweepAfter через сколько после последней неудачи запись считается устаревшей,
+	// не меньше максимального ResetAfter политик.
+	sweepAfter = 24 * time.Hour
+)
+
+// MemoryRepository хранилище попыток входа в памяти, для одного экземпляра сервера.
+type MemoryRepository struct {
+	mu       sync.Mutex
+	attempts map[string]*entity.LoginAttempt
+	calls    int
+}
+
+// Read возвращает попытки по ключу или nil.
+func (r *MemoryRepository) Read(_ context.Context, key string) (*entity.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	a, ok := r.attempts[key]
+	if !ok {
+		return nil, nil
+	}
+	cp := *a
+	return &cp, nil
+}
+
+// Fail увеличивает счетчик неудач.
+func (r *MemoryRepository) Fail(_ context.Context, key string, now, resetBefore time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.calls++
+	if r.calls%sweepEvery == 0 {
+		r.sweep(now)
+	}
+
+	a, ok := r.attempts[key]
+	if !ok {
+		a = &entity.LoginAttempt{Key: key}
+		r.attempts[key] = a
+	}
+	if a.UpdatedAt.Before(resetBefore) {
+		a.Failures = 0
+	}
+	a.Failures++
+	a.UpdatedAt = now
+
+	return a.Failures, nil
+}
+
+// Lock блокирует ключ до until, более долгая блокировка не сокращается.
+func (r *MemoryRepository) Lock(_ context.Context, key string, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	a, ok := r.attempts[key]
+	if !ok {
+		return nil
+	}
+	if a.LockedUntil == nil || a.LockedUntil.Before(until) {
+		a.LockedUntil = &until
+	}
+	return nil
+}
+
+// Reset удаляет попытки по ключу.
+func (r *MemoryRepository) Reset(_ context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.attempts, key)
+	return nil
+}
+
+// sweep удаляет записи без активной блокировки, по которым давно не было неудач.
+func (r *MemoryRepository) sweep(now time.Time) {
+	staleBefore := now.Add(-sweepAfter)
+	for k, a := range r.attempts {
+		if a.UpdatedAt.Before(staleBefore) && (a.LockedUntil == nil || a.LockedUntil.Before(now)) {
+			delete(r.attempts, k)
+		}
+	}
+}
+
+// NewMemory конструктор.
+func NewMemory() *MemoryRepository {
+	return &MemoryRepository{
+		attempts: make(map[string]*entity.LoginAttempt),
+	}
+}
@@ -0,0 +1,104 @@
+package attempt
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ktigay/goph-keeper/internal/server/db"
+	"github.com/ktigay/goph-keeper/internal/server/entity"
+)
+
+var (
+	selectQuery = `
+		SELECT "key", "failures", "locked_until", "updated_at"
+		FROM "login_attempt"
+		WHERE "key" = $1
+	`
+
+	failQuery = `
+		INSERT INTO "login_attempt" ("key", "failures", "updated_at")
+			VALUES ($1, 1, $2)
+		ON CONFLICT ("key") DO UPDATE
+			SET "failures" = CASE
+					WHEN "login_attempt"."updated_at" < $3 THEN 1
+					ELSE "login_attempt"."failures" + 1
+				END,
+				"updated_at" = EXCLUDED."updated_at"
+		RETURNING "failures"`
+
+	lockQuery = `
+		UPDATE "login_attempt"
+		SET "locked_until" = GREATEST(COALESCE("locked_until", $2), $2)
+		WHERE "key" = $1`
+
+	deleteQuery = `DELETE FROM "login_attempt" WHERE "key" = $1`
+)
+
+// Repository хранилище попыток входа в postgres, общее для нескольких экземпляров сервера.
+type Repository struct {
+	db     db.ConnWrapper
+	logger *slog.Logger
+}
+
+// Read возвращает попытки по ключу или nil.
+func (r *Repository) Read(ctx context.Context, key string) (*entity.LoginAttempt, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	var a entity.LoginAttempt
+	err := r.db.Connection(ctx).QueryRow(c, selectQuery, key).Scan(
+		&a.Key,
+		&a.Failures,
+		&a.LockedUntil,
+		&a.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &a, nil
+}
+
+// Fail увеличивает счетчик неудач.
+func (r *Repository) Fail(ctx context.Context, key string, now, resetBefore time.Time) (int, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	var failures int
+	if err := r.db.Connection(ctx).QueryRow(c, failQuery, key, now, resetBefore).Scan(&failures); err != nil {
+		return 0, err
+	}
+	return failures, nil
+}
+
+// Lock блокирует ключ до until, более долгая блокировка не сокращается.
+func (r *Repository) Lock(ctx context.Context, key string, until time.Time) error {
+	return r.exec(ctx, lockQuery, key, until)
+}
+
+// Reset удаляет попытки по ключу.
+func (r *Repository) Reset(ctx context.Context, key string) error {
+	return r.exec(ctx, deleteQuery, key)
+}
+
+func (r *Repository) exec(ctx context.Context, query string, args ...any) error {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	_, err := r.db.Connection(ctx).Exec(c, query, args...)
+	return err
+}
+
+// New Конструктор.
+func New(db db.ConnWrapper, logger *slog.Logger) *Repository {
+	return &Repository{
+		db:     db,
+		logger: logger,
+	}
+}
@@ -0,0 +1,36 @@
+package audit
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/ktigay/goph-keeper/internal/server/db"
+	"github.com/ktigay/goph-keeper/internal/server/entity"
+)
+
+var insertQuery = `
+	INSERT INTO "account_audit" ("user_uuid", "event", "ip")
+		VALUES ($1, $2, $3)`
+
+// Repository репозиторий аудита аккаунтов.
+type Repository struct {
+	db     db.ConnWrapper
+	logger *slog.Logger
+}
+
+// Create сохраняет запись аудита.
+func (r *Repository) Create(ctx context.Context, rec entity.AuditRecord) error {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	_, err := r.db.Connection(ctx).Exec(c, insertQuery, rec.UserUUID, rec.Event, rec.IP)
+	return err
+}
+
+// New Конструктор.
+func New(db db.ConnWrapper, logger *slog.Logger) *Repository {
+	return &Repository{
+		db:     db,
+		logger: logger,
+	}
+}
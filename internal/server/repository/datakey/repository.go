@@ -0,0 +1,125 @@
+package datakey
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ktigay/goph-keeper/internal/entity"
+	"github.com/ktigay/goph-keeper/internal/server/db"
+)
+
+var (
+	insertQuery = `
+		INSERT INTO "user_data_key" ("user_uuid", "kek_id", "wrapped_key")
+			VALUES ($1, $2, $3)
+		ON CONFLICT ("user_uuid") DO NOTHING
+		RETURNING "user_uuid", "kek_id", "wrapped_key"`
+
+	selectByUserQuery = `
+		SELECT "user_uuid", "kek_id", "wrapped_key"
+		FROM "user_data_key"
+		WHERE "user_uuid" = $1
+	`
+
+	selectByOtherKEKQuery = `
+		SELECT "user_uuid", "kek_id", "wrapped_key"
+		FROM "user_data_key"
+		WHERE "kek_id" <> $1
+		ORDER BY "user_uuid"
+		LIMIT $2
+	`
+
+	updateWrappedQuery = `
+		UPDATE "user_data_key"
+		SET "kek_id" = $1, "wrapped_key" = $2, "updated_at" = NOW()
+		WHERE "user_uuid" = $3 AND "kek_id" = $4`
+)
+
+// Repository репозиторий ключей данных.
+type Repository struct {
+	db     db.ConnWrapper
+	logger *slog.Logger
+}
+
+// Create сохраняет ключ данных. Возвращает nil, если ключ уже существует.
+func (r *Repository) Create(ctx context.Context, key entity.DataKey) (*entity.DataKey, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	return r.queryRowOrNil(c, insertQuery, key.UserUUID, key.KEKID, key.WrappedKey)
+}
+
+// Read возвращает ключ данных пользователя.
+func (r *Repository) Read(ctx context.Context, userUUID string) (*entity.DataKey, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	return r.queryRowOrNil(c, selectByUserQuery, userUUID)
+}
+
+// ReadByOtherKEK возвращает ключи данных, зашифрованные не мастер-ключом kekID.
+func (r *Repository) ReadByOtherKEK(ctx context.Context, kekID string, limit int) ([]entity.DataKey, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	rows, err := r.db.Connection(ctx).Query(c, selectByOtherKEKQuery, kekID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := make([]entity.DataKey, 0)
+	for rows.Next() {
+		var k entity.DataKey
+		if err = r.fullScan(rows, &k); err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return keys, nil
+}
+
+// UpdateWrapped сохраняет перешифрованный ключ данных, если он всё ещё зашифрован мастер-ключом oldKEKID.
+func (r *Repository) UpdateWrapped(ctx context.Context, key entity.DataKey, oldKEKID string) (bool, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	tag, err := r.db.Connection(ctx).Exec(c, updateWrappedQuery, key.KEKID, key.WrappedKey, key.UserUUID, oldKEKID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *Repository) queryRowOrNil(ctx context.Context, query string, args ...any) (*entity.DataKey, error) {
+	var k entity.DataKey
+	if err := r.fullScan(r.db.Connection(ctx).QueryRow(ctx, query, args...), &k); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &k, nil
+}
+
+func (r *Repository) fullScan(row pgx.Row, k *entity.DataKey) error {
+	return row.Scan(
+		&k.UserUUID,
+		&k.KEKID,
+		&k.WrappedKey,
+	)
+}
+
+// New Конструктор.
+func New(db db.ConnWrapper, logger *slog.Logger) *Repository {
+	return &Repository{
+		db:     db,
+		logger: logger,
+	}
+}
@@ -0,0 +1,129 @@
+package secondfactor
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ktigay/goph-keeper/internal/server/db"
+	"github.com/ktigay/goph-keeper/internal/server/entity"
+)
+
+var (
+	upsertQuery = `
+		INSERT INTO "user_second_factor" ("user_uuid", "secret")
+			VALUES ($1, $2)
+		ON CONFLICT ("user_uuid") DO UPDATE
+			SET "secret" = EXCLUDED."secret", "enabled" = FALSE, "last_used_step" = 0, "updated_at" = NOW()
+		WHERE NOT "user_second_factor"."enabled"
+		RETURNING "user_uuid", "secret", "enabled", "last_used_step"`
+
+	selectQuery = `
+		SELECT "user_uuid", "secret", "enabled", "last_used_step"
+		FROM "user_second_factor"
+		WHERE "user_uuid" = $1
+	`
+
+	enableQuery = `
+		UPDATE "user_second_factor"
+		SET "enabled" = TRUE, "last_used_step" = $2, "updated_at" = NOW()
+		WHERE "user_uuid" = $1 AND NOT "enabled"`
+
+	useStepQuery = `
+		UPDATE "user_second_factor"
+		SET "last_used_step" = $2, "updated_at" = NOW()
+		WHERE "user_uuid" = $1 AND "last_used_step" < $2`
+
+	replaceRecoveryCodesQuery = `
+		WITH deleted AS (
+			DELETE FROM "user_recovery_code" WHERE "user_uuid" = $1
+		)
+		INSERT INTO "user_recovery_code" ("user_uuid", "code_hash")
+		SELECT $1, h FROM UNNEST($2::BYTEA[]) AS h`
+
+	useRecoveryCodeQuery = `
+		UPDATE "user_recovery_code"
+		SET "used_at" = NOW()
+		WHERE "user_uuid" = $1 AND "code_hash" = $2 AND "used_at" IS NULL`
+)
+
+// Repository репозиторий второго фактора.
+type Repository struct {
+	db     db.ConnWrapper
+	logger *slog.Logger
+}
+
+// Save сохраняет новый (не подтвержденный) секрет. Возвращает nil, если второй фактор уже включен.
+func (r *Repository) Save(ctx context.Context, sf entity.SecondFactor) (*entity.SecondFactor, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	return r.queryRowOrNil(c, upsertQuery, sf.UserUUID, sf.Secret)
+}
+
+// Read возвращает второй фактор пользователя или nil.
+func (r *Repository) Read(ctx context.Context, userUUID string) (*entity.SecondFactor, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	return r.queryRowOrNil(c, selectQuery, userUUID)
+}
+
+// Enable включает второй фактор, step - интервал TOTP кода подтверждения.
+func (r *Repository) Enable(ctx context.Context, userUUID string, step int64) (bool, error) {
+	return r.exec(ctx, enableQuery, userUUID, step)
+}
+
+// UseStep принимает интервал TOTP, если он больше последнего принятого.
+func (r *Repository) UseStep(ctx context.Context, userUUID string, step int64) (bool, error) {
+	return r.exec(ctx, useStepQuery, userUUID, step)
+}
+
+// ReplaceRecoveryCodes заменяет коды восстановления пользователя.
+func (r *Repository) ReplaceRecoveryCodes(ctx context.Context, userUUID string, hashes [][]byte) error {
+	_, err := r.exec(ctx, replaceRecoveryCodesQuery, userUUID, hashes)
+	return err
+}
+
+// UseRecoveryCode помечает код восстановления использованным. Возвращает false, если код не найден или уже использован.
+func (r *Repository) UseRecoveryCode(ctx context.Context, userUUID string, hash []byte) (bool, error) {
+	return r.exec(ctx, useRecoveryCodeQuery, userUUID, hash)
+}
+
+func (r *Repository) exec(ctx context.Context, query string, args ...any) (bool, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	tag, err := r.db.Connection(ctx).Exec(c, query, args...)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() >= 1, nil
+}
+
+func (r *Repository) queryRowOrNil(ctx context.Context, query string, args ...any) (*entity.SecondFactor, error) {
+	var sf entity.SecondFactor
+	err := r.db.Connection(ctx).QueryRow(ctx, query, args...).Scan(
+		&sf.UserUUID,
+		&sf.Secret,
+		&sf.Enabled,
+		&sf.LastUsedStep,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &sf, nil
+}
+
+// New Конструктор.
+func New(db db.ConnWrapper, logger *slog.Logger) *Repository {
+	return &Repository{
+		db:     db,
+		logger: logger,
+	}
+}
@@ -0,0 +1,220 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ktigay/goph-keeper/internal/server/db"
+	"github.com/ktigay/goph-keeper/internal/server/entity"
+)
+
+const fields = `"uuid", "user_uuid", "refresh_hash", "previous_refresh_hash", "device_name", "client_version", "ip",
+	"expires_at", "revoked_at", "last_seen_at", "created_at", "updated_at",
+	(SELECT u."role" FROM "user" u WHERE u."uuid" = "session"."user_uuid")`
+
+var (
+	insertQuery = `
+		INSERT INTO "session" ("user_uuid", "refresh_hash", "device_name", "client_version", "ip", "expires_at")
+			VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + fields
+
+	selectByRefreshHashQuery = `
+		SELECT ` + fields + `
+		FROM "session"
+		WHERE "refresh_hash" = $1 OR "previous_refresh_hash" = $1
+		LIMIT 1
+	`
+
+	rotateQuery = `
+		UPDATE "session"
+		SET "previous_refresh_hash" = "refresh_hash", "refresh_hash" = $1, "expires_at" = $2, "updated_at" = NOW()
+		WHERE "uuid" = $3 AND "refresh_hash" = $4 AND "revoked_at" IS NULL
+		RETURNING ` + fields
+
+	revokeQuery = `
+		UPDATE "session"
+		SET "revoked_at" = NOW(), "updated_at" = NOW()
+		WHERE "uuid" = $1 AND "revoked_at" IS NULL`
+
+	selectActiveByUserQuery = `
+		SELECT ` + fields + `
+		FROM "session"
+		WHERE "user_uuid" = $1 AND "revoked_at" IS NULL AND "expires_at" > NOW()
+		ORDER BY "last_seen_at" DESC
+	`
+
+	revokeByUserQuery = `
+		UPDATE "session"
+		SET "revoked_at" = NOW(), "updated_at" = NOW()
+		WHERE "uuid" = $1 AND "user_uuid" = $2 AND "revoked_at" IS NULL`
+
+	revokeAllExceptQuery = `
+		UPDATE "session"
+		SET "revoked_at" = NOW(), "updated_at" = NOW()
+		WHERE "user_uuid" = $1 AND "uuid" <> $2 AND "revoked_at" IS NULL`
+
+	revokeAllQuery = `
+		UPDATE "session"
+		SET "revoked_at" = NOW(), "updated_at" = NOW()
+		WHERE "user_uuid" = $1 AND "revoked_at" IS NULL`
+
+	touchQuery = `
+		UPDATE "session"
+		SET "last_seen_at" = NOW(), "ip" = $2
+		WHERE "uuid" = $1 AND "revoked_at" IS NULL`
+)
+
+// Repository репозиторий сессий.
+type Repository struct {
+	db     db.ConnWrapper
+	logger *slog.Logger
+}
+
+// Create создает сессию.
+func (r *Repository) Create(ctx context.Context, s entity.Session) (*entity.Session, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	return r.queryRowOrNil(c, insertQuery, s.UserUUID, s.RefreshHash, s.DeviceName, s.ClientVersion, s.IP, s.ExpiresAt)
+}
+
+// ReadByRefreshHash возвращает сессию по хешу действующего или предыдущего refresh токена.
+func (r *Repository) ReadByRefreshHash(ctx context.Context, hash []byte) (*entity.Session, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	return r.queryRowOrNil(c, selectByRefreshHashQuery, hash)
+}
+
+// Rotate заменяет refresh токен сессии, если действующий токен всё ещё oldHash.
+// Возвращает nil, если токен уже заменен или сессия отозвана.
+func (r *Repository) Rotate(ctx context.Context, uuid string, oldHash, newHash []byte, expiresAt time.Time) (*entity.Session, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	return r.queryRowOrNil(c, rotateQuery, newHash, expiresAt, uuid, oldHash)
+}
+
+// Revoke отзывает сессию.
+func (r *Repository) Revoke(ctx context.Context, uuid string) error {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	_, err := r.db.Connection(ctx).Exec(c, revokeQuery, uuid)
+	return err
+}
+
+// ListActive возвращает действующие сессии пользователя.
+func (r *Repository) ListActive(ctx context.Context, userUUID string) ([]entity.Session, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	rows, err := r.db.Connection(ctx).Query(c, selectActiveByUserQuery, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := make([]entity.Session, 0)
+	for rows.Next() {
+		var s entity.Session
+		if err = r.fullScan(rows, &s); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, s)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return sessions, nil
+}
+
+// RevokeByUser отзывает сессию пользователя. Возвращает false, если действующей сессии нет.
+func (r *Repository) RevokeByUser(ctx context.Context, uuid, userUUID string) (bool, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	tag, err := r.db.Connection(ctx).Exec(c, revokeByUserQuery, uuid, userUUID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// RevokeAllExcept отзывает все сессии пользователя, кроме keepUUID. Возвращает количество отозванных.
+func (r *Repository) RevokeAllExcept(ctx context.Context, userUUID, keepUUID string) (int64, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	tag, err := r.db.Connection(ctx).Exec(c, revokeAllExceptQuery, userUUID, keepUUID)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// RevokeAll отзывает все сессии пользователя. Возвращает количество отозванных.
+func (r *Repository) RevokeAll(ctx context.Context, userUUID string) (int64, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	tag, err := r.db.Connection(ctx).Exec(c, revokeAllQuery, userUUID)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// Touch обновляет время последней активности и IP сессии.
+// Возвращает false, если сессия не найдена или отозвана.
+func (r *Repository) Touch(ctx context.Context, uuid, ip string) (bool, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	tag, err := r.db.Connection(ctx).Exec(c, touchQuery, uuid, ip)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *Repository) queryRowOrNil(ctx context.Context, query string, args ...any) (*entity.Session, error) {
+	var s entity.Session
+	if err := r.fullScan(r.db.Connection(ctx).QueryRow(ctx, query, args...), &s); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *Repository) fullScan(row pgx.Row, s *entity.Session) error {
+	return row.Scan(
+		&s.UUID,
+		&s.UserUUID,
+		&s.RefreshHash,
+		&s.PreviousRefreshHash,
+		&s.DeviceName,
+		&s.ClientVersion,
+		&s.IP,
+		&s.ExpiresAt,
+		&s.RevokedAt,
+		&s.LastSeenAt,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+		&s.Role,
+	)
+}
+
+// New Конструктор.
+func New(db db.ConnWrapper, logger *slog.Logger) *Repository {
+	return &Repository{
+		db:     db,
+		logger: logger,
+	}
+}
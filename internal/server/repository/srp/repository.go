@@ -0,0 +1,90 @@
+package srp
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ktigay/goph-keeper/internal/server/db"
+	"github.com/ktigay/goph-keeper/internal/server/entity"
+)
+
+var (
+	insertQuery = `
+		INSERT INTO "srp_handshake" ("user_uuid", "login", "proof_hash", "server_proof", "expires_at")
+			VALUES (NULLIF($1, '')::UUID, $2, $3, $4, $5)
+		RETURNING "id"`
+
+	// takeQuery удаляет обмен при чтении: доказательство можно предъявить только один раз.
+	takeQuery = `
+		DELETE FROM "srp_handshake"
+		WHERE "id" = $1
+		RETURNING "id", COALESCE("user_uuid"::TEXT, ''), "login", "proof_hash", "server_proof", "expires_at"`
+
+	deleteExpiredQuery = `DELETE FROM "srp_handshake" WHERE "expires_at" < $1`
+)
+
+// Repository репозиторий незавершенных обменов SRP.
+type Repository struct {
+	db     db.ConnWrapper
+	logger *slog.Logger
+}
+
+// Create сохраняет обмен, возвращает его идентификатор.
+func (r *Repository) Create(ctx context.Context, h entity.SRPHandshake) (string, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	var id string
+	err := r.db.Connection(ctx).QueryRow(c, insertQuery, h.UserUUID, h.Login, h.ProofHash, h.ServerProof, h.ExpiresAt).Scan(&id)
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// Take возвращает и удаляет обмен или nil, если его нет.
+func (r *Repository) Take(ctx context.Context, id string) (*entity.SRPHandshake, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	var h entity.SRPHandshake
+	err := r.db.Connection(ctx).QueryRow(c, takeQuery, id).Scan(
+		&h.ID,
+		&h.UserUUID,
+		&h.Login,
+		&h.ProofHash,
+		&h.ServerProof,
+		&h.ExpiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &h, nil
+}
+
+// DeleteExpired удаляет просроченные обмены. Возвращает количество удаленных.
+func (r *Repository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	tag, err := r.db.Connection(ctx).Exec(c, deleteExpiredQuery, now)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// New Конструктор.
+func New(db db.ConnWrapper, logger *slog.Logger) *Repository {
+	return &Repository{
+		db:     db,
+		logger: logger,
+	}
+}
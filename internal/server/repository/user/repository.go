@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 
@@ -14,14 +15,90 @@ import (
 var (
 	insertQuery = `
 		INSERT INTO "user" ("login", "password")
-			VALUES ($1, $2) 
-		RETURNING "uuid", "login", "password", "created_at", "updated_at"`
+			VALUES ($1, $2)
+		ON CONFLICT ON CONSTRAINT "login_idx" DO NOTHING
+		RETURNING "uuid", "login", "password", "srp_salt", "srp_verifier", "delete_after", "created_at", "updated_at"`
+
+	insertWithVerifierQuery = `
+		INSERT INTO "user" ("login", "password", "srp_salt", "srp_verifier")
+			VALUES ($1, '', $2, $3)
+		ON CONFLICT ON CONSTRAINT "login_idx" DO NOTHING
+		RETURNING "uuid", "login", "password", "srp_salt", "srp_verifier", "delete_after", "created_at", "updated_at"`
 
 	selectByLoginQuery = `
-		SELECT "uuid", "login", "password", "created_at", "updated_at"
+		SELECT "uuid", "login", "password", "srp_salt", "srp_verifier", "delete_after", "created_at", "updated_at"
 		FROM "user"
 		WHERE "login" = $1
 	`
+
+	selectByUUIDQuery = `
+		SELECT "uuid", "login", "password", "srp_salt", "srp_verifier", "delete_after", "created_at", "updated_at"
+		FROM "user"
+		WHERE "uuid" = $1
+	`
+
+	// updateVerifierQuery сохраняет верификатор SRP, хеш пароля при этом удаляется.
+	updateVerifierQuery = `
+		UPDATE "user"
+		SET "password" = '', "srp_salt" = $2, "srp_verifier" = $3, "updated_at" = NOW()
+		WHERE "uuid" = $1`
+
+	// updatePasswordHashQuery заменяет хеш пароля, только если он не изменился с момента чтения.
+	updatePasswordHashQuery = `
+		UPDATE "user"
+		SET "password" = $3, "updated_at" = NOW()
+		WHERE "uuid" = $1 AND "password" = $2 AND "password" <> ''`
+
+	scheduleDeletionQuery = `
+		UPDATE "user"
+		SET "delete_after" = $2, "updated_at" = NOW()
+		WHERE "uuid" = $1`
+
+	cancelDeletionQuery = `
+		UPDATE "user"
+		SET "delete_after" = NULL, "updated_at" = NOW()
+		WHERE "uuid" = $1 AND "delete_after" IS NOT NULL`
+
+	selectDueForDeletionQuery = `
+		SELECT "uuid"
+		FROM "user"
+		WHERE "delete_after" IS NOT NULL AND "delete_after" <= $1
+		ORDER BY "delete_after"
+		LIMIT $2`
+
+	// purgeQuery удаляет пользователя и все его данные одним запросом,
+	// только если удаление всё ещё запрошено и срок наступил.
+	purgeQuery = `
+		WITH u AS (
+			DELETE FROM "user"
+			WHERE "uuid" = $1 AND "delete_after" IS NOT NULL AND "delete_after" <= $2
+			RETURNING "uuid"
+		), d AS (
+			DELETE FROM "user_data" WHERE "user_uuid" IN (SELECT "uuid" FROM u)
+		), dh AS (
+			DELETE FROM "user_data_history" WHERE "user_uuid" IN (SELECT "uuid" FROM u)
+		), dt AS (
+			DELETE FROM "user_data_tombstone" WHERE "user_uuid" IN (SELECT "uuid" FROM u)
+		), db AS (
+			DELETE FROM "user_data_batch" WHERE "user_uuid" IN (SELECT "uuid" FROM u)
+		), vk AS (
+			DELETE FROM "vault_key" WHERE "user_uuid" IN (SELECT "uuid" FROM u)
+		), vkr AS (
+			DELETE FROM "vault_key_rotation" WHERE "user_uuid" IN (SELECT "uuid" FROM u)
+		), dk AS (
+			DELETE FROM "user_data_key" WHERE "user_uuid" IN (SELECT "uuid" FROM u)
+		), s AS (
+			DELETE FROM "session" WHERE "user_uuid" IN (SELECT "uuid" FROM u)
+		), sf AS (
+			DELETE FROM "user_second_factor" WHERE "user_uuid" IN (SELECT "uuid" FROM u)
+		), rc AS (
+			DELETE FROM "user_recovery_code" WHERE "user_uuid" IN (SELECT "uuid" FROM u)
+		), h AS (
+			DELETE FROM "srp_handshake" WHERE "user_uuid" IN (SELECT "uuid" FROM u)
+		), t AS (
+			DELETE FROM "api_token" WHERE "user_uuid" IN (SELECT "uuid" FROM u)
+		)
+		SELECT COUNT(*) FROM u`
 )
 
 // Repository репозиторий.
@@ -30,12 +107,34 @@ type Repository struct {
 	logger *slog.Logger
 }
 
-// Create создаёт пользователя.
+// Create создаёт пользователя. Возвращает nil, если логин уже занят.
 func (r *Repository) Create(ctx context.Context, login, password string) (*entity.User, error) {
 	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
 	defer cancel()
 
-	return r.queryRow(c, insertQuery, login, password)
+	e, err := r.queryRow(c, insertQuery, login, password)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return e, nil
+}
+
+// CreateWithVerifier создаёт пользователя с верификатором SRP вместо пароля. Возвращает nil, если логин уже занят.
+func (r *Repository) CreateWithVerifier(ctx context.Context, login string, salt, verifier []byte) (*entity.User, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	e, err := r.queryRow(c, insertWithVerifierQuery, login, salt, verifier)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return e, nil
 }
 
 // Read возвращает пользователя.
@@ -53,6 +152,87 @@ func (r *Repository) Read(ctx context.Context, login string) (*entity.User, erro
 	return e, nil
 }
 
+// ReadByUUID возвращает пользователя по идентификатору.
+func (r *Repository) ReadByUUID(ctx context.Context, uuid string) (*entity.User, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	e, err := r.queryRow(c, selectByUUIDQuery, uuid)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return e, nil
+}
+
+// UpdateVerifier сохраняет верификатор SRP и удаляет хеш пароля. Возвращает false, если пользователь не найден.
+func (r *Repository) UpdateVerifier(ctx context.Context, uuid string, salt, verifier []byte) (bool, error) {
+	return r.exec(ctx, updateVerifierQuery, uuid, salt, verifier)
+}
+
+// UpdatePasswordHash заменяет хеш пароля old на hash. Возвращает false, если хеш уже изменился.
+func (r *Repository) UpdatePasswordHash(ctx context.Context, uuid, old, hash string) (bool, error) {
+	return r.exec(ctx, updatePasswordHashQuery, uuid, old, hash)
+}
+
+// ScheduleDeletion назначает удаление аккаунта на время at.
+func (r *Repository) ScheduleDeletion(ctx context.Context, uuid string, at time.Time) (bool, error) {
+	return r.exec(ctx, scheduleDeletionQuery, uuid, at)
+}
+
+// CancelDeletion отменяет удаление аккаунта. Возвращает false, если удаление не было запрошено.
+func (r *Repository) CancelDeletion(ctx context.Context, uuid string) (bool, error) {
+	return r.exec(ctx, cancelDeletionQuery, uuid)
+}
+
+// ListDueForDeletion возвращает идентификаторы пользователей, срок удаления которых наступил.
+func (r *Repository) ListDueForDeletion(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	rows, err := r.db.Connection(ctx).Query(c, selectDueForDeletionQuery, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var uuids []string
+	for rows.Next() {
+		var uuid string
+		if err = rows.Scan(&uuid); err != nil {
+			return nil, err
+		}
+		uuids = append(uuids, uuid)
+	}
+	return uuids, rows.Err()
+}
+
+// Purge удаляет пользователя и все его данные, если срок удаления наступил.
+// Возвращает false, если удаление отменено или ещё не наступило.
+func (r *Repository) Purge(ctx context.Context, uuid string, now time.Time) (bool, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	var users int64
+	if err := r.db.Connection(ctx).QueryRow(c, purgeQuery, uuid, now).Scan(&users); err != nil {
+		return false, err
+	}
+	return users > 0, nil
+}
+
+func (r *Repository) exec(ctx context.Context, query string, args ...any) (bool, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	tag, err := r.db.Connection(ctx).Exec(c, query, args...)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() >= 1, nil
+}
+
 func (r *Repository) queryRow(ctx context.Context, query string, args ...any) (*entity.User, error) {
 	var (
 		u   entity.User
@@ -73,8 +253,11 @@ func (r *Repository) fullScan(row pgx.Row, ud *entity.User) error {
 		&ud.UUID,
 		&ud.Login,
 		&ud.Password,
-		&ud.UpdatedAt,
+		&ud.SRPSalt,
+		&ud.SRPVerifier,
+		&ud.DeleteAfter,
 		&ud.CreatedAt,
+		&ud.UpdatedAt,
 	)
 }
 
@@ -0,0 +1,261 @@
+package userdata
+
+import (
+	"context"
+	"crypto/cipher"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ktigay/goph-keeper/internal/entity"
+	"github.com/ktigay/goph-keeper/internal/server/security/envelope"
+)
+
+// ErrMalformedSealedData некорректные зашифрованные данные.
+var ErrMalformedSealedData = errors.New("malformed sealed user data")
+
+// Storage хранилище пользовательских данных.
+//
+//go:generate mockgen -destination=./mocks/mock_storage.go -package=mocks github.com/ktigay/goph-keeper/internal/server/repository/userdata Storage
+type Storage interface {
+	Create(ctx context.Context, data entity.UserData) (*entity.UserData, error)
+	Update(ctx context.Context, data entity.UserData) (*entity.UserData, error)
+	Delete(ctx context.Context, userUUID string, revision uint64, uuids ...string) error
+	Read(ctx context.Context, userUUID string, uuids ...string) ([]entity.UserData, error)
+	NextRevision(ctx context.Context, userUUID string) (uint64, error)
+	ReadChanges(ctx context.Context, userUUID string, since uint64) (*entity.UserDataChanges, error)
+	Reencrypt(ctx context.Context, data entity.UserData) (bool, error)
+	CountByOtherKeyVersion(ctx context.Context, userUUID string, keyVersion uint32) (int, error)
+	ReadRevisions(ctx context.Context, userUUID, itemUUID string) ([]entity.UserDataRevision, error)
+	ReadRevision(ctx context.Context, userUUID, itemUUID, revisionUUID string) (*entity.UserDataRevision, error)
+	PruneRevisions(ctx context.Context, retention int) (int64, error)
+	PruneTombstones(ctx context.Context, before time.Time) (int64, error)
+	ReadBatch(ctx context.Context, userUUID, batchUUID string) (*entity.UserDataBatchResult, error)
+	SaveBatch(ctx context.Context, userUUID, batchUUID string, res entity.UserDataBatchResult) error
+	PruneBatches(ctx context.Context, before time.Time) (int64, error)
+}
+
+// DataKeys ключи данных пользователей.
+//
+//go:generate mockgen -destination=./mocks/mock_datakeys.go -package=mocks github.com/ktigay/goph-keeper/internal/server/repository/userdata DataKeys
+type DataKeys interface {
+	Get(ctx context.Context, userUUID string) (cipher.AEAD, error)
+}
+
+// EnvelopeRepository декоратор [Storage], шифрующий данные и метаданные записей
+// ключом данных пользователя перед записью в БД.
+type EnvelopeRepository struct {
+	next Storage
+	keys DataKeys
+}
+
+// Create создаёт запись пользовательских данных.
+func (r *EnvelopeRepository) Create(ctx context.Context, data entity.UserData) (*entity.UserData, error) {
+	// UUID участвует в дополнительных данных шифра, поэтому назначается до шифрования.
+	if data.UUID == "" {
+		data.UUID = uuid.New().String()
+	}
+	sealed, err := r.seal(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	d, err := r.next.Create(ctx, *sealed)
+	if err != nil {
+		return nil, err
+	}
+	return r.open(ctx, *d)
+}
+
+// Update обновляет запись пользовательских данных.
+func (r *EnvelopeRepository) Update(ctx context.Context, data entity.UserData) (*entity.UserData, error) {
+	sealed, err := r.seal(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	d, err := r.next.Update(ctx, *sealed)
+	if err != nil || d == nil {
+		return nil, err
+	}
+	return r.open(ctx, *d)
+}
+
+// Delete удаляет запись пользовательских данных.
+func (r *EnvelopeRepository) Delete(ctx context.Context, userUUID string, revision uint64, uuids ...string) error {
+	return r.next.Delete(ctx, userUUID, revision, uuids...)
+}
+
+// Read читает записи пользовательских данных.
+func (r *EnvelopeRepository) Read(ctx context.Context, userUUID string, uuids ...string) ([]entity.UserData, error) {
+	data, err := r.next.Read(ctx, userUUID, uuids...)
+	if err != nil {
+		return nil, err
+	}
+	for i := range data {
+		var d *entity.UserData
+		if d, err = r.open(ctx, data[i]); err != nil {
+			return nil, err
+		}
+		data[i] = *d
+	}
+	return data, nil
+}
+
+// NextRevision увеличивает счётчик ревизий данных пользователя.
+func (r *EnvelopeRepository) NextRevision(ctx context.Context, userUUID string) (uint64, error) {
+	return r.next.NextRevision(ctx, userUUID)
+}
+
+// ReadChanges возвращает изменения данных пользователя после ревизии since.
+func (r *EnvelopeRepository) ReadChanges(ctx context.Context, userUUID string, since uint64) (*entity.UserDataChanges, error) {
+	changes, err := r.next.ReadChanges(ctx, userUUID, since)
+	if err != nil {
+		return nil, err
+	}
+	for i := range changes.Upserts {
+		var d *entity.UserData
+		if d, err = r.open(ctx, changes.Upserts[i]); err != nil {
+			return nil, err
+		}
+		changes.Upserts[i] = *d
+	}
+	return changes, nil
+}
+
+// Reencrypt сохраняет перешифрованную клиентом запись.
+func (r *EnvelopeRepository) Reencrypt(ctx context.Context, data entity.UserData) (bool, error) {
+	sealed, err := r.seal(ctx, data)
+	if err != nil {
+		return false, err
+	}
+	return r.next.Reencrypt(ctx, *sealed)
+}
+
+// CountByOtherKeyVersion возвращает количество записей пользователя, зашифрованных не ключом keyVersion.
+func (r *EnvelopeRepository) CountByOtherKeyVersion(ctx context.Context, userUUID string, keyVersion uint32) (int, error) {
+	return r.next.CountByOtherKeyVersion(ctx, userUUID, keyVersion)
+}
+
+// ReadRevisions возвращает предыдущие версии записи, от новых к старым.
+func (r *EnvelopeRepository) ReadRevisions(ctx context.Context, userUUID, itemUUID string) ([]entity.UserDataRevision, error) {
+	revisions, err := r.next.ReadRevisions(ctx, userUUID, itemUUID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range revisions {
+		var d *entity.UserData
+		if d, err = r.open(ctx, revisions[i].Item); err != nil {
+			return nil, err
+		}
+		revisions[i].Item = *d
+	}
+	return revisions, nil
+}
+
+// ReadRevision возвращает версию записи или nil, если её нет.
+func (r *EnvelopeRepository) ReadRevision(ctx context.Context, userUUID, itemUUID, revisionUUID string) (*entity.UserDataRevision, error) {
+	rev, err := r.next.ReadRevision(ctx, userUUID, itemUUID, revisionUUID)
+	if err != nil || rev == nil {
+		return nil, err
+	}
+	d, err := r.open(ctx, rev.Item)
+	if err != nil {
+		return nil, err
+	}
+	rev.Item = *d
+	return rev, nil
+}
+
+// PruneRevisions удаляет версии сверх хранимого количества.
+func (r *EnvelopeRepository) PruneRevisions(ctx context.Context, retention int) (int64, error) {
+	return r.next.PruneRevisions(ctx, retention)
+}
+
+// PruneTombstones удаляет отметки об удалении записей, сделанные раньше before.
+func (r *EnvelopeRepository) PruneTombstones(ctx context.Context, before time.Time) (int64, error) {
+	return r.next.PruneTombstones(ctx, before)
+}
+
+// ReadBatch возвращает сохраненный результат пакета изменений или nil, если пакет не применялся.
+func (r *EnvelopeRepository) ReadBatch(ctx context.Context, userUUID, batchUUID string) (*entity.UserDataBatchResult, error) {
+	return r.next.ReadBatch(ctx, userUUID, batchUUID)
+}
+
+// SaveBatch сохраняет результат пакета изменений.
+func (r *EnvelopeRepository) SaveBatch(ctx context.Context, userUUID, batchUUID string, res entity.UserDataBatchResult) error {
+	return r.next.SaveBatch(ctx, userUUID, batchUUID, res)
+}
+
+// PruneBatches удаляет результаты пакетов изменений, примененных раньше before.
+func (r *EnvelopeRepository) PruneBatches(ctx context.Context, before time.Time) (int64, error) {
+	return r.next.PruneBatches(ctx, before)
+}
+
+// seal упаковывает метаданные и данные в один шифротекст:
+// длина метаданных (4 байта) | метаданные (JSON) | данные.
+func (r *EnvelopeRepository) seal(ctx context.Context, data entity.UserData) (*entity.UserData, error) {
+	aead, err := r.keys.Get(ctx, data.UserUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	meta, err := json.Marshal(data.MetaData)
+	if err != nil {
+		return nil, err
+	}
+	plain := make([]byte, 4, 4+len(meta)+len(data.Data))
+	binary.BigEndian.PutUint32(plain, uint32(len(meta)))
+	plain = append(append(plain, meta...), data.Data...)
+
+	if data.Data, err = envelope.Seal(aead, plain, associatedData(data)); err != nil {
+		return nil, err
+	}
+	data.MetaData = []entity.MetaData{}
+	data.Sealed = true
+	return &data, nil
+}
+
+func (r *EnvelopeRepository) open(ctx context.Context, data entity.UserData) (*entity.UserData, error) {
+	// Записи, созданные до включения шифрования, возвращаются как есть.
+	if !data.Sealed {
+		return &data, nil
+	}
+
+	aead, err := r.keys.Get(ctx, data.UserUUID)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := envelope.Open(aead, data.Data, associatedData(data))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(plain) < 4 {
+		return nil, ErrMalformedSealedData
+	}
+	n := binary.BigEndian.Uint32(plain)
+	if uint64(n) > uint64(len(plain)-4) {
+		return nil, ErrMalformedSealedData
+	}
+	data.MetaData = nil
+	if err = json.Unmarshal(plain[4:4+n], &data.MetaData); err != nil {
+		return nil, err
+	}
+	data.Data = plain[4+n:]
+	data.Sealed = false
+	return &data, nil
+}
+
+func associatedData(data entity.UserData) []byte {
+	return []byte(data.UserUUID + "|" + data.UUID)
+}
+
+// NewEnvelopeRepository конструктор.
+func NewEnvelopeRepository(next Storage, keys DataKeys) *EnvelopeRepository {
+	return &EnvelopeRepository{
+		next: next,
+		keys: keys,
+	}
+}
@@ -0,0 +1,108 @@
+package userdata
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/ktigay/goph-keeper/internal/entity"
+	"github.com/ktigay/goph-keeper/internal/server/repository/userdata/mocks"
+	"github.com/ktigay/goph-keeper/internal/server/security/envelope"
+)
+
+func TestEnvelopeRepository_CreateRead(t *testing.T) {
+	aead, err := envelope.NewAEAD(bytes.Repeat([]byte{3}, envelope.KeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	userUUID := "0b1f3c3e-4d6a-4b8e-9f7a-2c1d5e6f7a8b"
+	plain := entity.UserData{
+		UserUUID: userUUID,
+		Title:    "title",
+		Type:     entity.DataTypeText,
+		Data:     []byte("secret"),
+		MetaData: []entity.MetaData{{Title: "site", Value: "example.com"}},
+	}
+
+	ctrl := gomock.NewController(t)
+	keys := mocks.NewMockDataKeys(ctrl)
+	keys.EXPECT().Get(gomock.Any(), userUUID).AnyTimes().Return(aead, nil)
+
+	var stored entity.UserData
+	next := mocks.NewMockStorage(ctrl)
+	next.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+		func(_ context.Context, d entity.UserData) (*entity.UserData, error) {
+			if !d.Sealed || bytes.Contains(d.Data, []byte("secret")) || len(d.MetaData) != 0 {
+				t.Errorf("Create() got unsealed data %+v", d)
+			}
+			stored = d
+			return &d, nil
+		})
+
+	r := NewEnvelopeRepository(next, keys)
+	got, err := r.Create(context.Background(), plain)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	plain.UUID = got.UUID
+	if !reflect.DeepEqual(*got, plain) {
+		t.Errorf("Create() got = %+v, want %+v", *got, plain)
+	}
+
+	legacy := entity.UserData{UUID: "legacy", UserUUID: userUUID, Data: []byte("plain")}
+	moved := stored
+	moved.UUID = "other"
+
+	tests := []struct {
+		name    string
+		rows    []entity.UserData
+		want    []entity.UserData
+		wantErr bool
+	}{
+		{
+			name: "Read_Sealed_Success",
+			rows: []entity.UserData{stored},
+			want: []entity.UserData{plain},
+		},
+		{
+			name: "Read_Legacy_Success",
+			rows: []entity.UserData{legacy},
+			want: []entity.UserData{legacy},
+		},
+		{
+			name:    "Read_Moved_Row_Error",
+			rows:    []entity.UserData{moved},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next.EXPECT().Read(gomock.Any(), userUUID).Times(1).Return(tt.rows, nil)
+			got, err := r.Read(context.Background(), userUUID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Read() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Read() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	next.EXPECT().ReadChanges(gomock.Any(), userUUID, uint64(5)).Times(1).Return(&entity.UserDataChanges{
+		Revision: 7,
+		Upserts:  []entity.UserData{stored},
+		Deleted:  []string{"deleted"},
+	}, nil)
+	changes, err := r.ReadChanges(context.Background(), userUUID, 5)
+	if err != nil {
+		t.Fatalf("ReadChanges() error = %v", err)
+	}
+	wantChanges := &entity.UserDataChanges{Revision: 7, Upserts: []entity.UserData{plain}, Deleted: []string{"deleted"}}
+	if !reflect.DeepEqual(changes, wantChanges) {
+		t.Errorf("ReadChanges() got = %+v, want %+v", changes, wantChanges)
+	}
+}
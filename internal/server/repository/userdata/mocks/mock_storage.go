@@ -0,0 +1,270 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ktigay/goph-keeper/internal/server/repository/userdata (interfaces: Storage)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	entity "github.com/ktigay/goph-keeper/internal/entity"
+)
+
+// MockStorage is a mock of Storage interface.
+type MockStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockStorageMockRecorder
+}
+
+// MockStorageMockRecorder is the mock recorder for MockStorage.
+type MockStorageMockRecorder struct {
+	mock *MockStorage
+}
+
+// NewMockStorage creates a new mock instance.
+func NewMockStorage(ctrl *gomock.Controller) *MockStorage {
+	mock := &MockStorage{ctrl: ctrl}
+	mock.recorder = &MockStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStorage) EXPECT() *MockStorageMockRecorder {
+	return m.recorder
+}
+
+// CountByOtherKeyVersion mocks base method.
+func (m *MockStorage) CountByOtherKeyVersion(arg0 context.Context, arg1 string, arg2 uint32) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByOtherKeyVersion", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByOtherKeyVersion indicates an expected call of CountByOtherKeyVersion.
+func (mr *MockStorageMockRecorder) CountByOtherKeyVersion(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByOtherKeyVersion", reflect.TypeOf((*MockStorage)(nil).CountByOtherKeyVersion), arg0, arg1, arg2)
+}
+
+// Create mocks base method.
+func (m *MockStorage) Create(arg0 context.Context, arg1 entity.UserData) (*entity.UserData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(*entity.UserData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockStorageMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockStorage)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method.
+func (m *MockStorage) Delete(arg0 context.Context, arg1 string, arg2 uint64, arg3 ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Delete", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockStorageMockRecorder) Delete(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockStorage)(nil).Delete), varargs...)
+}
+
+// NextRevision mocks base method.
+func (m *MockStorage) NextRevision(arg0 context.Context, arg1 string) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NextRevision", arg0, arg1)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextRevision indicates an expected call of NextRevision.
+func (mr *MockStorageMockRecorder) NextRevision(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextRevision", reflect.TypeOf((*MockStorage)(nil).NextRevision), arg0, arg1)
+}
+
+// PruneBatches mocks base method.
+func (m *MockStorage) PruneBatches(arg0 context.Context, arg1 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneBatches", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PruneBatches indicates an expected call of PruneBatches.
+func (mr *MockStorageMockRecorder) PruneBatches(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneBatches", reflect.TypeOf((*MockStorage)(nil).PruneBatches), arg0, arg1)
+}
+
+// PruneRevisions mocks base method.
+func (m *MockStorage) PruneRevisions(arg0 context.Context, arg1 int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneRevisions", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PruneRevisions indicates an expected call of PruneRevisions.
+func (mr *MockStorageMockRecorder) PruneRevisions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneRevisions", reflect.TypeOf((*MockStorage)(nil).PruneRevisions), arg0, arg1)
+}
+
+// PruneTombstones mocks base method.
+func (m *MockStorage) PruneTombstones(arg0 context.Context, arg1 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneTombstones", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PruneTombstones indicates an expected call of PruneTombstones.
+func (mr *MockStorageMockRecorder) PruneTombstones(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneTombstones", reflect.TypeOf((*MockStorage)(nil).PruneTombstones), arg0, arg1)
+}
+
+// Read mocks base method.
+func (m *MockStorage) Read(arg0 context.Context, arg1 string, arg2 ...string) ([]entity.UserData, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Read", varargs...)
+	ret0, _ := ret[0].([]entity.UserData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Read indicates an expected call of Read.
+func (mr *MockStorageMockRecorder) Read(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Read", reflect.TypeOf((*MockStorage)(nil).Read), varargs...)
+}
+
+// ReadBatch mocks base method.
+func (m *MockStorage) ReadBatch(arg0 context.Context, arg1, arg2 string) (*entity.UserDataBatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadBatch", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*entity.UserDataBatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadBatch indicates an expected call of ReadBatch.
+func (mr *MockStorageMockRecorder) ReadBatch(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadBatch", reflect.TypeOf((*MockStorage)(nil).ReadBatch), arg0, arg1, arg2)
+}
+
+// ReadChanges mocks base method.
+func (m *MockStorage) ReadChanges(arg0 context.Context, arg1 string, arg2 uint64) (*entity.UserDataChanges, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadChanges", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*entity.UserDataChanges)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadChanges indicates an expected call of ReadChanges.
+func (mr *MockStorageMockRecorder) ReadChanges(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadChanges", reflect.TypeOf((*MockStorage)(nil).ReadChanges), arg0, arg1, arg2)
+}
+
+// ReadRevision mocks base method.
+func (m *MockStorage) ReadRevision(arg0 context.Context, arg1, arg2, arg3 string) (*entity.UserDataRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadRevision", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*entity.UserDataRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadRevision indicates an expected call of ReadRevision.
+func (mr *MockStorageMockRecorder) ReadRevision(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadRevision", reflect.TypeOf((*MockStorage)(nil).ReadRevision), arg0, arg1, arg2, arg3)
+}
+
+// ReadRevisions mocks base method.
+func (m *MockStorage) ReadRevisions(arg0 context.Context, arg1, arg2 string) ([]entity.UserDataRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadRevisions", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]entity.UserDataRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadRevisions indicates an expected call of ReadRevisions.
+func (mr *MockStorageMockRecorder) ReadRevisions(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadRevisions", reflect.TypeOf((*MockStorage)(nil).ReadRevisions), arg0, arg1, arg2)
+}
+
+// Reencrypt mocks base method.
+func (m *MockStorage) Reencrypt(arg0 context.Context, arg1 entity.UserData) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reencrypt", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reencrypt indicates an expected call of Reencrypt.
+func (mr *MockStorageMockRecorder) Reencrypt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reencrypt", reflect.TypeOf((*MockStorage)(nil).Reencrypt), arg0, arg1)
+}
+
+// SaveBatch mocks base method.
+func (m *MockStorage) SaveBatch(arg0 context.Context, arg1, arg2 string, arg3 entity.UserDataBatchResult) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveBatch", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveBatch indicates an expected call of SaveBatch.
+func (mr *MockStorageMockRecorder) SaveBatch(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveBatch", reflect.TypeOf((*MockStorage)(nil).SaveBatch), arg0, arg1, arg2, arg3)
+}
+
+// Update mocks base method.
+func (m *MockStorage) Update(arg0 context.Context, arg1 entity.UserData) (*entity.UserData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(*entity.UserData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockStorageMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockStorage)(nil).Update), arg0, arg1)
+}
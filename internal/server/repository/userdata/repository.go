@@ -5,43 +5,179 @@ import (
 	"errors"
 	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 
 	"github.com/ktigay/goph-keeper/internal/entity"
+	"github.com/ktigay/goph-keeper/internal/server/changefeed"
 	"github.com/ktigay/goph-keeper/internal/server/db"
 )
 
+const (
+	historyFields = `"item_uuid", "user_uuid", "title", "type", "data", "metadata", "tag_macs", "key_version", "sealed", "version", "created_at", "updated_at", "deleted"`
+	historySource = `"uuid", "user_uuid", "title", "type", "data", "metadata", "tag_macs", "key_version", "sealed", "version", "created_at", "updated_at"`
+)
+
 var (
 	insertQuery = `
-		INSERT INTO "user_data" ("user_uuid", "title", "type", "data", "metadata", "created_at", "updated_at")
-			VALUES ($1, $2, $3, $4, $5, $6, $7) 
-		RETURNING "uuid", "user_uuid", "title", "type", "data", "metadata", "created_at", "updated_at"`
+		INSERT INTO "user_data" ("user_uuid", "title", "type", "data", "metadata", "tag_macs", "key_version", "sealed", "created_at", "updated_at", "revision")
+			VALUES ($1, $2, $3, $4, $11, $5, $6, $7, $8, $9, $10) 
+		RETURNING "uuid", "user_uuid", "title", "type", "data", "metadata", "tag_macs", "key_version", "sealed", "version", "revision", "created_at", "updated_at"`
 
+	// insertQueryWithUUID создаёт запись с заданным UUID, удаляя отметку об удалении записи с этим UUID.
 	insertQueryWithUUID = `
-		INSERT INTO "user_data" ("uuid", "user_uuid", "title", "type", "data", "metadata", "created_at", "updated_at")
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8) 
-		RETURNING "uuid", "user_uuid", "title", "type", "data", "metadata", "created_at", "updated_at"`
+		WITH "revived" AS (
+			DELETE FROM "user_data_tombstone" WHERE "item_uuid" = $1 AND "user_uuid" = $2
+		)
+		INSERT INTO "user_data" ("uuid", "user_uuid", "title", "type", "data", "metadata", "tag_macs", "key_version", "sealed", "created_at", "updated_at", "revision")
+			VALUES ($1, $2, $3, $4, $5, $6, $12, $7, $8, $9, $10, $11) 
+		RETURNING "uuid", "user_uuid", "title", "type", "data", "metadata", "tag_macs", "key_version", "sealed", "version", "revision", "created_at", "updated_at"`
 
+	// updateQuery обновляет запись, только если её версия не изменилась с момента чтения клиентом.
+	// Предыдущая версия записи сохраняется в историю тем же запросом.
 	updateQuery = `
+		WITH "prev" AS (
+			SELECT * FROM "user_data"
+			WHERE "uuid" = $8 AND "user_uuid" = $9 AND "version" = $10
+			FOR UPDATE
+		), "archived" AS (
+			INSERT INTO "user_data_history" (` + historyFields + `)
+			SELECT ` + historySource + `, FALSE FROM "prev"
+		)
 		UPDATE "user_data" 
 		SET 
-		    "title" = $1, "type" = $2, "data" = $3, "metadata" = $4, "updated_at" = $5
-		WHERE "uuid" = $6 AND "user_uuid" = $7
-		RETURNING "uuid", "user_uuid", "title", "type", "data", "metadata", "created_at", "updated_at"`
+		    "title" = $1, "type" = $2, "data" = $3, "metadata" = $4, "tag_macs" = $12, "key_version" = $5, "sealed" = $6, "updated_at" = $7,
+		    "version" = "version" + 1, "revision" = $11
+		WHERE "uuid" IN (SELECT "uuid" FROM "prev")
+		RETURNING "uuid", "user_uuid", "title", "type", "data", "metadata", "tag_macs", "key_version", "sealed", "version", "revision", "created_at", "updated_at"`
+
+	// reencryptQuery обновляет запись, только если её версия не изменилась с момента чтения клиентом.
+	// "updated_at" не меняется: ротация ключа не изменяет содержимое записи.
+	reencryptQuery = `
+		UPDATE "user_data"
+		SET
+		    "title" = $1, "data" = $2, "metadata" = $3, "tag_macs" = $10, "key_version" = $4, "sealed" = $5,
+		    "version" = "version" + 1, "revision" = $9
+		WHERE "uuid" = $6 AND "user_uuid" = $7 AND "version" = $8`
 
+	countByOtherKeyVersionQuery = `
+		SELECT COUNT(*)
+		FROM "user_data"
+		WHERE "user_uuid" = $1 AND "key_version" <> $2
+	`
+
+	// deleteQuery удаляет записи, сохраняя их последние версии в историю и отметки об удалении в ревизии $3.
 	deleteQuery = `
+		WITH "prev" AS (
+			SELECT * FROM "user_data"
+			WHERE "user_uuid" = $1 AND "uuid" = ANY($2::uuid[])
+			FOR UPDATE
+		), "archived" AS (
+			INSERT INTO "user_data_history" (` + historyFields + `)
+			SELECT ` + historySource + `, TRUE FROM "prev"
+		), "tombstone" AS (
+			INSERT INTO "user_data_tombstone" ("item_uuid", "user_uuid", "revision")
+			SELECT "uuid", "user_uuid", $3 FROM "prev"
+			ON CONFLICT ("user_uuid", "item_uuid") DO UPDATE SET "revision" = EXCLUDED."revision", "deleted_at" = NOW()
+		)
 		DELETE FROM "user_data"
-		WHERE "user_uuid" = $1 AND "uuid" = ANY($2::uuid[])
+		WHERE "uuid" IN (SELECT "uuid" FROM "prev")
+	`
+
+	// nextRevisionQuery увеличивает счётчик ревизий пользователя. Блокировка строки пользователя
+	// упорядочивает изменения его данных: ревизии фиксируются в порядке возрастания.
+	nextRevisionQuery = `
+		UPDATE "user" SET "data_revision" = "data_revision" + 1
+		WHERE "uuid" = $1
+		RETURNING "data_revision"
+	`
+
+	// notifyQuery уведомляет подписчиков об изменении, уведомление доставляется после фиксации транзакции.
+	notifyQuery = `SELECT pg_notify($1, $2)`
+
+	selectDataRevisionQuery = `
+		SELECT "data_revision", "tombstone_revision" FROM "user" WHERE "uuid" = $1
+	`
+
+	selectChangedQuery = `
+		SELECT "uuid", "user_uuid", "title", "type", "data", "metadata", "tag_macs", "key_version", "sealed", "version", "revision", "created_at", "updated_at"
+		FROM "user_data"
+		WHERE "user_uuid" = $1 AND "revision" > $2
+		ORDER BY "revision"
+	`
+
+	selectTombstonesQuery = `
+		SELECT "item_uuid"
+		FROM "user_data_tombstone"
+		WHERE "user_uuid" = $1 AND "revision" > $2
+	`
+
+	// pruneTombstonesQuery удаляет отметки об удалении старше $1 и запоминает для пользователя
+	// наибольшую удаленную ревизию: изменения до неё клиент получить уже не может.
+	pruneTombstonesQuery = `
+		WITH "pruned" AS (
+			DELETE FROM "user_data_tombstone"
+			WHERE "deleted_at" < $1
+			RETURNING "user_uuid", "revision"
+		), "horizon" AS (
+			UPDATE "user" u
+			SET "tombstone_revision" = GREATEST(u."tombstone_revision", p."revision")
+			FROM (SELECT "user_uuid", MAX("revision") AS "revision" FROM "pruned" GROUP BY "user_uuid") p
+			WHERE u."uuid" = p."user_uuid"
+		)
+		SELECT COUNT(*) FROM "pruned"
+	`
+
+	selectRevisionsQuery = `
+		SELECT "uuid", ` + historyFields + `, "archived_at"
+		FROM "user_data_history"
+		WHERE "user_uuid" = $1 AND "item_uuid" = $2
+		ORDER BY "archived_at" DESC, "version" DESC
+	`
+
+	selectRevisionQuery = `
+		SELECT "uuid", ` + historyFields + `, "archived_at"
+		FROM "user_data_history"
+		WHERE "user_uuid" = $1 AND "item_uuid" = $2 AND "uuid" = $3
+	`
+
+	// pruneRevisionsQuery оставляет для каждой записи последние версии по настройке пользователя
+	// ("user"."history_retention") или значению по умолчанию $1.
+	pruneRevisionsQuery = `
+		DELETE FROM "user_data_history"
+		WHERE "uuid" IN (
+			SELECT r."uuid"
+			FROM (
+				SELECT h."uuid",
+					ROW_NUMBER() OVER (PARTITION BY h."user_uuid", h."item_uuid" ORDER BY h."archived_at" DESC, h."version" DESC) AS "n",
+					COALESCE(u."history_retention", $1) AS "keep"
+				FROM "user_data_history" h
+				LEFT JOIN "user" u ON u."uuid" = h."user_uuid"
+			) r
+			WHERE r."n" > r."keep"
+		)
+	`
+
+	selectBatchQuery = `
+		SELECT "result" FROM "user_data_batch" WHERE "user_uuid" = $1 AND "uuid" = $2
+	`
+
+	insertBatchQuery = `
+		INSERT INTO "user_data_batch" ("uuid", "user_uuid", "result") VALUES ($1, $2, $3)
+	`
+
+	pruneBatchesQuery = `
+		DELETE FROM "user_data_batch" WHERE "created_at" < $1
 	`
 
 	selectByUserQuery = `
-		SELECT "uuid", "user_uuid", "title", "type", "data", "metadata", "created_at", "updated_at"
+		SELECT "uuid", "user_uuid", "title", "type", "data", "metadata", "tag_macs", "key_version", "sealed", "version", "revision", "created_at", "updated_at"
 		FROM "user_data"
 		WHERE "user_uuid" = $1
 	`
 	selectByUserAndIDQuery = `
-		SELECT "uuid", "user_uuid", "title", "type", "data", "metadata", "created_at", "updated_at"
+		SELECT "uuid", "user_uuid", "title", "type", "data", "metadata", "tag_macs", "key_version", "sealed", "version", "revision", "created_at", "updated_at"
 		FROM "user_data"
 		WHERE "user_uuid" = $1 AND "uuid" = ANY($2::uuid[])
 	`
@@ -59,28 +195,138 @@ func (r *Repository) Create(ctx context.Context, data entity.UserData) (*entity.
 	defer cancel()
 
 	if data.UUID != "" {
-		return r.queryRow(c, insertQueryWithUUID, data.UUID, data.UserUUID, data.Title, data.Type, data.Data, data.MetaData, data.CreatedAt, data.UpdatedAt)
+		return r.queryRow(c, insertQueryWithUUID, data.UUID, data.UserUUID, data.Title, data.Type, data.Data, data.MetaData, data.KeyVersion, data.Sealed, data.CreatedAt, data.UpdatedAt, data.Revision, tagMACs(data))
 	}
-	return r.queryRow(c, insertQuery, data.UserUUID, data.Title, data.Type, data.Data, data.MetaData, data.CreatedAt, data.UpdatedAt)
+	return r.queryRow(c, insertQuery, data.UserUUID, data.Title, data.Type, data.Data, data.MetaData, data.KeyVersion, data.Sealed, data.CreatedAt, data.UpdatedAt, data.Revision, tagMACs(data))
 }
 
-// Update обновляет запись пользовательских данных.
+// Update обновляет запись пользовательских данных версии data.Version.
+// Возвращает nil, если записи нет или её версия другая.
 func (r *Repository) Update(ctx context.Context, data entity.UserData) (*entity.UserData, error) {
 	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
 	defer cancel()
 
-	return r.queryRow(c, updateQuery, data.Title, data.Type, data.Data, data.MetaData, data.UpdatedAt, data.UUID, data.UserUUID)
+	d, err := r.queryRow(c, updateQuery, data.Title, data.Type, data.Data, data.MetaData, data.KeyVersion, data.Sealed, data.UpdatedAt, data.UUID, data.UserUUID, data.Version, data.Revision, tagMACs(data))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return d, err
+}
+
+// Reencrypt сохраняет перешифрованную запись, если её версия всё ещё data.Version.
+// Возвращает false, если запись изменена или удалена.
+func (r *Repository) Reencrypt(ctx context.Context, data entity.UserData) (bool, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	tag, err := r.db.Connection(ctx).Exec(c, reencryptQuery, data.Title, data.Data, data.MetaData, data.KeyVersion, data.Sealed, data.UUID, data.UserUUID, data.Version, data.Revision, tagMACs(data))
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
 }
 
-// Delete удаляет запись пользовательских данных.
-func (r *Repository) Delete(ctx context.Context, userUUID string, uuids ...string) error {
+// CountByOtherKeyVersion возвращает количество записей пользователя, зашифрованных не ключом keyVersion.
+func (r *Repository) CountByOtherKeyVersion(ctx context.Context, userUUID string, keyVersion uint32) (int, error) {
 	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
 	defer cancel()
 
-	_, err := r.db.Connection(ctx).Exec(c, deleteQuery, userUUID, "{"+strings.Join(uuids, ",")+"}")
+	var n int
+	if err := r.db.Connection(ctx).QueryRow(c, countByOtherKeyVersionQuery, userUUID, keyVersion).Scan(&n); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// Delete удаляет записи пользовательских данных в ревизии revision, их последние версии сохраняются в историю.
+func (r *Repository) Delete(ctx context.Context, userUUID string, revision uint64, uuids ...string) error {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	_, err := r.db.Connection(ctx).Exec(c, deleteQuery, userUUID, "{"+strings.Join(uuids, ",")+"}", revision)
 	return err
 }
 
+// NextRevision увеличивает счётчик ревизий данных пользователя и возвращает новую ревизию.
+// Строка пользователя остается заблокированной до конца транзакции,
+// подписчики [changefeed.Channel] получат новую ревизию после её фиксации.
+func (r *Repository) NextRevision(ctx context.Context, userUUID string) (uint64, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	conn := r.db.Connection(ctx)
+
+	var rev uint64
+	if err := conn.QueryRow(c, nextRevisionQuery, userUUID).Scan(&rev); err != nil {
+		return 0, err
+	}
+	if _, err := conn.Exec(c, notifyQuery, changefeed.Channel, changefeed.Payload(userUUID, rev)); err != nil {
+		return 0, err
+	}
+	return rev, nil
+}
+
+// ReadChanges возвращает записи, измененные после ревизии since, и UUID удаленных записей.
+// since = 0 - все записи без удаленных, так же, если отметки об удалении после since уже не хранятся.
+func (r *Repository) ReadChanges(ctx context.Context, userUUID string, since uint64) (*entity.UserDataChanges, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	changes := &entity.UserDataChanges{
+		Upserts: make([]entity.UserData, 0),
+		Deleted: make([]string, 0),
+	}
+	var horizon uint64
+	if err := r.db.Connection(ctx).QueryRow(c, selectDataRevisionQuery, userUUID).Scan(&changes.Revision, &horizon); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return changes, nil
+		}
+		return nil, err
+	}
+	// Отметки об удалении после since уже удалены: клиенту возвращаются все записи.
+	if since != 0 && since < horizon {
+		since = 0
+		changes.Full = true
+	}
+
+	rows, err := r.db.Connection(ctx).Query(c, selectChangedQuery, userUUID, since)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var d entity.UserData
+		if err = r.fullScan(rows, &d); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		changes.Upserts = append(changes.Upserts, d)
+	}
+	rows.Close()
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	if since == 0 {
+		return changes, nil
+	}
+
+	if rows, err = r.db.Connection(ctx).Query(c, selectTombstonesQuery, userUUID, since); err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var u string
+		if err = rows.Scan(&u); err != nil {
+			return nil, err
+		}
+		changes.Deleted = append(changes.Deleted, u)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return changes, nil
+}
+
 // Read читает записи пользовательских данных.
 func (r *Repository) Read(ctx context.Context, userUUID string, uuids ...string) ([]entity.UserData, error) {
 	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
@@ -123,6 +369,106 @@ func (r *Repository) Read(ctx context.Context, userUUID string, uuids ...string)
 	return d, nil
 }
 
+// ReadRevisions возвращает предыдущие версии записи, от новых к старым.
+func (r *Repository) ReadRevisions(ctx context.Context, userUUID, itemUUID string) ([]entity.UserDataRevision, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	rows, err := r.db.Connection(ctx).Query(c, selectRevisionsQuery, userUUID, itemUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := make([]entity.UserDataRevision, 0)
+	for rows.Next() {
+		var rev entity.UserDataRevision
+		if err = r.revisionScan(rows, &rev); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return revisions, nil
+}
+
+// ReadRevision возвращает версию записи или nil, если её нет.
+func (r *Repository) ReadRevision(ctx context.Context, userUUID, itemUUID, revisionUUID string) (*entity.UserDataRevision, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	var rev entity.UserDataRevision
+	if err := r.revisionScan(r.db.Connection(ctx).QueryRow(c, selectRevisionQuery, userUUID, itemUUID, revisionUUID), &rev); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rev, nil
+}
+
+// PruneRevisions удаляет версии сверх хранимого количества. Возвращает количество удаленных.
+func (r *Repository) PruneRevisions(ctx context.Context, retention int) (int64, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	tag, err := r.db.Connection(ctx).Exec(c, pruneRevisionsQuery, retention)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// PruneTombstones удаляет отметки об удалении записей, сделанные раньше before. Возвращает количество удаленных.
+func (r *Repository) PruneTombstones(ctx context.Context, before time.Time) (int64, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	var n int64
+	if err := r.db.Connection(ctx).QueryRow(c, pruneTombstonesQuery, before).Scan(&n); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// ReadBatch возвращает сохраненный результат пакета изменений или nil, если пакет не применялся.
+func (r *Repository) ReadBatch(ctx context.Context, userUUID, batchUUID string) (*entity.UserDataBatchResult, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	var res entity.UserDataBatchResult
+	if err := r.db.Connection(ctx).QueryRow(c, selectBatchQuery, userUUID, batchUUID).Scan(&res); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &res, nil
+}
+
+// SaveBatch сохраняет результат пакета изменений. Сохраняются только статусы записей, без их данных.
+func (r *Repository) SaveBatch(ctx context.Context, userUUID, batchUUID string, res entity.UserDataBatchResult) error {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	_, err := r.db.Connection(ctx).Exec(c, insertBatchQuery, batchUUID, userUUID, res)
+	return err
+}
+
+// PruneBatches удаляет результаты пакетов изменений, примененных раньше before. Возвращает количество удаленных.
+func (r *Repository) PruneBatches(ctx context.Context, before time.Time) (int64, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	tag, err := r.db.Connection(ctx).Exec(c, pruneBatchesQuery, before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
 func (r *Repository) queryRow(ctx context.Context, query string, args ...any) (*entity.UserData, error) {
 	var (
 		ud  entity.UserData
@@ -146,11 +492,44 @@ func (r *Repository) fullScan(row pgx.Row, ud *entity.UserData) error {
 		&ud.Type,
 		&ud.Data,
 		&ud.MetaData,
+		&ud.TagMACs,
+		&ud.KeyVersion,
+		&ud.Sealed,
+		&ud.Version,
+		&ud.Revision,
 		&ud.CreatedAt,
 		&ud.UpdatedAt,
 	)
 }
 
+func (r *Repository) revisionScan(row pgx.Row, rev *entity.UserDataRevision) error {
+	return row.Scan(
+		&rev.UUID,
+		&rev.Item.UUID,
+		&rev.Item.UserUUID,
+		&rev.Item.Title,
+		&rev.Item.Type,
+		&rev.Item.Data,
+		&rev.Item.MetaData,
+		&rev.Item.TagMACs,
+		&rev.Item.KeyVersion,
+		&rev.Item.Sealed,
+		&rev.Item.Version,
+		&rev.Item.CreatedAt,
+		&rev.Item.UpdatedAt,
+		&rev.Deleted,
+		&rev.ArchivedAt,
+	)
+}
+
+// tagMACs возвращает HMAC тегов записи, колонка "tag_macs" не допускает NULL.
+func tagMACs(data entity.UserData) [][]byte {
+	if data.TagMACs == nil {
+		return [][]byte{}
+	}
+	return data.TagMACs
+}
+
 // New Конструктор.
 func New(db db.ConnWrapper, logger *slog.Logger) *Repository {
 	return &Repository{
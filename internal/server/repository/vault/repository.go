@@ -0,0 +1,171 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ktigay/goph-keeper/internal/entity"
+	"github.com/ktigay/goph-keeper/internal/server/db"
+)
+
+var (
+	insertQuery = `
+		INSERT INTO "vault_key" ("user_uuid", "salt", "wrapped_key", "key_version", "kdf_time", "kdf_memory", "kdf_threads", "wrapped_tag_key")
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT ("user_uuid") DO NOTHING
+		RETURNING "user_uuid", "salt", "wrapped_key", "key_version", "kdf_time", "kdf_memory", "kdf_threads", "wrapped_previous_key", "wrapped_tag_key"`
+
+	selectByUserQuery = `
+		SELECT "user_uuid", "salt", "wrapped_key", "key_version", "kdf_time", "kdf_memory", "kdf_threads", "wrapped_previous_key", "wrapped_tag_key"
+		FROM "vault_key"
+		WHERE "user_uuid" = $1
+	`
+
+	selectByUserForUpdateQuery = selectByUserQuery + ` FOR UPDATE`
+
+	upsertRotationQuery = `
+		INSERT INTO "vault_key_rotation" ("user_uuid", "salt", "wrapped_key", "key_version", "kdf_time", "kdf_memory", "kdf_threads", "wrapped_previous_key", "wrapped_tag_key", "created_at")
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW())
+		ON CONFLICT ("user_uuid") DO UPDATE SET
+			"salt" = EXCLUDED."salt",
+			"wrapped_key" = EXCLUDED."wrapped_key",
+			"key_version" = EXCLUDED."key_version",
+			"kdf_time" = EXCLUDED."kdf_time",
+			"kdf_memory" = EXCLUDED."kdf_memory",
+			"kdf_threads" = EXCLUDED."kdf_threads",
+			"wrapped_previous_key" = EXCLUDED."wrapped_previous_key",
+			"wrapped_tag_key" = EXCLUDED."wrapped_tag_key",
+			"created_at" = EXCLUDED."created_at"
+		RETURNING "user_uuid", "salt", "wrapped_key", "key_version", "kdf_time", "kdf_memory", "kdf_threads", "wrapped_previous_key", "wrapped_tag_key"`
+
+	selectRotationByUserQuery = `
+		SELECT "user_uuid", "salt", "wrapped_key", "key_version", "kdf_time", "kdf_memory", "kdf_threads", "wrapped_previous_key", "wrapped_tag_key"
+		FROM "vault_key_rotation"
+		WHERE "user_uuid" = $1
+	`
+
+	completeRotationQuery = `
+		WITH "r" AS (
+			DELETE FROM "vault_key_rotation" WHERE "user_uuid" = $1
+			RETURNING *
+		)
+		UPDATE "vault_key" AS "v"
+		SET
+			"salt" = "r"."salt",
+			"wrapped_key" = "r"."wrapped_key",
+			"key_version" = "r"."key_version",
+			"kdf_time" = "r"."kdf_time",
+			"kdf_memory" = "r"."kdf_memory",
+			"kdf_threads" = "r"."kdf_threads",
+			"wrapped_previous_key" = "r"."wrapped_previous_key",
+			"wrapped_tag_key" = "r"."wrapped_tag_key",
+			"updated_at" = NOW()
+		FROM "r"
+		WHERE "v"."user_uuid" = "r"."user_uuid"
+		RETURNING "v"."user_uuid", "v"."salt", "v"."wrapped_key", "v"."key_version", "v"."kdf_time", "v"."kdf_memory", "v"."kdf_threads", "v"."wrapped_previous_key", "v"."wrapped_tag_key"`
+)
+
+// Repository репозиторий ключей хранилища.
+type Repository struct {
+	db     db.ConnWrapper
+	logger *slog.Logger
+}
+
+// Create сохраняет ключ хранилища. Возвращает nil, если ключ уже существует.
+func (r *Repository) Create(ctx context.Context, key entity.VaultKey) (*entity.VaultKey, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	return r.queryRowOrNil(c, insertQuery, key.UserUUID, key.Salt, key.WrappedKey, key.KeyVersion, key.KDF.Time, key.KDF.Memory, key.KDF.Threads, key.WrappedTagKey)
+}
+
+// Read возвращает ключ хранилища пользователя.
+func (r *Repository) Read(ctx context.Context, userUUID string) (*entity.VaultKey, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	return r.queryRowOrNil(c, selectByUserQuery, userUUID)
+}
+
+// ReadForUpdate возвращает ключ хранилища пользователя и блокирует его до конца транзакции.
+func (r *Repository) ReadForUpdate(ctx context.Context, userUUID string) (*entity.VaultKey, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	return r.queryRowOrNil(c, selectByUserForUpdateQuery, userUUID)
+}
+
+// SaveRotation сохраняет новый ключ незавершённой ротации, заменяя предыдущую попытку.
+func (r *Repository) SaveRotation(ctx context.Context, key entity.VaultKey) (*entity.VaultKey, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	return r.queryRow(c, upsertRotationQuery, key.UserUUID, key.Salt, key.WrappedKey, key.KeyVersion, key.KDF.Time, key.KDF.Memory, key.KDF.Threads, key.WrappedPreviousKey, key.WrappedTagKey)
+}
+
+// ReadRotation возвращает ключ незавершённой ротации.
+func (r *Repository) ReadRotation(ctx context.Context, userUUID string) (*entity.VaultKey, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	return r.queryRowOrNil(c, selectRotationByUserQuery, userUUID)
+}
+
+// CompleteRotation делает ключ ротации текущим ключом хранилища.
+func (r *Repository) CompleteRotation(ctx context.Context, userUUID string) (*entity.VaultKey, error) {
+	c, cancel := context.WithTimeout(ctx, db.RequestTimeout)
+	defer cancel()
+
+	return r.queryRowOrNil(c, completeRotationQuery, userUUID)
+}
+
+func (r *Repository) queryRowOrNil(ctx context.Context, query string, args ...any) (*entity.VaultKey, error) {
+	k, err := r.queryRow(ctx, query, args...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return k, nil
+}
+
+func (r *Repository) queryRow(ctx context.Context, query string, args ...any) (*entity.VaultKey, error) {
+	var (
+		k   entity.VaultKey
+		err error
+	)
+
+	if err = r.fullScan(
+		r.db.Connection(ctx).QueryRow(ctx, query, args...),
+		&k,
+	); err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+func (r *Repository) fullScan(row pgx.Row, k *entity.VaultKey) error {
+	return row.Scan(
+		&k.UserUUID,
+		&k.Salt,
+		&k.WrappedKey,
+		&k.KeyVersion,
+		&k.KDF.Time,
+		&k.KDF.Memory,
+		&k.KDF.Threads,
+		&k.WrappedPreviousKey,
+		&k.WrappedTagKey,
+	)
+}
+
+// New Конструктор.
+func New(db db.ConnWrapper, logger *slog.Logger) *Repository {
+	return &Repository{
+		db:     db,
+		logger: logger,
+	}
+}
@@ -0,0 +1,23 @@
+package certs
+
+import (
+	"crypto/x509"
+	"strings"
+)
+
+// UserURIPrefix префикс URI в SAN клиентского сертификата, привязывающий его к аккаунту:
+// urn:goph-keeper:user:<uuid пользователя>.
+const UserURIPrefix = "urn:goph-keeper:user:"
+
+// BoundUser возвращает UUID пользователя, к которому привязан сертификат, или пустую строку.
+func BoundUser(cert *x509.Certificate) string {
+	if cert == nil {
+		return ""
+	}
+	for _, u := range cert.URIs {
+		if s := u.String(); strings.HasPrefix(s, UserURIPrefix) {
+			return strings.TrimPrefix(s, UserURIPrefix)
+		}
+	}
+	return ""
+}
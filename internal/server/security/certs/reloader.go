@@ -0,0 +1,178 @@
+// Package certs загружает сертификат TLS сервера и CA клиентских сертификатов
+// и перечитывает их при изменении файлов без перезапуска сервера.
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNoClientCA в файле CA нет ни одного сертификата.
+	ErrNoClientCA = errors.New("no certificates found in client CA file")
+	// ErrClientCARequired обязательный клиентский сертификат без CA для его проверки.
+	ErrClientCARequired = errors.New("client CA file is required to verify client certificates")
+)
+
+// Files файлы сертификатов.
+type Files struct {
+	CertFile string
+	KeyFile  string
+	// ClientCAFile CA клиентских сертификатов (mTLS). Пусто - клиентские сертификаты не запрашиваются.
+	ClientCAFile string
+	// RequireClientCert соединение без клиентского сертификата отклоняется.
+	RequireClientCert bool
+}
+
+// Reloader текущие сертификаты сервера.
+type Reloader struct {
+	files  Files
+	logger *slog.Logger
+
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	clientCA *x509.CertPool
+	modTimes map[string]time.Time
+}
+
+// TLSConfig возвращает конфигурацию TLS, которая на каждое соединение берет текущие сертификаты.
+func (r *Reloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return r.current(), nil
+		},
+	}
+}
+
+// Reload перечитывает файлы, если они изменились. При ошибке остаются прежние сертификаты.
+func (r *Reloader) Reload() (bool, error) {
+	modTimes, err := r.stat()
+	if err != nil {
+		return false, err
+	}
+
+	r.mu.RLock()
+	changed := r.cert == nil || !equalTimes(modTimes, r.modTimes)
+	r.mu.RUnlock()
+	if !changed {
+		return false, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.files.CertFile, r.files.KeyFile)
+	if err != nil {
+		return false, fmt.Errorf("load key pair: %w", err)
+	}
+	var pool *x509.CertPool
+	if r.files.ClientCAFile != "" {
+		if pool, err = loadPool(r.files.ClientCAFile); err != nil {
+			return false, err
+		}
+	}
+
+	r.mu.Lock()
+	r.cert, r.clientCA, r.modTimes = &cert, pool, modTimes
+	r.mu.Unlock()
+	return true, nil
+}
+
+// Watch проверяет файлы раз в interval до отмены ctx.
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			reloaded, err := r.Reload()
+			if err != nil {
+				r.logger.Error("tls certificates reload failed, keeping previous ones", "error", err)
+				continue
+			}
+			if reloaded {
+				r.logger.Info("tls certificates reloaded", "cert_file", r.files.CertFile)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (r *Reloader) current() *tls.Config {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	cfg := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{*r.cert},
+	}
+	if r.clientCA != nil {
+		cfg.ClientCAs = r.clientCA
+		cfg.ClientAuth = tls.VerifyClientCertIfGiven
+		if r.files.RequireClientCert {
+			cfg.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+	return cfg
+}
+
+func (r *Reloader) stat() (map[string]time.Time, error) {
+	modTimes := make(map[string]time.Time, 3)
+	for _, name := range []string{r.files.CertFile, r.files.KeyFile, r.files.ClientCAFile} {
+		if name == "" {
+			continue
+		}
+		info, err := os.Stat(name)
+		if err != nil {
+			return nil, err
+		}
+		modTimes[name] = info.ModTime()
+	}
+	return modTimes, nil
+}
+
+func equalTimes(a, b map[string]time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if !v.Equal(b[k]) {
+			return false
+		}
+	}
+	return true
+}
+
+func loadPool(name string) (*x509.CertPool, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(b) {
+		return nil, fmt.Errorf("%w: %s", ErrNoClientCA, name)
+	}
+	return pool, nil
+}
+
+// New конструктор, загружает сертификаты.
+func New(files Files, logger *slog.Logger) (*Reloader, error) {
+	if files.RequireClientCert && files.ClientCAFile == "" {
+		return nil, ErrClientCARequired
+	}
+	r := &Reloader{
+		files:  files,
+		logger: logger,
+	}
+	if _, err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
@@ -0,0 +1,129 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"log/slog"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeCert записывает самоподписанный сертификат и ключ, mtime файлов - modTime.
+func writeCert(t *testing.T, certFile, keyFile, cn string, modTime time.Time) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{cn},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{certFile, keyFile} {
+		if err = os.Chtimes(name, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func commonName(t *testing.T, r *Reloader) string {
+	t.Helper()
+
+	cfg, err := r.TLSConfig().GetConfigForClient(&tls.ClientHelloInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(cfg.Certificates[0].Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return leaf.Subject.CommonName
+}
+
+func TestReloader_Reload(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key")
+	start := time.Unix(1700000000, 0)
+	writeCert(t, certFile, keyFile, "first", start)
+
+	r, err := New(Files{CertFile: certFile, KeyFile: keyFile}, slog.Default())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if got := commonName(t, r); got != "first" {
+		t.Fatalf("certificate = %q, want first", got)
+	}
+
+	if reloaded, err := r.Reload(); reloaded || err != nil {
+		t.Errorf("Reload() of unchanged files = %v, %v", reloaded, err)
+	}
+
+	writeCert(t, certFile, keyFile, "second", start.Add(time.Minute))
+	if reloaded, err := r.Reload(); !reloaded || err != nil {
+		t.Fatalf("Reload() = %v, %v, want reloaded", reloaded, err)
+	}
+	if got := commonName(t, r); got != "second" {
+		t.Errorf("certificate = %q, want second", got)
+	}
+
+	// Поврежденный файл не заменяет рабочий сертификат.
+	if err = os.WriteFile(keyFile, []byte("broken"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = r.Reload(); err == nil {
+		t.Error("Reload() of a broken key must fail")
+	}
+	if got := commonName(t, r); got != "second" {
+		t.Errorf("certificate = %q after failed reload, want second", got)
+	}
+}
+
+func TestNew_ClientCA(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key")
+	writeCert(t, certFile, keyFile, "server", time.Now())
+
+	if _, err := New(Files{CertFile: certFile, KeyFile: keyFile, RequireClientCert: true}, slog.Default()); !errors.Is(err, ErrClientCARequired) {
+		t.Errorf("New() error = %v, want %v", err, ErrClientCARequired)
+	}
+	if _, err := New(Files{CertFile: certFile, KeyFile: keyFile, ClientCAFile: keyFile}, slog.Default()); !errors.Is(err, ErrNoClientCA) {
+		t.Errorf("New() error = %v, want %v", err, ErrNoClientCA)
+	}
+
+	r, err := New(Files{CertFile: certFile, KeyFile: keyFile, ClientCAFile: certFile, RequireClientCert: true}, slog.Default())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	cfg, _ := r.TLSConfig().GetConfigForClient(&tls.ClientHelloInfo{})
+	if cfg.ClientAuth != tls.RequireAndVerifyClientCert || cfg.ClientCAs == nil {
+		t.Errorf("client auth = %v, want RequireAndVerifyClientCert with CA", cfg.ClientAuth)
+	}
+}
@@ -0,0 +1,146 @@
+package envelope
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"log/slog"
+	"sync"
+
+	"github.com/ktigay/goph-keeper/internal/entity"
+)
+
+// rewrapBatchSize количество ключей данных, перешифровываемых за один запрос.
+const rewrapBatchSize = 100
+
+// KeyRepository репозиторий ключей данных.
+//
+//go:generate mockgen -destination=./mocks/mock_datakey.go -package=mocks github.com/ktigay/goph-keeper/internal/server/security/envelope KeyRepository
+type KeyRepository interface {
+	Create(ctx context.Context, key entity.DataKey) (*entity.DataKey, error)
+	Read(ctx context.Context, userUUID string) (*entity.DataKey, error)
+	ReadByOtherKEK(ctx context.Context, kekID string, limit int) ([]entity.DataKey, error)
+	UpdateWrapped(ctx context.Context, key entity.DataKey, oldKEKID string) (bool, error)
+}
+
+// DataKeys ключи данных пользователей, зашифрованные мастер-ключом.
+type DataKeys struct {
+	repo    KeyRepository
+	keyring *Keyring
+	logger  *slog.Logger
+
+	mu    sync.RWMutex
+	cache map[string]cipher.AEAD
+}
+
+// Get возвращает ключ данных пользователя, создавая его при первом обращении.
+func (d *DataKeys) Get(ctx context.Context, userUUID string) (cipher.AEAD, error) {
+	d.mu.RLock()
+	aead, ok := d.cache[userUUID]
+	d.mu.RUnlock()
+	if ok {
+		return aead, nil
+	}
+
+	key, err := d.repo.Read(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		if key, err = d.create(ctx, userUUID); err != nil {
+			return nil, err
+		}
+	}
+
+	dek, err := d.keyring.Unwrap(key.KEKID, key.WrappedKey, []byte(userUUID))
+	if err != nil {
+		return nil, err
+	}
+	if aead, err = NewAEAD(dek); err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	d.cache[userUUID] = aead
+	d.mu.Unlock()
+	return aead, nil
+}
+
+// Rewrap перешифровывает текущим мастер-ключом все ключи данных, зашифрованные другими мастер-ключами.
+// Сами данные не перешифровываются. Возвращает количество перешифрованных ключей.
+func (d *DataKeys) Rewrap(ctx context.Context) (int, error) {
+	current := d.keyring.CurrentID()
+	total := 0
+	for {
+		keys, err := d.repo.ReadByOtherKEK(ctx, current, rewrapBatchSize)
+		if err != nil {
+			return total, err
+		}
+		if len(keys) == 0 {
+			return total, nil
+		}
+
+		for _, key := range keys {
+			dek, err := d.keyring.Unwrap(key.KEKID, key.WrappedKey, []byte(key.UserUUID))
+			if err != nil {
+				return total, err
+			}
+
+			oldKEKID := key.KEKID
+			if key.KEKID, key.WrappedKey, err = d.keyring.Wrap(dek, []byte(key.UserUUID)); err != nil {
+				return total, err
+			}
+
+			var ok bool
+			if ok, err = d.repo.UpdateWrapped(ctx, key, oldKEKID); err != nil {
+				return total, err
+			}
+			if ok {
+				total++
+			}
+		}
+		d.logger.Info("data keys rewrapped", "kek_id", current, "total", total)
+	}
+}
+
+func (d *DataKeys) create(ctx context.Context, userUUID string) (*entity.DataKey, error) {
+	dek := make([]byte, KeySize)
+	if _, err := rand.Read(dek); err != nil {
+		return nil, err
+	}
+	kekID, wrapped, err := d.keyring.Wrap(dek, []byte(userUUID))
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := d.repo.Create(ctx, entity.DataKey{
+		UserUUID:   userUUID,
+		KEKID:      kekID,
+		WrappedKey: wrapped,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if key != nil {
+		return key, nil
+	}
+	// Ключ создан параллельным запросом.
+	if key, err = d.repo.Read(ctx, userUUID); err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, errors.New("data key not found after create")
+	}
+	return key, nil
+}
+
+// NewDataKeys конструктор.
+func NewDataKeys(repo KeyRepository, keyring *Keyring, logger *slog.Logger) *DataKeys {
+	return &DataKeys{
+		repo:    repo,
+		keyring: keyring,
+		logger:  logger,
+		cache:   make(map[string]cipher.AEAD),
+	}
+}
@@ -0,0 +1,134 @@
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// KeySize размер мастер-ключа и ключа данных (AES-256).
+const KeySize = 32
+
+var (
+	// ErrNoMasterKey мастер-ключ не задан.
+	ErrNoMasterKey = errors.New("master key is not configured")
+	// ErrUnknownKEK ключ с таким идентификатором не загружен.
+	ErrUnknownKEK = errors.New("unknown kek id")
+	// ErrDecrypt ошибка расшифровки.
+	ErrDecrypt = errors.New("decryption failed")
+)
+
+// Keyring набор мастер-ключей (KEK). Первый ключ - текущий, им шифруются ключи данных,
+// остальные используются только для расшифровки при ротации.
+type Keyring struct {
+	current string
+	keys    map[string]cipher.AEAD
+}
+
+// CurrentID возвращает идентификатор текущего мастер-ключа.
+func (k *Keyring) CurrentID() string {
+	return k.current
+}
+
+// Wrap шифрует ключ данных текущим мастер-ключом.
+func (k *Keyring) Wrap(dek, ad []byte) (string, []byte, error) {
+	wrapped, err := Seal(k.keys[k.current], dek, ad)
+	if err != nil {
+		return "", nil, err
+	}
+	return k.current, wrapped, nil
+}
+
+// Unwrap расшифровывает ключ данных мастер-ключом kekID.
+func (k *Keyring) Unwrap(kekID string, wrapped, ad []byte) ([]byte, error) {
+	aead, ok := k.keys[kekID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKEK, kekID)
+	}
+	return Open(aead, wrapped, ad)
+}
+
+// ParseKeyring разбирает мастер-ключи в формате "id:base64" через запятую или перевод строки.
+func ParseKeyring(s string) (*Keyring, error) {
+	k := &Keyring{keys: make(map[string]cipher.AEAD)}
+
+	for _, entry := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '\n' }) {
+		if entry = strings.TrimSpace(entry); entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("invalid master key entry, want id:base64")
+		}
+		if _, exists := k.keys[id]; exists {
+			return nil, fmt.Errorf("duplicate master key id %q", id)
+		}
+		secret, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("master key %q: %w", id, err)
+		}
+		if len(secret) != KeySize {
+			return nil, fmt.Errorf("master key %q: want %d bytes, got %d", id, KeySize, len(secret))
+		}
+		aead, err := NewAEAD(secret)
+		if err != nil {
+			return nil, err
+		}
+		if k.current == "" {
+			k.current = id
+		}
+		k.keys[id] = aead
+	}
+
+	if k.current == "" {
+		return nil, ErrNoMasterKey
+	}
+	return k, nil
+}
+
+// LoadKeyring загружает мастер-ключи из файла path, если он указан, иначе из строки keys.
+func LoadKeyring(path, keys string) (*Keyring, error) {
+	if path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		keys = string(b)
+	}
+	return ParseKeyring(keys)
+}
+
+// NewAEAD создаёт AES-256-GCM.
+func NewAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Seal шифрует данные, nonce добавляется в начало шифротекста.
+func Seal(aead cipher.AEAD, plain, ad []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plain)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plain, ad), nil
+}
+
+// Open расшифровывает данные, зашифрованные [Seal].
+func Open(aead cipher.AEAD, ciphertext, ad []byte) ([]byte, error) {
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, ErrDecrypt
+	}
+	plain, err := aead.Open(nil, ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():], ad)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plain, nil
+}
@@ -0,0 +1,134 @@
+package envelope
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/ktigay/goph-keeper/internal/entity"
+	"github.com/ktigay/goph-keeper/internal/log"
+	"github.com/ktigay/goph-keeper/internal/server/security/envelope/mocks"
+)
+
+var (
+	testKey1 = base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, KeySize))
+	testKey2 = base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{2}, KeySize))
+)
+
+func TestParseKeyring(t *testing.T) {
+	tests := []struct {
+		name        string
+		keys        string
+		wantCurrent string
+		wantErr     bool
+	}{
+		{
+			name:        "Parse_Single_Success",
+			keys:        "k1:" + testKey1,
+			wantCurrent: "k1",
+		},
+		{
+			name:        "Parse_Multiple_Lines_Success",
+			keys:        "# active\nk2:" + testKey2 + "\nk1:" + testKey1 + "\n",
+			wantCurrent: "k2",
+		},
+		{
+			name:        "Parse_Comma_Separated_Success",
+			keys:        "k2:" + testKey2 + ",k1:" + testKey1,
+			wantCurrent: "k2",
+		},
+		{
+			name:    "Parse_Empty_Error",
+			keys:    "",
+			wantErr: true,
+		},
+		{
+			name:    "Parse_Short_Key_Error",
+			keys:    "k1:" + base64.StdEncoding.EncodeToString([]byte("short")),
+			wantErr: true,
+		},
+		{
+			name:    "Parse_Duplicate_ID_Error",
+			keys:    "k1:" + testKey1 + ",k1:" + testKey2,
+			wantErr: true,
+		},
+		{
+			name:    "Parse_Without_ID_Error",
+			keys:    testKey1,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseKeyring(tt.keys)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseKeyring() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got.CurrentID() != tt.wantCurrent {
+				t.Errorf("CurrentID() got = %v, want %v", got.CurrentID(), tt.wantCurrent)
+			}
+		})
+	}
+}
+
+func TestKeyring_WrapUnwrap(t *testing.T) {
+	old, _ := ParseKeyring("k1:" + testKey1)
+	rotated, _ := ParseKeyring("k2:" + testKey2 + ",k1:" + testKey1)
+
+	kekID, wrapped, err := old.Wrap([]byte("data key"), []byte("user"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := rotated.Unwrap(kekID, wrapped, []byte("user"))
+	if err != nil || string(got) != "data key" {
+		t.Errorf("Unwrap() got = %q, err = %v", got, err)
+	}
+	if _, err = rotated.Unwrap(kekID, wrapped, []byte("other")); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("Unwrap() with wrong ad error = %v, want %v", err, ErrDecrypt)
+	}
+	if _, err = rotated.Unwrap("k3", wrapped, []byte("user")); !errors.Is(err, ErrUnknownKEK) {
+		t.Errorf("Unwrap() with unknown kek error = %v, want %v", err, ErrUnknownKEK)
+	}
+}
+
+func TestDataKeys_Rewrap(t *testing.T) {
+	old, _ := ParseKeyring("k1:" + testKey1)
+	rotated, _ := ParseKeyring("k2:" + testKey2 + ",k1:" + testKey1)
+
+	userUUID := "0b1f3c3e-4d6a-4b8e-9f7a-2c1d5e6f7a8b"
+	_, wrapped, _ := old.Wrap(bytes.Repeat([]byte{7}, KeySize), []byte(userUUID))
+	stored := entity.DataKey{UserUUID: userUUID, KEKID: "k1", WrappedKey: wrapped}
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockKeyRepository(ctrl)
+	gomock.InOrder(
+		repo.EXPECT().ReadByOtherKEK(gomock.Any(), "k2", rewrapBatchSize).Times(1).Return([]entity.DataKey{stored}, nil),
+		repo.EXPECT().UpdateWrapped(gomock.Any(), gomock.Any(), "k1").Times(1).DoAndReturn(
+			func(_ context.Context, k entity.DataKey, _ string) (bool, error) {
+				stored = k
+				return true, nil
+			}),
+		repo.EXPECT().ReadByOtherKEK(gomock.Any(), "k2", rewrapBatchSize).Times(1).Return(nil, nil),
+	)
+
+	n, err := NewDataKeys(repo, rotated, log.MockLogger).Rewrap(context.Background())
+	if err != nil || n != 1 {
+		t.Fatalf("Rewrap() got = %v, err = %v", n, err)
+	}
+	if stored.KEKID != "k2" {
+		t.Errorf("Rewrap() kek_id = %v, want %v", stored.KEKID, "k2")
+	}
+
+	// После ротации ключ данных открывается только новым мастер-ключом.
+	newOnly, _ := ParseKeyring("k2:" + testKey2)
+	got, err := newOnly.Unwrap(stored.KEKID, stored.WrappedKey, []byte(userUUID))
+	if err != nil || !bytes.Equal(got, bytes.Repeat([]byte{7}, KeySize)) {
+		t.Errorf("Unwrap() after rewrap got = %v, err = %v", got, err)
+	}
+}
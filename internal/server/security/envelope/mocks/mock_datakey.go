@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ktigay/goph-keeper/internal/server/security/envelope (interfaces: KeyRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	entity "github.com/ktigay/goph-keeper/internal/entity"
+)
+
+// MockKeyRepository is a mock of KeyRepository interface.
+type MockKeyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockKeyRepositoryMockRecorder
+}
+
+// MockKeyRepositoryMockRecorder is the mock recorder for MockKeyRepository.
+type MockKeyRepositoryMockRecorder struct {
+	mock *MockKeyRepository
+}
+
+// NewMockKeyRepository creates a new mock instance.
+func NewMockKeyRepository(ctrl *gomock.Controller) *MockKeyRepository {
+	mock := &MockKeyRepository{ctrl: ctrl}
+	mock.recorder = &MockKeyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKeyRepository) EXPECT() *MockKeyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockKeyRepository) Create(arg0 context.Context, arg1 entity.DataKey) (*entity.DataKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(*entity.DataKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockKeyRepositoryMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockKeyRepository)(nil).Create), arg0, arg1)
+}
+
+// Read mocks base method.
+func (m *MockKeyRepository) Read(arg0 context.Context, arg1 string) (*entity.DataKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Read", arg0, arg1)
+	ret0, _ := ret[0].(*entity.DataKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Read indicates an expected call of Read.
+func (mr *MockKeyRepositoryMockRecorder) Read(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Read", reflect.TypeOf((*MockKeyRepository)(nil).Read), arg0, arg1)
+}
+
+// ReadByOtherKEK mocks base method.
+func (m *MockKeyRepository) ReadByOtherKEK(arg0 context.Context, arg1 string, arg2 int) ([]entity.DataKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadByOtherKEK", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]entity.DataKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadByOtherKEK indicates an expected call of ReadByOtherKEK.
+func (mr *MockKeyRepositoryMockRecorder) ReadByOtherKEK(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadByOtherKEK", reflect.TypeOf((*MockKeyRepository)(nil).ReadByOtherKEK), arg0, arg1, arg2)
+}
+
+// UpdateWrapped mocks base method.
+func (m *MockKeyRepository) UpdateWrapped(arg0 context.Context, arg1 entity.DataKey, arg2 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWrapped", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWrapped indicates an expected call of UpdateWrapped.
+func (mr *MockKeyRepositoryMockRecorder) UpdateWrapped(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWrapped", reflect.TypeOf((*MockKeyRepository)(nil).UpdateWrapped), arg0, arg1, arg2)
+}
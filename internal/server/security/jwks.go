@@ -0,0 +1,50 @@
+package security
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"encoding/base64"
+)
+
+// JWK открытый ключ в формате JSON Web Key (RFC 7517, RFC 8037).
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	X   string `json:"x"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JWKS открытые ключи набора для проверки токенов сторонними сервисами.
+// Симметричные ключи не публикуются.
+func (k *KeySet) JWKS() []JWK {
+	res := make([]JWK, 0, len(k.keys))
+	for _, key := range k.keys {
+		jwk := JWK{
+			Kid: key.ID,
+			Use: "sig",
+			Alg: key.Method.Alg(),
+		}
+		switch p := key.Public.(type) {
+		case ed25519.PublicKey:
+			jwk.Kty, jwk.Crv = "OKP", "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(p)
+		case *ecdsa.PublicKey:
+			// Несжатая точка: 0x04 || X || Y.
+			point, err := p.Bytes()
+			if err != nil {
+				continue
+			}
+			size := (len(point) - 1) / 2
+			jwk.Kty, jwk.Crv = "EC", "P-256"
+			jwk.X = base64.RawURLEncoding.EncodeToString(point[1 : 1+size])
+			jwk.Y = base64.RawURLEncoding.EncodeToString(point[1+size:])
+		default:
+			continue
+		}
+		res = append(res, jwk)
+	}
+	return res
+}
@@ -4,72 +4,115 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
-)
-
-const (
-	subjectKey = "sub"
+	"github.com/google/uuid"
 )
 
 // ErrMissingIdentity ошибка - нет идентификатора пользователя.
 var ErrMissingIdentity = fmt.Errorf("missing identity")
 
+const (
+	// AudienceAccess назначение access токена.
+	AudienceAccess = "goph-keeper:access"
+	// AudienceSecondFactor назначение токена входа, ожидающего второй фактор.
+	AudienceSecondFactor = "goph-keeper:second-factor"
+)
+
 // JWTWrapper Обертка для JWT.
 type JWTWrapper[T any] struct {
-	secret []byte
+	keys *KeySet
+	// audience назначение токена, не дает использовать токен одного вида вместо другого.
+	audience string
+	ttl      time.Duration
+	now      func() time.Time
 }
 
-// GenerateToken генерирует токен JWT.
+// GenerateToken генерирует токен JWT со сроком действия ttl, подписанный активным ключом набора.
 func (j *JWTWrapper[T]) GenerateToken(payload T) (string, error) {
 	v, err := json.Marshal(payload)
 	if err != nil {
 		return "", err
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		subjectKey: string(v),
+
+	now := j.now()
+	key, err := j.keys.Signing(now)
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(key.Method, jwt.RegisteredClaims{
+		Subject:   string(v),
+		Audience:  jwt.ClaimStrings{j.audience},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(j.ttl)),
+		ID:        uuid.NewString(),
 	})
-	return token.SignedString(j.secret)
+	if key.ID != "" {
+		token.Header["kid"] = key.ID
+	}
+	return token.SignedString(key.Private)
 }
 
-// ParseToken парсит токен JWT.
+// ParseToken парсит токен JWT. Токены без срока действия или с чужим назначением не принимаются.
+// Ключ проверки выбирается по kid, алгоритм токена должен совпадать с алгоритмом ключа.
 func (j *JWTWrapper[T]) ParseToken(s string) (*T, error) {
+	resp, _, err := j.ParseTokenExpiry(s)
+	return resp, err
+}
+
+// ParseTokenExpiry парсит токен JWT как [JWTWrapper.ParseToken] и возвращает срок его действия.
+func (j *JWTWrapper[T]) ParseTokenExpiry(s string) (*T, time.Time, error) {
 	s = strings.TrimPrefix(s, "Bearer ")
 	if len(s) == 0 {
-		return nil, ErrMissingIdentity
+		return nil, time.Time{}, ErrMissingIdentity
 	}
 
-	token, err := jwt.Parse(s, func(token *jwt.Token) (interface{}, error) {
-		return j.secret, nil
-	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
-	if err != nil {
-		return nil, err
-	}
-
-	var (
-		claims jwt.MapClaims
-		subj   string
-		ok     bool
+	claims := &jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(s, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, kErr := j.keys.Verification(kid)
+		if kErr != nil {
+			return nil, kErr
+		}
+		if token.Method.Alg() != key.Method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method %s for key %q", token.Method.Alg(), kid)
+		}
+		return key.Public, nil
+	},
+		jwt.WithValidMethods([]string{
+			jwt.SigningMethodEdDSA.Alg(),
+			jwt.SigningMethodES256.Alg(),
+			jwt.SigningMethodHS256.Alg(),
+		}),
+		jwt.WithAudience(j.audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithTimeFunc(j.now),
 	)
-	if claims, ok = token.Claims.(jwt.MapClaims); !ok {
-		return nil, ErrMissingIdentity
+	if err != nil {
+		return nil, time.Time{}, err
 	}
 
-	if subj, err = claims.GetSubject(); err != nil {
-		return nil, ErrMissingIdentity
+	if claims.Subject == "" {
+		return nil, time.Time{}, ErrMissingIdentity
 	}
 
 	var resp T
-	if err = json.Unmarshal([]byte(subj), &resp); err != nil {
-		return nil, err
+	if err = json.Unmarshal([]byte(claims.Subject), &resp); err != nil {
+		return nil, time.Time{}, err
 	}
 
-	return &resp, nil
+	return &resp, claims.ExpiresAt.Time, nil
 }
 
 // NewJWTWrapper Конструктор.
-func NewJWTWrapper[T any](secret string) *JWTWrapper[T] {
+func NewJWTWrapper[T any](keys *KeySet, audience string, ttl time.Duration) *JWTWrapper[T] {
 	return &JWTWrapper[T]{
-		secret: []byte(secret),
+		keys:     keys,
+		audience: audience,
+		ttl:      ttl,
+		now:      time.Now,
 	}
 }
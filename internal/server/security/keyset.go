@@ -0,0 +1,191 @@
+package security
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// activateAtHeader PEM заголовок с моментом, начиная с которого ключ используется для подписи (RFC 3339).
+const activateAtHeader = "Activate-At"
+
+var (
+	// ErrNoSigningKey ошибка - нет ключа подписи, активного на текущий момент.
+	ErrNoSigningKey = errors.New("no active signing key")
+	// ErrUnknownKey ошибка - токен подписан неизвестным ключом.
+	ErrUnknownKey = errors.New("unknown signing key")
+)
+
+// SigningKey ключ подписи токенов.
+type SigningKey struct {
+	// ID идентификатор ключа, передается в заголовке kid.
+	ID     string
+	Method jwt.SigningMethod
+	// Private закрытый ключ, nil для ключей, оставленных только для проверки.
+	Private any
+	Public  any
+	// ActivateAt момент, начиная с которого ключ используется для подписи.
+	ActivateAt time.Time
+}
+
+// KeySet набор ключей: ключ подписи выбирается по времени, проверка - любым ключом набора.
+type KeySet struct {
+	// keys отсортированы по ActivateAt.
+	keys []SigningKey
+	byID map[string]*SigningKey
+}
+
+// Signing возвращает ключ подписи, активный на момент now: ключ с закрытой частью и наибольшим ActivateAt <= now.
+func (k *KeySet) Signing(now time.Time) (*SigningKey, error) {
+	for i := len(k.keys) - 1; i >= 0; i-- {
+		key := &k.keys[i]
+		if key.Private != nil && !key.ActivateAt.After(now) {
+			return key, nil
+		}
+	}
+	return nil, ErrNoSigningKey
+}
+
+// Verification возвращает ключ проверки по kid.
+func (k *KeySet) Verification(kid string) (*SigningKey, error) {
+	key, ok := k.byID[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, kid)
+	}
+	return key, nil
+}
+
+// Keys возвращает все ключи набора.
+func (k *KeySet) Keys() []SigningKey {
+	return k.keys
+}
+
+// NewKeySet конструктор.
+func NewKeySet(keys []SigningKey) (*KeySet, error) {
+	set := &KeySet{
+		keys: make([]SigningKey, len(keys)),
+		byID: make(map[string]*SigningKey, len(keys)),
+	}
+	copy(set.keys, keys)
+	sort.SliceStable(set.keys, func(i, j int) bool {
+		return set.keys[i].ActivateAt.Before(set.keys[j].ActivateAt)
+	})
+
+	for i := range set.keys {
+		key := &set.keys[i]
+		if _, ok := set.byID[key.ID]; ok {
+			return nil, fmt.Errorf("duplicate key id %q", key.ID)
+		}
+		set.byID[key.ID] = key
+	}
+	return set, nil
+}
+
+// NewSecretKeySet набор из одного общего секрета HS256 без kid.
+func NewSecretKeySet(secret string) *KeySet {
+	set, _ := NewKeySet([]SigningKey{{
+		Method:  jwt.SigningMethodHS256,
+		Private: []byte(secret),
+		Public:  []byte(secret),
+	}})
+	return set
+}
+
+// LoadKeyDir загружает ключи из каталога: каждый файл *.pem - один ключ, kid - имя файла без расширения.
+// Файл с PRIVATE KEY (PKCS #8, Ed25519 или ECDSA P-256) используется для подписи и проверки,
+// файл с PUBLIC KEY - только для проверки токенов, подписанных выведенным из оборота ключом.
+// Необязательный PEM заголовок Activate-At задает момент перехода подписи на ключ.
+func LoadKeyDir(dir string) (*KeySet, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no *.pem keys in %s", dir)
+	}
+
+	keys := make([]SigningKey, 0, len(files))
+	for _, file := range files {
+		var key *SigningKey
+		if key, err = loadKeyFile(file); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		keys = append(keys, *key)
+	}
+
+	return NewKeySet(keys)
+}
+
+func loadKeyFile(file string) (*SigningKey, error) {
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, errors.New("no PEM block")
+	}
+
+	key := &SigningKey{
+		ID: strings.TrimSuffix(filepath.Base(file), filepath.Ext(file)),
+	}
+	if v, ok := block.Headers[activateAtHeader]; ok {
+		if key.ActivateAt, err = time.Parse(time.RFC3339, v); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", activateAtHeader, err)
+		}
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		var private any
+		if private, err = x509.ParsePKCS8PrivateKey(block.Bytes); err != nil {
+			return nil, err
+		}
+		switch p := private.(type) {
+		case ed25519.PrivateKey:
+			key.Private, key.Public = p, p.Public()
+		case *ecdsa.PrivateKey:
+			key.Private, key.Public = p, &p.PublicKey
+		default:
+			return nil, fmt.Errorf("unsupported private key type %T", private)
+		}
+	case "PUBLIC KEY":
+		if key.Public, err = x509.ParsePKIXPublicKey(block.Bytes); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+
+	if key.Method, err = signingMethod(key.Public); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// signingMethod алгоритм подписи по типу открытого ключа.
+func signingMethod(public any) (jwt.SigningMethod, error) {
+	switch p := public.(type) {
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	case *ecdsa.PublicKey:
+		if p.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("unsupported curve %s, want P-256", p.Curve.Params().Name)
+		}
+		return jwt.SigningMethodES256, nil
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", public)
+	}
+}
@@ -0,0 +1,275 @@
+package security
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/ktigay/goph-keeper/internal/entity"
+)
+
+func writeKey(t *testing.T, dir, kid, blockType string, der []byte, activateAt string) {
+	t.Helper()
+
+	block := &pem.Block{Type: blockType, Bytes: der}
+	if activateAt != "" {
+		block.Headers = map[string]string{activateAtHeader: activateAt}
+	}
+	if err := os.WriteFile(filepath.Join(dir, kid+".pem"), pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeEd25519Key(t *testing.T, dir, kid, activateAt string) ed25519.PrivateKey {
+	t.Helper()
+
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeKey(t, dir, kid, "PRIVATE KEY", der, activateAt)
+	return private
+}
+
+func writeECDSAKey(t *testing.T, dir, kid, activateAt string) *ecdsa.PrivateKey {
+	t.Helper()
+
+	private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeKey(t, dir, kid, "PRIVATE KEY", der, activateAt)
+	return private
+}
+
+func tokenKid(t *testing.T, s string) string {
+	t.Helper()
+
+	token, _, err := jwt.NewParser().ParseUnverified(s, &jwt.RegisteredClaims{})
+	if err != nil {
+		t.Fatalf("ParseUnverified() error = %v", err)
+	}
+	kid, _ := token.Header["kid"].(string)
+	return kid
+}
+
+func TestLoadKeyDir_ScheduledSwitchOver(t *testing.T) {
+	dir := t.TempDir()
+	writeEd25519Key(t, dir, "2024-01", "")
+	writeECDSAKey(t, dir, "2024-06", "2024-06-01T00:00:00Z")
+
+	keys, err := LoadKeyDir(dir)
+	if err != nil {
+		t.Fatalf("LoadKeyDir() error = %v", err)
+	}
+
+	j := NewJWTWrapper[entity.Identity](keys, AudienceAccess, time.Hour)
+	payload := entity.Identity{UUID: "user-uuid", SessionUUID: "session-uuid"}
+
+	j.now = func() time.Time { return time.Date(2024, 5, 31, 23, 30, 0, 0, time.UTC) }
+	before, err := j.GenerateToken(payload)
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+	if kid := tokenKid(t, before); kid != "2024-01" {
+		t.Errorf("GenerateToken() kid = %q, want 2024-01 before switch-over", kid)
+	}
+
+	j.now = func() time.Time { return time.Date(2024, 6, 1, 0, 10, 0, 0, time.UTC) }
+	after, err := j.GenerateToken(payload)
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+	if kid := tokenKid(t, after); kid != "2024-06" {
+		t.Errorf("GenerateToken() kid = %q, want 2024-06 after switch-over", kid)
+	}
+
+	// Токены, подписанные предыдущим ключом, продолжают действовать до истечения срока.
+	for _, s := range []string{before, after} {
+		if _, err = j.ParseToken(s); err != nil {
+			t.Errorf("ParseToken() error = %v", err)
+		}
+	}
+}
+
+func TestLoadKeyDir_VerificationOnlyKey(t *testing.T) {
+	signDir, verifyDir := t.TempDir(), t.TempDir()
+	retired := writeEd25519Key(t, signDir, "retired", "")
+	writeEd25519Key(t, verifyDir, "current", "")
+
+	der, err := x509.MarshalPKIXPublicKey(retired.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeKey(t, verifyDir, "retired", "PUBLIC KEY", der, "")
+
+	signKeys, err := LoadKeyDir(signDir)
+	if err != nil {
+		t.Fatalf("LoadKeyDir() error = %v", err)
+	}
+	verifyKeys, err := LoadKeyDir(verifyDir)
+	if err != nil {
+		t.Fatalf("LoadKeyDir() error = %v", err)
+	}
+
+	s, err := NewJWTWrapper[entity.Identity](signKeys, AudienceAccess, time.Hour).GenerateToken(entity.Identity{UUID: "user-uuid"})
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+
+	j := NewJWTWrapper[entity.Identity](verifyKeys, AudienceAccess, time.Hour)
+	if _, err = j.ParseToken(s); err != nil {
+		t.Errorf("ParseToken() error = %v", err)
+	}
+	if key, _ := verifyKeys.Signing(time.Now()); key.ID != "current" {
+		t.Errorf("Signing() = %q, public key must not be used for signing", key.ID)
+	}
+}
+
+func TestJWTWrapper_ParseToken_RejectsForeignTokens(t *testing.T) {
+	dir := t.TempDir()
+	private := writeEd25519Key(t, dir, "k1", "")
+
+	keys, err := LoadKeyDir(dir)
+	if err != nil {
+		t.Fatalf("LoadKeyDir() error = %v", err)
+	}
+	j := NewJWTWrapper[entity.Identity](keys, AudienceAccess, time.Hour)
+
+	claims := jwt.RegisteredClaims{
+		Subject:   `{"UUID":"user-uuid"}`,
+		Audience:  jwt.ClaimStrings{AudienceAccess},
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}
+
+	// HS256 токен, подписанный открытым ключом как секретом.
+	hmac := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	hmac.Header["kid"] = "k1"
+	hmacToken, err := hmac.SignedString([]byte(private.Public().(ed25519.PublicKey)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unknown := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	unknown.Header["kid"] = "k2"
+	unknownToken, err := unknown.SignedString(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	challenge, err := NewJWTWrapper[entity.Identity](keys, AudienceSecondFactor, time.Hour).GenerateToken(entity.Identity{UUID: "user-uuid"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{name: "Algorithm_Confusion", token: hmacToken},
+		{name: "Unknown_Kid", token: unknownToken, wantErr: ErrUnknownKey},
+		{name: "Other_Audience", token: challenge, wantErr: jwt.ErrTokenInvalidAudience},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := j.ParseToken(tt.token)
+			if err == nil {
+				t.Fatalf("ParseToken() error = nil, want error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("ParseToken() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadKeyDir_Errors(t *testing.T) {
+	t.Run("Empty_Dir", func(t *testing.T) {
+		if _, err := LoadKeyDir(t.TempDir()); err == nil {
+			t.Errorf("LoadKeyDir() error = nil, want error")
+		}
+	})
+	t.Run("Unsupported_Curve", func(t *testing.T) {
+		dir := t.TempDir()
+		private, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		der, err := x509.MarshalPKCS8PrivateKey(private)
+		if err != nil {
+			t.Fatal(err)
+		}
+		writeKey(t, dir, "p384", "PRIVATE KEY", der, "")
+		if _, err = LoadKeyDir(dir); err == nil {
+			t.Errorf("LoadKeyDir() error = nil, want error")
+		}
+	})
+	t.Run("Invalid_Activate_At", func(t *testing.T) {
+		dir := t.TempDir()
+		writeEd25519Key(t, dir, "k1", "tomorrow")
+		if _, err := LoadKeyDir(dir); err == nil {
+			t.Errorf("LoadKeyDir() error = nil, want error")
+		}
+	})
+	t.Run("No_Active_Signing_Key", func(t *testing.T) {
+		dir := t.TempDir()
+		writeEd25519Key(t, dir, "k1", "2999-01-01T00:00:00Z")
+		keys, err := LoadKeyDir(dir)
+		if err != nil {
+			t.Fatalf("LoadKeyDir() error = %v", err)
+		}
+		if _, err = keys.Signing(time.Now()); !errors.Is(err, ErrNoSigningKey) {
+			t.Errorf("Signing() error = %v, want %v", err, ErrNoSigningKey)
+		}
+	})
+}
+
+func TestKeySet_JWKS(t *testing.T) {
+	dir := t.TempDir()
+	ed := writeEd25519Key(t, dir, "ed", "")
+	writeECDSAKey(t, dir, "ec", "2024-06-01T00:00:00Z")
+
+	keys, err := LoadKeyDir(dir)
+	if err != nil {
+		t.Fatalf("LoadKeyDir() error = %v", err)
+	}
+
+	got := keys.JWKS()
+	if len(got) != 2 {
+		t.Fatalf("JWKS() got %d keys, want 2", len(got))
+	}
+	if got[0].Kid != "ed" || got[0].Kty != "OKP" || got[0].Alg != "EdDSA" || got[0].Y != "" {
+		t.Errorf("JWKS() got[0] = %+v", got[0])
+	}
+	if x, _ := base64.RawURLEncoding.DecodeString(got[0].X); !ed.Public().(ed25519.PublicKey).Equal(ed25519.PublicKey(x)) {
+		t.Errorf("JWKS() got[0].X = %q, want the public key", got[0].X)
+	}
+	if got[1].Kid != "ec" || got[1].Kty != "EC" || got[1].Crv != "P-256" || got[1].Alg != "ES256" || len(got[1].X) != 43 || len(got[1].Y) != 43 {
+		t.Errorf("JWKS() got[1] = %+v", got[1])
+	}
+
+	if len(NewSecretKeySet("secret").JWKS()) != 0 {
+		t.Errorf("JWKS() must not publish symmetric keys")
+	}
+}
@@ -0,0 +1,156 @@
+// Package password хеширует пароли для входа по паролю (устаревший способ, см. SRP).
+//
+// Хеши хранятся в формате PHC: $argon2id$v=19$m=<KiB>,t=<итерации>,p=<потоки>$<соль>$<хеш>.
+// Хеши bcrypt ($2a$, $2b$, $2y$), созданные до перехода на Argon2id, проверяются и пересчитываются при входе.
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	saltSize = 16
+	keySize  = 32
+
+	argon2idPrefix = "$argon2id$"
+)
+
+var (
+	// ErrUnknownHash хеш в неизвестном формате.
+	ErrUnknownHash = errors.New("unknown password hash format")
+	// ErrInvalidParams недопустимые параметры Argon2id.
+	ErrInvalidParams = errors.New("invalid argon2id parameters")
+
+	b64 = base64.RawStdEncoding
+)
+
+// Params параметры Argon2id.
+type Params struct {
+	// Time количество проходов.
+	Time uint32
+	// Memory объём памяти, КиБ.
+	Memory uint32
+	// Threads степень параллелизма.
+	Threads uint8
+}
+
+// Validate проверяет параметры.
+func (p Params) Validate() error {
+	if p.Time < 1 || p.Threads < 1 || p.Memory < 8*uint32(p.Threads) {
+		return fmt.Errorf("%w: t=%d, m=%d, p=%d", ErrInvalidParams, p.Time, p.Memory, p.Threads)
+	}
+	return nil
+}
+
+// DefaultParams параметры по умолчанию (RFC 9106, второй рекомендованный набор).
+func DefaultParams() Params {
+	return Params{
+		Time:    3,
+		Memory:  64 * 1024,
+		Threads: 4,
+	}
+}
+
+// Hasher хеширует пароли Argon2id с текущими параметрами.
+type Hasher struct {
+	params Params
+	// dummy хеш для сравнения, когда пользователь не найден,
+	// чтобы время ответа не выдавало существование логина.
+	dummy     string
+	dummyOnce sync.Once
+}
+
+// Hash хеширует пароль текущими параметрами.
+func (h *Hasher) Hash(password string) (string, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, h.params.Time, h.params.Memory, h.params.Threads, keySize)
+
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idPrefix, argon2.Version, h.params.Memory, h.params.Time, h.params.Threads,
+		b64.EncodeToString(salt), b64.EncodeToString(key),
+	), nil
+}
+
+// Verify проверяет пароль по хешу Argon2id или bcrypt.
+func (h *Hasher) Verify(encoded, password string) (bool, error) {
+	if isBcrypt(encoded) {
+		err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+
+	p, salt, key, err := decode(encoded)
+	if err != nil {
+		return false, err
+	}
+	got := argon2.IDKey([]byte(password), salt, p.Time, p.Memory, p.Threads, uint32(len(key)))
+	return subtle.ConstantTimeCompare(got, key) == 1, nil
+}
+
+// VerifyDummy вычисляет хеш так же долго, как [Hasher.Verify], для несуществующего пользователя.
+func (h *Hasher) VerifyDummy(password string) {
+	h.dummyOnce.Do(func() {
+		h.dummy, _ = h.Hash("dummy password")
+	})
+	_, _ = h.Verify(h.dummy, password)
+}
+
+// NeedsRehash возвращает true, если хеш создан другим алгоритмом или с другими параметрами.
+func (h *Hasher) NeedsRehash(encoded string) bool {
+	p, _, key, err := decode(encoded)
+	return err != nil || p != h.params || len(key) != keySize
+}
+
+// decode разбирает хеш Argon2id: $argon2id$v=19$m=65536,t=3,p=4$соль$хеш.
+func decode(encoded string) (Params, []byte, []byte, error) {
+	var (
+		p       Params
+		version int
+	)
+
+	parts := strings.Split(strings.TrimPrefix(encoded, argon2idPrefix), "$")
+	if !strings.HasPrefix(encoded, argon2idPrefix) || len(parts) != 4 {
+		return p, nil, nil, ErrUnknownHash
+	}
+	if _, err := fmt.Sscanf(parts[0], "v=%d", &version); err != nil || version != argon2.Version {
+		return p, nil, nil, ErrUnknownHash
+	}
+	if _, err := fmt.Sscanf(parts[1], "m=%d,t=%d,p=%d", &p.Memory, &p.Time, &p.Threads); err != nil || p.Validate() != nil {
+		return p, nil, nil, ErrUnknownHash
+	}
+	salt, err := b64.DecodeString(parts[2])
+	if err != nil {
+		return p, nil, nil, ErrUnknownHash
+	}
+	key, err := b64.DecodeString(parts[3])
+	if err != nil || len(key) == 0 {
+		return p, nil, nil, ErrUnknownHash
+	}
+	return p, salt, key, nil
+}
+
+func isBcrypt(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") || strings.HasPrefix(encoded, "$2b$") || strings.HasPrefix(encoded, "$2y$")
+}
+
+// New конструктор.
+func New(p Params) (*Hasher, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return &Hasher{params: p}, nil
+}
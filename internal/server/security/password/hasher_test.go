@@ -0,0 +1,88 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+var testParams = Params{Time: 1, Memory: 64, Threads: 1}
+
+func TestHasher_Verify(t *testing.T) {
+	h, err := New(testParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	argonHash, err := h.Hash("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		encoded    string
+		password   string
+		want       bool
+		wantErr    error
+		wantRehash bool
+	}{
+		{name: "Argon2id_Match", encoded: argonHash, password: "password", want: true},
+		{name: "Argon2id_Mismatch", encoded: argonHash, password: "other"},
+		{name: "Bcrypt_Match", encoded: string(bcryptHash), password: "password", want: true, wantRehash: true},
+		{name: "Bcrypt_Mismatch", encoded: string(bcryptHash), password: "other", wantRehash: true},
+		{name: "Unknown_Format", encoded: "plain", password: "password", wantErr: ErrUnknownHash, wantRehash: true},
+		{name: "Broken_Argon2id", encoded: "$argon2id$v=19$m=64,t=1,p=1$!!$!!", password: "password", wantErr: ErrUnknownHash, wantRehash: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := h.Verify(tt.encoded, tt.password)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Verify() got = %v, want %v", got, tt.want)
+			}
+			if rehash := h.NeedsRehash(tt.encoded); rehash != tt.wantRehash {
+				t.Errorf("NeedsRehash() got = %v, want %v", rehash, tt.wantRehash)
+			}
+		})
+	}
+}
+
+func TestHasher_NeedsRehash_Params(t *testing.T) {
+	old, _ := New(testParams)
+	current, _ := New(Params{Time: 2, Memory: 64, Threads: 1})
+
+	encoded, err := old.Hash("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(encoded, "$argon2id$v=19$m=64,t=1,p=1$") {
+		t.Errorf("Hash() got = %q", encoded)
+	}
+	if !current.NeedsRehash(encoded) {
+		t.Error("NeedsRehash() must be true when parameters changed")
+	}
+	// Хеш со старыми параметрами по-прежнему проверяется.
+	if ok, err := current.Verify(encoded, "password"); !ok || err != nil {
+		t.Errorf("Verify() got = %v, err = %v", ok, err)
+	}
+}
+
+func TestParams_Validate(t *testing.T) {
+	if _, err := New(Params{Time: 0, Memory: 64, Threads: 1}); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("New() error = %v, want %v", err, ErrInvalidParams)
+	}
+	if _, err := New(Params{Time: 1, Memory: 8, Threads: 4}); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("New() error = %v, want %v", err, ErrInvalidParams)
+	}
+	if err := DefaultParams().Validate(); err != nil {
+		t.Errorf("DefaultParams().Validate() error = %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ktigay/goph-keeper/internal/server/service/account (interfaces: PasswordVerifier)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPasswordVerifier is a mock of PasswordVerifier interface.
+type MockPasswordVerifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockPasswordVerifierMockRecorder
+}
+
+// MockPasswordVerifierMockRecorder is the mock recorder for MockPasswordVerifier.
+type MockPasswordVerifierMockRecorder struct {
+	mock *MockPasswordVerifier
+}
+
+// NewMockPasswordVerifier creates a new mock instance.
+func NewMockPasswordVerifier(ctrl *gomock.Controller) *MockPasswordVerifier {
+	mock := &MockPasswordVerifier{ctrl: ctrl}
+	mock.recorder = &MockPasswordVerifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPasswordVerifier) EXPECT() *MockPasswordVerifierMockRecorder {
+	return m.recorder
+}
+
+// Verify mocks base method.
+func (m *MockPasswordVerifier) Verify(arg0, arg1 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockPasswordVerifierMockRecorder) Verify(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockPasswordVerifier)(nil).Verify), arg0, arg1)
+}
@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ktigay/goph-keeper/internal/server/service/account (interfaces: ProofVerifier)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	entity "github.com/ktigay/goph-keeper/internal/server/entity"
+)
+
+// MockProofVerifier is a mock of ProofVerifier interface.
+type MockProofVerifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockProofVerifierMockRecorder
+}
+
+// MockProofVerifierMockRecorder is the mock recorder for MockProofVerifier.
+type MockProofVerifierMockRecorder struct {
+	mock *MockProofVerifier
+}
+
+// NewMockProofVerifier creates a new mock instance.
+func NewMockProofVerifier(ctrl *gomock.Controller) *MockProofVerifier {
+	mock := &MockProofVerifier{ctrl: ctrl}
+	mock.recorder = &MockProofVerifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProofVerifier) EXPECT() *MockProofVerifierMockRecorder {
+	return m.recorder
+}
+
+// Finish mocks base method.
+func (m *MockProofVerifier) Finish(arg0 context.Context, arg1 string, arg2 []byte) (*entity.SRPHandshake, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Finish", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*entity.SRPHandshake)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Finish indicates an expected call of Finish.
+func (mr *MockProofVerifierMockRecorder) Finish(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Finish", reflect.TypeOf((*MockProofVerifier)(nil).Finish), arg0, arg1, arg2)
+}
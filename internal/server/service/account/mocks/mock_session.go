@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ktigay/goph-keeper/internal/server/service/account (interfaces: SessionRevoker)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockSessionRevoker is a mock of SessionRevoker interface.
+type MockSessionRevoker struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionRevokerMockRecorder
+}
+
+// MockSessionRevokerMockRecorder is the mock recorder for MockSessionRevoker.
+type MockSessionRevokerMockRecorder struct {
+	mock *MockSessionRevoker
+}
+
+// NewMockSessionRevoker creates a new mock instance.
+func NewMockSessionRevoker(ctrl *gomock.Controller) *MockSessionRevoker {
+	mock := &MockSessionRevoker{ctrl: ctrl}
+	mock.recorder = &MockSessionRevokerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSessionRevoker) EXPECT() *MockSessionRevokerMockRecorder {
+	return m.recorder
+}
+
+// RevokeAllOther mocks base method.
+func (m *MockSessionRevoker) RevokeAllOther(arg0 context.Context, arg1, arg2 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAllOther", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeAllOther indicates an expected call of RevokeAllOther.
+func (mr *MockSessionRevokerMockRecorder) RevokeAllOther(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAllOther", reflect.TypeOf((*MockSessionRevoker)(nil).RevokeAllOther), arg0, arg1, arg2)
+}
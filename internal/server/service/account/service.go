@@ -0,0 +1,272 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	e "github.com/ktigay/goph-keeper/internal/entity"
+	"github.com/ktigay/goph-keeper/internal/server/entity"
+	authsrv "github.com/ktigay/goph-keeper/internal/server/service/auth"
+	"github.com/ktigay/goph-keeper/internal/srp"
+)
+
+const (
+	// purgeBatch сколько аккаунтов удаляется за один проход.
+	purgeBatch = 100
+)
+
+var (
+	// ErrUserNotFound пользователь не найден.
+	ErrUserNotFound = errors.New("user not found")
+	// ErrWrongPassword неправильный текущий пароль.
+	ErrWrongPassword = errors.New("wrong password")
+	// ErrPasswordEmpty новый пароль (верификатор SRP) пустой.
+	ErrPasswordEmpty = errors.New("password is empty")
+	// ErrInvalidVerifier некорректный верификатор SRP.
+	ErrInvalidVerifier = errors.New("invalid srp verifier")
+	// ErrDeletionNotRequested удаление аккаунта не запрошено.
+	ErrDeletionNotRequested = errors.New("account deletion is not requested")
+)
+
+// UserRepository репозиторий пользователей.
+//
+//go:generate mockgen -destination=./mocks/mock_user.go -package=mocks github.com/ktigay/goph-keeper/internal/server/service/account UserRepository
+type UserRepository interface {
+	ReadByUUID(ctx context.Context, uuid string) (*entity.User, error)
+	UpdateVerifier(ctx context.Context, uuid string, salt, verifier []byte) (bool, error)
+	ScheduleDeletion(ctx context.Context, uuid string, at time.Time) (bool, error)
+	CancelDeletion(ctx context.Context, uuid string) (bool, error)
+	ListDueForDeletion(ctx context.Context, now time.Time, limit int) ([]string, error)
+	Purge(ctx context.Context, uuid string, now time.Time) (bool, error)
+}
+
+// AuditRepository репозиторий аудита.
+//
+//go:generate mockgen -destination=./mocks/mock_audit.go -package=mocks github.com/ktigay/goph-keeper/internal/server/service/account AuditRepository
+type AuditRepository interface {
+	Create(ctx context.Context, rec entity.AuditRecord) error
+}
+
+// SessionRevoker отзыв сессий.
+//
+//go:generate mockgen -destination=./mocks/mock_session.go -package=mocks github.com/ktigay/goph-keeper/internal/server/service/account SessionRevoker
+type SessionRevoker interface {
+	RevokeAllOther(ctx context.Context, userUUID, currentUUID string) (int64, error)
+}
+
+// ProofVerifier проверка доказательства пароля SRP.
+//
+//go:generate mockgen -destination=./mocks/mock_proof.go -package=mocks github.com/ktigay/goph-keeper/internal/server/service/account ProofVerifier
+type ProofVerifier interface {
+	Finish(ctx context.Context, handshakeID string, proof []byte) (*entity.SRPHandshake, error)
+}
+
+// PasswordVerifier проверка пароля по хешу для пользователей без верификатора SRP.
+//
+//go:generate mockgen -destination=./mocks/mock_password.go -package=mocks github.com/ktigay/goph-keeper/internal/server/service/account PasswordVerifier
+type PasswordVerifier interface {
+	Verify(encoded, password string) (bool, error)
+}
+
+// TxFacade транзакции.
+//
+//go:generate mockgen -destination=./mocks/mock_tx.go -package=mocks github.com/ktigay/goph-keeper/internal/server/service/account TxFacade
+type TxFacade interface {
+	RunInTx(ctx context.Context, opts pgx.TxOptions, fn func(ctxWithTx context.Context) error) error
+}
+
+// Service сервис управления аккаунтом: смена пароля и удаление.
+type Service struct {
+	users    UserRepository
+	audit    AuditRepository
+	sessions SessionRevoker
+	proofs   ProofVerifier
+	hasher   PasswordVerifier
+	tx       TxFacade
+	grace    time.Duration
+	now      func() time.Time
+}
+
+// ChangePassword меняет пароль и отзывает все сессии, кроме текущей. Возвращает количество отозванных.
+// Новый пароль передается только верификатором SRP, пароль без верификатора при этом переводится на SRP.
+func (s *Service) ChangePassword(ctx context.Context, identity e.Identity, current entity.PasswordProof, salt, verifier []byte, ip string) (int64, error) {
+	if err := checkVerifier(salt, verifier); err != nil {
+		return 0, err
+	}
+
+	if err := s.checkPassword(ctx, identity.UUID, current); err != nil {
+		return 0, err
+	}
+
+	var revoked int64
+	err := s.tx.RunInTx(ctx, pgx.TxOptions{}, func(ctx context.Context) error {
+		ok, err := s.users.UpdateVerifier(ctx, identity.UUID, salt, verifier)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrUserNotFound
+		}
+		if revoked, err = s.sessions.RevokeAllOther(ctx, identity.UUID, identity.SessionUUID); err != nil {
+			return err
+		}
+		return s.audit.Create(ctx, entity.AuditRecord{UserUUID: identity.UUID, Event: entity.AuditPasswordChanged, IP: ip})
+	})
+	if err != nil {
+		return 0, err
+	}
+	return revoked, nil
+}
+
+// RequestDeletion назначает удаление аккаунта по истечении периода ожидания.
+// До этого момента удаление можно отменить [Service.CancelDeletion].
+func (s *Service) RequestDeletion(ctx context.Context, userUUID string, current entity.PasswordProof, ip string) (time.Time, error) {
+	if err := s.checkPassword(ctx, userUUID, current); err != nil {
+		return time.Time{}, err
+	}
+
+	deleteAfter := s.now().Add(s.grace).UTC()
+	err := s.tx.RunInTx(ctx, pgx.TxOptions{}, func(ctx context.Context) error {
+		ok, err := s.users.ScheduleDeletion(ctx, userUUID, deleteAfter)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrUserNotFound
+		}
+		return s.audit.Create(ctx, entity.AuditRecord{UserUUID: userUUID, Event: entity.AuditDeletionRequested, IP: ip})
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	return deleteAfter, nil
+}
+
+// UpgradeToSRP сохраняет верификатор SRP пользователя, вошедшего по паролю, хеш пароля удаляется.
+// Пароль должен быть проверен вызывающей стороной.
+func (s *Service) UpgradeToSRP(ctx context.Context, userUUID string, salt, verifier []byte, ip string) error {
+	if err := checkVerifier(salt, verifier); err != nil {
+		return err
+	}
+
+	return s.tx.RunInTx(ctx, pgx.TxOptions{}, func(ctx context.Context) error {
+		ok, err := s.users.UpdateVerifier(ctx, userUUID, salt, verifier)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrUserNotFound
+		}
+		return s.audit.Create(ctx, entity.AuditRecord{UserUUID: userUUID, Event: entity.AuditSRPEnabled, IP: ip})
+	})
+}
+
+// CancelDeletion отменяет запрошенное удаление аккаунта.
+func (s *Service) CancelDeletion(ctx context.Context, userUUID, ip string) error {
+	return s.tx.RunInTx(ctx, pgx.TxOptions{}, func(ctx context.Context) error {
+		ok, err := s.users.CancelDeletion(ctx, userUUID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrDeletionNotRequested
+		}
+		return s.audit.Create(ctx, entity.AuditRecord{UserUUID: userUUID, Event: entity.AuditDeletionCancelled, IP: ip})
+	})
+}
+
+// PurgeDue удаляет аккаунты, срок удаления которых наступил. Возвращает количество удаленных.
+// Каждый аккаунт со всеми данными удаляется в отдельной транзакции вместе с записью аудита.
+func (s *Service) PurgeDue(ctx context.Context) (int, error) {
+	now := s.now()
+
+	uuids, err := s.users.ListDueForDeletion(ctx, now, purgeBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	var purged int
+	for _, uuid := range uuids {
+		var deleted bool
+		err = s.tx.RunInTx(ctx, pgx.TxOptions{}, func(ctx context.Context) error {
+			var err error
+			if deleted, err = s.users.Purge(ctx, uuid, now); err != nil || !deleted {
+				return err
+			}
+			return s.audit.Create(ctx, entity.AuditRecord{UserUUID: uuid, Event: entity.AuditAccountDeleted})
+		})
+		if err != nil {
+			return purged, err
+		}
+		if deleted {
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// checkPassword проверяет доказательство SRP или, для пользователя без верификатора, пароль.
+func (s *Service) checkPassword(ctx context.Context, userUUID string, current entity.PasswordProof) error {
+	if current.HandshakeID != "" {
+		h, err := s.proofs.Finish(ctx, current.HandshakeID, current.Proof)
+		switch {
+		case errors.Is(err, authsrv.ErrWrongPassword), errors.Is(err, authsrv.ErrUserNotFound):
+			return ErrWrongPassword
+		case err != nil:
+			return err
+		case h.UserUUID != userUUID:
+			// Обмен начат для другого пользователя.
+			return ErrWrongPassword
+		}
+		return nil
+	}
+
+	usr, err := s.users.ReadByUUID(ctx, userUUID)
+	if err != nil {
+		return err
+	}
+	if usr == nil {
+		return ErrUserNotFound
+	}
+	if usr.Password == "" {
+		// Пользователь перешел на SRP, пароль не принимается.
+		return ErrWrongPassword
+	}
+
+	match, err := s.hasher.Verify(usr.Password, strings.TrimSpace(current.Password))
+	if err != nil {
+		return err
+	}
+	if !match {
+		return ErrWrongPassword
+	}
+	return nil
+}
+
+func checkVerifier(salt, verifier []byte) error {
+	if len(salt) == 0 || len(verifier) == 0 {
+		return ErrPasswordEmpty
+	}
+	if srp.CheckVerifier(verifier) != nil {
+		return ErrInvalidVerifier
+	}
+	return nil
+}
+
+// New конструктор.
+func New(u UserRepository, a AuditRepository, sess SessionRevoker, p ProofVerifier, h PasswordVerifier, tx TxFacade, grace time.Duration) *Service {
+	return &Service{
+		users:    u,
+		audit:    a,
+		sessions: sess,
+		proofs:   p,
+		hasher:   h,
+		tx:       tx,
+		grace:    grace,
+		now:      time.Now,
+	}
+}
@@ -0,0 +1,290 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/crypto/bcrypt"
+
+	e "github.com/ktigay/goph-keeper/internal/entity"
+	"github.com/ktigay/goph-keeper/internal/server/entity"
+	"github.com/ktigay/goph-keeper/internal/server/security/password"
+	"github.com/ktigay/goph-keeper/internal/server/service/account/mocks"
+	authsrv "github.com/ktigay/goph-keeper/internal/server/service/auth"
+	"github.com/ktigay/goph-keeper/internal/srp"
+)
+
+const (
+	userUUID    = "33b06619-1ee7-3db5-827d-0dc85df1f759"
+	sessionUUID = "7b1c0a52-5a3e-4e0b-9d55-2f3f1d3c6a10"
+)
+
+func passthroughTx(ctrl *gomock.Controller) *mocks.MockTxFacade {
+	tx := mocks.NewMockTxFacade(ctrl)
+	tx.EXPECT().RunInTx(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(ctx context.Context, _ pgx.TxOptions, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+	return tx
+}
+
+// hasher проверяет хеши bcrypt, созданные до перехода на Argon2id.
+var hasher, _ = password.New(password.Params{Time: 1, Memory: 64, Threads: 1})
+
+func userWithPassword(t *testing.T, password string) *entity.User {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &entity.User{UUID: userUUID, Password: string(hash)}
+}
+
+func TestService_ChangePassword(t *testing.T) {
+	salt := []byte("0123456789abcdef")
+	verifier := srp.FakeVerifier([]byte("new-password"))
+	proof := entity.PasswordProof{HandshakeID: "handshake-id", Proof: []byte("proof")}
+
+	updated := func(u *mocks.MockUserRepository) UserRepository {
+		u.EXPECT().UpdateVerifier(gomock.Any(), userUUID, salt, verifier).Times(1).Return(true, nil)
+		return u
+	}
+	revoked := func(ctrl *gomock.Controller) SessionRevoker {
+		s := mocks.NewMockSessionRevoker(ctrl)
+		s.EXPECT().RevokeAllOther(gomock.Any(), userUUID, sessionUUID).Times(1).Return(int64(2), nil)
+		return s
+	}
+	audited := func(ctrl *gomock.Controller) AuditRepository {
+		a := mocks.NewMockAuditRepository(ctrl)
+		a.EXPECT().Create(gomock.Any(), entity.AuditRecord{UserUUID: userUUID, Event: entity.AuditPasswordChanged, IP: "127.0.0.1"}).Times(1).Return(nil)
+		return a
+	}
+	noProofs := func(ctrl *gomock.Controller) ProofVerifier {
+		return mocks.NewMockProofVerifier(ctrl)
+	}
+	noSessions := func(ctrl *gomock.Controller) SessionRevoker {
+		return mocks.NewMockSessionRevoker(ctrl)
+	}
+	noAudit := func(ctrl *gomock.Controller) AuditRepository {
+		return mocks.NewMockAuditRepository(ctrl)
+	}
+	proofOf := func(owner string, err error) func(ctrl *gomock.Controller) ProofVerifier {
+		return func(ctrl *gomock.Controller) ProofVerifier {
+			p := mocks.NewMockProofVerifier(ctrl)
+			p.EXPECT().Finish(gomock.Any(), "handshake-id", []byte("proof")).Times(1).Return(&entity.SRPHandshake{UserUUID: owner}, err)
+			return p
+		}
+	}
+
+	tests := []struct {
+		name        string
+		current     entity.PasswordProof
+		verifier    []byte
+		users       func(ctrl *gomock.Controller) UserRepository
+		proofs      func(ctrl *gomock.Controller) ProofVerifier
+		sessions    func(ctrl *gomock.Controller) SessionRevoker
+		audit       func(ctrl *gomock.Controller) AuditRepository
+		wantRevoked int64
+		wantErr     error
+	}{
+		{
+			name:     "Change_With_SRP_Proof",
+			current:  proof,
+			verifier: verifier,
+			users: func(ctrl *gomock.Controller) UserRepository {
+				return updated(mocks.NewMockUserRepository(ctrl))
+			},
+			proofs:      proofOf(userUUID, nil),
+			sessions:    revoked,
+			audit:       audited,
+			wantRevoked: 2,
+		},
+		{
+			name:     "Change_Legacy_Password_Moves_To_SRP",
+			current:  entity.PasswordProof{Password: "password"},
+			verifier: verifier,
+			users: func(ctrl *gomock.Controller) UserRepository {
+				u := mocks.NewMockUserRepository(ctrl)
+				u.EXPECT().ReadByUUID(gomock.Any(), userUUID).Times(1).Return(userWithPassword(t, "password"), nil)
+				return updated(u)
+			},
+			proofs:      noProofs,
+			sessions:    revoked,
+			audit:       audited,
+			wantRevoked: 2,
+		},
+		{
+			name:     "Wrong_SRP_Proof",
+			current:  proof,
+			verifier: verifier,
+			users: func(ctrl *gomock.Controller) UserRepository {
+				return mocks.NewMockUserRepository(ctrl)
+			},
+			proofs:   proofOf(userUUID, authsrv.ErrWrongPassword),
+			sessions: noSessions,
+			audit:    noAudit,
+			wantErr:  ErrWrongPassword,
+		},
+		{
+			name:     "Proof_Of_Other_User",
+			current:  proof,
+			verifier: verifier,
+			users: func(ctrl *gomock.Controller) UserRepository {
+				return mocks.NewMockUserRepository(ctrl)
+			},
+			proofs:   proofOf("other-user", nil),
+			sessions: noSessions,
+			audit:    noAudit,
+			wantErr:  ErrWrongPassword,
+		},
+		{
+			name:     "Wrong_Current_Password",
+			current:  entity.PasswordProof{Password: "wrong"},
+			verifier: verifier,
+			users: func(ctrl *gomock.Controller) UserRepository {
+				u := mocks.NewMockUserRepository(ctrl)
+				u.EXPECT().ReadByUUID(gomock.Any(), userUUID).Times(1).Return(userWithPassword(t, "password"), nil)
+				return u
+			},
+			proofs:   noProofs,
+			sessions: noSessions,
+			audit:    noAudit,
+			wantErr:  ErrWrongPassword,
+		},
+		{
+			name:     "Password_Not_Accepted_After_SRP",
+			current:  entity.PasswordProof{Password: "password"},
+			verifier: verifier,
+			users: func(ctrl *gomock.Controller) UserRepository {
+				u := mocks.NewMockUserRepository(ctrl)
+				u.EXPECT().ReadByUUID(gomock.Any(), userUUID).Times(1).Return(&entity.User{UUID: userUUID, SRPVerifier: verifier}, nil)
+				return u
+			},
+			proofs:   noProofs,
+			sessions: noSessions,
+			audit:    noAudit,
+			wantErr:  ErrWrongPassword,
+		},
+		{
+			name:    "Empty_New_Verifier",
+			current: proof,
+			users: func(ctrl *gomock.Controller) UserRepository {
+				return mocks.NewMockUserRepository(ctrl)
+			},
+			proofs:   noProofs,
+			sessions: noSessions,
+			audit:    noAudit,
+			wantErr:  ErrPasswordEmpty,
+		},
+		{
+			name:     "Invalid_New_Verifier",
+			current:  proof,
+			verifier: []byte{0},
+			users: func(ctrl *gomock.Controller) UserRepository {
+				return mocks.NewMockUserRepository(ctrl)
+			},
+			proofs:   noProofs,
+			sessions: noSessions,
+			audit:    noAudit,
+			wantErr:  ErrInvalidVerifier,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			s := New(tt.users(ctrl), tt.audit(ctrl), tt.sessions(ctrl), tt.proofs(ctrl), hasher, passthroughTx(ctrl), time.Hour)
+
+			got, err := s.ChangePassword(context.Background(), e.Identity{UUID: userUUID, SessionUUID: sessionUUID}, tt.current, salt, tt.verifier, "127.0.0.1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ChangePassword() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.wantRevoked {
+				t.Errorf("ChangePassword() revoked = %v, want %v", got, tt.wantRevoked)
+			}
+		})
+	}
+}
+
+func TestService_UpgradeToSRP(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	salt := []byte("0123456789abcdef")
+	verifier := srp.FakeVerifier([]byte("password"))
+
+	users := mocks.NewMockUserRepository(ctrl)
+	users.EXPECT().UpdateVerifier(gomock.Any(), userUUID, salt, verifier).Times(1).Return(true, nil)
+	audit := mocks.NewMockAuditRepository(ctrl)
+	audit.EXPECT().Create(gomock.Any(), entity.AuditRecord{UserUUID: userUUID, Event: entity.AuditSRPEnabled, IP: "127.0.0.1"}).Times(1).Return(nil)
+
+	s := New(users, audit, mocks.NewMockSessionRevoker(ctrl), mocks.NewMockProofVerifier(ctrl), hasher, passthroughTx(ctrl), time.Hour)
+	if err := s.UpgradeToSRP(context.Background(), userUUID, salt, verifier, "127.0.0.1"); err != nil {
+		t.Fatalf("UpgradeToSRP() error = %v", err)
+	}
+	if err := s.UpgradeToSRP(context.Background(), userUUID, salt, nil, "127.0.0.1"); !errors.Is(err, ErrPasswordEmpty) {
+		t.Errorf("UpgradeToSRP() error = %v, want %v", err, ErrPasswordEmpty)
+	}
+}
+
+func TestService_RequestDeletion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	wantAfter := now.Add(7 * 24 * time.Hour)
+
+	users := mocks.NewMockUserRepository(ctrl)
+	users.EXPECT().ReadByUUID(gomock.Any(), userUUID).Times(1).Return(userWithPassword(t, "password"), nil)
+	users.EXPECT().ScheduleDeletion(gomock.Any(), userUUID, wantAfter).Times(1).Return(true, nil)
+	audit := mocks.NewMockAuditRepository(ctrl)
+	audit.EXPECT().Create(gomock.Any(), entity.AuditRecord{UserUUID: userUUID, Event: entity.AuditDeletionRequested}).Times(1).Return(nil)
+
+	s := New(users, audit, mocks.NewMockSessionRevoker(ctrl), mocks.NewMockProofVerifier(ctrl), hasher, passthroughTx(ctrl), 7*24*time.Hour)
+	s.now = func() time.Time { return now }
+
+	got, err := s.RequestDeletion(context.Background(), userUUID, entity.PasswordProof{Password: "password"}, "")
+	if err != nil {
+		t.Fatalf("RequestDeletion() error = %v", err)
+	}
+	if !got.Equal(wantAfter) {
+		t.Errorf("RequestDeletion() = %v, want %v", got, wantAfter)
+	}
+}
+
+func TestService_CancelDeletion_NotRequested(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	users := mocks.NewMockUserRepository(ctrl)
+	users.EXPECT().CancelDeletion(gomock.Any(), userUUID).Times(1).Return(false, nil)
+	audit := mocks.NewMockAuditRepository(ctrl)
+	audit.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+
+	s := New(users, audit, mocks.NewMockSessionRevoker(ctrl), mocks.NewMockProofVerifier(ctrl), hasher, passthroughTx(ctrl), time.Hour)
+	if err := s.CancelDeletion(context.Background(), userUUID, ""); !errors.Is(err, ErrDeletionNotRequested) {
+		t.Errorf("CancelDeletion() error = %v, want %v", err, ErrDeletionNotRequested)
+	}
+}
+
+func TestService_PurgeDue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	users := mocks.NewMockUserRepository(ctrl)
+	users.EXPECT().ListDueForDeletion(gomock.Any(), now, purgeBatch).Times(1).Return([]string{"deleted-uuid", "cancelled-uuid"}, nil)
+	users.EXPECT().Purge(gomock.Any(), "deleted-uuid", now).Times(1).Return(true, nil)
+	// Удаление отменено между выборкой и очисткой.
+	users.EXPECT().Purge(gomock.Any(), "cancelled-uuid", now).Times(1).Return(false, nil)
+	audit := mocks.NewMockAuditRepository(ctrl)
+	audit.EXPECT().Create(gomock.Any(), entity.AuditRecord{UserUUID: "deleted-uuid", Event: entity.AuditAccountDeleted}).Times(1).Return(nil)
+
+	s := New(users, audit, mocks.NewMockSessionRevoker(ctrl), mocks.NewMockProofVerifier(ctrl), hasher, passthroughTx(ctrl), time.Hour)
+	s.now = func() time.Time { return now }
+
+	got, err := s.PurgeDue(context.Background())
+	if err != nil {
+		t.Fatalf("PurgeDue() error = %v", err)
+	}
+	if got != 1 {
+		t.Errorf("PurgeDue() = %v, want 1", got)
+	}
+}
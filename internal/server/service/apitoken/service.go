@@ -0,0 +1,153 @@
+package apitoken
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ktigay/goph-keeper/internal/server/entity"
+)
+
+const (
+	tokenSize     = 32
+	maxNameLength = 128
+	// maxTTL максимальный срок действия токена.
+	maxTTL = 366 * 24 * time.Hour
+)
+
+var (
+	// ErrTokenNotFound токен не найден.
+	ErrTokenNotFound = errors.New("token not found")
+	// ErrTokenExpired срок действия токена истек.
+	ErrTokenExpired = errors.New("token expired")
+	// ErrTokenRevoked токен отозван.
+	ErrTokenRevoked = errors.New("token revoked")
+	// ErrInvalidToken некорректные параметры токена.
+	ErrInvalidToken = errors.New("invalid token parameters")
+)
+
+// Repository репозиторий.
+//
+//go:generate mockgen -destination=./mocks/mock_apitoken.go -package=mocks github.com/ktigay/goph-keeper/internal/server/service/apitoken Repository
+type Repository interface {
+	Create(ctx context.Context, t entity.APIToken) (*entity.APIToken, error)
+	ReadByHash(ctx context.Context, hash []byte) (*entity.APIToken, error)
+	ListActive(ctx context.Context, userUUID string) ([]entity.APIToken, error)
+	RevokeByUser(ctx context.Context, uuid, userUUID string) (bool, error)
+	Touch(ctx context.Context, uuid string) error
+}
+
+// Service сервис токенов доступа. Токены хранятся только в виде хеша.
+type Service struct {
+	repo Repository
+	now  func() time.Time
+}
+
+// Create создает токен пользователя, возвращает его и сам токен. Токен показывается только один раз.
+func (s *Service) Create(ctx context.Context, userUUID, name string, expiresAt time.Time, scope entity.APITokenScope) (*entity.APIToken, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxNameLength {
+		return nil, "", fmt.Errorf("%w: name must be 1..%d characters", ErrInvalidToken, maxNameLength)
+	}
+	now := s.now()
+	if !expiresAt.After(now) || expiresAt.After(now.Add(maxTTL)) {
+		return nil, "", fmt.Errorf("%w: expires_at must be within %s", ErrInvalidToken, maxTTL)
+	}
+	for _, u := range scope.ItemUUIDs {
+		if uuid.Validate(u) != nil {
+			return nil, "", fmt.Errorf("%w: item uuid %q", ErrInvalidToken, u)
+		}
+	}
+	if len(scope.TagMAC) != 0 && len(scope.TagMAC) != sha256.Size {
+		return nil, "", fmt.Errorf("%w: tag_mac must be %d bytes", ErrInvalidToken, sha256.Size)
+	}
+
+	token, hash, err := newToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	created, err := s.repo.Create(ctx, entity.APIToken{
+		UserUUID:  userUUID,
+		Name:      name,
+		Hash:      hash,
+		Scope:     scope,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return created, token, nil
+}
+
+// List возвращает действующие токены пользователя.
+func (s *Service) List(ctx context.Context, userUUID string) ([]entity.APIToken, error) {
+	return s.repo.ListActive(ctx, userUUID)
+}
+
+// Revoke отзывает токен пользователя.
+func (s *Service) Revoke(ctx context.Context, userUUID, uuid string) error {
+	ok, err := s.repo.RevokeByUser(ctx, uuid, userUUID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrTokenNotFound
+	}
+	return nil
+}
+
+// Authenticate проверяет токен и отмечает его использование.
+func (s *Service) Authenticate(ctx context.Context, token string) (*entity.APIToken, error) {
+	if !strings.HasPrefix(token, entity.APITokenPrefix) {
+		return nil, ErrTokenNotFound
+	}
+
+	t, err := s.repo.ReadByHash(ctx, hashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, ErrTokenNotFound
+	}
+	if t.RevokedAt != nil {
+		return nil, ErrTokenRevoked
+	}
+	if !s.now().Before(t.ExpiresAt) {
+		return nil, ErrTokenExpired
+	}
+
+	if err = s.repo.Touch(ctx, t.UUID); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func newToken() (string, []byte, error) {
+	b := make([]byte, tokenSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", nil, err
+	}
+	token := entity.APITokenPrefix + base64.RawURLEncoding.EncodeToString(b)
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
+
+// New конструктор.
+func New(r Repository) *Service {
+	return &Service{
+		repo: r,
+		now:  time.Now,
+	}
+}
@@ -0,0 +1,190 @@
+package apitoken
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/ktigay/goph-keeper/internal/server/entity"
+	"github.com/ktigay/goph-keeper/internal/server/service/apitoken/mocks"
+)
+
+const (
+	userUUID  = "33b06619-1ee7-3db5-827d-0dc85df1f759"
+	tokenUUID = "7b1c0a52-5a3e-4e0b-9d55-2f3f1d3c6a10"
+)
+
+func TestService_Create(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name      string
+		tokenName string
+		expiresAt time.Time
+		scope     entity.APITokenScope
+		wantErr   error
+	}{
+		{
+			name:      "Create_Success",
+			tokenName: " ci ",
+			expiresAt: now.Add(24 * time.Hour),
+			scope:     entity.APITokenScope{ReadOnly: true, ItemUUIDs: []string{tokenUUID}},
+		},
+		{
+			name:      "Create_Tag_Success",
+			tokenName: "ci",
+			expiresAt: now.Add(time.Hour),
+			scope:     entity.APITokenScope{ReadOnly: true, TagMAC: bytes.Repeat([]byte{1}, sha256.Size)},
+		},
+		{name: "Empty_Name", tokenName: " ", expiresAt: now.Add(time.Hour), wantErr: ErrInvalidToken},
+		{name: "Expired", tokenName: "ci", expiresAt: now, wantErr: ErrInvalidToken},
+		{name: "Too_Long", tokenName: "ci", expiresAt: now.Add(maxTTL + time.Hour), wantErr: ErrInvalidToken},
+		{
+			name:      "Invalid_Item_UUID",
+			tokenName: "ci",
+			expiresAt: now.Add(time.Hour),
+			scope:     entity.APITokenScope{ItemUUIDs: []string{"item"}},
+			wantErr:   ErrInvalidToken,
+		},
+		{
+			name:      "Invalid_Tag_MAC",
+			tokenName: "ci",
+			expiresAt: now.Add(time.Hour),
+			scope:     entity.APITokenScope{TagMAC: []byte("deploy")},
+			wantErr:   ErrInvalidToken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockRepository(ctrl)
+
+			var stored entity.APIToken
+			if tt.wantErr == nil {
+				repo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+					func(_ context.Context, t entity.APIToken) (*entity.APIToken, error) {
+						stored = t
+						t.UUID = tokenUUID
+						return &t, nil
+					})
+			}
+
+			s := New(repo)
+			s.now = func() time.Time { return now }
+
+			got, token, err := s.Create(context.Background(), userUUID, tt.tokenName, tt.expiresAt, tt.scope)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Create() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if !strings.HasPrefix(token, entity.APITokenPrefix) || got.UUID != tokenUUID {
+				t.Fatalf("Create() got = %+v, token = %q", got, token)
+			}
+			if !bytes.Equal(stored.Hash, hashToken(token)) {
+				t.Errorf("Create() token must be stored hashed")
+			}
+			if stored.Name != "ci" || stored.UserUUID != userUUID || !stored.Scope.ReadOnly {
+				t.Errorf("Create() stored = %+v", stored)
+			}
+		})
+	}
+}
+
+func TestService_Authenticate(t *testing.T) {
+	const token = entity.APITokenPrefix + "secret"
+	now := time.Unix(1700000000, 0)
+	revokedAt := now.Add(-time.Minute)
+
+	tests := []struct {
+		name    string
+		token   string
+		repo    func(r *mocks.MockRepository)
+		wantErr error
+	}{
+		{
+			name:  "Authenticate_Success",
+			token: token,
+			repo: func(r *mocks.MockRepository) {
+				r.EXPECT().ReadByHash(gomock.Any(), hashToken(token)).Times(1).Return(&entity.APIToken{
+					UUID:      tokenUUID,
+					UserUUID:  userUUID,
+					ExpiresAt: now.Add(time.Hour),
+				}, nil)
+				r.EXPECT().Touch(gomock.Any(), tokenUUID).Times(1).Return(nil)
+			},
+		},
+		{
+			name:    "Wrong_Prefix",
+			token:   "jwt",
+			repo:    func(*mocks.MockRepository) {},
+			wantErr: ErrTokenNotFound,
+		},
+		{
+			name:  "Not_Found",
+			token: token,
+			repo: func(r *mocks.MockRepository) {
+				r.EXPECT().ReadByHash(gomock.Any(), hashToken(token)).Times(1).Return(nil, nil)
+			},
+			wantErr: ErrTokenNotFound,
+		},
+		{
+			name:  "Revoked",
+			token: token,
+			repo: func(r *mocks.MockRepository) {
+				r.EXPECT().ReadByHash(gomock.Any(), hashToken(token)).Times(1).Return(&entity.APIToken{
+					UUID:      tokenUUID,
+					ExpiresAt: now.Add(time.Hour),
+					RevokedAt: &revokedAt,
+				}, nil)
+			},
+			wantErr: ErrTokenRevoked,
+		},
+		{
+			name:  "Expired",
+			token: token,
+			repo: func(r *mocks.MockRepository) {
+				r.EXPECT().ReadByHash(gomock.Any(), hashToken(token)).Times(1).Return(&entity.APIToken{
+					UUID:      tokenUUID,
+					ExpiresAt: now,
+				}, nil)
+			},
+			wantErr: ErrTokenExpired,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockRepository(ctrl)
+			tt.repo(repo)
+
+			s := New(repo)
+			s.now = func() time.Time { return now }
+
+			got, err := s.Authenticate(context.Background(), tt.token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Authenticate() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && got.UserUUID != userUUID {
+				t.Errorf("Authenticate() user = %q, want %q", got.UserUUID, userUUID)
+			}
+		})
+	}
+}
+
+func TestService_Revoke(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockRepository(ctrl)
+	repo.EXPECT().RevokeByUser(gomock.Any(), tokenUUID, userUUID).Times(1).Return(false, nil)
+
+	if err := New(repo).Revoke(context.Background(), userUUID, tokenUUID); !errors.Is(err, ErrTokenNotFound) {
+		t.Errorf("Revoke() error = %v, want %v", err, ErrTokenNotFound)
+	}
+}
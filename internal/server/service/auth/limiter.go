@@ -0,0 +1,176 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ktigay/goph-keeper/internal/server/entity"
+)
+
+const (
+	// ScopeLogin попытки по логину.
+	ScopeLogin = "login"
+	// ScopeUser попытки по пользователю (второй фактор).
+	ScopeUser = "user"
+	// ScopeIP попытки по IP адресу клиента.
+	ScopeIP = "ip"
+
+	// maxLockoutShift ограничивает степень двойки при расчете блокировки.
+	maxLockoutShift = 16
+)
+
+// ErrLocked слишком много неудачных попыток, вход временно заблокирован.
+var ErrLocked = errors.New("too many attempts")
+
+// LockedError блокировка входа с временем до её снятия.
+type LockedError struct {
+	RetryAfter time.Duration
+}
+
+// Error описание ошибки.
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", ErrLocked, e.RetryAfter.Round(time.Second))
+}
+
+// Is для errors.Is(err, ErrLocked).
+func (e *LockedError) Is(target error) bool {
+	return target == ErrLocked
+}
+
+// AttemptStore хранилище неудачных попыток входа.
+//
+//go:generate mockgen -destination=./mocks/mock_attempt.go -package=mocks github.com/ktigay/goph-keeper/internal/server/service/auth AttemptStore
+type AttemptStore interface {
+	Read(ctx context.Context, key string) (*entity.LoginAttempt, error)
+	// Fail увеличивает счетчик неудач и возвращает его значение.
+	// Если последняя неудача была раньше resetBefore, счетчик начинается заново.
+	Fail(ctx context.Context, key string, now, resetBefore time.Time) (int, error)
+	Lock(ctx context.Context, key string, until time.Time) error
+	Reset(ctx context.Context, key string) error
+}
+
+// Policy политика блокировки.
+type Policy struct {
+	// MaxFailures количество неудач до первой блокировки.
+	MaxFailures int
+	// BaseLockout первая блокировка, каждая следующая неудача удваивает её.
+	BaseLockout time.Duration
+	// MaxLockout максимальная блокировка.
+	MaxLockout time.Duration
+	// ResetAfter через сколько после последней неудачи счетчик сбрасывается.
+	ResetAfter time.Duration
+}
+
+// DefaultPolicies политики по умолчанию.
+// Для IP порог выше: за одним адресом (NAT) может быть много пользователей.
+func DefaultPolicies() map[string]Policy {
+	return map[string]Policy{
+		ScopeLogin: {MaxFailures: 5, BaseLockout: 30 * time.Second, MaxLockout: time.Hour, ResetAfter: 24 * time.Hour},
+		ScopeUser:  {MaxFailures: 5, BaseLockout: 30 * time.Second, MaxLockout: time.Hour, ResetAfter: 24 * time.Hour},
+		ScopeIP:    {MaxFailures: 20, BaseLockout: 30 * time.Second, MaxLockout: time.Hour, ResetAfter: time.Hour},
+	}
+}
+
+// LimiterKey ключ ограничения попыток.
+type LimiterKey struct {
+	Scope string
+	Value string
+}
+
+// String строковое представление для хранилища.
+func (k LimiterKey) String() string {
+	return k.Scope + ":" + k.Value
+}
+
+// Limiter ограничитель неудачных попыток входа с экспоненциально растущей блокировкой.
+type Limiter struct {
+	store    AttemptStore
+	policies map[string]Policy
+	now      func() time.Time
+}
+
+// Check возвращает [*LockedError], если хотя бы один из ключей заблокирован.
+func (l *Limiter) Check(ctx context.Context, keys ...LimiterKey) error {
+	now := l.now()
+
+	var retryAfter time.Duration
+	for _, k := range l.filter(keys) {
+		a, err := l.store.Read(ctx, k.String())
+		if err != nil {
+			return err
+		}
+		if a == nil || a.LockedUntil == nil {
+			continue
+		}
+		if d := a.LockedUntil.Sub(now); d > retryAfter {
+			retryAfter = d
+		}
+	}
+
+	if retryAfter > 0 {
+		return &LockedError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// Fail учитывает неудачную попытку и при превышении порога блокирует ключ.
+func (l *Limiter) Fail(ctx context.Context, keys ...LimiterKey) error {
+	now := l.now()
+
+	for _, k := range l.filter(keys) {
+		p := l.policies[k.Scope]
+
+		failures, err := l.store.Fail(ctx, k.String(), now, now.Add(-p.ResetAfter))
+		if err != nil {
+			return err
+		}
+		if failures < p.MaxFailures {
+			continue
+		}
+		if err = l.store.Lock(ctx, k.String(), now.Add(p.lockout(failures))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reset сбрасывает счетчики после успешного входа.
+func (l *Limiter) Reset(ctx context.Context, keys ...LimiterKey) error {
+	for _, k := range l.filter(keys) {
+		if err := l.store.Reset(ctx, k.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// filter оставляет ключи с известной политикой и непустым значением.
+func (l *Limiter) filter(keys []LimiterKey) []LimiterKey {
+	res := make([]LimiterKey, 0, len(keys))
+	for _, k := range keys {
+		if _, ok := l.policies[k.Scope]; ok && k.Value != "" {
+			res = append(res, k)
+		}
+	}
+	return res
+}
+
+func (p Policy) lockout(failures int) time.Duration {
+	shift := min(failures-p.MaxFailures, maxLockoutShift)
+	d := p.BaseLockout << shift
+	if d > p.MaxLockout || d <= 0 {
+		return p.MaxLockout
+	}
+	return d
+}
+
+// NewLimiter конструктор.
+func NewLimiter(store AttemptStore, policies map[string]Policy) *Limiter {
+	return &Limiter{
+		store:    store,
+		policies: policies,
+		now:      time.Now,
+	}
+}
@@ -0,0 +1,107 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/ktigay/goph-keeper/internal/server/repository/attempt"
+	"github.com/ktigay/goph-keeper/internal/server/service/auth/mocks"
+)
+
+func TestLimiter_Lockout(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	policy := Policy{MaxFailures: 3, BaseLockout: 30 * time.Second, MaxLockout: 2 * time.Minute, ResetAfter: time.Hour}
+
+	l := NewLimiter(attempt.NewMemory(), map[string]Policy{ScopeLogin: policy})
+	l.now = func() time.Time { return now }
+	key := LimiterKey{Scope: ScopeLogin, Value: "login"}
+
+	for i := 0; i < policy.MaxFailures-1; i++ {
+		if err := l.Fail(ctx, key); err != nil {
+			t.Fatalf("Fail() error = %v", err)
+		}
+	}
+	if err := l.Check(ctx, key); err != nil {
+		t.Fatalf("Check() before threshold error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		want time.Duration
+	}{
+		{name: "First_Lockout", want: 30 * time.Second},
+		{name: "Doubled", want: time.Minute},
+		{name: "Capped", want: 2 * time.Minute},
+		{name: "Still_Capped", want: 2 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := l.Fail(ctx, key); err != nil {
+				t.Fatalf("Fail() error = %v", err)
+			}
+			var locked *LockedError
+			err := l.Check(ctx, key)
+			if !errors.As(err, &locked) || !errors.Is(err, ErrLocked) {
+				t.Fatalf("Check() error = %v, want LockedError", err)
+			}
+			if locked.RetryAfter != tt.want {
+				t.Errorf("Check() RetryAfter = %v, want %v", locked.RetryAfter, tt.want)
+			}
+		})
+	}
+
+	now = now.Add(3 * time.Minute)
+	if err := l.Check(ctx, key); err != nil {
+		t.Errorf("Check() after lockout error = %v", err)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if err := l.Fail(ctx, key); err != nil {
+		t.Fatalf("Fail() error = %v", err)
+	}
+	if err := l.Check(ctx, key); err != nil {
+		t.Errorf("Check() counter must be reset after ResetAfter, error = %v", err)
+	}
+}
+
+func TestLimiter_Reset(t *testing.T) {
+	ctx := context.Background()
+	l := NewLimiter(attempt.NewMemory(), map[string]Policy{
+		ScopeLogin: {MaxFailures: 1, BaseLockout: time.Minute, MaxLockout: time.Minute, ResetAfter: time.Hour},
+	})
+	key := LimiterKey{Scope: ScopeLogin, Value: "login"}
+
+	if err := l.Fail(ctx, key); err != nil {
+		t.Fatalf("Fail() error = %v", err)
+	}
+	if err := l.Check(ctx, key); !errors.Is(err, ErrLocked) {
+		t.Fatalf("Check() error = %v, want %v", err, ErrLocked)
+	}
+	if err := l.Reset(ctx, key); err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+	if err := l.Check(ctx, key); err != nil {
+		t.Errorf("Check() after Reset() error = %v", err)
+	}
+}
+
+func TestLimiter_SkipsUnknownAndEmptyKeys(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mocks.NewMockAttemptStore(ctrl)
+	store.EXPECT().Read(gomock.Any(), "login:login").Times(1).Return(nil, nil)
+
+	l := NewLimiter(store, DefaultPolicies())
+	err := l.Check(context.Background(),
+		LimiterKey{Scope: ScopeLogin, Value: "login"},
+		LimiterKey{Scope: ScopeIP, Value: ""},
+		LimiterKey{Scope: "unknown", Value: "value"},
+	)
+	if err != nil {
+		t.Errorf("Check() error = %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ktigay/goph-keeper/internal/server/service/auth (interfaces: AttemptStore)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	entity "github.com/ktigay/goph-keeper/internal/server/entity"
+)
+
+// MockAttemptStore is a mock of AttemptStore interface.
+type MockAttemptStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockAttemptStoreMockRecorder
+}
+
+// MockAttemptStoreMockRecorder is the mock recorder for MockAttemptStore.
+type MockAttemptStoreMockRecorder struct {
+	mock *MockAttemptStore
+}
+
+// NewMockAttemptStore creates a new mock instance.
+func NewMockAttemptStore(ctrl *gomock.Controller) *MockAttemptStore {
+	mock := &MockAttemptStore{ctrl: ctrl}
+	mock.recorder = &MockAttemptStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAttemptStore) EXPECT() *MockAttemptStoreMockRecorder {
+	return m.recorder
+}
+
+// Fail mocks base method.
+func (m *MockAttemptStore) Fail(arg0 context.Context, arg1 string, arg2, arg3 time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fail", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Fail indicates an expected call of Fail.
+func (mr *MockAttemptStoreMockRecorder) Fail(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fail", reflect.TypeOf((*MockAttemptStore)(nil).Fail), arg0, arg1, arg2, arg3)
+}
+
+// Lock mocks base method.
+func (m *MockAttemptStore) Lock(arg0 context.Context, arg1 string, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockAttemptStoreMockRecorder) Lock(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockAttemptStore)(nil).Lock), arg0, arg1, arg2)
+}
+
+// Read mocks base method.
+func (m *MockAttemptStore) Read(arg0 context.Context, arg1 string) (*entity.LoginAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Read", arg0, arg1)
+	ret0, _ := ret[0].(*entity.LoginAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Read indicates an expected call of Read.
+func (mr *MockAttemptStoreMockRecorder) Read(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Read", reflect.TypeOf((*MockAttemptStore)(nil).Read), arg0, arg1)
+}
+
+// Reset mocks base method.
+func (m *MockAttemptStore) Reset(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reset", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockAttemptStoreMockRecorder) Reset(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockAttemptStore)(nil).Reset), arg0, arg1)
+}
@@ -0,0 +1,82 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ktigay/goph-keeper/internal/server/service/auth (interfaces: HandshakeRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	entity "github.com/ktigay/goph-keeper/internal/server/entity"
+)
+
+// MockHandshakeRepository is a mock of HandshakeRepository interface.
+type MockHandshakeRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockHandshakeRepositoryMockRecorder
+}
+
+// MockHandshakeRepositoryMockRecorder is the mock recorder for MockHandshakeRepository.
+type MockHandshakeRepositoryMockRecorder struct {
+	mock *MockHandshakeRepository
+}
+
+// NewMockHandshakeRepository creates a new mock instance.
+func NewMockHandshakeRepository(ctrl *gomock.Controller) *MockHandshakeRepository {
+	mock := &MockHandshakeRepository{ctrl: ctrl}
+	mock.recorder = &MockHandshakeRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHandshakeRepository) EXPECT() *MockHandshakeRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockHandshakeRepository) Create(arg0 context.Context, arg1 entity.SRPHandshake) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockHandshakeRepositoryMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockHandshakeRepository)(nil).Create), arg0, arg1)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockHandshakeRepository) DeleteExpired(arg0 context.Context, arg1 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockHandshakeRepositoryMockRecorder) DeleteExpired(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockHandshakeRepository)(nil).DeleteExpired), arg0, arg1)
+}
+
+// Take mocks base method.
+func (m *MockHandshakeRepository) Take(arg0 context.Context, arg1 string) (*entity.SRPHandshake, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Take", arg0, arg1)
+	ret0, _ := ret[0].(*entity.SRPHandshake)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Take indicates an expected call of Take.
+func (mr *MockHandshakeRepositoryMockRecorder) Take(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Take", reflect.TypeOf((*MockHandshakeRepository)(nil).Take), arg0, arg1)
+}
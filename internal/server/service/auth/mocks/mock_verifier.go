@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ktigay/goph-keeper/internal/server/service/auth (interfaces: VerifierRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	entity "github.com/ktigay/goph-keeper/internal/server/entity"
+)
+
+// MockVerifierRepository is a mock of VerifierRepository interface.
+type MockVerifierRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockVerifierRepositoryMockRecorder
+}
+
+// MockVerifierRepositoryMockRecorder is the mock recorder for MockVerifierRepository.
+type MockVerifierRepositoryMockRecorder struct {
+	mock *MockVerifierRepository
+}
+
+// NewMockVerifierRepository creates a new mock instance.
+func NewMockVerifierRepository(ctrl *gomock.Controller) *MockVerifierRepository {
+	mock := &MockVerifierRepository{ctrl: ctrl}
+	mock.recorder = &MockVerifierRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVerifierRepository) EXPECT() *MockVerifierRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateWithVerifier mocks base method.
+func (m *MockVerifierRepository) CreateWithVerifier(arg0 context.Context, arg1 string, arg2, arg3 []byte) (*entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWithVerifier", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWithVerifier indicates an expected call of CreateWithVerifier.
+func (mr *MockVerifierRepositoryMockRecorder) CreateWithVerifier(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWithVerifier", reflect.TypeOf((*MockVerifierRepository)(nil).CreateWithVerifier), arg0, arg1, arg2, arg3)
+}
+
+// Read mocks base method.
+func (m *MockVerifierRepository) Read(arg0 context.Context, arg1 string) (*entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Read", arg0, arg1)
+	ret0, _ := ret[0].(*entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Read indicates an expected call of Read.
+func (mr *MockVerifierRepositoryMockRecorder) Read(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Read", reflect.TypeOf((*MockVerifierRepository)(nil).Read), arg0, arg1)
+}
+
+// ReadByUUID mocks base method.
+func (m *MockVerifierRepository) ReadByUUID(arg0 context.Context, arg1 string) (*entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadByUUID", arg0, arg1)
+	ret0, _ := ret[0].(*entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadByUUID indicates an expected call of ReadByUUID.
+func (mr *MockVerifierRepositoryMockRecorder) ReadByUUID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadByUUID", reflect.TypeOf((*MockVerifierRepository)(nil).ReadByUUID), arg0, arg1)
+}
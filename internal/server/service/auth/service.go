@@ -5,15 +5,9 @@ import (
 	"errors"
 	"strings"
 
-	"golang.org/x/crypto/bcrypt"
-
 	"github.com/ktigay/goph-keeper/internal/server/entity"
 )
 
-const (
-	bcryptCost = bcrypt.DefaultCost
-)
-
 var (
 	// ErrLoginOrPwdEmpty логин или пароль пустые.
 	ErrLoginOrPwdEmpty = errors.New("login or password is empty")
@@ -21,6 +15,10 @@ var (
 	ErrUserNotFound = errors.New("user not found")
 	// ErrWrongPassword неправильный пароль.
 	ErrWrongPassword = errors.New("wrong password")
+	// ErrLoginExists логин уже занят.
+	ErrLoginExists = errors.New("login already exists")
+	// ErrLegacyDisabled вход и регистрация с передачей пароля серверу отключены.
+	ErrLegacyDisabled = errors.New("password login is disabled, use srp")
 )
 
 // Repository репозиторий.
@@ -29,46 +27,72 @@ var (
 type Repository interface {
 	Create(ctx context.Context, login, password string) (*entity.User, error)
 	Read(ctx context.Context, login string) (*entity.User, error)
+	UpdatePasswordHash(ctx context.Context, uuid, old, hash string) (bool, error)
 }
 
-// Service сервис.
+// PasswordHasher хеширование паролей.
+//
+//go:generate mockgen -destination=./mocks/mock_hasher.go -package=mocks github.com/ktigay/goph-keeper/internal/server/service/auth PasswordHasher
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(encoded, password string) (bool, error)
+	VerifyDummy(password string)
+	NeedsRehash(encoded string) bool
+}
+
+// Service сервис входа по паролю. Устаревший способ: пароль передается серверу,
+// включается явно на время перехода пользователей на [SRP].
 type Service struct {
-	repo Repository
+	repo   Repository
+	hasher PasswordHasher
+	legacy bool
 }
 
 // Register регистрирует пользователя.
 func (s *Service) Register(ctx context.Context, login, password string) (*entity.User, error) {
 	var (
 		newUsr       *entity.User
-		hashedPasswd []byte
+		hashedPasswd string
 		err          error
 	)
 
+	if !s.legacy {
+		return nil, ErrLegacyDisabled
+	}
+
 	login = strings.TrimSpace(login)
 	password = strings.TrimSpace(password)
 	if login == "" || password == "" {
 		return nil, ErrLoginOrPwdEmpty
 	}
 
-	hashedPasswd, err = bcrypt.GenerateFromPassword([]byte(password), bcryptCost)
-	if err != nil {
+	if hashedPasswd, err = s.hasher.Hash(password); err != nil {
 		return nil, err
 	}
 
-	if newUsr, err = s.repo.Create(ctx, login, string(hashedPasswd)); err != nil {
+	if newUsr, err = s.repo.Create(ctx, login, hashedPasswd); err != nil {
 		return nil, err
 	}
+	if newUsr == nil {
+		return nil, ErrLoginExists
+	}
 
 	return newUsr, err
 }
 
-// Login авторизует пользователя.
+// Login авторизует пользователя. Хеш, созданный другим алгоритмом или с другими параметрами,
+// после успешного входа пересчитывается текущими.
 func (s *Service) Login(ctx context.Context, login, password string) (*entity.User, error) {
 	var (
-		usr *entity.User
-		err error
+		usr   *entity.User
+		match bool
+		err   error
 	)
 
+	if !s.legacy {
+		return nil, ErrLegacyDisabled
+	}
+
 	login = strings.TrimSpace(login)
 	password = strings.TrimSpace(password)
 	if login == "" || password == "" {
@@ -79,23 +103,52 @@ func (s *Service) Login(ctx context.Context, login, password string) (*entity.Us
 		return nil, err
 	}
 	if usr == nil {
+		s.hasher.VerifyDummy(password)
 		return nil, ErrUserNotFound
 	}
+	if usr.Password == "" {
+		// Пользователь перешел на SRP, пароль не принимается.
+		s.hasher.VerifyDummy(password)
+		return nil, ErrWrongPassword
+	}
 
-	if err = bcrypt.CompareHashAndPassword([]byte(usr.Password), []byte(password)); err != nil {
-		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
-			return nil, ErrWrongPassword
-		}
-
+	if match, err = s.hasher.Verify(usr.Password, password); err != nil {
 		return nil, err
 	}
+	if !match {
+		return nil, ErrWrongPassword
+	}
+
+	if s.hasher.NeedsRehash(usr.Password) {
+		if err = s.rehash(ctx, usr, password); err != nil {
+			return nil, err
+		}
+	}
 
 	return usr, nil
 }
 
-// New конструктор.
-func New(r Repository) *Service {
+// rehash пересчитывает хеш пароля. Хеш заменяется, только если не изменился с момента чтения.
+func (s *Service) rehash(ctx context.Context, usr *entity.User, password string) error {
+	hash, err := s.hasher.Hash(password)
+	if err != nil {
+		return err
+	}
+	updated, err := s.repo.UpdatePasswordHash(ctx, usr.UUID, usr.Password, hash)
+	if err != nil {
+		return err
+	}
+	if updated {
+		usr.Password = hash
+	}
+	return nil
+}
+
+// New конструктор. legacy разрешает вход и регистрацию по паролю.
+func New(r Repository, h PasswordHasher, legacy bool) *Service {
 	return &Service{
-		repo: r,
+		repo:   r,
+		hasher: h,
+		legacy: legacy,
 	}
 }
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"reflect"
 	"testing"
 
@@ -12,8 +13,11 @@ import (
 )
 
 func TestService_Login(t *testing.T) {
+	const argonHash = "$argon2id$v=19$m=65536,t=3,p=4$c2FsdA$aGFzaA"
+
 	type fields struct {
-		repo func(controller *gomock.Controller) Repository
+		repo   func(controller *gomock.Controller) Repository
+		hasher func(controller *gomock.Controller) PasswordHasher
 	}
 	type args struct {
 		login    string
@@ -34,22 +38,92 @@ func TestService_Login(t *testing.T) {
 					repo.EXPECT().
 						Read(gomock.Any(), gomock.Any()).Times(1).Return(
 						&entity.User{
+							Login:    "test",
+							Password: argonHash,
+						}, nil)
+					repo.EXPECT().UpdatePasswordHash(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+					return repo
+				},
+				hasher: func(ctrl *gomock.Controller) PasswordHasher {
+					h := mocks.NewMockPasswordHasher(ctrl)
+					h.EXPECT().Verify(argonHash, "test").Times(1).Return(true, nil)
+					h.EXPECT().NeedsRehash(argonHash).Times(1).Return(false)
+					return h
+				},
+			},
+			args: args{
+				login:    "test",
+				password: "test",
+			},
+			want: &entity.User{
+				Login:    "test",
+				Password: argonHash,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Login_Bcrypt_Rehashed",
+			fields: fields{
+				repo: func(ctrl *gomock.Controller) Repository {
+					repo := mocks.NewMockRepository(ctrl)
+					repo.EXPECT().
+						Read(gomock.Any(), gomock.Any()).Times(1).Return(
+						&entity.User{
+							UUID:     "33b06619-1ee7-3db5-827d-0dc85df1f759",
 							Login:    "test",
 							Password: "$2a$10$dvLRmGJ8HMdgLTHeklRXNelmcoHb82y5lGIX3JJl4tawa9/mNEkba",
 						}, nil)
+					repo.EXPECT().
+						UpdatePasswordHash(gomock.Any(), "33b06619-1ee7-3db5-827d-0dc85df1f759", "$2a$10$dvLRmGJ8HMdgLTHeklRXNelmcoHb82y5lGIX3JJl4tawa9/mNEkba", argonHash).
+						Times(1).Return(true, nil)
 					return repo
 				},
+				hasher: func(ctrl *gomock.Controller) PasswordHasher {
+					h := mocks.NewMockPasswordHasher(ctrl)
+					h.EXPECT().Verify(gomock.Any(), "test").Times(1).Return(true, nil)
+					h.EXPECT().NeedsRehash(gomock.Any()).Times(1).Return(true)
+					h.EXPECT().Hash("test").Times(1).Return(argonHash, nil)
+					return h
+				},
 			},
 			args: args{
 				login:    "test",
 				password: "test",
 			},
 			want: &entity.User{
+				UUID:     "33b06619-1ee7-3db5-827d-0dc85df1f759",
 				Login:    "test",
-				Password: "$2a$10$dvLRmGJ8HMdgLTHeklRXNelmcoHb82y5lGIX3JJl4tawa9/mNEkba",
+				Password: argonHash,
 			},
 			wantErr: false,
 		},
+		{
+			name: "Login_ErrWrongPassword",
+			fields: fields{
+				repo: func(ctrl *gomock.Controller) Repository {
+					repo := mocks.NewMockRepository(ctrl)
+					repo.EXPECT().
+						Read(gomock.Any(), gomock.Any()).Times(1).Return(
+						&entity.User{
+							Login:    "test",
+							Password: "$2a$10$dvLRmGJ8HMdgLTHeklRXNelmcoHb82y5lGIX3JJl4tawa9/mNEkba",
+						}, nil)
+					repo.EXPECT().UpdatePasswordHash(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+					return repo
+				},
+				hasher: func(ctrl *gomock.Controller) PasswordHasher {
+					h := mocks.NewMockPasswordHasher(ctrl)
+					h.EXPECT().Verify(gomock.Any(), "wrong").Times(1).Return(false, nil)
+					return h
+				},
+			},
+			args: args{
+				login:    "test",
+				password: "wrong",
+			},
+			want:    nil,
+			wantErr: true,
+		},
 		{
 			name: "Login_ErrUserNotFound",
 			fields: fields{
@@ -60,6 +134,11 @@ func TestService_Login(t *testing.T) {
 						nil, nil)
 					return repo
 				},
+				hasher: func(ctrl *gomock.Controller) PasswordHasher {
+					h := mocks.NewMockPasswordHasher(ctrl)
+					h.EXPECT().VerifyDummy("test").Times(1)
+					return h
+				},
 			},
 			args: args{
 				login:    "test",
@@ -90,7 +169,12 @@ func TestService_Login(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			s := &Service{
-				repo: tt.fields.repo(ctrl),
+				repo:   tt.fields.repo(ctrl),
+				hasher: mocks.NewMockPasswordHasher(ctrl),
+				legacy: true,
+			}
+			if tt.fields.hasher != nil {
+				s.hasher = tt.fields.hasher(ctrl)
 			}
 			got, err := s.Login(context.Background(), tt.args.login, tt.args.password)
 			if (err != nil) != tt.wantErr {
@@ -125,7 +209,7 @@ func TestService_Register(t *testing.T) {
 				repo: func(ctrl *gomock.Controller) Repository {
 					repo := mocks.NewMockRepository(ctrl)
 					repo.EXPECT().
-						Create(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(
+						Create(gomock.Any(), "test", "$argon2id$hash").Times(1).Return(
 						&entity.User{
 							Login: "test",
 						}, nil)
@@ -175,12 +259,33 @@ func TestService_Register(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name: "Register_ErrLoginExists",
+			fields: fields{
+				repo: func(ctrl *gomock.Controller) Repository {
+					repo := mocks.NewMockRepository(ctrl)
+					repo.EXPECT().
+						Create(gomock.Any(), "test", gomock.Any()).Times(1).Return(nil, nil)
+					return repo
+				},
+			},
+			args: args{
+				login:    "test",
+				password: "test",
+			},
+			want:    nil,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			ctrl := gomock.NewController(t)
+			hasher := mocks.NewMockPasswordHasher(ctrl)
+			hasher.EXPECT().Hash(gomock.Any()).AnyTimes().Return("$argon2id$hash", nil)
 			s := &Service{
-				repo: tt.fields.repo(ctrl),
+				repo:   tt.fields.repo(ctrl),
+				hasher: hasher,
+				legacy: true,
 			}
 			got, err := s.Register(context.Background(), tt.args.login, tt.args.password)
 			if (err != nil) != tt.wantErr {
@@ -193,3 +298,15 @@ func TestService_Register(t *testing.T) {
 		})
 	}
 }
+
+func TestService_LegacyDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	s := New(mocks.NewMockRepository(ctrl), mocks.NewMockPasswordHasher(ctrl), false)
+
+	if _, err := s.Login(context.Background(), "test", "test"); !errors.Is(err, ErrLegacyDisabled) {
+		t.Errorf("Login() error = %v, want %v", err, ErrLegacyDisabled)
+	}
+	if _, err := s.Register(context.Background(), "test", "test"); !errors.Is(err, ErrLegacyDisabled) {
+		t.Errorf("Register() error = %v, want %v", err, ErrLegacyDisabled)
+	}
+}
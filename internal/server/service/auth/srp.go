@@ -0,0 +1,199 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ktigay/goph-keeper/internal/server/entity"
+	srplib "github.com/ktigay/goph-keeper/internal/srp"
+)
+
+// handshakeTTL время, за которое клиент должен предъявить доказательство после начала обмена.
+const handshakeTTL = time.Minute
+
+var (
+	// ErrHandshakeNotFound обмен SRP не найден, уже использован или просрочен.
+	ErrHandshakeNotFound = errors.New("srp handshake not found or expired")
+	// ErrInvalidVerifier некорректный верификатор SRP.
+	ErrInvalidVerifier = errors.New("invalid srp verifier")
+	// ErrInvalidPublicKey некорректное открытое значение клиента.
+	ErrInvalidPublicKey = errors.New("invalid srp public value")
+)
+
+// VerifierRepository репозиторий пользователей с верификаторами SRP.
+//
+//go:generate mockgen -destination=./mocks/mock_verifier.go -package=mocks github.com/ktigay/goph-keeper/internal/server/service/auth VerifierRepository
+type VerifierRepository interface {
+	CreateWithVerifier(ctx context.Context, login string, salt, verifier []byte) (*entity.User, error)
+	Read(ctx context.Context, login string) (*entity.User, error)
+	ReadByUUID(ctx context.Context, uuid string) (*entity.User, error)
+}
+
+// HandshakeRepository репозиторий незавершенных обменов SRP.
+//
+//go:generate mockgen -destination=./mocks/mock_handshake.go -package=mocks github.com/ktigay/goph-keeper/internal/server/service/auth HandshakeRepository
+type HandshakeRepository interface {
+	Create(ctx context.Context, h entity.SRPHandshake) (string, error)
+	Take(ctx context.Context, id string) (*entity.SRPHandshake, error)
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// SRP вход по SRP-6a: сервер хранит только верификатор и не получает пароль.
+type SRP struct {
+	users      VerifierRepository
+	handshakes HandshakeRepository
+	// seed секрет для соли и верификатора несуществующих логинов.
+	seed         []byte
+	fakeVerifier []byte
+	// legacy разрешен вход с передачей пароля серверу.
+	legacy bool
+	now    func() time.Time
+}
+
+// Register регистрирует пользователя с верификатором SRP.
+func (s *SRP) Register(ctx context.Context, login string, salt, verifier []byte) (*entity.User, error) {
+	login = strings.TrimSpace(login)
+	if login == "" || len(salt) == 0 || len(verifier) == 0 {
+		return nil, ErrLoginOrPwdEmpty
+	}
+	if err := srplib.CheckVerifier(verifier); err != nil {
+		return nil, ErrInvalidVerifier
+	}
+
+	usr, err := s.users.CreateWithVerifier(ctx, login, salt, verifier)
+	if err != nil {
+		return nil, err
+	}
+	if usr == nil {
+		return nil, ErrLoginExists
+	}
+	return usr, nil
+}
+
+// Start начинает обмен по логину. Ответ не зависит от того, существует ли логин: несуществующий логин
+// и пользователь без верификатора получают поддельный обмен с постоянной для логина солью. Legacy одинаков
+// для всех логинов - сервер принимает вход по паролю, и клиент входит им, если вход по SRP не прошел.
+func (s *SRP) Start(ctx context.Context, login string, clientPublic []byte) (*entity.SRPChallenge, error) {
+	login = strings.TrimSpace(login)
+	if login == "" {
+		return nil, ErrLoginOrPwdEmpty
+	}
+
+	usr, err := s.users.Read(ctx, login)
+	if err != nil {
+		return nil, err
+	}
+	if usr != nil && len(usr.SRPVerifier) == 0 {
+		usr = nil
+	}
+	ch, err := s.start(ctx, login, usr, clientPublic)
+	if err != nil {
+		return nil, err
+	}
+	ch.Legacy = s.legacy
+	return ch, nil
+}
+
+// StartForUser начинает обмен для авторизованного пользователя, чтобы подтвердить пароль при смене или удалении.
+func (s *SRP) StartForUser(ctx context.Context, userUUID string, clientPublic []byte) (*entity.SRPChallenge, error) {
+	usr, err := s.users.ReadByUUID(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	if usr == nil {
+		return nil, ErrUserNotFound
+	}
+	if len(usr.SRPVerifier) == 0 {
+		return &entity.SRPChallenge{Legacy: true}, nil
+	}
+	return s.start(ctx, usr.Login, usr, clientPublic)
+}
+
+func (s *SRP) start(ctx context.Context, login string, usr *entity.User, clientPublic []byte) (*entity.SRPChallenge, error) {
+	salt, verifier, userUUID := s.fakeSalt(login), s.fakeVerifier, ""
+	if usr != nil {
+		salt, verifier, userUUID = usr.SRPSalt, usr.SRPVerifier, usr.UUID
+	}
+
+	server, err := srplib.NewServer(verifier, clientPublic)
+	if err != nil {
+		if errors.Is(err, srplib.ErrInvalidPublicKey) {
+			return nil, ErrInvalidPublicKey
+		}
+		return nil, err
+	}
+
+	proofHash := sha256.Sum256(server.ClientProof())
+	id, err := s.handshakes.Create(ctx, entity.SRPHandshake{
+		UserUUID:    userUUID,
+		Login:       login,
+		ProofHash:   proofHash[:],
+		ServerProof: server.ServerProof(),
+		ExpiresAt:   s.now().Add(handshakeTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.SRPChallenge{
+		HandshakeID:  id,
+		Salt:         salt,
+		ServerPublic: server.PublicKey(),
+	}, nil
+}
+
+// Finish проверяет доказательство клиента, обмен удаляется при любом исходе.
+// При неверном доказательстве обмен возвращается вместе с ошибкой, чтобы неудачу можно было учесть по логину.
+func (s *SRP) Finish(ctx context.Context, handshakeID string, proof []byte) (*entity.SRPHandshake, error) {
+	if uuid.Validate(handshakeID) != nil {
+		return nil, ErrHandshakeNotFound
+	}
+
+	h, err := s.handshakes.Take(ctx, handshakeID)
+	if err != nil {
+		return nil, err
+	}
+	if h == nil || s.now().After(h.ExpiresAt) {
+		return nil, ErrHandshakeNotFound
+	}
+
+	sum := sha256.Sum256(proof)
+	if err = srplib.VerifyProof(h.ProofHash, sum[:]); err != nil {
+		return h, ErrWrongPassword
+	}
+	if h.UserUUID == "" {
+		return h, ErrUserNotFound
+	}
+	return h, nil
+}
+
+// PurgeExpired удаляет просроченные обмены. Возвращает количество удаленных.
+func (s *SRP) PurgeExpired(ctx context.Context) (int64, error) {
+	return s.handshakes.DeleteExpired(ctx, s.now())
+}
+
+// fakeSalt постоянная для логина соль, чтобы повторные запросы не выдавали несуществующий логин.
+func (s *SRP) fakeSalt(login string) []byte {
+	mac := hmac.New(sha256.New, s.seed)
+	mac.Write([]byte(login))
+	return mac.Sum(nil)[:srplib.SaltSize]
+}
+
+// NewSRP конструктор. seed - постоянный секрет сервера для ответов на несуществующие логины,
+// legacy - разрешен ли вход с передачей пароля серверу.
+func NewSRP(u VerifierRepository, h HandshakeRepository, seed []byte, legacy bool) *SRP {
+	return &SRP{
+		users:        u,
+		handshakes:   h,
+		seed:         seed,
+		fakeVerifier: srplib.FakeVerifier(seed),
+		legacy:       legacy,
+		now:          time.Now,
+	}
+}
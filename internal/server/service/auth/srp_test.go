@@ -0,0 +1,271 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/ktigay/goph-keeper/internal/server/entity"
+	"github.com/ktigay/goph-keeper/internal/server/service/auth/mocks"
+	srplib "github.com/ktigay/goph-keeper/internal/srp"
+)
+
+const handshakeID = "2b8f5e7a-6c1d-4f0e-9a3b-1d2c3e4f5a6b"
+
+// srpLogin проходит обмен SRP паролем entered для пользователя usr (nil - логин не существует).
+func srpLogin(t *testing.T, usr *entity.User, entered string, now time.Time) (*entity.SRPHandshake, error) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	users := mocks.NewMockVerifierRepository(ctrl)
+	handshakes := mocks.NewMockHandshakeRepository(ctrl)
+
+	var stored entity.SRPHandshake
+	users.EXPECT().Read(gomock.Any(), "test").Times(1).Return(usr, nil)
+	handshakes.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+		func(_ context.Context, h entity.SRPHandshake) (string, error) {
+			stored = h
+			return handshakeID, nil
+		})
+	handshakes.EXPECT().Take(gomock.Any(), handshakeID).Times(1).DoAndReturn(
+		func(_ context.Context, _ string) (*entity.SRPHandshake, error) {
+			return &stored, nil
+		})
+
+	s := NewSRP(users, handshakes, []byte("seed"), false)
+	s.now = func() time.Time { return time.Unix(1700000000, 0) }
+
+	client, err := srplib.NewClient(entered)
+	if err != nil {
+		t.Fatal(err)
+	}
+	challenge, err := s.Start(context.Background(), " test ", client.PublicKey())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if challenge.Legacy || challenge.HandshakeID != handshakeID {
+		t.Fatalf("Start() got = %+v", challenge)
+	}
+	proof, err := client.Proof(challenge.Salt, challenge.ServerPublic)
+	if err != nil {
+		t.Fatalf("Proof() error = %v", err)
+	}
+
+	s.now = func() time.Time { return now }
+	h, err := s.Finish(context.Background(), handshakeID, proof)
+	if err == nil {
+		if vErr := client.VerifyServer(h.ServerProof); vErr != nil {
+			t.Errorf("VerifyServer() error = %v", vErr)
+		}
+	}
+	return h, err
+}
+
+func TestSRP_Login(t *testing.T) {
+	salt, err := srplib.NewSalt()
+	if err != nil {
+		t.Fatal(err)
+	}
+	usr := &entity.User{
+		UUID:        "33b06619-1ee7-3db5-827d-0dc85df1f759",
+		Login:       "test",
+		SRPSalt:     salt,
+		SRPVerifier: srplib.Verifier("password", salt),
+	}
+	inTime := time.Unix(1700000000, 0).Add(handshakeTTL / 2)
+
+	tests := []struct {
+		name     string
+		usr      *entity.User
+		password string
+		now      time.Time
+		wantErr  error
+	}{
+		{name: "Login_Success", usr: usr, password: "password", now: inTime},
+		{name: "Wrong_Password", usr: usr, password: "other", now: inTime, wantErr: ErrWrongPassword},
+		{name: "Unknown_Login", usr: nil, password: "password", now: inTime, wantErr: ErrWrongPassword},
+		{name: "Expired_Handshake", usr: usr, password: "password", now: inTime.Add(handshakeTTL), wantErr: ErrHandshakeNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := srpLogin(t, tt.usr, tt.password, tt.now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Finish() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && h.UserUUID != usr.UUID {
+				t.Errorf("Finish() user = %q, want %q", h.UserUUID, usr.UUID)
+			}
+			if errors.Is(tt.wantErr, ErrWrongPassword) && h.Login != "test" {
+				t.Errorf("Finish() must return the handshake login on a wrong proof, got %+v", h)
+			}
+		})
+	}
+}
+
+func TestSRP_Start(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	users := mocks.NewMockVerifierRepository(ctrl)
+	handshakes := mocks.NewMockHandshakeRepository(ctrl)
+	s := NewSRP(users, handshakes, []byte("seed"), false)
+
+	client, err := srplib.NewClient("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Legacy_User_Without_Legacy_Login", func(t *testing.T) {
+		users.EXPECT().Read(gomock.Any(), "legacy").Times(1).Return(&entity.User{UUID: "uuid", Password: "$2a$10$hash"}, nil)
+		handshakes.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+			func(_ context.Context, h entity.SRPHandshake) (string, error) {
+				if h.UserUUID != "" {
+					t.Errorf("Create() user = %q, want fake handshake", h.UserUUID)
+				}
+				return handshakeID, nil
+			})
+
+		got, err := s.Start(context.Background(), "legacy", client.PublicKey())
+		if err != nil {
+			t.Fatalf("Start() error = %v", err)
+		}
+		if got.Legacy || !bytes.Equal(got.Salt, s.fakeSalt("legacy")) {
+			t.Errorf("Start() got = %+v, want the same answer as for an unknown login", got)
+		}
+	})
+
+	t.Run("Same_Answer_For_Every_Login", func(t *testing.T) {
+		enrolled := &entity.User{UUID: "enrolled-uuid", SRPSalt: bytes.Repeat([]byte{1}, srplib.SaltSize), SRPVerifier: srplib.FakeVerifier([]byte("enrolled"))}
+		withoutVerifier := &entity.User{UUID: "legacy-uuid", Password: "$2a$10$hash"}
+
+		for _, legacy := range []bool{false, true} {
+			ls := NewSRP(users, handshakes, []byte("seed"), legacy)
+			users.EXPECT().Read(gomock.Any(), "enrolled").Times(1).Return(enrolled, nil)
+			users.EXPECT().Read(gomock.Any(), "legacy").Times(1).Return(withoutVerifier, nil)
+			users.EXPECT().Read(gomock.Any(), "nobody").Times(1).Return(nil, nil)
+			handshakes.EXPECT().Create(gomock.Any(), gomock.Any()).Times(3).Return(handshakeID, nil)
+
+			// Ответы различаются только значениями, но не набором и размером полей.
+			shape := func(ch *entity.SRPChallenge) string {
+				return fmt.Sprintf("legacy=%t handshake=%t salt=%d public=%d", ch.Legacy, ch.HandshakeID != "", len(ch.Salt), len(ch.ServerPublic))
+			}
+			var want string
+			for _, login := range []string{"enrolled", "legacy", "nobody"} {
+				got, err := ls.Start(context.Background(), login, client.PublicKey())
+				if err != nil {
+					t.Fatalf("Start(%q) error = %v", login, err)
+				}
+				if got.Legacy != legacy {
+					t.Errorf("Start(%q) Legacy = %t, want %t", login, got.Legacy, legacy)
+				}
+				if want == "" {
+					want = shape(got)
+				}
+				if shape(got) != want {
+					t.Errorf("Start(%q) got %s, want %s as for an enrolled login", login, shape(got), want)
+				}
+			}
+		}
+	})
+
+	t.Run("Unknown_Login_Stable_Salt", func(t *testing.T) {
+		users.EXPECT().Read(gomock.Any(), "nobody").Times(2).Return(nil, nil)
+		handshakes.EXPECT().Create(gomock.Any(), gomock.Any()).Times(2).Return(handshakeID, nil)
+
+		first, err := s.Start(context.Background(), "nobody", client.PublicKey())
+		if err != nil {
+			t.Fatalf("Start() error = %v", err)
+		}
+		second, err := s.Start(context.Background(), "nobody", client.PublicKey())
+		if err != nil {
+			t.Fatalf("Start() error = %v", err)
+		}
+		if first.Legacy || len(first.Salt) != srplib.SaltSize || !bytes.Equal(first.Salt, second.Salt) {
+			t.Errorf("Start() salts %x and %x must be equal and look real", first.Salt, second.Salt)
+		}
+	})
+
+	t.Run("Invalid_Public_Key", func(t *testing.T) {
+		users.EXPECT().Read(gomock.Any(), "nobody").Times(1).Return(nil, nil)
+
+		if _, err := s.Start(context.Background(), "nobody", []byte{0}); !errors.Is(err, ErrInvalidPublicKey) {
+			t.Errorf("Start() error = %v, want %v", err, ErrInvalidPublicKey)
+		}
+	})
+
+	t.Run("Empty_Login", func(t *testing.T) {
+		if _, err := s.Start(context.Background(), " ", client.PublicKey()); !errors.Is(err, ErrLoginOrPwdEmpty) {
+			t.Errorf("Start() error = %v, want %v", err, ErrLoginOrPwdEmpty)
+		}
+	})
+
+	t.Run("Malformed_Handshake_ID", func(t *testing.T) {
+		if _, err := s.Finish(context.Background(), "not-a-uuid", []byte("proof")); !errors.Is(err, ErrHandshakeNotFound) {
+			t.Errorf("Finish() error = %v, want %v", err, ErrHandshakeNotFound)
+		}
+	})
+}
+
+func TestSRP_Register(t *testing.T) {
+	salt := []byte("0123456789abcdef")
+	verifier := srplib.FakeVerifier([]byte("verifier"))
+
+	tests := []struct {
+		name     string
+		login    string
+		salt     []byte
+		verifier []byte
+		repo     func(repo *mocks.MockVerifierRepository)
+		wantErr  error
+	}{
+		{
+			name:     "Register_Success",
+			login:    " test ",
+			salt:     salt,
+			verifier: verifier,
+			repo: func(repo *mocks.MockVerifierRepository) {
+				repo.EXPECT().CreateWithVerifier(gomock.Any(), "test", salt, verifier).Times(1).Return(&entity.User{UUID: "uuid"}, nil)
+			},
+		},
+		{
+			name:     "Login_Exists",
+			login:    "test",
+			salt:     salt,
+			verifier: verifier,
+			repo: func(repo *mocks.MockVerifierRepository) {
+				repo.EXPECT().CreateWithVerifier(gomock.Any(), "test", salt, verifier).Times(1).Return(nil, nil)
+			},
+			wantErr: ErrLoginExists,
+		},
+		{
+			name:    "Empty_Verifier",
+			login:   "test",
+			salt:    salt,
+			repo:    func(*mocks.MockVerifierRepository) {},
+			wantErr: ErrLoginOrPwdEmpty,
+		},
+		{
+			name:     "Invalid_Verifier",
+			login:    "test",
+			salt:     salt,
+			verifier: []byte{0},
+			repo:     func(*mocks.MockVerifierRepository) {},
+			wantErr:  ErrInvalidVerifier,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockVerifierRepository(ctrl)
+			tt.repo(repo)
+
+			_, err := NewSRP(repo, mocks.NewMockHandshakeRepository(ctrl), []byte("seed"), false).Register(context.Background(), tt.login, tt.salt, tt.verifier)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Register() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
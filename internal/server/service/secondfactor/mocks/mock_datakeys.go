@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ktigay/goph-keeper/internal/server/service/secondfactor (interfaces: DataKeys)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	cipher "crypto/cipher"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockDataKeys is a mock of DataKeys interface.
+type MockDataKeys struct {
+	ctrl     *gomock.Controller
+	recorder *MockDataKeysMockRecorder
+}
+
+// MockDataKeysMockRecorder is the mock recorder for MockDataKeys.
+type MockDataKeysMockRecorder struct {
+	mock *MockDataKeys
+}
+
+// NewMockDataKeys creates a new mock instance.
+func NewMockDataKeys(ctrl *gomock.Controller) *MockDataKeys {
+	mock := &MockDataKeys{ctrl: ctrl}
+	mock.recorder = &MockDataKeysMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDataKeys) EXPECT() *MockDataKeysMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockDataKeys) Get(arg0 context.Context, arg1 string) (cipher.AEAD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	ret0, _ := ret[0].(cipher.AEAD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockDataKeysMockRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockDataKeys)(nil).Get), arg0, arg1)
+}
@@ -0,0 +1,277 @@
+package secondfactor
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ktigay/goph-keeper/internal/otp"
+	"github.com/ktigay/goph-keeper/internal/server/entity"
+	"github.com/ktigay/goph-keeper/internal/server/security/envelope"
+)
+
+const (
+	// Issuer издатель в otpauth:// URI.
+	Issuer = "goph-keeper"
+
+	secretSize         = 20
+	recoveryCodesCount = 10
+	recoveryCodeSize   = 10
+	// skew допустимое расхождение часов клиента и сервера в интервалах TOTP.
+	skew = 1
+)
+
+var (
+	// ErrUserNotFound пользователь не найден.
+	ErrUserNotFound = errors.New("user not found")
+	// ErrNotEnrolled второй фактор не подключен.
+	ErrNotEnrolled = errors.New("second factor is not enrolled")
+	// ErrAlreadyEnabled второй фактор уже включен.
+	ErrAlreadyEnabled = errors.New("second factor is already enabled")
+	// ErrInvalidCode неверный или уже использованный код.
+	ErrInvalidCode = errors.New("invalid second factor code")
+)
+
+// Repository репозиторий.
+//
+//go:generate mockgen -destination=./mocks/mock_secondfactor.go -package=mocks github.com/ktigay/goph-keeper/internal/server/service/secondfactor Repository
+type Repository interface {
+	Save(ctx context.Context, sf entity.SecondFactor) (*entity.SecondFactor, error)
+	Read(ctx context.Context, userUUID string) (*entity.SecondFactor, error)
+	Enable(ctx context.Context, userUUID string, step int64) (bool, error)
+	UseStep(ctx context.Context, userUUID string, step int64) (bool, error)
+	ReplaceRecoveryCodes(ctx context.Context, userUUID string, hashes [][]byte) error
+	UseRecoveryCode(ctx context.Context, userUUID string, hash []byte) (bool, error)
+}
+
+// UserRepository репозиторий пользователей.
+//
+//go:generate mockgen -destination=./mocks/mock_user.go -package=mocks github.com/ktigay/goph-keeper/internal/server/service/secondfactor UserRepository
+type UserRepository interface {
+	ReadByUUID(ctx context.Context, uuid string) (*entity.User, error)
+}
+
+// DataKeys ключи данных пользователей.
+//
+//go:generate mockgen -destination=./mocks/mock_datakeys.go -package=mocks github.com/ktigay/goph-keeper/internal/server/service/secondfactor DataKeys
+type DataKeys interface {
+	Get(ctx context.Context, userUUID string) (cipher.AEAD, error)
+}
+
+// Service сервис второго фактора аккаунта (TOTP, RFC 6238).
+// Секрет хранится зашифрованным ключом данных пользователя, коды восстановления - в виде хеша.
+type Service struct {
+	repo  Repository
+	users UserRepository
+	keys  DataKeys
+	now   func() time.Time
+}
+
+// Enroll создает новый секрет и коды восстановления.
+// Второй фактор включается только после подтверждения кодом в [Service.Confirm].
+func (s *Service) Enroll(ctx context.Context, userUUID string) (string, []string, error) {
+	usr, err := s.users.ReadByUUID(ctx, userUUID)
+	if err != nil {
+		return "", nil, err
+	}
+	if usr == nil {
+		return "", nil, ErrUserNotFound
+	}
+
+	secret := make([]byte, secretSize)
+	if _, err = rand.Read(secret); err != nil {
+		return "", nil, err
+	}
+	sealed, err := s.seal(ctx, userUUID, secret)
+	if err != nil {
+		return "", nil, err
+	}
+
+	saved, err := s.repo.Save(ctx, entity.SecondFactor{
+		UserUUID: userUUID,
+		Secret:   sealed,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	if saved == nil {
+		return "", nil, ErrAlreadyEnabled
+	}
+
+	codes := make([]string, 0, recoveryCodesCount)
+	hashes := make([][]byte, 0, recoveryCodesCount)
+	for range recoveryCodesCount {
+		var code string
+		if code, err = newRecoveryCode(); err != nil {
+			return "", nil, err
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(userUUID, code))
+	}
+	if err = s.repo.ReplaceRecoveryCodes(ctx, userUUID, hashes); err != nil {
+		return "", nil, err
+	}
+
+	key := otp.Key{
+		Type:      otp.TypeTOTP,
+		Secret:    otp.EncodeSecret(secret),
+		Issuer:    Issuer,
+		Account:   usr.Login,
+		Algorithm: otp.AlgorithmSHA1,
+		Digits:    otp.DefaultDigits,
+		Period:    otp.DefaultPeriod,
+	}
+	return key.URI(), codes, nil
+}
+
+// Confirm включает второй фактор, если code соответствует секрету из [Service.Enroll].
+func (s *Service) Confirm(ctx context.Context, userUUID, code string) error {
+	sf, secret, err := s.read(ctx, userUUID)
+	if err != nil {
+		return err
+	}
+	if sf.Enabled {
+		return ErrAlreadyEnabled
+	}
+
+	step, ok := s.matchTOTP(secret, normalizeCode(code), 0)
+	if !ok {
+		return ErrInvalidCode
+	}
+	if ok, err = s.repo.Enable(ctx, userUUID, step); err != nil {
+		return err
+	}
+	if !ok {
+		return ErrAlreadyEnabled
+	}
+	return nil
+}
+
+// IsEnabled возвращает true, если для аккаунта включен второй фактор.
+func (s *Service) IsEnabled(ctx context.Context, userUUID string) (bool, error) {
+	sf, err := s.repo.Read(ctx, userUUID)
+	if err != nil {
+		return false, err
+	}
+	return sf != nil && sf.Enabled, nil
+}
+
+// Verify проверяет TOTP код или код восстановления. Каждый код принимается один раз.
+func (s *Service) Verify(ctx context.Context, userUUID, code string) error {
+	sf, secret, err := s.read(ctx, userUUID)
+	if err != nil {
+		return err
+	}
+	if !sf.Enabled {
+		return ErrNotEnrolled
+	}
+
+	code = normalizeCode(code)
+	if len(code) == otp.DefaultDigits {
+		step, ok := s.matchTOTP(secret, code, sf.LastUsedStep)
+		if !ok {
+			return ErrInvalidCode
+		}
+		// Условное обновление не дает принять один код в параллельных запросах.
+		if ok, err = s.repo.UseStep(ctx, userUUID, step); err != nil {
+			return err
+		}
+		if !ok {
+			return ErrInvalidCode
+		}
+		return nil
+	}
+
+	ok, err := s.repo.UseRecoveryCode(ctx, userUUID, hashRecoveryCode(userUUID, code))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidCode
+	}
+	return nil
+}
+
+func (s *Service) read(ctx context.Context, userUUID string) (*entity.SecondFactor, []byte, error) {
+	sf, err := s.repo.Read(ctx, userUUID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if sf == nil {
+		return nil, nil, ErrNotEnrolled
+	}
+
+	aead, err := s.keys.Get(ctx, userUUID)
+	if err != nil {
+		return nil, nil, err
+	}
+	secret, err := envelope.Open(aead, sf.Secret, secretAD(userUUID))
+	if err != nil {
+		return nil, nil, err
+	}
+	return sf, secret, nil
+}
+
+func (s *Service) seal(ctx context.Context, userUUID string, secret []byte) ([]byte, error) {
+	aead, err := s.keys.Get(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	return envelope.Seal(aead, secret, secretAD(userUUID))
+}
+
+// matchTOTP ищет интервал после lastStep, код которого совпадает с code.
+func (s *Service) matchTOTP(secret []byte, code string, lastStep int64) (int64, bool) {
+	current := s.now().Unix() / otp.DefaultPeriod
+	for step := current - skew; step <= current+skew; step++ {
+		if step <= lastStep {
+			continue
+		}
+		want, err := otp.HOTP(secret, uint64(step), otp.DefaultDigits, otp.AlgorithmSHA1)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(want), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+func secretAD(userUUID string) []byte {
+	return []byte(userUUID + "|totp")
+}
+
+func newRecoveryCode() (string, error) {
+	b := make([]byte, recoveryCodeSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	code := otp.EncodeSecret(b)[:recoveryCodeSize]
+	return code[:recoveryCodeSize/2] + "-" + code[recoveryCodeSize/2:], nil
+}
+
+func hashRecoveryCode(userUUID, code string) []byte {
+	sum := sha256.Sum256([]byte(userUUID + ":" + normalizeCode(code)))
+	return sum[:]
+}
+
+func normalizeCode(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	return strings.NewReplacer(" ", "", "-", "").Replace(code)
+}
+
+// New конструктор.
+func New(r Repository, u UserRepository, k DataKeys) *Service {
+	return &Service{
+		repo:  r,
+		users: u,
+		keys:  k,
+		now:   time.Now,
+	}
+}
@@ -0,0 +1,167 @@
+package secondfactor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/ktigay/goph-keeper/internal/otp"
+	"github.com/ktigay/goph-keeper/internal/server/entity"
+	"github.com/ktigay/goph-keeper/internal/server/security/envelope"
+	"github.com/ktigay/goph-keeper/internal/server/service/secondfactor/mocks"
+)
+
+const userUUID = "33b06619-1ee7-3db5-827d-0dc85df1f759"
+
+func newKeys(t *testing.T, ctrl *gomock.Controller) DataKeys {
+	t.Helper()
+	aead, err := envelope.NewAEAD(bytes.Repeat([]byte{1}, envelope.KeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	k := mocks.NewMockDataKeys(ctrl)
+	k.EXPECT().Get(gomock.Any(), userUUID).AnyTimes().Return(aead, nil)
+	return k
+}
+
+func TestService_Enroll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	keys := newKeys(t, ctrl)
+
+	users := mocks.NewMockUserRepository(ctrl)
+	users.EXPECT().ReadByUUID(gomock.Any(), userUUID).Times(1).Return(&entity.User{UUID: userUUID, Login: "alice"}, nil)
+
+	var (
+		saved  entity.SecondFactor
+		hashes [][]byte
+	)
+	repo := mocks.NewMockRepository(ctrl)
+	repo.EXPECT().Save(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+		func(_ context.Context, sf entity.SecondFactor) (*entity.SecondFactor, error) {
+			saved = sf
+			return &sf, nil
+		})
+	repo.EXPECT().ReplaceRecoveryCodes(gomock.Any(), userUUID, gomock.Any()).Times(1).DoAndReturn(
+		func(_ context.Context, _ string, h [][]byte) error {
+			hashes = h
+			return nil
+		})
+
+	uri, codes, err := New(repo, users, keys).Enroll(context.Background(), userUUID)
+	if err != nil {
+		t.Fatalf("Enroll() error = %v", err)
+	}
+
+	key, err := otp.ParseURI(uri)
+	if err != nil {
+		t.Fatalf("ParseURI() error = %v", err)
+	}
+	if key.Issuer != Issuer || key.Account != "alice" {
+		t.Errorf("Enroll() uri label = %s:%s", key.Issuer, key.Account)
+	}
+	secret, _ := otp.DecodeSecret(key.Secret)
+	if bytes.Contains(saved.Secret, secret) {
+		t.Errorf("Enroll() secret must be stored encrypted")
+	}
+	if len(codes) != recoveryCodesCount || len(hashes) != recoveryCodesCount {
+		t.Fatalf("Enroll() got %d codes, %d hashes", len(codes), len(hashes))
+	}
+	if !bytes.Equal(hashes[0], hashRecoveryCode(userUUID, codes[0])) {
+		t.Errorf("Enroll() recovery codes must be stored hashed")
+	}
+}
+
+func TestService_Verify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	step := now.Unix() / otp.DefaultPeriod
+	secret := []byte("12345678901234567890")
+	code, _ := otp.TOTP(secret, now, otp.DefaultPeriod, otp.DefaultDigits, otp.AlgorithmSHA1)
+
+	aead, _ := envelope.NewAEAD(bytes.Repeat([]byte{1}, envelope.KeySize))
+	sealed, _ := envelope.Seal(aead, secret, secretAD(userUUID))
+
+	tests := []struct {
+		name    string
+		code    string
+		repo    func(ctrl *gomock.Controller) Repository
+		wantErr error
+	}{
+		{
+			name: "Verify_TOTP_Success",
+			code: code,
+			repo: func(ctrl *gomock.Controller) Repository {
+				r := mocks.NewMockRepository(ctrl)
+				r.EXPECT().Read(gomock.Any(), userUUID).Times(1).Return(&entity.SecondFactor{Secret: sealed, Enabled: true}, nil)
+				r.EXPECT().UseStep(gomock.Any(), userUUID, step).Times(1).Return(true, nil)
+				return r
+			},
+		},
+		{
+			name: "Verify_TOTP_Replay_Error",
+			code: code,
+			repo: func(ctrl *gomock.Controller) Repository {
+				r := mocks.NewMockRepository(ctrl)
+				r.EXPECT().Read(gomock.Any(), userUUID).Times(1).Return(&entity.SecondFactor{Secret: sealed, Enabled: true, LastUsedStep: step}, nil)
+				r.EXPECT().UseStep(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				return r
+			},
+			wantErr: ErrInvalidCode,
+		},
+		{
+			name: "Verify_TOTP_Wrong_Code_Error",
+			code: "000000",
+			repo: func(ctrl *gomock.Controller) Repository {
+				r := mocks.NewMockRepository(ctrl)
+				r.EXPECT().Read(gomock.Any(), userUUID).Times(1).Return(&entity.SecondFactor{Secret: sealed, Enabled: true}, nil)
+				return r
+			},
+			wantErr: ErrInvalidCode,
+		},
+		{
+			name: "Verify_Recovery_Code_Success",
+			code: "abcde-fghij",
+			repo: func(ctrl *gomock.Controller) Repository {
+				r := mocks.NewMockRepository(ctrl)
+				r.EXPECT().Read(gomock.Any(), userUUID).Times(1).Return(&entity.SecondFactor{Secret: sealed, Enabled: true}, nil)
+				r.EXPECT().UseRecoveryCode(gomock.Any(), userUUID, hashRecoveryCode(userUUID, "ABCDEFGHIJ")).Times(1).Return(true, nil)
+				return r
+			},
+		},
+		{
+			name: "Verify_Used_Recovery_Code_Error",
+			code: "ABCDE-FGHIJ",
+			repo: func(ctrl *gomock.Controller) Repository {
+				r := mocks.NewMockRepository(ctrl)
+				r.EXPECT().Read(gomock.Any(), userUUID).Times(1).Return(&entity.SecondFactor{Secret: sealed, Enabled: true}, nil)
+				r.EXPECT().UseRecoveryCode(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				return r
+			},
+			wantErr: ErrInvalidCode,
+		},
+		{
+			name: "Verify_Not_Confirmed_Error",
+			code: code,
+			repo: func(ctrl *gomock.Controller) Repository {
+				r := mocks.NewMockRepository(ctrl)
+				r.EXPECT().Read(gomock.Any(), userUUID).Times(1).Return(&entity.SecondFactor{Secret: sealed}, nil)
+				return r
+			},
+			wantErr: ErrNotEnrolled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			s := New(tt.repo(ctrl), mocks.NewMockUserRepository(ctrl), newKeys(t, ctrl))
+			s.now = func() time.Time { return now }
+
+			if err := s.Verify(context.Background(), userUUID, tt.code); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
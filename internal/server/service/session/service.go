@@ -0,0 +1,170 @@
+package session
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/ktigay/goph-keeper/internal/server/entity"
+)
+
+const refreshTokenSize = 32
+
+var (
+	// ErrSessionNotFound сессия не найдена.
+	ErrSessionNotFound = errors.New("session not found")
+	// ErrSessionExpired срок действия refresh токена истек.
+	ErrSessionExpired = errors.New("session expired")
+	// ErrSessionRevoked сессия отозвана.
+	ErrSessionRevoked = errors.New("session revoked")
+	// ErrRefreshTokenReused повторное использование refresh токена, сессия отозвана.
+	ErrRefreshTokenReused = errors.New("refresh token reused")
+)
+
+// Repository репозиторий.
+//
+//go:generate mockgen -destination=./mocks/mock_session.go -package=mocks github.com/ktigay/goph-keeper/internal/server/service/session Repository
+type Repository interface {
+	Create(ctx context.Context, s entity.Session) (*entity.Session, error)
+	ReadByRefreshHash(ctx context.Context, hash []byte) (*entity.Session, error)
+	Rotate(ctx context.Context, uuid string, oldHash, newHash []byte, expiresAt time.Time) (*entity.Session, error)
+	Revoke(ctx context.Context, uuid string) error
+	ListActive(ctx context.Context, userUUID string) ([]entity.Session, error)
+	RevokeByUser(ctx context.Context, uuid, userUUID string) (bool, error)
+	RevokeAllExcept(ctx context.Context, userUUID, keepUUID string) (int64, error)
+	RevokeAll(ctx context.Context, userUUID string) (int64, error)
+	Touch(ctx context.Context, uuid, ip string) (bool, error)
+}
+
+// Service сервис сессий. Refresh токены хранятся только в виде хеша
+// и заменяются при каждом использовании.
+type Service struct {
+	repo Repository
+	ttl  time.Duration
+	now  func() time.Time
+}
+
+// Create создает сессию пользователя с устройства из sess, возвращает её и refresh токен.
+func (s *Service) Create(ctx context.Context, sess entity.Session) (*entity.Session, string, error) {
+	token, hash, err := newRefreshToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	sess.RefreshHash = hash
+	sess.ExpiresAt = s.now().Add(s.ttl)
+	created, err := s.repo.Create(ctx, sess)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return created, token, nil
+}
+
+// Refresh проверяет refresh токен и заменяет его новым.
+// Предъявление уже замененного токена означает его утечку: сессия отзывается.
+func (s *Service) Refresh(ctx context.Context, token string) (*entity.Session, string, error) {
+	hash := hashRefreshToken(token)
+
+	sess, err := s.repo.ReadByRefreshHash(ctx, hash)
+	if err != nil {
+		return nil, "", err
+	}
+	if sess == nil {
+		return nil, "", ErrSessionNotFound
+	}
+	if sess.RevokedAt != nil {
+		return nil, "", ErrSessionRevoked
+	}
+	if !bytes.Equal(sess.RefreshHash, hash) {
+		return nil, "", s.revokeReused(ctx, sess.UUID)
+	}
+	if !s.now().Before(sess.ExpiresAt) {
+		return nil, "", ErrSessionExpired
+	}
+
+	next, nextHash, err := newRefreshToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	rotated, err := s.repo.Rotate(ctx, sess.UUID, hash, nextHash, s.now().Add(s.ttl))
+	if err != nil {
+		return nil, "", err
+	}
+	if rotated == nil {
+		// Токен заменили параллельным запросом.
+		return nil, "", s.revokeReused(ctx, sess.UUID)
+	}
+
+	return rotated, next, nil
+}
+
+// List возвращает действующие сессии пользователя.
+func (s *Service) List(ctx context.Context, userUUID string) ([]entity.Session, error) {
+	return s.repo.ListActive(ctx, userUUID)
+}
+
+// Revoke отзывает сессию пользователя.
+func (s *Service) Revoke(ctx context.Context, userUUID, uuid string) error {
+	ok, err := s.repo.RevokeByUser(ctx, uuid, userUUID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+// RevokeAllOther отзывает все сессии пользователя, кроме текущей. Возвращает количество отозванных.
+func (s *Service) RevokeAllOther(ctx context.Context, userUUID, currentUUID string) (int64, error) {
+	return s.repo.RevokeAllExcept(ctx, userUUID, currentUUID)
+}
+
+// RevokeAll отзывает все сессии пользователя. Возвращает количество отозванных.
+func (s *Service) RevokeAll(ctx context.Context, userUUID string) (int64, error) {
+	return s.repo.RevokeAll(ctx, userUUID)
+}
+
+// Touch отмечает активность сессии. Возвращает false, если сессия отозвана.
+func (s *Service) Touch(ctx context.Context, uuid, ip string) (bool, error) {
+	if uuid == "" {
+		return false, nil
+	}
+	return s.repo.Touch(ctx, uuid, ip)
+}
+
+func (s *Service) revokeReused(ctx context.Context, uuid string) error {
+	if err := s.repo.Revoke(ctx, uuid); err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
+
+func newRefreshToken() (string, []byte, error) {
+	b := make([]byte, refreshTokenSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	return token, hashRefreshToken(token), nil
+}
+
+func hashRefreshToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
+
+// New конструктор.
+func New(r Repository, ttl time.Duration) *Service {
+	return &Service{
+		repo: r,
+		ttl:  ttl,
+		now:  time.Now,
+	}
+}
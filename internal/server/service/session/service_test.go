@@ -0,0 +1,201 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/ktigay/goph-keeper/internal/server/entity"
+	"github.com/ktigay/goph-keeper/internal/server/service/session/mocks"
+)
+
+func TestService_Create(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockRepository(ctrl)
+
+	var stored entity.Session
+	repo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+		func(_ context.Context, s entity.Session) (*entity.Session, error) {
+			stored = s
+			s.UUID = "7b1c0a52-5a3e-4e0b-9d55-2f3f1d3c6a10"
+			return &s, nil
+		})
+
+	s := New(repo, time.Hour)
+	s.now = func() time.Time { return now }
+
+	sess, token, err := s.Create(context.Background(), entity.Session{
+		UserUUID:   "33b06619-1ee7-3db5-827d-0dc85df1f759",
+		DeviceName: "laptop",
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if token == "" || sess.UUID == "" {
+		t.Fatalf("Create() got empty token or session")
+	}
+	if string(stored.RefreshHash) != string(hashRefreshToken(token)) {
+		t.Errorf("Create() refresh token must be stored hashed")
+	}
+	if stored.DeviceName != "laptop" {
+		t.Errorf("Create() device_name = %v, want laptop", stored.DeviceName)
+	}
+	if !stored.ExpiresAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("Create() expires_at = %v, want %v", stored.ExpiresAt, now.Add(time.Hour))
+	}
+}
+
+func TestService_Refresh(t *testing.T) {
+	const (
+		token       = "refresh-token"
+		sessionUUID = "7b1c0a52-5a3e-4e0b-9d55-2f3f1d3c6a10"
+	)
+	now := time.Unix(1700000000, 0)
+	revokedAt := now.Add(-time.Minute)
+
+	tests := []struct {
+		name    string
+		repo    func(ctrl *gomock.Controller) Repository
+		wantErr error
+	}{
+		{
+			name: "Refresh_Success",
+			repo: func(ctrl *gomock.Controller) Repository {
+				r := mocks.NewMockRepository(ctrl)
+				r.EXPECT().ReadByRefreshHash(gomock.Any(), hashRefreshToken(token)).Times(1).Return(&entity.Session{
+					UUID:        sessionUUID,
+					RefreshHash: hashRefreshToken(token),
+					ExpiresAt:   now.Add(time.Hour),
+				}, nil)
+				r.EXPECT().Rotate(gomock.Any(), sessionUUID, hashRefreshToken(token), gomock.Any(), now.Add(time.Hour)).Times(1).
+					Return(&entity.Session{UUID: sessionUUID}, nil)
+				r.EXPECT().Revoke(gomock.Any(), gomock.Any()).Times(0)
+				return r
+			},
+		},
+		{
+			name: "Refresh_Not_Found_Error",
+			repo: func(ctrl *gomock.Controller) Repository {
+				r := mocks.NewMockRepository(ctrl)
+				r.EXPECT().ReadByRefreshHash(gomock.Any(), gomock.Any()).Times(1).Return(nil, nil)
+				return r
+			},
+			wantErr: ErrSessionNotFound,
+		},
+		{
+			name: "Refresh_Expired_Error",
+			repo: func(ctrl *gomock.Controller) Repository {
+				r := mocks.NewMockRepository(ctrl)
+				r.EXPECT().ReadByRefreshHash(gomock.Any(), gomock.Any()).Times(1).Return(&entity.Session{
+					UUID:        sessionUUID,
+					RefreshHash: hashRefreshToken(token),
+					ExpiresAt:   now,
+				}, nil)
+				r.EXPECT().Rotate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				return r
+			},
+			wantErr: ErrSessionExpired,
+		},
+		{
+			name: "Refresh_Revoked_Error",
+			repo: func(ctrl *gomock.Controller) Repository {
+				r := mocks.NewMockRepository(ctrl)
+				r.EXPECT().ReadByRefreshHash(gomock.Any(), gomock.Any()).Times(1).Return(&entity.Session{
+					UUID:        sessionUUID,
+					RefreshHash: hashRefreshToken(token),
+					ExpiresAt:   now.Add(time.Hour),
+					RevokedAt:   &revokedAt,
+				}, nil)
+				return r
+			},
+			wantErr: ErrSessionRevoked,
+		},
+		{
+			name: "Refresh_Reused_Token_Revokes_Session",
+			repo: func(ctrl *gomock.Controller) Repository {
+				r := mocks.NewMockRepository(ctrl)
+				r.EXPECT().ReadByRefreshHash(gomock.Any(), gomock.Any()).Times(1).Return(&entity.Session{
+					UUID:                sessionUUID,
+					RefreshHash:         hashRefreshToken("next-token"),
+					PreviousRefreshHash: hashRefreshToken(token),
+					ExpiresAt:           now.Add(time.Hour),
+				}, nil)
+				r.EXPECT().Revoke(gomock.Any(), sessionUUID).Times(1).Return(nil)
+				return r
+			},
+			wantErr: ErrRefreshTokenReused,
+		},
+		{
+			name: "Refresh_Concurrent_Rotation_Revokes_Session",
+			repo: func(ctrl *gomock.Controller) Repository {
+				r := mocks.NewMockRepository(ctrl)
+				r.EXPECT().ReadByRefreshHash(gomock.Any(), gomock.Any()).Times(1).Return(&entity.Session{
+					UUID:        sessionUUID,
+					RefreshHash: hashRefreshToken(token),
+					ExpiresAt:   now.Add(time.Hour),
+				}, nil)
+				r.EXPECT().Rotate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil, nil)
+				r.EXPECT().Revoke(gomock.Any(), sessionUUID).Times(1).Return(nil)
+				return r
+			},
+			wantErr: ErrRefreshTokenReused,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			s := New(tt.repo(ctrl), time.Hour)
+			s.now = func() time.Time { return now }
+
+			sess, next, err := s.Refresh(context.Background(), token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Refresh() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if sess == nil || next == "" || next == token {
+				t.Errorf("Refresh() got = %v, %q, want rotated token", sess, next)
+			}
+		})
+	}
+}
+
+func TestService_Revoke(t *testing.T) {
+	tests := []struct {
+		name    string
+		repo    func(ctrl *gomock.Controller) Repository
+		wantErr error
+	}{
+		{
+			name: "Revoke_Success",
+			repo: func(ctrl *gomock.Controller) Repository {
+				r := mocks.NewMockRepository(ctrl)
+				r.EXPECT().RevokeByUser(gomock.Any(), "session-uuid", "user-uuid").Times(1).Return(true, nil)
+				return r
+			},
+		},
+		{
+			name: "Revoke_Foreign_Session_Not_Found",
+			repo: func(ctrl *gomock.Controller) Repository {
+				r := mocks.NewMockRepository(ctrl)
+				r.EXPECT().RevokeByUser(gomock.Any(), "session-uuid", "user-uuid").Times(1).Return(false, nil)
+				return r
+			},
+			wantErr: ErrSessionNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			s := New(tt.repo(ctrl), time.Hour)
+			if err := s.Revoke(context.Background(), "user-uuid", "session-uuid"); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Revoke() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	entity "github.com/ktigay/goph-keeper/internal/entity"
@@ -51,10 +52,10 @@ func (mr *MockRepositoryMockRecorder) Create(arg0, arg1 interface{}) *gomock.Cal
 }
 
 // Delete mocks base method.
-func (m *MockRepository) Delete(arg0 context.Context, arg1 string, arg2 ...string) error {
+func (m *MockRepository) Delete(arg0 context.Context, arg1 string, arg2 uint64, arg3 ...string) error {
 	m.ctrl.T.Helper()
-	varargs := []interface{}{arg0, arg1}
-	for _, a := range arg2 {
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
 		varargs = append(varargs, a)
 	}
 	ret := m.ctrl.Call(m, "Delete", varargs...)
@@ -63,12 +64,72 @@ func (m *MockRepository) Delete(arg0 context.Context, arg1 string, arg2 ...strin
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockRepositoryMockRecorder) Delete(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Delete(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), varargs...)
 }
 
+// NextRevision mocks base method.
+func (m *MockRepository) NextRevision(arg0 context.Context, arg1 string) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NextRevision", arg0, arg1)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextRevision indicates an expected call of NextRevision.
+func (mr *MockRepositoryMockRecorder) NextRevision(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextRevision", reflect.TypeOf((*MockRepository)(nil).NextRevision), arg0, arg1)
+}
+
+// PruneBatches mocks base method.
+func (m *MockRepository) PruneBatches(arg0 context.Context, arg1 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneBatches", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PruneBatches indicates an expected call of PruneBatches.
+func (mr *MockRepositoryMockRecorder) PruneBatches(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneBatches", reflect.TypeOf((*MockRepository)(nil).PruneBatches), arg0, arg1)
+}
+
+// PruneRevisions mocks base method.
+func (m *MockRepository) PruneRevisions(arg0 context.Context, arg1 int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneRevisions", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PruneRevisions indicates an expected call of PruneRevisions.
+func (mr *MockRepositoryMockRecorder) PruneRevisions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneRevisions", reflect.TypeOf((*MockRepository)(nil).PruneRevisions), arg0, arg1)
+}
+
+// PruneTombstones mocks base method.
+func (m *MockRepository) PruneTombstones(arg0 context.Context, arg1 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneTombstones", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PruneTombstones indicates an expected call of PruneTombstones.
+func (mr *MockRepositoryMockRecorder) PruneTombstones(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneTombstones", reflect.TypeOf((*MockRepository)(nil).PruneTombstones), arg0, arg1)
+}
+
 // Read mocks base method.
 func (m *MockRepository) Read(arg0 context.Context, arg1 string, arg2 ...string) ([]entity.UserData, error) {
 	m.ctrl.T.Helper()
@@ -89,6 +150,80 @@ func (mr *MockRepositoryMockRecorder) Read(arg0, arg1 interface{}, arg2 ...inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Read", reflect.TypeOf((*MockRepository)(nil).Read), varargs...)
 }
 
+// ReadBatch mocks base method.
+func (m *MockRepository) ReadBatch(arg0 context.Context, arg1, arg2 string) (*entity.UserDataBatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadBatch", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*entity.UserDataBatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadBatch indicates an expected call of ReadBatch.
+func (mr *MockRepositoryMockRecorder) ReadBatch(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadBatch", reflect.TypeOf((*MockRepository)(nil).ReadBatch), arg0, arg1, arg2)
+}
+
+// ReadChanges mocks base method.
+func (m *MockRepository) ReadChanges(arg0 context.Context, arg1 string, arg2 uint64) (*entity.UserDataChanges, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadChanges", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*entity.UserDataChanges)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadChanges indicates an expected call of ReadChanges.
+func (mr *MockRepositoryMockRecorder) ReadChanges(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadChanges", reflect.TypeOf((*MockRepository)(nil).ReadChanges), arg0, arg1, arg2)
+}
+
+// ReadRevision mocks base method.
+func (m *MockRepository) ReadRevision(arg0 context.Context, arg1, arg2, arg3 string) (*entity.UserDataRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadRevision", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*entity.UserDataRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadRevision indicates an expected call of ReadRevision.
+func (mr *MockRepositoryMockRecorder) ReadRevision(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadRevision", reflect.TypeOf((*MockRepository)(nil).ReadRevision), arg0, arg1, arg2, arg3)
+}
+
+// ReadRevisions mocks base method.
+func (m *MockRepository) ReadRevisions(arg0 context.Context, arg1, arg2 string) ([]entity.UserDataRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadRevisions", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]entity.UserDataRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadRevisions indicates an expected call of ReadRevisions.
+func (mr *MockRepositoryMockRecorder) ReadRevisions(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadRevisions", reflect.TypeOf((*MockRepository)(nil).ReadRevisions), arg0, arg1, arg2)
+}
+
+// SaveBatch mocks base method.
+func (m *MockRepository) SaveBatch(arg0 context.Context, arg1, arg2 string, arg3 entity.UserDataBatchResult) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveBatch", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveBatch indicates an expected call of SaveBatch.
+func (mr *MockRepositoryMockRecorder) SaveBatch(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveBatch", reflect.TypeOf((*MockRepository)(nil).SaveBatch), arg0, arg1, arg2, arg3)
+}
+
 // Update mocks base method.
 func (m *MockRepository) Update(arg0 context.Context, arg1 entity.UserData) (*entity.UserData, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ktigay/goph-keeper/internal/server/service/userdata (interfaces: VaultRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	entity "github.com/ktigay/goph-keeper/internal/entity"
+)
+
+// MockVaultRepository is a mock of VaultRepository interface.
+type MockVaultRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockVaultRepositoryMockRecorder
+}
+
+// MockVaultRepositoryMockRecorder is the mock recorder for MockVaultRepository.
+type MockVaultRepositoryMockRecorder struct {
+	mock *MockVaultRepository
+}
+
+// NewMockVaultRepository creates a new mock instance.
+func NewMockVaultRepository(ctrl *gomock.Controller) *MockVaultRepository {
+	mock := &MockVaultRepository{ctrl: ctrl}
+	mock.recorder = &MockVaultRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVaultRepository) EXPECT() *MockVaultRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Read mocks base method.
+func (m *MockVaultRepository) Read(arg0 context.Context, arg1 string) (*entity.VaultKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Read", arg0, arg1)
+	ret0, _ := ret[0].(*entity.VaultKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Read indicates an expected call of Read.
+func (mr *MockVaultRepositoryMockRecorder) Read(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Read", reflect.TypeOf((*MockVaultRepository)(nil).Read), arg0, arg1)
+}
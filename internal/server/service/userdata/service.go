@@ -3,8 +3,11 @@ package userdata
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	v "github.com/ktigay/goph-keeper/internal/validator"
 
 	"github.com/ktigay/goph-keeper/internal/entity"
@@ -15,21 +18,84 @@ var (
 	ErrDataNotFound = errors.New("data not found")
 	// ErrBadRequest неправильный запрос.
 	ErrBadRequest = errors.New("bad request")
+	// ErrRevisionNotFound версия записи не найдена.
+	ErrRevisionNotFound = errors.New("revision not found")
+	// ErrStaleKeyVersion версия записи зашифрована не текущим ключом хранилища, её восстанавливает клиент,
+	// перешифровав текущим ключом.
+	ErrStaleKeyVersion = errors.New("revision is encrypted with another vault key")
+	// ErrVersionConflict запись изменена после чтения клиентом.
+	ErrVersionConflict = errors.New("data has been modified by another client")
+
+	// errBatchApplied пакет изменений уже применен, транзакция откатывается.
+	errBatchApplied = errors.New("batch has already been applied")
 )
 
+// batchRetention срок хранения результатов пакетов изменений для повторной отправки.
+const batchRetention = 24 * time.Hour
+
+// ConflictError запись изменена после чтения клиентом, Current - текущая версия на сервере.
+type ConflictError struct {
+	Current entity.UserData
+}
+
+// Error описание ошибки.
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%v: expected another version than %d", ErrVersionConflict, e.Current.Version)
+}
+
+// Is сопоставляет ошибку с [ErrVersionConflict].
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrVersionConflict
+}
+
 // Repository репозиторий.
 //
 //go:generate mockgen -destination=./mocks/mock_userdata.go -package=mocks github.com/ktigay/goph-keeper/internal/server/service/userdata Repository
 type Repository interface {
 	Create(ctx context.Context, data entity.UserData) (*entity.UserData, error)
+	// Update обновляет запись версии data.Version, nil - записи этой версии нет.
 	Update(ctx context.Context, data entity.UserData) (*entity.UserData, error)
-	Delete(ctx context.Context, userUUID string, uuids ...string) error
+	// Delete удаляет записи в ревизии revision.
+	Delete(ctx context.Context, userUUID string, revision uint64, uuids ...string) error
 	Read(ctx context.Context, userUUID string, uuids ...string) ([]entity.UserData, error)
+	// NextRevision увеличивает счётчик ревизий пользователя, блокируя его до конца транзакции.
+	NextRevision(ctx context.Context, userUUID string) (uint64, error)
+	ReadChanges(ctx context.Context, userUUID string, since uint64) (*entity.UserDataChanges, error)
+	ReadRevisions(ctx context.Context, userUUID, itemUUID string) ([]entity.UserDataRevision, error)
+	// ReadRevision возвращает версию записи, nil - версии нет.
+	ReadRevision(ctx context.Context, userUUID, itemUUID, revisionUUID string) (*entity.UserDataRevision, error)
+	PruneRevisions(ctx context.Context, retention int) (int64, error)
+	PruneTombstones(ctx context.Context, before time.Time) (int64, error)
+	// ReadBatch возвращает сохраненный результат пакета изменений, nil - пакет не применялся.
+	ReadBatch(ctx context.Context, userUUID, batchUUID string) (*entity.UserDataBatchResult, error)
+	SaveBatch(ctx context.Context, userUUID, batchUUID string, res entity.UserDataBatchResult) error
+	PruneBatches(ctx context.Context, before time.Time) (int64, error)
+}
+
+// VaultRepository репозиторий ключей хранилища.
+//
+//go:generate mockgen -destination=./mocks/mock_vault.go -package=mocks github.com/ktigay/goph-keeper/internal/server/service/userdata VaultRepository
+type VaultRepository interface {
+	// Read возвращает ключ хранилища пользователя, nil - хранилище не создано.
+	Read(ctx context.Context, userUUID string) (*entity.VaultKey, error)
+}
+
+// TxFacade транзакции.
+//
+//go:generate mockgen -destination=./mocks/mock_tx.go -package=mocks github.com/ktigay/goph-keeper/internal/server/service/userdata TxFacade
+type TxFacade interface {
+	RunInTx(ctx context.Context, opts pgx.TxOptions, fn func(ctxWithTx context.Context) error) error
 }
 
 // Service сервис.
 type Service struct {
-	repo Repository
+	repo  Repository
+	vault VaultRepository
+	tx    TxFacade
+	// historyRetention количество хранимых версий каждой записи, если у пользователя не задано своё.
+	historyRetention int
+	// tombstoneRetention срок хранения отметок об удалении записей.
+	tombstoneRetention time.Duration
 }
 
 // Create создаёт запись пользовательских данных.
@@ -39,17 +105,47 @@ func (s *Service) Create(ctx context.Context, userUUID string, data entity.UserD
 	}
 
 	data.UserUUID = userUUID
-	return s.repo.Create(ctx, data)
+	var d *entity.UserData
+	err := s.inRevision(ctx, userUUID, func(ctx context.Context, revision uint64) (err error) {
+		data.Revision = revision
+		d, err = s.repo.Create(ctx, data)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
 }
 
-// Update обновляет запись пользовательских данных.
+// Update обновляет запись пользовательских данных, если на сервере она всё еще версии data.Version.
+// Иначе возвращает [*ConflictError] с текущей версией записи.
 func (s *Service) Update(ctx context.Context, userUUID string, data entity.UserData) (*entity.UserData, error) {
-	if err := v.ValidateUserData(data); err != nil {
+	if err := v.ValidateUserData(data); err != nil || data.Version == 0 {
 		return nil, ErrBadRequest
 	}
 
 	data.UserUUID = userUUID
-	return s.repo.Update(ctx, data)
+	var d *entity.UserData
+	err := s.inRevision(ctx, userUUID, func(ctx context.Context, revision uint64) (err error) {
+		data.Revision = revision
+		d, err = s.repo.Update(ctx, data)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if d != nil {
+		return d, nil
+	}
+
+	current, err := s.repo.Read(ctx, userUUID, data.UUID)
+	if err != nil {
+		return nil, err
+	}
+	if len(current) == 0 {
+		return nil, ErrDataNotFound
+	}
+	return nil, &ConflictError{Current: current[0]}
 }
 
 // Delete удаляет записи пользовательских данных.
@@ -62,7 +158,180 @@ func (s *Service) Delete(ctx context.Context, userUUID string, uuids ...string)
 			return ErrBadRequest
 		}
 	}
-	return s.repo.Delete(ctx, userUUID, uuids...)
+	return s.inRevision(ctx, userUUID, func(ctx context.Context, revision uint64) error {
+		return s.repo.Delete(ctx, userUUID, revision, uuids...)
+	})
+}
+
+// SyncBatch применяет пакет изменений одной транзакцией и возвращает результат по каждой записи.
+// Изменения записей, измененных или удаленных после чтения клиентом, не применяются, остальные применяются.
+// Пакет с уже примененным batch.UUID повторно не применяется: возвращается сохраненный результат
+// с текущими версиями записей.
+func (s *Service) SyncBatch(ctx context.Context, userUUID string, batch entity.UserDataBatch) (*entity.UserDataBatchResult, error) {
+	if err := validateBatch(batch); err != nil {
+		return nil, err
+	}
+
+	var res *entity.UserDataBatchResult
+	err := s.inRevision(ctx, userUUID, func(ctx context.Context, revision uint64) (err error) {
+		// Изменения пользователя упорядочены блокировкой ревизии, поэтому параллельно
+		// отправленный повтор пакета дождется фиксации первого и увидит его результат.
+		if res, err = s.repo.ReadBatch(ctx, userUUID, batch.UUID); err != nil {
+			return err
+		}
+		if res != nil {
+			// Откат отменяет и увеличение ревизии.
+			return errBatchApplied
+		}
+		if res, err = s.applyBatch(ctx, userUUID, revision, batch); err != nil {
+			return err
+		}
+		return s.repo.SaveBatch(ctx, userUUID, batch.UUID, *res)
+	})
+	if errors.Is(err, errBatchApplied) {
+		return s.replayBatch(ctx, userUUID, res)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// applyBatch применяет изменения пакета в ревизии revision. Вызывается в транзакции.
+func (s *Service) applyBatch(ctx context.Context, userUUID string, revision uint64, batch entity.UserDataBatch) (*entity.UserDataBatchResult, error) {
+	res := &entity.UserDataBatchResult{
+		Revision: revision,
+		Items:    make([]entity.UserDataBatchItem, 0, len(batch.Upserts)+len(batch.Deletes)),
+	}
+
+	current := make(map[string]entity.UserData, len(batch.Upserts))
+	if len(batch.Upserts) > 0 {
+		uuids := make([]string, 0, len(batch.Upserts))
+		for _, d := range batch.Upserts {
+			uuids = append(uuids, d.UUID)
+		}
+		existing, err := s.repo.Read(ctx, userUUID, uuids...)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range existing {
+			current[d.UUID] = d
+		}
+	}
+
+	for _, data := range batch.Upserts {
+		data.UserUUID = userUUID
+		data.Revision = revision
+
+		item := entity.UserDataBatchItem{UUID: data.UUID, Status: entity.BatchItemApplied}
+		cur, exists := current[data.UUID]
+		var err error
+		switch {
+		case data.Version == 0 && !exists:
+			item.Item, err = s.repo.Create(ctx, data)
+		case !exists:
+			item.Status = entity.BatchItemNotFound
+		case data.Version != cur.Version:
+			item.Status, item.Item = entity.BatchItemConflict, &cur
+		default:
+			item.Item, err = s.repo.Update(ctx, data)
+			if err == nil && item.Item == nil {
+				item.Status, item.Item = entity.BatchItemConflict, &cur
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		res.Items = append(res.Items, item)
+	}
+
+	if len(batch.Deletes) > 0 {
+		if err := s.repo.Delete(ctx, userUUID, revision, batch.Deletes...); err != nil {
+			return nil, err
+		}
+	}
+	for _, id := range batch.Deletes {
+		res.Items = append(res.Items, entity.UserDataBatchItem{UUID: id, Status: entity.BatchItemApplied, Deleted: true})
+	}
+	return res, nil
+}
+
+// replayBatch дополняет сохраненный результат пакета текущими версиями записей.
+// Записи, удаленные после применения пакета, остаются без версии.
+func (s *Service) replayBatch(ctx context.Context, userUUID string, res *entity.UserDataBatchResult) (*entity.UserDataBatchResult, error) {
+	res.Replayed = true
+
+	uuids := make([]string, 0, len(res.Items))
+	for _, item := range res.Items {
+		if !item.Deleted && item.Status != entity.BatchItemNotFound {
+			uuids = append(uuids, item.UUID)
+		}
+	}
+	if len(uuids) == 0 {
+		return res, nil
+	}
+
+	current, err := s.repo.Read(ctx, userUUID, uuids...)
+	if err != nil {
+		return nil, err
+	}
+	byUUID := make(map[string]entity.UserData, len(current))
+	for _, d := range current {
+		byUUID[d.UUID] = d
+	}
+	for i := range res.Items {
+		if d, ok := byUUID[res.Items[i].UUID]; ok && !res.Items[i].Deleted {
+			res.Items[i].Item = &d
+		}
+	}
+	return res, nil
+}
+
+// validateBatch проверяет пакет: UUID пакета и записей заданы, каждая запись изменяется один раз.
+func validateBatch(batch entity.UserDataBatch) error {
+	n := len(batch.Upserts) + len(batch.Deletes)
+	if uuid.Validate(batch.UUID) != nil || n == 0 || n > entity.MaxUserDataBatchSize {
+		return ErrBadRequest
+	}
+
+	seen := make(map[string]struct{}, n)
+	unique := func(id string) bool {
+		if uuid.Validate(id) != nil {
+			return false
+		}
+		if _, ok := seen[id]; ok {
+			return false
+		}
+		seen[id] = struct{}{}
+		return true
+	}
+	for _, d := range batch.Upserts {
+		if !unique(d.UUID) || v.ValidateUserData(d) != nil {
+			return ErrBadRequest
+		}
+	}
+	for _, id := range batch.Deletes {
+		if !unique(id) {
+			return ErrBadRequest
+		}
+	}
+	return nil
+}
+
+// Changes возвращает изменения данных пользователя после ревизии since; since = 0 - все записи.
+func (s *Service) Changes(ctx context.Context, userUUID string, since uint64) (*entity.UserDataChanges, error) {
+	var changes *entity.UserDataChanges
+	// Ревизия и изменения читаются из одного снимка: изменения с ревизией не больше
+	// возвращаемой уже зафиксированы, поэтому следующий запрос с ней ничего не пропустит.
+	opts := pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly}
+	err := s.tx.RunInTx(ctx, opts, func(ctx context.Context) (err error) {
+		changes, err = s.repo.ReadChanges(ctx, userUUID, since)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
 }
 
 // Read возвращает записи пользовательских данных.
@@ -83,9 +352,89 @@ func (s *Service) Read(ctx context.Context, userUUID string, uuids ...string) ([
 	return d, nil
 }
 
-// New конструктор.
-func New(r Repository) *Service {
+// Revisions возвращает предыдущие версии записи, от новых к старым.
+func (s *Service) Revisions(ctx context.Context, userUUID, itemUUID string) ([]entity.UserDataRevision, error) {
+	if err := uuid.Validate(itemUUID); err != nil {
+		return nil, ErrBadRequest
+	}
+	return s.repo.ReadRevisions(ctx, userUUID, itemUUID)
+}
+
+// Restore сохраняет версию записи как новую. Удаленная запись создается заново с тем же UUID.
+// Текущее состояние записи при этом сохраняется в историю. Версию, зашифрованную не текущим ключом
+// хранилища, сервер не восстанавливает: ключ старше предыдущего уже удален, а после следующей
+// ротации запись на предыдущем ключе стала бы нечитаемой.
+func (s *Service) Restore(ctx context.Context, userUUID, itemUUID, revisionUUID string) (*entity.UserData, error) {
+	if uuid.Validate(itemUUID) != nil || uuid.Validate(revisionUUID) != nil {
+		return nil, ErrBadRequest
+	}
+
+	rev, err := s.repo.ReadRevision(ctx, userUUID, itemUUID, revisionUUID)
+	if err != nil {
+		return nil, err
+	}
+	if rev == nil {
+		return nil, ErrRevisionNotFound
+	}
+
+	key, err := s.vault.Read(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	if key != nil && rev.Item.KeyVersion != key.KeyVersion {
+		return nil, fmt.Errorf("%w: revision key version %d, vault key version %d", ErrStaleKeyVersion, rev.Item.KeyVersion, key.KeyVersion)
+	}
+
+	current, err := s.repo.Read(ctx, userUUID, itemUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	data := rev.Item
+	data.UpdatedAt = time.Now()
+	if len(current) == 0 {
+		return s.Create(ctx, userUUID, data)
+	}
+	data.Version = current[0].Version
+	return s.Update(ctx, userUUID, data)
+}
+
+// PruneRevisions удаляет версии сверх хранимого количества. Возвращает количество удаленных.
+func (s *Service) PruneRevisions(ctx context.Context) (int64, error) {
+	return s.repo.PruneRevisions(ctx, s.historyRetention)
+}
+
+// PruneTombstones удаляет отметки об удалении старше срока хранения. Возвращает количество удаленных.
+// Клиенты, не синхронизировавшиеся дольше этого срока, получат все записи заново.
+func (s *Service) PruneTombstones(ctx context.Context) (int64, error) {
+	return s.repo.PruneTombstones(ctx, time.Now().Add(-s.tombstoneRetention))
+}
+
+// PruneBatches удаляет результаты пакетов изменений старше срока хранения. Возвращает количество удаленных.
+// Пакет, повторно отправленный позже этого срока, применяется заново.
+func (s *Service) PruneBatches(ctx context.Context) (int64, error) {
+	return s.repo.PruneBatches(ctx, time.Now().Add(-batchRetention))
+}
+
+// inRevision выполняет изменение данных пользователя в транзакции с новой ревизией.
+func (s *Service) inRevision(ctx context.Context, userUUID string, fn func(ctx context.Context, revision uint64) error) error {
+	return s.tx.RunInTx(ctx, pgx.TxOptions{}, func(ctx context.Context) error {
+		revision, err := s.repo.NextRevision(ctx, userUUID)
+		if err != nil {
+			return err
+		}
+		return fn(ctx, revision)
+	})
+}
+
+// New конструктор. historyRetention - количество хранимых версий каждой записи по умолчанию,
+// tombstoneRetention - срок хранения отметок об удалении записей.
+func New(r Repository, vault VaultRepository, tx TxFacade, historyRetention int, tombstoneRetention time.Duration) *Service {
 	return &Service{
-		repo: r,
+		repo:               r,
+		vault:              vault,
+		tx:                 tx,
+		historyRetention:   historyRetention,
+		tombstoneRetention: tombstoneRetention,
 	}
 }
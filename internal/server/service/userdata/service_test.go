@@ -2,10 +2,13 @@ package userdata
 
 import (
 	"context"
+	"errors"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
 	"github.com/ktigay/goph-keeper/internal/server/service/userdata/mocks"
 
 	"github.com/ktigay/goph-keeper/internal/entity"
@@ -32,6 +35,7 @@ func TestService_Create(t *testing.T) {
 			fields: fields{
 				repo: func(ctrl *gomock.Controller) Repository {
 					repo := mocks.NewMockRepository(ctrl)
+					repo.EXPECT().NextRevision(gomock.Any(), gomock.Any()).Times(1).Return(uint64(7), nil)
 					repo.EXPECT().
 						Create(gomock.Any(), gomock.Any()).Times(1).
 						DoAndReturn(func(_ context.Context, data entity.UserData) (*entity.UserData, error) {
@@ -57,6 +61,7 @@ func TestService_Create(t *testing.T) {
 				UserUUID: "513bf07c-2148-43a5-8e18-d42d1548ae48",
 				Type:     entity.DataTypeCard,
 				Data:     []byte(`{"number":"111111","exp_month":"11","exp_year":"11","cvc":"112"}`),
+				Revision: 7,
 			},
 			wantErr: false,
 		},
@@ -89,6 +94,7 @@ func TestService_Create(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			s := &Service{
 				repo: tt.fields.repo(ctrl),
+				tx:   passTx(ctrl),
 			}
 			got, err := s.Create(tt.args.ctx, tt.args.userUUID, tt.args.data)
 			if (err != nil) != tt.wantErr {
@@ -122,8 +128,9 @@ func TestService_Delete(t *testing.T) {
 			fields: fields{
 				repo: func(ctrl *gomock.Controller) Repository {
 					repo := mocks.NewMockRepository(ctrl)
+					repo.EXPECT().NextRevision(gomock.Any(), gomock.Any()).Times(1).Return(uint64(7), nil)
 					repo.EXPECT().
-						Delete(gomock.Any(), gomock.Any(), gomock.All()).Times(1).Return(nil)
+						Delete(gomock.Any(), gomock.Any(), uint64(7), gomock.Any(), gomock.Any()).Times(1).Return(nil)
 					return repo
 				},
 			},
@@ -143,7 +150,7 @@ func TestService_Delete(t *testing.T) {
 				repo: func(ctrl *gomock.Controller) Repository {
 					repo := mocks.NewMockRepository(ctrl)
 					repo.EXPECT().
-						Delete(gomock.Any(), gomock.Any()).Times(0)
+						Delete(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 					return repo
 				},
 			},
@@ -159,6 +166,7 @@ func TestService_Delete(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			s := &Service{
 				repo: tt.fields.repo(ctrl),
+				tx:   passTx(ctrl),
 			}
 			if err := s.Delete(tt.args.ctx, tt.args.userUUID, tt.args.uuids...); (err != nil) != tt.wantErr {
 				t.Errorf("Delete() error = %v, wantErr %v", err, tt.wantErr)
@@ -311,6 +319,7 @@ func TestService_Read(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			s := &Service{
 				repo: tt.fields.repo(ctrl),
+				tx:   passTx(ctrl),
 			}
 			got, err := s.Read(tt.args.ctx, tt.args.userUUID, tt.args.uuids...)
 			if (err != nil) != tt.wantErr {
@@ -339,12 +348,14 @@ func TestService_Update(t *testing.T) {
 		args    args
 		want    *entity.UserData
 		wantErr bool
+		errIs   error
 	}{
 		{
 			name: "Update_UserData_Success",
 			fields: fields{
 				repo: func(ctrl *gomock.Controller) Repository {
 					repo := mocks.NewMockRepository(ctrl)
+					repo.EXPECT().NextRevision(gomock.Any(), gomock.Any()).Times(1).Return(uint64(7), nil)
 					repo.EXPECT().
 						Update(gomock.Any(), gomock.Any()).Times(1).
 						DoAndReturn(func(_ context.Context, data entity.UserData) (*entity.UserData, error) {
@@ -362,6 +373,7 @@ func TestService_Update(t *testing.T) {
 					UserUUID: "4d8de9dc-b3b3-4c45-b71b-189fb41837ea",
 					Type:     entity.DataTypeCard,
 					Data:     []byte(`{"number":"111111","exp_month":"11","exp_year":"11","cvc":"112"}`),
+					Version:  3,
 				},
 			},
 			want: &entity.UserData{
@@ -370,9 +382,85 @@ func TestService_Update(t *testing.T) {
 				UserUUID: "513bf07c-2148-43a5-8e18-d42d1548ae48",
 				Type:     entity.DataTypeCard,
 				Data:     []byte(`{"number":"111111","exp_month":"11","exp_year":"11","cvc":"112"}`),
+				Version:  3,
+				Revision: 7,
 			},
 			wantErr: false,
 		},
+		{
+			name: "Update_UserData_VersionConflict",
+			fields: fields{
+				repo: func(ctrl *gomock.Controller) Repository {
+					repo := mocks.NewMockRepository(ctrl)
+					repo.EXPECT().NextRevision(gomock.Any(), gomock.Any()).Times(1).Return(uint64(7), nil)
+					repo.EXPECT().Update(gomock.Any(), gomock.Any()).Times(1).Return(nil, nil)
+					repo.EXPECT().
+						Read(gomock.Any(), "513bf07c-2148-43a5-8e18-d42d1548ae48", "4d8de9dc-b3b3-4c45-b71b-189fb41837ea").Times(1).
+						Return([]entity.UserData{{UUID: "4d8de9dc-b3b3-4c45-b71b-189fb41837ea", Version: 4}}, nil)
+					return repo
+				},
+			},
+			args: args{
+				ctx:      context.Background(),
+				userUUID: "513bf07c-2148-43a5-8e18-d42d1548ae48",
+				data: entity.UserData{
+					Title:   "title",
+					UUID:    "4d8de9dc-b3b3-4c45-b71b-189fb41837ea",
+					Type:    entity.DataTypeText,
+					Data:    []byte("text"),
+					Version: 3,
+				},
+			},
+			wantErr: true,
+			errIs:   ErrVersionConflict,
+		},
+		{
+			name: "Update_UserData_NotFound",
+			fields: fields{
+				repo: func(ctrl *gomock.Controller) Repository {
+					repo := mocks.NewMockRepository(ctrl)
+					repo.EXPECT().NextRevision(gomock.Any(), gomock.Any()).Times(1).Return(uint64(7), nil)
+					repo.EXPECT().Update(gomock.Any(), gomock.Any()).Times(1).Return(nil, nil)
+					repo.EXPECT().Read(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil, nil)
+					return repo
+				},
+			},
+			args: args{
+				ctx:      context.Background(),
+				userUUID: "513bf07c-2148-43a5-8e18-d42d1548ae48",
+				data: entity.UserData{
+					Title:   "title",
+					UUID:    "4d8de9dc-b3b3-4c45-b71b-189fb41837ea",
+					Type:    entity.DataTypeText,
+					Data:    []byte("text"),
+					Version: 3,
+				},
+			},
+			wantErr: true,
+			errIs:   ErrDataNotFound,
+		},
+		{
+			name: "Update_UserData_NoExpectedVersion",
+			fields: fields{
+				repo: func(ctrl *gomock.Controller) Repository {
+					repo := mocks.NewMockRepository(ctrl)
+					repo.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+					return repo
+				},
+			},
+			args: args{
+				ctx:      context.Background(),
+				userUUID: "513bf07c-2148-43a5-8e18-d42d1548ae48",
+				data: entity.UserData{
+					Title: "title",
+					UUID:  "4d8de9dc-b3b3-4c45-b71b-189fb41837ea",
+					Type:  entity.DataTypeText,
+					Data:  []byte("text"),
+				},
+			},
+			wantErr: true,
+			errIs:   ErrBadRequest,
+		},
 		{
 			name: "Update_UserData_BadRequest",
 			fields: fields{
@@ -402,15 +490,351 @@ func TestService_Update(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			s := &Service{
 				repo: tt.fields.repo(ctrl),
+				tx:   passTx(ctrl),
 			}
 			got, err := s.Update(tt.args.ctx, tt.args.userUUID, tt.args.data)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Update() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if tt.errIs != nil && !errors.Is(err, tt.errIs) {
+				t.Errorf("Update() error = %v, want %v", err, tt.errIs)
+			}
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("Update() got = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func TestService_Restore(t *testing.T) {
+	const (
+		userUUID     = "513bf07c-2148-43a5-8e18-d42d1548ae48"
+		itemUUID     = "4d8de9dc-b3b3-4c45-b71b-189fb41837ea"
+		revisionUUID = "7f0c1a52-8d3e-4b6a-9c2f-1e5d4a3b2c10"
+	)
+	revision := &entity.UserDataRevision{
+		UUID: revisionUUID,
+		Item: entity.UserData{UUID: itemUUID, UserUUID: userUUID, Title: "old", Type: entity.DataTypeText, Data: []byte("old"), Version: 2, KeyVersion: 3},
+	}
+	currentKey := &entity.VaultKey{UserUUID: userUUID, KeyVersion: 3}
+
+	tests := []struct {
+		name    string
+		repo    func(ctrl *gomock.Controller) Repository
+		key     *entity.VaultKey
+		want    *entity.UserData
+		wantErr error
+	}{
+		{
+			name: "Restore_Existing_UpdatesCurrentVersion",
+			repo: func(ctrl *gomock.Controller) Repository {
+				repo := mocks.NewMockRepository(ctrl)
+				repo.EXPECT().ReadRevision(gomock.Any(), userUUID, itemUUID, revisionUUID).Return(revision, nil)
+				repo.EXPECT().Read(gomock.Any(), userUUID, itemUUID).Return([]entity.UserData{{UUID: itemUUID, Version: 5}}, nil)
+				repo.EXPECT().NextRevision(gomock.Any(), gomock.Any()).Times(1).Return(uint64(7), nil)
+				repo.EXPECT().Update(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, d entity.UserData) (*entity.UserData, error) {
+						if d.Version != 5 || d.Title != "old" {
+							t.Errorf("Update() got %+v, want revision content on version 5", d)
+						}
+						d.Version++
+						return &d, nil
+					})
+				return repo
+			},
+			key:  currentKey,
+			want: &entity.UserData{Title: "old", Version: 6},
+		},
+		{
+			name: "Restore_Deleted_CreatesItem",
+			repo: func(ctrl *gomock.Controller) Repository {
+				repo := mocks.NewMockRepository(ctrl)
+				repo.EXPECT().ReadRevision(gomock.Any(), userUUID, itemUUID, revisionUUID).Return(revision, nil)
+				repo.EXPECT().Read(gomock.Any(), userUUID, itemUUID).Return(nil, nil)
+				repo.EXPECT().NextRevision(gomock.Any(), gomock.Any()).Times(1).Return(uint64(7), nil)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, d entity.UserData) (*entity.UserData, error) {
+						if d.UUID != itemUUID {
+							t.Errorf("Create() uuid = %s, want %s", d.UUID, itemUUID)
+						}
+						d.Version = 1
+						return &d, nil
+					})
+				return repo
+			},
+			key:  currentKey,
+			want: &entity.UserData{Title: "old", Version: 1},
+		},
+		{
+			name: "Restore_RevisionNotFound",
+			repo: func(ctrl *gomock.Controller) Repository {
+				repo := mocks.NewMockRepository(ctrl)
+				repo.EXPECT().ReadRevision(gomock.Any(), userUUID, itemUUID, revisionUUID).Return(nil, nil)
+				repo.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+				return repo
+			},
+			wantErr: ErrRevisionNotFound,
+		},
+		{
+			name: "Restore_Stale_Key_Version",
+			repo: func(ctrl *gomock.Controller) Repository {
+				repo := mocks.NewMockRepository(ctrl)
+				repo.EXPECT().ReadRevision(gomock.Any(), userUUID, itemUUID, revisionUUID).Return(revision, nil)
+				repo.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+				return repo
+			},
+			key:     &entity.VaultKey{UserUUID: userUUID, KeyVersion: 4},
+			wantErr: ErrStaleKeyVersion,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			vault := mocks.NewMockVaultRepository(ctrl)
+			vault.EXPECT().Read(gomock.Any(), userUUID).AnyTimes().Return(tt.key, nil)
+			s := New(tt.repo(ctrl), vault, passTx(ctrl), 10, time.Hour)
+			got, err := s.Restore(context.Background(), userUUID, itemUUID, revisionUUID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Restore() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.want == nil {
+				return
+			}
+			if got.Title != tt.want.Title || got.Version != tt.want.Version {
+				t.Errorf("Restore() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestService_Revisions_BadRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockRepository(ctrl)
+	repo.EXPECT().ReadRevisions(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	if _, err := New(repo, nil, passTx(ctrl), 10, time.Hour).Revisions(context.Background(), "513bf07c-2148-43a5-8e18-d42d1548ae48", "not-a-uuid"); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("Revisions() error = %v, want %v", err, ErrBadRequest)
+	}
+}
+
+func TestService_Changes(t *testing.T) {
+	const userUUID = "513bf07c-2148-43a5-8e18-d42d1548ae48"
+	want := &entity.UserDataChanges{
+		Revision: 9,
+		Upserts:  []entity.UserData{{UUID: "4d8de9dc-b3b3-4c45-b71b-189fb41837ea", Revision: 9}},
+		Deleted:  []string{"9b89b845-164b-498d-bc0e-f197fec9008a"},
+	}
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockRepository(ctrl)
+	repo.EXPECT().ReadChanges(gomock.Any(), userUUID, uint64(4)).Times(1).Return(want, nil)
+	tx := mocks.NewMockTxFacade(ctrl)
+	tx.EXPECT().RunInTx(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(ctx context.Context, opts pgx.TxOptions, fn func(context.Context) error) error {
+			if opts.IsoLevel != pgx.RepeatableRead {
+				t.Errorf("RunInTx() isolation = %s, want %s", opts.IsoLevel, pgx.RepeatableRead)
+			}
+			return fn(ctx)
+		})
+
+	got, err := New(repo, nil, tx, 10, time.Hour).Changes(context.Background(), userUUID, 4)
+	if err != nil {
+		t.Fatalf("Changes() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Changes() got = %+v, want %+v", got, want)
+	}
+}
+
+// passTx транзакция, выполняющая функцию без БД.
+func passTx(ctrl *gomock.Controller) TxFacade {
+	tx := mocks.NewMockTxFacade(ctrl)
+	tx.EXPECT().RunInTx(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(ctx context.Context, _ pgx.TxOptions, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+	return tx
+}
+
+func TestService_PruneTombstones(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockRepository(ctrl)
+	repo.EXPECT().PruneTombstones(gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(_ context.Context, before time.Time) (int64, error) {
+			if d := time.Until(before); d > -time.Hour+time.Minute || d < -time.Hour-time.Minute {
+				t.Errorf("PruneTombstones() before = %v, want an hour ago", before)
+			}
+			return 3, nil
+		})
+
+	n, err := New(repo, nil, passTx(ctrl), 10, time.Hour).PruneTombstones(context.Background())
+	if err != nil || n != 3 {
+		t.Errorf("PruneTombstones() = %d, %v, want 3", n, err)
+	}
+}
+
+func TestService_SyncBatch(t *testing.T) {
+	const (
+		userUUID  = "513bf07c-2148-43a5-8e18-d42d1548ae48"
+		batchUUID = "0f5cf0a4-5a2e-4d0c-93a6-3b2b9d3e4a11"
+		newUUID   = "4d8de9dc-b3b3-4c45-b71b-189fb41837ea"
+		oldUUID   = "9b89b845-164b-498d-bc0e-f197fec9008a"
+		delUUID   = "5d33d26d-47b5-4f6e-ac39-cf63f5a1c1cf"
+	)
+	item := func(uuid string, version uint64) entity.UserData {
+		return entity.UserData{UUID: uuid, Title: "title", Type: entity.DataTypeText, Data: []byte("text"), Version: version}
+	}
+
+	type want struct {
+		statuses []entity.BatchItemStatus
+		versions []uint64
+		replayed bool
+	}
+	tests := []struct {
+		name    string
+		repo    func(ctrl *gomock.Controller) Repository
+		batch   entity.UserDataBatch
+		want    want
+		wantErr error
+	}{
+		{
+			name: "SyncBatch_Applied",
+			repo: func(ctrl *gomock.Controller) Repository {
+				repo := mocks.NewMockRepository(ctrl)
+				repo.EXPECT().NextRevision(gomock.Any(), userUUID).Times(1).Return(uint64(7), nil)
+				repo.EXPECT().ReadBatch(gomock.Any(), userUUID, batchUUID).Times(1).Return(nil, nil)
+				repo.EXPECT().Read(gomock.Any(), userUUID, newUUID, oldUUID).Times(1).Return([]entity.UserData{item(oldUUID, 2)}, nil)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, d entity.UserData) (*entity.UserData, error) {
+						if d.UUID != newUUID || d.UserUUID != userUUID || d.Revision != 7 {
+							t.Errorf("Create() data = %+v", d)
+						}
+						d.Version = 1
+						return &d, nil
+					})
+				repo.EXPECT().Update(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, d entity.UserData) (*entity.UserData, error) {
+						d.Version++
+						return &d, nil
+					})
+				repo.EXPECT().Delete(gomock.Any(), userUUID, uint64(7), delUUID).Times(1).Return(nil)
+				repo.EXPECT().SaveBatch(gomock.Any(), userUUID, batchUUID, gomock.Any()).Times(1).Return(nil)
+				return repo
+			},
+			batch: entity.UserDataBatch{
+				UUID:    batchUUID,
+				Upserts: []entity.UserData{item(newUUID, 0), item(oldUUID, 2)},
+				Deletes: []string{delUUID},
+			},
+			want: want{
+				statuses: []entity.BatchItemStatus{entity.BatchItemApplied, entity.BatchItemApplied, entity.BatchItemApplied},
+				versions: []uint64{1, 3, 0},
+			},
+		},
+		{
+			name: "SyncBatch_Conflicts_NotApplied",
+			repo: func(ctrl *gomock.Controller) Repository {
+				repo := mocks.NewMockRepository(ctrl)
+				repo.EXPECT().NextRevision(gomock.Any(), userUUID).Times(1).Return(uint64(7), nil)
+				repo.EXPECT().ReadBatch(gomock.Any(), userUUID, batchUUID).Times(1).Return(nil, nil)
+				repo.EXPECT().Read(gomock.Any(), userUUID, newUUID, oldUUID, delUUID).Times(1).
+					Return([]entity.UserData{item(newUUID, 1), item(oldUUID, 5)}, nil)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().SaveBatch(gomock.Any(), userUUID, batchUUID, gomock.Any()).Times(1).Return(nil)
+				return repo
+			},
+			batch: entity.UserDataBatch{
+				UUID:    batchUUID,
+				Upserts: []entity.UserData{item(newUUID, 0), item(oldUUID, 2), item(delUUID, 3)},
+			},
+			want: want{
+				statuses: []entity.BatchItemStatus{entity.BatchItemConflict, entity.BatchItemConflict, entity.BatchItemNotFound},
+				versions: []uint64{1, 5, 0},
+			},
+		},
+		{
+			name: "SyncBatch_Replayed",
+			repo: func(ctrl *gomock.Controller) Repository {
+				repo := mocks.NewMockRepository(ctrl)
+				repo.EXPECT().NextRevision(gomock.Any(), userUUID).Times(1).Return(uint64(8), nil)
+				repo.EXPECT().ReadBatch(gomock.Any(), userUUID, batchUUID).Times(1).
+					Return(&entity.UserDataBatchResult{Revision: 7, Items: []entity.UserDataBatchItem{
+						{UUID: newUUID, Status: entity.BatchItemApplied},
+						{UUID: delUUID, Status: entity.BatchItemApplied, Deleted: true},
+					}}, nil)
+				repo.EXPECT().Read(gomock.Any(), userUUID, newUUID).Times(1).Return([]entity.UserData{item(newUUID, 1)}, nil)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().SaveBatch(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				return repo
+			},
+			batch: entity.UserDataBatch{
+				UUID:    batchUUID,
+				Upserts: []entity.UserData{item(newUUID, 0)},
+				Deletes: []string{delUUID},
+			},
+			want: want{
+				statuses: []entity.BatchItemStatus{entity.BatchItemApplied, entity.BatchItemApplied},
+				versions: []uint64{1, 0},
+				replayed: true,
+			},
+		},
+		{
+			name: "SyncBatch_DuplicateItem_BadRequest",
+			repo: func(ctrl *gomock.Controller) Repository {
+				repo := mocks.NewMockRepository(ctrl)
+				repo.EXPECT().NextRevision(gomock.Any(), gomock.Any()).Times(0)
+				return repo
+			},
+			batch: entity.UserDataBatch{
+				UUID:    batchUUID,
+				Upserts: []entity.UserData{item(newUUID, 0)},
+				Deletes: []string{newUUID},
+			},
+			wantErr: ErrBadRequest,
+		},
+		{
+			name: "SyncBatch_NoBatchUUID_BadRequest",
+			repo: func(ctrl *gomock.Controller) Repository {
+				repo := mocks.NewMockRepository(ctrl)
+				repo.EXPECT().NextRevision(gomock.Any(), gomock.Any()).Times(0)
+				return repo
+			},
+			batch: entity.UserDataBatch{
+				Upserts: []entity.UserData{item(newUUID, 0)},
+			},
+			wantErr: ErrBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			s := &Service{
+				repo: tt.repo(ctrl),
+				tx:   passTx(ctrl),
+			}
+			got, err := s.SyncBatch(context.Background(), userUUID, tt.batch)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SyncBatch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.Replayed != tt.want.replayed || len(got.Items) != len(tt.want.statuses) {
+				t.Fatalf("SyncBatch() = %+v, want %+v", got, tt.want)
+			}
+			for i, item := range got.Items {
+				var version uint64
+				if item.Item != nil {
+					version = item.Item.Version
+				}
+				if item.Status != tt.want.statuses[i] || version != tt.want.versions[i] {
+					t.Errorf("SyncBatch() item %d = %s v%d, want %s v%d", i, item.Status, version, tt.want.statuses[i], tt.want.versions[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ktigay/goph-keeper/internal/server/service/vault (interfaces: DataRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	entity "github.com/ktigay/goph-keeper/internal/entity"
+)
+
+// MockDataRepository is a mock of DataRepository interface.
+type MockDataRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDataRepositoryMockRecorder
+}
+
+// MockDataRepositoryMockRecorder is the mock recorder for MockDataRepository.
+type MockDataRepositoryMockRecorder struct {
+	mock *MockDataRepository
+}
+
+// NewMockDataRepository creates a new mock instance.
+func NewMockDataRepository(ctrl *gomock.Controller) *MockDataRepository {
+	mock := &MockDataRepository{ctrl: ctrl}
+	mock.recorder = &MockDataRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDataRepository) EXPECT() *MockDataRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountByOtherKeyVersion mocks base method.
+func (m *MockDataRepository) CountByOtherKeyVersion(arg0 context.Context, arg1 string, arg2 uint32) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByOtherKeyVersion", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByOtherKeyVersion indicates an expected call of CountByOtherKeyVersion.
+func (mr *MockDataRepositoryMockRecorder) CountByOtherKeyVersion(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByOtherKeyVersion", reflect.TypeOf((*MockDataRepository)(nil).CountByOtherKeyVersion), arg0, arg1, arg2)
+}
+
+// NextRevision mocks base method.
+func (m *MockDataRepository) NextRevision(arg0 context.Context, arg1 string) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NextRevision", arg0, arg1)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextRevision indicates an expected call of NextRevision.
+func (mr *MockDataRepositoryMockRecorder) NextRevision(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextRevision", reflect.TypeOf((*MockDataRepository)(nil).NextRevision), arg0, arg1)
+}
+
+// Reencrypt mocks base method.
+func (m *MockDataRepository) Reencrypt(arg0 context.Context, arg1 entity.UserData) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reencrypt", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reencrypt indicates an expected call of Reencrypt.
+func (mr *MockDataRepositoryMockRecorder) Reencrypt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reencrypt", reflect.TypeOf((*MockDataRepository)(nil).Reencrypt), arg0, arg1)
+}
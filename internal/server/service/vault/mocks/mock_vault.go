@@ -0,0 +1,126 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ktigay/goph-keeper/internal/server/service/vault (interfaces: Repository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	entity "github.com/ktigay/goph-keeper/internal/entity"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CompleteRotation mocks base method.
+func (m *MockRepository) CompleteRotation(arg0 context.Context, arg1 string) (*entity.VaultKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteRotation", arg0, arg1)
+	ret0, _ := ret[0].(*entity.VaultKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteRotation indicates an expected call of CompleteRotation.
+func (mr *MockRepositoryMockRecorder) CompleteRotation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteRotation", reflect.TypeOf((*MockRepository)(nil).CompleteRotation), arg0, arg1)
+}
+
+// Create mocks base method.
+func (m *MockRepository) Create(arg0 context.Context, arg1 entity.VaultKey) (*entity.VaultKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(*entity.VaultKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRepositoryMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), arg0, arg1)
+}
+
+// Read mocks base method.
+func (m *MockRepository) Read(arg0 context.Context, arg1 string) (*entity.VaultKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Read", arg0, arg1)
+	ret0, _ := ret[0].(*entity.VaultKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Read indicates an expected call of Read.
+func (mr *MockRepositoryMockRecorder) Read(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Read", reflect.TypeOf((*MockRepository)(nil).Read), arg0, arg1)
+}
+
+// ReadForUpdate mocks base method.
+func (m *MockRepository) ReadForUpdate(arg0 context.Context, arg1 string) (*entity.VaultKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(*entity.VaultKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadForUpdate indicates an expected call of ReadForUpdate.
+func (mr *MockRepositoryMockRecorder) ReadForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadForUpdate", reflect.TypeOf((*MockRepository)(nil).ReadForUpdate), arg0, arg1)
+}
+
+// ReadRotation mocks base method.
+func (m *MockRepository) ReadRotation(arg0 context.Context, arg1 string) (*entity.VaultKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadRotation", arg0, arg1)
+	ret0, _ := ret[0].(*entity.VaultKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadRotation indicates an expected call of ReadRotation.
+func (mr *MockRepositoryMockRecorder) ReadRotation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadRotation", reflect.TypeOf((*MockRepository)(nil).ReadRotation), arg0, arg1)
+}
+
+// SaveRotation mocks base method.
+func (m *MockRepository) SaveRotation(arg0 context.Context, arg1 entity.VaultKey) (*entity.VaultKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveRotation", arg0, arg1)
+	ret0, _ := ret[0].(*entity.VaultKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveRotation indicates an expected call of SaveRotation.
+func (mr *MockRepositoryMockRecorder) SaveRotation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRotation", reflect.TypeOf((*MockRepository)(nil).SaveRotation), arg0, arg1)
+}
@@ -0,0 +1,206 @@
+package vault
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ktigay/goph-keeper/internal/entity"
+)
+
+const (
+	minSaltLen       = 16
+	minWrappedKeyLen = 32
+)
+
+var (
+	// ErrVaultKeyNotFound ключ хранилища не найден.
+	ErrVaultKeyNotFound = errors.New("vault key not found")
+	// ErrVaultKeyExists ключ хранилища уже создан.
+	ErrVaultKeyExists = errors.New("vault key already exists")
+	// ErrBadRequest неправильный запрос.
+	ErrBadRequest = errors.New("bad request")
+	// ErrRotationNotFound ротация ключа не начата.
+	ErrRotationNotFound = errors.New("vault key rotation not found")
+	// ErrRotationVersionMismatch версия ключа ротации не соответствует текущему ключу.
+	ErrRotationVersionMismatch = errors.New("vault key rotation version mismatch")
+	// ErrRotationConflict данные изменились во время ротации, ротацию нужно повторить.
+	ErrRotationConflict = errors.New("user data changed during vault key rotation")
+)
+
+// Repository репозиторий.
+//
+//go:generate mockgen -destination=./mocks/mock_vault.go -package=mocks github.com/ktigay/goph-keeper/internal/server/service/vault Repository
+type Repository interface {
+	Create(ctx context.Context, key entity.VaultKey) (*entity.VaultKey, error)
+	Read(ctx context.Context, userUUID string) (*entity.VaultKey, error)
+	ReadForUpdate(ctx context.Context, userUUID string) (*entity.VaultKey, error)
+	SaveRotation(ctx context.Context, key entity.VaultKey) (*entity.VaultKey, error)
+	ReadRotation(ctx context.Context, userUUID string) (*entity.VaultKey, error)
+	CompleteRotation(ctx context.Context, userUUID string) (*entity.VaultKey, error)
+}
+
+// DataRepository репозиторий пользовательских данных.
+//
+//go:generate mockgen -destination=./mocks/mock_data.go -package=mocks github.com/ktigay/goph-keeper/internal/server/service/vault DataRepository
+type DataRepository interface {
+	Reencrypt(ctx context.Context, data entity.UserData) (bool, error)
+	NextRevision(ctx context.Context, userUUID string) (uint64, error)
+	CountByOtherKeyVersion(ctx context.Context, userUUID string, keyVersion uint32) (int, error)
+}
+
+// TxFacade транзакции.
+//
+//go:generate mockgen -destination=./mocks/mock_tx.go -package=mocks github.com/ktigay/goph-keeper/internal/server/service/vault TxFacade
+type TxFacade interface {
+	RunInTx(ctx context.Context, opts pgx.TxOptions, fn func(ctxWithTx context.Context) error) error
+}
+
+// Service сервис ключей хранилища.
+// Сервер хранит только соль, параметры KDF и ключ, зашифрованный мастер-паролем.
+type Service struct {
+	repo     Repository
+	dataRepo DataRepository
+	tx       TxFacade
+}
+
+// Get возвращает ключ хранилища пользователя вместе с незавершённой ротацией, если она есть.
+func (s *Service) Get(ctx context.Context, userUUID string) (*entity.VaultKey, error) {
+	k, err := s.repo.Read(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	if k == nil {
+		return nil, ErrVaultKeyNotFound
+	}
+	if k.Rotation, err = s.repo.ReadRotation(ctx, userUUID); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// Create сохраняет первый ключ хранилища пользователя.
+func (s *Service) Create(ctx context.Context, userUUID string, key entity.VaultKey) (*entity.VaultKey, error) {
+	if !isValidKey(key) {
+		return nil, ErrBadRequest
+	}
+
+	key.UserUUID = userUUID
+	k, err := s.repo.Create(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if k == nil {
+		return nil, ErrVaultKeyExists
+	}
+	return k, nil
+}
+
+// BeginRotation сохраняет новый ключ хранилища как незавершённую ротацию.
+// Ключ ротации содержит ключ тегов: клиент перешифровывает существующий или создаёт его, если его ещё нет.
+// Ключ ротации служит маркером прогресса: пока ротация не завершена, данные зашифрованы
+// текущим ключом, и прерванную ротацию можно продолжить или начать заново.
+func (s *Service) BeginRotation(ctx context.Context, userUUID string, key entity.VaultKey) (*entity.VaultKey, error) {
+	if !isValidKey(key) || len(key.WrappedPreviousKey) < minWrappedKeyLen || len(key.WrappedTagKey) < minWrappedKeyLen {
+		return nil, ErrBadRequest
+	}
+
+	current, err := s.repo.Read(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, ErrVaultKeyNotFound
+	}
+	if key.KeyVersion != current.KeyVersion+1 {
+		return nil, ErrRotationVersionMismatch
+	}
+
+	key.UserUUID = userUUID
+	return s.repo.SaveRotation(ctx, key)
+}
+
+// CommitRotation в одной транзакции сохраняет перешифрованные записи и делает ключ ротации текущим.
+// items должны содержать все записи пользователя.
+func (s *Service) CommitRotation(ctx context.Context, userUUID string, keyVersion uint32, items []entity.UserData) (*entity.VaultKey, error) {
+	var key *entity.VaultKey
+
+	err := s.tx.RunInTx(ctx, pgx.TxOptions{}, func(ctx context.Context) error {
+		current, err := s.repo.ReadForUpdate(ctx, userUUID)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			return ErrVaultKeyNotFound
+		}
+
+		rotation, err := s.repo.ReadRotation(ctx, userUUID)
+		if err != nil {
+			return err
+		}
+		if rotation == nil {
+			return ErrRotationNotFound
+		}
+		if rotation.KeyVersion != keyVersion || current.KeyVersion+1 != keyVersion {
+			return ErrRotationVersionMismatch
+		}
+
+		// Перешифрованные записи попадают в новую ревизию, чтобы клиенты получили их при синхронизации.
+		revision, err := s.dataRepo.NextRevision(ctx, userUUID)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			item.UserUUID = userUUID
+			item.KeyVersion = keyVersion
+			item.Revision = revision
+
+			var ok bool
+			if ok, err = s.dataRepo.Reencrypt(ctx, item); err != nil {
+				return err
+			}
+			if !ok {
+				return ErrRotationConflict
+			}
+		}
+
+		var n int
+		if n, err = s.dataRepo.CountByOtherKeyVersion(ctx, userUUID, keyVersion); err != nil {
+			return err
+		}
+		if n > 0 {
+			return ErrRotationConflict
+		}
+
+		key, err = s.repo.CompleteRotation(ctx, userUUID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, ErrRotationNotFound
+	}
+	return key, nil
+}
+
+// New конструктор.
+func New(r Repository, d DataRepository, tx TxFacade) *Service {
+	return &Service{
+		repo:     r,
+		dataRepo: d,
+		tx:       tx,
+	}
+}
+
+func isValidKey(key entity.VaultKey) bool {
+	if len(key.Salt) < minSaltLen || len(key.WrappedKey) < minWrappedKeyLen || key.KeyVersion == 0 {
+		return false
+	}
+	if len(key.WrappedTagKey) != 0 && len(key.WrappedTagKey) < minWrappedKeyLen {
+		return false
+	}
+	return key.KDF.Time != 0 && key.KDF.Memory != 0 && key.KDF.Threads != 0
+}
@@ -0,0 +1,242 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ktigay/goph-keeper/internal/entity"
+	"github.com/ktigay/goph-keeper/internal/server/service/vault/mocks"
+)
+
+func TestService_Create(t *testing.T) {
+	userUUID := "0b1f3c3e-4d6a-4b8e-9f7a-2c1d5e6f7a8b"
+	valid := entity.VaultKey{
+		Salt:          bytes.Repeat([]byte{1}, minSaltLen),
+		WrappedKey:    bytes.Repeat([]byte{2}, 60),
+		KeyVersion:    1,
+		KDF:           entity.KDFParams{Time: 3, Memory: 65536, Threads: 4},
+		WrappedTagKey: bytes.Repeat([]byte{3}, 60),
+	}
+	stored := valid
+	stored.UserUUID = userUUID
+
+	tests := []struct {
+		name    string
+		repo    func(ctrl *gomock.Controller) Repository
+		key     entity.VaultKey
+		want    *entity.VaultKey
+		wantErr error
+	}{
+		{
+			name: "Create_Success",
+			repo: func(ctrl *gomock.Controller) Repository {
+				repo := mocks.NewMockRepository(ctrl)
+				repo.EXPECT().Create(gomock.Any(), stored).Times(1).Return(&stored, nil)
+				return repo
+			},
+			key:  valid,
+			want: &stored,
+		},
+		{
+			name: "Create_Exists_Error",
+			repo: func(ctrl *gomock.Controller) Repository {
+				repo := mocks.NewMockRepository(ctrl)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil, nil)
+				return repo
+			},
+			key:     valid,
+			wantErr: ErrVaultKeyExists,
+		},
+		{
+			name: "Create_Short_Salt_Error",
+			repo: func(ctrl *gomock.Controller) Repository {
+				return mocks.NewMockRepository(ctrl)
+			},
+			key: func() entity.VaultKey {
+				k := valid
+				k.Salt = []byte{1}
+				return k
+			}(),
+			wantErr: ErrBadRequest,
+		},
+		{
+			name: "Create_Empty_KDF_Error",
+			repo: func(ctrl *gomock.Controller) Repository {
+				return mocks.NewMockRepository(ctrl)
+			},
+			key: func() entity.VaultKey {
+				k := valid
+				k.KDF = entity.KDFParams{}
+				return k
+			}(),
+			wantErr: ErrBadRequest,
+		},
+		{
+			name: "Create_Short_Tag_Key_Error",
+			repo: func(ctrl *gomock.Controller) Repository {
+				return mocks.NewMockRepository(ctrl)
+			},
+			key: func() entity.VaultKey {
+				k := valid
+				k.WrappedTagKey = []byte{3}
+				return k
+			}(),
+			wantErr: ErrBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			s := New(tt.repo(ctrl), nil, nil)
+			got, err := s.Create(context.Background(), userUUID, tt.key)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Create() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Create() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestService_Get(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockRepository(ctrl)
+	repo.EXPECT().Read(gomock.Any(), "missing").Times(1).Return(nil, nil)
+
+	if _, err := New(repo, nil, nil).Get(context.Background(), "missing"); !errors.Is(err, ErrVaultKeyNotFound) {
+		t.Errorf("Get() error = %v, wantErr %v", err, ErrVaultKeyNotFound)
+	}
+}
+
+func TestService_CommitRotation(t *testing.T) {
+	userUUID := "0b1f3c3e-4d6a-4b8e-9f7a-2c1d5e6f7a8b"
+	current := &entity.VaultKey{UserUUID: userUUID, KeyVersion: 1}
+	rotation := &entity.VaultKey{UserUUID: userUUID, KeyVersion: 2}
+	items := []entity.UserData{{UUID: "a", Version: 3}, {UUID: "b", Version: 5}}
+
+	tests := []struct {
+		name       string
+		repo       func(ctrl *gomock.Controller) Repository
+		dataRepo   func(ctrl *gomock.Controller) DataRepository
+		keyVersion uint32
+		want       *entity.VaultKey
+		wantErr    error
+	}{
+		{
+			name: "Commit_Success",
+			repo: func(ctrl *gomock.Controller) Repository {
+				repo := mocks.NewMockRepository(ctrl)
+				repo.EXPECT().ReadForUpdate(gomock.Any(), userUUID).Times(1).Return(current, nil)
+				repo.EXPECT().ReadRotation(gomock.Any(), userUUID).Times(1).Return(rotation, nil)
+				repo.EXPECT().CompleteRotation(gomock.Any(), userUUID).Times(1).Return(rotation, nil)
+				return repo
+			},
+			dataRepo: func(ctrl *gomock.Controller) DataRepository {
+				repo := mocks.NewMockDataRepository(ctrl)
+				repo.EXPECT().NextRevision(gomock.Any(), userUUID).Times(1).Return(uint64(4), nil)
+				repo.EXPECT().Reencrypt(gomock.Any(), gomock.Any()).Times(2).DoAndReturn(
+					func(_ context.Context, d entity.UserData) (bool, error) {
+						if d.KeyVersion != 2 || d.UserUUID != userUUID || d.Revision != 4 || d.Version == 0 {
+							t.Errorf("Reencrypt() got = %+v", d)
+						}
+						return true, nil
+					})
+				repo.EXPECT().CountByOtherKeyVersion(gomock.Any(), userUUID, uint32(2)).Times(1).Return(0, nil)
+				return repo
+			},
+			keyVersion: 2,
+			want:       rotation,
+		},
+		{
+			name: "Commit_Item_Changed_Error",
+			repo: func(ctrl *gomock.Controller) Repository {
+				repo := mocks.NewMockRepository(ctrl)
+				repo.EXPECT().ReadForUpdate(gomock.Any(), userUUID).Times(1).Return(current, nil)
+				repo.EXPECT().ReadRotation(gomock.Any(), userUUID).Times(1).Return(rotation, nil)
+				return repo
+			},
+			dataRepo: func(ctrl *gomock.Controller) DataRepository {
+				repo := mocks.NewMockDataRepository(ctrl)
+				repo.EXPECT().NextRevision(gomock.Any(), userUUID).Times(1).Return(uint64(4), nil)
+				repo.EXPECT().Reencrypt(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				return repo
+			},
+			keyVersion: 2,
+			wantErr:    ErrRotationConflict,
+		},
+		{
+			name: "Commit_Item_Missing_Error",
+			repo: func(ctrl *gomock.Controller) Repository {
+				repo := mocks.NewMockRepository(ctrl)
+				repo.EXPECT().ReadForUpdate(gomock.Any(), userUUID).Times(1).Return(current, nil)
+				repo.EXPECT().ReadRotation(gomock.Any(), userUUID).Times(1).Return(rotation, nil)
+				return repo
+			},
+			dataRepo: func(ctrl *gomock.Controller) DataRepository {
+				repo := mocks.NewMockDataRepository(ctrl)
+				repo.EXPECT().NextRevision(gomock.Any(), userUUID).Times(1).Return(uint64(4), nil)
+				repo.EXPECT().Reencrypt(gomock.Any(), gomock.Any()).Times(2).Return(true, nil)
+				repo.EXPECT().CountByOtherKeyVersion(gomock.Any(), userUUID, uint32(2)).Times(1).Return(1, nil)
+				return repo
+			},
+			keyVersion: 2,
+			wantErr:    ErrRotationConflict,
+		},
+		{
+			name: "Commit_Without_Rotation_Error",
+			repo: func(ctrl *gomock.Controller) Repository {
+				repo := mocks.NewMockRepository(ctrl)
+				repo.EXPECT().ReadForUpdate(gomock.Any(), userUUID).Times(1).Return(current, nil)
+				repo.EXPECT().ReadRotation(gomock.Any(), userUUID).Times(1).Return(nil, nil)
+				return repo
+			},
+			dataRepo: func(ctrl *gomock.Controller) DataRepository {
+				return mocks.NewMockDataRepository(ctrl)
+			},
+			keyVersion: 2,
+			wantErr:    ErrRotationNotFound,
+		},
+		{
+			name: "Commit_Version_Mismatch_Error",
+			repo: func(ctrl *gomock.Controller) Repository {
+				repo := mocks.NewMockRepository(ctrl)
+				repo.EXPECT().ReadForUpdate(gomock.Any(), userUUID).Times(1).Return(current, nil)
+				repo.EXPECT().ReadRotation(gomock.Any(), userUUID).Times(1).Return(rotation, nil)
+				return repo
+			},
+			dataRepo: func(ctrl *gomock.Controller) DataRepository {
+				return mocks.NewMockDataRepository(ctrl)
+			},
+			keyVersion: 3,
+			wantErr:    ErrRotationVersionMismatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			tx := mocks.NewMockTxFacade(ctrl)
+			tx.EXPECT().RunInTx(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+				func(ctx context.Context, _ pgx.TxOptions, fn func(context.Context) error) error {
+					return fn(ctx)
+				})
+
+			s := New(tt.repo(ctrl), tt.dataRepo(ctrl), tx)
+			got, err := s.CommitRotation(context.Background(), userUUID, tt.keyVersion, items)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("CommitRotation() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CommitRotation() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
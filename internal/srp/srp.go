@@ -0,0 +1,253 @@
+// Package srp реализует SRP-6a (RFC 2945, RFC 5054): проверку пароля без передачи его серверу.
+//
+// Группа - 2048-битное простое число RFC 3526 (group 14) с генератором 2, хеш - SHA-256.
+// Секретный множитель x выводится из пароля Argon2id, так что утечка верификатора не позволяет
+// быстро перебирать пароли. Логин в x не входит: соль уникальна для каждого верификатора.
+package srp
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	// SaltSize размер соли верификатора.
+	SaltSize = 16
+
+	// Параметры Argon2id для вывода x из пароля (RFC 9106, второй рекомендованный набор).
+	kdfTime    = 3
+	kdfMemory  = 64 * 1024
+	kdfThreads = 4
+	kdfKeySize = 32
+
+	// secretSize размер случайных эфемерных секретов a и b.
+	secretSize = 32
+)
+
+var (
+	// ErrInvalidPublicKey открытое значение другой стороны некорректно (A или B вне интервала (0, N)).
+	ErrInvalidPublicKey = errors.New("invalid srp public value")
+	// ErrInvalidVerifier верификатор вне интервала (0, N).
+	ErrInvalidVerifier = errors.New("invalid srp verifier")
+	// ErrInvalidProof доказательство другой стороны не совпало.
+	ErrInvalidProof = errors.New("invalid srp proof")
+
+	groupN, _ = new(big.Int).SetString(
+		"FFFFFFFFFFFFFFFFC90FDAA22168C234C4C6628B80DC1CD129024E088A67CC74020BBEA63B139B22514A08798E3404DDEF9519B3CD3A431B302B0A6DF25F14374FE1356D6D51C245E485B576625E7EC6F44C42E9A637ED6B0BFF5CB6F406B7EDEE386BFB5A899FA5AE9F24117C4B1FE649286651ECE45B3DC2007CB8A163BF0598DA48361C55D39A69163FA8FD24CF5F83655D23DCA3AD961C62F356208552BB9ED529077096966D670C354E4ABC9804F1746C08CA18217C32905E462E36CE3BE39E772C180E86039B2783A2EC07A28FB5C55DF06F4C52C9DE2BCBF6955817183995497CEA956AE515D2261898FA051015728E5A8AACAA68FFFFFFFFFFFFFFFF",
+		16,
+	)
+	groupG = big.NewInt(2)
+	// groupK множитель k = H(N | PAD(g)).
+	groupK = hashInt(groupN.Bytes(), pad(groupG))
+)
+
+// NewSalt генерирует соль верификатора.
+func NewSalt() ([]byte, error) {
+	salt := make([]byte, SaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
+
+// Verifier вычисляет верификатор v = g^x mod N, который хранится на сервере вместо пароля.
+func Verifier(password string, salt []byte) []byte {
+	return pad(new(big.Int).Exp(groupG, secretX(password, salt), groupN))
+}
+
+// FakeVerifier верификатор для несуществующего логина, выведенный из секрета сервера seed:
+// ответ сервера выглядит так же, как для существующего, а вход завершается ошибкой доказательства.
+func FakeVerifier(seed []byte) []byte {
+	return pad(new(big.Int).Exp(groupG, hashInt(seed), groupN))
+}
+
+// CheckVerifier проверяет, что верификатор - допустимый элемент группы.
+func CheckVerifier(verifier []byte) error {
+	if invalidPublic(new(big.Int).SetBytes(verifier)) {
+		return ErrInvalidVerifier
+	}
+	return nil
+}
+
+// Client клиентская сторона обмена.
+type Client struct {
+	password string
+	a        *big.Int
+	bigA     *big.Int
+	// serverProof ожидаемое доказательство сервера, вычисляется в [Client.Proof].
+	serverProof []byte
+}
+
+// PublicKey возвращает открытое значение клиента A.
+func (c *Client) PublicKey() []byte {
+	return pad(c.bigA)
+}
+
+// Proof вычисляет доказательство клиента M1 по соли и открытому значению сервера B.
+func (c *Client) Proof(salt, serverPublic []byte) ([]byte, error) {
+	bigB := new(big.Int).SetBytes(serverPublic)
+	if invalidPublic(bigB) {
+		return nil, ErrInvalidPublicKey
+	}
+
+	u := hashInt(pad(c.bigA), pad(bigB))
+	if u.Sign() == 0 {
+		return nil, ErrInvalidPublicKey
+	}
+	x := secretX(c.password, salt)
+
+	// S = (B - k * g^x) ^ (a + u * x) mod N
+	base := new(big.Int).Exp(groupG, x, groupN)
+	base.Mul(base, groupK)
+	base.Sub(bigB, base)
+	base.Mod(base, groupN)
+	exp := new(big.Int).Mul(u, x)
+	exp.Add(exp, c.a)
+	s := new(big.Int).Exp(base, exp, groupN)
+
+	clientProof, serverProof := proofs(c.bigA, bigB, s)
+	c.serverProof = serverProof
+	return clientProof, nil
+}
+
+// VerifyServer проверяет доказательство сервера M2: сервер знает верификатор, а не подменен.
+func (c *Client) VerifyServer(proof []byte) error {
+	if c.serverProof == nil || subtle.ConstantTimeCompare(c.serverProof, proof) != 1 {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// NewClient конструктор.
+func NewClient(password string) (*Client, error) {
+	a, err := randomSecret()
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		password: password,
+		a:        a,
+		bigA:     new(big.Int).Exp(groupG, a, groupN),
+	}, nil
+}
+
+// Server серверная сторона обмена. Ключи и доказательства вычисляются сразу,
+// серверу достаточно сохранить их до получения доказательства клиента.
+type Server struct {
+	bigB        *big.Int
+	clientProof []byte
+	serverProof []byte
+}
+
+// PublicKey возвращает открытое значение сервера B.
+func (s *Server) PublicKey() []byte {
+	return pad(s.bigB)
+}
+
+// ClientProof возвращает ожидаемое доказательство клиента M1.
+func (s *Server) ClientProof() []byte {
+	return s.clientProof
+}
+
+// ServerProof возвращает доказательство сервера M2, которое отдается клиенту после проверки M1.
+func (s *Server) ServerProof() []byte {
+	return s.serverProof
+}
+
+// NewServer конструктор: вычисляет B и доказательства по верификатору и открытому значению клиента A.
+func NewServer(verifier, clientPublic []byte) (*Server, error) {
+	bigA := new(big.Int).SetBytes(clientPublic)
+	if invalidPublic(bigA) {
+		return nil, ErrInvalidPublicKey
+	}
+
+	b, err := randomSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err = CheckVerifier(verifier); err != nil {
+		return nil, err
+	}
+	v := new(big.Int).SetBytes(verifier)
+
+	// B = k * v + g^b mod N
+	bigB := new(big.Int).Mul(groupK, v)
+	bigB.Add(bigB, new(big.Int).Exp(groupG, b, groupN))
+	bigB.Mod(bigB, groupN)
+
+	u := hashInt(pad(bigA), pad(bigB))
+	if u.Sign() == 0 {
+		return nil, ErrInvalidPublicKey
+	}
+
+	// S = (A * v^u) ^ b mod N
+	base := new(big.Int).Exp(v, u, groupN)
+	base.Mul(base, bigA)
+	base.Mod(base, groupN)
+	s := new(big.Int).Exp(base, b, groupN)
+
+	clientProof, serverProof := proofs(bigA, bigB, s)
+	return &Server{
+		bigB:        bigB,
+		clientProof: clientProof,
+		serverProof: serverProof,
+	}, nil
+}
+
+// VerifyProof сравнивает доказательства за постоянное время.
+func VerifyProof(expected, proof []byte) error {
+	if len(expected) == 0 || subtle.ConstantTimeCompare(expected, proof) != 1 {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// proofs вычисляет K = H(S), M1 = H(A | B | K) и M2 = H(A | M1 | K).
+func proofs(bigA, bigB, s *big.Int) ([]byte, []byte) {
+	k := hash(pad(s))
+	m1 := hash(pad(bigA), pad(bigB), k)
+	m2 := hash(pad(bigA), m1, k)
+	return m1, m2
+}
+
+// secretX x = H(salt | Argon2id(password, salt)). Пароль обрезается по краям так же, как при входе по паролю.
+func secretX(password string, salt []byte) *big.Int {
+	key := argon2.IDKey([]byte(strings.TrimSpace(password)), salt, kdfTime, kdfMemory, kdfThreads, kdfKeySize)
+	return hashInt(salt, key)
+}
+
+func randomSecret() (*big.Int, error) {
+	buf := make([]byte, secretSize)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(buf), nil
+}
+
+// invalidPublic открытое значение должно быть в интервале (0, N), иначе A mod N == 0 или значение не поместится в PAD.
+func invalidPublic(v *big.Int) bool {
+	return v.Sign() <= 0 || v.Cmp(groupN) >= 0
+}
+
+// pad дополняет значение нулями слева до размера N.
+func pad(v *big.Int) []byte {
+	return v.FillBytes(make([]byte, (groupN.BitLen()+7)/8))
+}
+
+func hash(parts ...[]byte) []byte {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write(p)
+	}
+	return h.Sum(nil)
+}
+
+func hashInt(parts ...[]byte) *big.Int {
+	return new(big.Int).SetBytes(hash(parts...))
+}
@@ -0,0 +1,112 @@
+package srp
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestGroup(t *testing.T) {
+	if groupN.BitLen() != 2048 || !groupN.ProbablyPrime(20) {
+		t.Fatalf("N must be a 2048-bit prime")
+	}
+	// Безопасное простое: (N-1)/2 тоже простое.
+	q := new(big.Int).Rsh(groupN, 1)
+	if !q.ProbablyPrime(20) {
+		t.Errorf("N must be a safe prime")
+	}
+}
+
+func handshake(t *testing.T, registered, entered string) (*Client, *Server, []byte) {
+	t.Helper()
+
+	salt, err := NewSalt()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier := Verifier(registered, salt)
+
+	client, err := NewClient(entered)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := NewServer(verifier, client.PublicKey())
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	proof, err := client.Proof(salt, server.PublicKey())
+	if err != nil {
+		t.Fatalf("Proof() error = %v", err)
+	}
+	return client, server, proof
+}
+
+func TestHandshake(t *testing.T) {
+	tests := []struct {
+		name       string
+		registered string
+		entered    string
+		wantErr    bool
+	}{
+		{name: "Correct_Password", registered: "password", entered: "password"},
+		{name: "Password_Trimmed", registered: "password", entered: " password "},
+		{name: "Wrong_Password", registered: "password", entered: "passw0rd", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, server, proof := handshake(t, tt.registered, tt.entered)
+
+			err := VerifyProof(server.ClientProof(), proof)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("VerifyProof() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if err = client.VerifyServer(server.ServerProof()); err != nil {
+				t.Errorf("VerifyServer() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestClient_VerifyServer_Forged(t *testing.T) {
+	client, server, _ := handshake(t, "password", "password")
+
+	forged := bytes.Clone(server.ServerProof())
+	forged[0] ^= 1
+	if err := client.VerifyServer(forged); !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("VerifyServer() error = %v, want %v", err, ErrInvalidProof)
+	}
+}
+
+func TestInvalidPublicKey(t *testing.T) {
+	verifier := FakeVerifier([]byte("seed"))
+	if err := CheckVerifier(verifier); err != nil {
+		t.Fatalf("CheckVerifier() error = %v", err)
+	}
+
+	var err error
+	for _, public := range [][]byte{nil, {0}, groupN.Bytes(), new(big.Int).Lsh(groupN, 1).Bytes()} {
+		if _, err = NewServer(verifier, public); !errors.Is(err, ErrInvalidPublicKey) {
+			t.Errorf("NewServer() error = %v, want %v", err, ErrInvalidPublicKey)
+		}
+
+		client, cErr := NewClient("password")
+		if cErr != nil {
+			t.Fatal(cErr)
+		}
+		if _, err = client.Proof([]byte("salt"), public); !errors.Is(err, ErrInvalidPublicKey) {
+			t.Errorf("Proof() error = %v, want %v", err, ErrInvalidPublicKey)
+		}
+	}
+}
+
+func TestCheckVerifier(t *testing.T) {
+	for _, v := range [][]byte{nil, {0}, groupN.Bytes()} {
+		if err := CheckVerifier(v); !errors.Is(err, ErrInvalidVerifier) {
+			t.Errorf("CheckVerifier() error = %v, want %v", err, ErrInvalidVerifier)
+		}
+	}
+}
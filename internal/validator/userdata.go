@@ -5,6 +5,7 @@ import (
 
 	"github.com/go-playground/validator/v10"
 	"github.com/ktigay/goph-keeper/internal/entity"
+	"github.com/ktigay/goph-keeper/internal/otp"
 )
 
 // ValidateUserData валидирует [entity.UserData].
@@ -14,14 +15,37 @@ func ValidateUserData(data entity.UserData) error {
 		return err
 	}
 
-	if data.Type == entity.DataTypeCard {
-		card := entity.UserDataCard{}
-		if err := json.Unmarshal(data.Data, &card); err != nil {
+	// Зашифрованные на клиенте данные проверить невозможно.
+	if data.KeyVersion > 0 {
+		return nil
+	}
+
+	switch data.Type {
+	case entity.DataTypeCard:
+		_, err := validateJSONData[entity.UserDataCard](vd, data.Data)
+		return err
+	case entity.DataTypeCredential:
+		_, err := validateJSONData[entity.UserDataCredential](vd, data.Data)
+		return err
+	case entity.DataTypeOTP:
+		o, err := validateJSONData[entity.UserDataOTP](vd, data.Data)
+		if err != nil {
 			return err
 		}
-		if err := vd.Struct(card); err != nil {
+		if _, err = otp.DecodeSecret(o.Secret); err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+func validateJSONData[T any](vd *validator.Validate, data []byte) (*T, error) {
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	if err := vd.Struct(v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
@@ -111,6 +111,90 @@ func TestValidateUserData(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Validate_Credential_success",
+			args: args{
+				data: entity.UserData{
+					Title: "title",
+					UUID:  "4d8de9dc-b3b3-4c45-b71b-189fb41837ea",
+					Type:  entity.DataTypeCredential,
+					Data:  []byte(`{"username":"user","password":"secret","urls":["https://example.com/login"],"notes":""}`),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Validate_Credential_with_error_#1",
+			args: args{
+				data: entity.UserData{
+					Title: "title",
+					UUID:  "4d8de9dc-b3b3-4c45-b71b-189fb41837ea",
+					Type:  entity.DataTypeCredential,
+					Data:  []byte(`{"username":"","password":"secret"}`),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Validate_Credential_with_error_#2",
+			args: args{
+				data: entity.UserData{
+					Title: "title",
+					UUID:  "4d8de9dc-b3b3-4c45-b71b-189fb41837ea",
+					Type:  entity.DataTypeCredential,
+					Data:  []byte(`{"username":"user","password":"secret","urls":["not a url"]}`),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Validate_OTP_success",
+			args: args{
+				data: entity.UserData{
+					Title: "title",
+					UUID:  "4d8de9dc-b3b3-4c45-b71b-189fb41837ea",
+					Type:  entity.DataTypeOTP,
+					Data:  []byte(`{"type":"TOTP","secret":"GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ","algorithm":"SHA1","digits":6,"period":30}`),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Validate_OTP_with_error_#1",
+			args: args{
+				data: entity.UserData{
+					Title: "title",
+					UUID:  "4d8de9dc-b3b3-4c45-b71b-189fb41837ea",
+					Type:  entity.DataTypeOTP,
+					Data:  []byte(`{"type":"TOTP","secret":"not-base32!","algorithm":"SHA1","digits":6,"period":30}`),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Validate_OTP_with_error_#2",
+			args: args{
+				data: entity.UserData{
+					Title: "title",
+					UUID:  "4d8de9dc-b3b3-4c45-b71b-189fb41837ea",
+					Type:  entity.DataTypeOTP,
+					Data:  []byte(`{"type":"TOTP","secret":"GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ","algorithm":"MD5","digits":6,"period":30}`),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Validate_OTP_with_error_#3",
+			args: args{
+				data: entity.UserData{
+					Title: "title",
+					UUID:  "4d8de9dc-b3b3-4c45-b71b-189fb41837ea",
+					Type:  entity.DataTypeOTP,
+					Data:  []byte(`{"type":"TOTP","secret":"GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ","algorithm":"SHA1","digits":6,"period":0}`),
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {